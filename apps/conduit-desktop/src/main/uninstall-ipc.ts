@@ -370,9 +370,11 @@ function buildCLIFlags(options: UninstallOptions, dryRun: boolean): string[] {
 }
 
 function checkDaemonRunningSync(): boolean {
-  // Simple sync check using curl
+  // Simple sync check using curl against the daemon's socket, the only
+  // place it listens; -f fails on a non-2xx health response
+  const socketPath = path.join(os.homedir(), '.conduit', 'conduit.sock')
   try {
-    execFileSync('curl', ['-s', '--connect-timeout', '2', 'http://localhost:9090/health'], {
+    execFileSync('curl', ['-sf', '--connect-timeout', '2', '--unix-socket', socketPath, 'http://localhost/api/v1/health'], {
       encoding: 'utf8',
       timeout: 3000
     })
@@ -3,7 +3,9 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
@@ -63,8 +65,19 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		cfg.LogFormat = logFormat
 	}
 
-	// Setup logging
-	observability.SetupLogging(cfg.LogLevel, cfg.LogFormat, os.Stderr)
+	// Setup logging. The Windows service manager discards stderr, so log to
+	// the data directory instead.
+	logOutput := io.Writer(os.Stderr)
+	if isService() {
+		if err := os.MkdirAll(cfg.DataDir, 0700); err == nil {
+			logFile, err := os.OpenFile(filepath.Join(cfg.DataDir, "daemon.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+			if err == nil {
+				defer logFile.Close()
+				logOutput = logFile
+			}
+		}
+	}
+	observability.SetupLogging(cfg.LogLevel, cfg.LogFormat, logOutput)
 
 	// Set version info for daemon handlers
 	daemon.Version = Version
@@ -76,5 +89,10 @@ func runDaemon(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create daemon: %w", err)
 	}
 
+	// Under the Windows service control manager, let it drive shutdown
+	if isService() {
+		return runService(d)
+	}
+
 	return d.Run()
 }
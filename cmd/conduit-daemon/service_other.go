@@ -0,0 +1,16 @@
+//go:build !windows
+
+package main
+
+import "github.com/simpleflo/conduit/internal/daemon"
+
+// isService is always false outside Windows; launchd and systemd run the
+// daemon as a regular foreground process.
+func isService() bool {
+	return false
+}
+
+// runService runs the daemon until interrupted.
+func runService(d *daemon.Daemon) error {
+	return d.Run()
+}
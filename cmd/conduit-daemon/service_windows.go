@@ -0,0 +1,71 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+
+	"golang.org/x/sys/windows/svc"
+
+	"github.com/simpleflo/conduit/internal/daemon"
+)
+
+// serviceName must match the name registered by the installer.
+const serviceName = "conduit"
+
+// windowsService adapts the daemon to the Windows service control manager.
+type windowsService struct {
+	d *daemon.Daemon
+}
+
+// isService reports whether the process was started by the service control
+// manager rather than interactively.
+func isService() bool {
+	ok, err := svc.IsWindowsService()
+	return err == nil && ok
+}
+
+// runService runs the daemon under the service control manager, which
+// delivers stop requests instead of signals.
+func runService(d *daemon.Daemon) error {
+	return svc.Run(serviceName, &windowsService{d: d})
+}
+
+// Execute implements svc.Handler.
+func (s *windowsService) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+
+	status <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.d.RunContext(ctx)
+	}()
+
+	status <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for {
+		select {
+		case err := <-errCh:
+			if err != nil {
+				return false, 1
+			}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+				if err := <-errCh; err != nil {
+					return false, 1
+				}
+				return false, 0
+			}
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/simpleflo/conduit/internal/installer"
+	"github.com/simpleflo/conduit/internal/kb"
+)
+
+// doctorFix is a repair `conduit doctor --fix` can attempt for a failing
+// check.
+type doctorFix struct {
+	// Description says what the fix will do; it is shown before asking.
+	Description string
+
+	// Destructive fixes remove or change files, so they are always confirmed,
+	// even with --yes.
+	Destructive bool
+
+	// Apply performs the fix and reports what it did.
+	Apply func(ctx context.Context) (string, error)
+}
+
+// runDoctorFixes offers each fix in turn, applying the ones the user
+// confirms, and returns how many were applied. With dryRun the fixes are
+// only listed.
+func runDoctorFixes(ctx context.Context, fixes []doctorFix, assumeYes, dryRun bool) int {
+	fmt.Println()
+	fmt.Println("🔧 Repairs")
+	fmt.Println("────────────────────────────────────────────────────────")
+
+	applied := 0
+	for _, fix := range fixes {
+		if dryRun {
+			note := ""
+			if fix.Destructive || !assumeYes {
+				note = " (asks first)"
+			}
+			fmt.Printf("○ Would: %s%s\n", fix.Description, note)
+			continue
+		}
+		if fix.Destructive || !assumeYes {
+			if !confirmAction(fix.Description + "?") {
+				fmt.Println("○ Skipped")
+				continue
+			}
+		} else {
+			fmt.Printf("→ %s\n", fix.Description)
+		}
+
+		report, err := fix.Apply(ctx)
+		if err != nil {
+			fmt.Printf("❌ Failed: %v\n", err)
+			continue
+		}
+		fmt.Printf("✓ %s\n", report)
+		applied++
+	}
+	return applied
+}
+
+// staleSocketFix removes a daemon socket file nothing is listening on.
+func staleSocketFix(path string) doctorFix {
+	return doctorFix{
+		Description: fmt.Sprintf("Remove stale daemon socket %s", path),
+		Destructive: true,
+		Apply: func(ctx context.Context) (string, error) {
+			// Check again: a daemon may have started since the diagnosis
+			if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+				conn.Close()
+				return "", fmt.Errorf("a daemon is now listening on %s, leaving it in place", path)
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return "", err
+			}
+			return fmt.Sprintf("Removed stale socket %s", path), nil
+		},
+	}
+}
+
+// isStaleSocket reports whether path is a socket file nothing accepts
+// connections on, as left behind by a daemon that crashed.
+func isStaleSocket(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return true
+	}
+	conn.Close()
+	return false
+}
+
+// startDaemonFix starts an installed daemon that is not running: through its
+// service when one is installed, otherwise by launching conduit-daemon in the
+// background. It reports false when no daemon is installed.
+func startDaemonFix() (doctorFix, bool) {
+	inst := newInstaller(false)
+
+	if inst.IsDaemonServiceInstalled() {
+		return doctorFix{
+			Description: "Start the Conduit daemon service",
+			Apply: func(ctx context.Context) (string, error) {
+				if err := inst.StartDaemonService(); err != nil {
+					return "", fmt.Errorf("start service: %w", err)
+				}
+				if !waitForDaemon(ctx, inst) {
+					return "", fmt.Errorf("service started but the daemon is not answering; check: conduit service logs")
+				}
+				return "Started the daemon service", nil
+			},
+		}, true
+	}
+
+	daemonPath := findDaemonBinary()
+	if daemonPath == "" {
+		return doctorFix{}, false
+	}
+	return doctorFix{
+		Description: fmt.Sprintf("Start the Conduit daemon (%s)", daemonPath),
+		Apply: func(ctx context.Context) (string, error) {
+			cmd := exec.Command(daemonPath, "--foreground", "--socket", inst.SocketPath())
+			if err := cmd.Start(); err != nil {
+				return "", fmt.Errorf("start daemon: %w", err)
+			}
+			cmd.Process.Release()
+			if !waitForDaemon(ctx, inst) {
+				return "", fmt.Errorf("daemon did not become ready; try: conduit-daemon --foreground")
+			}
+			return fmt.Sprintf("Started the daemon in the background (%s)", daemonPath), nil
+		},
+	}, true
+}
+
+// waitForDaemon waits up to 15 seconds for the daemon to answer its health
+// endpoint.
+func waitForDaemon(ctx context.Context, inst *installer.Installer) bool {
+	for j := 0; j < 30; j++ {
+		if inst.IsDaemonRunning() {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	return false
+}
+
+// findDaemonBinary returns the path of conduit-daemon, looked up in PATH and
+// next to this binary, or "".
+func findDaemonBinary() string {
+	if path, err := exec.LookPath("conduit-daemon"); err == nil {
+		return path
+	}
+	conduitPath, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	name := "conduit-daemon"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	path := filepath.Join(filepath.Dir(conduitPath), name)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// podmanMachineStopped reports whether a Podman machine exists but is not
+// running. Only macOS and Windows run Podman in a machine.
+func podmanMachineStopped(ctx context.Context, podmanPath string) bool {
+	if runtime.GOOS == "linux" || podmanPath == "" {
+		return false
+	}
+	names, err := exec.CommandContext(ctx, podmanPath, "machine", "list", "--format", "{{.Name}}").Output()
+	if err != nil || strings.TrimSpace(string(names)) == "" {
+		return false
+	}
+	running, err := exec.CommandContext(ctx, podmanPath, "machine", "list", "--format", "{{.Running}}").Output()
+	return err == nil && !strings.Contains(string(running), "true")
+}
+
+// podmanMachineFix starts the stopped Podman machine.
+func podmanMachineFix(podmanPath string) doctorFix {
+	return doctorFix{
+		Description: "Start the Podman machine",
+		Apply: func(ctx context.Context) (string, error) {
+			out, err := exec.CommandContext(ctx, podmanPath, "machine", "start").CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("podman machine start: %s", strings.TrimSpace(string(out)))
+			}
+			return "Started the Podman machine", nil
+		},
+	}
+}
+
+// ollamaFix starts the installed Ollama server.
+func ollamaFix() doctorFix {
+	return doctorFix{
+		Description: "Start Ollama (ollama serve)",
+		Apply: func(ctx context.Context) (string, error) {
+			if err := ensureOllamaRunning(ctx); err != nil {
+				return "", err
+			}
+			return "Ollama is running", nil
+		},
+	}
+}
+
+// qdrantCollectionFix creates the missing conduit_kb collection in Qdrant
+// with the settings the daemon uses. Documents already indexed get their
+// vectors back on the next sync.
+func qdrantCollectionFix() doctorFix {
+	return doctorFix{
+		Description: fmt.Sprintf("Create the missing Qdrant collection %s", kb.DefaultCollectionName),
+		Apply: func(ctx context.Context) (string, error) {
+			vectorStore, err := kb.NewVectorStore(kb.VectorStoreConfig{})
+			if err != nil {
+				return "", fmt.Errorf("connect to Qdrant: %w", err)
+			}
+			defer vectorStore.Close()
+
+			if err := vectorStore.EnsureCollection(ctx); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Created collection %s (run 'conduit kb sync' to re-index vectors)", kb.DefaultCollectionName), nil
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withStdin makes confirmAction read input for the rest of the test.
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(input), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		f.Close()
+	})
+}
+
+// recordingFix returns a fix that records its name in applied when it runs.
+func recordingFix(name string, destructive bool, applied *[]string, err error) doctorFix {
+	return doctorFix{
+		Description: name,
+		Destructive: destructive,
+		Apply: func(ctx context.Context) (string, error) {
+			*applied = append(*applied, name)
+			return name + " done", err
+		},
+	}
+}
+
+func TestRunDoctorFixes(t *testing.T) {
+	ctx := context.Background()
+
+	// --yes applies safe fixes without asking; a failed fix is not counted
+	var applied []string
+	fixes := []doctorFix{
+		recordingFix("start daemon", false, &applied, nil),
+		recordingFix("start ollama", false, &applied, errors.New("not installed")),
+	}
+	if n := runDoctorFixes(ctx, fixes, true, false); n != 1 {
+		t.Errorf("applied %d fixes, want 1", n)
+	}
+	if len(applied) != 2 {
+		t.Errorf("expected both fixes to run, got %v", applied)
+	}
+
+	// Destructive fixes are confirmed even with --yes
+	applied = nil
+	withStdin(t, "")
+	fixes = []doctorFix{
+		recordingFix("remove socket", true, &applied, nil),
+		recordingFix("start daemon", false, &applied, nil),
+	}
+	if n := runDoctorFixes(ctx, fixes, true, false); n != 1 || len(applied) != 1 || applied[0] != "start daemon" {
+		t.Errorf("expected only the safe fix without an answer, got %d applied: %v", n, applied)
+	}
+
+	applied = nil
+	withStdin(t, "y\n")
+	if n := runDoctorFixes(ctx, fixes[:1], true, false); n != 1 || len(applied) != 1 {
+		t.Errorf("expected the confirmed destructive fix to run, got %d applied: %v", n, applied)
+	}
+
+	// Without --yes every fix is confirmed
+	applied = nil
+	withStdin(t, "n\n")
+	if n := runDoctorFixes(ctx, fixes[1:], false, false); n != 0 || len(applied) != 0 {
+		t.Errorf("expected the declined fix to be skipped, got %d applied: %v", n, applied)
+	}
+}
+
+func TestRunDoctorFixesDryRun(t *testing.T) {
+	var applied []string
+	fixes := []doctorFix{
+		recordingFix("remove socket", true, &applied, nil),
+		recordingFix("start daemon", false, &applied, nil),
+	}
+	// A dry run neither asks nor applies, even with --yes
+	withStdin(t, "y\ny\n")
+	for _, assumeYes := range []bool{false, true} {
+		if n := runDoctorFixes(context.Background(), fixes, assumeYes, true); n != 0 || len(applied) != 0 {
+			t.Errorf("dry run applied %d fixes: %v", n, applied)
+		}
+	}
+}
+
+func TestStaleSocketFix(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "conduit.sock")
+
+	// A socket with a listener is left in place
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	if isStaleSocket(sock) {
+		t.Error("socket with a listener reported stale")
+	}
+	if _, err := staleSocketFix(sock).Apply(context.Background()); err == nil {
+		t.Error("expected the fix to refuse a live socket")
+	}
+
+	// Closing a unix listener removes its file; recreate it without one
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	if !isStaleSocket(sock) {
+		t.Fatal("socket without a listener not reported stale")
+	}
+	if _, err := staleSocketFix(sock).Apply(context.Background()); err != nil {
+		t.Fatalf("remove stale socket: %v", err)
+	}
+	if _, err := os.Stat(sock); !os.IsNotExist(err) {
+		t.Errorf("stale socket still present: %v", err)
+	}
+	if isStaleSocket(filepath.Join(dir, "missing.sock")) {
+		t.Error("missing socket reported stale")
+	}
+}
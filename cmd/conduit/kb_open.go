@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// kbLastSearch is the most recent kb search, saved so `kb open <n>` can refer
+// to its results by number.
+type kbLastSearch struct {
+	Query   string             `json:"query"`
+	Results []kbLastSearchItem `json:"results"`
+}
+
+// kbLastSearchItem is one saved result: the document and where the matched
+// chunk starts, as a file line when known and an offset in its indexed text.
+type kbLastSearchItem struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line,omitempty"`
+	StartChar int    `json:"start_char,omitempty"`
+	Content   string `json:"content,omitempty"`
+}
+
+// maxSavedContent bounds the matched text kept per result; only the start is
+// needed to find the line.
+const maxSavedContent = 500
+
+func lastKBSearchPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".conduit", "kb_last_search.json")
+}
+
+// saveLastKBSearch records the results of a search response. Failures are
+// ignored: the search itself already succeeded.
+func saveLastKBSearch(query string, data []byte) {
+	var resp struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return
+	}
+
+	last := kbLastSearch{Query: query}
+	for _, result := range resp.Results {
+		item := kbLastSearchItem{}
+		item.Path, _ = result["path"].(string)
+		item.Content, _ = result["content"].(string)
+		if item.Content == "" {
+			item.Content, _ = result["snippet"].(string)
+		}
+		// Drop highlight markers so the text can be found in the file
+		item.Content = strings.ReplaceAll(item.Content, "**", "")
+		if r := []rune(item.Content); len(r) > maxSavedContent {
+			item.Content = string(r[:maxSavedContent])
+		}
+		if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+			if s, ok := metadata["start_line"].(string); ok {
+				item.StartLine, _ = strconv.Atoi(s)
+			}
+			if s, ok := metadata["start_char"].(string); ok {
+				item.StartChar, _ = strconv.Atoi(s)
+			}
+		}
+		last.Results = append(last.Results, item)
+	}
+
+	out, err := json.MarshalIndent(last, "", "  ")
+	if err != nil {
+		return
+	}
+	path := lastKBSearchPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	os.WriteFile(path, out, 0600)
+}
+
+func loadLastKBSearch() (*kbLastSearch, error) {
+	data, err := os.ReadFile(lastKBSearchPath())
+	if err != nil {
+		return nil, err
+	}
+	var last kbLastSearch
+	if err := json.Unmarshal(data, &last); err != nil {
+		return nil, err
+	}
+	return &last, nil
+}
+
+func kbOpenCmd() *cobra.Command {
+	var printOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "open <result>",
+		Short: "Open a search result's source document",
+		Long: `Open the document behind a search result in your editor or viewer.
+
+<result> is either a result number from the last 'conduit kb search'
+(shown in brackets, starting at 1) or a document path.
+
+Documents open in $EDITOR when it is set, scrolled to the matched line for
+editors that accept a line number (vim, nvim, nano, emacs, micro, helix,
+VS Code, Cursor, Sublime Text, Zed, ...). Otherwise the system viewer is
+used: 'open' on macOS, 'xdg-open' on Linux.
+
+Examples:
+  conduit kb search "rate limits"
+  conduit kb open 2
+  conduit kb open ~/docs/api/limits.md
+  conduit kb open 1 --print          # Print path:line instead of opening`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			item, err := resolveKBOpenTarget(args[0])
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(item.Path)
+			if err != nil {
+				return fmt.Errorf("cannot open document: %w", err)
+			}
+			// Line numbers recorded at indexing are exact; older indexes
+			// only have the offset and matched text to go on
+			line := item.StartLine
+			if line <= 0 {
+				line = findMatchLine(data, item.StartChar, item.Content)
+			}
+
+			if printOnly {
+				if line > 0 {
+					fmt.Printf("%s:%d\n", item.Path, line)
+				} else {
+					fmt.Println(item.Path)
+				}
+				return nil
+			}
+			return openDocument(item.Path, line)
+		},
+	}
+
+	cmd.Flags().BoolVar(&printOnly, "print", false, "Print the document path and matched line instead of opening it")
+
+	return cmd
+}
+
+// resolveKBOpenTarget turns a result number or path into the document to
+// open. A path that appeared in the last search keeps that result's match.
+func resolveKBOpenTarget(arg string) (*kbLastSearchItem, error) {
+	last, lastErr := loadLastKBSearch()
+
+	if n, err := strconv.Atoi(arg); err == nil {
+		if lastErr != nil {
+			return nil, fmt.Errorf("no previous search to take result %d from: run 'conduit kb search' first", n)
+		}
+		if n < 1 || n > len(last.Results) {
+			return nil, fmt.Errorf("result %d out of range: the last search for %q returned %d results",
+				n, last.Query, len(last.Results))
+		}
+		item := last.Results[n-1]
+		return &item, nil
+	}
+
+	path := expandGrantPath(arg)
+	if lastErr == nil {
+		for _, item := range last.Results {
+			if item.Path == path {
+				return &item, nil
+			}
+		}
+	}
+	return &kbLastSearchItem{Path: path}, nil
+}
+
+// findMatchLine returns the 1-based line of a document where the matched
+// chunk starts, or 0 when it cannot be told (binary formats, no match data).
+// Chunk offsets refer to the cleaned text that was indexed, so the first
+// line of the matched content is searched for in the file, preferring the
+// occurrence nearest the offset; the offset alone is the fallback.
+func findMatchLine(data []byte, startChar int, content string) int {
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return 0
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	approx := 0
+	if startChar > 0 && startChar <= len(text) {
+		approx = strings.Count(text[:startChar], "\n") + 1
+	}
+
+	probe := matchProbe(content)
+	if probe == "" {
+		return approx
+	}
+
+	best := 0
+	for i, line := range strings.Split(text, "\n") {
+		if !strings.Contains(strings.Join(strings.Fields(line), " "), probe) {
+			continue
+		}
+		if best == 0 || absInt(i+1-approx) < absInt(best-approx) {
+			best = i + 1
+		}
+	}
+	if best == 0 {
+		return approx
+	}
+	return best
+}
+
+// matchProbe picks the first line of matched content long enough to locate
+// it in the file, with whitespace collapsed as the result processor does.
+func matchProbe(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if len(line) >= 12 {
+			if r := []rune(line); len(r) > 60 {
+				line = string(r[:60])
+			}
+			return line
+		}
+	}
+	return ""
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// openDocument opens path in $EDITOR at line, or in the system viewer.
+func openDocument(path string, line int) error {
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		args := strings.Fields(editor)
+		args = append(args, editorArgs(filepath.Base(args[0]), path, line)...)
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("run %s: %w", args[0], err)
+		}
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", "", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("open %s: %w (set $EDITOR to choose an editor)", path, err)
+	}
+	return nil
+}
+
+// editorArgs returns the arguments that open path at line in the named
+// editor. Editors with no known line syntax just get the path.
+func editorArgs(editor, path string, line int) []string {
+	if line <= 0 {
+		return []string{path}
+	}
+	switch strings.TrimSuffix(editor, ".exe") {
+	case "vi", "vim", "nvim", "gvim", "mvim", "view", "nano", "pico", "emacs", "emacsclient", "micro", "kak", "mg", "joe", "ne":
+		return []string{fmt.Sprintf("+%d", line), path}
+	case "code", "code-insiders", "codium", "cursor", "windsurf":
+		return []string{"--goto", fmt.Sprintf("%s:%d", path, line)}
+	case "subl", "zed", "hx", "helix":
+		return []string{fmt.Sprintf("%s:%d", path, line)}
+	case "mate":
+		return []string{"-l", strconv.Itoa(line), path}
+	}
+	return []string{path}
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// kbSearchFunc runs a knowledge base search against the daemon and returns
+// the raw response body.
+type kbSearchFunc func(query, mode string, limit int) ([]byte, error)
+
+// kbBrowserModes is the order the browser cycles search modes in.
+var kbBrowserModes = []string{"hybrid", "semantic", "fts5"}
+
+const (
+	kbBrowserDefaultLimit = 10
+	kbBrowserMaxLimit     = 100
+)
+
+// Keys produced by readKey for sequences that are not a single rune.
+const (
+	keyUp rune = -(iota + 1)
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyEscape
+)
+
+// kbBrowserResult is one search hit as shown in the browser.
+type kbBrowserResult struct {
+	Label   string
+	Path    string
+	Content string
+}
+
+// kbBrowser is the state of the interactive search view.
+type kbBrowser struct {
+	search kbSearchFunc
+	query  string
+	mode   string
+	limit  int
+
+	results []kbBrowserResult
+	total   interface{}
+	cursor  int
+	status  string
+
+	// Preview of the selected result; document is set when the whole file
+	// is shown rather than the matched chunk.
+	previewing bool
+	document   bool
+	preview    []string
+	scroll     int
+
+	// Query editing
+	editing bool
+	input   []rune
+
+	width, height int
+}
+
+// runKBSearchBrowser shows search results in a full-screen terminal view
+// until the user quits. Mode, limit and query changes re-run the search.
+func runKBSearchBrowser(search kbSearchFunc, query, mode string, limit int) error {
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(in) || !term.IsTerminal(out) {
+		return fmt.Errorf("--interactive requires a terminal")
+	}
+	if limit <= 0 {
+		limit = kbBrowserDefaultLimit
+	}
+
+	state, err := term.MakeRaw(in)
+	if err != nil {
+		return fmt.Errorf("enter raw mode: %w", err)
+	}
+	defer term.Restore(in, state)
+
+	// Alternate screen with the cursor hidden; both are undone on exit
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	b := &kbBrowser{search: search, query: query, mode: mode, limit: limit}
+	b.refresh()
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		b.width, b.height, err = term.GetSize(out)
+		if err != nil || b.width <= 0 || b.height <= 0 {
+			b.width, b.height = 80, 24
+		}
+		b.render(os.Stdout)
+
+		key, err := readKey(reader)
+		if err != nil {
+			return nil
+		}
+		if b.handleKey(key) {
+			return nil
+		}
+	}
+}
+
+// readKey reads one keypress, decoding the arrow and paging escape
+// sequences sent by terminals in raw mode.
+func readKey(r *bufio.Reader) (rune, error) {
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	// A lone escape arrives without a sequence already buffered behind it
+	if ch != '\x1b' || r.Buffered() == 0 {
+		if ch == '\x1b' {
+			return keyEscape, nil
+		}
+		return ch, nil
+	}
+
+	if next, _ := r.ReadByte(); next != '[' && next != 'O' {
+		return keyEscape, nil
+	}
+	code, _ := r.ReadByte()
+	switch code {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case '5', '6':
+		r.ReadByte() // trailing '~'
+		if code == '5' {
+			return keyPageUp, nil
+		}
+		return keyPageDown, nil
+	}
+	return keyEscape, nil
+}
+
+// handleKey applies a keypress and reports whether the browser should exit.
+func (b *kbBrowser) handleKey(key rune) bool {
+	if key == 3 { // Ctrl-C
+		return true
+	}
+	b.status = ""
+
+	switch {
+	case b.editing:
+		b.handleEditKey(key)
+	case b.previewing:
+		b.handlePreviewKey(key)
+	default:
+		return b.handleListKey(key)
+	}
+	return false
+}
+
+func (b *kbBrowser) handleListKey(key rune) bool {
+	switch key {
+	case 'q', keyEscape:
+		return true
+	case keyUp, 'k':
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case keyDown, 'j':
+		if b.cursor < len(b.results)-1 {
+			b.cursor++
+		}
+	case '\r', '\n', 'p':
+		b.openPreview(false)
+	case 'd':
+		b.openPreview(true)
+	case 'm':
+		b.mode = nextKBBrowserMode(b.mode)
+		b.refresh()
+	case '+', '=':
+		if b.limit < kbBrowserMaxLimit {
+			b.limit = min(b.limit+5, kbBrowserMaxLimit)
+			b.refresh()
+		}
+	case '-', '_':
+		if b.limit > 1 {
+			b.limit = max(b.limit-5, 1)
+			b.refresh()
+		}
+	case 'r':
+		b.refresh()
+	case '/':
+		b.editing = true
+		b.input = []rune(b.query)
+	}
+	return false
+}
+
+func (b *kbBrowser) handlePreviewKey(key rune) {
+	page := max(b.height-3, 1)
+	switch key {
+	case 'q', keyEscape, '\r', '\n', 'p':
+		b.previewing = false
+	case 'd':
+		b.openPreview(!b.document)
+	case keyUp, 'k':
+		b.scrollPreview(-1)
+	case keyDown, 'j':
+		b.scrollPreview(1)
+	case keyPageUp, 'b':
+		b.scrollPreview(-page)
+	case keyPageDown, ' ':
+		b.scrollPreview(page)
+	}
+}
+
+func (b *kbBrowser) handleEditKey(key rune) {
+	switch key {
+	case keyEscape:
+		b.editing = false
+	case '\r', '\n':
+		b.editing = false
+		if q := strings.TrimSpace(string(b.input)); q != "" {
+			b.query = q
+			b.refresh()
+		}
+	case 127, '\b':
+		if len(b.input) > 0 {
+			b.input = b.input[:len(b.input)-1]
+		}
+	default:
+		if key > 0 && unicode.IsPrint(key) {
+			b.input = append(b.input, key)
+		}
+	}
+}
+
+// refresh re-runs the search with the current query, mode and limit.
+func (b *kbBrowser) refresh() {
+	b.cursor = 0
+	b.results = nil
+	b.total = 0
+	b.status = ""
+
+	data, err := b.search(b.query, b.mode, b.limit)
+	if err != nil {
+		b.status = fmt.Sprintf("search failed: %v", err)
+		return
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		b.status = fmt.Sprintf("search failed: %v", err)
+		return
+	}
+	if _, failed := resp["error"]; failed {
+		b.status = "search failed: " + daemonErrorMessage(data)
+		return
+	}
+
+	results, _ := resp["results"].([]interface{})
+	for _, r := range results {
+		if result, ok := r.(map[string]interface{}); ok {
+			b.results = append(b.results, newKBBrowserResult(result))
+		}
+	}
+	b.total = resp["total_hits"]
+}
+
+// newKBBrowserResult extracts the display fields from a processed or raw
+// search result.
+func newKBBrowserResult(result map[string]interface{}) kbBrowserResult {
+	path, _ := result["path"].(string)
+	content, _ := result["content"].(string)
+	if content == "" {
+		content, _ = result["snippet"].(string)
+	}
+
+	label := filepath.Base(path)
+	if source, ok := result["source"].(map[string]interface{}); ok {
+		if breadcrumb, ok := source["breadcrumb"].(string); ok && breadcrumb != "" {
+			label = breadcrumb
+		}
+	}
+	if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+		if headingPath, ok := metadata["heading_path"].(string); ok && headingPath != "" {
+			label += " › " + headingPath
+		}
+	}
+
+	return kbBrowserResult{Label: label, Path: path, Content: content}
+}
+
+// openPreview shows the selected result's chunk, or with document set the
+// whole file it came from. Documents are read from disk: the CLI runs on the
+// same machine as the indexed sources.
+func (b *kbBrowser) openPreview(document bool) {
+	if len(b.results) == 0 {
+		return
+	}
+	result := b.results[b.cursor]
+
+	text := result.Content
+	if document {
+		data, err := os.ReadFile(result.Path)
+		if err != nil {
+			b.status = fmt.Sprintf("cannot open document: %v", err)
+			return
+		}
+		text = string(data)
+	}
+
+	b.previewing = true
+	b.document = document
+	b.preview = wrapText(text, max(b.width, 20))
+	b.scroll = 0
+}
+
+func (b *kbBrowser) scrollPreview(delta int) {
+	b.scroll = max(min(b.scroll+delta, len(b.preview)-1), 0)
+}
+
+// render draws the whole screen. Lines end in \r\n because raw mode turns
+// off output newline translation.
+func (b *kbBrowser) render(w io.Writer) {
+	var lines []string
+	header := fmt.Sprintf("Search: %s   mode: %s   limit: %d   results: %v", b.query, b.mode, b.limit, b.total)
+	lines = append(lines, "\x1b[1m"+truncateLine(header, b.width)+"\x1b[0m", "")
+
+	body := max(b.height-4, 1)
+	var help string
+
+	if b.previewing {
+		result := b.results[b.cursor]
+		what := "chunk"
+		if b.document {
+			what = "document"
+		}
+		lines[1] = "\x1b[2m" + truncateLine(fmt.Sprintf("%s (%s)", result.Path, what), b.width) + "\x1b[0m"
+		end := min(b.scroll+body, len(b.preview))
+		lines = append(lines, b.preview[b.scroll:end]...)
+		help = "↑/↓ scroll  space/b page  d chunk/document  q back"
+	} else {
+		// Keep the cursor inside the visible window of results
+		offset := 0
+		if b.cursor >= body {
+			offset = b.cursor - body + 1
+		}
+		if len(b.results) == 0 && b.status == "" {
+			lines = append(lines, "No results found for: "+b.query)
+		}
+		end := min(offset+body, len(b.results))
+		for i := offset; i < end; i++ {
+			line := truncateLine(fmt.Sprintf("%3d. %s  %s", i+1, b.results[i].Label, b.results[i].Path), b.width)
+			if i == b.cursor {
+				line = "\x1b[7m" + line + "\x1b[0m"
+			}
+			lines = append(lines, line)
+		}
+		help = "↑/↓ move  enter preview  d document  m mode  +/- limit  / query  q quit"
+	}
+
+	for len(lines) < b.height-1 {
+		lines = append(lines, "")
+	}
+
+	footer := help
+	if b.status != "" {
+		footer = b.status
+	}
+	if b.editing {
+		footer = "New query: " + string(b.input) + "█"
+	}
+	lines = append(lines[:b.height-1], "\x1b[2m"+truncateLine(footer, b.width)+"\x1b[0m")
+
+	fmt.Fprint(w, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+}
+
+// nextKBBrowserMode returns the search mode after mode.
+func nextKBBrowserMode(mode string) string {
+	for i, m := range kbBrowserModes {
+		if m == mode {
+			return kbBrowserModes[(i+1)%len(kbBrowserModes)]
+		}
+	}
+	return kbBrowserModes[0]
+}
+
+// truncateLine cuts s to width runes, marking the cut with an ellipsis.
+func truncateLine(s string, width int) string {
+	r := []rune(s)
+	if width <= 0 || len(r) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// wrapText splits text into lines of at most width runes, breaking at the
+// last space where possible. Tabs are expanded so widths stay predictable.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\t", "    "), "\n") {
+		r := []rune(strings.TrimRight(para, "\r"))
+		for len(r) > width {
+			cut := width
+			for i := width; i > width/2; i-- {
+				if r[i] == ' ' {
+					cut = i
+					break
+				}
+			}
+			lines = append(lines, string(r[:cut]))
+			r = r[cut:]
+			if len(r) > 0 && r[0] == ' ' {
+				r = r[1:]
+			}
+		}
+		lines = append(lines, string(r))
+	}
+	return lines
+}
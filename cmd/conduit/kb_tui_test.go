@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeKBSearch returns n results for every search and records the requests.
+type fakeKBSearch struct {
+	n     int
+	calls []string
+}
+
+func (f *fakeKBSearch) search(query, mode string, limit int) ([]byte, error) {
+	f.calls = append(f.calls, fmt.Sprintf("%s/%s/%d", query, mode, limit))
+	results := make([]string, f.n)
+	for i := range results {
+		results[i] = fmt.Sprintf(`{"path": "/docs/doc%d.md", "content": "chunk %d"}`, i, i)
+	}
+	return []byte(fmt.Sprintf(`{"results": [%s], "total_hits": %d}`, strings.Join(results, ","), f.n)), nil
+}
+
+func newTestKBBrowser(f *fakeKBSearch) *kbBrowser {
+	b := &kbBrowser{search: f.search, query: "quota", mode: "hybrid", limit: 10, width: 80, height: 10}
+	b.refresh()
+	return b
+}
+
+func TestReadKey(t *testing.T) {
+	tests := map[string]rune{
+		"j":       'j',
+		"\x1b[A":  keyUp,
+		"\x1b[B":  keyDown,
+		"\x1bOA":  keyUp,
+		"\x1b[5~": keyPageUp,
+		"\x1b[6~": keyPageDown,
+		"\x1b":    keyEscape,
+	}
+	for input, want := range tests {
+		got, err := readKey(bufio.NewReader(strings.NewReader(input)))
+		if err != nil || got != want {
+			t.Errorf("readKey(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+}
+
+func TestKBBrowserListKeys(t *testing.T) {
+	f := &fakeKBSearch{n: 3}
+	b := newTestKBBrowser(f)
+
+	// The cursor stays within the results
+	b.handleKey(keyUp)
+	if b.cursor != 0 {
+		t.Errorf("cursor moved above the first result: %d", b.cursor)
+	}
+	for i := 0; i < 5; i++ {
+		b.handleKey('j')
+	}
+	if b.cursor != 2 {
+		t.Errorf("cursor = %d, want the last result", b.cursor)
+	}
+
+	// Mode and limit changes re-run the search from the top
+	b.handleKey('m')
+	if b.mode != "semantic" || b.cursor != 0 {
+		t.Errorf("after m: mode %q, cursor %d", b.mode, b.cursor)
+	}
+	b.handleKey('+')
+	b.handleKey('-')
+	b.handleKey('-')
+	if b.limit != 5 {
+		t.Errorf("limit = %d, want 5", b.limit)
+	}
+	want := []string{"quota/hybrid/10", "quota/semantic/10", "quota/semantic/15", "quota/semantic/10", "quota/semantic/5"}
+	if strings.Join(f.calls, " ") != strings.Join(want, " ") {
+		t.Errorf("searches = %v, want %v", f.calls, want)
+	}
+
+	if !b.handleKey('q') {
+		t.Error("expected q to quit the list")
+	}
+	if !b.handleKey(3) {
+		t.Error("expected Ctrl-C to quit")
+	}
+}
+
+func TestKBBrowserLimitBounds(t *testing.T) {
+	b := newTestKBBrowser(&fakeKBSearch{n: 1})
+	b.limit = kbBrowserMaxLimit - 2
+	b.handleKey('+')
+	if b.limit != kbBrowserMaxLimit {
+		t.Errorf("limit = %d, want the maximum %d", b.limit, kbBrowserMaxLimit)
+	}
+	b.limit = 3
+	b.handleKey('-')
+	if b.limit != 1 {
+		t.Errorf("limit = %d, want 1", b.limit)
+	}
+}
+
+func TestKBBrowserQueryEditing(t *testing.T) {
+	f := &fakeKBSearch{n: 1}
+	b := newTestKBBrowser(f)
+
+	b.handleKey('/')
+	for _, key := range []rune{127, 127, 127, 127, 127, 'l', 'o', 'g', 's'} {
+		b.handleKey(key)
+	}
+	// Keys are text while editing, so q does not quit
+	if b.handleKey('q') {
+		t.Fatal("q quit while editing the query")
+	}
+	b.handleKey(127)
+	b.handleKey('\r')
+	if b.editing || b.query != "logs" {
+		t.Errorf("after enter: editing %v, query %q", b.editing, b.query)
+	}
+	if f.calls[len(f.calls)-1] != "logs/hybrid/10" {
+		t.Errorf("expected a search for the new query, got %v", f.calls)
+	}
+
+	// Escape and blank queries leave the search unchanged
+	searches := len(f.calls)
+	b.handleKey('/')
+	b.handleKey('x')
+	b.handleKey(keyEscape)
+	b.handleKey('/')
+	b.input = []rune("  ")
+	b.handleKey('\r')
+	if b.query != "logs" || len(f.calls) != searches {
+		t.Errorf("query %q after cancelled edits, %d extra searches", b.query, len(f.calls)-searches)
+	}
+}
+
+func TestKBBrowserPreviewPaging(t *testing.T) {
+	f := &fakeKBSearch{n: 2}
+	b := newTestKBBrowser(f)
+	b.handleKey('j')
+
+	var text []string
+	for i := 0; i < 30; i++ {
+		text = append(text, fmt.Sprintf("line %d", i))
+	}
+	b.results[1].Content = strings.Join(text, "\n")
+
+	b.handleKey('\r')
+	if !b.previewing || b.document || len(b.preview) != 30 {
+		t.Fatalf("expected the chunk preview, got previewing %v document %v lines %d", b.previewing, b.document, len(b.preview))
+	}
+
+	// A page is the screen height less the header and footer
+	b.handleKey(' ')
+	if b.scroll != 7 {
+		t.Errorf("scroll after page down = %d, want 7", b.scroll)
+	}
+	b.handleKey(keyDown)
+	b.handleKey(keyPageUp)
+	b.handleKey(keyUp)
+	if b.scroll != 0 {
+		t.Errorf("scroll = %d, want 0", b.scroll)
+	}
+	for i := 0; i < 10; i++ {
+		b.handleKey(keyPageDown)
+	}
+	if b.scroll != 29 {
+		t.Errorf("scroll = %d, want the last line", b.scroll)
+	}
+
+	if b.handleKey('q') || b.previewing {
+		t.Error("expected q to close the preview without quitting")
+	}
+	if b.cursor != 1 {
+		t.Errorf("cursor = %d after closing the preview, want 1", b.cursor)
+	}
+
+	// A document that cannot be read stays in the list with an error
+	b.handleKey('d')
+	if b.previewing || !strings.Contains(b.status, "cannot open document") {
+		t.Errorf("expected an error for a missing document, got previewing %v status %q", b.previewing, b.status)
+	}
+}
+
+func TestKBBrowserEmptyState(t *testing.T) {
+	b := newTestKBBrowser(&fakeKBSearch{})
+
+	// Navigation and preview do nothing without results
+	for _, key := range []rune{'j', 'k', '\r', 'd'} {
+		if b.handleKey(key) {
+			t.Fatalf("key %q quit the browser", key)
+		}
+	}
+	if b.cursor != 0 || b.previewing {
+		t.Errorf("cursor %d, previewing %v without results", b.cursor, b.previewing)
+	}
+
+	var out bytes.Buffer
+	b.render(&out)
+	if !strings.Contains(out.String(), "No results found for: quota") {
+		t.Errorf("expected the empty state, got %q", out.String())
+	}
+
+	// A failed search shows the error instead
+	b.search = func(query, mode string, limit int) ([]byte, error) {
+		return nil, errors.New("daemon not running")
+	}
+	b.refresh()
+	out.Reset()
+	b.render(&out)
+	if strings.Contains(out.String(), "No results found") || !strings.Contains(out.String(), "search failed: daemon not running") {
+		t.Errorf("expected the search error, got %q", out.String())
+	}
+}
+
+func TestNextKBBrowserMode(t *testing.T) {
+	mode := "hybrid"
+	for _, want := range []string{"semantic", "fts5", "hybrid"} {
+		mode = nextKBBrowserMode(mode)
+		if mode != want {
+			t.Errorf("next mode = %q, want %q", mode, want)
+		}
+	}
+	if got := nextKBBrowserMode("unknown"); got != "hybrid" {
+		t.Errorf("next mode after an unknown mode = %q", got)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	got := wrapText("the quick brown fox jumps\n\tover", 10)
+	want := []string{"the quick", "brown fox", "jumps", "    over"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapText = %q, want %q", got, want)
+	}
+	if got := truncateLine("abcdef", 4); got != "abc…" {
+		t.Errorf("truncateLine = %q", got)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
@@ -15,22 +17,35 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 
+	"github.com/simpleflo/conduit/internal/adapters"
 	"github.com/simpleflo/conduit/internal/ai"
 	"github.com/simpleflo/conduit/internal/config"
+	"github.com/simpleflo/conduit/internal/diskspace"
 	"github.com/simpleflo/conduit/internal/installer"
 	"github.com/simpleflo/conduit/internal/kb"
+	"github.com/simpleflo/conduit/internal/lifecycle"
+	"github.com/simpleflo/conduit/internal/observability"
+	"github.com/simpleflo/conduit/internal/policy"
 	containerRuntime "github.com/simpleflo/conduit/internal/runtime"
+	"github.com/simpleflo/conduit/internal/secrets"
 	"github.com/simpleflo/conduit/internal/store"
+	"github.com/simpleflo/conduit/pkg/models"
 )
 
 var (
@@ -51,16 +66,147 @@ func newClient(socketPath string) *client {
 }
 
 func newClientWithTimeout(socketPath string, timeout time.Duration) *client {
+	address := socketPath
+	if apiURL != "" {
+		address = apiURL
+	}
 	return &client{
 		httpClient: &http.Client{
-			Transport: &http.Transport{
-				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
-					return net.Dial("unix", socketPath)
-				},
+			Transport: &loggingTransport{
+				address: address,
+				base:    daemonTransport(socketPath),
 			},
 			Timeout: timeout,
 		},
-		baseURL: "http://localhost",
+		baseURL: daemonBaseURL(),
+	}
+}
+
+// daemonTransport connects to the daemon on socketPath, or on its TCP
+// listener with the API token when --api-url is set.
+func daemonTransport(socketPath string) http.RoundTripper {
+	if apiURL != "" {
+		if apiToken == "" {
+			// Shell completions run without the root command's pre-run
+			apiToken, _ = resolveAPIToken()
+		}
+		return &bearerTransport{token: apiToken, base: http.DefaultTransport}
+	}
+	return &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+}
+
+// daemonBaseURL is the URL daemon API paths are appended to.
+func daemonBaseURL() string {
+	if apiURL != "" {
+		return strings.TrimSuffix(apiURL, "/")
+	}
+	return "http://localhost"
+}
+
+// bearerTransport adds the API token the daemon's TCP listener requires.
+type bearerTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
+
+// resolveAPIToken finds the token for the daemon's TCP listener: the
+// CONDUIT_API_TOKEN environment variable, else api.tcp.token from the
+// config, else the token file conduit setup writes.
+func resolveAPIToken() (string, error) {
+	if token := os.Getenv("CONDUIT_API_TOKEN"); token != "" {
+		return token, nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("load config: %w", err)
+	}
+	if cfg.API.TCP.Token != "" {
+		return cfg.API.TCP.Token, nil
+	}
+	data, err := os.ReadFile(cfg.APITokenPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no API token for %s: run 'conduit setup' or set CONDUIT_API_TOKEN", apiURL)
+		}
+		return "", fmt.Errorf("read API token: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// loggingTransport logs every daemon request at debug level on cliLogger,
+// so --verbose shows what the CLI is doing and how the daemon answered.
+type loggingTransport struct {
+	address string // Socket path or API URL
+	base    http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	event := cliLogger.Debug().
+		Str("daemon", t.address).
+		Str("method", req.Method).
+		Str("path", req.URL.RequestURI()).
+		Str("duration", time.Since(start).Round(time.Microsecond).String())
+	if err != nil {
+		event.Err(err).Msg("daemon request failed")
+		return nil, err
+	}
+	event.Int("status", resp.StatusCode).Msg("daemon request")
+	return resp, nil
+}
+
+// waitReady polls the daemon's readiness endpoint with backoff until it
+// reports ready or timeout elapses. Right after a service start the daemon is
+// still opening its database and warming up search, so commands wait here
+// instead of failing with connection errors. A zero timeout skips the wait.
+func (c *client) waitReady(timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	backoff := 100 * time.Millisecond
+	announced := false
+
+	for {
+		resp, err := c.httpClient.Get(c.baseURL + "/api/v1/ready")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			if resp.StatusCode == http.StatusUnauthorized {
+				return fmt.Errorf("daemon rejected the API token (check CONDUIT_API_TOKEN, api.tcp.token or ~/.conduit/api.token)")
+			}
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("daemon not ready after %s (is it running? start it with 'conduit service start')", timeout)
+		}
+		cliLogger.Debug().Str("backoff", backoff.String()).Msg("daemon not ready, retrying")
+		if !announced {
+			// stderr keeps --json output on stdout clean
+			fmt.Fprintln(os.Stderr, "⏳ Daemon starting, waiting for it to be ready...")
+			announced = true
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > 2*time.Second {
+			backoff = 2 * time.Second
+		}
 	}
 }
 
@@ -90,6 +236,24 @@ func (c *client) post(path string, body interface{}) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
+func (c *client) put(path string, body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPut, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
 func (c *client) delete(path string) error {
 	req, _ := http.NewRequest(http.MethodDelete, c.baseURL+path, nil)
 	resp, err := c.httpClient.Do(req)
@@ -112,6 +276,33 @@ func (c *client) deleteWithResponse(path string) ([]byte, error) {
 
 var socketPath string
 
+// apiURL is the daemon's TCP listener (--api-url), used instead of the socket
+// when set; apiToken authenticates to it.
+var (
+	apiURL   string
+	apiToken string
+)
+
+// readyTimeout bounds how long commands wait for a starting daemon
+var readyTimeout time.Duration
+
+// verbose and logLevel turn on the CLI's diagnostic logging to stderr
+var (
+	verbose  bool
+	logLevel string
+)
+
+// cliLogger logs the CLI's own activity. It discards everything unless
+// --verbose or --log-level is given.
+var cliLogger = zerolog.Nop()
+
+// outputFormat is the --output format: table for people, json or yaml for
+// scripts.
+var outputFormat string
+
+// outputFormats lists the values accepted by --output.
+var outputFormats = []string{"table", "json", "yaml"}
+
 func main() {
 	rootCmd := &cobra.Command{
 		Use:   "conduit",
@@ -122,12 +313,38 @@ AI clients to MCP servers and your private knowledge base.
 Configure once, works everywhere - across Claude Code, Cursor,
 VS Code, Gemini CLI, and more.`,
 		Version: fmt.Sprintf("%s (built %s)", Version, BuildTime),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := setupCLILogging(); err != nil {
+				return err
+			}
+			if apiURL != "" {
+				token, err := resolveAPIToken()
+				if err != nil {
+					return err
+				}
+				apiToken = token
+			}
+			return resolveOutputFormat(cmd)
+		},
 	}
 
 	// Global flags
 	defaultSocket := getDefaultSocketPath()
 	rootCmd.PersistentFlags().StringVar(&socketPath, "socket", defaultSocket,
 		"Unix socket path for daemon communication")
+	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", os.Getenv("CONDUIT_API_URL"),
+		"Daemon TCP listener to use instead of the socket, e.g. http://127.0.0.1:9470 (env CONDUIT_API_URL)")
+	rootCmd.PersistentFlags().DurationVar(&readyTimeout, "ready-timeout", 15*time.Second,
+		"How long to wait for a starting daemon to become ready (0 to not wait)")
+	// No -v shorthand: it is cobra's version flag, and doctor, qdrant status
+	// and falkordb status use -v for their own detailed output
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false,
+		"Log daemon requests to stderr (same as --log-level debug)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "",
+		"Log level for stderr diagnostics: debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table",
+		"Output format: table, json or yaml")
+	rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(outputFormats, cobra.ShellCompDirectiveNoFileComp))
 
 	// Add subcommands
 	rootCmd.AddCommand(setupCmd())
@@ -138,24 +355,33 @@ VS Code, Gemini CLI, and more.`,
 	rootCmd.AddCommand(listCmd())
 	rootCmd.AddCommand(startCmd())
 	rootCmd.AddCommand(stopCmd())
+	rootCmd.AddCommand(restartCmd())
 	rootCmd.AddCommand(removeCmd())
+	rootCmd.AddCommand(gcCmd())
 	rootCmd.AddCommand(createCmd())
 	rootCmd.AddCommand(statsCmd())
 	rootCmd.AddCommand(permissionsCmd())
+	rootCmd.AddCommand(policyCmd())
+	rootCmd.AddCommand(secretsCmd())
 	rootCmd.AddCommand(auditCmd())
 	rootCmd.AddCommand(logsCmd())
 	rootCmd.AddCommand(clientCmd())
+	rootCmd.AddCommand(bindCmd())
 	rootCmd.AddCommand(kbCmd())
 	rootCmd.AddCommand(mcpCmd())
 	rootCmd.AddCommand(doctorCmd())
+	rootCmd.AddCommand(versionCmd())
 	rootCmd.AddCommand(uninstallCmd())
 	rootCmd.AddCommand(serviceCmd())
 	rootCmd.AddCommand(configCmd())
 	rootCmd.AddCommand(backupCmd())
+	rootCmd.AddCommand(exportCmd())
+	rootCmd.AddCommand(importCmd())
 	rootCmd.AddCommand(qdrantCmd())
 	rootCmd.AddCommand(falkordbCmd())
 	rootCmd.AddCommand(ollamaCmd())
 	rootCmd.AddCommand(eventsCmd())
+	rootCmd.AddCommand(completionCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -170,6 +396,212 @@ func getDefaultSocketPath() string {
 	return filepath.Join(homeDir, ".conduit", "conduit.sock")
 }
 
+// newInstaller creates an installer that checks the daemon on the --socket
+// the CLI talks to and keeps the configured disk space free.
+func newInstaller(verbose bool) *installer.Installer {
+	inst := installer.New(verbose)
+	inst.SetSocketPath(socketPath)
+	if cfg, err := config.Load(); err == nil {
+		inst.SetMinFree(cfg.Disk.MinFreeBytes())
+	}
+	return inst
+}
+
+// setupCLILogging enables console logging to stderr when --verbose or
+// --log-level is given. Without either the CLI stays quiet.
+func setupCLILogging() error {
+	level := logLevel
+	if level == "" {
+		if !verbose {
+			return nil
+		}
+		level = "debug"
+	}
+	if _, err := zerolog.ParseLevel(level); err != nil {
+		return fmt.Errorf("invalid --log-level %q (use debug, info, warn or error)", level)
+	}
+
+	observability.SetupLogging(level, "console", os.Stderr)
+	cliLogger = observability.Logger("cli")
+	cliLogger.Debug().Str("socket", socketPath).Str("version", Version).Msg("conduit CLI starting")
+	return nil
+}
+
+// resolveOutputFormat validates --output and reconciles it with the older
+// per-command --json flags: --json selects json output, and -o json turns on
+// a command's --json so both spellings take the same code path.
+func resolveOutputFormat(cmd *cobra.Command) error {
+	if !slices.Contains(outputFormats, outputFormat) {
+		return fmt.Errorf("invalid --output %q (use %s)", outputFormat, strings.Join(outputFormats, ", "))
+	}
+
+	jsonFlag := cmd.Flags().Lookup("json")
+	switch {
+	case jsonFlag == nil:
+	case jsonFlag.Changed && jsonFlag.Value.String() == "true":
+		outputFormat = "json"
+	case outputFormat == "json":
+		return jsonFlag.Value.Set("true")
+	}
+	return nil
+}
+
+// printOutput writes v in the structured --output format. A daemon response
+// body is passed through unchanged as JSON and converted for YAML; any other
+// value is marshalled.
+func printOutput(v interface{}) error {
+	if data, ok := v.([]byte); ok {
+		if outputFormat == "json" {
+			fmt.Println(string(data))
+			return nil
+		}
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("parse daemon response: %w", err)
+		}
+		v = doc
+	}
+
+	var data []byte
+	var err error
+	if outputFormat == "yaml" {
+		data, err = yaml.Marshal(v)
+	} else {
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// completionCmd emits shell completion scripts. Cobra would add a default
+// command; this one documents how to install the script for each shell.
+func completionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate a shell completion script",
+		Long: `Generate a shell completion script for conduit.
+
+Instance IDs, knowledge base sources and client IDs are completed from the
+running daemon. When the daemon is not running only commands and flags are
+completed.
+
+Bash (requires bash-completion):
+  source <(conduit completion bash)
+  # Persist:
+  conduit completion bash > /etc/bash_completion.d/conduit             # Linux
+  conduit completion bash > $(brew --prefix)/etc/bash_completion.d/conduit  # macOS
+
+Zsh:
+  conduit completion zsh > "${fpath[1]}/_conduit"
+  # Start a new shell (compinit must be enabled)
+
+Fish:
+  conduit completion fish > ~/.config/fish/completions/conduit.fish
+
+PowerShell:
+  conduit completion powershell | Out-String | Invoke-Expression`,
+		DisableFlagsInUseLine: true,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(os.Stdout, true)
+			case "zsh":
+				return root.GenZshCompletion(os.Stdout)
+			case "fish":
+				return root.GenFishCompletion(os.Stdout, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(os.Stdout)
+			}
+		},
+	}
+}
+
+// completionTimeout bounds daemon lookups during shell completion so a
+// stopped or busy daemon never stalls the shell.
+const completionTimeout = 2 * time.Second
+
+// completeFromDaemon completes the first argument with values from a daemon
+// list endpoint. list is the response field holding the items; entry returns
+// an item's value and its description. Lookup failures complete nothing.
+func completeFromDaemon(path, list string, entry func(item map[string]interface{}) (string, string)) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return daemonCompletions(path, list, entry), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// daemonCompletions fetches completion candidates as "value\tdescription".
+func daemonCompletions(path, list string, entry func(item map[string]interface{}) (string, string)) []string {
+	c := newClientWithTimeout(socketPath, completionTimeout)
+	data, err := c.get(path)
+	if err != nil {
+		return nil
+	}
+	var resp map[string]json.RawMessage
+	var items []map[string]interface{}
+	if json.Unmarshal(data, &resp) != nil || json.Unmarshal(resp[list], &items) != nil {
+		return nil
+	}
+
+	var completions []string
+	for _, item := range items {
+		value, desc := entry(item)
+		if value == "" {
+			continue
+		}
+		if desc != "" {
+			value += "\t" + desc
+		}
+		completions = append(completions, value)
+	}
+	return completions
+}
+
+// completeInstanceIDs completes connector instance IDs, described by their
+// display name and status.
+var completeInstanceIDs = completeFromDaemon("/api/v1/instances", "instances", func(item map[string]interface{}) (string, string) {
+	id, _ := item["instance_id"].(string)
+	name, _ := item["display_name"].(string)
+	status, _ := item["status"].(string)
+	return id, fmt.Sprintf("%s (%s)", name, status)
+})
+
+// completeKBSourceIDs completes knowledge base source IDs, described by name.
+var completeKBSourceIDs = completeFromDaemon("/api/v1/kb/sources", "sources", func(item map[string]interface{}) (string, string) {
+	id, _ := item["source_id"].(string)
+	name, _ := item["name"].(string)
+	return id, name
+})
+
+// completeKBSourceNames completes knowledge base source names, described by
+// path, for commands that accept a name or an ID.
+var completeKBSourceNames = completeFromDaemon("/api/v1/kb/sources", "sources", func(item map[string]interface{}) (string, string) {
+	name, _ := item["name"].(string)
+	path, _ := item["path"].(string)
+	return name, path
+})
+
+// completeClientIDs completes AI client IDs for --client flags.
+func completeClientIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return daemonCompletions("/api/v1/clients", "clients", func(item map[string]interface{}) (string, string) {
+		id, _ := item["client_id"].(string)
+		name, _ := item["display_name"].(string)
+		if installed, _ := item["installed"].(bool); !installed {
+			name += " (not detected)"
+		}
+		return id, name
+	}), cobra.ShellCompDirectiveNoFileComp
+}
+
 // setupCmd runs the initial setup wizard
 func setupCmd() *cobra.Command {
 	var skipDeps bool
@@ -181,7 +613,7 @@ func setupCmd() *cobra.Command {
 
 This wizard will help you:
 1. Install required dependencies (Docker/Podman, Ollama)
-2. Choose an AI provider for intelligent MCP server installation
+2. Choose an AI provider and model for intelligent MCP server installation
 3. Configure necessary settings
 4. Verify everything is working`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -197,6 +629,7 @@ This wizard will help you:
 // installDepsCmd installs Conduit dependencies
 func installDepsCmd() *cobra.Command {
 	var verbose bool
+	var assumeYes bool
 
 	cmd := &cobra.Command{
 		Use:   "install-deps",
@@ -207,15 +640,20 @@ func installDepsCmd() *cobra.Command {
 - Ollama (local AI runtime)
 - AI model (qwen2.5-coder:7b)
 
-This command will prompt for confirmation before installing each component.`,
+This command will prompt for confirmation before installing each component.
+Use --yes for unattended installs (CI, provisioning scripts): every prompt is
+confirmed and the recommended option is chosen.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			inst := installer.New(verbose)
+			inst := newInstaller(verbose)
+			inst.SetAssumeYes(assumeYes)
 			_, err := inst.CheckAndInstallAll(cmd.Context())
 			return err
 		},
 	}
 
 	cmd.Flags().BoolVar(&verbose, "verbose", false, "Show verbose output")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Confirm all prompts and choose recommended options")
+	cmd.Flags().BoolVar(&assumeYes, "non-interactive", false, "Alias for --yes")
 
 	return cmd
 }
@@ -745,7 +1183,7 @@ func runSetup(skipDeps bool) error {
 		fmt.Println()
 
 		if confirmAction("Check and install dependencies now?") {
-			inst := installer.New(false)
+			inst := newInstaller(false)
 			ctx := context.Background()
 			results, _ := inst.CheckAndInstallAll(ctx)
 
@@ -801,26 +1239,32 @@ func runSetup(skipDeps bool) error {
 	switch choice {
 	case "1", "":
 		provider = "ollama"
-		model = "qwen2.5-coder:7b"
 		fmt.Println()
 		fmt.Println("✓ Selected: Local AI (Ollama)")
 		fmt.Println()
+		model = selectOllamaModel(reader)
+		fmt.Println()
+		fmt.Printf("✓ Model: %s\n", model)
+		fmt.Println()
 		fmt.Println("Make sure Ollama is installed and running:")
 		fmt.Println("  1. Install: https://ollama.ai")
 		fmt.Println("  2. Start:   ollama serve")
 		fmt.Printf("  3. Pull:    ollama pull %s\n", model)
 	case "2":
 		provider = "anthropic"
-		model = "claude-sonnet-4-20250514"
 		fmt.Println()
 		fmt.Println("✓ Selected: Cloud AI (Anthropic)")
 		fmt.Println()
+		model = selectAnthropicModel(reader)
+		fmt.Println()
+		fmt.Printf("✓ Model: %s\n", model)
+		fmt.Println()
 		fmt.Println("Make sure to set your API key:")
 		fmt.Println("  export ANTHROPIC_API_KEY=sk-ant-...")
 	default:
 		fmt.Println("Invalid choice. Using default (Ollama).")
 		provider = "ollama"
-		model = "qwen2.5-coder:7b"
+		model = defaultOllamaModel
 	}
 
 	// Create config directory
@@ -871,6 +1315,14 @@ runtime:
 # Policy settings
 policy:
   allow_network_egress: false
+
+# Localhost TCP API for browser-based tools (off by default). Requests must
+# send the token in ~/.conduit/api.token as "Authorization: Bearer <token>".
+# api:
+#   tcp:
+#     enabled: true
+#     address: 127.0.0.1:9470
+#     cors_origins: [http://localhost:5173]
 `, provider, model)
 
 	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
@@ -880,6 +1332,15 @@ policy:
 	fmt.Println()
 	fmt.Printf("✓ Configuration written to: %s\n", configPath)
 
+	// The TCP listener is off by default; create its token now so enabling
+	// it later needs no extra step
+	tokenPath := filepath.Join(configDir, "api.token")
+	if _, err := secrets.LoadOrCreateToken(tokenPath); err != nil {
+		fmt.Printf("⚠️  Could not create API token: %v\n", err)
+	} else {
+		fmt.Printf("✓ API token for the TCP listener: %s\n", tokenPath)
+	}
+
 	// Step 2: Daemon Service Setup
 	fmt.Println()
 	fmt.Println("Step 2: Daemon Service")
@@ -889,7 +1350,7 @@ policy:
 	fmt.Println("It can be set up as a system service that starts automatically.")
 	fmt.Println()
 
-	inst := installer.New(false)
+	inst := newInstaller(false)
 
 	if confirmAction("Install daemon as a system service?") {
 		// Find the daemon binary
@@ -1127,10 +1588,9 @@ func statusCmd() *cobra.Command {
 				return fmt.Errorf("daemon not running or unreachable: %w", err)
 			}
 
-			// JSON output for GUI consumption
-			if jsonOutput {
-				fmt.Println(string(data))
-				return nil
+			// JSON/YAML output for scripts and GUI consumption
+			if outputFormat != "table" {
+				return printOutput(data)
 			}
 
 			var status map[string]interface{}
@@ -1160,6 +1620,11 @@ func statusCmd() *cobra.Command {
 			if bindings, ok := status["bindings"].(map[string]interface{}); ok {
 				fmt.Printf("   Bindings:  %v\n", bindings["total"])
 			}
+			if ops, ok := status["operations"].(map[string]interface{}); ok {
+				if running, ok := ops["running"].(float64); ok && running > 0 {
+					fmt.Printf("   Operations: %d running\n", int(running))
+				}
+			}
 
 			// Dependencies section - from daemon
 			fmt.Println()
@@ -1247,6 +1712,8 @@ func statusCmd() *cobra.Command {
 								fmt.Printf(" (v%s)", version)
 							}
 							fmt.Println()
+						} else {
+							fmt.Println("   Full-Text Search:  ✗ FTS5 unavailable (daemon built without -tags fts5)")
 						}
 					}
 				}
@@ -1327,6 +1794,17 @@ func statusCmd() *cobra.Command {
 					fmt.Println("   FalkorDB: ○ Not running")
 				}
 
+				// Graph search readiness as reported by the daemon
+				if deps, ok := status["dependencies"].(map[string]interface{}); ok {
+					if kg, ok := deps["knowledge_graph"].(map[string]interface{}); ok {
+						if available, _ := kg["search_available"].(bool); available {
+							fmt.Println("   Search:   ✓ Graph search ready")
+						} else if reason, ok := kg["reason"].(string); ok {
+							fmt.Printf("   Search:   ○ Unavailable: %s\n", reason)
+						}
+					}
+				}
+
 				// Get KAG stats from database
 				homeDir, _ := os.UserHomeDir()
 				dbPath := filepath.Join(homeDir, ".conduit", "conduit.db")
@@ -1348,8 +1826,8 @@ func statusCmd() *cobra.Command {
 					db.DB().QueryRow(`
 						SELECT COUNT(*) FROM kb_chunks c
 						LEFT JOIN kb_extraction_status s ON c.chunk_id = s.chunk_id
-						WHERE s.status IS NULL
-					`).Scan(&pending)
+						WHERE s.status IS NULL AND ` + kb.ExtractableChunkFilter,
+					).Scan(&pending)
 					db.DB().QueryRow(`
 						SELECT COUNT(*) FROM kb_extraction_status WHERE status = 'error'
 					`).Scan(&errors)
@@ -1382,6 +1860,13 @@ func installCmd() *cobra.Command {
 	var skipBuild bool
 	var dryRun bool
 	var documentTools bool
+	var assumeYes bool
+	var noCache bool
+	var force bool
+	var minConfidence float64
+	var token string
+	var platform string
+	var timeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "install [url]",
@@ -1396,19 +1881,56 @@ For MCP server installation, Conduit will:
 4. Build the container
 5. Optionally add it to your AI clients (Claude Code, etc.)
 
+Analysis results are cached per repository commit, so re-installing an
+unchanged repository skips the AI step. Use --no-cache to force re-analysis.
+
+The generated Dockerfile is validated before building; builds are refused
+when it has errors unless --force is given.
+
+If the analysis confidence is below ai.confidence_threshold (or
+--min-confidence for this install), you are asked whether to continue;
+--yes continues without asking.
+
+Each AI request is limited to ai.timeout_seconds. Large repositories or
+slow local models may need longer: --timeout overrides the limit for this
+install without editing the config.
+
+For monorepos, add the server's path to the URL (or paste a GitHub
+/tree/<branch>/<path> link). Only that directory is analyzed and built,
+and the instance and image are named after it. When the directory relies
+on lockfiles at the repository root (npm/pnpm/yarn workspaces, uv, Cargo,
+go.work), the repository root is used as the build context instead.
+
+Images are built for the host's platform (linux/arm64 on Apple Silicon,
+linux/amd64 on most other machines). Use --platform to cross-build, for
+example when the image will run on a different machine; the platform is
+recorded on the instance so it is started with the same architecture.
+
+Private repositories can be cloned over SSH (git@github.com:owner/repo)
+with your SSH keys, or over HTTPS with a token from --token, GITHUB_TOKEN
+or GH_TOKEN (in that order). The token is only handed to git for the
+clone; it is never logged or saved.
+
 For document tools installation (--document-tools):
 Installs pdftotext, antiword, unrtf for indexing PDF, DOC, and RTF files.
 
 Examples:
   conduit install https://github.com/7nohe/local-mcp-server-sample
   conduit install github.com/modelcontextprotocol/servers/src/filesystem
+  conduit install https://github.com/modelcontextprotocol/servers/tree/main/src/git
   conduit install https://github.com/user/mcp-server --name "My Server"
+  conduit install https://github.com/user/mcp-server --min-confidence 0.8 --yes
+  conduit install https://github.com/user/big-server --timeout 10m
+  conduit install https://github.com/user/mcp-server --platform linux/amd64
+  GITHUB_TOKEN=ghp_... conduit install https://github.com/org/private-server
+  conduit install git@github.com:org/private-server.git
   conduit install --document-tools`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Handle --document-tools flag
 			if documentTools {
-				inst := installer.New(false)
+				inst := newInstaller(false)
+				inst.SetAssumeYes(assumeYes)
 				_, err := inst.InstallDocumentToolsOnly(cmd.Context())
 				return err
 			}
@@ -1418,8 +1940,61 @@ Examples:
 				return fmt.Errorf("URL required for MCP server installation. Use --document-tools to install document extraction tools")
 			}
 
+			opts := installOptions{
+				customName:       name,
+				providerOverride: provider,
+				skipBuild:        skipBuild,
+				dryRun:           dryRun,
+				noCache:          noCache,
+				force:            force,
+				assumeYes:        assumeYes,
+				minConfidence:    -1,
+				token:            token,
+				platform:         platform,
+			}
+			if opts.platform == "" {
+				opts.platform = containerRuntime.HostPlatform()
+			} else if err := containerRuntime.ValidatePlatform(opts.platform); err != nil {
+				return err
+			}
+			if opts.token == "" {
+				opts.token = os.Getenv("GITHUB_TOKEN")
+			}
+			if opts.token == "" {
+				opts.token = os.Getenv("GH_TOKEN")
+			}
+			if cmd.Flags().Changed("min-confidence") {
+				if minConfidence < 0 || minConfidence > 1 {
+					return fmt.Errorf("--min-confidence must be between 0 and 1")
+				}
+				opts.minConfidence = minConfidence
+			}
+			if cmd.Flags().Changed("timeout") {
+				secs, err := wholeSeconds(timeout)
+				if err != nil {
+					return fmt.Errorf("--timeout %w", err)
+				}
+				opts.timeoutSeconds = secs
+			}
+
+			// Ctrl-C cancels the in-flight step (AI request, clone or build)
+			// and lets runInstall clean up. Default handling is restored
+			// after the first signal, so a second Ctrl-C exits immediately.
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				stop()
+			}()
+
 			repoURL := args[0]
-			return runInstall(cmd.Context(), repoURL, name, provider, skipBuild, dryRun)
+			if err := runInstall(ctx, repoURL, opts); err != nil {
+				if ctx.Err() != nil {
+					return errors.New("installation cancelled")
+				}
+				return err
+			}
+			return nil
 		},
 	}
 
@@ -1428,12 +2003,44 @@ Examples:
 	cmd.Flags().BoolVar(&skipBuild, "skip-build", false, "Skip Docker build (just analyze)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be done without doing it")
 	cmd.Flags().BoolVar(&documentTools, "document-tools", false, "Install document extraction tools (pdftotext, antiword, unrtf)")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Don't prompt (low-confidence analysis, document tools install)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Ignore cached analysis and re-run the AI")
+	cmd.Flags().BoolVar(&force, "force", false, "Build even if the generated Dockerfile fails validation")
+	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0, "Confidence threshold (0-1) for this install, overriding ai.confidence_threshold")
+	cmd.Flags().StringVar(&platform, "platform", "", "Platform to build the image for, e.g. linux/amd64 or linux/arm64 (default: the host's)")
+	cmd.Flags().StringVar(&token, "token", "", "Access token for cloning a private repository over HTTPS (default $GITHUB_TOKEN or $GH_TOKEN)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Timeout for each AI request, e.g. 5m (default: ai.timeout_seconds)")
 
 	return cmd
 }
 
+// installOptions are the flags controlling runInstall.
+type installOptions struct {
+	customName       string
+	providerOverride string
+	skipBuild        bool
+	dryRun           bool
+	noCache          bool
+	force            bool
+	assumeYes        bool
+	minConfidence    float64 // negative means use the config threshold
+	token            string  // repository access token; never logged
+	platform         string  // image platform, e.g. linux/arm64
+	timeoutSeconds   int     // per-request AI timeout; zero means use the config
+}
+
+// wholeSeconds converts a timeout flag to the whole seconds the config
+// takes. It rounds up, since a sub-second timeout would otherwise become
+// zero, which means no limit.
+func wholeSeconds(d time.Duration) (int, error) {
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	return int((d + time.Second - 1) / time.Second), nil
+}
+
 // runInstall performs the intelligent installation
-func runInstall(ctx context.Context, repoURL, customName, providerOverride string, skipBuild, dryRun bool) error {
+func runInstall(ctx context.Context, repoURL string, opts installOptions) error {
 	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
 	fmt.Println("║              Conduit Intelligent MCP Installer               ║")
 	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
@@ -1455,8 +2062,23 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 		ConfidenceThreshold: cfg.AI.ConfidenceThreshold,
 	}
 
-	if providerOverride != "" {
-		aiConfig.Provider = providerOverride
+	if opts.providerOverride != "" {
+		aiConfig.Provider = opts.providerOverride
+	}
+
+	timeoutSource := "config ai.timeout_seconds"
+	if opts.timeoutSeconds > 0 {
+		aiConfig.TimeoutSeconds = opts.timeoutSeconds
+		timeoutSource = "--timeout"
+	}
+	cliLogger.Debug().
+		Int("timeout_seconds", aiConfig.TimeoutSeconds).
+		Str("source", timeoutSource).
+		Msg("AI request timeout")
+
+	threshold, thresholdSource := cfg.AI.ConfidenceThreshold, "config ai.confidence_threshold"
+	if opts.minConfidence >= 0 {
+		threshold, thresholdSource = opts.minConfidence, "--min-confidence"
 	}
 
 	// Create AI manager
@@ -1464,6 +2086,8 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 	if err != nil {
 		return fmt.Errorf("create AI manager: %w", err)
 	}
+	aiManager.SetNoCache(opts.noCache)
+	aiManager.SetRepoToken(opts.token)
 
 	// Check AI provider availability
 	fmt.Printf("🤖 AI Provider: %s\n", aiManager.ProviderName())
@@ -1500,9 +2124,30 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 	fmt.Println("📊 Analysis Results")
 	fmt.Println("──────────────────────────────────────────────────────────────")
 	fmt.Printf("   Repository: %s/%s\n", fetchResult.Owner, fetchResult.RepoName)
+	if fetchResult.Ref != "" {
+		fmt.Printf("   Ref:        %s\n", fetchResult.Ref)
+	}
+	if fetchResult.Subdir != "" {
+		fmt.Printf("   Package:    %s\n", fetchResult.Subdir)
+		if len(fetchResult.RootFiles) > 0 {
+			fmt.Printf("   Build from: repository root (uses %s)\n", strings.Join(fetchResult.RootFiles, ", "))
+		}
+	}
+	if fetchResult.CommitSHA != "" {
+		commit := fetchResult.CommitSHA
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		if fetchResult.Cached {
+			fmt.Printf("   Commit:     %s (cached analysis, use --no-cache to re-analyze)\n", commit)
+		} else {
+			fmt.Printf("   Commit:     %s\n", commit)
+		}
+	}
 	fmt.Printf("   Runtime:    %s %s\n", analysis.Runtime, analysis.RuntimeVersion)
 	fmt.Printf("   Transport:  %s\n", analysis.Transport)
-	fmt.Printf("   Confidence: %.0f%%\n", analysis.Confidence*100)
+	fmt.Printf("   Confidence: %.0f%% (threshold %.0f%% from %s)\n",
+		analysis.Confidence*100, threshold*100, thresholdSource)
 	if analysis.Description != "" {
 		fmt.Printf("   Description: %s\n", analysis.Description)
 	}
@@ -1520,12 +2165,18 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 	}
 
 	// Check confidence threshold
-	if analysis.Confidence < cfg.AI.ConfidenceThreshold {
+	if analysis.Confidence < threshold {
 		fmt.Println()
-		fmt.Printf("⚠️  AI confidence (%.0f%%) is below threshold (%.0f%%)\n",
-			analysis.Confidence*100, cfg.AI.ConfidenceThreshold*100)
+		fmt.Printf("⚠️  AI confidence (%.0f%%) is below threshold (%.0f%% from %s)\n",
+			analysis.Confidence*100, threshold*100, thresholdSource)
 		fmt.Println()
-		if !confirmAction("Continue anyway?") {
+		if opts.assumeYes {
+			fmt.Println("Continuing anyway (--yes)")
+		} else if !confirmActionContext(ctx, "Continue anyway?") {
+			if ctx.Err() != nil {
+				fmt.Println()
+				return ctx.Err()
+			}
 			fmt.Println("Installation cancelled.")
 			return nil
 		}
@@ -1550,8 +2201,18 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 			fmt.Printf("     • %s → %s (%s)\n", v.HostPath, v.ContainerPath, mode)
 		}
 	}
+	if len(dockerConfig.Issues) > 0 {
+		fmt.Println("   Dockerfile issues:")
+		for _, issue := range dockerConfig.Issues {
+			icon := "⚠️ "
+			if issue.Severity == ai.SeverityError {
+				icon = "❌"
+			}
+			fmt.Printf("     %s %s\n", icon, issue)
+		}
+	}
 
-	if dryRun {
+	if opts.dryRun {
 		fmt.Println()
 		fmt.Println("📄 Generated Dockerfile:")
 		fmt.Println("──────────────────────────────────────────────────────────────")
@@ -1566,16 +2227,26 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 		}, "   ", "  ")
 		fmt.Printf("   %s\n", mcpJSON)
 		fmt.Println()
+		printPolicyPreview(fetchResult.PackageID(), installPermissions(analysis, dockerConfig))
+		fmt.Println()
 		fmt.Println("(Dry run - no changes made)")
 		return nil
 	}
 
-	if skipBuild {
+	if opts.skipBuild {
 		fmt.Println()
 		fmt.Println("(Skipping build as requested)")
 		return nil
 	}
 
+	if ai.HasDockerfileErrors(dockerConfig.Issues) {
+		if !opts.force {
+			return fmt.Errorf("generated Dockerfile failed validation; re-run with --no-cache to regenerate or --force to build anyway")
+		}
+		fmt.Println()
+		fmt.Println("⚠️  Building despite Dockerfile validation errors (--force)")
+	}
+
 	// Step 3: Write Dockerfile
 	dockerfilePath, err := aiManager.WriteDockerfile(fetchResult, dockerConfig.Dockerfile)
 	if err != nil {
@@ -1586,8 +2257,9 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 	// Step 4: Build container
 	fmt.Println()
 	fmt.Println("🔨 Building container...")
-	imageName := fmt.Sprintf("conduit-mcp-%s", fetchResult.RepoName)
+	imageName := fetchResult.ImageName()
 	fmt.Printf("   Image name: %s\n", imageName)
+	fmt.Printf("   Platform:   %s\n", opts.platform)
 
 	// Select container runtime (prefer Podman)
 	selector := containerRuntime.NewSelector(cfg.Runtime.Preferred)
@@ -1599,7 +2271,8 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 		fmt.Println()
 		fmt.Println("📋 Manual Build Steps")
 		fmt.Println("──────────────────────────────────────────────────────────────")
-		fmt.Printf("1. Build: cd %s && docker build -f Dockerfile.conduit -t %s .\n", fetchResult.LocalPath, imageName)
+		fmt.Printf("1. Build: cd %s && docker build --platform %s -f Dockerfile.conduit -t %s .\n",
+			fetchResult.ContextDir(), opts.platform, imageName)
 		return nil
 	}
 
@@ -1608,9 +2281,10 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 
 	// Build the container
 	buildOpts := containerRuntime.BuildOptions{
-		ContextDir:     fetchResult.LocalPath,
+		ContextDir:     fetchResult.ContextDir(),
 		DockerfilePath: dockerfilePath,
 		ImageName:      imageName,
+		Platform:       opts.platform,
 		NoCache:        false,
 		Progress: func(line string) {
 			// Show build progress
@@ -1624,8 +2298,8 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 		fmt.Printf("   ❌ Build failed: %v\n", err)
 		fmt.Println()
 		fmt.Println("📋 Try building manually:")
-		fmt.Printf("   cd %s && %s build -f Dockerfile.conduit -t %s .\n",
-			fetchResult.LocalPath, provider.Name(), imageName)
+		fmt.Printf("   cd %s && %s build --platform %s -f Dockerfile.conduit -t %s .\n",
+			fetchResult.ContextDir(), provider.Name(), opts.platform, imageName)
 		return fmt.Errorf("container build failed: %w", err)
 	}
 
@@ -1636,14 +2310,16 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 	// Step 5: Create instance in daemon (if daemon is running)
 	c := newClient(socketPath)
 	instanceReq := map[string]interface{}{
-		"package_id":      fmt.Sprintf("github.com/%s/%s", fetchResult.Owner, fetchResult.RepoName),
+		"package_id":      fetchResult.PackageID(),
 		"package_version": "latest",
-		"display_name":    customName,
+		"display_name":    opts.customName,
 		"image_ref":       imageName,
+		"platform":        opts.platform,
 		"config":          map[string]string{},
+		"permissions":     installPermissions(analysis, dockerConfig),
 	}
-	if customName == "" {
-		instanceReq["display_name"] = fetchResult.RepoName
+	if opts.customName == "" {
+		instanceReq["display_name"] = fetchResult.PackageName()
 	}
 
 	data, err := c.post("/api/v1/instances", instanceReq)
@@ -1655,6 +2331,26 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 		json.Unmarshal(data, &resp)
 		if instanceID, ok := resp["instance_id"].(string); ok {
 			fmt.Printf("✓ Instance registered: %s\n", instanceID)
+
+			// Run the daemon install flow (audit, policy check) for the freshly built image
+			installData, err := c.post("/api/v1/instances/"+instanceID+"/install", map[string]interface{}{
+				"skip_pull": true,
+			})
+			if err == nil {
+				var installResp map[string]interface{}
+				json.Unmarshal(installData, &installResp)
+				if operationID, ok := installResp["operation_id"].(string); ok {
+					op, err := waitForOperation(ctx, c, operationID)
+					if err != nil {
+						fmt.Printf("⚠️  Could not track installation: %v\n", err)
+					} else if op["status"] == "failed" {
+						fmt.Printf("❌ Installation failed: %v\n", op["error"])
+					} else {
+						fmt.Println("✓ Instance installed")
+					}
+				}
+			}
+
 			fmt.Println()
 			fmt.Println("📋 Next Steps")
 			fmt.Println("──────────────────────────────────────────────────────────────")
@@ -1671,9 +2367,9 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 	runtimeName := provider.Name()
 	mcpConfig := map[string]interface{}{
 		"mcpServers": map[string]interface{}{
-			fetchResult.RepoName: map[string]interface{}{
+			fetchResult.PackageName(): map[string]interface{}{
 				"command": runtimeName,
-				"args":    []string{"run", "-i", "--rm", imageName},
+				"args":    []string{"run", "-i", "--rm", "--platform", opts.platform, imageName},
 			},
 		},
 	}
@@ -1685,42 +2381,288 @@ func runInstall(ctx context.Context, repoURL, customName, providerOverride strin
 	return nil
 }
 
-// confirmAction prompts the user for confirmation
-func confirmAction(prompt string) bool {
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Printf("%s [y/N]: ", prompt)
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(strings.ToLower(response))
-	return response == "y" || response == "yes"
+// installPermissions derives the permissions a connector asks for from its
+// analysis and generated container configuration.
+func installPermissions(analysis *ai.AnalysisResponse, dockerConfig *ai.DockerfileResponse) policy.PermissionSet {
+	var perms policy.PermissionSet
+	for _, v := range dockerConfig.Volumes {
+		if v.ReadOnly {
+			perms.Filesystem.ReadonlyPaths = append(perms.Filesystem.ReadonlyPaths, v.HostPath)
+		} else {
+			perms.Filesystem.ReadwritePaths = append(perms.Filesystem.ReadwritePaths, v.HostPath)
+		}
+	}
+	if analysis.RequiresNetwork {
+		perms.Network.Mode = "egress"
+	}
+	return perms
 }
 
-// listCmd lists all instances
-func listCmd() *cobra.Command {
-	var jsonOutput bool
-
-	cmd := &cobra.Command{
-		Use:     "list",
-		Short:   "List connector instances",
-		Aliases: []string{"ls"},
-		RunE: func(cmd *cobra.Command, args []string) error {
-			c := newClient(socketPath)
-			data, err := c.get("/api/v1/instances")
-			if err != nil {
-				if jsonOutput {
-					fmt.Printf(`{"success":false,"error":"failed to list instances: %s"}`, err.Error())
-					return nil
-				}
-				return fmt.Errorf("failed to list instances: %w", err)
-			}
+// printPolicyPreview asks the daemon how policy would treat an install
+// without recording a decision, and prints the verdict.
+func printPolicyPreview(packageID string, requested policy.PermissionSet) {
+	fmt.Println("🛡️  Policy Verdict:")
 
-			// JSON output for GUI consumption
-			if jsonOutput {
-				fmt.Println(string(data))
-				return nil
-			}
+	c := newClient(socketPath)
+	data, err := c.post("/api/v1/policy/evaluate", policy.Request{
+		Scope:     policy.ScopeInstall,
+		PackageID: packageID,
+		Actor:     "user",
+		Requested: requested,
+	})
+	if err != nil {
+		fmt.Println("   ⚠️  Policy preview unavailable (is the daemon running?)")
+		return
+	}
 
-			var resp map[string]interface{}
-			json.Unmarshal(data, &resp)
+	var decision policy.Decision
+	if err := json.Unmarshal(data, &decision); err != nil || decision.Decision == "" {
+		fmt.Printf("   ⚠️  Policy preview failed: %s\n", daemonErrorMessage(data))
+		return
+	}
+
+	switch decision.Decision {
+	case policy.Allow:
+		fmt.Printf("   ✓ ALLOW - %s\n", decision.Reason)
+	case policy.Warn:
+		fmt.Printf("   ⚠️  WARN - %s\n", decision.Reason)
+	case policy.Deny:
+		fmt.Printf("   ❌ DENY - %s\n", decision.Reason)
+	}
+	for _, reason := range decision.BlockReasons {
+		fmt.Printf("   ✗ %s\n", reason)
+	}
+	for _, w := range decision.Warnings {
+		fmt.Printf("   • %s\n", w)
+	}
+}
+
+// operationTimeout bounds how long waitForOperation follows an operation.
+// Installs pull and audit images, so this is generous.
+const operationTimeout = 30 * time.Minute
+
+// waitForOperation polls a daemon lifecycle operation until it completes,
+// rendering a progress bar, and returns the final operation state. It gives
+// up when ctx is cancelled or the operation runs longer than operationTimeout.
+func waitForOperation(ctx context.Context, c *client, operationID string) (map[string]interface{}, error) {
+	const barWidth = 30
+
+	waitCtx, cancel := context.WithTimeout(ctx, operationTimeout)
+	defer cancel()
+
+	for {
+		data, err := c.get("/api/v1/operations/" + operationID)
+		if err != nil {
+			return nil, err
+		}
+
+		var op map[string]interface{}
+		if err := json.Unmarshal(data, &op); err != nil {
+			return nil, fmt.Errorf("parse operation: %w", err)
+		}
+		if _, ok := op["operation_id"]; !ok {
+			return nil, fmt.Errorf("%s", daemonErrorMessage(data))
+		}
+
+		progress, _ := op["progress"].(float64)
+		progress = math.Max(0, math.Min(100, progress))
+		stage, _ := op["current_stage"].(string)
+		filled := int(float64(barWidth) * progress / 100)
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", barWidth-filled)
+		fmt.Printf("\r   %s %3.0f%% %-20s", bar, progress, stage)
+
+		status, _ := op["status"].(string)
+		if status != "pending" && status != "running" {
+			fmt.Println()
+			return op, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			fmt.Println()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("operation %s did not finish within %s", operationID, operationTimeout)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// defaultOllamaModel is the local model recommended for repository analysis.
+const defaultOllamaModel = "qwen2.5-coder:7b"
+
+// selectOllamaModel prompts for the local analysis model, listing the models
+// already installed in Ollama. Embedding-only models are not offered.
+func selectOllamaModel(reader *bufio.Reader) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	installed, err := ai.ListOllamaModels(ctx, installer.DefaultOllamaHost)
+	cancel()
+
+	options := []string{defaultOllamaModel}
+	recommendedInstalled := false
+	for _, name := range installed {
+		if name == defaultOllamaModel {
+			recommendedInstalled = true
+			continue
+		}
+		if strings.Contains(name, "embed") {
+			continue
+		}
+		options = append(options, name)
+	}
+
+	fmt.Println("Choose the model used to analyze MCP servers:")
+	fmt.Println()
+	for i, name := range options {
+		note := ""
+		switch {
+		case i == 0 && recommendedInstalled:
+			note = " (recommended, installed)"
+		case i == 0:
+			note = " (recommended, will need to be pulled)"
+		default:
+			note = " (installed)"
+		}
+		fmt.Printf("  [%d] %s%s\n", i+1, name, note)
+	}
+	fmt.Println("  [c] Enter another model name")
+	if err != nil {
+		fmt.Println()
+		fmt.Println("  ⚠️  Could not list installed models (is Ollama running?)")
+	}
+	fmt.Println()
+
+	for {
+		fmt.Printf("Model [1-%d/c]: ", len(options))
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		switch {
+		case input == "":
+			return options[0]
+		case strings.EqualFold(input, "c"):
+			fmt.Print("Model name (e.g. llama3.1:8b): ")
+			name, _ := reader.ReadString('\n')
+			if name = strings.TrimSpace(name); name != "" {
+				return name
+			}
+		default:
+			if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(options) {
+				return options[n-1]
+			}
+		}
+		fmt.Println("Invalid choice.")
+	}
+}
+
+// selectAnthropicModel prompts for a Claude model and checks with the
+// Anthropic API that the name is accepted before returning it.
+func selectAnthropicModel(reader *bufio.Reader) string {
+	fmt.Println("Choose the Claude model used to analyze MCP servers:")
+	fmt.Println()
+	for i, m := range ai.AnthropicModels {
+		fmt.Printf("  [%d] %s\n", i+1, m.Name)
+		fmt.Printf("      • %s\n", m.Description)
+	}
+	fmt.Println("  [c] Enter another model name")
+	fmt.Println()
+
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	for {
+		fmt.Printf("Model [1-%d/c]: ", len(ai.AnthropicModels))
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		var model string
+		switch {
+		case input == "":
+			model = ai.AnthropicModels[0].Name
+		case strings.EqualFold(input, "c"):
+			fmt.Print("Model name (e.g. claude-sonnet-4-20250514): ")
+			name, _ := reader.ReadString('\n')
+			model = strings.TrimSpace(name)
+		default:
+			if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(ai.AnthropicModels) {
+				model = ai.AnthropicModels[n-1].Name
+			}
+		}
+		if model == "" {
+			fmt.Println("Invalid choice.")
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		err := ai.ValidateAnthropicModel(ctx, apiKey, model)
+		cancel()
+
+		switch e := err.(type) {
+		case nil:
+			return model
+		case *ai.ErrUnknownModel:
+			fmt.Printf("❌ %v. Choose another model.\n", e)
+		case *ai.ErrProviderUnavailable:
+			// The name looks like a Claude model but could not be checked online
+			fmt.Printf("⚠️  Could not verify %s with Anthropic: %s\n", model, e.Reason)
+			return model
+		default:
+			fmt.Printf("⚠️  Could not verify %s: %v\n", model, e)
+			return model
+		}
+	}
+}
+
+// confirmAction prompts the user for confirmation
+func confirmAction(prompt string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("%s [y/N]: ", prompt)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// confirmActionContext is confirmAction that gives up, answering no, when
+// ctx is cancelled while waiting for input.
+func confirmActionContext(ctx context.Context, prompt string) bool {
+	answer := make(chan bool, 1)
+	go func() { answer <- confirmAction(prompt) }()
+	select {
+	case ok := <-answer:
+		return ok
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// listCmd lists all instances
+func listCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List connector instances",
+		Aliases: []string{"ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+			data, err := c.get("/api/v1/instances")
+			if err != nil {
+				if jsonOutput {
+					fmt.Printf(`{"success":false,"error":"failed to list instances: %s"}`, err.Error())
+					return nil
+				}
+				return fmt.Errorf("failed to list instances: %w", err)
+			}
+
+			// JSON/YAML output for scripts and GUI consumption
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(data, &resp)
 
 			instances, _ := resp["instances"].([]interface{})
 			if len(instances) == 0 {
@@ -1752,12 +2694,16 @@ func startCmd() *cobra.Command {
 	var jsonOutput bool
 
 	cmd := &cobra.Command{
-		Use:   "start <instance-id>",
-		Short: "Start a connector instance",
-		Args:  cobra.ExactArgs(1),
+		Use:               "start <instance-id>",
+		Short:             "Start a connector instance",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
 			data, err := c.post("/api/v1/instances/"+instanceID+"/start", nil)
 			if err != nil {
@@ -1791,16 +2737,32 @@ func startCmd() *cobra.Command {
 // stopCmd stops an instance
 func stopCmd() *cobra.Command {
 	var jsonOutput bool
+	var timeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "stop <instance-id>",
 		Short: "Stop a connector instance",
-		Args:  cobra.ExactArgs(1),
+		Long: `Stop a connector instance.
+
+The container is sent SIGTERM and given a grace period to exit before it is
+killed. The grace period defaults to runtime.stop_timeout in the config and can
+be overridden with --timeout.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
-			c := newClient(socketPath)
+			// Allow the daemon the full grace period plus time to kill the container
+			c := newClientWithTimeout(socketPath, timeout+30*time.Second)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
-			data, err := c.post("/api/v1/instances/"+instanceID+"/stop", nil)
+			stopPath := "/api/v1/instances/" + instanceID + "/stop"
+			if timeout > 0 {
+				stopPath += "?timeout=" + timeout.String()
+			}
+
+			data, err := c.post(stopPath, nil)
 			if err != nil {
 				if jsonOutput {
 					fmt.Printf(`{"success":false,"instance_id":"%s","error":"failed to stop instance: %s"}`, instanceID, err.Error())
@@ -1819,28 +2781,123 @@ func stopCmd() *cobra.Command {
 				return nil
 			}
 
+			var resp map[string]interface{}
+			json.Unmarshal(data, &resp)
+			if _, ok := resp["error"]; ok {
+				return fmt.Errorf("failed to stop instance: %s", daemonErrorMessage(data))
+			}
+			if duration, ok := resp["duration"].(string); ok {
+				fmt.Printf("Stopped instance %s (took %s)\n", instanceID, duration)
+				return nil
+			}
+
 			fmt.Printf("Stopped instance %s\n", instanceID)
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Grace period before the container is killed (default: runtime.stop_timeout)")
+	return cmd
+}
+
+// restartCmd restarts an instance
+func restartCmd() *cobra.Command {
+	var jsonOutput bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "restart <instance-id>",
+		Short: "Restart a connector instance",
+		Long: `Restart a connector instance.
+
+Stops the container (honoring the stop grace period) and starts a fresh one.
+The instance must be RUNNING or DEGRADED. If either phase fails the command
+reports the error and the instance is left STOPPED or DEGRADED.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceID := args[0]
+			// Allow the daemon the full grace period plus time to start the new container
+			c := newClientWithTimeout(socketPath, timeout+60*time.Second)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			restartPath := "/api/v1/instances/" + instanceID + "/restart"
+			if timeout > 0 {
+				restartPath += "?timeout=" + timeout.String()
+			}
+
+			data, err := c.post(restartPath, nil)
+			if err != nil {
+				if jsonOutput {
+					fmt.Printf(`{"success":false,"instance_id":"%s","error":"failed to restart instance: %s"}`, instanceID, err.Error())
+					return nil
+				}
+				return fmt.Errorf("failed to restart instance: %w", err)
+			}
+
+			// JSON output for GUI consumption
+			if jsonOutput {
+				fmt.Println(string(data))
+				return nil
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(data, &resp)
+			if _, ok := resp["error"]; ok {
+				return fmt.Errorf("failed to restart instance: %s", daemonErrorMessage(data))
+			}
+
+			fmt.Printf("Restarted instance %s (status: %v, took %v)\n", instanceID, resp["status"], resp["duration"])
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Grace period before the old container is killed (default: runtime.stop_timeout)")
 	return cmd
 }
 
 // removeCmd removes an instance
 func removeCmd() *cobra.Command {
 	var jsonOutput bool
+	var purgeImage bool
 
 	cmd := &cobra.Command{
 		Use:   "remove <instance-id>",
 		Short: "Remove a connector instance",
-		Args:  cobra.ExactArgs(1),
+		Long: `Remove a connector instance. A running instance is stopped and its
+container removed first.
+
+The instance's image is kept, so reinstalling is fast. Use --purge-image to
+remove it as well; an image that another instance uses is always kept.
+
+Examples:
+  conduit remove abc123
+  conduit remove abc123 --purge-image`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
-			err := c.delete("/api/v1/instances/" + instanceID)
+			path := "/api/v1/instances/" + url.PathEscape(instanceID)
+			if purgeImage {
+				path += "?purge_image=true"
+			}
+			data, err := c.deleteWithResponse(path)
+			if err == nil && len(data) > 0 {
+				var resp map[string]interface{}
+				json.Unmarshal(data, &resp)
+				if _, ok := resp["error"]; ok {
+					err = errors.New(daemonErrorMessage(data))
+				}
+			}
 			if err != nil {
 				if jsonOutput {
 					fmt.Printf(`{"success":false,"instance_id":"%s","error":"failed to remove instance: %s"}`, instanceID, err.Error())
@@ -1849,18 +2906,166 @@ func removeCmd() *cobra.Command {
 				return fmt.Errorf("failed to remove instance: %w", err)
 			}
 
+			var result struct {
+				ImageRef     string `json:"image_ref,omitempty"`
+				ImageRemoved bool   `json:"image_removed"`
+				ImageKept    string `json:"image_kept,omitempty"`
+			}
+			if purgeImage {
+				json.Unmarshal(data, &result)
+			}
+
 			// JSON output for GUI consumption
 			if jsonOutput {
-				fmt.Printf(`{"success":true,"instance_id":"%s","message":"Removed instance"}`, instanceID)
+				out := struct {
+					Success      bool   `json:"success"`
+					InstanceID   string `json:"instance_id"`
+					Message      string `json:"message"`
+					ImageRef     string `json:"image_ref,omitempty"`
+					ImageRemoved *bool  `json:"image_removed,omitempty"`
+					ImageKept    string `json:"image_kept,omitempty"`
+				}{Success: true, InstanceID: instanceID, Message: "Removed instance"}
+				if purgeImage {
+					out.ImageRef, out.ImageRemoved, out.ImageKept = result.ImageRef, &result.ImageRemoved, result.ImageKept
+				}
+				encoded, _ := json.Marshal(out)
+				fmt.Print(string(encoded))
 				return nil
 			}
 
 			fmt.Printf("Removed instance %s\n", instanceID)
+			if purgeImage && result.ImageRef != "" {
+				if result.ImageRemoved {
+					fmt.Printf("Removed image %s\n", result.ImageRef)
+				} else {
+					fmt.Printf("Kept image %s: %s\n", result.ImageRef, result.ImageKept)
+				}
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	cmd.Flags().BoolVar(&purgeImage, "purge-image", false, "Also remove the instance's image unless another instance uses it")
+	return cmd
+}
+
+// gcCmd removes orphaned containers and images
+func gcCmd() *cobra.Command {
+	var jsonOutput bool
+	var dryRun bool
+	var minAge time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove orphaned containers and images",
+		Long: `Remove the containers and images Conduit created that no instance owns.
+
+Crashes and interrupted operations can leave conduit-* containers behind
+after their instance is gone, and removed instances leave their
+conduit-mcp-* images. gc lists the containers and images labeled
+conduit.managed=true, compares them with the instances in the database and
+removes the orphans, reclaiming disk space and avoiding name collisions on
+reinstall. Images still used by an instance or a remaining container are
+kept.
+
+Anything younger than runtime.gc_min_age (default 10m) is spared, since an
+install in progress may not have recorded it yet; --min-age overrides it.
+Set runtime.gc_interval to have the daemon collect periodically.
+
+Examples:
+  conduit gc --dry-run     # Preview what would be removed
+  conduit gc
+  conduit gc --min-age 0   # Include containers and images created just now`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if minAge < 0 {
+				return fmt.Errorf("--min-age must not be negative")
+			}
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			query := url.Values{}
+			if dryRun {
+				query.Set("dry_run", "true")
+			}
+			if cmd.Flags().Changed("min-age") {
+				query.Set("min_age", minAge.String())
+			}
+			path := "/api/v1/gc"
+			if len(query) > 0 {
+				path += "?" + query.Encode()
+			}
+
+			var result lifecycle.GCResult
+			if err := postDaemonJSON(c, path, nil, &result); err != nil {
+				return fmt.Errorf("garbage collection failed: %w", err)
+			}
+
+			if jsonOutput || outputFormat != "table" {
+				data, err := json.Marshal(result)
+				if err != nil {
+					return err
+				}
+				return printOutput(data)
+			}
+
+			fmt.Println("🧹 Garbage Collection")
+			fmt.Println(strings.Repeat("─", 50))
+			if len(result.Containers) == 0 && len(result.Images) == 0 {
+				fmt.Println("No orphaned containers or images")
+			}
+			failed := 0
+			printItems := func(kind string, items []lifecycle.GCItem) {
+				if len(items) == 0 {
+					return
+				}
+				fmt.Printf("%s:\n", kind)
+				for _, item := range items {
+					name := item.Name
+					if name == "" {
+						name = "<none>"
+					}
+					detail := ""
+					if item.InstanceID != "" {
+						detail = " (instance " + item.InstanceID + ")"
+					}
+					switch {
+					case dryRun:
+						fmt.Printf("  • %s %s%s\n", item.ID, name, detail)
+					case item.Removed:
+						fmt.Printf("  ✓ %s %s%s\n", item.ID, name, detail)
+					default:
+						failed++
+						fmt.Printf("  ✗ %s %s%s: %s\n", item.ID, name, detail, item.Error)
+					}
+				}
+			}
+			printItems("Containers", result.Containers)
+			printItems("Images", result.Images)
+
+			fmt.Println()
+			total := len(result.Containers) + len(result.Images)
+			if dryRun {
+				fmt.Printf("Dry run: %d would be removed", total)
+			} else {
+				fmt.Printf("Removed %d", total-failed)
+				if failed > 0 {
+					fmt.Printf(", %d failed", failed)
+				}
+			}
+			if result.Skipped > 0 {
+				fmt.Printf("; %d spared as younger than the minimum age", result.Skipped)
+			}
+			fmt.Println()
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without removing")
+	cmd.Flags().DurationVar(&minAge, "min-age", 0, "Spare anything younger than this (default: runtime.gc_min_age)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
 	return cmd
 }
 
@@ -1954,11 +3159,9 @@ Examples:
 				stats["daemon"] = daemon
 			}
 
-			// JSON output for GUI consumption
-			if jsonOutput {
-				jsonBytes, _ := json.MarshalIndent(stats, "", "  ")
-				fmt.Println(string(jsonBytes))
-				return nil
+			// JSON/YAML output for scripts and GUI consumption
+			if outputFormat != "table" {
+				return printOutput(stats)
 			}
 
 			// Human-readable output
@@ -2004,7 +3207,8 @@ Examples:
   conduit permissions abc123 --json   # JSON output for GUI
 
 NOTE: This feature requires daemon API support (coming in future release).`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
 
@@ -2055,45 +3259,583 @@ NOTE: This feature requires daemon API support (coming in future release).`,
 	return cmd
 }
 
-// auditCmd shows instance access audit logs (Advanced Mode)
-func auditCmd() *cobra.Command {
-	var limit int
-	var jsonOutput bool
-
+// policyCmd manages permission grants through the policy engine
+func policyCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "audit <instance-id>",
-		Short: "Show instance audit logs (Advanced Mode)",
-		Long: `View audit logs for a connector instance.
+		Use:   "policy",
+		Short: "View and manage instance permission grants",
+		Long: `View and manage the permissions granted to connector instances.
 
-Audit logs track all access and operations performed by the instance.
-This is an Advanced Mode feature for security monitoring.
+A connector only receives the permissions it declares AND that you grant.
+Grants touching forbidden paths (credentials, system directories) are
+always rejected by the policy engine.
 
 Examples:
-  conduit audit abc123
-  conduit audit abc123 --limit 50
-  conduit audit abc123 --json   # JSON output for GUI
+  conduit policy show abc123
+  conduit policy grant abc123 --fs-ro ~/Documents --net
+  conduit policy grant abc123 --secret github-token=GITHUB_TOKEN
+  conduit policy revoke abc123 network
+  conduit policy log --decision deny --since 24h`,
+	}
 
-NOTE: This feature requires daemon API support (coming in future release).`,
-		Args: cobra.ExactArgs(1),
+	cmd.AddCommand(policyShowCmd())
+	cmd.AddCommand(policyGrantCmd())
+	cmd.AddCommand(policyRevokeCmd())
+	cmd.AddCommand(policyLogCmd())
+
+	return cmd
+}
+
+// policyShowCmd shows declared, granted and effective permissions
+func policyShowCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:               "show <instance-id>",
+		Short:             "Show declared, granted and effective permissions",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
-			// NOTE: Daemon API for audit doesn't exist yet
-			// Return a structured response indicating feature is planned
-			if jsonOutput {
-				result := map[string]interface{}{
-					"success":      false,
-					"instance_id":  instanceID,
-					"audit_logs":   []interface{}{},
-					"error":        "Audit log API not yet implemented in daemon",
-					"feature_note": "This feature is planned for a future release",
-				}
-				jsonBytes, _ := json.MarshalIndent(result, "", "  ")
-				fmt.Println(string(jsonBytes))
-				return nil
+			data, err := c.get("/api/v1/policy/instances/" + instanceID)
+			if err != nil {
+				return fmt.Errorf("failed to get permissions: %w", err)
 			}
 
-			fmt.Printf("⚠️  Audit logging is not yet implemented\n")
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(data, &resp)
+			if _, ok := resp["error"]; ok {
+				return fmt.Errorf("failed to get permissions: %s", daemonErrorMessage(data))
+			}
+
+			var perms policy.EffectivePermissions
+			if err := json.Unmarshal(data, &perms); err != nil {
+				return fmt.Errorf("parse response: %w", err)
+			}
+
+			fmt.Printf("Permissions for %s\n", instanceID)
+			fmt.Println(strings.Repeat("─", 50))
+			if perms.Declared != nil {
+				printPermissionSet("Declared", *perms.Declared)
+			} else {
+				fmt.Println("Declared:  (not declared by package)")
+			}
+			printPermissionSet("Granted", perms.Granted)
+			printPermissionSet("Effective", perms.Effective)
+
+			if len(perms.Warnings) > 0 {
+				fmt.Println()
+				for _, w := range perms.Warnings {
+					fmt.Printf("⚠️  %s\n", w)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	return cmd
+}
+
+// policyGrantCmd grants permissions to an instance
+func policyGrantCmd() *cobra.Command {
+	var fsRead, fsWrite, secrets []string
+	var network bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "grant <instance-id>",
+		Short: "Grant permissions to an instance",
+		Long: `Grant permissions to a connector instance.
+
+Grants are added to any existing grants. Secrets are given as NAME or
+NAME=ENV_KEY; without an explicit key the variable name is derived from
+the secret name (github-token -> GITHUB_TOKEN).
+
+--net allows outbound connections to any host. Docker and Podman cannot
+filter egress by hostname, so network access is all or nothing, and
+policy.allow_network_egress must also be enabled.
+
+Examples:
+  conduit policy grant abc123 --fs-ro ~/Documents
+  conduit policy grant abc123 --fs-rw ~/Projects/notes
+  conduit policy grant abc123 --net
+  conduit policy grant abc123 --secret github-token=GITHUB_TOKEN
+
+The instance must be restarted for new grants to take effect.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceID := args[0]
+
+			if len(fsRead)+len(fsWrite)+len(secrets) == 0 && !network {
+				return fmt.Errorf("nothing to grant: use --fs-ro, --fs-rw, --net or --secret")
+			}
+
+			var grant policy.PermissionSet
+			for _, p := range fsRead {
+				grant.Filesystem.ReadonlyPaths = append(grant.Filesystem.ReadonlyPaths, expandGrantPath(p))
+			}
+			for _, p := range fsWrite {
+				grant.Filesystem.ReadwritePaths = append(grant.Filesystem.ReadwritePaths, expandGrantPath(p))
+			}
+			if network {
+				grant.Network = policy.NetworkPerms{Mode: "egress"}
+			}
+			for _, s := range secrets {
+				grant.Secrets = append(grant.Secrets, parseSecretGrant(s))
+			}
+
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+			data, err := c.post("/api/v1/policy/instances/"+instanceID+"/grants", grant)
+			if err != nil {
+				return fmt.Errorf("failed to grant permissions: %w", err)
+			}
+
+			if jsonOutput {
+				fmt.Println(string(data))
+				return nil
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(data, &resp)
+			if _, ok := resp["error"]; ok {
+				return fmt.Errorf("failed to grant permissions: %s", daemonErrorMessage(data))
+			}
+
+			fmt.Printf("✓ Updated grants for %s\n", instanceID)
+			fmt.Println("  Restart the instance to apply: conduit restart " + instanceID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&fsRead, "fs-ro", nil, "Grant read-only access to a path (repeatable)")
+	cmd.Flags().StringArrayVar(&fsWrite, "fs-rw", nil, "Grant read-write access to a path (repeatable)")
+	cmd.Flags().BoolVar(&network, "net", false, "Allow outbound network access to any host")
+	cmd.Flags().StringArrayVar(&secrets, "secret", nil, "Bind a secret as NAME or NAME=ENV_KEY (repeatable)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	return cmd
+}
+
+// policyRevokeCmd revokes a category of grants from an instance
+func policyRevokeCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:               "revoke <instance-id> <type>",
+		Short:             "Revoke all grants of a type (filesystem, network, secrets, exposure)",
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeInstanceIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceID, permType := args[0], args[1]
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			data, err := c.deleteWithResponse("/api/v1/policy/instances/" + instanceID + "/grants/" + permType)
+			if err != nil {
+				return fmt.Errorf("failed to revoke permission: %w", err)
+			}
+
+			if jsonOutput {
+				fmt.Println(string(data))
+				return nil
+			}
+
+			var resp map[string]interface{}
+			json.Unmarshal(data, &resp)
+			if _, ok := resp["error"]; ok {
+				return fmt.Errorf("failed to revoke permission: %s", daemonErrorMessage(data))
+			}
+
+			fmt.Printf("✓ Revoked %s grants for %s\n", permType, instanceID)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	return cmd
+}
+
+// policyLogCmd shows the audit log of policy decisions
+func policyLogCmd() *cobra.Command {
+	var instanceID, decision, since string
+	var limit int
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "log",
+		Short: "Show recorded policy decisions",
+		Long: `Show the audit log of policy decisions, newest first.
+
+Each entry shows whether a request was allowed, allowed with warnings or
+denied, and why. Use it to understand why a connector was blocked or what
+it asked for.
+
+Examples:
+  conduit policy log
+  conduit policy log --instance abc123
+  conduit policy log --decision deny --since 24h
+  conduit policy log --since 2026-01-01T00:00:00Z --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			params := url.Values{}
+			if instanceID != "" {
+				params.Set("instance_id", instanceID)
+			}
+			if decision != "" {
+				params.Set("decision", decision)
+			}
+			if since != "" {
+				params.Set("since", since)
+			}
+			params.Set("limit", strconv.Itoa(limit))
+
+			data, err := c.get("/api/v1/policy/decisions?" + params.Encode())
+			if err != nil {
+				return fmt.Errorf("failed to get policy decisions: %w", err)
+			}
+
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
+			var resp struct {
+				Decisions []policy.Decision `json:"decisions"`
+				Error     interface{}       `json:"error"`
+			}
+			json.Unmarshal(data, &resp)
+			if resp.Error != nil {
+				return fmt.Errorf("failed to get policy decisions: %s", daemonErrorMessage(data))
+			}
+
+			if len(resp.Decisions) == 0 {
+				fmt.Println("No policy decisions recorded")
+				return nil
+			}
+
+			for _, d := range resp.Decisions {
+				icon := "✓"
+				switch d.Decision {
+				case policy.Warn:
+					icon = "⚠️ "
+				case policy.Deny:
+					icon = "✗"
+				}
+
+				subject := d.InstanceID
+				if subject == "" {
+					subject = d.PackageID
+				}
+				fmt.Printf("%s %s  %-5s  %-17s  %s\n", icon, d.Timestamp.Local().Format("2006-01-02 15:04:05"),
+					d.Decision, d.Scope, subject)
+				fmt.Printf("    %s\n", d.Reason)
+				for _, reason := range d.BlockReasons {
+					fmt.Printf("    ✗ %s\n", reason)
+				}
+				for _, w := range d.Warnings {
+					fmt.Printf("    ⚠ %s\n", w)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&instanceID, "instance", "", "Only show decisions for this instance")
+	cmd.Flags().StringVar(&decision, "decision", "", "Only show decisions of this type (allow, warn, deny)")
+	cmd.Flags().StringVar(&since, "since", "", "Only show decisions since a time (RFC3339 or a date like 2024-01-01) or duration ago (e.g. 24h)")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of decisions to show")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	return cmd
+}
+
+// printPermissionSet prints one permission set for `conduit policy show`.
+func printPermissionSet(label string, p policy.PermissionSet) {
+	fmt.Printf("%s:\n", label)
+	printed := false
+	for _, path := range p.Filesystem.ReadonlyPaths {
+		fmt.Printf("  📖 read   %s\n", path)
+		printed = true
+	}
+	for _, path := range p.Filesystem.ReadwritePaths {
+		fmt.Printf("  ✏️  write  %s\n", path)
+		printed = true
+	}
+	if p.Network.Mode == "egress" {
+		if len(p.Network.EgressDomains) == 0 {
+			fmt.Println("  🌐 net    (any host)")
+		}
+		for _, host := range p.Network.EgressDomains {
+			fmt.Printf("  🌐 net    %s\n", host)
+		}
+		printed = true
+	}
+	for _, secret := range p.Secrets {
+		fmt.Printf("  🔑 secret %s → $%s\n", secret.SecretID, secret.EnvKey)
+		printed = true
+	}
+	if p.Exposure.SecureLink {
+		fmt.Println("  🔗 secure link")
+		printed = true
+	}
+	if !printed {
+		fmt.Println("  (none)")
+	}
+}
+
+// expandGrantPath expands ~ and makes a granted path absolute.
+func expandGrantPath(path string) string {
+	if strings.HasPrefix(path, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// parseSecretGrant parses NAME or NAME=ENV_KEY into a secret reference.
+func parseSecretGrant(s string) policy.SecretRef {
+	if name, key, ok := strings.Cut(s, "="); ok {
+		return policy.SecretRef{SecretID: name, EnvKey: key}
+	}
+	return policy.SecretRef{
+		SecretID: s,
+		EnvKey:   strings.ToUpper(strings.ReplaceAll(s, "-", "_")),
+	}
+}
+
+// secretsCmd manages the encrypted secrets store
+func secretsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage secrets for connector instances",
+		Long: `Manage the secrets connector instances can use, such as API keys.
+
+Secrets are kept in ~/.conduit/secrets.enc, encrypted with a key created on
+first use in ~/.conduit/conduit.key (mode 0600). They never enter the database.
+
+An instance gets a secret when it is granted to it, either bound to an
+environment variable or referenced from its config as ${secret:NAME}; the
+value is resolved when the container starts.
+
+Examples:
+  conduit secrets set github-token
+  conduit secrets set openai-key --from-env OPENAI_API_KEY
+  conduit policy grant abc123 --secret github-token=GITHUB_TOKEN
+  conduit create github --config 'token=${secret:github-token}'
+  conduit secrets list
+  conduit secrets remove github-token`,
+	}
+
+	cmd.AddCommand(secretsSetCmd())
+	cmd.AddCommand(secretsListCmd())
+	cmd.AddCommand(secretsRemoveCmd())
+
+	return cmd
+}
+
+// openSecretStore opens the secrets store in the configured data directory
+func openSecretStore() (*secrets.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	return secrets.NewStore(cfg.SecretsPath(), cfg.KeyPath()), nil
+}
+
+// secretsSetCmd stores a secret
+func secretsSetCmd() *cobra.Command {
+	var fromEnv string
+
+	cmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "Store a secret",
+		Long: `Store a secret, replacing any secret with the same name.
+
+The value is prompted for without echo, read from stdin when it is not a
+terminal, or taken from an environment variable with --from-env. It is never
+accepted as an argument, so it does not end up in shell history.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := secrets.ValidateName(name); err != nil {
+				return err
+			}
+
+			var value string
+			switch {
+			case fromEnv != "":
+				v, ok := os.LookupEnv(fromEnv)
+				if !ok {
+					return fmt.Errorf("environment variable %s is not set", fromEnv)
+				}
+				value = v
+			case term.IsTerminal(int(os.Stdin.Fd())):
+				fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+				data, err := term.ReadPassword(int(os.Stdin.Fd()))
+				fmt.Fprintln(os.Stderr)
+				if err != nil {
+					return fmt.Errorf("read value: %w", err)
+				}
+				value = string(data)
+			default:
+				data, err := io.ReadAll(os.Stdin)
+				if err != nil {
+					return fmt.Errorf("read value: %w", err)
+				}
+				value = strings.TrimRight(string(data), "\r\n")
+			}
+			if value == "" {
+				return fmt.Errorf("empty value: secret not stored")
+			}
+
+			store, err := openSecretStore()
+			if err != nil {
+				return err
+			}
+			if err := store.Set(name, value); err != nil {
+				return fmt.Errorf("store secret: %w", err)
+			}
+
+			fmt.Printf("✓ Stored secret %s\n", name)
+			fmt.Printf("  Grant it to an instance: conduit policy grant <instance-id> --secret %s\n", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fromEnv, "from-env", "", "Read the value from this environment variable")
+
+	return cmd
+}
+
+// secretsListCmd lists stored secret names
+func secretsListCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List stored secret names",
+		Long:  "List the names of stored secrets. Values are never shown.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openSecretStore()
+			if err != nil {
+				return err
+			}
+			names, err := store.List()
+			if err != nil {
+				return fmt.Errorf("list secrets: %w", err)
+			}
+
+			if jsonOutput {
+				out, _ := json.MarshalIndent(map[string]interface{}{"secrets": names}, "", "  ")
+				fmt.Println(string(out))
+				return nil
+			}
+
+			if len(names) == 0 {
+				fmt.Println("No secrets stored. Add one with 'conduit secrets set <name>'.")
+				return nil
+			}
+			for _, name := range names {
+				fmt.Println(name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+// secretsRemoveCmd deletes a secret
+func secretsRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   "Remove a secret",
+		Long: `Remove a stored secret. Instances that reference it will fail to start
+until it is stored again; ones that only have it granted start without it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := openSecretStore()
+			if err != nil {
+				return err
+			}
+			if err := store.Delete(args[0]); err != nil {
+				if errors.Is(err, secrets.ErrNotFound) {
+					return fmt.Errorf("no secret named %s", args[0])
+				}
+				return fmt.Errorf("remove secret: %w", err)
+			}
+			fmt.Printf("✓ Removed secret %s\n", args[0])
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// auditCmd shows instance access audit logs (Advanced Mode)
+func auditCmd() *cobra.Command {
+	var limit int
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "audit <instance-id>",
+		Short: "Show instance audit logs (Advanced Mode)",
+		Long: `View audit logs for a connector instance.
+
+Audit logs track all access and operations performed by the instance.
+This is an Advanced Mode feature for security monitoring.
+
+Examples:
+  conduit audit abc123
+  conduit audit abc123 --limit 50
+  conduit audit abc123 --json   # JSON output for GUI
+
+NOTE: This feature requires daemon API support (coming in future release).`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceID := args[0]
+
+			// NOTE: Daemon API for audit doesn't exist yet
+			// Return a structured response indicating feature is planned
+			if jsonOutput {
+				result := map[string]interface{}{
+					"success":      false,
+					"instance_id":  instanceID,
+					"audit_logs":   []interface{}{},
+					"error":        "Audit log API not yet implemented in daemon",
+					"feature_note": "This feature is planned for a future release",
+				}
+				jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+				fmt.Println(string(jsonBytes))
+				return nil
+			}
+
+			fmt.Printf("⚠️  Audit logging is not yet implemented\n")
 			fmt.Printf("    Instance: %s\n", instanceID)
 			fmt.Printf("    Limit: %d entries\n", limit)
 			fmt.Printf("    This feature is planned for a future release.\n")
@@ -2125,6 +3867,7 @@ The instance will be created but not started. Use 'conduit start' to run it.
 Examples:
   conduit create filesystem --name "My Files"
   conduit create github --name "GitHub Repos" --config "token=ghp_xxx"
+  conduit create github --config 'token=${secret:github-token}'   # Resolved at start, see 'conduit secrets'
   conduit create filesystem --json   # JSON output for GUI`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -2161,6 +3904,9 @@ Examples:
 			}
 
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 			data, err := c.post("/api/v1/instances", req)
 			if err != nil {
 				if jsonOutput {
@@ -2238,6 +3984,10 @@ func clientListCmd() *cobra.Command {
 				return fmt.Errorf("failed to list clients: %w", err)
 			}
 
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
 			var resp map[string]interface{}
 			json.Unmarshal(data, &resp)
 
@@ -2275,6 +4025,9 @@ func clientBindCmd() *cobra.Command {
 	var clientID string
 	var scope string
 	var jsonOutput bool
+	var export bool
+	var format string
+	var force bool
 
 	cmd := &cobra.Command{
 		Use:   "bind <instance-id>",
@@ -2284,11 +4037,23 @@ func clientBindCmd() *cobra.Command {
 This injects the MCP server configuration into the client's config file,
 allowing the AI client to access the connector.
 
+The instance must be running or startable; a running instance must also pass
+a health check. Use --force to bind anyway.
+
+With --export, nothing is written: the MCP server block is printed to stdout
+so it can be pasted into any client, including ones without an adapter.
+
+Project and workspace scopes write to the current directory (for example
+.vscode/mcp.json or .cursor/mcp.json); user scope writes to the client's
+per-user config, which is created if the client has never been launched.
+
 Examples:
   conduit client bind my-server --client claude-code
   conduit client bind abc123 --client cursor --scope user
+  conduit client bind abc123 --client vscode --scope workspace
   conduit client bind my-server --json   # JSON output for GUI`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
 
@@ -2297,12 +4062,23 @@ Examples:
 			}
 
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
-			// Create binding request
+			if export {
+				return exportBinding(c, instanceID, format)
+			}
+
+			// Create binding request. Project and workspace configs are
+			// written relative to where the command runs, not the daemon.
+			projectPath, _ := os.Getwd()
 			req := map[string]interface{}{
-				"instance_id": instanceID,
-				"client_id":   clientID,
-				"scope":       scope,
+				"instance_id":  instanceID,
+				"client_id":    clientID,
+				"scope":        scope,
+				"project_path": projectPath,
+				"force":        force,
 			}
 
 			data, err := c.post("/api/v1/bindings", req)
@@ -2326,6 +4102,9 @@ Examples:
 					fmt.Println()
 					return nil
 				}
+				if errMap["code"] == string(models.ErrInstanceNotReady) {
+					errMsg += "\nFix the instance first, or bind anyway with --force"
+				}
 				return fmt.Errorf("%s", errMsg)
 			}
 
@@ -2355,9 +4134,52 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&clientID, "client", "c", "claude-code", "Client to bind to")
+	cmd.RegisterFlagCompletionFunc("client", completeClientIDs)
 	cmd.Flags().StringVarP(&scope, "scope", "s", "project", "Binding scope: project, user, workspace")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	cmd.Flags().BoolVar(&export, "export", false, "Print the MCP server config instead of writing it to a client")
+	cmd.Flags().StringVar(&format, "format", "json", "Export format: json, toml")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Bind even if the instance is not running or healthy")
+
+	return cmd
+}
+
+// exportBinding prints the MCP server block a bind would inject for an instance.
+func exportBinding(c *client, instanceID, format string) error {
+	data, err := c.get("/api/v1/instances/" + url.PathEscape(instanceID))
+	if err != nil {
+		return fmt.Errorf("get instance: %w", err)
+	}
+
+	var instance struct {
+		InstanceID  string      `json:"instance_id"`
+		DisplayName string      `json:"display_name"`
+		Error       interface{} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if instance.Error != nil {
+		return fmt.Errorf("%s", daemonErrorMessage(data))
+	}
+
+	out, err := adapters.ExportServerConfig(adapters.ServerName(instance.DisplayName), adapters.NewServerEntry(instance.InstanceID), format)
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.TrimRight(string(out), "\n"))
+	return nil
+}
 
+// bindCmd is "conduit bind", shorthand for "conduit client bind". It is the
+// short form for exporting a server config to a client without an adapter.
+func bindCmd() *cobra.Command {
+	cmd := clientBindCmd()
+	cmd.Long += `
+
+"conduit bind" is shorthand for "conduit client bind":
+  conduit bind abc123 --export
+  conduit bind abc123 --export --format toml`
 	return cmd
 }
 
@@ -2376,7 +4198,8 @@ Examples:
   conduit client unbind my-server --client claude-code
   conduit client unbind abc123 --client cursor
   conduit client unbind my-server --json   # JSON output for GUI`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
 
@@ -2385,6 +4208,9 @@ Examples:
 			}
 
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
 			// Find the binding
 			data, err := c.get("/api/v1/bindings")
@@ -2452,6 +4278,7 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&clientID, "client", "c", "claude-code", "Client to unbind from")
+	cmd.RegisterFlagCompletionFunc("client", completeClientIDs)
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
 
 	return cmd
@@ -2469,8 +4296,12 @@ Examples:
   conduit client bindings
   conduit client bindings abc123
   conduit client bindings --json   # JSON output for GUI`,
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
 			data, err := c.get("/api/v1/bindings")
 			if err != nil {
@@ -2567,7 +4398,12 @@ Examples:
 	cmd.AddCommand(kbListCmd())
 	cmd.AddCommand(kbRemoveCmd())
 	cmd.AddCommand(kbSearchCmd())
+	cmd.AddCommand(kbEvalCmd())
+	cmd.AddCommand(kbOpenCmd())
+	cmd.AddCommand(kbGetCmd())
 	cmd.AddCommand(kbSyncCmd())
+	cmd.AddCommand(kbWatchCmd())
+	cmd.AddCommand(kbReindexCmd())
 	cmd.AddCommand(kbStatsCmd())
 	cmd.AddCommand(kbMigrateCmd())
 	cmd.AddCommand(kbKagSyncCmd())
@@ -2576,6 +4412,10 @@ Examples:
 	cmd.AddCommand(kbKagDedupeCmd())
 	cmd.AddCommand(kbKagVectorizeCmd())
 	cmd.AddCommand(kbKagQueryCmd())
+	cmd.AddCommand(kbEntitiesCmd())
+	cmd.AddCommand(kbEntityCmd())
+	cmd.AddCommand(kbBackupCmd())
+	cmd.AddCommand(kbRestoreCmd())
 
 	return cmd
 }
@@ -2602,14 +4442,6 @@ func kbStatsCmd() *cobra.Command {
 			totalChunks := 0
 			var totalSize int64 = 0
 
-			fmt.Println("Knowledge Base Statistics")
-			fmt.Println("═════════════════════════════════════════")
-
-			if len(sources) == 0 {
-				fmt.Println("No sources configured")
-				return nil
-			}
-
 			for _, src := range sources {
 				source := src.(map[string]interface{})
 				docCount := int(source["doc_count"].(float64))
@@ -2621,6 +4453,24 @@ func kbStatsCmd() *cobra.Command {
 				totalSize += sizeBytes
 			}
 
+			if outputFormat != "table" {
+				return printOutput(map[string]interface{}{
+					"sources":    len(sources),
+					"documents":  totalDocs,
+					"chunks":     totalChunks,
+					"size_bytes": totalSize,
+					"by_source":  sources,
+				})
+			}
+
+			fmt.Println("Knowledge Base Statistics")
+			fmt.Println("═════════════════════════════════════════")
+
+			if len(sources) == 0 {
+				fmt.Println("No sources configured")
+				return nil
+			}
+
 			fmt.Printf("Sources:     %d\n", len(sources))
 			fmt.Printf("Documents:   %d\n", totalDocs)
 			fmt.Printf("Chunks:      %d\n", totalChunks)
@@ -2628,7 +4478,7 @@ func kbStatsCmd() *cobra.Command {
 			fmt.Println()
 			fmt.Println("By Source:")
 			fmt.Println("─────────────────────────────────────────")
-			fmt.Printf("%-20s %-8s %-8s %s\n", "NAME", "DOCS", "CHUNKS", "SIZE")
+			fmt.Printf("%-20s %-8s %-8s %-10s %-4s %s\n", "NAME", "DOCS", "CHUNKS", "SIZE", "KAG", "ENTITIES")
 
 			for _, src := range sources {
 				source := src.(map[string]interface{})
@@ -2636,9 +4486,14 @@ func kbStatsCmd() *cobra.Command {
 				docCount := int(source["doc_count"].(float64))
 				chunkCount := int(source["chunk_count"].(float64))
 				sizeBytes := int64(source["size_bytes"].(float64))
+				entityCount, _ := source["entity_count"].(float64)
+				kag := "off"
+				if extract, _ := source["extract_entities"].(bool); extract {
+					kag = "on"
+				}
 
-				fmt.Printf("%-20s %-8d %-8d %s\n",
-					truncate(name, 20), docCount, chunkCount, formatBytes(sizeBytes))
+				fmt.Printf("%-20s %-8d %-8d %-10s %-4s %d\n",
+					truncate(name, 20), docCount, chunkCount, formatBytes(sizeBytes), kag, int(entityCount))
 			}
 
 			return nil
@@ -2660,6 +4515,14 @@ func formatBytes(bytes int64) string {
 }
 
 // formatDuration formats a duration as a human-readable string (e.g., "2h 15m", "45m 30s")
+// formatLines renders a span of file lines as "12" or "12-18".
+func formatLines(start, end int) string {
+	if end <= start {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d-%d", start, end)
+}
+
 func formatDuration(d time.Duration) string {
 	d = d.Round(time.Second)
 
@@ -2682,7 +4545,9 @@ func kbAddCmd() *cobra.Command {
 	var name string
 	var patterns string
 	var excludes string
+	var exclude []string
 	var syncMode string
+	var extractEntities bool
 	var jsonOutput bool
 
 	cmd := &cobra.Command{
@@ -2693,10 +4558,22 @@ func kbAddCmd() *cobra.Command {
 The folder will be scanned for matching files which are then indexed
 for full-text search. By default, common text and code files are indexed.
 
+Exclude patterns use gitignore syntax and extend the default excludes
+(node_modules, .git, vendor, dist, ...): a name matches at any depth, a
+leading or inner "/" anchors to the folder root, a trailing "/" matches
+directories only, "**" spans directories, and "!" re-includes a path.
+A .conduitignore file in the folder root is honored the same way.
+
+KAG entity extraction is off for new sources unless
+kb.kag.extraction.default_for_new_sources is set. Use --extract-entities
+to enable or disable it for this source, e.g. on for docs and off for logs.
+
 Examples:
   conduit kb add ./docs --name "Project Docs"
   conduit kb add /path/to/notes --patterns "*.md,*.txt"
-  conduit kb add ./src --excludes "node_modules,dist"`,
+  conduit kb add ./src --exclude "*.log" --exclude "generated/"
+  conduit kb add ./repo --exclude "!vendor"
+  conduit kb add ./docs --extract-entities`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sourcePath := args[0]
@@ -2741,13 +4618,22 @@ Examples:
 				req["patterns"] = strings.Split(patterns, ",")
 			}
 			if excludes != "" {
-				req["excludes"] = strings.Split(excludes, ",")
+				exclude = append(exclude, strings.Split(excludes, ",")...)
+			}
+			if len(exclude) > 0 {
+				req["excludes"] = exclude
 			}
 			if syncMode != "" {
 				req["sync_mode"] = syncMode
 			}
+			if cmd.Flags().Changed("extract-entities") {
+				req["extract_entities"] = extractEntities
+			}
 
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 			data, err := c.post("/api/v1/kb/sources", req)
 			if err != nil {
 				if jsonOutput {
@@ -2777,6 +4663,9 @@ Examples:
 			fmt.Printf("✓ Added source: %s\n", sourceName)
 			fmt.Printf("  ID:   %s\n", sourceID)
 			fmt.Printf("  Path: %s\n", absPath)
+			if extract, _ := resp["extract_entities"].(bool); extract {
+				fmt.Println("  KAG:  entity extraction enabled")
+			}
 			fmt.Println()
 			fmt.Println("Run 'conduit kb sync' to index documents")
 
@@ -2786,8 +4675,11 @@ Examples:
 
 	cmd.Flags().StringVar(&name, "name", "", "Display name for the source")
 	cmd.Flags().StringVar(&patterns, "patterns", "", "File patterns to index (comma-separated, e.g., '*.md,*.txt')")
-	cmd.Flags().StringVar(&excludes, "excludes", "", "Directories to exclude (comma-separated, e.g., 'node_modules,dist')")
-	cmd.Flags().StringVar(&syncMode, "sync", "manual", "Sync mode: manual or auto")
+	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "Gitignore-style exclude pattern (repeatable, e.g., '*.log', 'build/', '!keep.md')")
+	cmd.Flags().StringVar(&excludes, "excludes", "", "Directories to exclude (comma-separated)")
+	cmd.Flags().MarkDeprecated("excludes", "use --exclude instead")
+	cmd.Flags().StringVar(&syncMode, "sync", "manual", "Sync mode: manual, or watch to re-index files as they change")
+	cmd.Flags().BoolVar(&extractEntities, "extract-entities", false, "Extract KAG entities from this source (default from kb.kag.extraction.default_for_new_sources)")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
 
 	return cmd
@@ -2799,37 +4691,61 @@ func kbListCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List knowledge base sources",
-		Aliases: []string{"ls"},
+		Aliases: []string{"ls", "sources"},
+		Long: `List knowledge base sources with their document and chunk counts and
+the outcome of their last sync.
+
+LAST SYNC is when the source last synced successfully. STATUS is the
+outcome of the most recent attempt: ✓ success, ⚠️ partial (some files
+failed), ❌ failed, or ⟳ while a sync is running. The error of a failed
+or partial sync is shown under the source.
+
+With --json, each source also carries last_sync_attempt, last_sync_status,
+error and syncing for monitoring.
+
+Examples:
+  conduit kb list
+  conduit kb sources --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 			data, err := c.get("/api/v1/kb/sources")
 			if err != nil {
 				return fmt.Errorf("failed to list KB sources: %w", err)
 			}
 
-			// JSON output for GUI consumption
-			if jsonOutput {
-				fmt.Println(string(data))
-				return nil
+			// JSON/YAML output for scripts and GUI consumption
+			if outputFormat != "table" {
+				return printOutput(data)
 			}
 
-			var resp map[string]interface{}
-			json.Unmarshal(data, &resp)
+			var resp struct {
+				Sources []kb.Source `json:"sources"`
+			}
+			if err := json.Unmarshal(data, &resp); err != nil {
+				return fmt.Errorf("parse sources: %w", err)
+			}
 
-			sources, _ := resp["sources"].([]interface{})
-			if len(sources) == 0 {
+			if len(resp.Sources) == 0 {
 				fmt.Println("No knowledge base sources configured")
 				return nil
 			}
 
-			fmt.Printf("%-20s %-40s %-10s\n", "NAME", "PATH", "DOCS")
-			for _, src := range sources {
-				s := src.(map[string]interface{})
-				fmt.Printf("%-20s %-40s %-10v\n",
-					s["name"],
-					s["path"],
-					s["doc_count"],
+			fmt.Printf("%-20s %-40s %8s %8s  %-12s %s\n", "NAME", "PATH", "DOCS", "CHUNKS", "LAST SYNC", "STATUS")
+			for _, src := range resp.Sources {
+				fmt.Printf("%-20s %-40s %8d %8d  %-12s %s\n",
+					truncate(src.Name, 20),
+					truncate(src.Path, 40),
+					src.DocCount,
+					src.ChunkCount,
+					formatSyncAge(src.LastSync),
+					formatSyncStatus(src),
 				)
+				if src.Error != "" && (src.LastSyncStatus == kb.SyncStatusFailed || src.LastSyncStatus == kb.SyncStatusPartial) {
+					fmt.Printf("    %s (%s)\n", src.Error, formatSyncAge(src.LastSyncAttempt))
+				}
 			}
 
 			return nil
@@ -2840,6 +4756,37 @@ func kbListCmd() *cobra.Command {
 	return cmd
 }
 
+// formatSyncAge renders a sync time as how long ago it was, or "never".
+func formatSyncAge(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+	}
+	return formatDuration(d.Truncate(time.Minute)) + " ago"
+}
+
+// formatSyncStatus renders the outcome of a source's last sync.
+func formatSyncStatus(src kb.Source) string {
+	if src.Syncing {
+		return "⟳ syncing"
+	}
+	switch src.LastSyncStatus {
+	case kb.SyncStatusSuccess:
+		return "✓ success"
+	case kb.SyncStatusPartial:
+		return "⚠️ partial"
+	case kb.SyncStatusFailed:
+		return "❌ failed"
+	}
+	return "○ not synced"
+}
+
 func kbRemoveCmd() *cobra.Command {
 	var force bool
 	var jsonOutput bool
@@ -2854,12 +4801,16 @@ Use 'conduit kb list' to see source names.
 Examples:
   conduit kb remove "User Files"
   conduit kb remove test --force`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeKBSourceNames,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			nameOrID := args[0]
 
 			// Get all sources to find by name or ID
 			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 			data, err := c.get("/api/v1/kb/sources")
 			if err != nil {
 				if jsonOutput {
@@ -2929,10 +4880,18 @@ Examples:
 
 			// Parse the response to get deletion statistics
 			var deleteResult struct {
-				DocumentsDeleted int `json:"documents_deleted"`
-				VectorsDeleted   int `json:"vectors_deleted"`
+				DocumentsDeleted int         `json:"documents_deleted"`
+				VectorsDeleted   int         `json:"vectors_deleted"`
+				Error            interface{} `json:"error"`
 			}
 			json.Unmarshal(respBytes, &deleteResult)
+			if deleteResult.Error != nil {
+				if jsonOutput {
+					fmt.Printf(`{"success":false,"error":%q}`, daemonErrorMessage(respBytes))
+					return nil
+				}
+				return fmt.Errorf("remove source: %s", daemonErrorMessage(respBytes))
+			}
 
 			// JSON output for GUI consumption
 			if jsonOutput {
@@ -2966,9 +4925,10 @@ Examples:
 
 func kbSearchCmd() *cobra.Command {
 	var semantic, fts5, raw, jsonOutput bool
-	var contextChunks, limit int
+	var contextChunks, limit, snippetLength int
+	var sourceID, pathPrefix, modifiedAfter, modifiedBefore, since, highlight, lang string
 	var minScore, semanticWeight, mmrLambda float64
-	var disableMMR, disableRerank bool
+	var disableMMR, disableRerank, explain, explainEmpty, expandEntities, interactive bool
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
@@ -2991,6 +4951,29 @@ ADVANCED MODE: RAG tuning flags allow fine-grained control over retrieval:
   --min-score         Minimum similarity threshold (0.0-1.0, default 0.0)
   --semantic-weight   Balance between semantic/lexical (0.0-1.0, default 0.5)
   --mmr-lambda        Relevance vs diversity (0.0-1.0, default 0.7)
+  --explain           Show how each hybrid result's score was built
+
+With KAG enabled, --expand-entities also matches the aliases of entities
+the query names in the knowledge graph (e.g. "k8s" finds "Kubernetes"),
+and the names of entities extracted as similar to them (hybrid mode;
+default from kb.rag.expand_entities).
+
+When nothing matches, --explain-why-empty first retries with relaxed
+matching and corrected spelling (hybrid mode), then explains why nothing was found: whether
+anything is indexed, whether the filters excluded everything, whether
+keyword and semantic search were available, which query terms appear in
+no document, and how to relax the query.
+
+Matched terms are marked **like this**; --highlight html marks them with
+<mark> tags instead, and --highlight none turns marking off.
+--snippet-length sets how much text each matched chunk contributes.
+
+Results are numbered; 'conduit kb open <n>' opens result n of the last
+search in your editor at the matched line.
+
+Use --interactive to browse results in a full-screen view: arrow through
+hits, preview the chunk (enter) or whole document (d), cycle the search
+mode (m), change the limit (+/-) or edit the query (/).
 
 Examples:
   conduit kb search "how does authentication work"    # Hybrid RRF (default)
@@ -2998,6 +4981,10 @@ Examples:
   conduit kb search "authentication" --semantic       # Force semantic only
   conduit kb search "class AuthProvider" --fts5       # Force keyword only
   conduit kb search "query" --raw                     # Raw chunks without processing
+  conduit kb search "rate limits" --path-prefix docs/api  # Only documents under docs/api
+  conduit kb search "retry decorator" --lang python   # Only Python files and code blocks
+  conduit kb search "release notes" --since 168h      # Only documents modified in the last week
+  conduit kb search "roadmap" --modified-after 2025-01-01 --modified-before 2025-07-01
 
   # Advanced: Lower threshold for more permissive matching
   conduit kb search "ASL-3 safeguards" --min-score 0.05
@@ -3006,12 +4993,25 @@ Examples:
   conduit kb search "AI safety deployment" --semantic --min-score 0.0
 
   # Advanced: Higher relevance, less diversity
-  conduit kb search "authentication" --mmr-lambda 0.9`,
+  conduit kb search "authentication" --mmr-lambda 0.9
+
+  # Debug ranking: per-result RRF contributions and boosts
+  conduit kb search "Oak Ridge" --explain --raw
+
+  # Find out why a search returns nothing
+  conduit kb search "zebra migration" --path-prefix docs/api --explain-why-empty
+
+  # Browse results interactively
+  conduit kb search "authentication" --interactive`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := args[0]
 			c := newClient(socketPath)
 
+			if interactive && jsonOutput {
+				return fmt.Errorf("cannot use both --interactive and --json flags")
+			}
+
 			// Determine search mode
 			mode := "hybrid"
 			if semantic && fts5 {
@@ -3023,36 +5023,81 @@ Examples:
 				mode = "fts5"
 			}
 
-			// Build API URL with processing options
-			apiURL := fmt.Sprintf("/api/v1/kb/search?q=%s&mode=%s", url.QueryEscape(query), mode)
+			// Build query parameters for processing options. Query, mode and
+			// limit are added per search so the interactive view can change them.
+			params := ""
 			if raw {
-				apiURL += "&raw=true"
+				params += "&raw=true"
 			}
 			if contextChunks > 0 {
-				apiURL += fmt.Sprintf("&context=%d", contextChunks)
+				params += fmt.Sprintf("&context=%d", contextChunks)
+			}
+			if sourceID != "" {
+				params += "&source_id=" + url.QueryEscape(sourceID)
+			}
+			if pathPrefix != "" {
+				params += "&path_prefix=" + url.QueryEscape(pathPrefix)
+			}
+			if lang != "" {
+				params += "&lang=" + url.QueryEscape(lang)
+			}
+			if modifiedAfter != "" {
+				params += "&modified_after=" + url.QueryEscape(modifiedAfter)
 			}
-			if limit > 0 {
-				apiURL += fmt.Sprintf("&limit=%d", limit)
+			if modifiedBefore != "" {
+				params += "&modified_before=" + url.QueryEscape(modifiedBefore)
 			}
+			if since != "" {
+				params += "&since=" + url.QueryEscape(since)
+			}
+			if snippetLength != 0 {
+				params += fmt.Sprintf("&snippet_length=%d", snippetLength)
+			}
+			// The full-screen view draws text as is, so markers only get in the way
+			if interactive && !cmd.Flags().Changed("highlight") {
+				highlight = "none"
+			}
+			params += "&highlight=" + url.QueryEscape(highlight)
 
 			// Advanced RAG parameters
 			if minScore >= 0 {
-				apiURL += fmt.Sprintf("&min_score=%.4f", minScore)
+				params += fmt.Sprintf("&min_score=%.4f", minScore)
 			}
 			if semanticWeight >= 0 {
-				apiURL += fmt.Sprintf("&semantic_weight=%.2f", semanticWeight)
+				params += fmt.Sprintf("&semantic_weight=%.2f", semanticWeight)
 			}
 			if mmrLambda >= 0 {
-				apiURL += fmt.Sprintf("&mmr_lambda=%.2f", mmrLambda)
+				params += fmt.Sprintf("&mmr_lambda=%.2f", mmrLambda)
 			}
 			if disableMMR {
-				apiURL += "&enable_mmr=false"
+				params += "&enable_mmr=false"
 			}
 			if disableRerank {
-				apiURL += "&enable_rerank=false"
+				params += "&enable_rerank=false"
+			}
+			if explain {
+				params += "&explain=true"
+			}
+			if explainEmpty {
+				params += "&explain_empty=true"
+			}
+			if cmd.Flags().Changed("expand-entities") {
+				params += fmt.Sprintf("&expand_entities=%t", expandEntities)
+			}
+
+			search := func(query, mode string, limit int) ([]byte, error) {
+				u := fmt.Sprintf("/api/v1/kb/search?q=%s&mode=%s", url.QueryEscape(query), mode)
+				if limit > 0 {
+					u += fmt.Sprintf("&limit=%d", limit)
+				}
+				return c.get(u + params)
+			}
+
+			if interactive {
+				return runKBSearchBrowser(search, query, mode, limit)
 			}
 
-			data, err := c.get(apiURL)
+			data, err := search(query, mode, limit)
 			if err != nil {
 				if jsonOutput {
 					fmt.Printf(`{"success":false,"error":"search failed: %s"}`, err.Error())
@@ -3061,111 +5106,575 @@ Examples:
 				return fmt.Errorf("search failed: %w", err)
 			}
 
-			// JSON output for GUI consumption
-			if jsonOutput {
-				fmt.Println(string(data))
-				return nil
-			}
-
 			var resp map[string]interface{}
 			json.Unmarshal(data, &resp)
+			if _, failed := resp["error"]; !failed {
+				saveLastKBSearch(query, data)
+			}
+
+			// JSON/YAML output for scripts and GUI consumption
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
+			if _, failed := resp["error"]; failed {
+				return fmt.Errorf("search failed: %s", daemonErrorMessage(data))
+			}
 
 			results, _ := resp["results"].([]interface{})
 			searchMode, _ := resp["search_mode"].(string)
 
 			if len(results) == 0 {
 				fmt.Printf("No results found for: %s\n", query)
+				if whyEmpty, ok := resp["why_empty"]; ok {
+					printEmptySearchExplanation(whyEmpty)
+				} else if !explainEmpty {
+					fmt.Println("Run again with --explain-why-empty to see why")
+				}
 				return nil
 			}
 
-			// Show search mode indicator
-			modeLabel := ""
-			switch searchMode {
-			case "semantic":
-				modeLabel = " [semantic]"
-			case "fts5", "lexical":
-				modeLabel = " [keyword]"
-			case "fusion":
-				modeLabel = " [hybrid RRF]"
-			case "auto":
-				modeLabel = " [hybrid]"
+			// Show search mode indicator
+			modeLabel := ""
+			switch searchMode {
+			case "semantic":
+				modeLabel = " [semantic]"
+			case "fts5", "lexical":
+				modeLabel = " [keyword]"
+			case "fusion":
+				modeLabel = " [hybrid RRF]"
+			case "auto":
+				modeLabel = " [hybrid]"
+			}
+
+			// Check if results are processed (merged)
+			isProcessed, _ := resp["processed"].(bool)
+			if isProcessed {
+				modeLabel += " [processed]"
+			}
+
+			total := fmt.Sprint(resp["total_hits"])
+			if lowerBound, _ := resp["total_is_lower_bound"].(bool); lowerBound {
+				total = "at least " + total
+			}
+			fmt.Printf("Found %s results for: %s%s\n", total, query, modeLabel)
+			if note, ok := resp["note"].(string); ok && note != "" {
+				fmt.Printf("⚠️  %s\n", note)
+			}
+			if expansions, ok := resp["entity_expansions"].([]interface{}); ok {
+				printEntityExpansions(expansions)
+			}
+			fmt.Println()
+
+			// Display results based on whether they're processed or raw
+			if isProcessed {
+				// Processed results have merged content
+				for i, r := range results {
+					result := r.(map[string]interface{})
+					path, _ := result["path"].(string)
+					content, _ := result["content"].(string)
+					chunkCount := 1
+					if cc, ok := result["chunk_count"].(float64); ok {
+						chunkCount = int(cc)
+					}
+
+					// Extract filename for cleaner display
+					parts := strings.Split(path, "/")
+					filename := path
+					if len(parts) > 0 {
+						filename = parts[len(parts)-1]
+					}
+
+					// Prefer the section breadcrumb (e.g. "README.md › Install › Docker")
+					label := filename
+					if source, ok := result["source"].(map[string]interface{}); ok {
+						if breadcrumb, ok := source["breadcrumb"].(string); ok && breadcrumb != "" {
+							label = breadcrumb
+						}
+					}
+
+					if chunkCount > 1 {
+						fmt.Printf("[%d] %s (%d chunks merged)\n", i+1, label, chunkCount)
+					} else {
+						fmt.Printf("[%d] %s\n", i+1, label)
+					}
+					fmt.Printf("  Path: %s\n", path)
+					if source, ok := result["source"].(map[string]interface{}); ok {
+						start, _ := source["start_line"].(float64)
+						end, _ := source["end_line"].(float64)
+						if start > 0 {
+							fmt.Printf("  Lines: %s\n", formatLines(int(start), int(end)))
+						}
+					}
+					if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+						if language, ok := metadata["language"].(string); ok && language != "" {
+							fmt.Printf("  Language: %s\n", language)
+						}
+					}
+					if alternates, ok := result["alternates"].([]interface{}); ok && len(alternates) > 0 {
+						paths := make([]string, 0, len(alternates))
+						for _, a := range alternates {
+							if s, ok := a.(string); ok {
+								paths = append(paths, s)
+							}
+						}
+						fmt.Printf("  Also in: %s\n", strings.Join(paths, ", "))
+					}
+					if explanations, ok := result["explanations"].([]interface{}); ok {
+						for _, e := range explanations {
+							printScoreExplanation(e)
+						}
+					}
+					fmt.Printf("  %s\n\n", content)
+				}
+			} else {
+				// Raw results show individual chunks
+				for i, r := range results {
+					result := r.(map[string]interface{})
+					path, _ := result["path"].(string)
+					snippet, _ := result["snippet"].(string)
+
+					// Show confidence for semantic results
+					confidence, hasConfidence := result["confidence"].(string)
+					if hasConfidence && confidence != "" {
+						fmt.Printf("[%d] %s [%s]\n", i+1, path, confidence)
+					} else {
+						fmt.Printf("[%d] %s\n", i+1, path)
+					}
+					if metadata, ok := result["metadata"].(map[string]interface{}); ok {
+						if headingPath, ok := metadata["heading_path"].(string); ok && headingPath != "" {
+							fmt.Printf("  Section: %s\n", headingPath)
+						}
+						startLine, _ := metadata["start_line"].(string)
+						endLine, _ := metadata["end_line"].(string)
+						if start, _ := strconv.Atoi(startLine); start > 0 {
+							end, _ := strconv.Atoi(endLine)
+							fmt.Printf("  Lines: %s\n", formatLines(start, end))
+						}
+						if language, ok := metadata["language"].(string); ok && language != "" {
+							fmt.Printf("  Language: %s\n", language)
+						}
+					}
+					if e, ok := result["explanation"]; ok {
+						printScoreExplanation(e)
+					}
+					fmt.Printf("  %s\n\n", snippet)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&semantic, "semantic", false, "Force semantic search (requires Qdrant + Ollama)")
+	cmd.Flags().BoolVar(&fts5, "fts5", false, "Force FTS5 keyword search")
+	cmd.Flags().BoolVar(&raw, "raw", false, "Return raw chunks without processing")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Browse results in an interactive terminal view")
+	cmd.Flags().IntVar(&contextChunks, "context", 0, "Number of adjacent chunks to include")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum results to return (default: 10)")
+	cmd.Flags().IntVar(&snippetLength, "snippet-length", 0, "Characters of text per matched chunk (50-4000, default from config: 300)")
+	cmd.Flags().StringVar(&highlight, "highlight", "markdown", "Mark matched terms: markdown, html or none")
+	cmd.Flags().StringVar(&sourceID, "source", "", "Only search this source (ID from 'conduit kb list --json')")
+	cmd.Flags().StringVar(&pathPrefix, "path-prefix", "", "Only search documents under this path (absolute, or relative to the source root)")
+	cmd.Flags().StringVar(&lang, "lang", "", "Only chunks in these languages, comma-separated (e.g. python,typescript)")
+	cmd.Flags().StringVar(&modifiedAfter, "modified-after", "", "Only documents modified on or after this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().StringVar(&modifiedBefore, "modified-before", "", "Only documents modified before this date (YYYY-MM-DD or RFC3339)")
+	cmd.Flags().StringVar(&since, "since", "", "Only documents modified within this duration (e.g. 72h)")
+
+	// Advanced RAG tuning flags
+	cmd.Flags().Float64Var(&minScore, "min-score", -1, "Minimum similarity threshold (0.0-1.0)")
+	cmd.Flags().Float64Var(&semanticWeight, "semantic-weight", -1, "Semantic vs lexical weight (0.0-1.0)")
+	cmd.Flags().Float64Var(&mmrLambda, "mmr-lambda", -1, "Relevance vs diversity balance (0.0-1.0)")
+	cmd.Flags().BoolVar(&disableMMR, "no-mmr", false, "Disable MMR diversity filtering")
+	cmd.Flags().BoolVar(&disableRerank, "no-rerank", false, "Disable semantic reranking")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Show per-result score breakdown (hybrid mode)")
+	cmd.Flags().BoolVar(&explainEmpty, "explain-why-empty", false, "When nothing matches, retry with relaxed matching and explain why")
+	cmd.Flags().BoolVar(&expandEntities, "expand-entities", false, "Also match aliases and similar names of knowledge graph entities in the query (needs KAG)")
+
+	return cmd
+}
+
+// printEntityExpansions prints the entity names a search added to the query,
+// from the entity_expansions field of a search response.
+func printEntityExpansions(expansions []interface{}) {
+	for _, e := range expansions {
+		expansion, _ := e.(map[string]interface{})
+		mention, _ := expansion["mention"].(string)
+		names, _ := expansion["names"].([]interface{})
+		parts := make([]string, 0, len(names))
+		for _, n := range names {
+			if name, ok := n.(string); ok {
+				parts = append(parts, name)
+			}
+		}
+		if mention != "" && len(parts) > 0 {
+			fmt.Printf("🔗 Also matched %q as: %s\n", mention, strings.Join(parts, ", "))
+		}
+	}
+}
+
+// printEmptySearchExplanation prints why a search found nothing, from the
+// why_empty field of a search response.
+func printEmptySearchExplanation(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	var e kb.EmptySearchExplanation
+	if err := json.Unmarshal(data, &e); err != nil {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("🔍 Why No Results")
+	fmt.Println("────────────────────────────────────────────────────────")
+	fmt.Printf("Index:     %d documents, %d chunks in %d source(s)\n", e.Documents, e.Chunks, e.Sources)
+	if e.FTSAvailable {
+		fmt.Println("Keyword:   ✓ available")
+	} else {
+		fmt.Println("Keyword:   ❌ unavailable")
+	}
+	switch {
+	case !e.SemanticAvailable:
+		fmt.Printf("Semantic:  ❌ unavailable (%s)\n", e.SemanticError)
+	case e.SemanticError != "":
+		fmt.Printf("Semantic:  ⚠️  %s\n", e.SemanticError)
+	default:
+		fmt.Printf("Semantic:  ✓ available (%d vectors)\n", e.Vectors)
+	}
+	for i, f := range e.Filters {
+		label := ""
+		if i == 0 {
+			label = "Filters:"
+		}
+		mark := "✓"
+		if f.Chunks == 0 {
+			mark = "❌"
+		}
+		fmt.Printf("%-10s %s %s=%s (%d chunks)\n", label, mark, f.Name, f.Value, f.Chunks)
+	}
+	if len(e.Filters) > 1 {
+		fmt.Printf("%-10s %d chunks pass all filters\n", "", e.FilteredChunks)
+	}
+	if len(e.Terms) > 0 {
+		terms := make([]string, len(e.Terms))
+		for i, t := range e.Terms {
+			terms[i] = fmt.Sprintf("%q %d", t.Term, t.Chunks)
+		}
+		fmt.Printf("Terms:     %s (chunks containing each)\n", strings.Join(terms, ", "))
+	}
+
+	if len(e.Reasons) > 0 {
+		fmt.Println()
+		fmt.Println("Why:")
+		for _, r := range e.Reasons {
+			fmt.Printf("  • %s\n", r)
+		}
+	}
+	if len(e.Suggestions) > 0 {
+		fmt.Println()
+		fmt.Println("Try:")
+		for _, s := range e.Suggestions {
+			fmt.Printf("  • %s\n", s)
+		}
+	}
+}
+
+// printScoreExplanation prints one hybrid score breakdown from a search response.
+func printScoreExplanation(v interface{}) {
+	e, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	num := func(key string) float64 {
+		f, _ := e[key].(float64)
+		return f
+	}
+	rank := func(key string) string {
+		if r := num(key); r > 0 {
+			return fmt.Sprintf("#%d", int(r))
+		}
+		return "-"
+	}
+
+	fmt.Printf("  Rank %d: %.4f = (lex %.4f %s + sem %.4f %s) × entity %.2f × agreement %.2f × rerank %.2f\n",
+		int(num("rank")), num("final_score"),
+		num("lexical_rrf"), rank("lexical_rank"),
+		num("semantic_rrf"), rank("semantic_rank"),
+		num("entity_boost"), num("agreement_boost"), num("rerank_multiplier"))
+	if matched, ok := e["matched_entities"].([]interface{}); ok && len(matched) > 0 {
+		names := make([]string, len(matched))
+		for i, m := range matched {
+			names[i] = fmt.Sprint(m)
+		}
+		fmt.Printf("    Matched entities: %s\n", strings.Join(names, ", "))
+	}
+}
+
+// kbEvalParams are the search parameters an evaluation configuration may set.
+var kbEvalParams = []string{
+	"mode", "hybrid_mode", "min_score", "semantic_weight", "rrf_constant", "mmr_lambda",
+	"enable_mmr", "enable_rerank", "expand_entities", "max_expansions",
+	"source_id", "path_prefix", "lang",
+}
+
+// kbEvalRun is the evaluation of one ranking configuration.
+type kbEvalRun struct {
+	Name    string               `json:"name"`
+	Params  string               `json:"params"`
+	Metrics kb.EvalMetrics       `json:"metrics"`
+	Queries []kb.EvalQueryResult `json:"queries"`
+}
+
+func kbEvalCmd() *cobra.Command {
+	var queriesFile, baseline, candidate string
+	var k int
+	var perQuery, jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Compare ranking configurations on a set of queries",
+		Long: `Run a set of queries with known relevant documents against one or two
+search configurations and report precision@k, recall@k and MRR (mean
+reciprocal rank of the first relevant document) for each.
+
+The queries file has one JSON object per line with the query and the
+documents relevant to it, by document ID (as shown by 'conduit kb search
+--json'), absolute path or path suffix. Blank lines and lines starting
+with # are skipped:
+
+  {"query": "deploy to staging", "expected_doc_ids": ["guides/deploy.md"]}
+  {"query": "rotate api keys", "expected_doc_ids": ["doc_3f2a9c1e"]}
+
+--baseline and --candidate are search parameters in query string form,
+applied on top of the daemon's configuration: mode, hybrid_mode,
+min_score, semantic_weight, rrf_constant, mmr_lambda, enable_mmr,
+enable_rerank, expand_entities, max_expansions, source_id, path_prefix
+and lang. Without --candidate only the baseline is evaluated.
+
+Examples:
+  conduit kb eval --queries queries.jsonl
+  conduit kb eval --queries queries.jsonl --candidate enable_mmr=false
+  conduit kb eval --queries queries.jsonl --baseline semantic_weight=0.3 \
+      --candidate "semantic_weight=0.7&rrf_constant=30" --k 5 --per-query`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if k <= 0 {
+				return fmt.Errorf("--k must be greater than 0")
 			}
-
-			// Check if results are processed (merged)
-			isProcessed, _ := resp["processed"].(bool)
-			if isProcessed {
-				modeLabel += " [processed]"
+			f, err := os.Open(queriesFile)
+			if err != nil {
+				return fmt.Errorf("open queries: %w", err)
+			}
+			queries, err := kb.ParseEvalQueries(f)
+			f.Close()
+			if err != nil {
+				return fmt.Errorf("read %s: %w", queriesFile, err)
 			}
 
-			fmt.Printf("Found %v results for: %s%s\n\n", resp["total_hits"], query, modeLabel)
+			type config struct {
+				name   string
+				params url.Values
+				raw    string
+			}
+			var configs []config
+			for _, c := range []struct{ name, flag, value string }{
+				{"baseline", "--baseline", baseline},
+				{"candidate", "--candidate", candidate},
+			} {
+				if c.name == "candidate" && !cmd.Flags().Changed("candidate") {
+					continue
+				}
+				params, err := parseKBEvalParams(c.value)
+				if err != nil {
+					return fmt.Errorf("%s: %w", c.flag, err)
+				}
+				configs = append(configs, config{c.name, params, c.value})
+			}
 
-			// Display results based on whether they're processed or raw
-			if isProcessed {
-				// Processed results have merged content
-				for _, r := range results {
-					result := r.(map[string]interface{})
-					path, _ := result["path"].(string)
-					content, _ := result["content"].(string)
-					chunkCount := 1
-					if cc, ok := result["chunk_count"].(float64); ok {
-						chunkCount = int(cc)
-					}
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
 
-					// Extract filename for cleaner display
-					parts := strings.Split(path, "/")
-					filename := path
-					if len(parts) > 0 {
-						filename = parts[len(parts)-1]
+			runs := make([]kbEvalRun, 0, len(configs))
+			failed := 0
+			for _, cfg := range configs {
+				run := kbEvalRun{Name: cfg.name, Params: cfg.raw}
+				for _, q := range queries {
+					params := url.Values{}
+					for key, values := range cfg.params {
+						params[key] = values
 					}
+					params.Set("q", q.Query)
+					params.Set("limit", strconv.Itoa(k))
 
-					if chunkCount > 1 {
-						fmt.Printf("• %s (%d chunks merged)\n", filename, chunkCount)
-					} else {
-						fmt.Printf("• %s\n", filename)
+					docs, err := kbEvalSearch(c, params, k)
+					result := kb.ScoreEvalQuery(q, docs, k)
+					if err != nil {
+						result.Error = err.Error()
+						failed++
 					}
-					fmt.Printf("  Path: %s\n", path)
-					fmt.Printf("  %s\n\n", content)
+					run.Queries = append(run.Queries, result)
 				}
-			} else {
-				// Raw results show individual chunks
-				for _, r := range results {
-					result := r.(map[string]interface{})
-					path, _ := result["path"].(string)
-					snippet, _ := result["snippet"].(string)
+				run.Metrics = kb.MeanEvalMetrics(run.Queries)
+				runs = append(runs, run)
+			}
 
-					// Show confidence for semantic results
-					confidence, hasConfidence := result["confidence"].(string)
-					if hasConfidence && confidence != "" {
-						fmt.Printf("• %s [%s]\n  %s\n\n", path, confidence, snippet)
-					} else {
-						fmt.Printf("• %s\n  %s\n\n", path, snippet)
-					}
+			if jsonOutput || outputFormat != "table" {
+				data, err := json.Marshal(map[string]interface{}{
+					"k":       k,
+					"queries": len(queries),
+					"runs":    runs,
+				})
+				if err != nil {
+					return err
 				}
+				return printOutput(data)
 			}
 
+			printKBEval(runs, len(queries), k, perQuery)
+			if failed > 0 {
+				fmt.Printf("\n⚠️  %d searches failed and count as finding nothing (see --json for the errors)\n", failed)
+			}
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&semantic, "semantic", false, "Force semantic search (requires Qdrant + Ollama)")
-	cmd.Flags().BoolVar(&fts5, "fts5", false, "Force FTS5 keyword search")
-	cmd.Flags().BoolVar(&raw, "raw", false, "Return raw chunks without processing")
-	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON (for GUI consumption)")
-	cmd.Flags().IntVar(&contextChunks, "context", 0, "Number of adjacent chunks to include")
-	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum results to return (default: 10)")
-
-	// Advanced RAG tuning flags
-	cmd.Flags().Float64Var(&minScore, "min-score", -1, "Minimum similarity threshold (0.0-1.0)")
-	cmd.Flags().Float64Var(&semanticWeight, "semantic-weight", -1, "Semantic vs lexical weight (0.0-1.0)")
-	cmd.Flags().Float64Var(&mmrLambda, "mmr-lambda", -1, "Relevance vs diversity balance (0.0-1.0)")
-	cmd.Flags().BoolVar(&disableMMR, "no-mmr", false, "Disable MMR diversity filtering")
-	cmd.Flags().BoolVar(&disableRerank, "no-rerank", false, "Disable semantic reranking")
+	cmd.Flags().StringVar(&queriesFile, "queries", "", "JSONL file of queries with their relevant documents (required)")
+	cmd.Flags().StringVar(&baseline, "baseline", "", "Search parameters of the baseline, e.g. enable_mmr=true (default: daemon config)")
+	cmd.Flags().StringVar(&candidate, "candidate", "", "Search parameters of the configuration to compare, e.g. \"semantic_weight=0.7&rrf_constant=30\"")
+	cmd.Flags().IntVar(&k, "k", 10, "Number of top documents scored per query")
+	cmd.Flags().BoolVar(&perQuery, "per-query", false, "Show the rank of the first relevant document for each query")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.MarkFlagRequired("queries")
 
 	return cmd
 }
 
+// parseKBEvalParams parses an evaluation configuration's search parameters.
+func parseKBEvalParams(s string) (url.Values, error) {
+	params, err := url.ParseQuery(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters %q: %w", s, err)
+	}
+	for key := range params {
+		if !slices.Contains(kbEvalParams, key) {
+			return nil, fmt.Errorf("unknown search parameter %q (allowed: %s)", key, strings.Join(kbEvalParams, ", "))
+		}
+	}
+	return params, nil
+}
+
+// kbEvalSearch runs one evaluation search and returns the ranked documents.
+func kbEvalSearch(c *client, params url.Values, k int) ([]kb.EvalDocument, error) {
+	// Several chunks of one document count as one result, so keep paging
+	// until the results name k documents or run out
+	var docs []kb.EvalDocument
+	seen := make(map[string]bool)
+	for page := 0; page < kbEvalMaxPages; page++ {
+		params.Set("offset", strconv.Itoa(page*k))
+		data, err := c.get("/api/v1/kb/search?" + params.Encode())
+		if err != nil {
+			return docs, err
+		}
+		var resp struct {
+			Results []kb.EvalDocument `json:"results"`
+			HasMore bool              `json:"has_more"`
+			Error   interface{}       `json:"error"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return docs, fmt.Errorf("invalid response: %w", err)
+		}
+		if resp.Error != nil {
+			return docs, fmt.Errorf("%s", daemonErrorMessage(data))
+		}
+
+		docs = append(docs, resp.Results...)
+		for _, doc := range resp.Results {
+			seen[doc.DocumentID] = true
+		}
+		if len(seen) >= k || !resp.HasMore {
+			break
+		}
+	}
+	return docs, nil
+}
+
+// kbEvalMaxPages bounds the result pages fetched for one evaluation query.
+const kbEvalMaxPages = 10
+
+// printKBEval prints the metrics of each evaluated configuration side by
+// side, with the candidate's change over the baseline.
+func printKBEval(runs []kbEvalRun, queries, k int, perQuery bool) {
+	fmt.Println("📊 Ranking Evaluation")
+	fmt.Println("────────────────────────────────────────────────────────")
+	fmt.Printf("Queries:    %d (top %d documents each)\n", queries, k)
+	for _, run := range runs {
+		params := run.Params
+		if params == "" {
+			params = "daemon config"
+		}
+		fmt.Printf("%-11s %s\n", strings.ToUpper(run.Name[:1])+run.Name[1:]+":", params)
+	}
+	fmt.Println()
+
+	header := fmt.Sprintf("%-14s", "METRIC")
+	for _, run := range runs {
+		header += fmt.Sprintf(" %-10s", strings.ToUpper(run.Name))
+	}
+	if len(runs) == 2 {
+		header += " CHANGE"
+	}
+	fmt.Println(header)
+	rows := []struct {
+		name  string
+		value func(kb.EvalMetrics) float64
+	}{
+		{fmt.Sprintf("Precision@%d", k), func(m kb.EvalMetrics) float64 { return m.PrecisionAtK }},
+		{fmt.Sprintf("Recall@%d", k), func(m kb.EvalMetrics) float64 { return m.RecallAtK }},
+		{"MRR", func(m kb.EvalMetrics) float64 { return m.MRR }},
+	}
+	for _, row := range rows {
+		line := fmt.Sprintf("%-14s", row.name)
+		for _, run := range runs {
+			line += fmt.Sprintf(" %-10.3f", row.value(run.Metrics))
+		}
+		if len(runs) == 2 {
+			line += fmt.Sprintf(" %+.3f", row.value(runs[1].Metrics)-row.value(runs[0].Metrics))
+		}
+		fmt.Println(line)
+	}
+
+	if !perQuery {
+		return
+	}
+	fmt.Println()
+	header = fmt.Sprintf("%-40s", "QUERY")
+	for _, run := range runs {
+		header += fmt.Sprintf(" %-10s", strings.ToUpper(run.Name))
+	}
+	fmt.Println(header + "  (rank of first relevant document, found/expected)")
+	for i := range runs[0].Queries {
+		line := fmt.Sprintf("%-40s", truncate(runs[0].Queries[i].Query, 40))
+		for _, run := range runs {
+			r := run.Queries[i]
+			rank := "-"
+			switch {
+			case r.Error != "":
+				rank = "error"
+			case r.FirstRank > 0:
+				rank = strconv.Itoa(r.FirstRank)
+			}
+			line += fmt.Sprintf(" %-10s", fmt.Sprintf("%s %d/%d", rank, r.Found, r.Expected))
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+}
+
 func kbSyncCmd() *cobra.Command {
 	var rebuildVectors bool
+	var schedule string
 
 	cmd := &cobra.Command{
 		Use:   "sync [source-id]",
@@ -3175,6 +5684,12 @@ func kbSyncCmd() *cobra.Command {
 If a source ID is provided, only that source is synced.
 If no source ID is provided, all sources are synced.
 
+With --schedule, nothing is synced: the daemon's background sync schedule
+is shown, or with a source ID and an interval, that source's interval is
+changed. Scheduled syncs are incremental and are turned on with
+kb.schedule.enabled in conduit.yaml; kb.schedule.interval (default 1h) is
+the interval of sources without their own.
+
 Exit Codes:
   0  Full success (FTS + semantic indexing)
   1  Error (sync failed)
@@ -3183,10 +5698,32 @@ Exit Codes:
 Examples:
   conduit kb sync                    # Sync all sources
   conduit kb sync abc123-def456      # Sync specific source
-  conduit kb sync --rebuild-vectors  # Force rebuild vector index`,
+  conduit kb sync --rebuild-vectors  # Force rebuild vector index
+  conduit kb sync --schedule         # Show the sync schedule
+  conduit kb sync abc123-def456 --schedule=30m      # Sync every 30 minutes
+  conduit kb sync abc123-def456 --schedule=off      # Never sync on a schedule
+  conduit kb sync abc123-def456 --schedule=default  # Use kb.schedule.interval`,
+		ValidArgsFunction: completeKBSourceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Use a longer timeout for sync (10 minutes) - large file embedding can be slow
 			c := newClientWithTimeout(socketPath, 10*time.Minute)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("schedule") {
+				// "--schedule 30m" leaves the interval as a second argument
+				if schedule == kbScheduleShow && len(args) == 2 {
+					schedule = args[1]
+				}
+				if schedule == kbScheduleShow {
+					return showKBSchedule(c)
+				}
+				if len(args) == 0 {
+					return fmt.Errorf("specify the source to schedule: conduit kb sync <source-id> --schedule=%s", schedule)
+				}
+				return setKBSourceSchedule(c, args[0], schedule)
+			}
 
 			if len(args) > 0 {
 				// Sync specific source
@@ -3225,6 +5762,7 @@ Examples:
 				fmt.Printf("  Added:   %d documents\n", added)
 				fmt.Printf("  Updated: %d documents\n", updated)
 				fmt.Printf("  Deleted: %d documents\n", deleted)
+				printSkippedFiles(result)
 
 				// Show semantic search status
 				semanticErrors := 0
@@ -3346,6 +5884,9 @@ Examples:
 					} else {
 						fmt.Printf("done (+%d/~%d/-%d)\n", added, updated, deleted)
 					}
+					if skipped, ok := result["skipped"].([]interface{}); ok && len(skipped) > 0 {
+						fmt.Printf("    ⚠️  %d files skipped (too large); see 'conduit kb sync %s'\n", len(skipped), sourceID)
+					}
 				}
 
 				fmt.Println()
@@ -3371,12 +5912,419 @@ Examples:
 		},
 	}
 
-	cmd.Flags().BoolVar(&rebuildVectors, "rebuild-vectors", false, "Force rebuild of vector index for all documents")
+	cmd.Flags().BoolVar(&rebuildVectors, "rebuild-vectors", false, "Force rebuild of vector index for all documents")
+	cmd.Flags().StringVar(&schedule, "schedule", "", "Show the sync schedule, or set a source's interval (e.g. 30m, off, default)")
+	cmd.Flags().Lookup("schedule").NoOptDefVal = kbScheduleShow
+	return cmd
+}
+
+// kbScheduleShow is the value of a bare --schedule flag.
+const kbScheduleShow = "show"
+
+// showKBSchedule prints the background sync schedule of every source.
+func showKBSchedule(c *client) error {
+	data, err := c.get("/api/v1/kb/schedule")
+	if err != nil {
+		return fmt.Errorf("get sync schedule: %w", err)
+	}
+	if outputFormat != "table" {
+		return printOutput(data)
+	}
+
+	var resp struct {
+		Enabled  bool        `json:"enabled"`
+		Interval string      `json:"interval"`
+		Sources  []kb.Source `json:"sources"`
+		Error    *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("parse sync schedule: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+
+	fmt.Println("📅 Scheduled Sync")
+	fmt.Println("────────────────────────────────────────────────────────")
+	if resp.Enabled {
+		fmt.Printf("✓ Enabled, every %s unless a source sets its own interval\n", resp.Interval)
+	} else {
+		fmt.Println("○ Disabled. To enable, set in ~/.conduit/conduit.yaml and restart the daemon:")
+		fmt.Println("    kb:")
+		fmt.Println("      schedule:")
+		fmt.Println("        enabled: true")
+		fmt.Printf("        interval: %s\n", resp.Interval)
+	}
+	if len(resp.Sources) == 0 {
+		fmt.Println()
+		fmt.Println("No knowledge base sources configured")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Printf("%-20s %-10s %-14s %-12s %s\n", "SOURCE", "INTERVAL", "NEXT SYNC", "LAST ATTEMPT", "STATUS")
+	for _, src := range resp.Sources {
+		interval := src.SyncInterval
+		if interval == "" {
+			interval = "default"
+		}
+		fmt.Printf("%-20s %-10s %-14s %-12s %s\n",
+			truncate(src.Name, 20),
+			interval,
+			formatNextSync(src, resp.Enabled),
+			formatSyncAge(src.LastSyncAttempt),
+			formatSyncStatus(src),
+		)
+	}
+	return nil
+}
+
+// formatNextSync renders when the scheduler syncs a source next.
+func formatNextSync(src kb.Source, enabled bool) string {
+	switch {
+	case (!enabled && src.WatchStatus != kb.WatchStatusFallback) || src.SyncInterval == kb.SyncIntervalOff:
+		return "-"
+	case src.Status != "active":
+		return src.Status
+	case src.NextSync == nil || !src.NextSync.After(time.Now()):
+		return "due"
+	case time.Until(*src.NextSync) < time.Minute:
+		return "in <1m"
+	}
+	return "in " + formatDuration(time.Until(*src.NextSync).Truncate(time.Minute))
+}
+
+// setKBSourceSchedule changes a source's scheduled sync interval.
+func setKBSourceSchedule(c *client, sourceID, interval string) error {
+	if _, _, err := kb.ParseSyncInterval(interval); err != nil {
+		return err
+	}
+
+	data, err := c.put("/api/v1/kb/sources/"+sourceID+"/schedule", map[string]string{"interval": interval})
+	if err != nil {
+		return fmt.Errorf("set sync schedule: %w", err)
+	}
+	var resp struct {
+		kb.Source
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	if outputFormat != "table" {
+		return printOutput(data)
+	}
+
+	switch resp.SyncInterval {
+	case "":
+		fmt.Printf("✓ %s now syncs on the default interval (kb.schedule.interval)\n", resp.Name)
+	case kb.SyncIntervalOff:
+		fmt.Printf("✓ %s is no longer synced on a schedule\n", resp.Name)
+	default:
+		fmt.Printf("✓ %s now syncs every %s\n", resp.Name, resp.SyncInterval)
+	}
+	if resp.NextSync == nil && resp.SyncInterval != kb.SyncIntervalOff {
+		fmt.Println("  Scheduled sync is disabled; set kb.schedule.enabled: true in conduit.yaml and restart the daemon")
+	}
+	return nil
+}
+
+func kbWatchCmd() *cobra.Command {
+	var off bool
+
+	cmd := &cobra.Command{
+		Use:   "watch [source-id]",
+		Short: "Re-index a source's files as they change",
+		Long: `Watch a knowledge base source folder and re-index files shortly after
+they are created, changed, renamed or deleted, instead of waiting for a
+manual or scheduled sync. Only the changed files are re-indexed.
+
+Without a source ID, the watch status of every source is shown:
+  watching  changes are picked up as they happen
+  fallback  the folder could not be watched (for example, the system's
+            limit on watched directories was reached); the source is
+            synced on the kb.schedule.interval instead
+
+Changes are batched until they settle for kb.watch_debounce (default
+500ms). Sources can also be watched from the start with
+'conduit kb add <path> --sync watch'.
+
+Examples:
+  conduit kb watch                       # Show watch status
+  conduit kb watch abc123-def456         # Watch a source
+  conduit kb watch abc123-def456 --off   # Stop watching it`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeKBSourceIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				if off {
+					return fmt.Errorf("specify the source to stop watching: conduit kb watch <source-id> --off")
+				}
+				return showKBWatch(c)
+			}
+			return setKBSourceWatch(c, args[0], !off)
+		},
+	}
+
+	cmd.Flags().BoolVar(&off, "off", false, "Stop watching the source (back to manual sync)")
+	return cmd
+}
+
+// showKBWatch prints the watch status of every source.
+func showKBWatch(c *client) error {
+	data, err := c.get("/api/v1/kb/sources")
+	if err != nil {
+		return fmt.Errorf("failed to list KB sources: %w", err)
+	}
+	if outputFormat != "table" {
+		return printOutput(data)
+	}
+
+	var resp struct {
+		Sources []kb.Source `json:"sources"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("parse sources: %w", err)
+	}
+
+	fmt.Println("👁  Watched Sources")
+	fmt.Println("────────────────────────────────────────────────────────")
+	if len(resp.Sources) == 0 {
+		fmt.Println("No knowledge base sources configured")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-40s %s\n", "SOURCE", "PATH", "WATCH")
+	for _, src := range resp.Sources {
+		fmt.Printf("%-20s %-40s %s\n", truncate(src.Name, 20), truncate(src.Path, 40), formatWatchStatus(src))
+		if src.WatchError != "" {
+			fmt.Printf("    %s\n", src.WatchError)
+		}
+	}
+	return nil
+}
+
+// formatWatchStatus renders whether a source's folder is being watched.
+func formatWatchStatus(src kb.Source) string {
+	switch {
+	case src.SyncMode != kb.SyncModeWatch:
+		return "○ off"
+	case src.Status != "active":
+		return "○ " + src.Status
+	case src.WatchStatus == kb.WatchStatusWatching:
+		return "✓ watching"
+	case src.WatchStatus == kb.WatchStatusFallback:
+		return "⚠️ fallback to scheduled sync"
+	}
+	return "⟳ starting"
+}
+
+// setKBSourceWatch switches a source between watch and manual sync mode.
+func setKBSourceWatch(c *client, sourceID string, enabled bool) error {
+	data, err := c.put("/api/v1/kb/sources/"+sourceID+"/watch", map[string]bool{"enabled": enabled})
+	if err != nil {
+		return fmt.Errorf("set watch mode: %w", err)
+	}
+	var resp struct {
+		kb.Source
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error.Message)
+	}
+	if outputFormat != "table" {
+		return printOutput(data)
+	}
+
+	switch {
+	case !enabled:
+		fmt.Printf("✓ %s is no longer watched; sync it with 'conduit kb sync'\n", resp.Name)
+	case resp.WatchStatus == kb.WatchStatusFallback:
+		fmt.Printf("⚠️  %s could not be watched: %s\n", resp.Name, resp.WatchError)
+		fmt.Println("   It is synced on the kb.schedule.interval instead")
+	default:
+		fmt.Printf("✓ Watching %s; changed files are re-indexed automatically\n", resp.Name)
+		if resp.LastSyncAttempt.IsZero() {
+			fmt.Printf("  Run 'conduit kb sync %s' once to index existing files\n", resp.SourceID)
+		}
+	}
+	return nil
+}
+
+// printSkippedFiles lists files a sync deliberately did not index.
+func printSkippedFiles(result map[string]interface{}) {
+	skipped, ok := result["skipped"].([]interface{})
+	if !ok || len(skipped) == 0 {
+		return
+	}
+
+	fmt.Printf("  Skipped: %d files\n", len(skipped))
+	for _, s := range skipped {
+		info, _ := s.(map[string]interface{})
+		size, _ := info["size"].(float64)
+		fmt.Printf("    - %s: %s (%s)\n", info["path"], info["reason"], formatBytes(int64(size)))
+	}
+	if limit, ok := result["max_file_size"].(float64); ok && limit > 0 {
+		fmt.Printf("  Files over %s are not indexed. Raise kb.max_file_size to include them.\n", formatBytes(int64(limit)))
+	}
+}
+
+func kbReindexCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "reindex <source-id>",
+		Short: "Rebuild a source's index from scratch",
+		Long: `Drop all chunks, FTS entries and vectors for a source and re-ingest
+every file from disk. The source configuration (path, patterns, excludes)
+is kept.
+
+Use this after chunking changes or when the index is corrupted. Unlike
+'kb sync', unchanged files are reprocessed too. The rebuild continues in the
+daemon even if this command is interrupted.
+
+Examples:
+  conduit kb reindex abc123-def456
+  conduit kb reindex abc123-def456 --json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeKBSourceIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sourceID := args[0]
+
+			// Use a longer timeout for reindex (30 minutes) - every file is re-embedded
+			c := newClientWithTimeout(socketPath, 30*time.Minute)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			if !jsonOutput {
+				fmt.Printf("Reindexing source: %s\n", sourceID)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go watchKBSyncProgress(ctx, sourceID, func(current, total int) {
+					// stderr keeps the final summary on stdout clean
+					fmt.Fprintf(os.Stderr, "\r  Processing: %d/%d files (%.0f%%)", current, total,
+						float64(current)/float64(total)*100)
+				})
+			}
+
+			data, err := c.post("/api/v1/kb/sources/"+sourceID+"/reindex", nil)
+			if err != nil {
+				return fmt.Errorf("reindex failed: %w", err)
+			}
+
+			var result map[string]interface{}
+			json.Unmarshal(data, &result)
+
+			if errData, ok := result["error"]; ok {
+				errMap := errData.(map[string]interface{})
+				return fmt.Errorf("%s", errMap["message"])
+			}
+
+			if jsonOutput {
+				jsonBytes, _ := json.MarshalIndent(result, "", "  ")
+				fmt.Println(string(jsonBytes))
+				return nil
+			}
+
+			var added int
+			if v, ok := result["added"].(float64); ok {
+				added = int(v)
+			}
+			fmt.Fprintln(os.Stderr)
+			fmt.Printf("✓ Reindex complete: %d documents indexed\n", added)
+			printSkippedFiles(result)
+
+			if semanticEnabled, ok := result["semantic_enabled"].(bool); ok {
+				if !semanticEnabled {
+					fmt.Printf("  Vectors: disabled (Qdrant/Ollama unavailable)\n")
+				} else if se, ok := result["semantic_errors"].(float64); ok && se > 0 {
+					fmt.Printf("  Vectors: ⚠️  %d documents failed (FTS5 fallback used)\n", int(se))
+				} else {
+					fmt.Printf("  Vectors: ✓ indexed\n")
+				}
+			}
+
+			if errors, ok := result["errors"].([]interface{}); ok && len(errors) > 0 {
+				fmt.Printf("  Errors:  %d\n", len(errors))
+				for _, e := range errors {
+					errInfo := e.(map[string]interface{})
+					fmt.Printf("    - %s: %s\n", errInfo["path"], errInfo["message"])
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the result as JSON (no progress)")
+
 	return cmd
 }
 
+// watchKBSyncProgress follows the daemon event stream and reports
+// kb_sync_progress events for one source until ctx is cancelled. Progress is
+// best effort: if the stream cannot be opened, nothing is reported.
+func watchKBSyncProgress(ctx context.Context, sourceID string, onProgress func(current, total int)) {
+	client := &http.Client{Transport: daemonTransport(socketPath)}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", daemonBaseURL()+"/api/v1/events", nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	var eventType string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "event: ") {
+			eventType = strings.TrimPrefix(line, "event: ")
+			continue
+		}
+		if !strings.HasPrefix(line, "data: ") || eventType != "kb_sync_progress" {
+			continue
+		}
+
+		var progress struct {
+			SourceID string `json:"source_id"`
+			Current  int    `json:"current"`
+			Total    int    `json:"total"`
+		}
+		if json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &progress) == nil &&
+			progress.SourceID == sourceID && progress.Total > 0 {
+			onProgress(progress.Current, progress.Total)
+		}
+	}
+}
+
 func kbMigrateCmd() *cobra.Command {
-	return &cobra.Command{
+	var statusOnly, restart bool
+
+	cmd := &cobra.Command{
 		Use:   "migrate",
 		Short: "Migrate FTS documents to vector search",
 		Long: `Migrate existing FTS5-indexed documents to the vector search index.
@@ -3385,11 +6333,30 @@ This is required to enable semantic search for documents that were indexed
 before semantic search was enabled. New documents are automatically indexed
 in both FTS5 and vector search.
 
+Progress is saved per document, so an interrupted migration (for example a
+daemon restart) resumes with the remaining documents when run again.
+
 Requires Qdrant and Ollama to be running.
 
 Examples:
-  conduit kb migrate`,
+  conduit kb migrate
+  conduit kb migrate --status
+  conduit kb migrate --restart`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			statusClient := newClient(socketPath)
+			if statusOnly {
+				status, err := fetchKBMigrateStatus(statusClient)
+				if err != nil {
+					return err
+				}
+				state := "idle"
+				if running, _ := status["running"].(bool); running {
+					state = "running"
+				}
+				fmt.Printf("Migration %s: %s\n", state, formatMigrateProgress(status))
+				return nil
+			}
+
 			// Use a longer timeout for migration (10 minutes)
 			c := newClientWithTimeout(socketPath, 10*time.Minute)
 
@@ -3397,34 +6364,203 @@ Examples:
 			fmt.Println("This may take a while for large knowledge bases.")
 			fmt.Println()
 
-			data, err := c.post("/api/v1/kb/migrate", nil)
-			if err != nil {
-				return fmt.Errorf("migration failed: %w", err)
+			path := "/api/v1/kb/migrate"
+			if restart {
+				path += "?restart=true"
+			}
+
+			type migrateResponse struct {
+				data []byte
+				err  error
+			}
+			done := make(chan migrateResponse, 1)
+			go func() {
+				data, err := c.post(path, nil)
+				done <- migrateResponse{data, err}
+			}()
+
+			// Show progress while the daemon works
+			ticker := time.NewTicker(2 * time.Second)
+			defer ticker.Stop()
+			var resp migrateResponse
+		wait:
+			for {
+				select {
+				case resp = <-done:
+					break wait
+				case <-ticker.C:
+					if status, err := fetchKBMigrateStatus(statusClient); err == nil {
+						fmt.Printf("\r  %s", formatMigrateProgress(status))
+					}
+				}
+			}
+			fmt.Print("\r\033[K")
+
+			if resp.err != nil {
+				return fmt.Errorf("migration failed: %w\nThe daemon keeps migrating in the background; check progress with 'conduit kb migrate --status'", resp.err)
 			}
 
 			var result map[string]interface{}
-			json.Unmarshal(data, &result)
+			json.Unmarshal(resp.data, &result)
 
 			if errData, ok := result["error"]; ok {
 				errMap := errData.(map[string]interface{})
 				return fmt.Errorf("%s", errMap["message"])
 			}
 
-			migratedVal, ok := result["migrated"]
-			if !ok || migratedVal == nil {
+			if _, ok := result["migrated"].(float64); !ok {
 				return fmt.Errorf("unexpected response: missing 'migrated' field")
 			}
-			migrated := int(migratedVal.(float64))
-			fmt.Printf("✓ Migration complete: %d documents migrated to vector search\n", migrated)
+			fmt.Printf("✓ Migration complete: %s\n", formatMigrateProgress(result))
+			if remaining, _ := result["remaining"].(float64); remaining > 0 {
+				fmt.Printf("  %d documents could not be migrated (see daemon logs); run 'conduit kb migrate' again to retry them\n", int(remaining))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&statusOnly, "status", false, "Show migration progress without starting a migration")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Re-embed all documents instead of resuming")
+
+	return cmd
+}
+
+// fetchKBMigrateStatus returns the daemon's migration progress.
+func fetchKBMigrateStatus(c *client) (map[string]interface{}, error) {
+	data, err := c.get("/api/v1/kb/migrate/status")
+	if err != nil {
+		return nil, fmt.Errorf("get migration status: %w", err)
+	}
+
+	var status map[string]interface{}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("parse migration status: %w", err)
+	}
+	if errData, ok := status["error"].(map[string]interface{}); ok {
+		return nil, fmt.Errorf("%s", errData["message"])
+	}
+	return status, nil
+}
+
+// formatMigrateProgress renders e.g. "migrated 4200/10000 documents".
+func formatMigrateProgress(status map[string]interface{}) string {
+	migrated, _ := status["migrated"].(float64)
+	total, _ := status["total"].(float64)
+	return fmt.Sprintf("migrated %d/%d documents", int(migrated), int(total))
+}
+
+// versionCmd shows the CLI and daemon versions and every Conduit
+// installation found, warning when they conflict
+func versionCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show version and installed copies of Conduit",
+		Long: `Show the version of this conduit binary and of the running daemon, and
+list every conduit and conduit-daemon binary found on PATH and where the
+install script, Homebrew, the desktop app and go install put them.
+
+Warns when several copies are installed, when their versions differ, or
+when more than one daemon service is registered.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			daemonVersion := ""
+			if data, err := newClientWithTimeout(socketPath, 2*time.Second).get("/api/v1/status"); err == nil {
+				var status struct {
+					Daemon struct {
+						Version string `json:"version"`
+					} `json:"daemon"`
+				}
+				if json.Unmarshal(data, &status) == nil {
+					daemonVersion = status.Daemon.Version
+				}
+			}
+
+			report := newInstaller(false).FindInstallations(cmd.Context())
+			if daemonVersion != "" && daemonVersion != Version {
+				report.Warnings = append(report.Warnings,
+					fmt.Sprintf("running daemon is version %s, this CLI is %s (restart it with: conduit service restart)", daemonVersion, Version))
+			}
 
+			if outputFormat != "table" {
+				return printOutput(map[string]interface{}{
+					"version":        Version,
+					"build_time":     BuildTime,
+					"daemon_version": daemonVersion,
+					"installations":  report,
+				})
+			}
+
+			fmt.Printf("conduit %s (built %s)\n", Version, BuildTime)
+			if daemonVersion != "" {
+				fmt.Printf("daemon  %s (running)\n", daemonVersion)
+			} else {
+				fmt.Println("daemon  not running")
+			}
+			fmt.Println()
+			printInstallationReport(report, true)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// printInstallationReport lists the Conduit binaries and daemon services
+// found, followed by any conflicts and how to clean them up. Without
+// listAll, only conflicts are listed in detail.
+func printInstallationReport(report *installer.InstallationReport, listAll bool) {
+	if listAll || report.Conflicting() {
+		for _, b := range report.Binaries {
+			marker := "  "
+			if b.Active {
+				marker = "★ " // What a bare command runs
+			}
+			version := b.Version
+			if version == "" {
+				version = "unknown version"
+			}
+			fmt.Printf("%s%-15s %-12s %s (%s)\n", marker, b.Name, version, b.Path, b.Source)
+			if b.Target != "" {
+				fmt.Printf("   %-15s → %s\n", "", b.Target)
+			}
+		}
+		for _, s := range report.Services {
+			if s.Path != "" {
+				fmt.Printf("  service         %s (%s)\n", s.Name, s.Path)
+			} else {
+				fmt.Printf("  service         %s\n", s.Name)
+			}
+		}
+	}
+
+	if !report.Conflicting() {
+		if listAll {
+			fmt.Println()
+		}
+		fmt.Println("✓ No conflicting installations")
+		return
+	}
+
+	fmt.Println()
+	for _, w := range report.Warnings {
+		fmt.Printf("⚠️  %s\n", w)
+	}
+	fmt.Println("   Keep one installation and remove the others:")
+	for _, hint := range report.CleanupHints() {
+		fmt.Printf("   - %s\n", hint)
+	}
 }
 
 // doctorCmd diagnoses issues
 func doctorCmd() *cobra.Command {
 	var verbose bool
+	var fix bool
+	var assumeYes bool
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "doctor",
@@ -3432,6 +6568,7 @@ func doctorCmd() *cobra.Command {
 		Long: `Run comprehensive diagnostics on the Conduit installation.
 
 Checks:
+  - Conflicting installations (several binaries, versions or services)
   - Daemon connectivity and health
   - Container runtime availability (Podman/Docker)
   - Database accessibility
@@ -3439,7 +6576,20 @@ Checks:
   - Semantic search (Qdrant vector database + embeddings)
   - Client configurations
   - Knowledge base status
-  - Document extraction tools (PDF, DOC, RTF, DOCX, ODT)`,
+  - Document extraction tools (PDF, DOC, RTF, DOCX, ODT)
+
+With --fix, doctor offers to repair what it can after the checks: start the
+daemon if it is installed but stopped, start the Podman machine, start
+Ollama, recreate a missing Qdrant collection and remove a stale daemon
+socket. Each repair is confirmed first; --yes confirms them all, except
+repairs that remove files, which are always confirmed. --dry-run lists the
+repairs --fix would offer without applying any.
+
+Examples:
+  conduit doctor
+  conduit doctor --fix
+  conduit doctor --fix --dry-run
+  conduit doctor --fix --yes`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fmt.Println("╔══════════════════════════════════════════════════════════════╗")
 			fmt.Println("║                   Conduit Diagnostics                        ║")
@@ -3448,6 +6598,7 @@ Checks:
 
 			issues := 0
 			warnings := 0
+			var fixes []doctorFix // Repairs offered with --fix
 
 			// Load configuration
 			cfg, cfgErr := config.Load()
@@ -3463,6 +6614,15 @@ Checks:
 				}
 			}
 
+			// Check for several installations fighting over PATH and the service
+			fmt.Println()
+			fmt.Println("📦 Installations")
+			fmt.Println("────────────────────────────────────────────────────────")
+
+			installs := newInstaller(false).FindInstallations(cmd.Context())
+			printInstallationReport(installs, verbose)
+			warnings += len(installs.Warnings)
+
 			// Check daemon connectivity
 			fmt.Println()
 			fmt.Println("📡 Daemon Status")
@@ -3476,16 +6636,34 @@ Checks:
 				fmt.Printf("   Socket: %s\n", socketPath)
 				fmt.Println("   Try: conduit service start")
 				issues++
+				if isStaleSocket(socketPath) {
+					fmt.Println("   Stale socket left by a previous daemon")
+					fixes = append(fixes, staleSocketFix(socketPath))
+				}
+				if startFix, ok := startDaemonFix(); ok {
+					fixes = append(fixes, startFix)
+				}
 			} else {
 				var health map[string]interface{}
 				json.Unmarshal(healthData, &health)
 
-				if health["status"] == "healthy" {
+				switch health["status"] {
+				case "healthy":
 					fmt.Println("✓ Daemon is running and healthy")
-				} else {
+				case "degraded":
+					fmt.Println("⚠️  Daemon is running but degraded (semantic search may be unavailable)")
+					warnings++
+				default:
 					fmt.Println("⚠️  Daemon is running but unhealthy")
 					warnings++
 				}
+				if checks, ok := health["checks"].(map[string]interface{}); ok {
+					for _, name := range []string{"database", "fts5", "qdrant", "ollama"} {
+						if result, ok := checks[name].(string); ok && result != "ok" {
+							fmt.Printf("   %s: %s\n", name, result)
+						}
+					}
+				}
 
 				// Get status info (with dependencies)
 				statusData, _ := c.get("/api/v1/status")
@@ -3553,6 +6731,15 @@ Checks:
 				fmt.Printf("   Managed container: %s\n", daemonContainer)
 			}
 
+			if len(runtimes) > 0 && !runtimes[0].Available {
+				if podmanPath := findBinaryPath("podman"); podmanMachineStopped(ctx, podmanPath) {
+					fmt.Println("⚠️  Podman machine is not running")
+					fmt.Println("   Start with: podman machine start")
+					warnings++
+					fixes = append(fixes, podmanMachineFix(podmanPath))
+				}
+			}
+
 			if !anyAvailable {
 				fmt.Println("❌ No container runtime available")
 				fmt.Println("   Install Podman or Docker to run MCP servers")
@@ -3578,6 +6765,16 @@ Checks:
 				}
 			}
 
+			// FTS5 is compiled into the SQLite driver; without it no
+			// database can be opened, so check this binary directly
+			if store.CheckFTS5(ctx) != nil {
+				fmt.Println("❌ FTS5: unavailable in this conduit binary")
+				fmt.Println("   Rebuild with: go build -tags fts5 ./cmd/conduit ./cmd/conduit-daemon")
+				issues++
+			} else {
+				fmt.Println("✓ FTS5: available")
+			}
+
 			// Check AI provider
 			fmt.Println()
 			fmt.Println("🤖 AI Provider")
@@ -3607,6 +6804,7 @@ Checks:
 							fmt.Println("⚠️  Ollama is installed but not running")
 							fmt.Println("   Start with: ollama serve")
 							warnings++
+							fixes = append(fixes, ollamaFix())
 						} else {
 							fmt.Println("❌ Ollama not installed")
 							fmt.Println("   Install from: https://ollama.ai")
@@ -3670,6 +6868,7 @@ Checks:
 					fmt.Printf("   Collection: conduit_kb (%d vectors)\n", count)
 				} else {
 					fmt.Println("   Collection: not yet created (run 'conduit kb sync')")
+					fixes = append(fixes, qdrantCollectionFix())
 				}
 				if daemonContainer != "" {
 					fmt.Println("   Managed by: Conduit (auto-started)")
@@ -3783,8 +6982,8 @@ Checks:
 					db.DB().QueryRow(`
 						SELECT COUNT(*) FROM kb_chunks c
 						LEFT JOIN kb_extraction_status s ON c.chunk_id = s.chunk_id
-						WHERE s.status IS NULL
-					`).Scan(&pending)
+						WHERE s.status IS NULL AND ` + kb.ExtractableChunkFilter,
+					).Scan(&pending)
 
 					fmt.Printf("   Entities:  %d\n", entityCount)
 					fmt.Printf("   Relations: %d\n", relationCount)
@@ -3813,7 +7012,7 @@ Checks:
 				{"Claude Code", filepath.Join(homeDir, ".claude.json")},
 				{"Cursor", filepath.Join(homeDir, ".cursor", "mcp.json")},
 				{"VS Code", filepath.Join(homeDir, ".vscode", "mcp.json")},
-				{"Gemini CLI", filepath.Join(homeDir, ".gemini", "mcp.json")},
+				{"Gemini CLI", filepath.Join(homeDir, ".gemini", "settings.json")},
 			}
 
 			for _, client := range clients {
@@ -3887,11 +7086,32 @@ Checks:
 				fmt.Println("   Fix the issues above and run 'conduit doctor' again.")
 			}
 
+			if len(fixes) > 0 && !fix && !dryRun {
+				fmt.Printf("   %d can be repaired automatically: conduit doctor --fix\n", len(fixes))
+			}
+			if fix || dryRun {
+				if len(fixes) == 0 {
+					fmt.Println()
+					fmt.Println("No automatic repairs available.")
+					return nil
+				}
+				applied := runDoctorFixes(ctx, fixes, assumeYes, dryRun)
+				fmt.Println()
+				if dryRun {
+					fmt.Printf("%d repair(s) available. Run 'conduit doctor --fix' to apply them.\n", len(fixes))
+					return nil
+				}
+				fmt.Printf("Applied %d of %d repair(s). Run 'conduit doctor' again to check.\n", applied, len(fixes))
+			}
+
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show detailed information")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Offer to repair failing checks")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Apply repairs without asking (files are never removed without asking)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the repairs --fix would offer without applying them")
 
 	return cmd
 }
@@ -4067,6 +7287,9 @@ Example usage in AI client config:
 				return fmt.Errorf("instance has no image reference")
 			}
 
+			// Run the image for the platform it was built for
+			platform, _ := instance["platform"].(string)
+
 			// Get configuration
 			cfg, err := config.Load()
 			if err != nil {
@@ -4092,17 +7315,28 @@ Example usage in AI client config:
 				Network: containerRuntime.NetworkSpec{
 					Mode: "none", // No network by default for security
 				},
+				Platform: platform,
 			}
 
-			// Apply any instance-specific config
-			if envMap, ok := instance["env"].(map[string]interface{}); ok {
-				spec.Env = make(map[string]string)
-				for k, v := range envMap {
+			// Build the environment as a daemon start would: granted secrets
+			// and the instance config with its secret references resolved
+			instanceConfig := make(map[string]string)
+			if configMap, ok := instance["config"].(map[string]interface{}); ok {
+				for k, v := range configMap {
 					if str, ok := v.(string); ok {
-						spec.Env[k] = str
+						instanceConfig[k] = str
 					}
 				}
 			}
+			var perms policy.EffectivePermissions
+			if err := getDaemonJSON(c, "/api/v1/policy/instances/"+instanceID, &perms); err != nil {
+				return fmt.Errorf("get permissions: %w", err)
+			}
+			secretStore := secrets.NewStore(cfg.SecretsPath(), cfg.KeyPath())
+			spec.Env, err = lifecycle.ResolveEnv(instanceID, instanceConfig, perms.Effective.Secrets, secretStore, cliLogger)
+			if err != nil {
+				return err
+			}
 
 			// Add instance labels
 			spec.Labels = map[string]string{
@@ -4124,14 +7358,31 @@ Example usage in AI client config:
 
 // mcpKBCmd runs the KB MCP server
 func mcpKBCmd() *cobra.Command {
-	return &cobra.Command{
+	var direct bool
+	var useHTTP bool
+	var addr string
+	var requireToken bool
+
+	cmd := &cobra.Command{
 		Use:   "kb",
 		Short: "Run Knowledge Base MCP server",
-		Long: `Run the Knowledge Base MCP server over stdio.
+		Long: `Run the Knowledge Base MCP server over stdio, or over HTTP with --http.
 
 This server provides search and document retrieval tools for AI clients
 to access your private knowledge base.
 
+When the daemon is running, requests are forwarded to it over the socket so
+the database is only opened by the daemon and search uses its configuration.
+If the daemon is not running, the database is opened directly. Use --direct
+to always open the database directly.
+
+With --http the same tools are served on --addr using the MCP HTTP+SSE
+transport: clients open an event stream at /sse and post messages to the
+endpoint it announces. Single JSON requests can also be posted to /mcp.
+--addr must be a loopback address, and requests whose Host or Origin is not
+localhost are refused. --require-token also requires the daemon's API token
+(see 'conduit setup') as "Authorization: Bearer <token>".
+
 Example MCP client configuration:
 {
   "mcpServers": {
@@ -4140,50 +7391,25 @@ Example MCP client configuration:
       "args": ["mcp", "kb"]
     }
   }
-}`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// Open database
-			homeDir, _ := os.UserHomeDir()
-			dataDir := filepath.Join(homeDir, ".conduit")
-			dbPath := filepath.Join(dataDir, "conduit.db")
-
-			st, err := store.New(dbPath)
-			if err != nil {
-				return fmt.Errorf("open database: %w", err)
-			}
-			defer st.Close()
+}
 
-			// Create FTS5 searcher
-			ftsSearcher := kb.NewSearcher(st.DB())
-
-			// Attempt to create semantic searcher (if Qdrant/Ollama available)
-			var semanticSearcher *kb.SemanticSearcher
-			semanticCfg := kb.SemanticSearchConfig{
-				EmbeddingConfig: kb.EmbeddingConfig{
-					OllamaHost: "http://localhost:11434",
-					Model:      "nomic-embed-text",
-					Dimension:  768,
-					BatchSize:  10,
-				},
-				VectorStoreConfig: kb.VectorStoreConfig{
-					Host:           "localhost",
-					Port:           6334, // gRPC port
-					CollectionName: "conduit_kb",
-					Dimension:      768,
-					BatchSize:      100,
-				},
+HTTP clients:
+  conduit mcp kb --http --addr localhost:8091
+  {"mcpServers": {"conduit-kb": {"url": "http://localhost:8091/sse"}}}`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var token string
+			if useHTTP {
+				if err := kb.ValidateMCPHTTPAddr(addr); err != nil {
+					return fmt.Errorf("--addr: %w", err)
+				}
+				if requireToken {
+					var err error
+					if token, err = resolveAPIToken(); err != nil {
+						return err
+					}
+				}
 			}
 
-			// Try to create semantic searcher - if it fails, we fall back to FTS5 only
-			semanticSearcher, _ = kb.NewSemanticSearcher(st.DB(), semanticCfg)
-			// Error is ignored - hybrid searcher works with nil semantic searcher
-
-			// Create hybrid searcher (combines FTS5 + semantic when available)
-			hybridSearcher := kb.NewHybridSearcher(ftsSearcher, semanticSearcher)
-
-			// Create and run MCP server with hybrid searcher
-			server := kb.NewMCPServer(st.DB(), hybridSearcher)
-
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
@@ -4195,9 +7421,197 @@ Example MCP client configuration:
 				cancel()
 			}()
 
-			return server.Run(ctx)
+			var handle kb.MCPHandler
+			if !direct {
+				// Tool calls can run a semantic or graph search, so allow
+				// longer than the default client timeout
+				c := newClientWithTimeout(socketPath, 2*time.Minute)
+				if c.ready() {
+					var closeFallback func()
+					handle, closeFallback = daemonKBMCPHandler(c)
+					defer closeFallback()
+				}
+			}
+			if handle == nil {
+				server, closeServer, err := newDirectKBMCPServer()
+				if err != nil {
+					return err
+				}
+				defer closeServer()
+
+				if !useHTTP {
+					return server.Run(ctx)
+				}
+				handle = func(ctx context.Context, req *kb.MCPRequest) (*kb.MCPResponse, error) {
+					return server.HandleRequest(ctx, req), nil
+				}
+			}
+
+			if !useHTTP {
+				return kb.ServeMCP(ctx, os.Stdin, os.Stdout, handle)
+			}
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("listen on %s: %w", addr, err)
+			}
+			fmt.Fprintf(os.Stderr, "KB MCP server listening on http://%s%s (Ctrl+C to stop)\n", ln.Addr(), kb.MCPSSEPath)
+			httpServer := kb.NewMCPHTTPServer(handle)
+			if token != "" {
+				httpServer.RequireToken(token)
+			}
+			return httpServer.Serve(ctx, ln)
+		},
+	}
+
+	cmd.Flags().BoolVar(&direct, "direct", false, "Open the database directly instead of using the daemon")
+	cmd.Flags().BoolVar(&useHTTP, "http", false, "Serve over the MCP HTTP+SSE transport instead of stdio")
+	cmd.Flags().StringVar(&addr, "addr", "localhost:8091", "Loopback address to listen on with --http")
+	cmd.Flags().BoolVar(&requireToken, "require-token", false, "With --http, require the API token as a bearer token")
+
+	return cmd
+}
+
+// ready reports whether the daemon is up and ready, without waiting
+func (c *client) ready() bool {
+	resp, err := c.httpClient.Get(c.baseURL + "/api/v1/ready")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// daemonKBMCPHandler returns a handler that forwards each MCP request to the
+// daemon. If the daemon goes away mid-session, the remaining requests are
+// served from the database directly. The returned func closes that database.
+// The HTTP transport calls the handler concurrently, so the switch is locked.
+func daemonKBMCPHandler(c *client) (kb.MCPHandler, func()) {
+	var mu sync.Mutex
+	var fallback *kb.MCPServer
+	closeFallback := func() {}
+
+	handle := func(ctx context.Context, req *kb.MCPRequest) (*kb.MCPResponse, error) {
+		mu.Lock()
+		server := fallback
+		mu.Unlock()
+
+		if server == nil {
+			resp, err := c.proxyKBMCP(req)
+			if err == nil {
+				return resp, nil
+			}
+
+			mu.Lock()
+			if fallback == nil {
+				// stdout may carry the MCP protocol, so diagnostics go to stderr
+				fmt.Fprintf(os.Stderr, "conduit daemon unavailable (%v), opening the database directly\n", err)
+				direct, closeDirect, err := newDirectKBMCPServer()
+				if err != nil {
+					mu.Unlock()
+					return nil, err
+				}
+				fallback, closeFallback = direct, closeDirect
+			}
+			server = fallback
+			mu.Unlock()
+		}
+		return server.HandleRequest(ctx, req), nil
+	}
+
+	return handle, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		closeFallback()
+	}
+}
+
+// proxyKBMCP sends one MCP request to the daemon. An error means the daemon
+// could not be reached; errors reported by the daemon become MCP errors.
+func (c *client) proxyKBMCP(req *kb.MCPRequest) (*kb.MCPResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.baseURL+"/api/v1/kb/mcp", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil, nil
+	case http.StatusNotFound:
+		// Daemon predates the MCP endpoint
+		return nil, fmt.Errorf("daemon does not serve KB MCP requests")
+	case http.StatusOK:
+		var mcpResp kb.MCPResponse
+		if err := json.Unmarshal(body, &mcpResp); err != nil {
+			return nil, fmt.Errorf("decode daemon response: %w", err)
+		}
+		return &mcpResp, nil
+	default:
+		if req.ID == nil {
+			return nil, nil
+		}
+		return kb.NewMCPResponse(req.ID, nil, fmt.Errorf("daemon: %s", daemonErrorMessage(body))), nil
+	}
+}
+
+// newDirectKBMCPServer opens the database and builds a KB MCP server on it.
+// The returned func closes the database.
+func newDirectKBMCPServer() (*kb.MCPServer, func(), error) {
+	homeDir, _ := os.UserHomeDir()
+	dataDir := filepath.Join(homeDir, ".conduit")
+	dbPath := filepath.Join(dataDir, "conduit.db")
+
+	// The daemon may have the database open too; use the configured busy
+	// timeout so writes wait for its lock instead of failing
+	storeOpts := store.Options{}
+	if cfg, err := config.Load(); err == nil {
+		storeOpts.BusyTimeout = cfg.Database.BusyTimeout
+	}
+	st, err := store.NewWithOptions(dbPath, storeOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open database: %w", err)
+	}
+
+	// Create FTS5 searcher
+	ftsSearcher := kb.NewSearcher(st.DB())
+
+	// Attempt to create semantic searcher (if Qdrant/Ollama available)
+	var semanticSearcher *kb.SemanticSearcher
+	semanticCfg := kb.SemanticSearchConfig{
+		EmbeddingConfig: kb.EmbeddingConfig{
+			OllamaHost: "http://localhost:11434",
+			Model:      "nomic-embed-text",
+			Dimension:  768,
+			BatchSize:  10,
+		},
+		VectorStoreConfig: kb.VectorStoreConfig{
+			Host:           "localhost",
+			Port:           6334, // gRPC port
+			CollectionName: "conduit_kb",
+			Dimension:      768,
+			BatchSize:      100,
 		},
 	}
+
+	// Try to create semantic searcher - if it fails, we fall back to FTS5 only
+	semanticSearcher, _ = kb.NewSemanticSearcher(st.DB(), semanticCfg)
+	// Error is ignored - hybrid searcher works with nil semantic searcher
+
+	// Create hybrid searcher (combines FTS5 + semantic when available)
+	hybridSearcher := kb.NewHybridSearcher(ftsSearcher, semanticSearcher)
+
+	return kb.NewMCPServer(st.DB(), hybridSearcher), func() { st.Close() }, nil
 }
 
 // mcpStatusCmd shows MCP server status and capabilities
@@ -4505,15 +7919,17 @@ func logsCmd() *cobra.Command {
 		Short: "Show connector instance logs",
 		Long: `Display logs from a connector instance.
 
-Shows both container runtime logs and stored application logs.
-Use --follow to stream logs in real-time.
+Shows container logs (fetched through the daemon's runtime provider) and
+stored application logs. Logs of a stopped instance are shown as long as its
+container still exists. Use --follow to stream logs in real-time.
 
 Examples:
   conduit logs my-server
   conduit logs abc123 --tail 100
   conduit logs abc123 --follow
   conduit logs abc123 --since 1h`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeInstanceIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			instanceID := args[0]
 
@@ -4521,51 +7937,71 @@ Examples:
 			c := newClient(socketPath)
 			data, err := c.get("/api/v1/instances/" + instanceID)
 			if err != nil {
-				return fmt.Errorf("instance not found: %w", err)
+				return fmt.Errorf("failed to connect to daemon: %w", err)
 			}
 
 			var instance map[string]interface{}
 			json.Unmarshal(data, &instance)
+			if errObj, ok := instance["error"].(map[string]interface{}); ok {
+				return fmt.Errorf("%v", errObj["message"])
+			}
 
-			// Check if instance has a container ID
-			containerID, hasContainer := instance["container_id"].(string)
-			status := instance["status"].(string)
+			status, _ := instance["status"].(string)
 
 			fmt.Printf("Logs for instance: %s (status: %s)\n", instanceID, status)
 			fmt.Println("═══════════════════════════════════════════════════════")
+			fmt.Println("\n📦 Container Logs:")
+			fmt.Println("───────────────────────────────────────────────────────")
+
+			query := url.Values{}
+			if tail > 0 {
+				query.Set("tail", strconv.Itoa(tail))
+			}
+			if since != "" {
+				query.Set("since", since)
+			}
+			logsPath := "/api/v1/instances/" + instanceID + "/logs"
+
+			if follow {
+				// Stream until interrupted - no client timeout for followed logs
+				query.Set("follow", "true")
+				ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+
+				streamClient := newClientWithTimeout(socketPath, 0)
+				req, _ := http.NewRequestWithContext(ctx, http.MethodGet, streamClient.baseURL+logsPath+"?"+query.Encode(), nil)
+				resp, err := streamClient.httpClient.Do(req)
+				if err != nil {
+					return fmt.Errorf("failed to stream logs: %w", err)
+				}
+				defer resp.Body.Close()
 
-			// If container is running, get container logs
-			if hasContainer && containerID != "" && (status == "running" || status == "stopped") {
-				fmt.Println("\n📦 Container Logs:")
-				fmt.Println("───────────────────────────────────────────────────────")
+				if resp.StatusCode != http.StatusOK {
+					body, _ := io.ReadAll(resp.Body)
+					fmt.Printf("  (%s)\n", daemonErrorMessage(body))
+					return nil
+				}
 
-				// Build log command
-				runtime := detectContainerRuntime()
-				if runtime == "" {
-					fmt.Println("  (No container runtime detected)")
-				} else {
-					logArgs := []string{"logs"}
-					if tail > 0 {
-						logArgs = append(logArgs, "--tail", strconv.Itoa(tail))
-					}
-					if since != "" {
-						logArgs = append(logArgs, "--since", since)
-					}
-					if follow {
-						logArgs = append(logArgs, "-f")
-					}
-					logArgs = append(logArgs, containerID)
+				io.Copy(os.Stdout, resp.Body)
+				return nil
+			}
 
-					logCmd := exec.CommandContext(cmd.Context(), runtime, logArgs...)
-					logCmd.Stdout = os.Stdout
-					logCmd.Stderr = os.Stderr
+			logsData, err := c.get(logsPath + "?" + query.Encode())
+			if err != nil {
+				return fmt.Errorf("failed to get logs: %w", err)
+			}
 
-					if err := logCmd.Run(); err != nil {
-						fmt.Printf("  (Container logs unavailable: %v)\n", err)
-					}
-				}
+			var logsResp map[string]interface{}
+			json.Unmarshal(logsData, &logsResp)
+			if _, ok := logsResp["error"]; ok {
+				fmt.Printf("  (%s)\n", daemonErrorMessage(logsData))
+			} else if logs, _ := logsResp["logs"].(string); logs == "" {
+				fmt.Println("  (No log output)")
 			} else {
-				fmt.Println("\n  (No container running for this instance)")
+				fmt.Print(logs)
+				if !strings.HasSuffix(logs, "\n") {
+					fmt.Println()
+				}
 			}
 
 			// Show stored logs from data directory
@@ -4600,7 +8036,7 @@ Examples:
 
 	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow log output")
 	cmd.Flags().IntVarP(&tail, "tail", "n", 0, "Number of lines to show from the end")
-	cmd.Flags().StringVar(&since, "since", "", "Show logs since timestamp (e.g., '1h', '2024-01-01')")
+	cmd.Flags().StringVar(&since, "since", "", "Show logs since duration, date or RFC3339 timestamp (e.g., '1h', '2024-01-01', '2024-01-01T00:00:00Z')")
 
 	return cmd
 }
@@ -4665,17 +8101,6 @@ func getEmptyAuthFile() string {
 	return authFile
 }
 
-// detectContainerRuntime finds the available container runtime
-func detectContainerRuntime() string {
-	if findBinaryPath("podman") != "" {
-		return "podman"
-	}
-	if findBinaryPath("docker") != "" {
-		return "docker"
-	}
-	return ""
-}
-
 // readLastNLines reads the last N lines from a file
 func readLastNLines(f *os.File, n int) []string {
 	scanner := bufio.NewScanner(f)
@@ -4691,6 +8116,19 @@ func readLastNLines(f *os.File, n int) []string {
 	return lines
 }
 
+// daemonErrorMessage extracts the message from a daemon error response body.
+func daemonErrorMessage(body []byte) string {
+	var resp struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error.Message == "" {
+		return strings.TrimSpace(string(body))
+	}
+	return resp.Error.Message
+}
+
 // configCmd shows configuration
 func configCmd() *cobra.Command {
 	var showAll bool
@@ -4740,13 +8178,19 @@ Examples:
 			fmt.Printf("  Pull Timeout:    %s\n", cfg.Runtime.PullTimeout)
 			fmt.Printf("  Start Timeout:   %s\n", cfg.Runtime.StartTimeout)
 			fmt.Printf("  Stop Timeout:    %s\n", cfg.Runtime.StopTimeout)
+			if cfg.Runtime.AutoRestart.Enabled {
+				fmt.Printf("  Auto-Restart:    after %s degraded, up to %d attempts\n",
+					cfg.Runtime.AutoRestart.DegradedThreshold, cfg.Runtime.AutoRestart.MaxAttempts)
+			} else {
+				fmt.Println("  Auto-Restart:    disabled")
+			}
 
 			if showAll {
 				fmt.Println("\n📚 Knowledge Base:")
 				fmt.Printf("  Workers:         %d\n", cfg.KB.Workers)
 				fmt.Printf("  Max File Size:   %s\n", formatBytes(cfg.KB.MaxFileSize))
-				fmt.Printf("  Chunk Size:      %d\n", cfg.KB.ChunkSize)
-				fmt.Printf("  Chunk Overlap:   %d\n", cfg.KB.ChunkOverlap)
+				fmt.Printf("  Chunk Size:      %d\n", cfg.KB.RAG.ChunkSize)
+				fmt.Printf("  Chunk Overlap:   %d\n", cfg.KB.RAG.ChunkOverlap)
 
 				fmt.Println("\n🔒 Policy:")
 				fmt.Printf("  Network Egress:  %v\n", cfg.Policy.AllowNetworkEgress)
@@ -5006,7 +8450,7 @@ The backup is saved as a compressed tar.gz archive.
 
 Examples:
   conduit backup
-  conduit backup --output ~/backups/conduit-backup.tar.gz`,
+  conduit backup --file ~/backups/conduit-backup.tar.gz`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load()
 			if err != nil {
@@ -5081,14 +8525,500 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output path for backup file")
+	cmd.Flags().StringVarP(&outputPath, "file", "f", "", "Path for the backup file")
+
+	return cmd
+}
+
+// exportFormatVersion is the version of the file written by "conduit export"
+const exportFormatVersion = 1
+
+// conduitExport is the portable configuration written by "conduit export"
+type conduitExport struct {
+	Version        int                   `json:"version"`
+	ConduitVersion string                `json:"conduit_version"`
+	ExportedAt     time.Time             `json:"exported_at"`
+	Sources        []kb.AddSourceRequest `json:"kb_sources"`
+	Instances      []exportedInstance    `json:"instances"`
+	Bindings       []exportedBinding     `json:"bindings"`
+}
+
+// exportedInstance is a connector instance without its container state. The
+// instance ID is the one on the exporting machine and only links bindings.
+type exportedInstance struct {
+	InstanceID     string                 `json:"instance_id"`
+	PackageID      string                 `json:"package_id"`
+	PackageVersion string                 `json:"package_version"`
+	DisplayName    string                 `json:"display_name"`
+	ImageRef       string                 `json:"image_ref"`
+	Platform       string                 `json:"platform,omitempty"`
+	Config         map[string]string      `json:"config,omitempty"`
+	Resources      *models.ResourceLimits `json:"resources,omitempty"`
+	Declared       *policy.PermissionSet  `json:"declared_permissions,omitempty"`
+	Granted        policy.PermissionSet   `json:"granted_permissions"`
+}
+
+// exportedBinding is a client binding. ProjectPath is set for project and
+// workspace scopes so the config can be written to the same project.
+type exportedBinding struct {
+	InstanceID  string `json:"instance_id"`
+	ClientID    string `json:"client_id"`
+	Scope       string `json:"scope"`
+	ProjectPath string `json:"project_path,omitempty"`
+}
+
+// exportCmd writes sources, instances, bindings and grants to a file
+func exportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export Conduit configuration to a file",
+		Long: `Export Conduit configuration to a portable JSON file.
+
+The export includes:
+  - Knowledge base sources (paths, patterns, excludes, sync mode)
+  - Connector instances (package, image, platform, config, resources; no
+    container state)
+  - Permission grants for each instance
+  - Client bindings
+
+Documents, indexes and containers are not exported: 'conduit import' re-syncs
+sources and reinstalls instances on the new machine. Instance config may hold
+credentials, so the file is only readable by you.
+
+Examples:
+  conduit export conduit-config.json
+  conduit import conduit-config.json   # on the new machine`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			export, err := collectExport(c)
+			if err != nil {
+				return err
+			}
+
+			data, err := json.MarshalIndent(export, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encode export: %w", err)
+			}
+			if err := os.WriteFile(args[0], append(data, '\n'), 0600); err != nil {
+				return fmt.Errorf("write export: %w", err)
+			}
+
+			fmt.Printf("✓ Exported configuration to %s\n", args[0])
+			fmt.Printf("  KB sources: %d\n", len(export.Sources))
+			fmt.Printf("  Instances:  %d\n", len(export.Instances))
+			fmt.Printf("  Bindings:   %d\n", len(export.Bindings))
+			return nil
+		},
+	}
+}
+
+// collectExport reads the exportable configuration from the daemon
+func collectExport(c *client) (*conduitExport, error) {
+	export := &conduitExport{
+		Version:        exportFormatVersion,
+		ConduitVersion: Version,
+		ExportedAt:     time.Now().UTC(),
+		Sources:        []kb.AddSourceRequest{},
+		Instances:      []exportedInstance{},
+		Bindings:       []exportedBinding{},
+	}
+
+	var sources struct {
+		Sources []kb.Source `json:"sources"`
+	}
+	if err := getDaemonJSON(c, "/api/v1/kb/sources", &sources); err != nil {
+		return nil, fmt.Errorf("list KB sources: %w", err)
+	}
+	for _, s := range sources.Sources {
+		extract := s.ExtractEntities
+		export.Sources = append(export.Sources, kb.AddSourceRequest{
+			Path:            s.Path,
+			Name:            s.Name,
+			Patterns:        s.Patterns,
+			Excludes:        s.Excludes,
+			SyncMode:        s.SyncMode,
+			ExtractEntities: &extract,
+		})
+	}
+
+	var instances struct {
+		Instances []models.ConnectorInstance `json:"instances"`
+	}
+	if err := getDaemonJSON(c, "/api/v1/instances", &instances); err != nil {
+		return nil, fmt.Errorf("list instances: %w", err)
+	}
+	for _, inst := range instances.Instances {
+		var perms policy.EffectivePermissions
+		if err := getDaemonJSON(c, "/api/v1/policy/instances/"+inst.InstanceID, &perms); err != nil {
+			return nil, fmt.Errorf("get permissions for %s: %w", inst.InstanceID, err)
+		}
+		export.Instances = append(export.Instances, exportedInstance{
+			InstanceID:     inst.InstanceID,
+			PackageID:      inst.PackageID,
+			PackageVersion: inst.PackageVersion,
+			DisplayName:    inst.DisplayName,
+			ImageRef:       inst.ImageRef,
+			Platform:       inst.Platform,
+			Config:         inst.Config,
+			Resources:      inst.Resources,
+			Declared:       perms.Declared,
+			Granted:        perms.Granted,
+		})
+	}
+
+	var bindings struct {
+		Bindings []models.ClientBinding `json:"bindings"`
+	}
+	if err := getDaemonJSON(c, "/api/v1/bindings", &bindings); err != nil {
+		return nil, fmt.Errorf("list bindings: %w", err)
+	}
+	for _, b := range bindings.Bindings {
+		exported := exportedBinding{
+			InstanceID: b.InstanceID,
+			ClientID:   b.ClientID,
+			Scope:      b.Scope,
+		}
+		if b.Scope != "user" && b.ConfigPath != "" {
+			exported.ProjectPath = bindingProjectPath(b.ConfigPath)
+		}
+		export.Bindings = append(export.Bindings, exported)
+	}
+
+	return export, nil
+}
+
+// bindingProjectPath returns the project directory a project or workspace
+// config file belongs to, e.g. /work/app for /work/app/.vscode/mcp.json.
+func bindingProjectPath(configPath string) string {
+	dir := filepath.Dir(configPath)
+	switch filepath.Base(dir) {
+	case ".vscode", ".cursor", ".gemini":
+		return filepath.Dir(dir)
+	}
+	return dir
+}
+
+// getDaemonJSON fetches path from the daemon and decodes it into v, turning
+// daemon error responses into errors.
+func getDaemonJSON(c *client, path string, v interface{}) error {
+	data, err := c.get(path)
+	if err != nil {
+		return err
+	}
+
+	var errResp struct {
+		Error interface{} `json:"error"`
+	}
+	if json.Unmarshal(data, &errResp) == nil && errResp.Error != nil {
+		return fmt.Errorf("%s", daemonErrorMessage(data))
+	}
+	return json.Unmarshal(data, v)
+}
+
+// postDaemonJSON posts body to the daemon and decodes the response into v
+// (if not nil), turning daemon error responses into errors.
+func postDaemonJSON(c *client, path string, body, v interface{}) error {
+	data, err := c.post(path, body)
+	if err != nil {
+		return err
+	}
+
+	var errResp struct {
+		Error interface{} `json:"error"`
+	}
+	if json.Unmarshal(data, &errResp) == nil && errResp.Error != nil {
+		return fmt.Errorf("%s", daemonErrorMessage(data))
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// importCmd recreates configuration from a file written by "conduit export"
+func importCmd() *cobra.Command {
+	var noSync bool
+	var noInstall bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import Conduit configuration from an export file",
+		Long: `Recreate configuration exported with 'conduit export' through the daemon.
+
+  - KB sources are added and synced from their paths. Sources whose path
+    does not exist on this machine, or that are already added, are skipped.
+  - Connector instances are created with their permission grants and
+    installed. Instances with the same package and name are reused.
+  - Bindings are re-applied to clients detected on this machine, once their
+    instance is installed.
+
+Existing configuration is never removed.
+
+Examples:
+  conduit import conduit-config.json
+  conduit import conduit-config.json --no-sync      # add sources without indexing`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("read export: %w", err)
+			}
+			var export conduitExport
+			if err := json.Unmarshal(data, &export); err != nil {
+				return fmt.Errorf("parse export: %w", err)
+			}
+			if export.Version == 0 || export.Version > exportFormatVersion {
+				return fmt.Errorf("unsupported export version %d (this conduit reads up to %d)", export.Version, exportFormatVersion)
+			}
+
+			// Syncing and installing can take a while
+			c := newClientWithTimeout(socketPath, 30*time.Minute)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			failures := importSources(c, export.Sources, !noSync)
+			instanceIDs, installed, n := importInstances(cmd.Context(), c, export.Instances, !noInstall)
+			failures += n
+			failures += importBindings(c, export.Bindings, instanceIDs, installed)
+
+			fmt.Println()
+			if failures > 0 {
+				return fmt.Errorf("import finished with %d problem(s)", failures)
+			}
+			fmt.Println("✓ Import complete")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noSync, "no-sync", false, "Add KB sources without syncing them")
+	cmd.Flags().BoolVar(&noInstall, "no-install", false, "Create instances without installing them (bindings are skipped)")
 
 	return cmd
 }
 
+// importSources adds and syncs exported KB sources, returning the number of failures
+func importSources(c *client, sources []kb.AddSourceRequest, sync bool) int {
+	if len(sources) == 0 {
+		return 0
+	}
+	fmt.Println("📚 KB sources")
+
+	var existing struct {
+		Sources []kb.Source `json:"sources"`
+	}
+	if err := getDaemonJSON(c, "/api/v1/kb/sources", &existing); err != nil {
+		fmt.Printf("   ❌ list sources: %v\n", err)
+		return 1
+	}
+	known := make(map[string]bool)
+	for _, s := range existing.Sources {
+		known[s.Path] = true
+	}
+
+	failures := 0
+	for _, req := range sources {
+		if known[req.Path] {
+			fmt.Printf("   ○ %s already added\n", req.Path)
+			continue
+		}
+		if _, err := os.Stat(req.Path); err != nil {
+			fmt.Printf("   ⚠️  %s skipped: path not found on this machine\n", req.Path)
+			continue
+		}
+
+		var source kb.Source
+		if err := postDaemonJSON(c, "/api/v1/kb/sources", req, &source); err != nil {
+			fmt.Printf("   ❌ %s: %v\n", req.Path, err)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ Added %s (%s)\n", source.Name, source.SourceID)
+
+		if sync {
+			var result kb.SyncResult
+			if err := postDaemonJSON(c, "/api/v1/kb/sources/"+source.SourceID+"/sync", nil, &result); err != nil {
+				fmt.Printf("   ❌ sync %s: %v\n", source.Name, err)
+				failures++
+				continue
+			}
+			fmt.Printf("     synced %d documents\n", result.Added+result.Updated)
+		}
+	}
+	return failures
+}
+
+// importInstances creates, grants and installs exported instances. It returns
+// the new instance ID for each exported ID, which instances are installed,
+// and the number of failures.
+func importInstances(ctx context.Context, c *client, instances []exportedInstance, install bool) (map[string]string, map[string]bool, int) {
+	ids := make(map[string]string)
+	installed := make(map[string]bool)
+	if len(instances) == 0 {
+		return ids, installed, 0
+	}
+	fmt.Println("🔌 Instances")
+
+	var existing struct {
+		Instances []models.ConnectorInstance `json:"instances"`
+	}
+	if err := getDaemonJSON(c, "/api/v1/instances", &existing); err != nil {
+		fmt.Printf("   ❌ list instances: %v\n", err)
+		return ids, installed, 1
+	}
+
+	failures := 0
+	for _, exp := range instances {
+		var match *models.ConnectorInstance
+		for i := range existing.Instances {
+			inst := &existing.Instances[i]
+			if inst.PackageID == exp.PackageID && inst.DisplayName == exp.DisplayName {
+				match = inst
+				break
+			}
+		}
+		if match != nil {
+			fmt.Printf("   ○ %s already exists (%s)\n", exp.DisplayName, match.InstanceID)
+			ids[exp.InstanceID] = match.InstanceID
+			installed[match.InstanceID] = models.IsBindable(match.Status)
+			continue
+		}
+
+		var created models.ConnectorInstance
+		err := postDaemonJSON(c, "/api/v1/instances", map[string]interface{}{
+			"package_id":      exp.PackageID,
+			"package_version": exp.PackageVersion,
+			"display_name":    exp.DisplayName,
+			"image_ref":       exp.ImageRef,
+			"platform":        exp.Platform,
+			"config":          exp.Config,
+			"resources":       exp.Resources,
+			"permissions":     exp.Declared,
+		}, &created)
+		if err != nil {
+			fmt.Printf("   ❌ %s: %v\n", exp.DisplayName, err)
+			failures++
+			continue
+		}
+		ids[exp.InstanceID] = created.InstanceID
+		fmt.Printf("   ✓ Created %s (%s)\n", exp.DisplayName, created.InstanceID)
+
+		if !exp.Granted.IsEmpty() {
+			if err := postDaemonJSON(c, "/api/v1/policy/instances/"+created.InstanceID+"/grants", exp.Granted, nil); err != nil {
+				fmt.Printf("   ❌ grants for %s: %v\n", exp.DisplayName, err)
+				failures++
+			}
+		}
+
+		if !install {
+			continue
+		}
+		var installResp struct {
+			OperationID string `json:"operation_id"`
+		}
+		if err := postDaemonJSON(c, "/api/v1/instances/"+created.InstanceID+"/install", nil, &installResp); err != nil {
+			fmt.Printf("   ❌ install %s: %v\n", exp.DisplayName, err)
+			failures++
+			continue
+		}
+		op, err := waitForOperation(ctx, c, installResp.OperationID)
+		if err != nil {
+			fmt.Printf("   ❌ install %s: %v\n", exp.DisplayName, err)
+			failures++
+			continue
+		}
+		if op["status"] == "failed" {
+			fmt.Printf("   ❌ install %s: %v\n", exp.DisplayName, op["error"])
+			failures++
+			continue
+		}
+		installed[created.InstanceID] = true
+		fmt.Printf("   ✓ Installed %s\n", exp.DisplayName)
+	}
+	return ids, installed, failures
+}
+
+// importBindings re-applies exported bindings to clients detected on this
+// machine, returning the number of failures
+func importBindings(c *client, bindings []exportedBinding, instanceIDs map[string]string, installed map[string]bool) int {
+	if len(bindings) == 0 {
+		return 0
+	}
+	fmt.Println("🔗 Bindings")
+
+	var clients struct {
+		Clients []models.ClientInfo `json:"clients"`
+	}
+	if err := getDaemonJSON(c, "/api/v1/clients", &clients); err != nil {
+		fmt.Printf("   ❌ detect clients: %v\n", err)
+		return 1
+	}
+	detected := make(map[string]bool)
+	for _, cl := range clients.Clients {
+		detected[cl.ClientID] = cl.Installed
+	}
+
+	var existing struct {
+		Bindings []models.ClientBinding `json:"bindings"`
+	}
+	if err := getDaemonJSON(c, "/api/v1/bindings", &existing); err != nil {
+		fmt.Printf("   ❌ list bindings: %v\n", err)
+		return 1
+	}
+
+	failures := 0
+	for _, b := range bindings {
+		instanceID, ok := instanceIDs[b.InstanceID]
+		switch {
+		case !ok:
+			fmt.Printf("   ⚠️  %s → %s skipped: instance was not imported\n", b.InstanceID, b.ClientID)
+			continue
+		case !detected[b.ClientID]:
+			fmt.Printf("   ⚠️  %s → %s skipped: client not detected\n", instanceID, b.ClientID)
+			continue
+		case !installed[instanceID]:
+			fmt.Printf("   ⚠️  %s → %s skipped: instance is not installed\n", instanceID, b.ClientID)
+			continue
+		}
+
+		bound := false
+		for _, e := range existing.Bindings {
+			if e.InstanceID == instanceID && e.ClientID == b.ClientID && e.Scope == b.Scope {
+				bound = true
+				break
+			}
+		}
+		if bound {
+			fmt.Printf("   ○ %s → %s already bound\n", instanceID, b.ClientID)
+			continue
+		}
+
+		var binding models.ClientBinding
+		err := postDaemonJSON(c, "/api/v1/bindings", map[string]interface{}{
+			"instance_id":  instanceID,
+			"client_id":    b.ClientID,
+			"scope":        b.Scope,
+			"project_path": b.ProjectPath,
+		}, &binding)
+		if err != nil {
+			fmt.Printf("   ❌ %s → %s: %v\n", instanceID, b.ClientID, err)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ Bound %s → %s (%s)\n", instanceID, b.ClientID, binding.ConfigPath)
+	}
+	return failures
+}
+
 // uninstallCmd removes Conduit
 func uninstallCmd() *cobra.Command {
 	var (
+		tier       string
 		keepData   bool
 		all        bool
 		force      bool
@@ -5102,12 +9032,13 @@ func uninstallCmd() *cobra.Command {
 		Short: "Uninstall Conduit",
 		Long: `Remove Conduit daemon service, binaries, and optionally data.
 
-UNINSTALL OPTIONS:
-  --keep-data    Remove binaries and service, keep data for reinstall
-  --all          Remove everything including data directory
+UNINSTALL TIERS (--tier):
+  keep-data      Remove binaries and service, keep data for reinstall
+  all            Remove everything including data directory
+  --keep-data and --all are shorthands for the two tiers.
 
 SAFETY FLAGS:
-  --force        Skip all confirmations
+  --yes, --force Skip all confirmations
   --dry-run      Show what would be removed without removing
   --json         Output results as JSON
 
@@ -5119,12 +9050,12 @@ NOTE: Dependencies (Ollama, container runtimes, containers) are NOT removed.
 
 Examples:
   conduit uninstall                    # Interactive mode
-  conduit uninstall --keep-data        # Keep data for reinstall
-  conduit uninstall --all --force      # Remove data without prompts
+  conduit uninstall --tier keep-data   # Keep data for reinstall
+  conduit uninstall --tier all --yes   # Remove data without prompts
   conduit uninstall --dry-run          # Preview what would be removed
   conduit uninstall --info             # Show what's installed`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			inst := installer.New(false)
+			inst := newInstaller(false)
 			ctx := cmd.Context()
 
 			// Show info mode
@@ -5147,10 +9078,17 @@ Examples:
 
 			switch {
 			case all:
-				opts = installer.NewUninstallOptionsAll()
+				tier = installer.UninstallTierAll
 			case keepData:
-				opts = installer.NewUninstallOptionsKeepData()
-			default:
+				tier = installer.UninstallTierKeepData
+			}
+
+			if tier != "" {
+				var err error
+				if opts, err = installer.UninstallOptionsForTier(tier); err != nil {
+					return err
+				}
+			} else {
 				// Interactive mode - show current state and ask
 				info, err := inst.GetUninstallInfo(ctx)
 				if err != nil {
@@ -5294,11 +9232,15 @@ Examples:
 	}
 
 	// Uninstall options
-	cmd.Flags().BoolVar(&keepData, "keep-data", false, "Remove binaries/service, keep data for reinstall")
-	cmd.Flags().BoolVar(&all, "all", false, "Remove everything including data directory")
+	cmd.Flags().StringVar(&tier, "tier", "", "What to remove: "+strings.Join(installer.UninstallTiers, ", "))
+	cmd.Flags().BoolVar(&keepData, "keep-data", false, "Remove binaries/service, keep data for reinstall (--tier keep-data)")
+	cmd.Flags().BoolVar(&all, "all", false, "Remove everything including data directory (--tier all)")
+	cmd.MarkFlagsMutuallyExclusive("tier", "keep-data", "all")
+	cmd.RegisterFlagCompletionFunc("tier", cobra.FixedCompletions(installer.UninstallTiers, cobra.ShellCompDirectiveNoFileComp))
 
 	// Safety flags
 	cmd.Flags().BoolVar(&force, "force", false, "Skip all confirmations")
+	cmd.Flags().BoolVarP(&force, "yes", "y", false, "Skip all confirmations (same as --force)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without removing")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output results as JSON")
 	cmd.Flags().BoolVar(&showInfo, "info", false, "Show installation status without uninstalling")
@@ -5311,13 +9253,15 @@ func serviceCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "service",
 		Short: "Manage Conduit daemon service",
-		Long:  "Install, start, stop, or remove the Conduit daemon service",
+		Long:  "Install, start, stop, restart, or remove the Conduit daemon service, and view its logs",
 	}
 
 	cmd.AddCommand(serviceInstallCmd())
 	cmd.AddCommand(serviceStartCmd())
 	cmd.AddCommand(serviceStopCmd())
+	cmd.AddCommand(serviceRestartCmd())
 	cmd.AddCommand(serviceStatusCmd())
+	cmd.AddCommand(serviceLogsCmd())
 	cmd.AddCommand(serviceRemoveCmd())
 
 	return cmd
@@ -5330,7 +9274,8 @@ func serviceInstallCmd() *cobra.Command {
 		Long: `Install the Conduit daemon as a system service.
 
 On macOS: Creates a launchd agent that starts on login
-On Linux: Creates a systemd user service that starts on login`,
+On Linux: Creates a systemd user service that starts on login
+On Windows: Registers a Windows service that starts at boot (run as Administrator)`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Find the daemon binary
 			daemonPath, err := exec.LookPath("conduit-daemon")
@@ -5346,7 +9291,7 @@ On Linux: Creates a systemd user service that starts on login`,
 				}
 			}
 
-			inst := installer.New(false)
+			inst := newInstaller(false)
 			result := inst.SetupDaemonService(cmd.Context(), daemonPath)
 			if result.Error != nil {
 				return result.Error
@@ -5361,7 +9306,7 @@ func serviceStartCmd() *cobra.Command {
 		Use:   "start",
 		Short: "Start the daemon service",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			inst := installer.New(false)
+			inst := newInstaller(false)
 
 			// First, check if the daemon is already running
 			if inst.IsDaemonRunning() {
@@ -5431,22 +9376,180 @@ func serviceStartCmd() *cobra.Command {
 				fmt.Println("✓ Daemon service started")
 				return nil
 
+			case "windows":
+				if _, err := inst.WindowsServiceState(); err != nil {
+					fmt.Println("Service not installed. Installing...")
+					daemonPath, err := exec.LookPath("conduit-daemon")
+					if err != nil {
+						conduitPath, err := os.Executable()
+						if err != nil {
+							return fmt.Errorf("could not find conduit-daemon binary")
+						}
+						daemonPath = filepath.Join(filepath.Dir(conduitPath), "conduit-daemon.exe")
+						if _, err := os.Stat(daemonPath); err != nil {
+							return fmt.Errorf("could not find conduit-daemon binary")
+						}
+					}
+					// Installing also starts the service
+					result := inst.SetupDaemonService(cmd.Context(), daemonPath)
+					if result.Error != nil {
+						return result.Error
+					}
+					return nil
+				}
+				if out, err := exec.Command("sc.exe", "start", "conduit").CombinedOutput(); err != nil {
+					return fmt.Errorf("failed to start service: %s", strings.TrimSpace(string(out)))
+				}
+				fmt.Println("✓ Daemon service started")
+				return nil
+
 			default:
 				fmt.Println("Start the daemon manually: conduit-daemon --foreground")
 				return nil
 			}
 		},
 	}
-}
+}
+
+func serviceStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the daemon service",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inst := newInstaller(false)
+			return inst.StopDaemonService()
+		},
+	}
+}
+
+func serviceRestartCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the daemon service",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inst := newInstaller(false)
+			if err := inst.RestartDaemonService(); err != nil {
+				return fmt.Errorf("failed to restart service: %w", err)
+			}
+
+			// Wait for the daemon to come back
+			for j := 0; j < 20; j++ {
+				if inst.IsDaemonRunning() {
+					fmt.Println("✓ Daemon service restarted")
+					return nil
+				}
+				time.Sleep(500 * time.Millisecond)
+			}
+
+			fmt.Println("⚠️  Service restarted but the daemon is not responding yet")
+			fmt.Println("   Check the logs: conduit service logs")
+			return nil
+		},
+	}
+}
+
+func serviceLogsCmd() *cobra.Command {
+	var follow bool
+	var lines int
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show daemon service logs",
+		Long: `Show the daemon service logs.
+
+On macOS and Windows: reads ~/.conduit/daemon.log
+On Linux: reads the systemd journal (journalctl --user -u conduit)`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if runtime.GOOS == "linux" {
+				journalArgs := []string{"--user", "-u", "conduit", "-n", strconv.Itoa(lines), "--no-pager"}
+				if follow {
+					journalArgs = append(journalArgs, "-f")
+				}
+				c := exec.CommandContext(cmd.Context(), "journalctl", journalArgs...)
+				c.Stdout = os.Stdout
+				c.Stderr = os.Stderr
+				return c.Run()
+			}
+
+			inst := newInstaller(false)
+			logPath := inst.DaemonLogPath()
+			if _, err := os.Stat(logPath); err != nil {
+				return fmt.Errorf("no daemon log at %s (is the service installed?)", logPath)
+			}
+			return tailFile(cmd.Context(), logPath, lines, follow)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Follow the log output")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 50, "Number of lines to show")
+	return cmd
+}
+
+// tailFile prints the last n lines of a file and, when follow is set, keeps
+// printing lines as they are appended until ctx is cancelled.
+func tailFile(ctx context.Context, path string, n int, follow bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Keep a ring of the last n lines
+	var last []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		last = append(last, scanner.Text())
+		if len(last) > n {
+			last = last[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	for _, line := range last {
+		fmt.Println(line)
+	}
+
+	if !follow {
+		return nil
+	}
+
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
 
-func serviceStopCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "stop",
-		Short: "Stop the daemon service",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			inst := installer.New(false)
-			return inst.StopDaemonService()
-		},
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		// Start over if the log was truncated or rotated
+		if info.Size() < offset {
+			offset = 0
+		}
+		if info.Size() == offset {
+			continue
+		}
+
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		written, err := io.Copy(os.Stdout, f)
+		if err != nil {
+			return err
+		}
+		offset += written
 	}
 }
 
@@ -5455,7 +9558,7 @@ func serviceStatusCmd() *cobra.Command {
 		Use:   "status",
 		Short: "Show daemon service status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			inst := installer.New(false)
+			inst := newInstaller(false)
 
 			if inst.IsDaemonRunning() {
 				fmt.Println("✓ Conduit daemon is running")
@@ -5480,6 +9583,12 @@ func serviceStatusCmd() *cobra.Command {
 				} else {
 					fmt.Println("○ Daemon service is not installed")
 				}
+			case "windows":
+				if state, err := inst.WindowsServiceState(); err == nil {
+					fmt.Printf("✓ Daemon service is installed (Windows service, %s)\n", state)
+				} else {
+					fmt.Println("○ Daemon service is not installed")
+				}
 			}
 
 			return nil
@@ -5492,7 +9601,7 @@ func serviceRemoveCmd() *cobra.Command {
 		Use:   "remove",
 		Short: "Remove the daemon service",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			inst := installer.New(false)
+			inst := newInstaller(false)
 			if err := inst.RemoveDaemonService(); err != nil {
 				return err
 			}
@@ -6462,14 +10571,17 @@ Examples:
 			// Count total chunks to process FIRST (before opening cursor)
 			ctx := cmd.Context()
 			var totalChunks int
+			// Sources added with extraction disabled are skipped entirely
 			if force {
-				db.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM kb_chunks").Scan(&totalChunks)
+				db.DB().QueryRowContext(ctx,
+					"SELECT COUNT(*) FROM kb_chunks c WHERE "+kb.ExtractableChunkFilter,
+				).Scan(&totalChunks)
 			} else {
 				db.DB().QueryRowContext(ctx, `
 					SELECT COUNT(*) FROM kb_chunks c
 					LEFT JOIN kb_extraction_status s ON c.chunk_id = s.chunk_id
-					WHERE s.status IS NULL OR s.status = 'error'
-				`).Scan(&totalChunks)
+					WHERE (s.status IS NULL OR s.status = 'error') AND `+kb.ExtractableChunkFilter,
+				).Scan(&totalChunks)
 			}
 
 			if totalChunks == 0 {
@@ -6486,6 +10598,7 @@ Examples:
 					SELECT c.chunk_id, c.document_id, c.content, COALESCE(d.title, '')
 					FROM kb_chunks c
 					LEFT JOIN kb_documents d ON c.document_id = d.document_id
+					WHERE ` + kb.ExtractableChunkFilter + `
 					ORDER BY c.chunk_id
 				`
 			} else {
@@ -6494,7 +10607,7 @@ Examples:
 					FROM kb_chunks c
 					LEFT JOIN kb_extraction_status s ON c.chunk_id = s.chunk_id
 					LEFT JOIN kb_documents d ON c.document_id = d.document_id
-					WHERE s.status IS NULL OR s.status = 'error'
+					WHERE (s.status IS NULL OR s.status = 'error') AND ` + kb.ExtractableChunkFilter + `
 					ORDER BY c.chunk_id
 				`
 			}
@@ -6698,8 +10811,8 @@ func kbKagStatusCmd() *cobra.Command {
 			db.DB().QueryRowContext(ctx, `
 				SELECT COUNT(*) FROM kb_chunks c
 				LEFT JOIN kb_extraction_status s ON c.chunk_id = s.chunk_id
-				WHERE s.status IS NULL
-			`).Scan(&pendingCount)
+				WHERE s.status IS NULL AND `+kb.ExtractableChunkFilter,
+			).Scan(&pendingCount)
 			if pendingCount > 0 {
 				statusCounts["pending"] = pendingCount
 			}
@@ -7579,6 +11692,394 @@ Examples:
 	return cmd
 }
 
+func kbEntitiesCmd() *cobra.Command {
+	var entityType string
+	var search string
+	var limit int
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "entities",
+		Short: "List entities in the knowledge graph",
+		Long: `List entities extracted by KAG, most connected first.
+
+Use this to check extraction quality: look for missing concepts,
+duplicates, or entities with the wrong type. Use 'conduit kb entity <name>'
+to see an entity's relations and source documents.
+
+Examples:
+  conduit kb entities
+  conduit kb entities --type technology
+  conduit kb entities --search auth --limit 100`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			params := url.Values{}
+			if entityType != "" {
+				params.Set("type", entityType)
+			}
+			if search != "" {
+				params.Set("search", search)
+			}
+			params.Set("limit", strconv.Itoa(limit))
+
+			data, err := c.get("/api/v1/kb/entities?" + params.Encode())
+			if err != nil {
+				return fmt.Errorf("list entities: %w", err)
+			}
+
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
+			var resp struct {
+				kb.EntityListResult
+				Error interface{} `json:"error"`
+			}
+			json.Unmarshal(data, &resp)
+			if resp.Error != nil {
+				return fmt.Errorf("list entities: %s", daemonErrorMessage(data))
+			}
+
+			if resp.Total == 0 {
+				fmt.Println("No entities found")
+				if entityType == "" && search == "" {
+					fmt.Println("Run 'conduit kb kag-sync' to extract entities from indexed documents")
+				}
+				return nil
+			}
+
+			// Type breakdown, largest first
+			types := make([]string, 0, len(resp.TypeCounts))
+			for t := range resp.TypeCounts {
+				types = append(types, t)
+			}
+			sort.Slice(types, func(i, j int) bool {
+				if resp.TypeCounts[types[i]] != resp.TypeCounts[types[j]] {
+					return resp.TypeCounts[types[i]] > resp.TypeCounts[types[j]]
+				}
+				return types[i] < types[j]
+			})
+			parts := make([]string, len(types))
+			for i, t := range types {
+				parts[i] = fmt.Sprintf("%s %d", t, resp.TypeCounts[t])
+			}
+			fmt.Printf("By type: %s\n\n", strings.Join(parts, ", "))
+
+			fmt.Printf("%-30s %-14s %-9s %-6s %s\n", "NAME", "TYPE", "RELATIONS", "CONF", "SOURCE")
+			for _, e := range resp.Entities {
+				source := e.SourceDocTitle
+				if source == "" {
+					source = e.SourceDocumentID
+				}
+				fmt.Printf("%-30s %-14s %-9d %-6.2f %s\n",
+					truncate(e.Name, 30), truncate(e.Type, 14), e.RelationCount, e.Confidence, truncate(source, 40))
+			}
+
+			if len(resp.Entities) < resp.Total {
+				fmt.Printf("\nShowing %d of %d entities (use --limit to see more)\n", len(resp.Entities), resp.Total)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&entityType, "type", "", "Only show entities of this type (e.g. concept, technology, person)")
+	cmd.Flags().StringVar(&search, "search", "", "Only show entities whose name contains this term")
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of entities to show")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func kbEntityCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "entity <name>",
+		Short: "Show an entity's relations and source documents",
+		Long: `Show a knowledge graph entity with its relations, related entities,
+and the documents it was extracted from. An exact name match is preferred;
+otherwise the most confident partial match is shown.
+
+Examples:
+  conduit kb entity "Conduit"
+  conduit kb entity oauth --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			data, err := c.get("/api/v1/kb/entities/" + url.PathEscape(args[0]))
+			if err != nil {
+				return fmt.Errorf("get entity: %w", err)
+			}
+
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
+			var resp struct {
+				kb.EntityDetail
+				Error interface{} `json:"error"`
+			}
+			json.Unmarshal(data, &resp)
+			if resp.Error != nil {
+				return fmt.Errorf("get entity: %s", daemonErrorMessage(data))
+			}
+
+			e := resp.Entity
+			fmt.Printf("%s (%s)\n", e.Name, e.Type)
+			fmt.Printf("  Confidence: %.2f\n", e.Confidence)
+			if e.Description != "" {
+				fmt.Printf("  %s\n", e.Description)
+			}
+
+			fmt.Println()
+			fmt.Printf("Relations (%d):\n", len(resp.Relations))
+			if len(resp.Relations) == 0 {
+				fmt.Println("  (none)")
+			}
+			for _, r := range resp.Relations {
+				fmt.Printf("  %s → %s → %s  (%.2f)\n", r.SubjectName, r.Predicate, r.ObjectName, r.Confidence)
+			}
+
+			if len(resp.Related) > 0 {
+				names := make([]string, len(resp.Related))
+				for i, rel := range resp.Related {
+					names[i] = fmt.Sprintf("%s (%s)", rel.Name, rel.Type)
+				}
+				fmt.Println()
+				fmt.Printf("Related: %s\n", strings.Join(names, ", "))
+			}
+
+			fmt.Println()
+			fmt.Printf("Documents (%d):\n", len(resp.Documents))
+			for _, doc := range resp.Documents {
+				if doc.Title != "" {
+					fmt.Printf("  %s  (%s)\n", doc.Path, doc.Title)
+				} else {
+					fmt.Printf("  %s\n", doc.Path)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+
+	return cmd
+}
+
+func kbGetCmd() *cobra.Command {
+	var jsonOutput, contentOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "get <document-id|path>",
+		Short: "Show an indexed document's full text",
+		Long: `Show a knowledge base document with its metadata and full text.
+
+The document is named by its ID (as shown in search results with --json)
+or by its path. Files still on disk are re-read, so the text keeps its
+original formatting; documents whose file is gone are reassembled from the
+indexed chunks. A note is shown when the file changed since it was indexed.
+
+Examples:
+  conduit kb get doc_3f2a9c1e5b7d4a60
+  conduit kb get ~/docs/api/limits.md
+  conduit kb get ~/docs/api/limits.md --content-only > limits.md`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			endpoint := "/api/v1/kb/documents/" + url.PathEscape(args[0])
+			if !kbDocumentIDPattern.MatchString(args[0]) {
+				endpoint = "/api/v1/kb/documents?path=" + url.QueryEscape(expandGrantPath(args[0]))
+			}
+			data, err := c.get(endpoint)
+			if err != nil {
+				return fmt.Errorf("get document: %w", err)
+			}
+
+			if outputFormat != "table" {
+				return printOutput(data)
+			}
+
+			var resp struct {
+				kb.DocumentContent
+				Error interface{} `json:"error"`
+			}
+			json.Unmarshal(data, &resp)
+			if resp.Error != nil {
+				return fmt.Errorf("get document: %s", daemonErrorMessage(data))
+			}
+
+			if contentOnly {
+				fmt.Print(resp.Content)
+				if !strings.HasSuffix(resp.Content, "\n") {
+					fmt.Println()
+				}
+				return nil
+			}
+
+			fmt.Printf("%s\n", resp.Title)
+			fmt.Printf("  ID:      %s\n", resp.DocumentID)
+			fmt.Printf("  Path:    %s\n", resp.Path)
+			fmt.Printf("  Type:    %s (%s)\n", resp.MimeType, formatBytes(resp.Size))
+			fmt.Printf("  Chunks:  %d\n", resp.ChunkCount)
+			fmt.Printf("  Indexed: %s\n", resp.IndexedAt.Format("2006-01-02 15:04"))
+			switch {
+			case resp.ContentFrom == kb.ContentFromIndex:
+				fmt.Println("  Note:    file not readable; text reassembled from the index")
+			case resp.Changed:
+				fmt.Println("  Note:    file changed since indexing; run 'conduit kb sync' to update")
+			}
+			fmt.Println()
+			fmt.Println(strings.TrimRight(resp.Content, "\n"))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output as JSON")
+	cmd.Flags().BoolVar(&contentOnly, "content-only", false, "Print only the document text")
+
+	return cmd
+}
+
+// kbDocumentIDPattern matches the IDs the daemon gives indexed documents;
+// any other argument to 'kb get' is taken as a path.
+var kbDocumentIDPattern = regexp.MustCompile(`^doc_[0-9a-f]+$`)
+
+func kbBackupCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the knowledge base database",
+		Long: `Write a consistent copy of the Conduit database (sources, documents,
+chunks, entities and instance state) while the daemon keeps running.
+
+Without --file the backup is written to a timestamped file in
+~/.conduit/backups. Automatic backups are also taken before schema
+migrations and 'conduit kb remove' (see database.auto_backup).
+
+Examples:
+  conduit kb backup
+  conduit kb backup --file ~/conduit-before-cleanup.db`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			// A path given on the command line is the user's own choice, so
+			// it may be outside the backups directory
+			req := map[string]interface{}{}
+			if output != "" {
+				path, err := filepath.Abs(output)
+				if err != nil {
+					return fmt.Errorf("resolve output path: %w", err)
+				}
+				req["path"] = path
+				req["confirm_path"] = true
+			}
+
+			data, err := c.post("/api/v1/kb/backup", req)
+			if err != nil {
+				return fmt.Errorf("backup failed: %w", err)
+			}
+
+			var resp struct {
+				Path  string      `json:"path"`
+				Size  int64       `json:"size"`
+				Error interface{} `json:"error"`
+			}
+			json.Unmarshal(data, &resp)
+			if resp.Error != nil {
+				return fmt.Errorf("backup failed: %s", daemonErrorMessage(data))
+			}
+
+			fmt.Printf("✓ Backed up database to %s (%s)\n", resp.Path, formatBytes(resp.Size))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "file", "f", "", "Backup file path (default: timestamped file in ~/.conduit/backups)")
+
+	return cmd
+}
+
+func kbRestoreCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Short: "Restore the knowledge base database from a backup",
+		Long: `Replace the Conduit database with a backup made by 'conduit kb backup'
+or an automatic backup from ~/.conduit/backups.
+
+The current database is backed up first, so a restore can itself be undone.
+Backups from older versions are migrated to the current schema; backups from
+newer versions are refused. Vectors in Qdrant are not part of the backup, so
+run 'conduit qdrant attach --reindex' if semantic results look out of date.
+
+Examples:
+  conduit kb restore ~/.conduit/backups/conduit-20250301-101500.db`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("resolve backup path: %w", err)
+			}
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("backup not found: %s", path)
+			}
+
+			if !force && !confirmAction(fmt.Sprintf("Replace the current database with %s?", filepath.Base(path))) {
+				fmt.Println("Cancelled")
+				return nil
+			}
+
+			c := newClient(socketPath)
+			if err := c.waitReady(readyTimeout); err != nil {
+				return err
+			}
+
+			// The user named the file and confirmed the restore above
+			data, err := c.post("/api/v1/kb/restore", map[string]interface{}{"path": path, "confirm_path": true})
+			if err != nil {
+				return fmt.Errorf("restore failed: %w", err)
+			}
+
+			var resp struct {
+				PreviousBackup string      `json:"previous_backup"`
+				Error          interface{} `json:"error"`
+			}
+			json.Unmarshal(data, &resp)
+			if resp.Error != nil {
+				return fmt.Errorf("restore failed: %s", daemonErrorMessage(data))
+			}
+
+			fmt.Printf("✓ Restored database from %s\n", path)
+			if resp.PreviousBackup != "" {
+				fmt.Printf("  Previous database saved to %s\n", resp.PreviousBackup)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation")
+
+	return cmd
+}
+
 // ollamaCmd returns the ollama parent command
 func ollamaCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -7813,17 +12314,24 @@ func ensureOllamaRunning(ctx context.Context) error {
 
 // ollamaPullCmd pulls an Ollama model with progress streaming
 func ollamaPullCmd() *cobra.Command {
-	return &cobra.Command{
+	var jsonOutput bool
+	var skipVerify bool
+
+	cmd := &cobra.Command{
 		Use:   "pull <model>",
 		Short: "Pull an Ollama model",
 		Long: `Pull (download) an Ollama model from the registry.
 
-Progress is streamed to stdout, making it suitable for GUI integration.
+Progress is reported as a percentage while layers download. With --json,
+each progress update is written as one JSON object per line, making it
+suitable for GUI integration. After the download the model is verified
+with a tiny test request so corrupt downloads are caught immediately.
 If Ollama is not running, it will be started automatically.
 
 Examples:
   conduit ollama pull nomic-embed-text
-  conduit ollama pull mistral:7b-instruct-q4_K_M`,
+  conduit ollama pull mistral:7b-instruct-q4_K_M
+  conduit ollama pull nomic-embed-text --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -7834,24 +12342,55 @@ Examples:
 				return err
 			}
 
-			fmt.Printf("Pulling model: %s\n", model)
-
-			// Run ollama pull with output streaming
-			ollamaBin := findOllamaBinary()
-			pullCmd := exec.CommandContext(ctx, ollamaBin, "pull", model)
+			progress := installer.PrintPullProgress
+			if jsonOutput {
+				enc := json.NewEncoder(os.Stdout)
+				progress = func(p installer.PullProgress) {
+					enc.Encode(p)
+				}
+			} else {
+				fmt.Printf("Pulling model: %s\n", model)
+			}
 
-			// Capture both stdout and stderr for progress
-			pullCmd.Stdout = os.Stdout
-			pullCmd.Stderr = os.Stderr
+			pullOpts := installer.PullOptions{MinFree: diskspace.DefaultMinFree}
+			if cfg, err := config.Load(); err == nil {
+				pullOpts.MinFree = cfg.Disk.MinFreeBytes()
+			}
 
-			if err := pullCmd.Run(); err != nil {
+			if err := installer.PullOllamaModelWithOptions(ctx, installer.DefaultOllamaHost, model, pullOpts, progress); err != nil {
+				if !jsonOutput {
+					fmt.Println()
+				}
 				return fmt.Errorf("pull failed: %w", err)
 			}
 
+			if !skipVerify {
+				if !jsonOutput {
+					fmt.Println()
+					fmt.Println("Verifying model...")
+				}
+				if err := installer.VerifyOllamaModel(ctx, installer.DefaultOllamaHost, model); err != nil {
+					return err
+				}
+			}
+
+			if jsonOutput {
+				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					"model":    model,
+					"status":   "complete",
+					"verified": !skipVerify,
+				})
+			}
+
 			fmt.Printf("\n✓ Model %s pulled successfully\n", model)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output progress as JSON lines")
+	cmd.Flags().BoolVar(&skipVerify, "skip-verify", false, "Skip the post-download test request")
+
+	return cmd
 }
 
 // ollamaWarmupCmd preloads required models into memory
@@ -7977,19 +12516,13 @@ Press Ctrl+C to stop streaming.`,
 
 // streamEvents connects to the SSE endpoint and streams events
 func streamEvents(socketPath string, jsonOutput bool) error {
-	// Create a custom HTTP client with Unix socket transport
-	transport := &http.Transport{
-		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
-			return net.Dial("unix", socketPath)
-		},
-	}
 	client := &http.Client{
-		Transport: transport,
+		Transport: daemonTransport(socketPath),
 		Timeout:   0, // No timeout for SSE streaming
 	}
 
 	// Make the SSE request
-	req, err := http.NewRequest("GET", "http://localhost/api/v1/events", nil)
+	req, err := http.NewRequest("GET", daemonBaseURL()+"/api/v1/events", nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/simpleflo/conduit/pkg/models"
+)
+
+// newTestDaemon serves handler on a Unix socket and returns the socket path.
+func newTestDaemon(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	sock := filepath.Join(t.TempDir(), "conduit.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Listener = ln
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return sock
+}
+
+func TestKBEvalSearchPagesUntilKDocuments(t *testing.T) {
+	// Each page holds two chunks of the same document
+	var offsets []int
+	sock := newTestDaemon(t, func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, offset)
+		doc := "doc_" + strconv.Itoa(offset)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results":  []map[string]string{{"document_id": doc}, {"document_id": doc}},
+			"has_more": offset < 4,
+		})
+	})
+
+	params := url.Values{"q": {"quota"}, "limit": {"2"}}
+	docs, err := kbEvalSearch(newClient(sock), params, 2)
+	if err != nil {
+		t.Fatalf("kbEvalSearch: %v", err)
+	}
+	if len(docs) != 4 || docs[2].DocumentID != "doc_2" {
+		t.Errorf("expected two pages of results, got %+v", docs)
+	}
+	if len(offsets) != 2 || offsets[1] != 2 {
+		t.Errorf("offsets requested = %v, want [0 2]", offsets)
+	}
+
+	// A ranking that runs out stops paging
+	offsets = nil
+	docs, err = kbEvalSearch(newClient(sock), params, 10)
+	if err != nil {
+		t.Fatalf("kbEvalSearch: %v", err)
+	}
+	if len(offsets) != 2 || offsets[1] != 10 || len(docs) != 4 {
+		t.Errorf("expected paging to stop when has_more is false, got offsets %v and %d results", offsets, len(docs))
+	}
+}
+
+func TestExportImportKeepsPlatform(t *testing.T) {
+	var created map[string]interface{}
+	sock := newTestDaemon(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/instances":
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(models.ConnectorInstance{InstanceID: "new"})
+		case r.URL.Path == "/api/v1/instances":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"instances": []models.ConnectorInstance{{InstanceID: "old", PackageID: "pkg", DisplayName: "Server", Platform: "linux/amd64"}},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{})
+		}
+	})
+	c := newClient(sock)
+
+	export, err := collectExport(c)
+	if err != nil {
+		t.Fatalf("collectExport: %v", err)
+	}
+	if len(export.Instances) != 1 || export.Instances[0].Platform != "linux/amd64" {
+		t.Fatalf("expected the platform to be exported, got %+v", export.Instances)
+	}
+
+	exp := export.Instances[0]
+	exp.DisplayName = "Server on another machine"
+	if _, _, failures := importInstances(context.Background(), c, []exportedInstance{exp}, false); failures != 0 {
+		t.Fatalf("importInstances reported %d failures", failures)
+	}
+	if created["platform"] != "linux/amd64" {
+		t.Errorf("expected the platform in the create request, got %v", created)
+	}
+}
+
+func TestWaitForOperation(t *testing.T) {
+	operationDaemon := func(status string) *client {
+		return newClient(newTestDaemon(t, func(w http.ResponseWriter, r *http.Request) {
+			// Progress out of range must not break the progress bar
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"operation_id": "op-1",
+				"status":       status,
+				"progress":     150,
+			})
+		}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := waitForOperation(ctx, operationDaemon("running"), "op-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a running operation to stop at the deadline, got %v", err)
+	}
+
+	op, err := waitForOperation(context.Background(), operationDaemon("completed"), "op-1")
+	if err != nil || op["status"] != "completed" {
+		t.Errorf("waitForOperation = %v, %v", op, err)
+	}
+}
+
+func TestWholeSeconds(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    int
+	}{
+		{500 * time.Millisecond, 1},
+		{time.Second, 1},
+		{90 * time.Second, 90},
+		{10*time.Minute + time.Millisecond, 601},
+	}
+	for _, tt := range tests {
+		if got, err := wholeSeconds(tt.timeout); err != nil || got != tt.want {
+			t.Errorf("wholeSeconds(%v) = %d, %v; want %d", tt.timeout, got, err, tt.want)
+		}
+	}
+	for _, bad := range []time.Duration{0, -time.Second} {
+		if _, err := wholeSeconds(bad); err == nil {
+			t.Errorf("expected wholeSeconds(%v) to be rejected", bad)
+		}
+	}
+}
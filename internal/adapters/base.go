@@ -2,6 +2,8 @@ package adapters
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -55,6 +57,53 @@ func ensureDir(path string) error {
 	return os.MkdirAll(path, 0755)
 }
 
+// removeServerEntry deletes the entries Conduit injected for instanceID from
+// the servers map under serversKey. Other servers and settings in the file
+// are kept; a missing file or entry is not an error.
+func removeServerEntry(path, serversKey, instanceID string) (*RollbackResult, error) {
+	result := &RollbackResult{
+		FilesRestored: []string{},
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		result.Success = true
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	servers, _ := config[serversKey].(map[string]interface{})
+	removed := false
+	for name, raw := range servers {
+		entry, ok := raw.(map[string]interface{})
+		if ok && entry["_managed_by"] == "conduit" && entry["_instance_id"] == instanceID {
+			delete(servers, name)
+			removed = true
+		}
+	}
+	if removed {
+		data, _ := json.MarshalIndent(config, "", "  ")
+		if err := os.WriteFile(path, data, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("write config: %w", err)
+		}
+		result.FilesRestored = append(result.FilesRestored, path)
+	}
+
+	result.Success = true
+	return result, nil
+}
+
 // storeBackup stores a backup record in the database.
 func (a *baseAdapter) storeBackup(changeSetID, clientID, originalPath, backupPath string, fileExisted bool) error {
 	_, err := a.db.Exec(`
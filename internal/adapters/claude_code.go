@@ -133,8 +133,7 @@ func (a *ClaudeCodeAdapter) PlanInjection(ctx context.Context, req PlanRequest)
 	}
 
 	// Build server entry name
-	serverName := fmt.Sprintf("conduit-%s", req.DisplayName)
-	serverName = strings.ToLower(strings.ReplaceAll(serverName, " ", "-"))
+	serverName := ServerName(req.DisplayName)
 
 	plan.ExpectedPostState = ExpectedState{
 		MCPServers: []string{serverName},
@@ -187,20 +186,16 @@ func (a *ClaudeCodeAdapter) ApplyInjection(ctx context.Context, plan *InjectionP
 
 			// Add Conduit server entry
 			serverName := plan.ExpectedPostState.MCPServers[0]
-			servers[serverName] = map[string]interface{}{
-				"command": "conduit",
-				"args":    []string{"mcp", "stdio", "--instance", plan.InstanceID},
-				"env": map[string]string{
-					"CONDUIT_SOCKET": filepath.Join(conduitDir(), "conduit.sock"),
-				},
-				"_managed_by":  "conduit",
-				"_instance_id": plan.InstanceID,
-			}
+			servers[serverName] = NewServerEntry(plan.InstanceID).managed(plan.InstanceID)
 
 			// Create parent directory if needed
 			if err := ensureDir(filepath.Dir(op.Path)); err != nil {
 				return nil, fmt.Errorf("create config dir: %w", err)
 			}
+			if op.Type == "create_file" {
+				// Rollback removes files the binding created
+				a.storeBackup(plan.ChangeSetID, a.ID(), op.Path, "", false)
+			}
 
 			// Write config
 			data, _ := json.MarshalIndent(config, "", "  ")
@@ -304,6 +299,12 @@ func (a *ClaudeCodeAdapter) Rollback(ctx context.Context, changeSetID string) (*
 	return result, nil
 }
 
+// RemoveInjection removes the server entry a binding added, leaving the rest
+// of the config as it is now.
+func (a *ClaudeCodeAdapter) RemoveInjection(ctx context.Context, binding BindingInfo) (*RollbackResult, error) {
+	return removeServerEntry(binding.ConfigPath, "mcpServers", binding.InstanceID)
+}
+
 // Doctor checks for issues with Claude Code configuration.
 func (a *ClaudeCodeAdapter) Doctor(ctx context.Context) ([]DoctorIssue, error) {
 	issues := []DoctorIssue{}
@@ -8,7 +8,6 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -75,6 +74,9 @@ func (a *CursorAdapter) Detect(ctx context.Context) (*DetectResult, error) {
 
 	if fileExists(appPath) || dirExists(appPath) {
 		result.Installed = true
+		if !dirExists(filepath.Join(homeDir(), ".cursor")) {
+			result.Notes = "Cursor has not been launched yet; config will be created on first bind"
+		}
 	} else {
 		result.Notes = "Cursor application not found"
 	}
@@ -105,13 +107,14 @@ func (a *CursorAdapter) PlanInjection(ctx context.Context, req PlanRequest) (*In
 		ChangeSetID: fmt.Sprintf("cs_%s_%s", time.Now().Format("20060102_150405"), uuid.New().String()[:8]),
 		ClientID:    a.ID(),
 		InstanceID:  req.InstanceID,
+		Scope:       req.Scope,
 		Operations:  []InjectionOp{},
 	}
 
 	// Determine config path based on scope
 	var configPath string
 	switch req.Scope {
-	case "project":
+	case "project", "workspace":
 		if req.ProjectPath != "" {
 			configPath = filepath.Join(req.ProjectPath, ".cursor", "mcp.json")
 		} else {
@@ -119,6 +122,7 @@ func (a *CursorAdapter) PlanInjection(ctx context.Context, req PlanRequest) (*In
 			configPath = filepath.Join(cwd, ".cursor", "mcp.json")
 		}
 	default: // "user" is default for Cursor
+		plan.Scope = "user"
 		configPath = filepath.Join(homeDir(), ".cursor", "mcp.json")
 	}
 
@@ -143,8 +147,7 @@ func (a *CursorAdapter) PlanInjection(ctx context.Context, req PlanRequest) (*In
 	}
 
 	// Build server entry name
-	serverName := fmt.Sprintf("conduit-%s", req.DisplayName)
-	serverName = strings.ToLower(strings.ReplaceAll(serverName, " ", "-"))
+	serverName := ServerName(req.DisplayName)
 
 	plan.ExpectedPostState = ExpectedState{
 		MCPServers: []string{serverName},
@@ -193,20 +196,17 @@ func (a *CursorAdapter) ApplyInjection(ctx context.Context, plan *InjectionPlan)
 
 			// Add Conduit server entry
 			serverName := plan.ExpectedPostState.MCPServers[0]
-			servers[serverName] = map[string]interface{}{
-				"command": "conduit",
-				"args":    []string{"mcp", "stdio", "--instance", plan.InstanceID},
-				"env": map[string]string{
-					"CONDUIT_SOCKET": filepath.Join(conduitDir(), "conduit.sock"),
-				},
-				"_managed_by":  "conduit",
-				"_instance_id": plan.InstanceID,
-			}
+			servers[serverName] = NewServerEntry(plan.InstanceID).managed(plan.InstanceID)
 
-			// Create parent directory if needed
+			// Create parent directory if needed; Cursor may never have
+			// been launched
 			if err := ensureDir(filepath.Dir(op.Path)); err != nil {
 				return nil, fmt.Errorf("create config dir: %w", err)
 			}
+			if op.Type == "create_file" {
+				// Rollback removes files the binding created
+				a.storeBackup(plan.ChangeSetID, a.ID(), op.Path, "", false)
+			}
 
 			data, _ := json.MarshalIndent(config, "", "  ")
 			if err := os.WriteFile(op.Path, data, 0644); err != nil {
@@ -219,7 +219,7 @@ func (a *CursorAdapter) ApplyInjection(ctx context.Context, plan *InjectionPlan)
 	}
 
 	result.Success = true
-	result.ConfigScope = "user"
+	result.ConfigScope = plan.Scope
 	return result, nil
 }
 
@@ -303,6 +303,12 @@ func (a *CursorAdapter) Rollback(ctx context.Context, changeSetID string) (*Roll
 	return result, nil
 }
 
+// RemoveInjection removes the server entry a binding added, leaving the rest
+// of the config as it is now.
+func (a *CursorAdapter) RemoveInjection(ctx context.Context, binding BindingInfo) (*RollbackResult, error) {
+	return removeServerEntry(binding.ConfigPath, "mcpServers", binding.InstanceID)
+}
+
 // Doctor checks for issues with Cursor configuration.
 func (a *CursorAdapter) Doctor(ctx context.Context) ([]DoctorIssue, error) {
 	issues := []DoctorIssue{}
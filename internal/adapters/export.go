@@ -0,0 +1,68 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ServerEntry is the MCP server block Conduit writes into client configs.
+type ServerEntry struct {
+	Command string            `json:"command" toml:"command"`
+	Args    []string          `json:"args" toml:"args"`
+	Env     map[string]string `json:"env,omitempty" toml:"env,omitempty"`
+}
+
+// ServerName returns the key a connector instance's entry is stored under.
+func ServerName(displayName string) string {
+	serverName := fmt.Sprintf("conduit-%s", displayName)
+	return strings.ToLower(strings.ReplaceAll(serverName, " ", "-"))
+}
+
+// NewServerEntry returns the server block that serves a connector instance
+// over stdio through the daemon.
+func NewServerEntry(instanceID string) ServerEntry {
+	return ServerEntry{
+		Command: "conduit",
+		Args:    []string{"mcp", "stdio", "--instance", instanceID},
+		Env: map[string]string{
+			"CONDUIT_SOCKET": filepath.Join(conduitDir(), "conduit.sock"),
+		},
+	}
+}
+
+// managed returns the entry as injected by an adapter, tagged so Conduit can
+// recognise the entries it owns.
+func (e ServerEntry) managed(instanceID string) map[string]interface{} {
+	return map[string]interface{}{
+		"command":      e.Command,
+		"args":         e.Args,
+		"env":          e.Env,
+		"_managed_by":  "conduit",
+		"_instance_id": instanceID,
+	}
+}
+
+// ExportFormats lists the formats supported by ExportServerConfig.
+var ExportFormats = []string{"json", "toml"}
+
+// ExportServerConfig renders a server entry for pasting into a client that
+// has no adapter. JSON uses the common "mcpServers" layout; TOML uses the
+// "mcp_servers" table.
+func ExportServerConfig(name string, entry ServerEntry, format string) ([]byte, error) {
+	switch format {
+	case "", "json":
+		return json.MarshalIndent(map[string]interface{}{
+			"mcpServers": map[string]ServerEntry{name: entry},
+		}, "", "  ")
+	case "toml":
+		return toml.Marshal(map[string]interface{}{
+			"mcp_servers": map[string]ServerEntry{name: entry},
+		})
+	default:
+		return nil, fmt.Errorf("unsupported format %q (use %s)", format, strings.Join(ExportFormats, " or "))
+	}
+}
@@ -125,8 +125,7 @@ func (a *GeminiCLIAdapter) PlanInjection(ctx context.Context, req PlanRequest) (
 	}
 
 	// Build server entry name
-	serverName := fmt.Sprintf("conduit-%s", req.DisplayName)
-	serverName = strings.ToLower(strings.ReplaceAll(serverName, " ", "-"))
+	serverName := ServerName(req.DisplayName)
 
 	plan.ExpectedPostState = ExpectedState{
 		MCPServers: []string{serverName},
@@ -137,20 +136,13 @@ func (a *GeminiCLIAdapter) PlanInjection(ctx context.Context, req PlanRequest) (
 }
 
 // ApplyInjection applies the injection plan.
-// Gemini CLI supports both CLI commands and direct file manipulation.
+// The settings file is edited directly rather than through "gemini mcp add"
+// so the original is backed up and the binding can be rolled back.
 func (a *GeminiCLIAdapter) ApplyInjection(ctx context.Context, plan *InjectionPlan) (*ApplyResult, error) {
 	result := &ApplyResult{
 		FilesChanged: []string{},
 	}
 
-	// Try using gemini CLI first (preferred for consistency)
-	if a.tryGeminiCLI(ctx, plan) {
-		result.Success = true
-		result.ConfigScope = "user"
-		return result, nil
-	}
-
-	// Fallback to direct file manipulation
 	for _, op := range plan.Operations {
 		switch op.Type {
 		case "backup_file":
@@ -184,20 +176,16 @@ func (a *GeminiCLIAdapter) ApplyInjection(ctx context.Context, plan *InjectionPl
 
 			// Add Conduit server entry
 			serverName := plan.ExpectedPostState.MCPServers[0]
-			servers[serverName] = map[string]interface{}{
-				"command": "conduit",
-				"args":    []string{"mcp", "stdio", "--instance", plan.InstanceID},
-				"env": map[string]string{
-					"CONDUIT_SOCKET": filepath.Join(conduitDir(), "conduit.sock"),
-				},
-				"_managed_by":  "conduit",
-				"_instance_id": plan.InstanceID,
-			}
+			servers[serverName] = NewServerEntry(plan.InstanceID).managed(plan.InstanceID)
 
 			// Create parent directory if needed
 			if err := ensureDir(filepath.Dir(op.Path)); err != nil {
 				return nil, fmt.Errorf("create config dir: %w", err)
 			}
+			if op.Type == "create_file" {
+				// Rollback removes files the binding created
+				a.storeBackup(plan.ChangeSetID, a.ID(), op.Path, "", false)
+			}
 
 			data, _ := json.MarshalIndent(config, "", "  ")
 			if err := os.WriteFile(op.Path, data, 0644); err != nil {
@@ -214,28 +202,6 @@ func (a *GeminiCLIAdapter) ApplyInjection(ctx context.Context, plan *InjectionPl
 	return result, nil
 }
 
-// tryGeminiCLI attempts to use gemini mcp add command.
-func (a *GeminiCLIAdapter) tryGeminiCLI(ctx context.Context, plan *InjectionPlan) bool {
-	geminiPath, err := exec.LookPath("gemini")
-	if err != nil {
-		return false
-	}
-
-	serverName := plan.ExpectedPostState.MCPServers[0]
-	args := []string{
-		"mcp", "add", serverName,
-		"--command", "conduit",
-		"--args", strings.Join([]string{"mcp", "stdio", "--instance", plan.InstanceID}, ","),
-	}
-
-	cmd := exec.CommandContext(ctx, geminiPath, args...)
-	if err := cmd.Run(); err != nil {
-		return false
-	}
-
-	return true
-}
-
 // Validate validates a binding.
 func (a *GeminiCLIAdapter) Validate(ctx context.Context, bindingID string) (*ValidationResult, error) {
 	result := &ValidationResult{
@@ -251,16 +217,7 @@ func (a *GeminiCLIAdapter) Validate(ctx context.Context, bindingID string) (*Val
 		return result, nil
 	}
 
-	// Try gemini mcp list command first
-	if tools := a.tryGeminiMCPList(ctx); len(tools) > 0 {
-		result.Status = "pass"
-		result.ToolsFound = tools
-		result.Observations["detected_via"] = "gemini_cli"
-		result.LatencyMS = int(time.Since(start).Milliseconds())
-		return result, nil
-	}
-
-	// Fallback to config file check
+	// Check config file exists and parses
 	if !fileExists(binding.ConfigPath) {
 		result.Status = "fail"
 		result.Errors = append(result.Errors, "Config file not found")
@@ -299,29 +256,6 @@ func (a *GeminiCLIAdapter) Validate(ctx context.Context, bindingID string) (*Val
 	return result, nil
 }
 
-// tryGeminiMCPList attempts to use gemini mcp list command.
-func (a *GeminiCLIAdapter) tryGeminiMCPList(ctx context.Context) []string {
-	geminiPath, err := exec.LookPath("gemini")
-	if err != nil {
-		return nil
-	}
-
-	cmd := exec.CommandContext(ctx, geminiPath, "mcp", "list", "--json")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
-
-	var result struct {
-		Servers []string `json:"servers"`
-	}
-	if err := json.Unmarshal(output, &result); err != nil {
-		return nil
-	}
-
-	return result.Servers
-}
-
 // Rollback rolls back a change set.
 func (a *GeminiCLIAdapter) Rollback(ctx context.Context, changeSetID string) (*RollbackResult, error) {
 	result := &RollbackResult{
@@ -349,6 +283,12 @@ func (a *GeminiCLIAdapter) Rollback(ctx context.Context, changeSetID string) (*R
 	return result, nil
 }
 
+// RemoveInjection removes the server entry a binding added, leaving the rest
+// of the config as it is now.
+func (a *GeminiCLIAdapter) RemoveInjection(ctx context.Context, binding BindingInfo) (*RollbackResult, error) {
+	return removeServerEntry(binding.ConfigPath, "mcpServers", binding.InstanceID)
+}
+
 // Doctor checks for issues with Gemini CLI configuration.
 func (a *GeminiCLIAdapter) Doctor(ctx context.Context) ([]DoctorIssue, error) {
 	issues := []DoctorIssue{}
@@ -2,7 +2,10 @@ package adapters
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -199,6 +202,249 @@ func TestAdapter_PlanInjection(t *testing.T) {
 	}
 }
 
+func TestGeminiCLIAdapter_ApplyAndRollback(t *testing.T) {
+	st := testStore(t)
+	defer st.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, ".gemini", "settings.json")
+	original := `{"theme": "Dracula"}`
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewGeminiCLIAdapter(st.DB())
+	ctx := context.Background()
+	plan, err := a.PlanInjection(ctx, PlanRequest{
+		InstanceID:  "inst_test",
+		DisplayName: "Test Connector",
+		Scope:       "project",
+		ProjectPath: projectDir,
+	})
+	if err != nil {
+		t.Fatalf("PlanInjection: %v", err)
+	}
+
+	result, err := a.ApplyInjection(ctx, plan)
+	if err != nil {
+		t.Fatalf("ApplyInjection: %v", err)
+	}
+	if result.ConfigPath != configPath {
+		t.Errorf("expected config path %s, got %s", configPath, result.ConfigPath)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config struct {
+		Theme      string                            `json:"theme"`
+		MCPServers map[string]map[string]interface{} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("invalid settings after apply: %v", err)
+	}
+	if config.Theme != "Dracula" {
+		t.Error("existing settings were not preserved")
+	}
+	entry, ok := config.MCPServers["conduit-test-connector"]
+	if !ok || entry["command"] != "conduit" {
+		t.Fatalf("expected conduit server entry, got %v", config.MCPServers)
+	}
+
+	rollback, err := a.Rollback(ctx, plan.ChangeSetID)
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if !rollback.Success {
+		t.Fatalf("rollback failed: %v", rollback.Errors)
+	}
+	data, _ = os.ReadFile(configPath)
+	if string(data) != original {
+		t.Errorf("expected original settings after rollback, got %s", data)
+	}
+}
+
+func TestClaudeCodeAdapter_RemoveInjection(t *testing.T) {
+	st := testStore(t)
+	defer st.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, ".mcp.json")
+
+	a := NewClaudeCodeAdapter(st.DB())
+	ctx := context.Background()
+	bind := func(instanceID, name string) {
+		t.Helper()
+		plan, err := a.PlanInjection(ctx, PlanRequest{
+			InstanceID:  instanceID,
+			DisplayName: name,
+			Scope:       "project",
+			ProjectPath: projectDir,
+		})
+		if err != nil {
+			t.Fatalf("PlanInjection: %v", err)
+		}
+		if _, err := a.ApplyInjection(ctx, plan); err != nil {
+			t.Fatalf("ApplyInjection: %v", err)
+		}
+	}
+	bind("inst_first", "First")
+	bind("inst_second", "Second")
+
+	result, err := a.RemoveInjection(ctx, BindingInfo{InstanceID: "inst_first", ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("RemoveInjection: %v", err)
+	}
+	if !result.Success || len(result.FilesRestored) != 1 {
+		t.Errorf("expected one updated file, got %+v", result)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("config was removed: %v", err)
+	}
+	var config struct {
+		MCPServers map[string]interface{} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.MCPServers["conduit-first"]; ok {
+		t.Error("expected the unbound entry to be removed")
+	}
+	if _, ok := config.MCPServers["conduit-second"]; !ok {
+		t.Error("expected the other binding's entry to be kept")
+	}
+
+	// Removing again is a no-op
+	result, err = a.RemoveInjection(ctx, BindingInfo{InstanceID: "inst_first", ConfigPath: configPath})
+	if err != nil || !result.Success || len(result.FilesRestored) != 0 {
+		t.Errorf("expected no-op, got %+v, %v", result, err)
+	}
+}
+
+func TestVSCodeAdapter_UserScopeFirstBind(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("user settings path is OS specific")
+	}
+	st := testStore(t)
+	defer st.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".config", "Code", "User", "mcp.json")
+
+	a := NewVSCodeAdapter(st.DB())
+	ctx := context.Background()
+	plan, err := a.PlanInjection(ctx, PlanRequest{
+		InstanceID:  "inst_test",
+		DisplayName: "Test Connector",
+		Scope:       "user",
+	})
+	if err != nil {
+		t.Fatalf("PlanInjection: %v", err)
+	}
+
+	// VS Code was never launched, so the config is created on first bind
+	result, err := a.ApplyInjection(ctx, plan)
+	if err != nil {
+		t.Fatalf("ApplyInjection: %v", err)
+	}
+	if result.ConfigPath != configPath || result.ConfigScope != "user" {
+		t.Errorf("expected user config %s, got %s (%s)", configPath, result.ConfigPath, result.ConfigScope)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("config not created: %v", err)
+	}
+	var config struct {
+		Servers map[string]interface{} `json:"servers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	if _, ok := config.Servers["conduit-test-connector"]; !ok {
+		t.Errorf("expected conduit server entry, got %v", config.Servers)
+	}
+
+	// Rolling back removes the file the bind created
+	if _, err := a.Rollback(ctx, plan.ChangeSetID); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if fileExists(configPath) {
+		t.Error("expected created config to be removed on rollback")
+	}
+}
+
+func TestVSCodeAdapter_WorkspaceScope(t *testing.T) {
+	st := testStore(t)
+	defer st.Close()
+
+	projectDir := t.TempDir()
+	a := NewVSCodeAdapter(st.DB())
+	plan, err := a.PlanInjection(context.Background(), PlanRequest{
+		InstanceID:  "inst_test",
+		DisplayName: "Test Connector",
+		Scope:       "project",
+		ProjectPath: projectDir,
+	})
+	if err != nil {
+		t.Fatalf("PlanInjection: %v", err)
+	}
+
+	want := filepath.Join(projectDir, ".vscode", "mcp.json")
+	if plan.Scope != "workspace" || plan.Operations[0].Path != want {
+		t.Errorf("expected workspace config %s, got %s (%s)", want, plan.Operations[0].Path, plan.Scope)
+	}
+}
+
+func TestExportServerConfig(t *testing.T) {
+	name := ServerName("My Notes")
+	if name != "conduit-my-notes" {
+		t.Errorf("expected server name conduit-my-notes, got %q", name)
+	}
+	entry := NewServerEntry("inst_test")
+
+	data, err := ExportServerConfig(name, entry, "json")
+	if err != nil {
+		t.Fatalf("export json: %v", err)
+	}
+	var config struct {
+		MCPServers map[string]ServerEntry `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	got := config.MCPServers[name]
+	if got.Command != "conduit" || strings.Join(got.Args, " ") != "mcp stdio --instance inst_test" {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+	if got.Env["CONDUIT_SOCKET"] == "" {
+		t.Error("expected CONDUIT_SOCKET in env")
+	}
+
+	data, err = ExportServerConfig(name, entry, "toml")
+	if err != nil {
+		t.Fatalf("export toml: %v", err)
+	}
+	for _, want := range []string{"[mcp_servers.conduit-my-notes]", "command = 'conduit'", "[mcp_servers.conduit-my-notes.env]"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q in toml output:\n%s", want, data)
+		}
+	}
+
+	if _, err := ExportServerConfig(name, entry, "yaml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
 // testStore creates a temporary store for testing.
 func testStore(t *testing.T) *store.Store {
 	t.Helper()
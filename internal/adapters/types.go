@@ -22,6 +22,7 @@ type Adapter interface {
 	ApplyInjection(ctx context.Context, plan *InjectionPlan) (*ApplyResult, error)
 	Validate(ctx context.Context, bindingID string) (*ValidationResult, error)
 	Rollback(ctx context.Context, changeSetID string) (*RollbackResult, error)
+	RemoveInjection(ctx context.Context, binding BindingInfo) (*RollbackResult, error)
 
 	// Doctor
 	Doctor(ctx context.Context) ([]DoctorIssue, error)
@@ -66,6 +67,7 @@ type InjectionPlan struct {
 	ChangeSetID       string        `json:"change_set_id"`
 	ClientID          string        `json:"client_id"`
 	InstanceID        string        `json:"instance_id"`
+	Scope             string        `json:"scope"` // scope the config path was chosen for
 	Operations        []InjectionOp `json:"operations"`
 	ExpectedPostState ExpectedState `json:"expected_post_state"`
 }
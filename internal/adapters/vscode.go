@@ -51,10 +51,7 @@ func (a *VSCodeAdapter) Detect(ctx context.Context) (*DetectResult, error) {
 
 	// Check if code CLI exists
 	path, err := exec.LookPath("code")
-	if err != nil {
-		result.Installed = false
-		result.Notes = "VS Code 'code' command not found in PATH"
-	} else {
+	if err == nil {
 		result.Installed = true
 
 		// Get version
@@ -64,20 +61,25 @@ func (a *VSCodeAdapter) Detect(ctx context.Context) (*DetectResult, error) {
 		if len(lines) > 0 {
 			result.Version = lines[0]
 		}
+	} else {
+		// The shell command is optional; fall back to the application itself
+		for _, appPath := range vscodeAppPaths() {
+			if fileExists(appPath) {
+				result.Installed = true
+				break
+			}
+		}
 	}
 
-	// User settings location varies by OS
-	var userSettingsDir string
-	switch runtime.GOOS {
-	case "darwin":
-		userSettingsDir = filepath.Join(homeDir(), "Library", "Application Support", "Code", "User")
-	case "windows":
-		userSettingsDir = filepath.Join(os.Getenv("APPDATA"), "Code", "User")
-	case "linux":
-		userSettingsDir = filepath.Join(homeDir(), ".config", "Code", "User")
+	userSettingsDir := vscodeUserDir()
+	switch {
+	case !result.Installed:
+		result.Notes = "VS Code not found"
+	case !dirExists(userSettingsDir):
+		result.Notes = "VS Code has not been launched yet; config will be created on first bind"
 	}
 
-	// User-level MCP config (not typically used for VS Code MCP, but included for completeness)
+	// User-level MCP config, available to every workspace
 	userMCPConfig := filepath.Join(userSettingsDir, "mcp.json")
 	result.ConfigRoots = append(result.ConfigRoots, ConfigRoot{
 		Path:   userMCPConfig,
@@ -103,16 +105,23 @@ func (a *VSCodeAdapter) PlanInjection(ctx context.Context, req PlanRequest) (*In
 		ChangeSetID: fmt.Sprintf("cs_%s_%s", time.Now().Format("20060102_150405"), uuid.New().String()[:8]),
 		ClientID:    a.ID(),
 		InstanceID:  req.InstanceID,
+		Scope:       req.Scope,
 		Operations:  []InjectionOp{},
 	}
 
-	// VS Code typically uses workspace scope
+	// Determine config path based on scope
 	var configPath string
-	if req.ProjectPath != "" {
-		configPath = filepath.Join(req.ProjectPath, ".vscode", "mcp.json")
-	} else {
-		cwd, _ := os.Getwd()
-		configPath = filepath.Join(cwd, ".vscode", "mcp.json")
+	switch req.Scope {
+	case "user":
+		configPath = filepath.Join(vscodeUserDir(), "mcp.json")
+	default: // "workspace" is default for VS Code
+		plan.Scope = "workspace"
+		if req.ProjectPath != "" {
+			configPath = filepath.Join(req.ProjectPath, ".vscode", "mcp.json")
+		} else {
+			cwd, _ := os.Getwd()
+			configPath = filepath.Join(cwd, ".vscode", "mcp.json")
+		}
 	}
 
 	if fileExists(configPath) {
@@ -135,8 +144,7 @@ func (a *VSCodeAdapter) PlanInjection(ctx context.Context, req PlanRequest) (*In
 	}
 
 	// Build server entry name
-	serverName := fmt.Sprintf("conduit-%s", req.DisplayName)
-	serverName = strings.ToLower(strings.ReplaceAll(serverName, " ", "-"))
+	serverName := ServerName(req.DisplayName)
 
 	plan.ExpectedPostState = ExpectedState{
 		MCPServers: []string{serverName},
@@ -185,19 +193,16 @@ func (a *VSCodeAdapter) ApplyInjection(ctx context.Context, plan *InjectionPlan)
 
 			// Add Conduit server entry
 			serverName := plan.ExpectedPostState.MCPServers[0]
-			servers[serverName] = map[string]interface{}{
-				"command": "conduit",
-				"args":    []string{"mcp", "stdio", "--instance", plan.InstanceID},
-				"env": map[string]string{
-					"CONDUIT_SOCKET": filepath.Join(conduitDir(), "conduit.sock"),
-				},
-				"_managed_by":  "conduit",
-				"_instance_id": plan.InstanceID,
-			}
+			servers[serverName] = NewServerEntry(plan.InstanceID).managed(plan.InstanceID)
 
-			// Create .vscode directory if needed
+			// Create the config directory if needed; VS Code may never
+			// have been launched
 			if err := ensureDir(filepath.Dir(op.Path)); err != nil {
-				return nil, fmt.Errorf("create .vscode dir: %w", err)
+				return nil, fmt.Errorf("create config dir: %w", err)
+			}
+			if op.Type == "create_file" {
+				// Rollback removes files the binding created
+				a.storeBackup(plan.ChangeSetID, a.ID(), op.Path, "", false)
 			}
 
 			data, _ := json.MarshalIndent(config, "", "  ")
@@ -211,10 +216,44 @@ func (a *VSCodeAdapter) ApplyInjection(ctx context.Context, plan *InjectionPlan)
 	}
 
 	result.Success = true
-	result.ConfigScope = "workspace"
+	result.ConfigScope = plan.Scope
 	return result, nil
 }
 
+// vscodeUserDir returns VS Code's per-user settings directory.
+func vscodeUserDir() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(homeDir(), "Library", "Application Support", "Code", "User")
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "Code", "User")
+	default:
+		return filepath.Join(homeDir(), ".config", "Code", "User")
+	}
+}
+
+// vscodeAppPaths returns where VS Code is installed by default on this OS.
+func vscodeAppPaths() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{
+			"/Applications/Visual Studio Code.app",
+			filepath.Join(homeDir(), "Applications", "Visual Studio Code.app"),
+		}
+	case "windows":
+		return []string{
+			filepath.Join(os.Getenv("LOCALAPPDATA"), "Programs", "Microsoft VS Code", "Code.exe"),
+			filepath.Join(os.Getenv("ProgramFiles"), "Microsoft VS Code", "Code.exe"),
+		}
+	default:
+		return []string{
+			"/usr/share/code/code",
+			"/snap/bin/code",
+			"/var/lib/flatpak/exports/bin/com.visualstudio.code",
+		}
+	}
+}
+
 // Validate validates a binding.
 func (a *VSCodeAdapter) Validate(ctx context.Context, bindingID string) (*ValidationResult, error) {
 	result := &ValidationResult{
@@ -296,6 +335,12 @@ func (a *VSCodeAdapter) Rollback(ctx context.Context, changeSetID string) (*Roll
 	return result, nil
 }
 
+// RemoveInjection removes the server entry a binding added, leaving the rest
+// of the config as it is now.
+func (a *VSCodeAdapter) RemoveInjection(ctx context.Context, binding BindingInfo) (*RollbackResult, error) {
+	return removeServerEntry(binding.ConfigPath, "servers", binding.InstanceID)
+}
+
 // Doctor checks for issues with VS Code configuration.
 func (a *VSCodeAdapter) Doctor(ctx context.Context) ([]DoctorIssue, error) {
 	issues := []DoctorIssue{}
@@ -13,10 +13,69 @@ import (
 )
 
 const (
-	anthropicAPIURL     = "https://api.anthropic.com/v1/messages"
-	anthropicAPIVersion = "2023-06-01"
+	anthropicAPIURL       = "https://api.anthropic.com/v1/messages"
+	anthropicModelsAPIURL = "https://api.anthropic.com/v1/models"
+	anthropicAPIVersion   = "2023-06-01"
 )
 
+// AnthropicModels are the Claude models offered by conduit setup.
+// The first entry is the default.
+var AnthropicModels = []struct {
+	Name        string
+	Description string
+}{
+	{"claude-sonnet-4-20250514", "Recommended balance of capability and cost"},
+	{"claude-opus-4-20250514", "Most capable, higher cost"},
+	{"claude-3-5-haiku-20241022", "Fastest and lowest cost"},
+}
+
+// ValidateAnthropicModel checks that Anthropic accepts the given model name.
+// It returns *ErrUnknownModel if the API does not know the model, and
+// *ErrProviderUnavailable if the check could not be made (e.g. no API key).
+func ValidateAnthropicModel(ctx context.Context, apiKey, model string) error {
+	return validateAnthropicModel(ctx, anthropicModelsAPIURL, apiKey, model)
+}
+
+func validateAnthropicModel(ctx context.Context, modelsURL, apiKey, model string) error {
+	if !strings.HasPrefix(model, "claude-") || strings.ContainsAny(model, " /") {
+		return &ErrUnknownModel{Provider: "anthropic", Model: model}
+	}
+	if apiKey == "" {
+		return &ErrProviderUnavailable{
+			Provider: "anthropic",
+			Reason:   "ANTHROPIC_API_KEY environment variable not set",
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", modelsURL+"/"+model, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return &ErrProviderUnavailable{Provider: "anthropic", Reason: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return &ErrUnknownModel{Provider: "anthropic", Model: model}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &ErrProviderUnavailable{Provider: "anthropic", Reason: "API key was rejected"}
+	default:
+		return &ErrProviderUnavailable{
+			Provider: "anthropic",
+			Reason:   fmt.Sprintf("model lookup returned status %d", resp.StatusCode),
+		}
+	}
+}
+
 // AnthropicProvider implements the Provider interface using Anthropic's Claude API.
 type AnthropicProvider struct {
 	config ProviderConfig
@@ -126,57 +185,46 @@ func (p *AnthropicProvider) chat(ctx context.Context, systemPrompt, userPrompt s
 	req.Header.Set("x-api-key", p.config.APIKey)
 	req.Header.Set("anthropic-version", anthropicAPIVersion)
 
-	var lastErr error
-	for attempt := 0; attempt <= p.config.MaxRetries; attempt++ {
-		resp, err := p.client.Do(req)
-		if err != nil {
-			lastErr = err
-			time.Sleep(time.Second * time.Duration(attempt+1))
-			continue
-		}
-		defer resp.Body.Close()
+	// Retries are handled by the Manager so every attempt is logged
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			lastErr = err
-			continue
-		}
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			var apiErr anthropicError
-			if err := json.Unmarshal(bodyBytes, &apiErr); err == nil {
-				lastErr = fmt.Errorf("Anthropic API error: %s - %s", apiErr.Error.Type, apiErr.Error.Message)
-			} else {
-				lastErr = fmt.Errorf("Anthropic returned status %d: %s", resp.StatusCode, string(bodyBytes))
-			}
+	if resp.StatusCode != http.StatusOK {
+		message := string(bodyBytes)
+		var apiErr anthropicError
+		if err := json.Unmarshal(bodyBytes, &apiErr); err == nil {
+			message = fmt.Sprintf("%s - %s", apiErr.Error.Type, apiErr.Error.Message)
+		}
 
-			// Don't retry on auth errors
-			if resp.StatusCode == 401 || resp.StatusCode == 403 {
-				return "", &ErrProviderUnavailable{
-					Provider: "anthropic",
-					Reason:   lastErr.Error(),
-				}
+		// Auth errors are not retryable
+		if resp.StatusCode == 401 || resp.StatusCode == 403 {
+			return "", &ErrProviderUnavailable{
+				Provider: "anthropic",
+				Reason:   "Anthropic API error: " + message,
 			}
-
-			time.Sleep(time.Second * time.Duration(attempt+1))
-			continue
 		}
 
-		var anthropicResp anthropicResponse
-		if err := json.Unmarshal(bodyBytes, &anthropicResp); err != nil {
-			lastErr = err
-			continue
-		}
+		return "", &ErrAPIStatus{Provider: "Anthropic", StatusCode: resp.StatusCode, Message: message}
+	}
 
-		if len(anthropicResp.Content) == 0 {
-			lastErr = fmt.Errorf("empty response from Anthropic")
-			continue
-		}
+	var anthropicResp anthropicResponse
+	if err := json.Unmarshal(bodyBytes, &anthropicResp); err != nil {
+		return "", err
+	}
 
-		return anthropicResp.Content[0].Text, nil
+	if len(anthropicResp.Content) == 0 {
+		return "", fmt.Errorf("empty response from Anthropic")
 	}
 
-	return "", fmt.Errorf("failed after %d attempts: %w", p.config.MaxRetries+1, lastErr)
+	return anthropicResp.Content[0].Text, nil
 }
 
 // Analyze analyzes an MCP server repository.
@@ -229,6 +277,7 @@ func (p *AnthropicProvider) buildAnalysisPrompt(req AnalysisRequest) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("Analyze this MCP server repository: %s\n\n", req.RepoURL))
+	writeSubdirContext(&sb, req)
 
 	if req.README != "" {
 		sb.WriteString("=== README.md ===\n")
@@ -270,8 +319,6 @@ func (p *AnthropicProvider) buildAnalysisPrompt(req AnalysisRequest) string {
 }
 
 func (p *AnthropicProvider) parseAnalysisResponse(response string) (*AnalysisResponse, error) {
-	response = extractJSON(response)
-
 	var parsed struct {
 		Confidence           float64           `json:"confidence"`
 		Runtime              string            `json:"runtime"`
@@ -288,8 +335,8 @@ func (p *AnthropicProvider) parseAnalysisResponse(response string) (*AnalysisRes
 		Warnings             []string          `json:"warnings"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w\nResponse: %s", err, response)
+	if err := decodeJSON(response, &parsed); err != nil {
+		return nil, err
 	}
 
 	return &AnalysisResponse{
@@ -378,8 +425,6 @@ func (p *AnthropicProvider) buildDockerfilePrompt(req DockerfileRequest) string
 }
 
 func (p *AnthropicProvider) parseDockerfileResponse(response string) (*DockerfileResponse, error) {
-	response = extractJSON(response)
-
 	var parsed struct {
 		Confidence  float64 `json:"confidence"`
 		Dockerfile  string  `json:"dockerfile"`
@@ -401,8 +446,8 @@ func (p *AnthropicProvider) parseDockerfileResponse(response string) (*Dockerfil
 		Warnings []string `json:"warnings"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w\nResponse: %s", err, response)
+	if err := decodeJSON(response, &parsed); err != nil {
+		return nil, err
 	}
 
 	volumes := make([]VolumeMount, len(parsed.Volumes))
@@ -504,8 +549,6 @@ func (p *AnthropicProvider) buildTroubleshootPrompt(req TroubleshootRequest) str
 }
 
 func (p *AnthropicProvider) parseTroubleshootResponse(response string) (*TroubleshootResponse, error) {
-	response = extractJSON(response)
-
 	var parsed struct {
 		Confidence     float64 `json:"confidence"`
 		Diagnosis      string  `json:"diagnosis"`
@@ -519,8 +562,8 @@ func (p *AnthropicProvider) parseTroubleshootResponse(response string) (*Trouble
 		NeedsHumanIntervention bool   `json:"needs_human_intervention"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w\nResponse: %s", err, response)
+	if err := decodeJSON(response, &parsed); err != nil {
+		return nil, err
 	}
 
 	fixes := make([]SuggestedFix, len(parsed.SuggestedFixes))
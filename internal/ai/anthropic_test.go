@@ -351,3 +351,45 @@ func TestAnthropicProvider_ParseTroubleshootResponse(t *testing.T) {
 		t.Error("expected needs_human_intervention to be false")
 	}
 }
+
+func TestValidateAnthropicModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "sk-ant-test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/v1/models/claude-sonnet-4-20250514" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "claude-sonnet-4-20250514", "type": "model"})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	modelsURL := server.URL + "/v1/models"
+
+	if err := validateAnthropicModel(ctx, modelsURL, "sk-ant-test", "claude-sonnet-4-20250514"); err != nil {
+		t.Errorf("expected known model to validate, got %v", err)
+	}
+
+	err := validateAnthropicModel(ctx, modelsURL, "sk-ant-test", "claude-nonexistent")
+	if _, ok := err.(*ErrUnknownModel); !ok {
+		t.Errorf("expected ErrUnknownModel for unknown model, got %v", err)
+	}
+
+	err = validateAnthropicModel(ctx, modelsURL, "sk-ant-test", "gpt-4")
+	if _, ok := err.(*ErrUnknownModel); !ok {
+		t.Errorf("expected ErrUnknownModel for non-Claude name, got %v", err)
+	}
+
+	err = validateAnthropicModel(ctx, modelsURL, "", "claude-sonnet-4-20250514")
+	if _, ok := err.(*ErrProviderUnavailable); !ok {
+		t.Errorf("expected ErrProviderUnavailable without API key, got %v", err)
+	}
+
+	err = validateAnthropicModel(ctx, modelsURL, "bad-key", "claude-sonnet-4-20250514")
+	if _, ok := err.(*ErrProviderUnavailable); !ok {
+		t.Errorf("expected ErrProviderUnavailable for rejected key, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CachedAnalysis is a stored AI result for one repository commit.
+type CachedAnalysis struct {
+	RepoURL      string              `json:"repo_url"`
+	CommitSHA    string              `json:"commit_sha"`
+	Provider     string              `json:"provider"`
+	Model        string              `json:"model"`
+	Analysis     *AnalysisResponse   `json:"analysis"`
+	DockerConfig *DockerfileResponse `json:"docker_config,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// AnalysisCache stores AI analysis results on disk, keyed by repository URL
+// and resolved commit SHA, so unchanged repositories are not re-analyzed.
+type AnalysisCache struct {
+	dir string
+}
+
+// NewAnalysisCache creates a cache rooted at dir.
+func NewAnalysisCache(dir string) *AnalysisCache {
+	return &AnalysisCache{dir: dir}
+}
+
+// Get returns the cached entry for a repository commit, if present.
+func (c *AnalysisCache) Get(repoURL, commitSHA string) (*CachedAnalysis, bool) {
+	if commitSHA == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.path(repoURL, commitSHA))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry CachedAnalysis
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Analysis == nil {
+		return nil, false
+	}
+	// Guard against hash collisions and hand-edited files
+	if entry.RepoURL != repoURL || entry.CommitSHA != commitSHA {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Put stores an entry, replacing any existing one for the same commit.
+func (c *AnalysisCache) Put(entry *CachedAnalysis) error {
+	if entry.CommitSHA == "" {
+		return fmt.Errorf("commit SHA required")
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so a crash never leaves a truncated entry
+	path := c.path(entry.RepoURL, entry.CommitSHA)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// path returns the file for a repository commit.
+func (c *AnalysisCache) path(repoURL, commitSHA string) string {
+	sum := sha256.Sum256([]byte(repoURL + "@" + commitSHA))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"testing"
+)
+
+func TestAnalysisCache_PutGet(t *testing.T) {
+	cache := NewAnalysisCache(t.TempDir())
+	repo := "https://github.com/example/mcp-server"
+
+	if _, ok := cache.Get(repo, "abc123"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+
+	err := cache.Put(&CachedAnalysis{
+		RepoURL:   repo,
+		CommitSHA: "abc123",
+		Provider:  "ollama",
+		Model:     "qwen2.5-coder:7b",
+		Analysis:  &AnalysisResponse{Runtime: "nodejs", Confidence: 0.9},
+	})
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	entry, ok := cache.Get(repo, "abc123")
+	if !ok {
+		t.Fatal("expected hit after Put")
+	}
+	if entry.Analysis.Runtime != "nodejs" || entry.CreatedAt.IsZero() {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	if _, ok := cache.Get(repo, "def456"); ok {
+		t.Error("expected miss for a different commit")
+	}
+	if _, ok := cache.Get(repo, ""); ok {
+		t.Error("expected miss without a commit SHA")
+	}
+	if err := cache.Put(&CachedAnalysis{RepoURL: repo}); err == nil {
+		t.Error("expected error when storing without a commit SHA")
+	}
+}
+
+func TestManager_CachedEntry(t *testing.T) {
+	config := DefaultProviderConfig()
+	manager, err := NewManager(config, t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fetch := &FetchResult{RepoURL: "https://github.com/example/mcp-server", CommitSHA: "abc123"}
+	manager.cache.Put(&CachedAnalysis{
+		RepoURL:   fetch.RepoURL,
+		CommitSHA: fetch.CommitSHA,
+		Provider:  "ollama",
+		Model:     config.Model,
+		Analysis:  &AnalysisResponse{Runtime: "python"},
+	})
+
+	if _, ok := manager.cachedEntry(fetch); !ok {
+		t.Error("expected cache hit for matching provider and model")
+	}
+
+	manager.SetNoCache(true)
+	if _, ok := manager.cachedEntry(fetch); ok {
+		t.Error("expected miss with caching disabled")
+	}
+	manager.SetNoCache(false)
+
+	manager.config.Model = "llama3.1:8b"
+	if _, ok := manager.cachedEntry(fetch); ok {
+		t.Error("expected miss when the model changed")
+	}
+}
@@ -0,0 +1,266 @@
+package ai
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dockerfile issue severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// DockerfileIssue is a problem found in a generated Dockerfile.
+type DockerfileIssue struct {
+	// Line is the 1-based line where the instruction starts (0 for file-level issues).
+	Line int
+
+	// Severity is SeverityError (the build will fail) or SeverityWarning.
+	Severity string
+
+	// Message describes the problem.
+	Message string
+}
+
+func (i DockerfileIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+	return i.Message
+}
+
+// Confidence penalties applied per validation issue.
+const (
+	dockerfileErrorPenalty   = 0.2
+	dockerfileWarningPenalty = 0.05
+)
+
+// dockerfileInstructions are the instructions the Dockerfile parser accepts.
+var dockerfileInstructions = map[string]bool{
+	"FROM": true, "RUN": true, "CMD": true, "LABEL": true, "MAINTAINER": true,
+	"EXPOSE": true, "ENV": true, "ADD": true, "COPY": true, "ENTRYPOINT": true,
+	"VOLUME": true, "USER": true, "WORKDIR": true, "ARG": true, "ONBUILD": true,
+	"STOPSIGNAL": true, "HEALTHCHECK": true, "SHELL": true,
+}
+
+// dockerfileInstruction is one logical instruction (continuations joined).
+type dockerfileInstruction struct {
+	line int
+	cmd  string
+	args string
+}
+
+// ValidateDockerfile performs lightweight static checks on a Dockerfile so
+// malformed AI output is caught before a build is attempted. It checks for a
+// FROM instruction, unknown instructions, missing arguments, and multi-stage
+// references to stages that do not exist.
+func ValidateDockerfile(content string) []DockerfileIssue {
+	var issues []DockerfileIssue
+	addError := func(line int, format string, args ...interface{}) {
+		issues = append(issues, DockerfileIssue{Line: line, Severity: SeverityError, Message: fmt.Sprintf(format, args...)})
+	}
+	addWarning := func(line int, format string, args ...interface{}) {
+		issues = append(issues, DockerfileIssue{Line: line, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+	}
+
+	instructions, unterminated := parseDockerfile(content)
+	if len(instructions) == 0 {
+		addError(0, "Dockerfile is empty")
+		return issues
+	}
+	if unterminated > 0 {
+		addError(unterminated, "line continuation at end of file")
+	}
+
+	stages := map[string]int{} // stage name -> index
+	stageCount := 0
+	sawFrom, reportedBeforeFrom := false, false
+	finalCmds, finalEntrypoints := 0, 0
+
+	for _, inst := range instructions {
+		if strings.HasPrefix(inst.cmd, "```") {
+			addError(inst.line, "contains a markdown code fence")
+			continue
+		}
+		if !dockerfileInstructions[inst.cmd] {
+			addError(inst.line, "unknown instruction %q", inst.cmd)
+			continue
+		}
+
+		if !sawFrom && inst.cmd != "FROM" && inst.cmd != "ARG" {
+			if !reportedBeforeFrom {
+				addError(inst.line, "%s before the first FROM", inst.cmd)
+				reportedBeforeFrom = true
+			}
+			continue
+		}
+
+		if inst.args == "" {
+			addError(inst.line, "%s requires arguments", inst.cmd)
+			continue
+		}
+
+		switch inst.cmd {
+		case "FROM":
+			sawFrom = true
+			finalCmds, finalEntrypoints = 0, 0
+
+			fields := strings.Fields(inst.args)
+			for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+				fields = fields[1:] // --platform=...
+			}
+			switch {
+			case len(fields) == 0:
+				addError(inst.line, "FROM requires an image")
+			case len(fields) == 2 || len(fields) > 3 || len(fields) == 3 && !strings.EqualFold(fields[1], "AS"):
+				addError(inst.line, "malformed FROM (expected 'FROM image [AS name]')")
+			case len(fields) == 3:
+				name := strings.ToLower(fields[2])
+				if _, dup := stages[name]; dup {
+					addError(inst.line, "duplicate stage name %q", fields[2])
+				}
+				stages[name] = stageCount
+			}
+			stageCount++
+
+		case "COPY", "ADD":
+			var from string
+			paths := 0
+			for _, f := range strings.Fields(inst.args) {
+				if strings.HasPrefix(f, "--from=") {
+					from = strings.TrimPrefix(f, "--from=")
+				} else if !strings.HasPrefix(f, "--") {
+					paths++
+				}
+			}
+			if paths < 2 && !strings.HasPrefix(strings.TrimSpace(inst.args), "[") {
+				addError(inst.line, "%s requires a source and a destination", inst.cmd)
+			}
+			if from != "" {
+				checkStageReference(from, stages, stageCount, inst.line, addError, addWarning)
+			}
+
+		case "EXPOSE":
+			for _, port := range strings.Fields(inst.args) {
+				port = strings.SplitN(port, "/", 2)[0]
+				if strings.HasPrefix(port, "$") {
+					continue
+				}
+				if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+					addError(inst.line, "invalid port %q in EXPOSE", port)
+				}
+			}
+
+		case "CMD":
+			finalCmds++
+		case "ENTRYPOINT":
+			finalEntrypoints++
+		}
+	}
+
+	if !sawFrom {
+		addError(0, "no FROM instruction")
+		return issues
+	}
+	if finalCmds > 1 {
+		addWarning(0, "multiple CMD instructions in the final stage; only the last takes effect")
+	}
+	if finalEntrypoints > 1 {
+		addWarning(0, "multiple ENTRYPOINT instructions in the final stage; only the last takes effect")
+	}
+	if finalCmds == 0 && finalEntrypoints == 0 {
+		addWarning(0, "final stage has no CMD or ENTRYPOINT; the container relies on the base image's default command")
+	}
+
+	return issues
+}
+
+// checkStageReference validates a COPY --from reference against the stages
+// defined so far. Names that match no stage may be image references.
+func checkStageReference(from string, stages map[string]int, stageCount, line int,
+	addError, addWarning func(int, string, ...interface{})) {
+	if n, err := strconv.Atoi(from); err == nil {
+		// The current stage cannot copy from itself
+		if n < 0 || n >= stageCount-1 {
+			addError(line, "COPY --from=%d refers to a stage that is not defined before this one", n)
+		}
+		return
+	}
+
+	if _, ok := stages[strings.ToLower(from)]; ok {
+		return
+	}
+	if !strings.ContainsAny(from, "/:.$") {
+		addWarning(line, "COPY --from=%s does not match any earlier stage; it will be pulled as an image", from)
+	}
+}
+
+// parseDockerfile splits a Dockerfile into logical instructions, joining
+// continuation lines and skipping comments. It returns the line of an
+// unterminated continuation, or 0.
+func parseDockerfile(content string) ([]dockerfileInstruction, int) {
+	var instructions []dockerfileInstruction
+	var current strings.Builder
+	startLine := 0
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if startLine == 0 {
+			startLine = i + 1
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			current.WriteString(strings.TrimSuffix(line, "\\"))
+			current.WriteString(" ")
+			continue
+		}
+		current.WriteString(line)
+
+		text := current.String()
+		current.Reset()
+		cmd, args, _ := strings.Cut(text, " ")
+		instructions = append(instructions, dockerfileInstruction{
+			line: startLine,
+			cmd:  strings.ToUpper(cmd),
+			args: strings.TrimSpace(args),
+		})
+		startLine = 0
+	}
+
+	if current.Len() > 0 {
+		return instructions, startLine
+	}
+	return instructions, 0
+}
+
+// applyDockerfileValidation validates the generated Dockerfile, records the
+// issues on the response and lowers its confidence accordingly.
+func applyDockerfileValidation(resp *DockerfileResponse) {
+	resp.Issues = ValidateDockerfile(resp.Dockerfile)
+	for _, issue := range resp.Issues {
+		if issue.Severity == SeverityError {
+			resp.Confidence -= dockerfileErrorPenalty
+		} else {
+			resp.Confidence -= dockerfileWarningPenalty
+		}
+	}
+	if resp.Confidence < 0 {
+		resp.Confidence = 0
+	}
+}
+
+// HasDockerfileErrors reports whether any issue would make the build fail.
+func HasDockerfileErrors(issues []DockerfileIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDockerfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		wantErrors []string
+		wantWarn   []string
+	}{
+		{
+			name: "valid multi-stage",
+			dockerfile: `# build
+ARG NODE_VERSION=20
+FROM node:${NODE_VERSION} AS builder
+WORKDIR /app
+COPY package*.json ./
+RUN npm ci && \
+    npm run build
+FROM node:20-slim
+COPY --from=builder /app/build /app
+EXPOSE 8080/tcp
+CMD ["node", "/app/index.js"]`,
+		},
+		{
+			name:       "empty",
+			dockerfile: "  \n# just a comment\n",
+			wantErrors: []string{"empty"},
+		},
+		{
+			name:       "missing FROM",
+			dockerfile: "RUN echo hi\nCMD [\"sh\"]",
+			wantErrors: []string{"before the first FROM", "no FROM"},
+		},
+		{
+			name:       "markdown fence",
+			dockerfile: "```dockerfile\nFROM alpine\nCMD [\"sh\"]\n```",
+			wantErrors: []string{"markdown code fence", "markdown code fence"},
+		},
+		{
+			name:       "unknown stage index",
+			dockerfile: "FROM golang:1.22 AS build\nFROM alpine\nCOPY --from=2 /out /out\nCMD [\"/out\"]",
+			wantErrors: []string{"--from=2"},
+		},
+		{
+			name:       "unknown stage name",
+			dockerfile: "FROM alpine\nCOPY --from=bulder /out /out\nCMD [\"/out\"]",
+			wantWarn:   []string{"does not match any earlier stage"},
+		},
+		{
+			name:       "duplicate stage and unknown instruction",
+			dockerfile: "FROM alpine AS a\nFROM alpine AS a\nINSTALL curl\nCMD [\"sh\"]",
+			wantErrors: []string{"duplicate stage", "unknown instruction"},
+		},
+		{
+			name:       "missing arguments and bad port",
+			dockerfile: "FROM alpine\nCOPY app.js\nEXPOSE http\nWORKDIR\nCMD [\"sh\"]",
+			wantErrors: []string{"source and a destination", "invalid port", "WORKDIR requires arguments"},
+		},
+		{
+			name:       "no command and dangling continuation",
+			dockerfile: "FROM alpine\nRUN apk add curl \\",
+			wantErrors: []string{"continuation"},
+			wantWarn:   []string{"no CMD or ENTRYPOINT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := ValidateDockerfile(tt.dockerfile)
+
+			var errs, warns []string
+			for _, issue := range issues {
+				if issue.Severity == SeverityError {
+					errs = append(errs, issue.String())
+				} else {
+					warns = append(warns, issue.String())
+				}
+			}
+
+			check := func(kind string, got, want []string) {
+				if len(got) != len(want) {
+					t.Errorf("expected %d %s, got %v", len(want), kind, got)
+					return
+				}
+				for i, w := range want {
+					if !strings.Contains(got[i], w) {
+						t.Errorf("%s %d: expected %q in %q", kind, i, w, got[i])
+					}
+				}
+			}
+			check("errors", errs, tt.wantErrors)
+			check("warnings", warns, tt.wantWarn)
+
+			if HasDockerfileErrors(issues) != (len(tt.wantErrors) > 0) {
+				t.Errorf("HasDockerfileErrors mismatch")
+			}
+		})
+	}
+}
+
+func TestApplyDockerfileValidation(t *testing.T) {
+	resp := &DockerfileResponse{
+		Confidence: 0.9,
+		Dockerfile: "RUN echo hi",
+	}
+	applyDockerfileValidation(resp)
+
+	if len(resp.Issues) == 0 {
+		t.Fatal("expected issues")
+	}
+	if resp.Confidence >= 0.9 {
+		t.Errorf("expected confidence to be lowered, got %.2f", resp.Confidence)
+	}
+	if resp.Confidence < 0 {
+		t.Errorf("confidence should not go negative, got %.2f", resp.Confidence)
+	}
+}
@@ -15,6 +15,8 @@ type Manager struct {
 	provider  Provider
 	fetcher   *RepoFetcher
 	cacheDir  string
+	cache     *AnalysisCache
+	noCache   bool
 }
 
 // NewManager creates a new AI manager.
@@ -25,6 +27,7 @@ func NewManager(config ProviderConfig, dataDir string) (*Manager, error) {
 		config:   config,
 		cacheDir: cacheDir,
 		fetcher:  NewRepoFetcher(cacheDir),
+		cache:    NewAnalysisCache(filepath.Join(dataDir, "analysis-cache")),
 	}
 
 	// Initialize provider based on config
@@ -54,6 +57,30 @@ func (m *Manager) SetProvider(provider string) error {
 	return m.initProvider()
 }
 
+// SetNoCache disables reading cached analysis results, forcing re-analysis.
+// Fresh results are still written to the cache.
+func (m *Manager) SetNoCache(noCache bool) {
+	m.noCache = noCache
+}
+
+// SetRepoToken sets the token used to clone private repositories over HTTPS.
+func (m *Manager) SetRepoToken(token string) {
+	m.fetcher.Token = token
+}
+
+// cachedEntry returns the cached analysis for a fetched commit, if it was
+// produced by the current provider and model.
+func (m *Manager) cachedEntry(fetchResult *FetchResult) (*CachedAnalysis, bool) {
+	if m.noCache {
+		return nil, false
+	}
+	entry, ok := m.cache.Get(fetchResult.cacheKey(), fetchResult.CommitSHA)
+	if !ok || entry.Provider != m.provider.Name() || entry.Model != m.config.Model {
+		return nil, false
+	}
+	return entry, true
+}
+
 // CheckAvailability checks if the current provider is available.
 func (m *Manager) CheckAvailability(ctx context.Context) (bool, error) {
 	return m.provider.IsAvailable(ctx)
@@ -104,11 +131,29 @@ func (m *Manager) AnalyzeRepository(ctx context.Context, repoURL string) (*Fetch
 	log.Info().
 		Str("name", fetchResult.RepoName).
 		Str("owner", fetchResult.Owner).
-		Msg("Repository fetched, analyzing with AI")
+		Str("subdir", fetchResult.Subdir).
+		Str("commit", fetchResult.CommitSHA).
+		Msg("Repository fetched")
+
+	if entry, ok := m.cachedEntry(fetchResult); ok {
+		log.Info().
+			Str("commit", fetchResult.CommitSHA).
+			Time("cached_at", entry.CreatedAt).
+			Msg("Using cached analysis")
+		fetchResult.Cached = true
+		return fetchResult, entry.Analysis, nil
+	}
+
+	log.Info().Msg("Analyzing with AI")
 
 	// Analyze with AI
 	analysisReq := fetchResult.ToAnalysisRequest()
-	analysis, err := m.provider.Analyze(ctx, analysisReq)
+	var analysis *AnalysisResponse
+	err = m.withRetry(ctx, "analyze", func() error {
+		var err error
+		analysis, err = m.provider.Analyze(ctx, analysisReq)
+		return err
+	})
 	if err != nil {
 		m.fetcher.Cleanup(fetchResult)
 		return nil, nil, fmt.Errorf("analyze repository: %w", err)
@@ -120,6 +165,18 @@ func (m *Manager) AnalyzeRepository(ctx context.Context, repoURL string) (*Fetch
 		Str("transport", analysis.Transport).
 		Msg("Analysis complete")
 
+	if fetchResult.CommitSHA != "" {
+		if err := m.cache.Put(&CachedAnalysis{
+			RepoURL:   fetchResult.cacheKey(),
+			CommitSHA: fetchResult.CommitSHA,
+			Provider:  m.provider.Name(),
+			Model:     m.config.Model,
+			Analysis:  analysis,
+		}); err != nil {
+			log.Warn().Err(err).Msg("Failed to cache analysis")
+		}
+	}
+
 	// Check confidence threshold
 	if analysis.Confidence < m.config.ConfidenceThreshold {
 		log.Warn().
@@ -133,29 +190,57 @@ func (m *Manager) AnalyzeRepository(ctx context.Context, repoURL string) (*Fetch
 
 // GenerateContainerConfig generates a Dockerfile and container configuration.
 func (m *Manager) GenerateContainerConfig(ctx context.Context, fetchResult *FetchResult, analysis *AnalysisResponse) (*DockerfileResponse, error) {
+	entry, cached := m.cachedEntry(fetchResult)
+	if cached && entry.DockerConfig != nil {
+		log.Info().Str("commit", fetchResult.CommitSHA).Msg("Using cached container configuration")
+		return entry.DockerConfig, nil
+	}
+
 	log.Info().Msg("Generating Dockerfile and container configuration")
 
 	dockerReq := DockerfileRequest{
-		Analysis: analysis,
-		RepoURL:  fetchResult.RepoURL,
+		Analysis:          analysis,
+		RepoURL:           fetchResult.RepoURL,
+		AdditionalContext: fetchResult.buildContextNote(),
 	}
 
-	dockerConfig, err := m.provider.GenerateDockerfile(ctx, dockerReq)
+	var dockerConfig *DockerfileResponse
+	err := m.withRetry(ctx, "generate_dockerfile", func() error {
+		var err error
+		dockerConfig, err = m.provider.GenerateDockerfile(ctx, dockerReq)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("generate dockerfile: %w", err)
 	}
 
+	applyDockerfileValidation(dockerConfig)
+
 	log.Info().
 		Float64("confidence", dockerConfig.Confidence).
 		Int("volumes", len(dockerConfig.Volumes)).
+		Int("issues", len(dockerConfig.Issues)).
 		Msg("Container configuration generated")
 
+	if fetchResult.CommitSHA != "" {
+		if err := m.cache.Put(&CachedAnalysis{
+			RepoURL:      fetchResult.cacheKey(),
+			CommitSHA:    fetchResult.CommitSHA,
+			Provider:     m.provider.Name(),
+			Model:        m.config.Model,
+			Analysis:     analysis,
+			DockerConfig: dockerConfig,
+		}); err != nil {
+			log.Warn().Err(err).Msg("Failed to cache container configuration")
+		}
+	}
+
 	return dockerConfig, nil
 }
 
-// WriteDockerfile writes the Dockerfile to disk.
+// WriteDockerfile writes the Dockerfile into the build context directory.
 func (m *Manager) WriteDockerfile(fetchResult *FetchResult, dockerfile string) (string, error) {
-	dockerfilePath := filepath.Join(fetchResult.LocalPath, "Dockerfile.conduit")
+	dockerfilePath := filepath.Join(fetchResult.ContextDir(), "Dockerfile.conduit")
 
 	if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
 		return "", fmt.Errorf("write dockerfile: %w", err)
@@ -179,7 +264,12 @@ func (m *Manager) Troubleshoot(ctx context.Context, analysis *AnalysisResponse,
 		PreviousAttempts: previousAttempts,
 	}
 
-	result, err := m.provider.Troubleshoot(ctx, troubleshootReq)
+	var result *TroubleshootResponse
+	err := m.withRetry(ctx, "troubleshoot", func() error {
+		var err error
+		result, err = m.provider.Troubleshoot(ctx, troubleshootReq)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("troubleshoot: %w", err)
 	}
@@ -80,6 +80,46 @@ func (p *OllamaProvider) IsAvailable(ctx context.Context) (bool, error) {
 	}
 }
 
+// ListOllamaModels returns the names of the models installed in Ollama.
+func ListOllamaModels(ctx context.Context, endpoint string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, &ErrProviderUnavailable{
+			Provider: "ollama",
+			Reason:   fmt.Sprintf("cannot connect to Ollama at %s: %v", endpoint, err),
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ErrProviderUnavailable{
+			Provider: "ollama",
+			Reason:   fmt.Sprintf("Ollama returned status %d", resp.StatusCode),
+		}
+	}
+
+	var tagsResp struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tagsResp); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(tagsResp.Models))
+	for _, m := range tagsResp.Models {
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
+
 // ollamaRequest is the request body for Ollama API.
 type ollamaRequest struct {
 	Model    string                   `json:"model"`
@@ -136,31 +176,24 @@ func (p *OllamaProvider) chat(ctx context.Context, systemPrompt, userPrompt stri
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	var lastErr error
-	for attempt := 0; attempt <= p.config.MaxRetries; attempt++ {
-		resp, err := p.client.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			lastErr = fmt.Errorf("Ollama returned status %d: %s", resp.StatusCode, string(bodyBytes))
-			continue
-		}
+	// Retries are handled by the Manager so every attempt is logged
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
 
-		var ollamaResp ollamaResponse
-		if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-			lastErr = err
-			continue
-		}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", &ErrAPIStatus{Provider: "Ollama", StatusCode: resp.StatusCode, Message: string(bodyBytes)}
+	}
 
-		return ollamaResp.Message.Content, nil
+	var ollamaResp ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return "", err
 	}
 
-	return "", fmt.Errorf("failed after %d attempts: %w", p.config.MaxRetries+1, lastErr)
+	return ollamaResp.Message.Content, nil
 }
 
 // Analyze analyzes an MCP server repository.
@@ -208,6 +241,7 @@ func (p *OllamaProvider) buildAnalysisPrompt(req AnalysisRequest) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("Analyze this MCP server repository: %s\n\n", req.RepoURL))
+	writeSubdirContext(&sb, req)
 
 	if req.README != "" {
 		sb.WriteString("=== README.md ===\n")
@@ -249,9 +283,6 @@ func (p *OllamaProvider) buildAnalysisPrompt(req AnalysisRequest) string {
 }
 
 func (p *OllamaProvider) parseAnalysisResponse(response string) (*AnalysisResponse, error) {
-	// Try to extract JSON from the response
-	response = extractJSON(response)
-
 	var parsed struct {
 		Confidence           float64           `json:"confidence"`
 		Runtime              string            `json:"runtime"`
@@ -268,8 +299,8 @@ func (p *OllamaProvider) parseAnalysisResponse(response string) (*AnalysisRespon
 		Warnings             []string          `json:"warnings"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w\nResponse: %s", err, response)
+	if err := decodeJSON(response, &parsed); err != nil {
+		return nil, err
 	}
 
 	return &AnalysisResponse{
@@ -354,8 +385,6 @@ func (p *OllamaProvider) buildDockerfilePrompt(req DockerfileRequest) string {
 }
 
 func (p *OllamaProvider) parseDockerfileResponse(response string) (*DockerfileResponse, error) {
-	response = extractJSON(response)
-
 	var parsed struct {
 		Confidence  float64 `json:"confidence"`
 		Dockerfile  string  `json:"dockerfile"`
@@ -377,8 +406,8 @@ func (p *OllamaProvider) parseDockerfileResponse(response string) (*DockerfileRe
 		Warnings []string `json:"warnings"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w\nResponse: %s", err, response)
+	if err := decodeJSON(response, &parsed); err != nil {
+		return nil, err
 	}
 
 	volumes := make([]VolumeMount, len(parsed.Volumes))
@@ -471,8 +500,6 @@ func (p *OllamaProvider) buildTroubleshootPrompt(req TroubleshootRequest) string
 }
 
 func (p *OllamaProvider) parseTroubleshootResponse(response string) (*TroubleshootResponse, error) {
-	response = extractJSON(response)
-
 	var parsed struct {
 		Confidence      float64 `json:"confidence"`
 		Diagnosis       string  `json:"diagnosis"`
@@ -486,8 +513,8 @@ func (p *OllamaProvider) parseTroubleshootResponse(response string) (*Troublesho
 		NeedsHumanIntervention bool   `json:"needs_human_intervention"`
 	}
 
-	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
-		return nil, fmt.Errorf("failed to parse AI response: %w\nResponse: %s", err, response)
+	if err := decodeJSON(response, &parsed); err != nil {
+		return nil, err
 	}
 
 	fixes := make([]SuggestedFix, len(parsed.SuggestedFixes))
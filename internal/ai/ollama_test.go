@@ -3,9 +3,11 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestOllamaProvider_Name(t *testing.T) {
@@ -226,6 +228,36 @@ func TestOllamaProvider_GenerateDockerfile_Success(t *testing.T) {
 	}
 }
 
+func TestOllamaProvider_Analyze_Cancelled(t *testing.T) {
+	// Mock server that never answers, like a model still generating
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	config := DefaultProviderConfig()
+	config.Endpoint = server.URL
+	config.TimeoutSeconds = 60
+	provider := NewOllamaProvider(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := provider.Analyze(ctx, AnalysisRequest{RepoURL: "https://github.com/test/repo"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request took %v to stop after cancellation", elapsed)
+	}
+}
+
 func TestOllamaProvider_ParseAnalysisResponse_InvalidJSON(t *testing.T) {
 	provider := NewOllamaProvider(DefaultProviderConfig())
 
@@ -255,3 +287,27 @@ func TestOllamaProvider_ParseAnalysisResponse_ExtractFromText(t *testing.T) {
 		t.Errorf("expected confidence 0.75, got %f", analysis.Confidence)
 	}
 }
+
+func TestListOllamaModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tags" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"models": []map[string]string{
+					{"name": "qwen2.5-coder:7b"},
+					{"name": "llama3.2:3b"},
+				},
+			})
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	models, err := ListOllamaModels(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 2 || models[0] != "qwen2.5-coder:7b" || models[1] != "llama3.2:3b" {
+		t.Errorf("unexpected models: %v", models)
+	}
+}
@@ -5,6 +5,7 @@ package ai
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // Provider defines the interface for AI providers.
@@ -30,6 +31,13 @@ type AnalysisRequest struct {
 	// RepoURL is the GitHub/GitLab URL of the MCP server.
 	RepoURL string
 
+	// Subdir is the server's path within a monorepo; the files below come
+	// from that directory.
+	Subdir string
+
+	// RootFiles are root-level lockfiles the subdirectory depends on.
+	RootFiles []string
+
 	// README content from the repository.
 	README string
 
@@ -132,6 +140,9 @@ type DockerfileResponse struct {
 	// Warnings about the generated config.
 	Warnings []string
 
+	// Issues found by ValidateDockerfile; each one lowers Confidence.
+	Issues []DockerfileIssue
+
 	// RawResponse for debugging.
 	RawResponse string
 }
@@ -240,7 +251,8 @@ type ProviderConfig struct {
 	// Timeout for API calls.
 	TimeoutSeconds int `mapstructure:"timeout_seconds"`
 
-	// MaxRetries for failed requests.
+	// MaxRetries is how many times a failed or unparseable AI request is
+	// retried, with exponential backoff between attempts.
 	MaxRetries int `mapstructure:"max_retries"`
 
 	// ConfidenceThreshold below which to warn user.
@@ -280,3 +292,26 @@ type ErrProviderUnavailable struct {
 func (e *ErrProviderUnavailable) Error() string {
 	return fmt.Sprintf("AI provider %s is unavailable: %s", e.Provider, e.Reason)
 }
+
+// ErrUnknownModel is returned when the AI provider does not recognize a model name.
+type ErrUnknownModel struct {
+	Provider string
+	Model    string
+}
+
+func (e *ErrUnknownModel) Error() string {
+	return fmt.Sprintf("model %q is not recognized by %s", e.Model, e.Provider)
+}
+
+// writeSubdirContext adds the monorepo location of a server to an analysis
+// prompt, so commands are given relative to that directory.
+func writeSubdirContext(sb *strings.Builder, req AnalysisRequest) {
+	if req.Subdir == "" {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("The server is in the %s subdirectory of a monorepo; the files below are from that directory.\n", req.Subdir))
+	if len(req.RootFiles) > 0 {
+		sb.WriteString(fmt.Sprintf("The repository root also has %s, which dependency installation uses.\n", strings.Join(req.RootFiles, ", ")))
+	}
+	sb.WriteString("\n")
+}
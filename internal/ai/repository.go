@@ -2,11 +2,15 @@ package ai
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -14,6 +18,12 @@ import (
 type RepoFetcher struct {
 	// CacheDir is where repos are cloned temporarily.
 	CacheDir string
+
+	// Token authenticates HTTPS clones of private repositories. It is passed
+	// to git through the environment, so it never appears in the clone's
+	// .git/config, the command line or log output. SSH remotes ignore it and
+	// use the user's SSH keys.
+	Token string
 }
 
 // NewRepoFetcher creates a new repository fetcher.
@@ -37,6 +47,24 @@ type FetchResult struct {
 	// Owner is the repository owner/organization.
 	Owner string
 
+	// CommitSHA is the resolved commit that was cloned.
+	CommitSHA string
+
+	// Ref is the branch or tag named in the URL, if any.
+	Ref string
+
+	// Subdir is the slash-separated path of the MCP server within the
+	// repository, for servers that live in a monorepo. Empty for the root.
+	Subdir string
+
+	// RootFiles lists lockfiles and workspace manifests at the repository
+	// root that the subdirectory's build depends on. When set, the build
+	// context is the whole repository rather than the subdirectory.
+	RootFiles []string
+
+	// Cached is true when the analysis for this commit came from the cache.
+	Cached bool
+
 	// Files contains extracted file contents.
 	Files ExtractedFiles
 }
@@ -52,10 +80,33 @@ type ExtractedFiles struct {
 	SourceFiles     map[string]string // Key source files
 }
 
-// Fetch clones a repository and extracts relevant files.
+// rootDependencyFiles are the root-level files a monorepo package may need
+// in order to install its dependencies.
+var rootDependencyFiles = []string{
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"pnpm-workspace.yaml",
+	"bun.lockb",
+	"uv.lock",
+	"poetry.lock",
+	"Cargo.lock",
+	"go.work",
+}
+
+// Fetch clones a repository and extracts relevant files. A URL with a path
+// beyond the repository, such as github.com/owner/repo/src/server or
+// https://github.com/owner/repo/tree/main/src/server, scopes the analysis
+// to that subdirectory.
 func (f *RepoFetcher) Fetch(ctx context.Context, repoURL string) (*FetchResult, error) {
+	repo, ref, subdir, err := splitRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
 	// Normalize the URL
-	normalizedURL, owner, name, err := parseRepoURL(repoURL)
+	normalizedURL, owner, name, err := parseRepoURL(repo)
 	if err != nil {
 		return nil, err
 	}
@@ -65,34 +116,243 @@ func (f *RepoFetcher) Fetch(ctx context.Context, repoURL string) (*FetchResult,
 		return nil, fmt.Errorf("create cache dir: %w", err)
 	}
 
-	// Clone to a temp directory
-	localPath := filepath.Join(f.CacheDir, fmt.Sprintf("%s-%s", owner, name))
+	// Clone to a temp directory. Nested GitLab groups put "/" in the owner.
+	localPath := filepath.Join(f.CacheDir, fmt.Sprintf("%s-%s", strings.ReplaceAll(owner, "/", "-"), name))
 
 	// Remove if exists (fresh clone)
 	os.RemoveAll(localPath)
 
-	// Clone the repository
-	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", normalizedURL, localPath)
+	// Clone the repository. SSH remotes are cloned as given so the user's
+	// SSH keys apply; everything else goes over HTTPS.
+	remote := normalizedURL
+	if isSSHURL(repo) {
+		remote = sshCloneURL(repo)
+	}
+	env := append(os.Environ(), f.gitAuthEnv(normalizedURL, remote)...)
+	if ref != "" && subdir != "" {
+		ref, subdir = splitRef(f.remoteRefs(ctx, remote, env), ref, subdir)
+	}
+	args := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	cmd := exec.CommandContext(ctx, "git", append(args, remote, localPath)...)
+	cmd.Env = env
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return nil, fmt.Errorf("git clone failed: %w", err)
 	}
 
+	// Only the server's own directory is analyzed
+	serverPath := localPath
+	var rootFiles []string
+	if subdir != "" {
+		serverPath = filepath.Join(localPath, filepath.FromSlash(subdir))
+		if info, err := os.Stat(serverPath); err != nil || !info.IsDir() {
+			os.RemoveAll(localPath)
+			return nil, fmt.Errorf("subdirectory %s not found in %s/%s", subdir, owner, name)
+		}
+		rootFiles = findRootDependencies(localPath, serverPath)
+	}
+
 	// Extract relevant files
-	files, err := f.extractFiles(localPath)
+	files, err := f.extractFiles(serverPath)
 	if err != nil {
 		return nil, fmt.Errorf("extract files: %w", err)
 	}
 
+	// Resolve the commit so analysis results can be cached per commit
+	var commitSHA string
+	if out, err := exec.CommandContext(ctx, "git", "-C", localPath, "rev-parse", "HEAD").Output(); err == nil {
+		commitSHA = strings.TrimSpace(string(out))
+	}
+
 	return &FetchResult{
 		LocalPath: localPath,
 		RepoURL:   normalizedURL,
 		RepoName:  name,
 		Owner:     owner,
+		CommitSHA: commitSHA,
+		Ref:       ref,
+		Subdir:    subdir,
+		RootFiles: rootFiles,
 		Files:     files,
 	}, nil
 }
 
+// findRootDependencies returns the root dependency files present at the
+// repository root but not in the server's directory, which the server then
+// relies on to install its dependencies (npm/pnpm/yarn workspaces, uv or
+// Cargo workspaces, go.work).
+func findRootDependencies(rootPath, serverPath string) []string {
+	var found []string
+	for _, name := range rootDependencyFiles {
+		if _, err := os.Stat(filepath.Join(rootPath, name)); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(serverPath, name)); err == nil {
+			continue
+		}
+		found = append(found, name)
+	}
+	return found
+}
+
+// splitRepoURL separates a repository URL from a trailing subdirectory path.
+// GitHub (/tree/<ref>/...) and GitLab (/-/tree/<ref>/...) browser URLs also
+// yield the ref to clone; a ref containing "/" is only its first segment
+// here, see splitRef. GitLab groups nest, so on GitLab the repository runs
+// up to "/-/" or the end of the URL rather than being host/owner/repo. SSH
+// remotes cannot carry a subdirectory.
+func splitRepoURL(url string) (repo, ref, subdir string, err error) {
+	url = strings.TrimSuffix(strings.TrimSpace(url), "/")
+	if isSSHURL(url) {
+		return url, "", "", nil
+	}
+
+	var scheme string
+	if i := strings.Index(url, "://"); i >= 0 {
+		scheme, url = url[:i+3], url[i+3:]
+	}
+	parts := strings.Split(url, "/")
+	end := 3
+	if i := slices.Index(parts, "-"); i >= 3 {
+		end = i
+	} else if strings.Contains(parts[0], "gitlab") {
+		end = len(parts)
+	}
+	if len(parts) <= end {
+		return scheme + url, "", "", nil
+	}
+
+	rest := parts[end:]
+	if len(rest) >= 2 && rest[0] == "-" && rest[1] == "tree" {
+		rest = rest[1:]
+	}
+	if len(rest) >= 2 && rest[0] == "tree" {
+		ref, rest = rest[1], rest[2:]
+	}
+	if len(rest) > 0 {
+		subdir = path.Clean(strings.Join(rest, "/"))
+		if subdir == ".." || strings.HasPrefix(subdir, "../") {
+			return "", "", "", fmt.Errorf("invalid repository subdirectory: %s", subdir)
+		}
+		if subdir == "." {
+			subdir = ""
+		}
+	}
+	return scheme + strings.Join(parts[:end], "/"), ref, subdir, nil
+}
+
+// splitRef moves the leading segments of subdir into ref when together they
+// name one of refs, so a browser URL for a branch such as feature/login is
+// not read as branch "feature" and subdirectory "login/...". The longest
+// matching ref wins.
+func splitRef(refs []string, ref, subdir string) (string, string) {
+	segments := strings.Split(subdir, "/")
+	for i := len(segments); i > 0; i-- {
+		candidate := ref + "/" + strings.Join(segments[:i], "/")
+		if slices.Contains(refs, candidate) {
+			return candidate, strings.Join(segments[i:], "/")
+		}
+	}
+	return ref, subdir
+}
+
+// remoteRefs lists the branch and tag names of remote. Errors are ignored:
+// without the list, a ref is taken to be a single path segment.
+func (f *RepoFetcher) remoteRefs(ctx context.Context, remote string, env []string) []string {
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--heads", "--tags", remote)
+	cmd.Env = env
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parseRemoteRefs(string(out))
+}
+
+// parseRemoteRefs returns the branch and tag names in git ls-remote output.
+func parseRemoteRefs(output string) []string {
+	var refs []string
+	for _, line := range strings.Split(output, "\n") {
+		_, name, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || strings.HasSuffix(name, "^{}") {
+			continue
+		}
+		if branch, ok := strings.CutPrefix(name, "refs/heads/"); ok {
+			refs = append(refs, branch)
+		} else if tag, ok := strings.CutPrefix(name, "refs/tags/"); ok {
+			refs = append(refs, tag)
+		}
+	}
+	return refs
+}
+
+// ContextDir is the directory the container is built from: the server's
+// subdirectory, or the repository root when the subdirectory depends on
+// root-level lockfiles.
+func (r *FetchResult) ContextDir() string {
+	if r.Subdir == "" || len(r.RootFiles) > 0 {
+		return r.LocalPath
+	}
+	return filepath.Join(r.LocalPath, filepath.FromSlash(r.Subdir))
+}
+
+// PackageName is the name of the installed package: the subdirectory's
+// name for monorepo servers, otherwise the repository name.
+func (r *FetchResult) PackageName() string {
+	if r.Subdir != "" {
+		return path.Base(r.Subdir)
+	}
+	return r.RepoName
+}
+
+// PackageID identifies the installed package, e.g. github.com/owner/repo
+// or github.com/owner/repo/src/server.
+func (r *FetchResult) PackageID() string {
+	id := strings.TrimSuffix(strings.TrimPrefix(r.RepoURL, "https://"), ".git")
+	if r.Subdir != "" {
+		id += "/" + r.Subdir
+	}
+	return id
+}
+
+// ImageName is the container image built for the package.
+func (r *FetchResult) ImageName() string {
+	name := "conduit-mcp-" + r.RepoName
+	if r.Subdir != "" {
+		name += "-" + path.Base(r.Subdir)
+	}
+	return strings.ToLower(name)
+}
+
+// cacheKey identifies the analyzed package in the analysis cache, so
+// servers in the same monorepo are cached separately.
+func (r *FetchResult) cacheKey() string {
+	if r.Subdir == "" {
+		return r.RepoURL
+	}
+	return r.RepoURL + "#" + r.Subdir
+}
+
+// buildContextNote tells the AI where a monorepo server lives and what the
+// build context contains. It is empty for servers at the repository root.
+func (r *FetchResult) buildContextNote() string {
+	if r.Subdir == "" {
+		return ""
+	}
+	if len(r.RootFiles) == 0 {
+		return fmt.Sprintf("The MCP server is in the %s subdirectory of the repository. "+
+			"The build context is that subdirectory: COPY the sources from the context "+
+			"instead of cloning the repository.", r.Subdir)
+	}
+	return fmt.Sprintf("The MCP server is in the %s subdirectory of the repository and "+
+		"depends on root-level files (%s). The build context is the repository root: "+
+		"COPY the sources from the context instead of cloning, install dependencies from "+
+		"the root so those files are used, then build and run in %s.",
+		r.Subdir, strings.Join(r.RootFiles, ", "), r.Subdir)
+}
+
 // Cleanup removes the cloned repository.
 func (f *RepoFetcher) Cleanup(result *FetchResult) error {
 	if result != nil && result.LocalPath != "" {
@@ -101,35 +361,77 @@ func (f *RepoFetcher) Cleanup(result *FetchResult) error {
 	return nil
 }
 
+// gitAuthEnv returns the environment that authenticates a clone of remote.
+// Git reads extra config from GIT_CONFIG_COUNT/KEY/VALUE variables, which
+// lets the token travel as an HTTP header scoped to the repository's host
+// without being written to disk. The header is added after any config
+// entries already in the environment so the user's own are kept. Prompts are
+// disabled so a private repository without credentials fails instead of
+// waiting for input.
+func (f *RepoFetcher) gitAuthEnv(normalizedURL, remote string) []string {
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	if f.Token == "" || !strings.HasPrefix(remote, "https://") {
+		return env
+	}
+
+	host := strings.TrimPrefix(normalizedURL, "https://")
+	host = host[:strings.Index(host, "/")]
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + f.Token))
+	n, err := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	if err != nil || n < 0 {
+		n = 0
+	}
+	return append(env,
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", n+1),
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=http.https://%s/.extraheader", n, host),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=Authorization: Basic %s", n, credentials),
+	)
+}
+
+// isSSHURL reports whether url is an SSH remote, in either scp-like
+// (git@host:owner/repo) or ssh:// form.
+func isSSHURL(url string) bool {
+	url = strings.TrimSpace(url)
+	return strings.HasPrefix(url, "git@") || strings.HasPrefix(url, "ssh://")
+}
+
+// sshCloneURL returns the SSH remote for url with a consistent .git suffix.
+func sshCloneURL(url string) string {
+	url = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(url), "/"), ".git")
+	return url + ".git"
+}
+
 // parseRepoURL parses various Git URL formats.
 func parseRepoURL(url string) (normalizedURL, owner, name string, err error) {
 	// Handle different URL formats:
 	// - https://github.com/owner/repo
 	// - https://github.com/owner/repo.git
 	// - git@github.com:owner/repo.git
+	// - ssh://git@github.com/owner/repo.git
 	// - github.com/owner/repo
+	// - gitlab.com/group/subgroup/repo (the owner is group/subgroup)
 
 	url = strings.TrimSpace(url)
 	url = strings.TrimSuffix(url, "/")
 	url = strings.TrimSuffix(url, ".git")
 
-	// Pattern for HTTPS URLs
-	httpsPattern := regexp.MustCompile(`^(?:https?://)?(?:www\.)?([^/]+)/([^/]+)/([^/]+)$`)
-	if matches := httpsPattern.FindStringSubmatch(url); len(matches) == 4 {
-		host := matches[1]
-		owner = matches[2]
-		name = matches[3]
+	// Pattern for SSH URLs
+	sshPattern := regexp.MustCompile(`^(?:ssh://git@([^/]+)/|git@([^:]+):)([^/]+(?:/[^/]+)*)/([^/]+)$`)
+	if matches := sshPattern.FindStringSubmatch(url); len(matches) == 5 {
+		host := matches[1] + matches[2]
+		owner = matches[3]
+		name = matches[4]
+		// The HTTPS form identifies the repository; Fetch still clones over SSH
 		normalizedURL = fmt.Sprintf("https://%s/%s/%s.git", host, owner, name)
 		return normalizedURL, owner, name, nil
 	}
 
-	// Pattern for SSH URLs
-	sshPattern := regexp.MustCompile(`^git@([^:]+):([^/]+)/([^/]+)$`)
-	if matches := sshPattern.FindStringSubmatch(url); len(matches) == 4 {
+	// Pattern for HTTPS URLs
+	httpsPattern := regexp.MustCompile(`^(?:https?://)?(?:www\.)?([^/]+)/([^/]+(?:/[^/]+)*)/([^/]+)$`)
+	if matches := httpsPattern.FindStringSubmatch(url); len(matches) == 4 {
 		host := matches[1]
 		owner = matches[2]
 		name = matches[3]
-		// Convert to HTTPS for cloning
 		normalizedURL = fmt.Sprintf("https://%s/%s/%s.git", host, owner, name)
 		return normalizedURL, owner, name, nil
 	}
@@ -274,6 +576,8 @@ func readFile(path string) (string, error) {
 func (r *FetchResult) ToAnalysisRequest() AnalysisRequest {
 	return AnalysisRequest{
 		RepoURL:         r.RepoURL,
+		Subdir:          r.Subdir,
+		RootFiles:       r.RootFiles,
 		README:          r.Files.README,
 		PackageJSON:     r.Files.PackageJSON,
 		RequirementsTxt: r.Files.RequirementsTxt,
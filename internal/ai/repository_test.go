@@ -1,6 +1,11 @@
 package ai
 
 import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +41,12 @@ func TestParseRepoURL_HTTPS(t *testing.T) {
 			expectedOwner: "owner",
 			expectedName:  "repo",
 		},
+		{
+			input:         "https://gitlab.com/group/subgroup/repo.git",
+			expectedURL:   "https://gitlab.com/group/subgroup/repo.git",
+			expectedOwner: "group/subgroup",
+			expectedName:  "repo",
+		},
 		{
 			input:       "invalid-url",
 			shouldError: true,
@@ -96,6 +107,18 @@ func TestParseRepoURL_SSH(t *testing.T) {
 			expectedOwner: "modelcontextprotocol",
 			expectedName:  "servers",
 		},
+		{
+			input:         "ssh://git@github.com/owner/repo.git",
+			expectedURL:   "https://github.com/owner/repo.git",
+			expectedOwner: "owner",
+			expectedName:  "repo",
+		},
+		{
+			input:         "git@gitlab.com:group/subgroup/repo.git",
+			expectedURL:   "https://gitlab.com/group/subgroup/repo.git",
+			expectedOwner: "group/subgroup",
+			expectedName:  "repo",
+		},
 	}
 
 	for _, tt := range tests {
@@ -122,6 +145,216 @@ func TestParseRepoURL_SSH(t *testing.T) {
 	}
 }
 
+func TestSplitRepoURL(t *testing.T) {
+	tests := []struct {
+		input  string
+		repo   string
+		ref    string
+		subdir string
+	}{
+		{input: "https://github.com/owner/repo", repo: "https://github.com/owner/repo"},
+		{input: "github.com/owner/repo.git/", repo: "github.com/owner/repo.git"},
+		{
+			input:  "github.com/modelcontextprotocol/servers/src/filesystem",
+			repo:   "github.com/modelcontextprotocol/servers",
+			subdir: "src/filesystem",
+		},
+		{
+			input:  "https://github.com/modelcontextprotocol/servers/tree/main/src/filesystem/",
+			repo:   "https://github.com/modelcontextprotocol/servers",
+			ref:    "main",
+			subdir: "src/filesystem",
+		},
+		{
+			input:  "https://gitlab.com/group/repo/-/tree/v1.2.0/packages/server",
+			repo:   "https://gitlab.com/group/repo",
+			ref:    "v1.2.0",
+			subdir: "packages/server",
+		},
+		{
+			input:  "https://gitlab.com/group/subgroup/repo/-/tree/main/packages/server",
+			repo:   "https://gitlab.com/group/subgroup/repo",
+			ref:    "main",
+			subdir: "packages/server",
+		},
+		{input: "gitlab.com/group/subgroup/repo", repo: "gitlab.com/group/subgroup/repo"},
+		{input: "https://github.com/owner/repo/tree/dev", repo: "https://github.com/owner/repo", ref: "dev"},
+		{
+			// The ref is split from the subdirectory once the remote's refs are known
+			input:  "https://github.com/owner/repo/tree/feature/login/src/server",
+			repo:   "https://github.com/owner/repo",
+			ref:    "feature",
+			subdir: "login/src/server",
+		},
+		{input: "git@github.com:owner/repo.git", repo: "git@github.com:owner/repo.git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			repo, ref, subdir, err := splitRepoURL(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo != tt.repo || ref != tt.ref || subdir != tt.subdir {
+				t.Errorf("got (%q, %q, %q), want (%q, %q, %q)", repo, ref, subdir, tt.repo, tt.ref, tt.subdir)
+			}
+		})
+	}
+
+	if _, _, _, err := splitRepoURL("github.com/owner/repo/src/../../etc"); err == nil {
+		t.Error("expected error for subdirectory escaping the repository")
+	}
+}
+
+func TestSplitRef(t *testing.T) {
+	refs := parseRemoteRefs("a1\trefs/heads/main\n" +
+		"b2\trefs/heads/feature\n" +
+		"c3\trefs/heads/feature/login\n" +
+		"d4\trefs/tags/release/v1\n" +
+		"e5\trefs/tags/release/v1^{}\n")
+	if want := []string{"main", "feature", "feature/login", "release/v1"}; !slices.Equal(refs, want) {
+		t.Fatalf("parseRemoteRefs = %v, want %v", refs, want)
+	}
+
+	tests := []struct {
+		ref, subdir         string
+		wantRef, wantSubdir string
+	}{
+		{"feature", "login/src/server", "feature/login", "src/server"},
+		{"feature", "login", "feature/login", ""},
+		{"feature", "src/server", "feature", "src/server"},
+		{"release", "v1/packages/server", "release/v1", "packages/server"},
+		{"main", "src", "main", "src"},
+	}
+	for _, tt := range tests {
+		ref, subdir := splitRef(refs, tt.ref, tt.subdir)
+		if ref != tt.wantRef || subdir != tt.wantSubdir {
+			t.Errorf("splitRef(%q, %q) = (%q, %q), want (%q, %q)", tt.ref, tt.subdir, ref, subdir, tt.wantRef, tt.wantSubdir)
+		}
+	}
+
+	// Without the remote's refs, the first segment is the ref
+	if ref, subdir := splitRef(nil, "feature", "login/src"); ref != "feature" || subdir != "login/src" {
+		t.Errorf("splitRef without refs = (%q, %q)", ref, subdir)
+	}
+}
+
+func TestFetchResultSubdir(t *testing.T) {
+	root := t.TempDir()
+	server := filepath.Join(root, "src", "filesystem")
+	if err := os.MkdirAll(server, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"package-lock.json", "Cargo.lock"} {
+		os.WriteFile(filepath.Join(root, name), []byte("{}"), 0644)
+	}
+	// The package's own Cargo.lock takes precedence over the root one
+	os.WriteFile(filepath.Join(server, "Cargo.lock"), []byte(""), 0644)
+
+	r := &FetchResult{
+		LocalPath: root,
+		RepoURL:   "https://github.com/modelcontextprotocol/servers.git",
+		RepoName:  "servers",
+		Subdir:    "src/filesystem",
+	}
+	if got := r.ContextDir(); got != server {
+		t.Errorf("ContextDir() = %s, want %s", got, server)
+	}
+
+	r.RootFiles = findRootDependencies(root, server)
+	if len(r.RootFiles) != 1 || r.RootFiles[0] != "package-lock.json" {
+		t.Fatalf("expected only package-lock.json, got %v", r.RootFiles)
+	}
+	if got := r.ContextDir(); got != root {
+		t.Errorf("ContextDir() with root lockfiles = %s, want %s", got, root)
+	}
+
+	if got := r.PackageID(); got != "github.com/modelcontextprotocol/servers/src/filesystem" {
+		t.Errorf("PackageID() = %s", got)
+	}
+	if got := r.PackageName(); got != "filesystem" {
+		t.Errorf("PackageName() = %s", got)
+	}
+	if got := r.ImageName(); got != "conduit-mcp-servers-filesystem" {
+		t.Errorf("ImageName() = %s", got)
+	}
+	if !strings.Contains(r.buildContextNote(), "package-lock.json") {
+		t.Errorf("build context note does not mention root lockfiles: %s", r.buildContextNote())
+	}
+
+	whole := &FetchResult{LocalPath: root, RepoURL: r.RepoURL, RepoName: "servers"}
+	if whole.cacheKey() == r.cacheKey() {
+		t.Error("subdirectory shares a cache key with the repository root")
+	}
+	if whole.ContextDir() != root || whole.ImageName() != "conduit-mcp-servers" || whole.buildContextNote() != "" {
+		t.Error("unexpected values for a repository-root package")
+	}
+}
+
+func TestSSHCloneURL(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:owner/repo":            "git@github.com:owner/repo.git",
+		"git@github.com:owner/repo.git":        "git@github.com:owner/repo.git",
+		"ssh://git@github.com/owner/repo.git/": "ssh://git@github.com/owner/repo.git",
+	}
+	for input, expected := range tests {
+		if !isSSHURL(input) {
+			t.Errorf("isSSHURL(%q) = false", input)
+		}
+		if got := sshCloneURL(input); got != expected {
+			t.Errorf("sshCloneURL(%q) = %q, want %q", input, got, expected)
+		}
+	}
+	if isSSHURL("https://github.com/owner/repo") {
+		t.Error("HTTPS URL reported as SSH")
+	}
+}
+
+func TestGitAuthEnv(t *testing.T) {
+	const token = "ghp_secret"
+	normalized := "https://github.com/owner/repo.git"
+	t.Setenv("GIT_CONFIG_COUNT", "")
+
+	f := &RepoFetcher{Token: token}
+	env := f.gitAuthEnv(normalized, normalized)
+
+	want := map[string]string{
+		"GIT_TERMINAL_PROMPT": "0",
+		"GIT_CONFIG_COUNT":    "1",
+		"GIT_CONFIG_KEY_0":    "http.https://github.com/.extraheader",
+		"GIT_CONFIG_VALUE_0":  "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:"+token)),
+	}
+	if len(env) != len(want) {
+		t.Fatalf("expected %d variables, got %v", len(want), env)
+	}
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		if want[key] != value {
+			t.Errorf("%s = %q, want %q", key, value, want[key])
+		}
+		if strings.Contains(kv, token) {
+			t.Errorf("token appears in plain text in %s", key)
+		}
+	}
+
+	// SSH remotes and anonymous clones get no credentials
+	if env := f.gitAuthEnv(normalized, "git@github.com:owner/repo.git"); len(env) != 1 {
+		t.Errorf("expected no credentials for SSH remote, got %v", env)
+	}
+	if env := (&RepoFetcher{}).gitAuthEnv(normalized, normalized); len(env) != 1 {
+		t.Errorf("expected no credentials without a token, got %v", env)
+	}
+
+	// Config entries already in the environment are kept
+	t.Setenv("GIT_CONFIG_COUNT", "2")
+	env = f.gitAuthEnv(normalized, normalized)
+	if len(env) != 4 || env[1] != "GIT_CONFIG_COUNT=3" ||
+		env[2] != "GIT_CONFIG_KEY_2=http.https://github.com/.extraheader" ||
+		!strings.HasPrefix(env[3], "GIT_CONFIG_VALUE_2=Authorization: Basic ") {
+		t.Errorf("expected the header appended after existing config, got %v", env)
+	}
+}
+
 func TestContainsMCPPatterns(t *testing.T) {
 	tests := []struct {
 		content  string
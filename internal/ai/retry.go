@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// retryBaseDelay is the backoff before the first retry; it doubles per attempt.
+var retryBaseDelay = time.Second
+
+// maxRetryDelay caps the exponential backoff between attempts.
+const maxRetryDelay = 30 * time.Second
+
+// ErrAPIStatus is returned when a provider API responds with a non-200 status.
+type ErrAPIStatus struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *ErrAPIStatus) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// ErrParseResponse is returned when the AI output cannot be parsed as the
+// expected JSON, even after a repair pass.
+type ErrParseResponse struct {
+	Err      error
+	Response string
+}
+
+func (e *ErrParseResponse) Error() string {
+	return fmt.Sprintf("failed to parse AI response: %v\nResponse: %s", e.Err, e.Response)
+}
+
+func (e *ErrParseResponse) Unwrap() error {
+	return e.Err
+}
+
+// isRetryable reports whether an AI request failure is worth retrying.
+// Network errors, rate limits, server errors and unparseable output are
+// retried; auth failures, client errors and cancellation are not.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var unavailable *ErrProviderUnavailable
+	if errors.As(err, &unavailable) {
+		return false
+	}
+
+	var status *ErrAPIStatus
+	if errors.As(err, &status) {
+		return status.StatusCode == http.StatusTooManyRequests || status.StatusCode >= 500
+	}
+
+	return true
+}
+
+// withRetry runs fn up to MaxRetries+1 times with exponential backoff,
+// logging each failed attempt so users can see why a model needed retries.
+func (m *Manager) withRetry(ctx context.Context, operation string, fn func() error) error {
+	maxAttempts := m.config.MaxRetries + 1
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = fn(); err == nil {
+			if attempt > 1 {
+				log.Info().
+					Str("operation", operation).
+					Int("attempt", attempt).
+					Msg("AI request succeeded after retry")
+			}
+			return nil
+		}
+
+		if !isRetryable(err) || attempt == maxAttempts {
+			break
+		}
+
+		backoff := retryBaseDelay << (attempt - 1)
+		if backoff > maxRetryDelay {
+			backoff = maxRetryDelay
+		}
+
+		log.Warn().
+			Err(err).
+			Str("operation", operation).
+			Str("provider", m.provider.Name()).
+			Int("attempt", attempt).
+			Int("max_attempts", maxAttempts).
+			Dur("backoff", backoff).
+			Msg("AI request failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+
+	return err
+}
+
+// decodeJSON extracts a JSON object from an AI response and decodes it into v.
+// If the raw object does not parse, a repair pass fixes common local-model
+// mistakes (markdown fences, trailing commas) before giving up.
+func decodeJSON(response string, v interface{}) error {
+	extracted := extractJSON(response)
+	err := json.Unmarshal([]byte(extracted), v)
+	if err == nil {
+		return nil
+	}
+
+	if repaired := repairJSON(response); repaired != extracted {
+		if json.Unmarshal([]byte(repaired), v) == nil {
+			log.Debug().Err(err).Msg("Repaired malformed JSON in AI response")
+			return nil
+		}
+	}
+
+	return &ErrParseResponse{Err: err, Response: extracted}
+}
+
+// repairJSON strips markdown code fences and removes trailing commas before
+// closing braces and brackets. Commas inside string literals are preserved.
+func repairJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if start := strings.Index(s, "```"); start >= 0 {
+		body := s[start+3:]
+		// Drop the language tag on the opening fence (e.g. ```json)
+		if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+			body = body[nl+1:]
+		}
+		if end := strings.Index(body, "```"); end >= 0 {
+			body = body[:end]
+		}
+		s = body
+	}
+	s = extractJSON(s)
+
+	var sb strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			sb.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			j := i + 1
+			for j < len(s) && strings.ContainsRune(" \t\r\n", rune(s[j])) {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				continue
+			}
+		}
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,141 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "markdown fence",
+			input:    "```json\n{\"a\": 1}\n```",
+			expected: `{"a": 1}`,
+		},
+		{
+			name:     "trailing comma in object",
+			input:    `{"a": 1, "b": 2,}`,
+			expected: `{"a": 1, "b": 2}`,
+		},
+		{
+			name:     "trailing comma in array",
+			input:    "{\"a\": [1, 2,\n]}",
+			expected: "{\"a\": [1, 2\n]}",
+		},
+		{
+			name:     "comma inside string preserved",
+			input:    `{"a": "x,}", "b": "\",]",}`,
+			expected: `{"a": "x,}", "b": "\",]"}`,
+		},
+		{
+			name:     "prose around fence",
+			input:    "Here is the config:\n```\n{\"a\": true,}\n```\nDone.",
+			expected: `{"a": true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repairJSON(tt.input); got != tt.expected {
+				t.Errorf("repairJSON(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var v struct {
+		Runtime string   `json:"runtime"`
+		Args    []string `json:"args"`
+	}
+
+	if err := decodeJSON("```json\n{\"runtime\": \"nodejs\", \"args\": [\"index.js\",],}\n```", &v); err != nil {
+		t.Fatalf("expected repaired JSON to decode, got %v", err)
+	}
+	if v.Runtime != "nodejs" || len(v.Args) != 1 {
+		t.Errorf("unexpected decode result: %+v", v)
+	}
+
+	err := decodeJSON(`{"runtime": nodejs}`, &v)
+	var parseErr *ErrParseResponse
+	if !errors.As(err, &parseErr) {
+		t.Errorf("expected ErrParseResponse, got %v", err)
+	}
+}
+
+func TestManager_WithRetry(t *testing.T) {
+	oldDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = oldDelay }()
+
+	config := DefaultProviderConfig()
+	config.MaxRetries = 2
+	m := &Manager{config: config, provider: NewOllamaProvider(config)}
+	ctx := context.Background()
+
+	t.Run("retries parse failures until success", func(t *testing.T) {
+		calls := 0
+		err := m.withRetry(ctx, "test", func() error {
+			calls++
+			if calls < 3 {
+				return &ErrParseResponse{Err: fmt.Errorf("bad json")}
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("expected success on third attempt, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("stops after max retries", func(t *testing.T) {
+		calls := 0
+		err := m.withRetry(ctx, "test", func() error {
+			calls++
+			return &ErrAPIStatus{Provider: "Ollama", StatusCode: 503}
+		})
+		if err == nil || calls != 3 {
+			t.Errorf("expected 3 attempts and an error, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		calls := 0
+		err := m.withRetry(ctx, "test", func() error {
+			calls++
+			return &ErrAPIStatus{Provider: "Anthropic", StatusCode: 400}
+		})
+		if err == nil || calls != 1 {
+			t.Errorf("expected a single attempt, got err=%v calls=%d", err, calls)
+		}
+	})
+
+	t.Run("does not retry auth errors", func(t *testing.T) {
+		calls := 0
+		m.withRetry(ctx, "test", func() error {
+			calls++
+			return &ErrProviderUnavailable{Provider: "anthropic", Reason: "bad key"}
+		})
+		if calls != 1 {
+			t.Errorf("expected a single attempt, got %d", calls)
+		}
+	})
+	t.Run("stops when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := m.withRetry(ctx, "test", func() error {
+			calls++
+			cancel()
+			return &ErrAPIStatus{Provider: "Ollama", StatusCode: 503}
+		})
+		if !errors.Is(err, context.Canceled) || calls != 1 {
+			t.Errorf("expected context.Canceled after one attempt, got err=%v calls=%d", err, calls)
+		}
+	})
+}
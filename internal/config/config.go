@@ -47,6 +47,12 @@ type Config struct {
 	// Runtime configuration
 	Runtime RuntimeConfig `mapstructure:"runtime"`
 
+	// Database configuration
+	Database DatabaseConfig `mapstructure:"database"`
+
+	// Disk space checks
+	Disk DiskConfig `mapstructure:"disk"`
+
 	// KB configuration
 	KB KBConfig `mapstructure:"kb"`
 
@@ -86,6 +92,27 @@ type APIConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+
+	// TCP is an optional localhost listener for browser-based tools. The
+	// Unix socket is always served.
+	TCP TCPConfig `mapstructure:"tcp"`
+}
+
+// TCPConfig configures the daemon's localhost TCP listener. Unlike the
+// socket, which filesystem permissions protect, every request on it must
+// carry the API token as "Authorization: Bearer <token>".
+type TCPConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Address string `mapstructure:"address"` // Must be a loopback address
+
+	// Token overrides the token in APITokenPath, which is generated by
+	// conduit setup or the first time the listener starts. At least 16
+	// characters.
+	Token string `mapstructure:"token"`
+
+	// CORSOrigins are the browser origins allowed to call the API, such as
+	// "http://localhost:5173"; "*" allows any origin
+	CORSOrigins []string `mapstructure:"cors_origins"`
 }
 
 // RuntimeConfig holds container runtime configuration.
@@ -95,16 +122,88 @@ type RuntimeConfig struct {
 	StartTimeout   time.Duration `mapstructure:"start_timeout"`
 	StopTimeout    time.Duration `mapstructure:"stop_timeout"`
 	HealthInterval time.Duration `mapstructure:"health_interval"`
+
+	// GCInterval is how often the daemon removes orphaned containers and
+	// images (see "conduit gc"); 0 disables the periodic collection
+	GCInterval time.Duration `mapstructure:"gc_interval"`
+
+	// GCMinAge spares orphans younger than this, which an install in
+	// progress may not have recorded yet
+	GCMinAge time.Duration `mapstructure:"gc_min_age"`
+
+	// AutoRestart controls recovery of instances stuck in DEGRADED
+	AutoRestart AutoRestartConfig `mapstructure:"auto_restart"`
+
+	// Resources are the default container limits for connector instances
+	Resources ResourceConfig `mapstructure:"resources"`
+}
+
+// DatabaseConfig holds SQLite database settings.
+type DatabaseConfig struct {
+	// AutoBackup takes a backup in BackupsDir before schema migrations and
+	// destructive KB operations such as removing a source
+	AutoBackup bool `mapstructure:"auto_backup"`
+
+	// BackupRetention is the number of automatic backups to keep
+	BackupRetention int `mapstructure:"backup_retention"`
+
+	// BusyTimeout is how long a write waits for a lock held by another
+	// process (e.g. the MCP KB server) before failing with "database is locked"
+	BusyTimeout time.Duration `mapstructure:"busy_timeout"`
+}
+
+// DiskConfig holds the free-space checks run before model pulls, KB syncs
+// and migrations.
+type DiskConfig struct {
+	// MinFreeMB is the free space, in MB, an operation must leave on top of
+	// its estimated writes; it is refused otherwise. 0 disables the checks.
+	// Default: 1024
+	MinFreeMB int64 `mapstructure:"min_free_mb"`
+}
+
+// MinFreeBytes returns MinFreeMB in bytes.
+func (d DiskConfig) MinFreeBytes() int64 {
+	return d.MinFreeMB * 1024 * 1024
+}
+
+// ResourceConfig holds default container resource limits.
+// Instances can override these in the create-instance request.
+type ResourceConfig struct {
+	MemoryMB  int64   `mapstructure:"memory_mb"`
+	CPUs      float64 `mapstructure:"cpus"`
+	CPUShares int64   `mapstructure:"cpu_shares"`
+}
+
+// AutoRestartConfig holds the restart policy for DEGRADED instances.
+type AutoRestartConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`
+	DegradedThreshold time.Duration `mapstructure:"degraded_threshold"`
+	MaxAttempts       int           `mapstructure:"max_attempts"`
+	InitialBackoff    time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff        time.Duration `mapstructure:"max_backoff"`
 }
 
 // KBConfig holds knowledge base configuration.
 type KBConfig struct {
 	Workers       int           `mapstructure:"workers"`
 	MaxFileSize   int64         `mapstructure:"max_file_size"`
-	ChunkSize     int           `mapstructure:"chunk_size"`
-	ChunkOverlap  int           `mapstructure:"chunk_overlap"`
 	WatchDebounce time.Duration `mapstructure:"watch_debounce"`
 
+	// ChunkSize and ChunkOverlap are the legacy names of RAG.ChunkSize and
+	// RAG.ChunkOverlap. Load copies them over when only these are set.
+	ChunkSize    int `mapstructure:"chunk_size"`
+	ChunkOverlap int `mapstructure:"chunk_overlap"`
+
+	// EmbeddingBatchSize is the number of chunks sent per embedding request
+	// during sync and migration.
+	EmbeddingBatchSize int `mapstructure:"embedding_batch_size"`
+
+	// EmbeddingConcurrency is the number of embedding requests in flight.
+	EmbeddingConcurrency int `mapstructure:"embedding_concurrency"`
+
+	// Schedule re-syncs sources in the background
+	Schedule KBScheduleConfig `mapstructure:"schedule"`
+
 	// RAG (Retrieval-Augmented Generation) settings
 	RAG RAGConfig `mapstructure:"rag"`
 
@@ -112,6 +211,18 @@ type KBConfig struct {
 	KAG KAGConfig `mapstructure:"kag"`
 }
 
+// KBScheduleConfig holds the background sync scheduler settings.
+type KBScheduleConfig struct {
+	// Enabled turns on scheduled incremental syncs of active sources.
+	// Default: false (opt-in)
+	Enabled bool `mapstructure:"enabled"`
+
+	// Interval between syncs of a source, unless the source overrides it
+	// with 'conduit kb sync <source> --schedule'. Minimum 1m.
+	// Default: 1h
+	Interval time.Duration `mapstructure:"interval"`
+}
+
 // KAGConfig holds Knowledge-Augmented Generation configuration.
 // Note: Full config is defined in internal/kb/kag_config.go
 type KAGConfig struct {
@@ -157,6 +268,10 @@ type KAGExtractionConfig struct {
 	MaxRelationsPerChunk int     `mapstructure:"max_relations_per_chunk"`
 	BatchSize            int     `mapstructure:"batch_size"`
 	TimeoutSeconds       int     `mapstructure:"timeout_seconds"`
+
+	// DefaultForNewSources enables extraction on sources added without an
+	// explicit extract_entities setting. Default: false (opt-in per source)
+	DefaultForNewSources bool `mapstructure:"default_for_new_sources"`
 }
 
 // KAGOllamaConfig holds Ollama-specific configuration.
@@ -175,11 +290,23 @@ type RAGConfig struct {
 	// Default: 0.1 (permissive - let the LLM decide relevance)
 	MinScore float64 `mapstructure:"min_score"`
 
-	// SemanticWeight controls the balance between semantic and lexical search (0.0-1.0).
-	// 0.0 = pure lexical (FTS5), 1.0 = pure semantic (vectors), 0.5 = balanced
+	// SemanticWeight controls the balance between semantic and lexical search (0.0-1.0)
+	// for every query type. 1.0 = pure semantic (vectors), 0.5 = balanced;
+	// 0 uses the built-in weights per query type.
 	// Default: 0.5
 	SemanticWeight float64 `mapstructure:"semantic_weight"`
 
+	// RRFConstant is the k of Reciprocal Rank Fusion (> 0; 0 uses the
+	// default). Larger values flatten the difference between top and lower
+	// ranks.
+	// Default: 60
+	RRFConstant int `mapstructure:"rrf_constant"`
+
+	// QueryWeights overrides the semantic/lexical weights of individual query
+	// types (exact_quote, entity, conceptual, factual, exploratory), taking
+	// precedence over SemanticWeight.
+	QueryWeights map[string]RAGQueryWeights `mapstructure:"query_weights"`
+
 	// EnableMMR enables Maximal Marginal Relevance for result diversity.
 	// When true, results are diversified to avoid redundant content.
 	// Default: true
@@ -198,6 +325,48 @@ type RAGConfig struct {
 	// DefaultLimit is the default number of results to return.
 	// Default: 10
 	DefaultLimit int `mapstructure:"default_limit"`
+
+	// SemanticTimeout bounds how long hybrid search waits for semantic
+	// results. When exceeded, search continues with keyword results only.
+	// Default: 5s
+	SemanticTimeout time.Duration `mapstructure:"semantic_timeout"`
+
+	// ChunkSize is the size of the chunks documents are split into for
+	// indexing, in characters (100-8000). Changes apply on the next reindex.
+	// Default: 1000
+	ChunkSize int `mapstructure:"chunk_size"`
+
+	// ChunkOverlap is the text, in characters, repeated between neighbouring
+	// chunks (0 to half of ChunkSize). 0 turns overlap off.
+	// Default: 100
+	ChunkOverlap int `mapstructure:"chunk_overlap"`
+
+	// SnippetLength is the length of each search hit's snippet in characters,
+	// for keyword, semantic and hybrid search alike (50-4000).
+	// Default: 300
+	SnippetLength int `mapstructure:"snippet_length"`
+
+	// HighlightStyle marks query terms in result text: "none", "markdown"
+	// (**term**) or "html" (<mark>term</mark>). Requests may override it.
+	// Default: "none"
+	HighlightStyle string `mapstructure:"highlight_style"`
+
+	// ExpandEntities adds the aliases and similar entities of knowledge graph
+	// entities a query names to its keyword search. Needs KAG enabled.
+	// Requests may override it.
+	// Default: false
+	ExpandEntities bool `mapstructure:"expand_entities"`
+
+	// MaxExpansionTerms caps the entity names one query is expanded with (1-20).
+	// Default: 5
+	MaxExpansionTerms int `mapstructure:"max_expansion_terms"`
+}
+
+// RAGQueryWeights weighs the semantic and lexical results of one query type
+// in hybrid search fusion. Each weight is in [0, 1]; they may not both be 0.
+type RAGQueryWeights struct {
+	Semantic float64 `mapstructure:"semantic"`
+	Lexical  float64 `mapstructure:"lexical"`
 }
 
 // PolicyConfig holds policy engine configuration.
@@ -272,6 +441,9 @@ func DefaultConfig() *Config {
 			ReadTimeout:  30 * time.Second,
 			WriteTimeout: 10 * time.Minute, // Long-running ops like kb sync need time
 			IdleTimeout:  120 * time.Second,
+			TCP: TCPConfig{
+				Address: "127.0.0.1:9470",
+			},
 		},
 
 		Runtime: RuntimeConfig{
@@ -280,14 +452,44 @@ func DefaultConfig() *Config {
 			StartTimeout:   30 * time.Second,
 			StopTimeout:    10 * time.Second,
 			HealthInterval: 30 * time.Second,
+			GCInterval:     0, // Opt-in
+			GCMinAge:       10 * time.Minute,
+			AutoRestart: AutoRestartConfig{
+				Enabled:           false, // Opt-in
+				DegradedThreshold: 1 * time.Minute,
+				MaxAttempts:       5,
+				InitialBackoff:    30 * time.Second,
+				MaxBackoff:        10 * time.Minute,
+			},
+			Resources: ResourceConfig{
+				MemoryMB: 512, // MCP servers are small; cap runaway processes
+				CPUs:     1.0,
+			},
+		},
+
+		Database: DatabaseConfig{
+			AutoBackup:      true,
+			BackupRetention: 5,
+			BusyTimeout:     5 * time.Second,
+		},
+
+		Disk: DiskConfig{
+			MinFreeMB: 1024,
 		},
 
 		KB: KBConfig{
 			Workers:       4,
-			MaxFileSize:   100 * 1024 * 1024, // 100MB
-			ChunkSize:     1000,
-			ChunkOverlap:  100,
+			MaxFileSize:   5 * 1024 * 1024, // 5MB; larger files are skipped during sync
 			WatchDebounce: 500 * time.Millisecond,
+
+			EmbeddingBatchSize:   32,
+			EmbeddingConcurrency: 2,
+
+			Schedule: KBScheduleConfig{
+				Enabled:  false, // Opt-in
+				Interval: time.Hour,
+			},
+
 			RAG: RAGConfig{
 				MinScore:       0.0,  // No filtering - return all results, let LLM decide relevance
 				SemanticWeight: 0.5,  // Balanced hybrid search
@@ -295,6 +497,16 @@ func DefaultConfig() *Config {
 				MMRLambda:      0.7,  // 70% relevance, 30% diversity
 				EnableRerank:   true, // Reranking enabled
 				DefaultLimit:   10,   // 10 results by default
+				RRFConstant:    60,   // Standard RRF constant
+
+				SemanticTimeout: 5 * time.Second, // Fall back to keyword results past this
+				SnippetLength:   300,
+				ChunkSize:       1000,
+				ChunkOverlap:    100,
+				HighlightStyle:  "none",
+
+				ExpandEntities:    false, // Opt-in; needs KAG
+				MaxExpansionTerms: 5,
 			},
 			KAG: KAGConfig{
 				Enabled:      false, // Opt-in for security
@@ -315,6 +527,7 @@ func DefaultConfig() *Config {
 					MaxRelationsPerChunk: 50,
 					BatchSize:            10,
 					TimeoutSeconds:       60,
+					DefaultForNewSources: false,
 				},
 				Ollama: KAGOllamaConfig{
 					Model:     "mistral:7b-instruct-q4_K_M",
@@ -401,6 +614,14 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	// Chunking settings used to live directly under kb
+	if v.IsSet("kb.chunk_size") && !v.IsSet("kb.rag.chunk_size") {
+		cfg.KB.RAG.ChunkSize = cfg.KB.ChunkSize
+	}
+	if v.IsSet("kb.chunk_overlap") && !v.IsSet("kb.rag.chunk_overlap") {
+		cfg.KB.RAG.ChunkOverlap = cfg.KB.ChunkOverlap
+	}
+
 	// Expand tildes in path fields
 	cfg.DataDir = expandPath(cfg.DataDir)
 	cfg.SocketPath = expandPath(cfg.SocketPath)
@@ -426,6 +647,22 @@ func (c *Config) BackupsDir() string {
 	return filepath.Join(c.DataDir, "backups")
 }
 
+// SecretsPath returns the path to the encrypted secrets store.
+func (c *Config) SecretsPath() string {
+	return filepath.Join(c.DataDir, "secrets.enc")
+}
+
+// KeyPath returns the path to the machine-local key that encrypts secrets.
+func (c *Config) KeyPath() string {
+	return filepath.Join(c.DataDir, "conduit.key")
+}
+
+// APITokenPath returns the path to the token that authenticates requests on
+// the TCP listener.
+func (c *Config) APITokenPath() string {
+	return filepath.Join(c.DataDir, "api.token")
+}
+
 // LogPath returns the path to the log file.
 func (c *Config) LogPath() string {
 	return filepath.Join(c.DataDir, "conduit.log")
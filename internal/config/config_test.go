@@ -69,6 +69,12 @@ func TestDefaultConfig_APIDefaults(t *testing.T) {
 	if cfg.API.IdleTimeout != 120*time.Second {
 		t.Errorf("IdleTimeout should be 120s, got %v", cfg.API.IdleTimeout)
 	}
+	if cfg.API.TCP.Enabled {
+		t.Error("TCP listener should be disabled by default")
+	}
+	if cfg.API.TCP.Address != "127.0.0.1:9470" {
+		t.Errorf("TCP address should be 127.0.0.1:9470, got %s", cfg.API.TCP.Address)
+	}
 }
 
 func TestDefaultConfig_RuntimeDefaults(t *testing.T) {
@@ -83,6 +89,24 @@ func TestDefaultConfig_RuntimeDefaults(t *testing.T) {
 	if cfg.Runtime.HealthInterval != 30*time.Second {
 		t.Errorf("HealthInterval should be 30s, got %v", cfg.Runtime.HealthInterval)
 	}
+	if cfg.Runtime.GCInterval != 0 || cfg.Runtime.GCMinAge != 10*time.Minute {
+		t.Errorf("GC should be off with a 10m minimum age, got interval %v, min age %v",
+			cfg.Runtime.GCInterval, cfg.Runtime.GCMinAge)
+	}
+}
+
+func TestDefaultConfig_DatabaseDefaults(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if !cfg.Database.AutoBackup {
+		t.Error("AutoBackup should be enabled by default")
+	}
+	if cfg.Database.BackupRetention != 5 {
+		t.Errorf("BackupRetention should be 5, got %d", cfg.Database.BackupRetention)
+	}
+	if cfg.Database.BusyTimeout != 5*time.Second {
+		t.Errorf("BusyTimeout should be 5s, got %v", cfg.Database.BusyTimeout)
+	}
 }
 
 func TestDefaultConfig_KBDefaults(t *testing.T) {
@@ -91,14 +115,23 @@ func TestDefaultConfig_KBDefaults(t *testing.T) {
 	if cfg.KB.Workers != 4 {
 		t.Errorf("Workers should be 4, got %d", cfg.KB.Workers)
 	}
-	if cfg.KB.MaxFileSize != 100*1024*1024 {
-		t.Errorf("MaxFileSize should be 100MB, got %d", cfg.KB.MaxFileSize)
+	if cfg.KB.MaxFileSize != 5*1024*1024 {
+		t.Errorf("MaxFileSize should be 5MB, got %d", cfg.KB.MaxFileSize)
 	}
-	if cfg.KB.ChunkSize != 1000 {
-		t.Errorf("ChunkSize should be 1000, got %d", cfg.KB.ChunkSize)
+	if cfg.KB.RAG.ChunkSize != 1000 {
+		t.Errorf("RAG.ChunkSize should be 1000, got %d", cfg.KB.RAG.ChunkSize)
 	}
-	if cfg.KB.ChunkOverlap != 100 {
-		t.Errorf("ChunkOverlap should be 100, got %d", cfg.KB.ChunkOverlap)
+	if cfg.KB.RAG.ChunkOverlap != 100 {
+		t.Errorf("RAG.ChunkOverlap should be 100, got %d", cfg.KB.RAG.ChunkOverlap)
+	}
+	if cfg.KB.EmbeddingBatchSize != 32 {
+		t.Errorf("EmbeddingBatchSize should be 32, got %d", cfg.KB.EmbeddingBatchSize)
+	}
+	if cfg.KB.EmbeddingConcurrency != 2 {
+		t.Errorf("EmbeddingConcurrency should be 2, got %d", cfg.KB.EmbeddingConcurrency)
+	}
+	if cfg.KB.RAG.SemanticTimeout != 5*time.Second {
+		t.Errorf("RAG.SemanticTimeout should be 5s, got %v", cfg.KB.RAG.SemanticTimeout)
 	}
 }
 
@@ -237,6 +270,41 @@ func TestLoad_DefaultsWhenNoConfig(t *testing.T) {
 	}
 }
 
+func TestLoad_ChunkSettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string
+		wantSize    int
+		wantOverlap int
+	}{
+		{"defaults", "log_level: info\n", 1000, 100},
+		{"rag section", "kb:\n  rag:\n    chunk_size: 800\n    chunk_overlap: 0\n", 800, 0},
+		{"legacy keys", "kb:\n  chunk_size: 600\n  chunk_overlap: 60\n", 600, 60},
+		{"rag wins over legacy", "kb:\n  chunk_size: 600\n  rag:\n    chunk_size: 900\n", 900, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if err := os.MkdirAll(filepath.Join(home, ".conduit"), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(home, ".conduit", "conduit.yaml"), []byte(tt.yaml), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.KB.RAG.ChunkSize != tt.wantSize || cfg.KB.RAG.ChunkOverlap != tt.wantOverlap {
+				t.Errorf("chunk settings = %d/%d, want %d/%d",
+					cfg.KB.RAG.ChunkSize, cfg.KB.RAG.ChunkOverlap, tt.wantSize, tt.wantOverlap)
+			}
+		})
+	}
+}
+
 func TestDefaultConfig_AIDefaults(t *testing.T) {
 	cfg := DefaultConfig()
 
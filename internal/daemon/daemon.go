@@ -20,10 +20,18 @@ import (
 	"github.com/simpleflo/conduit/internal/adapters"
 	"github.com/simpleflo/conduit/internal/config"
 	"github.com/simpleflo/conduit/internal/kb"
+	"github.com/simpleflo/conduit/internal/lifecycle"
 	"github.com/simpleflo/conduit/internal/observability"
+	"github.com/simpleflo/conduit/internal/policy"
+	"github.com/simpleflo/conduit/internal/runtime"
+	"github.com/simpleflo/conduit/internal/secrets"
 	"github.com/simpleflo/conduit/internal/store"
+	"github.com/simpleflo/conduit/pkg/models"
 )
 
+// operationRetention is how long finished lifecycle operations are kept.
+const operationRetention = 7 * 24 * time.Hour
+
 // Daemon is the core Conduit daemon.
 type Daemon struct {
 	cfg    *config.Config
@@ -32,18 +40,33 @@ type Daemon struct {
 	server *http.Server
 	logger zerolog.Logger
 
+	// Optional localhost TCP listener (api.tcp); nil when disabled
+	tcpServer *http.Server
+	tcpToken  string
+
 	// Module managers
-	adapters     adapters.Registry
-	kbSource     *kb.SourceManager
-	kbSearcher   *kb.Searcher
-	kbIndexer    *kb.Indexer
-	kbSemantic   *kb.SemanticSearcher // Optional: nil if Qdrant/Ollama unavailable
-	kbHybrid     *kb.HybridSearcher   // Combines FTS5 and semantic search
-	kbQdrant     *kb.QdrantManager    // Manages Qdrant container lifecycle
+	runtime    runtime.Provider // Optional: nil if no container runtime available
+	lifecycle  *lifecycle.Manager
+	policy     *policy.Engine
+	adapters   adapters.Registry
+	kbSource   *kb.SourceManager
+	kbSearcher *kb.Searcher
+	kbIndexer  *kb.Indexer
+	kbSemantic *kb.SemanticSearcher // Optional: nil if Qdrant/Ollama unavailable
+	kbHybrid   *kb.HybridSearcher   // Combines FTS5 and semantic search
+	kbQdrant   *kb.QdrantManager    // Manages Qdrant container lifecycle
+	kbFalkor   *kb.FalkorDBManager  // Manages FalkorDB container lifecycle
+	kbWatcher  *kb.Watcher          // Re-indexes sources in watch mode as files change
 
 	// Event system for real-time updates (SSE)
 	eventBus *EventBus
 
+	// Ollama model pulls in progress, keyed by model name (guarded by mu)
+	ollamaPulls map[string]bool
+
+	// Whether an FTS-to-vector migration is running (guarded by mu)
+	kbMigrating bool
+
 	// State
 	mu        sync.RWMutex
 	running   bool
@@ -63,22 +86,96 @@ func New(cfg *config.Config) (*Daemon, error) {
 	}
 
 	// Initialize store
-	st, err := store.New(cfg.DatabasePath())
+	storeOpts := store.Options{
+		BackupRetention: cfg.Database.BackupRetention,
+		BusyTimeout:     cfg.Database.BusyTimeout,
+	}
+	if cfg.Database.AutoBackup {
+		storeOpts.BackupDir = cfg.BackupsDir()
+	}
+	st, err := store.NewWithOptions(cfg.DatabasePath(), storeOpts)
 	if err != nil {
 		return nil, fmt.Errorf("create store: %w", err)
 	}
 
+	// Sensitive instance config is encrypted with a key derived from the
+	// machine-local keyfile; config written before upgrading is migrated here
+	configCipher, err := secrets.NewConfigCipher(cfg.KeyPath())
+	if err != nil {
+		st.Close()
+		return nil, fmt.Errorf("load encryption key: %w", err)
+	}
+	st.SetConfigCipher(configCipher)
+
 	// Initialize adapters registry with all built-in adapters
 	adapterRegistry := adapters.DefaultRegistry(st.DB())
 
+	logger := observability.Logger("daemon")
+
+	if n, err := st.EncryptInstanceConfigs(context.Background()); err != nil {
+		logger.Warn().Err(err).Msg("failed to encrypt existing instance config")
+	} else if n > 0 {
+		logger.Info().Int("instances", n).Msg("encrypted sensitive instance config at rest")
+	}
+
+	// Select container runtime for connector instances (optional)
+	rtCtx, rtCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	rt, err := runtime.NewSelector(cfg.Runtime.Preferred).Select(rtCtx)
+	rtCancel()
+	if err != nil {
+		logger.Warn().Err(err).Msg("no container runtime available, instance containers disabled")
+		rt = nil
+	}
+
+	// Lifecycle manager drives instance containers through the runtime provider
+	policyEngine := policy.New(st.DB())
+	policyEngine.SetAllowNetworkEgress(cfg.Policy.AllowNetworkEgress)
+	lifecycleMgr := lifecycle.New(st.DB(), rt, policyEngine)
+	if cfg.Runtime.StopTimeout > 0 {
+		lifecycleMgr.SetStopTimeout(cfg.Runtime.StopTimeout)
+	}
+	lifecycleMgr.SetDefaultResources(models.ResourceLimits{
+		MemoryMB:  cfg.Runtime.Resources.MemoryMB,
+		CPUs:      cfg.Runtime.Resources.CPUs,
+		CPUShares: cfg.Runtime.Resources.CPUShares,
+	})
+	lifecycleMgr.SetSecretStore(secrets.NewStore(cfg.SecretsPath(), cfg.KeyPath()))
+	lifecycleMgr.SetConfigCipher(configCipher)
+	lifecycleMgr.SetRestartPolicy(lifecycle.RestartPolicy{
+		Enabled:           cfg.Runtime.AutoRestart.Enabled,
+		DegradedThreshold: cfg.Runtime.AutoRestart.DegradedThreshold,
+		MaxAttempts:       cfg.Runtime.AutoRestart.MaxAttempts,
+		InitialBackoff:    cfg.Runtime.AutoRestart.InitialBackoff,
+		MaxBackoff:        cfg.Runtime.AutoRestart.MaxBackoff,
+	})
+	if err := lifecycleMgr.RestoreOperations(context.Background(), operationRetention); err != nil {
+		logger.Warn().Err(err).Msg("failed to restore lifecycle operations")
+	}
+
 	// Initialize KB services
 	kbSource := kb.NewSourceManager(st.DB())
 	kbSource.SetMaxFileSize(cfg.KB.MaxFileSize)
+	kbSource.SetExtractEntitiesDefault(cfg.KB.KAG.Extraction.DefaultForNewSources)
+	kbSource.SetDiskCheck(cfg.DataDir, cfg.Disk.MinFreeBytes())
+	kbSource.SetSchedule(cfg.KB.Schedule.Enabled, cfg.KB.Schedule.Interval)
+	if err := kbSource.SetChunkOptions(cfg.KB.RAG.ChunkSize, cfg.KB.RAG.ChunkOverlap); err != nil {
+		logger.Warn().Err(err).Msg("invalid KB chunking config, using defaults")
+	}
+	if err := kb.ValidateSnippetOptions(cfg.KB.RAG.SnippetLength, cfg.KB.RAG.HighlightStyle); err != nil {
+		logger.Warn().Err(err).Msg("invalid KB snippet config, using defaults")
+		cfg.KB.RAG.SnippetLength = kb.DefaultSnippetLength
+		cfg.KB.RAG.HighlightStyle = string(kb.HighlightNone)
+	}
+	if err := kbRankingConfig(cfg.KB.RAG).Validate(); err != nil {
+		logger.Warn().Err(err).Msg("invalid KB ranking config, using defaults")
+		defaults := config.DefaultConfig().KB.RAG
+		cfg.KB.RAG.SemanticWeight = defaults.SemanticWeight
+		cfg.KB.RAG.RRFConstant = defaults.RRFConstant
+		cfg.KB.RAG.QueryWeights = nil
+	}
 	kbSearcher := kb.NewSearcher(st.DB())
 	kbIndexer := kb.NewIndexer(st.DB())
 
-	logger := observability.Logger("daemon")
-
 	// Initialize Qdrant manager for managed container lifecycle
 	// This ensures storage directory exists, container is running, and collection is healthy
 	qdrantCfg := kb.QdrantConfig{
@@ -101,10 +198,11 @@ func New(cfg *config.Config) (*Daemon, error) {
 	var kbSemantic *kb.SemanticSearcher
 	semanticCfg := kb.SemanticSearchConfig{
 		EmbeddingConfig: kb.EmbeddingConfig{
-			OllamaHost: "http://localhost:11434",
-			Model:      "nomic-embed-text",
-			Dimension:  768,
-			BatchSize:  10,
+			OllamaHost:  "http://localhost:11434",
+			Model:       "nomic-embed-text",
+			Dimension:   768,
+			BatchSize:   cfg.KB.EmbeddingBatchSize,
+			Concurrency: cfg.KB.EmbeddingConcurrency,
 		},
 		VectorStoreConfig: kb.VectorStoreConfig{
 			Host:           "localhost",
@@ -133,7 +231,25 @@ func New(cfg *config.Config) (*Daemon, error) {
 	}
 
 	// Create hybrid searcher (always available - falls back to FTS5 if semantic unavailable)
-	kbHybrid := kb.NewHybridSearcher(kbSearcher, kbSemantic)
+	kbHybrid := newKBHybridSearcher(cfg, st, kbSearcher, kbSemantic)
+
+	// Initialize FalkorDB manager; the container is only managed when KAG uses it
+	falkorCfg := cfg.KB.KAG.Graph.FalkorDB
+	kbFalkor := kb.NewFalkorDBManager(kb.FalkorDBManagerConfig{
+		DataDir:       cfg.DataDir,
+		ContainerName: "conduit-falkordb",
+		Host:          falkorCfg.Host,
+		Port:          falkorCfg.Port,
+		Password:      falkorCfg.Password,
+		GraphName:     falkorCfg.GraphName,
+	})
+	if cfg.KB.KAG.Enabled && cfg.KB.KAG.Graph.Backend == "falkordb" {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		if err := kbFalkor.EnsureReady(ctx); err != nil {
+			logger.Warn().Err(err).Msg("FalkorDB not ready, knowledge graph will be unavailable")
+		}
+		cancel()
+	}
 
 	// Preload KAG extraction model if enabled
 	if cfg.KB.KAG.Enabled && cfg.KB.KAG.PreloadModel && cfg.KB.KAG.Provider == "ollama" {
@@ -167,36 +283,84 @@ func New(cfg *config.Config) (*Daemon, error) {
 	eventBus := NewEventBus(100) // Buffer 100 events per subscriber
 
 	d := &Daemon{
-		cfg:        cfg,
-		store:      st,
-		logger:     logger,
-		adapters:   adapterRegistry,
-		kbSource:   kbSource,
-		kbSearcher: kbSearcher,
-		kbIndexer:  kbIndexer,
-		kbSemantic: kbSemantic,
-		kbHybrid:   kbHybrid,
-		kbQdrant:   kbQdrant,
-		eventBus:   eventBus,
-		shutdownCh: make(chan struct{}),
+		cfg:         cfg,
+		store:       st,
+		logger:      logger,
+		runtime:     rt,
+		lifecycle:   lifecycleMgr,
+		policy:      policyEngine,
+		adapters:    adapterRegistry,
+		kbSource:    kbSource,
+		kbSearcher:  kbSearcher,
+		kbIndexer:   kbIndexer,
+		kbSemantic:  kbSemantic,
+		kbHybrid:    kbHybrid,
+		kbQdrant:    kbQdrant,
+		kbFalkor:    kbFalkor,
+		kbWatcher:   kb.NewWatcher(kbSource, cfg.KB.WatchDebounce),
+		eventBus:    eventBus,
+		shutdownCh:  make(chan struct{}),
+		ollamaPulls: make(map[string]bool),
 	}
 
+	d.kbWatcher.Sync = d.syncWatchedKBSource
+
 	// Setup router
 	d.setupRouter()
 
 	return d, nil
 }
 
+// newKBHybridSearcher creates the KB hybrid searcher with the configured
+// ranking. With KAG enabled, searches can expand queries with knowledge
+// graph entity names.
+func newKBHybridSearcher(cfg *config.Config, st *store.Store, fts *kb.Searcher, semantic *kb.SemanticSearcher) *kb.HybridSearcher {
+	hybrid, err := kb.NewHybridSearcherWithConfig(fts, semantic, kbRankingConfig(cfg.KB.RAG))
+	if err != nil {
+		// Invalid ranking config was replaced by the defaults at startup
+		hybrid = kb.NewHybridSearcher(fts, semantic)
+	}
+	if cfg.KB.KAG.Enabled {
+		hybrid.SetKAGSearcher(kb.NewKAGSearcher(st.DB(), nil))
+	}
+	return hybrid
+}
+
+// kbRankingConfig converts the RAG config to the hybrid searcher's ranking
+// config.
+func kbRankingConfig(rag config.RAGConfig) kb.HybridSearcherConfig {
+	cfg := kb.HybridSearcherConfig{
+		RRFConstant:    rag.RRFConstant,
+		SemanticWeight: rag.SemanticWeight,
+	}
+	if len(rag.QueryWeights) > 0 {
+		cfg.Weights = make(map[kb.QueryType]kb.StrategyWeights, len(rag.QueryWeights))
+		for queryType, w := range rag.QueryWeights {
+			cfg.Weights[kb.QueryType(queryType)] = kb.StrategyWeights{Semantic: w.Semantic, Lexical: w.Lexical}
+		}
+	}
+	return cfg
+}
+
 // setupRouter configures the HTTP router.
 func (d *Daemon) setupRouter() {
 	r := chi.NewRouter()
 
 	// Middleware
 	r.Use(middleware.RequestID)
+	r.Use(requestIDMiddleware)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
 	r.Use(d.loggingMiddleware)
 
+	// Unknown routes get the same JSON errors as the handlers
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, models.ErrRouteNotFound, "no such endpoint: "+r.URL.Path)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusMethodNotAllowed, models.ErrMethodNotAllowed, r.Method+" is not supported on "+r.URL.Path)
+	})
+
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Health endpoints
@@ -209,8 +373,29 @@ func (d *Daemon) setupRouter() {
 			r.Post("/", d.handleCreateInstance)
 			r.Get("/{instanceID}", d.handleGetInstance)
 			r.Delete("/{instanceID}", d.handleDeleteInstance)
+			r.Post("/{instanceID}/install", d.handleInstallInstance)
 			r.Post("/{instanceID}/start", d.handleStartInstance)
 			r.Post("/{instanceID}/stop", d.handleStopInstance)
+			r.Post("/{instanceID}/restart", d.handleRestartInstance)
+			r.Get("/{instanceID}/logs", d.handleInstanceLogs)
+		})
+
+		// Lifecycle operation endpoints
+		r.Route("/operations", func(r chi.Router) {
+			r.Get("/", d.handleListOperations)
+			r.Get("/{operationID}", d.handleGetOperation)
+		})
+
+		// Orphaned container and image cleanup
+		r.Post("/gc", d.handleCollectGarbage)
+
+		// Policy endpoints
+		r.Route("/policy", func(r chi.Router) {
+			r.Get("/decisions", d.handleListDecisions)
+			r.Post("/evaluate", d.handleEvaluatePolicy)
+			r.Get("/instances/{instanceID}", d.handleGetInstancePermissions)
+			r.Post("/instances/{instanceID}/grants", d.handleGrantPermissions)
+			r.Delete("/instances/{instanceID}/grants/{permissionType}", d.handleRevokePermission)
 		})
 
 		// Binding endpoints
@@ -235,9 +420,21 @@ func (d *Daemon) setupRouter() {
 				r.Get("/{sourceID}", d.handleGetKBSource)
 				r.Delete("/{sourceID}", d.handleDeleteKBSource)
 				r.Post("/{sourceID}/sync", d.handleSyncKBSource)
+				r.Post("/{sourceID}/reindex", d.handleReindexKBSource)
+				r.Put("/{sourceID}/schedule", d.handleSetKBSourceSchedule)
+				r.Put("/{sourceID}/watch", d.handleSetKBSourceWatch)
 			})
+			r.Get("/schedule", d.handleGetKBSchedule)
 			r.Get("/search", d.handleKBSearch)
+			r.Get("/documents", d.handleGetKBDocument)
+			r.Get("/documents/{documentID}", d.handleGetKBDocument)
 			r.Post("/migrate", d.handleKBMigrate)
+			r.Get("/migrate/status", d.handleKBMigrateStatus)
+			r.Get("/entities", d.handleListKBEntities)
+			r.Get("/entities/*", d.handleGetKBEntity) // names may contain "/"
+			r.Post("/backup", d.handleKBBackup)
+			r.Post("/restore", d.handleKBRestore)
+			r.Post("/mcp", d.handleKBMCP)
 		})
 
 		// Qdrant management endpoints (for hot-reload semantic search)
@@ -248,6 +445,11 @@ func (d *Daemon) setupRouter() {
 			r.Get("/status", d.handleQdrantStatus)
 		})
 
+		// Ollama model management
+		r.Route("/ollama", func(r chi.Router) {
+			r.Post("/pull", d.handleOllamaPull)
+		})
+
 		// Status endpoint
 		r.Get("/status", d.handleStatus)
 
@@ -259,6 +461,24 @@ func (d *Daemon) setupRouter() {
 	d.router = r
 }
 
+// requestIDMiddleware echoes the request ID in the X-Request-Id response
+// header and carries it in the context, so log entries written below the
+// HTTP layer (searchers, sync, migration) can be matched to a CLI call.
+// middleware.RequestID reuses an ID sent by the client.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.GetReqID(r.Context())
+		w.Header().Set(middleware.RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(observability.ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+// requestLogger returns the daemon logger tagged with the request's ID.
+func (d *Daemon) requestLogger(r *http.Request) *zerolog.Logger {
+	logger := observability.LoggerFromContext(r.Context(), d.logger)
+	return &logger
+}
+
 // loggingMiddleware logs HTTP requests.
 func (d *Daemon) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -293,12 +513,18 @@ func (d *Daemon) Start(ctx context.Context) error {
 		Str("data_dir", d.cfg.DataDir).
 		Msg("starting daemon")
 
-	// Remove existing socket file
 	socketDir := filepath.Dir(d.cfg.SocketPath)
 	if err := os.MkdirAll(socketDir, 0700); err != nil {
 		return fmt.Errorf("create socket directory: %w", err)
 	}
-	os.Remove(d.cfg.SocketPath)
+
+	// Clear a socket left behind by a crashed daemon, but never one in use
+	if err := d.removeStaleSocket(); err != nil {
+		d.mu.Lock()
+		d.running = false
+		d.mu.Unlock()
+		return err
+	}
 
 	// Create Unix socket listener
 	listener, err := net.Listen("unix", d.cfg.SocketPath)
@@ -312,6 +538,13 @@ func (d *Daemon) Start(ctx context.Context) error {
 		return fmt.Errorf("chmod socket: %w", err)
 	}
 
+	// Optional localhost TCP listener for browser-based tools
+	tcpListener, err := d.listenTCP()
+	if err != nil {
+		listener.Close()
+		return err
+	}
+
 	// Create HTTP server
 	d.server = &http.Server{
 		Handler:      d.router,
@@ -329,10 +562,50 @@ func (d *Daemon) Start(ctx context.Context) error {
 		}
 	}()
 
+	if tcpListener != nil {
+		d.tcpServer = &http.Server{
+			Handler:      d.tcpHandler(),
+			ReadTimeout:  d.cfg.API.ReadTimeout,
+			WriteTimeout: d.cfg.API.WriteTimeout,
+			IdleTimeout:  d.cfg.API.IdleTimeout,
+		}
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			if err := d.tcpServer.Serve(tcpListener); err != nil && err != http.ErrServerClosed {
+				d.logger.Error().Err(err).Msg("TCP server error")
+			}
+		}()
+		d.logger.Info().
+			Str("address", tcpListener.Addr().String()).
+			Strs("cors_origins", d.cfg.API.TCP.CORSOrigins).
+			Msg("TCP API listener enabled")
+	}
+
 	// Start health checker
 	d.wg.Add(1)
 	go d.healthCheckLoop(ctx)
 
+	// Start periodic garbage collection of orphaned containers and images
+	if d.cfg.Runtime.GCInterval > 0 && d.runtime != nil {
+		d.logger.Info().Dur("interval", d.cfg.Runtime.GCInterval).Msg("periodic garbage collection enabled")
+		d.wg.Add(1)
+		go d.gcLoop(ctx)
+	}
+
+	// Start scheduled KB syncs. The loop also syncs sources in watch mode
+	// whose folder can't be watched, so it runs with the schedule disabled.
+	if enabled, interval := d.kbSource.Schedule(); enabled {
+		d.logger.Info().Dur("interval", interval).Msg("scheduled KB sync enabled")
+	}
+	d.wg.Add(1)
+	go d.kbSyncLoop(ctx)
+
+	// Start watching KB sources in watch mode
+	d.refreshKBWatcher(ctx)
+	d.wg.Add(1)
+	go d.kbWatchLoop(ctx)
+
 	// Mark as ready
 	d.mu.Lock()
 	d.ready = true
@@ -347,6 +620,34 @@ func (d *Daemon) Start(ctx context.Context) error {
 	return nil
 }
 
+// removeStaleSocket removes the socket file if no daemon is listening on it.
+// It returns an error if another daemon is live on the socket or the path is
+// not a socket.
+func (d *Daemon) removeStaleSocket() error {
+	info, err := os.Lstat(d.cfg.SocketPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("stat socket: %w", err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("socket path %s exists and is not a socket", d.cfg.SocketPath)
+	}
+
+	conn, err := net.DialTimeout("unix", d.cfg.SocketPath, time.Second)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("another daemon is already listening on %s", d.cfg.SocketPath)
+	}
+
+	d.logger.Warn().Str("socket", d.cfg.SocketPath).Msg("removing stale socket from previous run")
+	if err := os.Remove(d.cfg.SocketPath); err != nil {
+		return fmt.Errorf("remove stale socket: %w", err)
+	}
+	return nil
+}
+
 // Stop gracefully stops the daemon.
 func (d *Daemon) Stop(ctx context.Context) error {
 	d.mu.Lock()
@@ -369,6 +670,11 @@ func (d *Daemon) Stop(ctx context.Context) error {
 			d.logger.Error().Err(err).Msg("server shutdown error")
 		}
 	}
+	if d.tcpServer != nil {
+		if err := d.tcpServer.Shutdown(ctx); err != nil {
+			d.logger.Error().Err(err).Msg("TCP server shutdown error")
+		}
+	}
 
 	// Wait for goroutines with timeout
 	done := make(chan struct{})
@@ -405,8 +711,12 @@ func (d *Daemon) Stop(ctx context.Context) error {
 
 // Run runs the daemon until interrupted.
 func (d *Daemon) Run() error {
-	ctx := context.Background()
+	return d.RunContext(context.Background())
+}
 
+// RunContext runs the daemon until interrupted or ctx is cancelled.
+// Service managers without signal delivery (Windows SCM) cancel ctx to stop.
+func (d *Daemon) RunContext(ctx context.Context) error {
 	if err := d.Start(ctx); err != nil {
 		return err
 	}
@@ -418,12 +728,14 @@ func (d *Daemon) Run() error {
 	select {
 	case sig := <-sigCh:
 		d.logger.Info().Str("signal", sig.String()).Msg("received shutdown signal")
+	case <-ctx.Done():
+		d.logger.Info().Msg("shutdown requested by service manager")
 	case <-d.shutdownCh:
 		// Shutdown requested programmatically
 	}
 
 	// Graceful shutdown with timeout
-	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
 	defer cancel()
 
 	return d.Stop(shutdownCtx)
@@ -475,8 +787,187 @@ func (d *Daemon) healthCheckLoop(ctx context.Context) {
 	}
 }
 
+// gcLoop periodically removes orphaned containers and images.
+func (d *Daemon) gcLoop(ctx context.Context) {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.cfg.Runtime.GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.shutdownCh:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := d.lifecycle.CollectGarbage(ctx, lifecycle.GCOptions{MinAge: d.cfg.Runtime.GCMinAge})
+			if err != nil {
+				d.logger.Warn().Err(err).Msg("garbage collection failed")
+				continue
+			}
+			for _, item := range append(result.Containers, result.Images...) {
+				if item.Error != "" {
+					d.logger.Warn().Str("id", item.ID).Str("name", item.Name).Str("error", item.Error).
+						Msg("failed to remove orphan")
+				}
+			}
+		}
+	}
+}
+
+// kbScheduleCheckInterval is how often the scheduler looks for KB sources
+// whose sync is due.
+const kbScheduleCheckInterval = 30 * time.Second
+
+// kbSyncLoop runs the scheduled syncs of KB sources as they fall due.
+func (d *Daemon) kbSyncLoop(ctx context.Context) {
+	defer d.wg.Done()
+
+	// Cancel a sync in progress on shutdown
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.shutdownCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(kbScheduleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.runScheduledKBSyncs(ctx)
+		}
+	}
+}
+
+// runScheduledKBSyncs incrementally syncs each KB source that is due, one at
+// a time, publishing the same events as a sync requested through the API.
+func (d *Daemon) runScheduledKBSyncs(ctx context.Context) {
+	due, err := d.kbSource.DueSources(ctx, time.Now())
+	if err != nil {
+		d.logger.Warn().Err(err).Msg("failed to find KB sources due for sync")
+		return
+	}
+
+	for _, src := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		d.runBackgroundKBSync(ctx, src, "scheduled", func() (*kb.SyncResult, error) {
+			return d.kbSource.Sync(ctx, src.SourceID)
+		})
+	}
+}
+
+// runBackgroundKBSync runs a sync the daemon started itself, logging it as
+// the given kind and publishing the same events as a sync requested through
+// the API.
+func (d *Daemon) runBackgroundKBSync(ctx context.Context, src *kb.Source, kind string, sync func() (*kb.SyncResult, error)) (*kb.SyncResult, error) {
+	startTime := time.Now()
+	d.logger.Info().Str("source_id", src.SourceID).Str("name", src.Name).Msg(kind + " KB sync")
+	d.EmitEvent(EventKBSyncStarted, KBSourceData{
+		SourceID: src.SourceID,
+		Name:     src.Name,
+		Path:     src.Path,
+	})
+
+	result, err := sync()
+	if err != nil {
+		if ctx.Err() == nil {
+			d.logger.Error().Err(err).Str("source_id", src.SourceID).Msg(kind + " KB sync failed")
+		}
+		d.EmitEvent(EventKBSyncFailed, KBSyncResultData{
+			SourceID:     src.SourceID,
+			ErrorMessage: err.Error(),
+			Duration:     time.Since(startTime).String(),
+		})
+		return nil, err
+	}
+
+	d.EmitEvent(EventKBSyncCompleted, KBSyncResultData{
+		SourceID: src.SourceID,
+		Added:    result.Added,
+		Updated:  result.Updated,
+		Deleted:  result.Deleted,
+		Skipped:  len(result.Skipped),
+		Errors:   len(result.Errors),
+		Duration: time.Since(startTime).String(),
+	})
+	return result, nil
+}
+
+// kbWatchLoop re-indexes files of KB sources in watch mode as they change,
+// until shutdown.
+func (d *Daemon) kbWatchLoop(ctx context.Context) {
+	defer d.wg.Done()
+
+	// Cancel a sync in progress on shutdown
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-d.shutdownCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	d.kbWatcher.Run(ctx)
+}
+
+// syncWatchedKBSource re-indexes the changed files of a watched KB source.
+func (d *Daemon) syncWatchedKBSource(ctx context.Context, src *kb.Source, paths []string) (*kb.SyncResult, error) {
+	return d.runBackgroundKBSync(ctx, src, "watched", func() (*kb.SyncResult, error) {
+		return d.kbSource.SyncFiles(ctx, src.SourceID, paths)
+	})
+}
+
+// refreshKBWatcher updates which KB sources are watched after sources are
+// added, removed or change sync mode.
+func (d *Daemon) refreshKBWatcher(ctx context.Context) {
+	if err := d.kbWatcher.Refresh(ctx); err != nil {
+		d.logger.Warn().Err(err).Msg("failed to refresh KB source watches")
+	}
+}
+
 // checkInstanceHealth checks the health of all running instances.
+// The lifecycle manager updates RUNNING/DEGRADED status and applies the
+// auto-restart policy; status changes are published as events.
 func (d *Daemon) checkInstanceHealth(ctx context.Context) {
-	// TODO: Implement health checks for running instances
-	// This will check container health and update instance status
+	before := make(map[string]models.InstanceStatus)
+	if instances, err := d.store.ListInstances(ctx); err == nil {
+		for _, inst := range instances {
+			before[inst.InstanceID] = inst.Status
+		}
+	}
+
+	if err := d.lifecycle.RunHealthChecks(ctx); err != nil {
+		d.logger.Warn().Err(err).Msg("instance health check failed")
+		return
+	}
+
+	instances, err := d.store.ListInstances(ctx)
+	if err != nil {
+		return
+	}
+	for _, inst := range instances {
+		prev, ok := before[inst.InstanceID]
+		if !ok || prev == inst.Status {
+			continue
+		}
+		d.EmitEvent(EventInstanceStatusChanged, InstanceStatusData{
+			InstanceID: inst.InstanceID,
+			Name:       inst.DisplayName,
+			Status:     string(inst.Status),
+			PrevStatus: string(prev),
+		})
+	}
 }
@@ -35,6 +35,12 @@ const (
 	EventKAGExtractionProgress EventType = "kag_extraction_progress"
 	EventKAGExtractionComplete EventType = "kag_extraction_completed"
 
+	// Ollama events
+	EventOllamaPullStarted   EventType = "ollama_pull_started"
+	EventOllamaPullProgress  EventType = "ollama_pull_progress"
+	EventOllamaPullCompleted EventType = "ollama_pull_completed"
+	EventOllamaPullFailed    EventType = "ollama_pull_failed"
+
 	// Binding events
 	EventBindingCreated EventType = "binding_created"
 	EventBindingDeleted EventType = "binding_deleted"
@@ -217,7 +223,7 @@ type KBSyncProgressData struct {
 	Current    int     `json:"current"`
 	Total      int     `json:"total"`
 	Percentage float64 `json:"percentage"`
-	Phase      string  `json:"phase"` // "scanning", "indexing", "vectorizing"
+	Phase      string  `json:"phase"` // "scanning", "indexing", "vectorizing", "reindexing"
 }
 
 // KBSyncResultData contains data for sync completion events.
@@ -226,6 +232,7 @@ type KBSyncResultData struct {
 	Added        int    `json:"added"`
 	Updated      int    `json:"updated"`
 	Deleted      int    `json:"deleted"`
+	Skipped      int    `json:"skipped"`
 	Errors       int    `json:"errors"`
 	Duration     string `json:"duration"`
 	ErrorMessage string `json:"error_message,omitempty"`
@@ -241,6 +248,16 @@ type KAGProgressData struct {
 	Relations  int     `json:"relations"`
 }
 
+// OllamaPullData contains data for Ollama model pull events.
+type OllamaPullData struct {
+	Model        string  `json:"model"`
+	Status       string  `json:"status"`
+	Completed    int64   `json:"completed,omitempty"`
+	Total        int64   `json:"total,omitempty"`
+	Percentage   float64 `json:"percentage"`
+	ErrorMessage string  `json:"error_message,omitempty"`
+}
+
 // BindingData contains data for binding events.
 type BindingData struct {
 	BindingID  string `json:"binding_id"`
@@ -1,10 +1,14 @@
 package daemon
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"net"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,7 +18,15 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/simpleflo/conduit/internal/adapters"
+	"github.com/simpleflo/conduit/internal/diskspace"
 	"github.com/simpleflo/conduit/internal/kb"
+	"github.com/simpleflo/conduit/internal/lifecycle"
+	"github.com/simpleflo/conduit/internal/observability"
+	"github.com/simpleflo/conduit/internal/policy"
+	"github.com/simpleflo/conduit/internal/runtime"
+	"github.com/simpleflo/conduit/internal/secrets"
+	"github.com/simpleflo/conduit/internal/store"
 	"github.com/simpleflo/conduit/pkg/models"
 )
 
@@ -35,21 +47,77 @@ func writeError(w http.ResponseWriter, status int, code models.ErrorCode, messag
 	})
 }
 
+// maxRequestBodySize bounds JSON request bodies. The largest legitimate
+// bodies, instance definitions with their config and permissions, are a few
+// kilobytes.
+const maxRequestBodySize = 1 << 20
+
+// decodeJSONBody decodes a request body holding a single JSON object into v.
+// Bodies over maxRequestBodySize, unknown fields and trailing data are
+// rejected; it writes the error response and returns false.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil && dec.Decode(&json.RawMessage{}) != io.EOF {
+		err = errors.New("request body must contain a single JSON object")
+	}
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, models.ErrRequestTooLarge,
+			fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+	case errors.Is(err, io.EOF):
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "request body is empty")
+	default:
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid,
+			"invalid request body: "+strings.TrimPrefix(err.Error(), "json: "))
+	}
+	return false
+}
+
 // Health endpoints
 
-// handleHealth returns the health status of the daemon.
+// handleHealth returns the health status of the daemon. The status is
+// "unhealthy" when the database is broken and "degraded" when only optional
+// search components (FTS5, Qdrant, Ollama) are unavailable.
 func (d *Daemon) handleHealth(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	status := "healthy"
 	checks := map[string]string{
 		"database": "ok",
+		"fts5":     "ok",
+		"qdrant":   "ok",
+		"ollama":   "ok",
 	}
 
-	// Check database connectivity
-	if err := d.store.Health(r.Context()); err != nil {
+	// Check the database (connectivity, schema, FTS5 and writability). This is
+	// the only failure that makes the daemon unhealthy; the search components
+	// below only degrade it.
+	if err := d.store.Health(ctx); err != nil {
 		status = "unhealthy"
 		checks["database"] = err.Error()
 	}
 
+	optional := map[string]error{
+		"fts5":   d.checkFTS5(ctx),
+		"qdrant": d.checkQdrant(ctx),
+		"ollama": checkOllama(ctx),
+	}
+	for name, err := range optional {
+		if err == nil {
+			continue
+		}
+		checks[name] = err.Error()
+		if status == "healthy" {
+			status = "degraded"
+		}
+	}
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"status":    status,
 		"checks":    checks,
@@ -57,6 +125,61 @@ func (d *Daemon) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// healthCheckTimeout bounds each reachability probe in handleHealth.
+const healthCheckTimeout = 2 * time.Second
+
+// checkFTS5 verifies the FTS5 index can be queried.
+func (d *Daemon) checkFTS5(ctx context.Context) error {
+	if d.store == nil || d.store.DB() == nil {
+		return fmt.Errorf("database not open")
+	}
+	var n int
+	if err := d.store.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM kb_fts WHERE kb_fts MATCH 'conduit'").Scan(&n); err != nil {
+		return fmt.Errorf("fts5 query failed: %w", err)
+	}
+	return nil
+}
+
+// checkQdrant verifies the Qdrant API is reachable.
+func (d *Daemon) checkQdrant(ctx context.Context) error {
+	if d.kbQdrant == nil {
+		return fmt.Errorf("not configured")
+	}
+	httpPort, _ := d.kbQdrant.GetPorts()
+	if err := probeHTTP(ctx, fmt.Sprintf("http://localhost:%d/healthz", httpPort)); err != nil {
+		return fmt.Errorf("unreachable: %w", err)
+	}
+	return nil
+}
+
+// checkOllama verifies the Ollama API is reachable.
+func checkOllama(ctx context.Context) error {
+	if err := probeHTTP(ctx, "http://localhost:11434/api/version"); err != nil {
+		return fmt.Errorf("unreachable: %w", err)
+	}
+	return nil
+}
+
+// probeHTTP issues a GET and requires a 200 response within healthCheckTimeout.
+func probeHTTP(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // handleReady returns whether the daemon is ready to serve requests.
 func (d *Daemon) handleReady(w http.ResponseWriter, r *http.Request) {
 	if d.Ready() {
@@ -99,10 +222,6 @@ func (d *Daemon) handleStatus(w http.ResponseWriter, r *http.Request) {
 		cliInfo["path"] = cliPath
 	}
 
-	// Get socket path
-	homeDir, _ := os.UserHomeDir()
-	socketPath := filepath.Join(homeDir, ".conduit", "conduit.sock")
-
 	// Daemon section - enhanced with pid and socket
 	daemonInfo := map[string]interface{}{
 		"ready":      d.Ready(),
@@ -110,12 +229,15 @@ func (d *Daemon) handleStatus(w http.ResponseWriter, r *http.Request) {
 		"build_time": BuildTime,
 		"uptime":     time.Since(d.startTime).String(),
 		"pid":        os.Getpid(),
-		"socket":     socketPath,
+		"socket":     d.cfg.SocketPath,
 	}
 
 	// KAG section
 	kagInfo := d.getKAGStats()
 
+	// In-flight lifecycle operations (installs, starts)
+	activeOps := d.lifecycle.ListOperations(ctx, true)
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"cli":          cliInfo,
 		"daemon":       daemonInfo,
@@ -128,6 +250,9 @@ func (d *Daemon) handleStatus(w http.ResponseWriter, r *http.Request) {
 		"bindings": map[string]interface{}{
 			"total": len(bindings),
 		},
+		"operations": map[string]interface{}{
+			"running": len(activeOps),
+		},
 		"timestamp": time.Now().Format(time.RFC3339),
 	})
 }
@@ -193,25 +318,40 @@ func (d *Daemon) getDependencyStatus(ctx context.Context) map[string]interface{}
 	// SQLite/FTS5 status - use helper function
 	deps["sqlite"] = d.getSQLiteInfo()
 
-	// FalkorDB status - TCP check first (like conduit doctor does)
+	// FalkorDB (Graph DB) status - enhanced with container info
 	falkorInfo := map[string]interface{}{
 		"available":  false,
 		"host":       "localhost",
 		"port":       6379,
 		"managed_by": "conduit",
-		"graph_name": "conduit_kag",
+		"graph_name": "conduit_kg",
 	}
+	falkorConnected := false
+	if d.kbFalkor != nil {
+		health := d.kbFalkor.CheckHealth(ctx)
+		falkorConnected = health.PingOK
+		falkorInfo["available"] = health.PingOK
+		falkorInfo["host"] = d.kbFalkor.GetHost()
+		falkorInfo["port"] = d.kbFalkor.GetPort()
+		falkorInfo["graph_name"] = d.kbFalkor.GetGraphName()
 
-	// Primary check: TCP connection (same as conduit doctor)
-	conn, err := net.DialTimeout("tcp", "localhost:6379", 2*time.Second)
-	if err == nil {
-		conn.Close()
-		falkorInfo["available"] = true
-	}
+		// Add container details
+		if containerInfo := getContainerInfo(runtimePath, d.kbFalkor.GetContainerName()); containerInfo != nil {
+			falkorInfo["container"] = containerInfo
+		}
 
-	// Supplementary: Add container details if available
-	if containerInfo := getContainerInfo(runtimePath, "conduit-falkordb"); containerInfo != nil {
-		falkorInfo["container"] = containerInfo
+		switch {
+		case health.NeedsRecovery:
+			falkorInfo["status"] = "needs_recovery"
+		case health.PingOK:
+			falkorInfo["status"] = "green"
+			falkorInfo["graph_exists"] = health.GraphExists
+		default:
+			falkorInfo["status"] = "not_running"
+		}
+		if health.Error != "" {
+			falkorInfo["error"] = health.Error
+		}
 	}
 
 	// Get entity/relationship counts from SQLite (they're stored there, not in FalkorDB directly)
@@ -226,17 +366,55 @@ func (d *Daemon) getDependencyStatus(ctx context.Context) map[string]interface{}
 
 	deps["falkordb"] = falkorInfo
 
+	// Knowledge graph (KAG) status - whether graph search will work
+	deps["knowledge_graph"] = d.getKnowledgeGraphInfo(ctx, falkorConnected)
+
 	return deps
 }
 
+// getKnowledgeGraphInfo reports whether KAG extraction is enabled, whether the
+// graph database is connected, and how many entities and relations have been
+// extracted. Graph search needs KAG enabled and at least one entity.
+func (d *Daemon) getKnowledgeGraphInfo(ctx context.Context, falkorConnected bool) map[string]interface{} {
+	info := map[string]interface{}{
+		"enabled":            d.cfg.KB.KAG.Enabled,
+		"backend":            d.cfg.KB.KAG.Graph.Backend,
+		"falkordb_connected": falkorConnected,
+		"entities_count":     0,
+		"relations_count":    0,
+		"search_available":   false,
+	}
+
+	entities := 0
+	if d.store != nil && d.store.DB() != nil {
+		stats, err := kb.NewKAGSearcher(d.store.DB(), nil).GetStats(ctx)
+		if err == nil {
+			entities, _ = stats["total_entities"].(int)
+			info["entities_count"] = entities
+			info["relations_count"] = stats["total_relations"]
+		}
+	}
+
+	switch {
+	case !d.cfg.KB.KAG.Enabled:
+		info["reason"] = "KAG extraction is disabled (set kb.kag.enabled=true)"
+	case entities == 0:
+		info["reason"] = "no entities extracted yet (run 'conduit kb kag-sync')"
+	default:
+		info["search_available"] = true
+	}
+
+	return info
+}
+
 // Instance endpoints
 
 // handleListInstances returns all connector instances.
 func (d *Daemon) handleListInstances(w http.ResponseWriter, r *http.Request) {
 	instances, err := d.store.ListInstances(r.Context())
 	if err != nil {
-		d.logger.Error().Err(err).Msg("failed to list instances")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to list instances")
+		d.requestLogger(r).Error().Err(err).Msg("failed to list instances")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to list instances")
 		return
 	}
 
@@ -249,18 +427,40 @@ func (d *Daemon) handleListInstances(w http.ResponseWriter, r *http.Request) {
 // handleCreateInstance creates a new connector instance.
 func (d *Daemon) handleCreateInstance(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		PackageID      string            `json:"package_id"`
-		PackageVersion string            `json:"package_version"`
-		DisplayName    string            `json:"display_name"`
-		ImageRef       string            `json:"image_ref"`
-		Config         map[string]string `json:"config,omitempty"`
+		PackageID      string                 `json:"package_id"`
+		PackageVersion string                 `json:"package_version"`
+		DisplayName    string                 `json:"display_name"`
+		ImageRef       string                 `json:"image_ref"`
+		Config         map[string]string      `json:"config,omitempty"`
+		Resources      *models.ResourceLimits `json:"resources,omitempty"`
+		Platform       string                 `json:"platform,omitempty"`
+		Permissions    *policy.PermissionSet  `json:"permissions,omitempty"`
+	}
+
+	if !decodeJSONBody(w, r, &req) {
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid request body")
+	if req.Resources != nil && (req.Resources.MemoryMB < 0 || req.Resources.CPUs < 0 || req.Resources.CPUShares < 0) {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "resource limits must not be negative")
 		return
 	}
 
+	if req.Platform != "" {
+		if err := runtime.ValidatePlatform(req.Platform); err != nil {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
+			return
+		}
+	}
+
+	// Secret references stay in the config and are resolved at start
+	for key, value := range req.Config {
+		if err := secrets.ValidateRefs(value); err != nil {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, fmt.Sprintf("config %s: %v", key, err))
+			return
+		}
+	}
+
 	// TODO: Validate package and run audit
 	// For now, create instance directly
 
@@ -271,17 +471,32 @@ func (d *Daemon) handleCreateInstance(w http.ResponseWriter, r *http.Request) {
 		DisplayName:    req.DisplayName,
 		ImageRef:       req.ImageRef,
 		Config:         req.Config,
+		Resources:      req.Resources,
+		Platform:       req.Platform,
 		Status:         models.StatusCreated,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}
 
 	if err := d.store.CreateInstance(r.Context(), instance); err != nil {
-		d.logger.Error().Err(err).Msg("failed to create instance")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to create instance")
+		d.requestLogger(r).Error().Err(err).Msg("failed to create instance")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to create instance")
 		return
 	}
 
+	// Record the permissions the package declares so they can be merged with user grants
+	if req.Permissions != nil {
+		if err := d.policy.SetDeclaredPermissions(r.Context(), instance.InstanceID, *req.Permissions); err != nil {
+			d.requestLogger(r).Error().Err(err).Msg("failed to store declared permissions")
+			// Don't leave an instance behind without the permissions it declares
+			if delErr := d.store.DeleteInstance(r.Context(), instance.InstanceID); delErr != nil {
+				d.requestLogger(r).Error().Err(delErr).Str("instance_id", instance.InstanceID).Msg("failed to remove instance")
+			}
+			writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to store declared permissions")
+			return
+		}
+	}
+
 	// Emit instance created event
 	d.EmitEvent(EventInstanceCreated, InstanceStatusData{
 		InstanceID: instance.InstanceID,
@@ -302,30 +517,32 @@ func (d *Daemon) handleGetInstance(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
 			return
 		}
-		d.logger.Error().Err(err).Msg("failed to get instance")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to get instance")
+		d.requestLogger(r).Error().Err(err).Msg("failed to get instance")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
 		return
 	}
 
 	writeJSON(w, http.StatusOK, instance)
 }
 
-// handleDeleteInstance removes an instance.
+// handleDeleteInstance removes an instance: its container is stopped and
+// removed first. With ?purge_image=true the image is removed as well, unless
+// another instance uses it, and the response reports what happened to it.
 func (d *Daemon) handleDeleteInstance(w http.ResponseWriter, r *http.Request) {
 	instanceID := chi.URLParam(r, "instanceID")
+	purgeImage := r.URL.Query().Get("purge_image") == "true"
 
 	// Get instance info before deletion for the event
 	instance, _ := d.store.GetInstance(r.Context(), instanceID)
 
-	// TODO: Stop container if running, cleanup resources
-
-	if err := d.store.DeleteInstance(r.Context(), instanceID); err != nil {
-		if conduitErr, ok := err.(*models.ConduitError); ok && conduitErr.Code == models.ErrInstanceNotFound {
-			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
-			return
-		}
-		d.logger.Error().Err(err).Msg("failed to delete instance")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to delete instance")
+	// Finish the removal even if the client goes away
+	ctx := context.WithoutCancel(r.Context())
+	result, err := d.lifecycle.RemoveInstanceWithOptions(ctx, instanceID, lifecycle.RemoveOptions{
+		PurgeImage: purgeImage,
+	})
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Str("instance_id", instanceID).Msg("failed to delete instance")
+		writeLifecycleError(w, err, "failed to delete instance")
 		return
 	}
 
@@ -340,10 +557,15 @@ func (d *Daemon) handleDeleteInstance(w http.ResponseWriter, r *http.Request) {
 		Status:     "deleted",
 	})
 
+	if purgeImage {
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // handleStartInstance starts a connector instance.
+// The container is launched through the lifecycle manager with the instance's security spec.
 func (d *Daemon) handleStartInstance(w http.ResponseWriter, r *http.Request) {
 	instanceID := chi.URLParam(r, "instanceID")
 
@@ -353,42 +575,98 @@ func (d *Daemon) handleStartInstance(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to get instance")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
 		return
 	}
 
-	// Check valid transition
-	if !models.IsValidTransition(instance.Status, models.StatusStarting) {
-		writeError(w, http.StatusConflict, models.ErrInvalidTransition,
-			"cannot start instance in current state: "+string(instance.Status))
+	prevStatus := string(instance.Status)
+
+	// Async starts return an operation the client can poll
+	if r.URL.Query().Get("async") == "true" {
+		op, err := d.lifecycle.StartInstanceAsync(r.Context(), instanceID)
+		if err != nil {
+			writeLifecycleError(w, err, "failed to start instance")
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"instance_id":  instanceID,
+			"operation_id": op.OperationID,
+			"status":       op.Status,
+			"message":      "instance start in progress",
+		})
 		return
 	}
 
-	// TODO: Start container using RuntimeProvider
-	// For now, just update status
-	prevStatus := string(instance.Status)
+	// Container operations must finish even if the client disconnects,
+	// otherwise the instance is left in a transitional state
+	ctx := context.WithoutCancel(r.Context())
+	startErr := d.lifecycle.StartInstance(ctx, instanceID)
 
-	if err := d.store.UpdateInstanceStatus(r.Context(), instanceID, models.StatusRunning, ""); err != nil {
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to update instance status")
+	// Report the state the instance actually ended up in (RUNNING, DEGRADED, ...)
+	updated, err := d.store.GetInstance(ctx, instanceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
 		return
 	}
+	if string(updated.Status) != prevStatus {
+		d.EmitEvent(EventInstanceStatusChanged, InstanceStatusData{
+			InstanceID: instanceID,
+			Name:       updated.DisplayName,
+			Status:     string(updated.Status),
+			PrevStatus: prevStatus,
+		})
+	}
 
-	// Emit status change event
-	d.EmitEvent(EventInstanceStatusChanged, InstanceStatusData{
-		InstanceID: instanceID,
-		Name:       instance.DisplayName,
-		Status:     string(models.StatusRunning),
-		PrevStatus: prevStatus,
-	})
+	if startErr != nil {
+		d.requestLogger(r).Error().Err(startErr).Str("instance_id", instanceID).Msg("failed to start instance")
+		writeLifecycleError(w, startErr, "failed to start instance")
+		return
+	}
+
+	message := "instance started"
+	if updated.Status == models.StatusDegraded {
+		message = "instance started but health check failed"
+	}
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"instance_id": instanceID,
-		"status":      models.StatusRunning,
-		"message":     "instance started",
+		"instance_id":  instanceID,
+		"status":       updated.Status,
+		"container_id": updated.ContainerID,
+		"message":      message,
+	})
+}
+
+// handleInstallInstance runs the install flow (audit, policy check, image pull) in the background.
+// The response carries an operation ID that can be polled via GET /api/v1/operations/{operationID}.
+func (d *Daemon) handleInstallInstance(w http.ResponseWriter, r *http.Request) {
+	instanceID := chi.URLParam(r, "instanceID")
+
+	var req struct {
+		SkipPull bool `json:"skip_pull"`
+	}
+	if r.ContentLength > 0 {
+		if !decodeJSONBody(w, r, &req) {
+			return
+		}
+	}
+
+	op, err := d.lifecycle.InstallInstanceWithOptions(r.Context(), instanceID, lifecycle.InstallOptions{
+		SkipPull: req.SkipPull,
+	})
+	if err != nil {
+		writeLifecycleError(w, err, "failed to install instance")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"instance_id":  instanceID,
+		"operation_id": op.OperationID,
+		"status":       op.Status,
+		"message":      "installation in progress",
 	})
 }
 
-// handleStopInstance stops a connector instance.
+// handleStopInstance stops a connector instance and terminates its container.
 func (d *Daemon) handleStopInstance(w http.ResponseWriter, r *http.Request) {
 	instanceID := chi.URLParam(r, "instanceID")
 
@@ -398,24 +676,30 @@ func (d *Daemon) handleStopInstance(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to get instance")
-		return
-	}
-
-	// Check valid transition
-	if !models.IsValidTransition(instance.Status, models.StatusStopping) {
-		writeError(w, http.StatusConflict, models.ErrInvalidTransition,
-			"cannot stop instance in current state: "+string(instance.Status))
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
 		return
 	}
 
-	// TODO: Stop container using RuntimeProvider
 	prevStatus := string(instance.Status)
 
-	if err := d.store.UpdateInstanceStopped(r.Context(), instanceID); err != nil {
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to update instance status")
+	// Optional grace period before the container is killed, e.g. ?timeout=5s or ?timeout=5
+	var timeout time.Duration
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		timeout, err = parseStopTimeout(t)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid timeout: "+t)
+			return
+		}
+	}
+
+	ctx := context.WithoutCancel(r.Context())
+	started := time.Now()
+	if err := d.lifecycle.StopInstanceWithTimeout(ctx, instanceID, timeout); err != nil {
+		d.requestLogger(r).Error().Err(err).Str("instance_id", instanceID).Msg("failed to stop instance")
+		writeLifecycleError(w, err, "failed to stop instance")
 		return
 	}
+	elapsed := time.Since(started)
 
 	// Emit status change event
 	d.EmitEvent(EventInstanceStatusChanged, InstanceStatusData{
@@ -429,17 +713,284 @@ func (d *Daemon) handleStopInstance(w http.ResponseWriter, r *http.Request) {
 		"instance_id": instanceID,
 		"status":      models.StatusStopped,
 		"message":     "instance stopped",
+		"duration":    elapsed.Round(time.Millisecond).String(),
+		"duration_ms": elapsed.Milliseconds(),
+	})
+}
+
+// handleRestartInstance stops an instance and starts it again with a fresh container.
+func (d *Daemon) handleRestartInstance(w http.ResponseWriter, r *http.Request) {
+	instanceID := chi.URLParam(r, "instanceID")
+
+	instance, err := d.store.GetInstance(r.Context(), instanceID)
+	if err != nil {
+		if conduitErr, ok := err.(*models.ConduitError); ok && conduitErr.Code == models.ErrInstanceNotFound {
+			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
+		return
+	}
+
+	prevStatus := string(instance.Status)
+
+	var timeout time.Duration
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		timeout, err = parseStopTimeout(t)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid timeout: "+t)
+			return
+		}
+	}
+
+	ctx := context.WithoutCancel(r.Context())
+	started := time.Now()
+	restartErr := d.lifecycle.RestartInstance(ctx, instanceID, timeout)
+	elapsed := time.Since(started)
+
+	updated, err := d.store.GetInstance(ctx, instanceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
+		return
+	}
+	if string(updated.Status) != prevStatus {
+		d.EmitEvent(EventInstanceStatusChanged, InstanceStatusData{
+			InstanceID: instanceID,
+			Name:       updated.DisplayName,
+			Status:     string(updated.Status),
+			PrevStatus: prevStatus,
+		})
+	}
+
+	if restartErr != nil {
+		d.requestLogger(r).Error().Err(restartErr).Str("instance_id", instanceID).Msg("failed to restart instance")
+		writeLifecycleError(w, restartErr, "failed to restart instance")
+		return
+	}
+
+	message := "instance restarted"
+	if updated.Status == models.StatusDegraded {
+		message = "instance restarted but health check failed"
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"instance_id":  instanceID,
+		"status":       updated.Status,
+		"container_id": updated.ContainerID,
+		"message":      message,
+		"duration":     elapsed.Round(time.Millisecond).String(),
+		"duration_ms":  elapsed.Milliseconds(),
+	})
+}
+
+// parseStopTimeout parses a stop grace period given as a duration ("5s") or whole seconds ("5").
+func parseStopTimeout(s string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(s); err == nil {
+		if secs < 0 {
+			return 0, fmt.Errorf("negative timeout")
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative timeout")
+	}
+	return d, nil
+}
+
+// writeLifecycleError maps a lifecycle manager error to an HTTP error response.
+func writeLifecycleError(w http.ResponseWriter, err error, fallback string) {
+	var conduitErr *models.ConduitError
+	if !errors.As(err, &conduitErr) {
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, fallback+": "+err.Error())
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch conduitErr.Code {
+	case models.ErrInstanceNotFound, models.ErrOperationNotFound:
+		status = http.StatusNotFound
+	case models.ErrInvalidTransition:
+		status = http.StatusConflict
+	case models.ErrRuntimeUnavailable:
+		status = http.StatusServiceUnavailable
+	case models.ErrPermissionRequired:
+		status = http.StatusForbidden
+	case models.ErrSecretNotFound, models.ErrConfigInvalid:
+		status = http.StatusBadRequest
+	}
+	writeError(w, status, conduitErr.Code, conduitErr.Error())
+}
+
+// handleListOperations returns tracked lifecycle operations.
+// GET /api/v1/operations?active=true limits the list to pending and running operations.
+func (d *Daemon) handleListOperations(w http.ResponseWriter, r *http.Request) {
+	activeOnly := r.URL.Query().Get("active") == "true"
+	ops := d.lifecycle.ListOperations(r.Context(), activeOnly)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"operations": ops,
+		"total":      len(ops),
+	})
+}
+
+// handleGetOperation returns the status, stage and progress of a lifecycle operation.
+func (d *Daemon) handleGetOperation(w http.ResponseWriter, r *http.Request) {
+	operationID := chi.URLParam(r, "operationID")
+
+	op, err := d.lifecycle.GetOperation(r.Context(), operationID)
+	if err != nil {
+		writeLifecycleError(w, err, "failed to get operation")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, op)
+}
+
+// handleCollectGarbage removes the containers and images labeled
+// conduit.managed=true that no instance owns.
+// POST /api/v1/gc?dry_run=true only lists them; min_age (e.g. 30m, or 0)
+// overrides runtime.gc_min_age.
+func (d *Daemon) handleCollectGarbage(w http.ResponseWriter, r *http.Request) {
+	opts := lifecycle.GCOptions{
+		DryRun: r.URL.Query().Get("dry_run") == "true",
+		MinAge: d.cfg.Runtime.GCMinAge,
+	}
+	if v := r.URL.Query().Get("min_age"); v != "" {
+		minAge, err := time.ParseDuration(v)
+		if err != nil || minAge < 0 {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid min_age: "+v)
+			return
+		}
+		opts.MinAge = minAge
+	}
+
+	result, err := d.lifecycle.CollectGarbage(context.WithoutCancel(r.Context()), opts)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("garbage collection failed")
+		writeLifecycleError(w, err, "garbage collection failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleInstanceLogs returns (or follows) the container logs for an instance.
+// Query parameters: tail (lines), since (duration like "1h" or RFC3339), follow (true/false).
+// When follow=true the response is streamed as text/plain until the client disconnects.
+func (d *Daemon) handleInstanceLogs(w http.ResponseWriter, r *http.Request) {
+	instanceID := chi.URLParam(r, "instanceID")
+
+	instance, err := d.store.GetInstance(r.Context(), instanceID)
+	if err != nil {
+		if conduitErr, ok := err.(*models.ConduitError); ok && conduitErr.Code == models.ErrInstanceNotFound {
+			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
+		return
+	}
+
+	if instance.ContainerID == "" {
+		writeError(w, http.StatusNotFound, models.ErrContainerNotFound,
+			"no container has been created for this instance (status: "+string(instance.Status)+")")
+		return
+	}
+
+	if d.runtime == nil {
+		writeError(w, http.StatusServiceUnavailable, models.ErrRuntimeUnavailable,
+			"no container runtime available")
+		return
+	}
+
+	// A stopped instance can still have logs as long as its container exists
+	containerStatus, err := d.runtime.Status(r.Context(), instance.ContainerID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, models.ErrContainerNotFound,
+			"container no longer exists: "+instance.ContainerID)
+		return
+	}
+
+	opts := runtime.LogOptions{
+		Follow: r.URL.Query().Get("follow") == "true",
+	}
+	if tailStr := r.URL.Query().Get("tail"); tailStr != "" {
+		if tail, err := strconv.Atoi(tailStr); err == nil && tail > 0 {
+			opts.Tail = tail
+		}
+	}
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid,
+				"invalid since value: use a duration (1h), a date (2024-01-01) or RFC3339 timestamp")
+			return
+		}
+		opts.Since = since
+	}
+
+	if opts.Follow {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, http.StatusInternalServerError, models.ErrInternal, "streaming not supported")
+			return
+		}
+
+		// Followed logs outlive the server's WriteTimeout, so lift the deadline
+		http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		// Streams until the client disconnects (request context cancelled)
+		if err := d.runtime.StreamLogs(r.Context(), instance.ContainerID, &flushWriter{w: w, f: flusher}, opts); err != nil && r.Context().Err() == nil {
+			d.requestLogger(r).Warn().Err(err).Str("instance_id", instanceID).Msg("log stream ended with error")
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := d.runtime.StreamLogs(r.Context(), instance.ContainerID, &buf, opts); err != nil {
+		d.requestLogger(r).Error().Err(err).Str("instance_id", instanceID).Msg("failed to get container logs")
+		writeError(w, http.StatusInternalServerError, models.ErrContainerFailed, "failed to get container logs")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"instance_id":      instanceID,
+		"container_id":     instance.ContainerID,
+		"status":           instance.Status,
+		"container_status": containerStatus,
+		"logs":             buf.String(),
 	})
 }
 
+// flushWriter flushes after every write so followed logs reach the client immediately.
+type flushWriter struct {
+	w io.Writer
+	f http.Flusher
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.f.Flush()
+	return n, err
+}
+
 // Binding endpoints
 
 // handleListBindings returns all bindings.
 func (d *Daemon) handleListBindings(w http.ResponseWriter, r *http.Request) {
 	bindings, err := d.store.ListBindings(r.Context())
 	if err != nil {
-		d.logger.Error().Err(err).Msg("failed to list bindings")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to list bindings")
+		d.requestLogger(r).Error().Err(err).Msg("failed to list bindings")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to list bindings")
 		return
 	}
 
@@ -452,38 +1003,93 @@ func (d *Daemon) handleListBindings(w http.ResponseWriter, r *http.Request) {
 // handleCreateBinding creates a new client binding.
 func (d *Daemon) handleCreateBinding(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		InstanceID string `json:"instance_id"`
-		ClientID   string `json:"client_id"`
-		Scope      string `json:"scope,omitempty"`
+		InstanceID  string `json:"instance_id"`
+		ClientID    string `json:"client_id"`
+		Scope       string `json:"scope,omitempty"`
+		ProjectPath string `json:"project_path,omitempty"`
+		Force       bool   `json:"force,omitempty"` // bind even if the instance is not healthy
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid request body")
+	if !decodeJSONBody(w, r, &req) {
 		return
 	}
 
 	if req.Scope == "" {
 		req.Scope = "project"
 	}
+	if req.ProjectPath != "" {
+		if err := validateProjectPath(req.ProjectPath); err != nil {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
+			return
+		}
+	}
 
-	// TODO: Use adapter to inject MCP configuration
-	// For now, create binding record
+	adapter, err := d.adapters.Get(req.ClientID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, models.ErrClientNotFound, fmt.Sprintf("unknown client: %s", req.ClientID))
+		return
+	}
+
+	instance, err := d.store.GetInstance(r.Context(), req.InstanceID)
+	if err != nil {
+		if conduitErr, ok := err.(*models.ConduitError); ok && conduitErr.Code == models.ErrInstanceNotFound {
+			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
+			return
+		}
+		d.requestLogger(r).Error().Err(err).Msg("failed to get instance")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
+		return
+	}
+
+	if !req.Force {
+		if msg := d.bindBlocker(r.Context(), instance); msg != "" {
+			writeError(w, http.StatusConflict, models.ErrInstanceNotReady, msg)
+			return
+		}
+	}
+
+	// Inject the MCP server entry into the client's config
+	plan, err := adapter.PlanInjection(r.Context(), adapters.PlanRequest{
+		InstanceID:  instance.InstanceID,
+		DisplayName: instance.DisplayName,
+		Scope:       req.Scope,
+		ProjectPath: req.ProjectPath,
+	})
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to plan injection")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, fmt.Sprintf("plan injection: %v", err))
+		return
+	}
+	result, err := adapter.ApplyInjection(r.Context(), plan)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Str("client_id", req.ClientID).Msg("failed to inject client config")
+		adapter.Rollback(r.Context(), plan.ChangeSetID)
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, fmt.Sprintf("inject client config: %v", err))
+		return
+	}
+
+	scope := req.Scope
+	if result.ConfigScope != "" {
+		scope = result.ConfigScope
+	}
 
 	binding := &models.ClientBinding{
 		BindingID:   generateID("bind"),
 		InstanceID:  req.InstanceID,
 		ClientID:    req.ClientID,
-		Scope:       req.Scope,
-		ConfigPath:  "", // Will be set by adapter
-		ChangeSetID: generateID("cs"),
+		Scope:       scope,
+		ConfigPath:  result.ConfigPath,
+		ChangeSetID: plan.ChangeSetID,
 		Status:      "active",
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
 	if err := d.store.CreateBinding(r.Context(), binding); err != nil {
-		d.logger.Error().Err(err).Msg("failed to create binding")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to create binding")
+		d.requestLogger(r).Error().Err(err).Msg("failed to create binding")
+		// Undo the injection so the client config matches the bindings
+		adapter.Rollback(r.Context(), plan.ChangeSetID)
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to create binding")
 		return
 	}
 
@@ -498,6 +1104,22 @@ func (d *Daemon) handleCreateBinding(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, binding)
 }
 
+// bindBlocker returns why an instance should not be bound to a client, or ""
+// if it can be. A running instance must also pass a health check.
+func (d *Daemon) bindBlocker(ctx context.Context, instance *models.ConnectorInstance) string {
+	if !models.IsBindable(instance.Status) {
+		return fmt.Sprintf("instance %s is %s and cannot be started", instance.InstanceID, instance.Status)
+	}
+
+	if instance.Status == models.StatusRunning || instance.Status == models.StatusDegraded {
+		health, err := d.lifecycle.CheckHealth(ctx, instance.InstanceID)
+		if err == nil && health.Status == "unhealthy" {
+			return fmt.Sprintf("instance %s is unhealthy: %s", instance.InstanceID, health.Message)
+		}
+	}
+	return ""
+}
+
 // handleGetBinding returns a specific binding.
 func (d *Daemon) handleGetBinding(w http.ResponseWriter, r *http.Request) {
 	bindingID := chi.URLParam(r, "bindingID")
@@ -508,7 +1130,7 @@ func (d *Daemon) handleGetBinding(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusNotFound, models.ErrBindingNotFound, "binding not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to get binding")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get binding")
 		return
 	}
 
@@ -522,14 +1144,35 @@ func (d *Daemon) handleDeleteBinding(w http.ResponseWriter, r *http.Request) {
 	// Get binding info before deletion for the event
 	binding, _ := d.store.GetBinding(r.Context(), bindingID)
 
-	// TODO: Use adapter to rollback MCP configuration
+	// Remove the injected MCP configuration
+	if binding != nil {
+		if adapter, err := d.adapters.Get(binding.ClientID); err == nil {
+			// Only this binding's server entry is removed; restoring the
+			// bind-time backup would undo later edits and other bindings
+			result, err := adapter.RemoveInjection(r.Context(), adapters.BindingInfo{
+				BindingID:  binding.BindingID,
+				InstanceID: binding.InstanceID,
+				ClientID:   binding.ClientID,
+				ConfigPath: binding.ConfigPath,
+				Scope:      binding.Scope,
+			})
+			if err == nil && !result.Success {
+				err = fmt.Errorf("%s", strings.Join(result.Errors, "; "))
+			}
+			if err != nil {
+				// The binding is removed anyway; the config entry can be
+				// deleted by hand
+				d.requestLogger(r).Warn().Err(err).Str("binding_id", bindingID).Msg("failed to remove client config entry")
+			}
+		}
+	}
 
 	if err := d.store.DeleteBinding(r.Context(), bindingID); err != nil {
 		if conduitErr, ok := err.(*models.ConduitError); ok && conduitErr.Code == models.ErrBindingNotFound {
 			writeError(w, http.StatusNotFound, models.ErrBindingNotFound, "binding not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to delete binding")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to delete binding")
 		return
 	}
 
@@ -546,14 +1189,31 @@ func (d *Daemon) handleDeleteBinding(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// validateProjectPath checks that a request-supplied project path is an
+// existing directory given as a clean absolute path, so a bind cannot write
+// client configs through relative or traversing paths.
+func validateProjectPath(path string) error {
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return fmt.Errorf("project_path must be a clean absolute path")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("project_path: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("project_path is not a directory: %s", path)
+	}
+	return nil
+}
+
 // Client endpoints
 
 // handleListClients returns all detected clients.
 func (d *Daemon) handleListClients(w http.ResponseWriter, r *http.Request) {
 	results, err := d.adapters.DetectAll(r.Context())
 	if err != nil {
-		d.logger.Error().Err(err).Msg("failed to detect clients")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to detect clients")
+		d.requestLogger(r).Error().Err(err).Msg("failed to detect clients")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to detect clients")
 		return
 	}
 
@@ -581,13 +1241,13 @@ func (d *Daemon) handleGetClient(w http.ResponseWriter, r *http.Request) {
 
 	adapter, err := d.adapters.Get(clientID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "client not found")
+		writeError(w, http.StatusNotFound, models.ErrClientNotFound, "client not found")
 		return
 	}
 
 	detect, err := adapter.Detect(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "detection failed")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "detection failed")
 		return
 	}
 
@@ -598,136 +1258,438 @@ func (d *Daemon) handleGetClient(w http.ResponseWriter, r *http.Request) {
 		Version:     detect.Version,
 		Notes:       detect.Notes,
 	})
-}
+}
+
+// KB endpoints
+
+// handleListKBSources returns all KB sources.
+func (d *Daemon) handleListKBSources(w http.ResponseWriter, r *http.Request) {
+	sources, err := d.kbSource.List(r.Context())
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to list KB sources")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to list sources")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"sources": sources,
+		"count":   len(sources),
+	})
+}
+
+// handleAddKBSource adds a new KB source.
+func (d *Daemon) handleAddKBSource(w http.ResponseWriter, r *http.Request) {
+	var req kb.AddSourceRequest
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+
+	source, err := d.kbSource.Add(r.Context(), req)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to add KB source")
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
+		return
+	}
+
+	if source.SyncMode == kb.SyncModeWatch {
+		d.refreshKBWatcher(r.Context())
+	}
+
+	// Emit source added event
+	d.EmitEvent(EventKBSourceAdded, KBSourceData{
+		SourceID: source.SourceID,
+		Name:     source.Name,
+		Path:     source.Path,
+	})
+
+	writeJSON(w, http.StatusCreated, source)
+}
+
+// handleGetKBSource returns a specific KB source.
+func (d *Daemon) handleGetKBSource(w http.ResponseWriter, r *http.Request) {
+	sourceID := chi.URLParam(r, "sourceID")
+
+	source, err := d.kbSource.Get(r.Context(), sourceID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, models.ErrSourceNotFound, "source not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, source)
+}
+
+// handleDeleteKBSource removes a KB source.
+func (d *Daemon) handleDeleteKBSource(w http.ResponseWriter, r *http.Request) {
+	sourceID := chi.URLParam(r, "sourceID")
+
+	// Get source info before deletion for the event
+	source, _ := d.kbSource.Get(r.Context(), sourceID)
+
+	// Removing a source deletes its documents, chunks and entities; keep a
+	// copy so the removal can be undone with kb restore
+	if source != nil {
+		backupPath, err := d.store.AutoBackup(r.Context(), "kb-remove")
+		if err != nil {
+			d.requestLogger(r).Error().Err(err).Msg("backup before KB source removal failed")
+			writeError(w, http.StatusInternalServerError, models.ErrBackupFailed,
+				err.Error()+" (set database.auto_backup: false to remove without a backup)")
+			return
+		}
+		if backupPath != "" {
+			d.requestLogger(r).Info().Str("backup", backupPath).Msg("backed up database before KB source removal")
+		}
+	}
+
+	result, err := d.kbSource.Remove(r.Context(), sourceID)
+	if err != nil {
+		if source == nil {
+			writeError(w, http.StatusNotFound, models.ErrSourceNotFound, err.Error())
+			return
+		}
+		d.requestLogger(r).Error().Err(err).Msg("failed to remove KB source")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		return
+	}
+
+	d.requestLogger(r).Info().
+		Str("source_id", sourceID).
+		Int("documents", result.DocumentsDeleted).
+		Int("vectors", result.VectorsDeleted).
+		Msg("removed KB source")
+	d.refreshKBWatcher(r.Context())
+
+	// Emit source removed event
+	name := sourceID
+	if source != nil {
+		name = source.Name
+	}
+	d.EmitEvent(EventKBSourceRemoved, KBSourceData{
+		SourceID: sourceID,
+		Name:     name,
+	})
+
+	// Return the removal statistics for transparency
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"documents_deleted": result.DocumentsDeleted,
+		"vectors_deleted":   result.VectorsDeleted,
+	})
+}
+
+// handleKBBackup writes a backup of the database. Without a path the backup
+// goes to a timestamped file in the backups directory.
+func (d *Daemon) handleKBBackup(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path        string `json:"path"`
+		ConfirmPath bool   `json:"confirm_path"`
+	}
+	if r.ContentLength > 0 {
+		if !decodeJSONBody(w, r, &req) {
+			return
+		}
+	}
+
+	path := req.Path
+	if path == "" {
+		path = filepath.Join(d.cfg.BackupsDir(), fmt.Sprintf("conduit-%s.db", time.Now().Format("20060102-150405")))
+	}
+	if err := d.checkBackupPath(path, req.ConfirmPath); err != nil {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
+		return
+	}
+
+	if err := d.store.Backup(r.Context(), path); err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("database backup failed")
+		writeError(w, http.StatusInternalServerError, models.ErrBackupFailed, err.Error())
+		return
+	}
+
+	resp := map[string]interface{}{"path": path}
+	if info, err := os.Stat(path); err == nil {
+		resp["size"] = info.Size()
+	}
+	d.requestLogger(r).Info().Str("path", path).Msg("backed up database")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// checkBackupPath allows backup and restore paths in the backups directory.
+// Any other absolute path has to be confirmed by the caller, so a client of
+// the API cannot quietly read or overwrite arbitrary files as the daemon.
+func (d *Daemon) checkBackupPath(path string, confirmed bool) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("backup path must be absolute")
+	}
+	rel, err := filepath.Rel(d.cfg.BackupsDir(), filepath.Clean(path))
+	if err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	if !confirmed {
+		return fmt.Errorf("%s is outside the backups directory %s; set confirm_path to use it", path, d.cfg.BackupsDir())
+	}
+	return nil
+}
+
+// handleKBMCP answers one KB MCP request proxied by "conduit mcp kb", so MCP
+// clients share the daemon's database connection and search configuration
+// instead of opening the database themselves. Notifications get 204.
+func (d *Daemon) handleKBMCP(w http.ResponseWriter, r *http.Request) {
+	// Unknown fields are allowed: the request is relayed from an MCP client
+	// and later protocol versions may add members
+	var req kb.MCPRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, models.ErrRequestTooLarge,
+				fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid MCP request")
+		return
+	}
+
+	// Built per request so it always uses the current hybrid searcher, which
+	// is replaced when Qdrant is attached or detached
+	server := kb.NewMCPServer(d.store.DB(), d.kbHybrid)
+	resp := server.HandleRequest(r.Context(), &req)
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleKBRestore replaces the database with a backup. The current database
+// is backed up first so the restore itself can be undone.
+func (d *Daemon) handleKBRestore(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path        string `json:"path"`
+		ConfirmPath bool   `json:"confirm_path"`
+	}
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if req.Path == "" {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "path is required")
+		return
+	}
+	if err := d.checkBackupPath(req.Path, req.ConfirmPath); err != nil {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
+		return
+	}
+	if _, err := os.Stat(req.Path); err != nil {
+		writeError(w, http.StatusNotFound, models.ErrBackupNotFound, "backup not found: "+req.Path)
+		return
+	}
+
+	previous, err := d.store.AutoBackup(r.Context(), "pre-restore")
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, models.ErrBackupFailed, err.Error())
+		return
+	}
+
+	if err := d.store.Restore(r.Context(), req.Path); err != nil {
+		d.requestLogger(r).Error().Err(err).Str("path", req.Path).Msg("database restore failed")
+		writeError(w, http.StatusInternalServerError, models.ErrRestoreFailed, err.Error())
+		return
+	}
+
+	d.requestLogger(r).Info().Str("path", req.Path).Str("previous", previous).Msg("restored database")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"restored_from":   req.Path,
+		"previous_backup": previous,
+	})
+}
+
+// handleSyncKBSource triggers a sync for a KB source.
+func (d *Daemon) handleSyncKBSource(w http.ResponseWriter, r *http.Request) {
+	sourceID := chi.URLParam(r, "sourceID")
+	startTime := time.Now()
+
+	// Parse rebuild_vectors parameter
+	rebuildVectors := r.URL.Query().Get("rebuild_vectors") == "true"
+
+	// Emit sync started event
+	d.EmitEvent(EventKBSyncStarted, KBSourceData{
+		SourceID: sourceID,
+	})
+
+	// Create sync options
+	opts := &kb.SyncOptions{
+		RebuildVectors: rebuildVectors,
+	}
+	result, err := d.kbSource.SyncWithOptions(r.Context(), sourceID, opts)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to sync KB source")
+		// Emit sync failed event
+		d.EmitEvent(EventKBSyncFailed, KBSyncResultData{
+			SourceID:     sourceID,
+			ErrorMessage: err.Error(),
+			Duration:     time.Since(startTime).String(),
+		})
+		if d.writeDiskSpaceError(w, r, err) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		return
+	}
+
+	// Emit sync completed event
+	d.EmitEvent(EventKBSyncCompleted, KBSyncResultData{
+		SourceID: sourceID,
+		Added:    result.Added,
+		Updated:  result.Updated,
+		Deleted:  result.Deleted,
+		Skipped:  len(result.Skipped),
+		Errors:   len(result.Errors),
+		Duration: time.Since(startTime).String(),
+	})
 
-// KB endpoints
+	writeJSON(w, http.StatusOK, result)
+}
 
-// handleListKBSources returns all KB sources.
-func (d *Daemon) handleListKBSources(w http.ResponseWriter, r *http.Request) {
+// handleGetKBSchedule reports whether scheduled syncs are enabled, the
+// default interval, and each source's interval and next sync.
+func (d *Daemon) handleGetKBSchedule(w http.ResponseWriter, r *http.Request) {
 	sources, err := d.kbSource.List(r.Context())
 	if err != nil {
-		d.logger.Error().Err(err).Msg("failed to list KB sources")
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", "failed to list sources")
+		d.requestLogger(r).Error().Err(err).Msg("failed to list KB sources")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to list sources")
 		return
 	}
 
+	enabled, interval := d.kbSource.Schedule()
+	entries := make([]map[string]interface{}, 0, len(sources))
+	for _, src := range sources {
+		entries = append(entries, map[string]interface{}{
+			"source_id":         src.SourceID,
+			"name":              src.Name,
+			"status":            src.Status,
+			"sync_interval":     src.SyncInterval,
+			"next_sync":         src.NextSync,
+			"last_sync_attempt": src.LastSyncAttempt,
+			"last_sync_status":  src.LastSyncStatus,
+			"syncing":           src.Syncing,
+		})
+	}
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"sources": sources,
-		"count":   len(sources),
+		"enabled":  enabled,
+		"interval": interval.String(),
+		"sources":  entries,
 	})
 }
 
-// handleAddKBSource adds a new KB source.
-func (d *Daemon) handleAddKBSource(w http.ResponseWriter, r *http.Request) {
-	var req kb.AddSourceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid request body")
+// handleSetKBSourceSchedule overrides a source's scheduled sync interval.
+// The body's interval is a duration such as "30m", "off" or "default".
+func (d *Daemon) handleSetKBSourceSchedule(w http.ResponseWriter, r *http.Request) {
+	sourceID := chi.URLParam(r, "sourceID")
+
+	var req struct {
+		Interval string `json:"interval"`
+	}
+	if !decodeJSONBody(w, r, &req) {
 		return
 	}
-
-	source, err := d.kbSource.Add(r.Context(), req)
-	if err != nil {
-		d.logger.Error().Err(err).Msg("failed to add KB source")
+	if _, _, err := kb.ParseSyncInterval(req.Interval); err != nil {
 		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
 		return
 	}
+	if _, err := d.kbSource.Get(r.Context(), sourceID); err != nil {
+		writeError(w, http.StatusNotFound, models.ErrSourceNotFound, "source not found")
+		return
+	}
 
-	// Emit source added event
-	d.EmitEvent(EventKBSourceAdded, KBSourceData{
-		SourceID: source.SourceID,
-		Name:     source.Name,
-		Path:     source.Path,
-	})
-
-	writeJSON(w, http.StatusCreated, source)
-}
-
-// handleGetKBSource returns a specific KB source.
-func (d *Daemon) handleGetKBSource(w http.ResponseWriter, r *http.Request) {
-	sourceID := chi.URLParam(r, "sourceID")
+	if err := d.kbSource.SetSyncInterval(r.Context(), sourceID, req.Interval); err != nil {
+		d.requestLogger(r).Error().Err(err).Str("source_id", sourceID).Msg("failed to set sync interval")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		return
+	}
 
 	source, err := d.kbSource.Get(r.Context(), sourceID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "E_NOT_FOUND", "source not found")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
 		return
 	}
-
 	writeJSON(w, http.StatusOK, source)
 }
 
-// handleDeleteKBSource removes a KB source.
-func (d *Daemon) handleDeleteKBSource(w http.ResponseWriter, r *http.Request) {
+// handleSetKBSourceWatch switches a source between watch and manual sync
+// mode. The returned source reports whether its folder is being watched.
+func (d *Daemon) handleSetKBSourceWatch(w http.ResponseWriter, r *http.Request) {
 	sourceID := chi.URLParam(r, "sourceID")
 
-	// Get source info before deletion for the event
-	source, _ := d.kbSource.Get(r.Context(), sourceID)
-
-	result, err := d.kbSource.Remove(r.Context(), sourceID)
-	if err != nil {
-		d.logger.Error().Err(err).Msg("failed to remove KB source")
-		writeError(w, http.StatusNotFound, "E_NOT_FOUND", err.Error())
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if _, err := d.kbSource.Get(r.Context(), sourceID); err != nil {
+		writeError(w, http.StatusNotFound, models.ErrSourceNotFound, "source not found")
 		return
 	}
 
-	d.logger.Info().
-		Str("source_id", sourceID).
-		Int("documents", result.DocumentsDeleted).
-		Int("vectors", result.VectorsDeleted).
-		Msg("removed KB source")
-
-	// Emit source removed event
-	name := sourceID
-	if source != nil {
-		name = source.Name
+	if err := d.kbSource.SetWatch(r.Context(), sourceID, req.Enabled); err != nil {
+		d.requestLogger(r).Error().Err(err).Str("source_id", sourceID).Msg("failed to set sync mode")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		return
 	}
-	d.EmitEvent(EventKBSourceRemoved, KBSourceData{
-		SourceID: sourceID,
-		Name:     name,
-	})
+	d.refreshKBWatcher(r.Context())
 
-	// Return the removal statistics for transparency
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"documents_deleted": result.DocumentsDeleted,
-		"vectors_deleted":   result.VectorsDeleted,
-	})
+	source, err := d.kbSource.Get(r.Context(), sourceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, source)
 }
 
-// handleSyncKBSource triggers a sync for a KB source.
-func (d *Daemon) handleSyncKBSource(w http.ResponseWriter, r *http.Request) {
+// handleReindexKBSource drops a source's chunks and vectors and re-ingests
+// all of its files. Progress is published as kb_sync_progress events with
+// phase "reindexing".
+func (d *Daemon) handleReindexKBSource(w http.ResponseWriter, r *http.Request) {
 	sourceID := chi.URLParam(r, "sourceID")
 	startTime := time.Now()
 
-	// Parse rebuild_vectors parameter
-	rebuildVectors := r.URL.Query().Get("rebuild_vectors") == "true"
+	// The index is cleared before re-ingesting, so a client disconnect must not
+	// stop the rebuild halfway and leave the source partially indexed.
+	ctx := observability.ContextWithRequestID(context.Background(), observability.RequestIDFromContext(r.Context()))
 
-	// Emit sync started event
 	d.EmitEvent(EventKBSyncStarted, KBSourceData{
 		SourceID: sourceID,
 	})
 
-	// Create sync options
 	opts := &kb.SyncOptions{
-		RebuildVectors: rebuildVectors,
+		Progress: func(current, total int, path string) {
+			d.EmitEvent(EventKBSyncProgress, KBSyncProgressData{
+				SourceID:   sourceID,
+				Current:    current,
+				Total:      total,
+				Percentage: float64(current) / float64(total) * 100,
+				Phase:      "reindexing",
+			})
+		},
 	}
-	result, err := d.kbSource.SyncWithOptions(r.Context(), sourceID, opts)
+	result, err := d.kbSource.Reindex(ctx, sourceID, opts)
 	if err != nil {
-		d.logger.Error().Err(err).Msg("failed to sync KB source")
-		// Emit sync failed event
+		d.requestLogger(r).Error().Err(err).Str("source_id", sourceID).Msg("failed to reindex KB source")
 		d.EmitEvent(EventKBSyncFailed, KBSyncResultData{
 			SourceID:     sourceID,
 			ErrorMessage: err.Error(),
 			Duration:     time.Since(startTime).String(),
 		})
-		writeError(w, http.StatusInternalServerError, "E_INTERNAL", err.Error())
+		if d.writeDiskSpaceError(w, r, err) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
 		return
 	}
 
-	// Emit sync completed event
 	d.EmitEvent(EventKBSyncCompleted, KBSyncResultData{
 		SourceID: sourceID,
 		Added:    result.Added,
 		Updated:  result.Updated,
 		Deleted:  result.Deleted,
+		Skipped:  len(result.Skipped),
 		Errors:   len(result.Errors),
 		Duration: time.Since(startTime).String(),
 	})
@@ -738,6 +1700,7 @@ func (d *Daemon) handleSyncKBSource(w http.ResponseWriter, r *http.Request) {
 // handleKBSearch searches the knowledge base.
 // Supports modes: "hybrid" (default), "semantic", "fts5"
 // Use raw=true to skip result processing (chunk merging, boilerplate filtering)
+// Use explain=true (hybrid mode) to include a per-result score breakdown
 func (d *Daemon) handleKBSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
@@ -753,37 +1716,56 @@ func (d *Daemon) handleKBSearch(w http.ResponseWriter, r *http.Request) {
 	// Check if raw results are requested
 	rawResults := r.URL.Query().Get("raw") == "true"
 
+	modifiedAfter, modifiedBefore, err := parseKBModifiedRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
+		return
+	}
+
+	snippet, err := d.kbSnippetOpts(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, err.Error())
+		return
+	}
+
 	ctx := r.Context()
 
 	switch mode {
 	case "semantic":
 		// Force semantic search only
 		if d.kbSemantic == nil {
-			writeError(w, http.StatusServiceUnavailable, "E_SEMANTIC_UNAVAILABLE",
+			writeError(w, http.StatusServiceUnavailable, models.ErrSemanticUnavailable,
 				"semantic search unavailable: Qdrant or Ollama not running")
 			return
 		}
-		result, err := d.kbSemantic.Search(ctx, query, d.kbSemanticOpts(r))
+		semOpts := d.kbSemanticOpts(r)
+		semOpts.ModifiedAfter, semOpts.ModifiedBefore = modifiedAfter, modifiedBefore
+		semOpts.ContextLen = snippet.Length
+		result, err := d.kbSemantic.Search(ctx, query, semOpts)
 		if err != nil {
-			d.logger.Error().Err(err).Msg("semantic search failed")
-			writeError(w, http.StatusInternalServerError, "E_INTERNAL", "semantic search failed")
+			d.requestLogger(r).Error().Err(err).Msg("semantic search failed")
+			writeError(w, http.StatusInternalServerError, models.ErrInternal, "semantic search failed")
 			return
 		}
 		if rawResults {
-			writeJSON(w, http.StatusOK, d.convertSemanticResult(result, "semantic"))
+			d.writeKBSearch(w, r, query, len(result.Results), d.convertSemanticResult(result, "semantic", snippet))
 		} else {
-			writeJSON(w, http.StatusOK, d.processSemanticResult(result, "semantic"))
+			d.writeKBSearch(w, r, query, len(result.Results), d.processSemanticResult(result, "semantic", snippet))
 		}
 
 	case "fts5":
 		// Force FTS5 keyword search only
-		result, err := d.kbSearcher.Search(ctx, query, d.kbSearchOpts(r))
+		ftsOpts := d.kbSearchOpts(r)
+		ftsOpts.ModifiedAfter, ftsOpts.ModifiedBefore = modifiedAfter, modifiedBefore
+		ftsOpts.ContextLen = snippet.Length / 2
+		result, err := d.kbSearcher.Search(ctx, query, ftsOpts)
 		if err != nil {
-			d.logger.Error().Err(err).Msg("fts5 search failed")
-			writeError(w, http.StatusInternalServerError, "E_INTERNAL", "fts5 search failed")
+			d.requestLogger(r).Error().Err(err).Msg("fts5 search failed")
+			writeError(w, http.StatusInternalServerError, models.ErrInternal, "fts5 search failed")
 			return
 		}
 		if rawResults {
+			highlightHits(result.Results, query, snippet.Highlight)
 			resp := map[string]interface{}{
 				"results":     result.Results,
 				"total_hits":  result.TotalHits,
@@ -792,9 +1774,9 @@ func (d *Daemon) handleKBSearch(w http.ResponseWriter, r *http.Request) {
 				"search_mode": "fts5",
 				"processed":   false,
 			}
-			writeJSON(w, http.StatusOK, resp)
+			d.writeKBSearch(w, r, query, len(result.Results), resp)
 		} else {
-			writeJSON(w, http.StatusOK, d.processFTS5Result(result, "fts5"))
+			d.writeKBSearch(w, r, query, len(result.Results), d.processFTS5Result(result, "fts5", snippet))
 		}
 
 	case "hybrid":
@@ -802,29 +1784,164 @@ func (d *Daemon) handleKBSearch(w http.ResponseWriter, r *http.Request) {
 	default:
 		// True hybrid search using RRF (Reciprocal Rank Fusion)
 		hybridOpts := d.kbHybridOpts(r)
-		result, err := d.kbHybrid.Search(ctx, query, hybridOpts)
+		hybridOpts.ModifiedAfter, hybridOpts.ModifiedBefore = modifiedAfter, modifiedBefore
+		hybridOpts.SnippetLength = snippet.Length
+		search := d.kbHybrid.Search
+		if kbExplainEmpty(r) {
+			// Diagnosing an empty search starts with the relaxed fallbacks
+			search = d.kbHybrid.SearchWithFallback
+		}
+		result, err := search(ctx, query, hybridOpts)
 		if err != nil {
-			d.logger.Error().Err(err).Msg("hybrid search failed")
-			writeError(w, http.StatusInternalServerError, "E_INTERNAL", "hybrid search failed")
+			d.requestLogger(r).Error().Err(err).Msg("hybrid search failed")
+			writeError(w, http.StatusInternalServerError, models.ErrInternal, "hybrid search failed")
 			return
 		}
 
 		if rawResults {
+			highlightHits(result.Results, query, snippet.Highlight)
 			resp := map[string]interface{}{
-				"results":        result.Results,
-				"total_hits":     result.TotalHits,
-				"query":          result.Query,
-				"search_time":    result.SearchTime,
-				"search_mode":    string(result.Mode),
-				"fts_hits":       result.FTSHits,
-				"semantic_hits":  result.SemanticHits,
-				"query_analysis": result.QueryAnalysis,
-				"processed":      false,
+				"results":              result.Results,
+				"total_hits":           result.TotalHits,
+				"query":                result.Query,
+				"search_time":          result.SearchTime,
+				"search_mode":          string(result.Mode),
+				"fts_hits":             result.FTSHits,
+				"semantic_hits":        result.SemanticHits,
+				"query_analysis":       result.QueryAnalysis,
+				"offset":               result.Offset,
+				"has_more":             result.HasMore,
+				"total_is_lower_bound": result.TotalIsLowerBound,
+				"processed":            false,
 			}
-			writeJSON(w, http.StatusOK, resp)
+			addKBFallback(resp, result)
+			d.writeKBSearch(w, r, query, len(result.Results), resp)
+		} else {
+			resp := d.processHybridResult(result, snippet)
+			addKBFallback(resp, result)
+			d.writeKBSearch(w, r, query, len(result.Results), resp)
+		}
+	}
+}
+
+// kbExplainEmpty reports whether a search asks why it found nothing.
+func kbExplainEmpty(r *http.Request) bool {
+	v := r.URL.Query().Get("explain_empty")
+	return v == "true" || v == "1"
+}
+
+// addKBFallback adds how far a hybrid search had to relax or expand the
+// query to a search response.
+func addKBFallback(resp map[string]interface{}, result *kb.HybridSearchResult) {
+	if result.FallbackLevel > 0 {
+		resp["fallback_level"] = result.FallbackLevel
+		resp["note"] = result.Note
+	}
+	if len(result.SpellingCorrections) > 0 {
+		resp["spelling_corrections"] = result.SpellingCorrections
+	}
+	if len(result.EntityExpansions) > 0 {
+		resp["entity_expansions"] = result.EntityExpansions
+	}
+}
+
+// writeKBSearch writes a search response. When the search found nothing and
+// explain_empty is set, the response carries an explanation in "why_empty".
+func (d *Daemon) writeKBSearch(w http.ResponseWriter, r *http.Request, query string, hits int, resp map[string]interface{}) {
+	if hits == 0 && kbExplainEmpty(r) {
+		opts := d.kbHybridOpts(r)
+		// The range was validated before searching
+		opts.ModifiedAfter, opts.ModifiedBefore, _ = parseKBModifiedRange(r)
+		explanation, err := d.kbHybrid.ExplainEmpty(r.Context(), query, opts)
+		if err != nil {
+			d.requestLogger(r).Warn().Err(err).Msg("failed to explain empty search")
 		} else {
-			writeJSON(w, http.StatusOK, d.processHybridResult(result))
+			resp["why_empty"] = explanation
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// kbDateLayouts are the accepted formats for modified_after/modified_before.
+var kbDateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// parseKBModifiedRange parses the modification time filters of a KB search.
+// modified_after and modified_before take an RFC3339 timestamp or a date
+// (midnight local time); since takes a duration ("72h") and is shorthand for
+// modified_after relative to now.
+func parseKBModifiedRange(r *http.Request) (time.Time, time.Time, error) {
+	parse := func(param string) (time.Time, error) {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			return time.Time{}, nil
+		}
+		for _, layout := range kbDateLayouts {
+			if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid %s value %q: use YYYY-MM-DD or an RFC3339 timestamp", param, value)
+	}
+
+	after, err := parse("modified_after")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	before, err := parse("modified_before")
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		dur, err := time.ParseDuration(sinceStr)
+		if err != nil || dur <= 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid since value %q: use a positive duration (72h)", sinceStr)
+		}
+		if !after.IsZero() {
+			return time.Time{}, time.Time{}, fmt.Errorf("since and modified_after cannot be combined")
+		}
+		after = time.Now().Add(-dur)
+	}
+
+	if !after.IsZero() && !before.IsZero() && !after.Before(before) {
+		return time.Time{}, time.Time{}, fmt.Errorf("modified_after must be earlier than modified_before")
+	}
+	return after, before, nil
+}
+
+// kbSnippetOpts parses the snippet length and highlight style of a KB
+// search, defaulting to the RAG config. Out-of-range values are rejected
+// rather than clamped so callers notice.
+func (d *Daemon) kbSnippetOpts(r *http.Request) (kb.SnippetOptions, error) {
+	ragCfg := d.cfg.KB.RAG
+	length := ragCfg.SnippetLength
+	if length <= 0 {
+		length = kb.DefaultSnippetLength
+	}
+	highlight := ragCfg.HighlightStyle
+
+	if lengthStr := r.URL.Query().Get("snippet_length"); lengthStr != "" {
+		n, err := strconv.Atoi(lengthStr)
+		if err != nil {
+			return kb.SnippetOptions{}, fmt.Errorf("invalid snippet_length value %q", lengthStr)
 		}
+		length = n
+	}
+	if style := r.URL.Query().Get("highlight"); style != "" {
+		highlight = style
+	}
+
+	if err := kb.ValidateSnippetOptions(length, highlight); err != nil {
+		return kb.SnippetOptions{}, err
+	}
+	style, _ := kb.ParseHighlightStyle(highlight)
+	return kb.SnippetOptions{Length: length, Highlight: style}, nil
+}
+
+// highlightHits marks query terms in raw hit snippets.
+func highlightHits(hits []kb.SearchHit, query string, style kb.HighlightStyle) {
+	for i := range hits {
+		hits[i].Snippet = kb.HighlightTerms(hits[i].Snippet, query, style)
 	}
 }
 
@@ -833,16 +1950,26 @@ func (d *Daemon) handleKBSearch(w http.ResponseWriter, r *http.Request) {
 func (d *Daemon) kbHybridOpts(r *http.Request) kb.HybridSearchOptions {
 	ragCfg := d.cfg.KB.RAG
 
-	// Start with config defaults
+	// Start with config defaults; the semantic weight and RRF constant
+	// come from the searcher (see newKBHybridSearcher)
 	opts := kb.HybridSearchOptions{
 		Limit:           ragCfg.DefaultLimit,
 		Mode:            kb.HybridModeAuto,
-		SemanticWeight:  ragCfg.SemanticWeight,
-		RRFConstant:     60,
-		EnableMMR:       ragCfg.EnableMMR,
 		MMRLambda:       ragCfg.MMRLambda,
 		SimilarityFloor: ragCfg.MinScore,
-		EnableRerank:    ragCfg.EnableRerank,
+		SemanticTimeout: ragCfg.SemanticTimeout,
+
+		ExpandEntities:    ragCfg.ExpandEntities,
+		MaxExpansionTerms: ragCfg.MaxExpansionTerms,
+	}
+
+	// MMR and reranking stay unset so the recall preset decides, unless the
+	// config turns them off; both are on by default and in the presets
+	if !ragCfg.EnableMMR {
+		opts.EnableMMR = kb.BoolPtr(false)
+	}
+	if !ragCfg.EnableRerank {
+		opts.EnableRerank = kb.BoolPtr(false)
 	}
 
 	// Fallback to safe defaults if config values are zero
@@ -861,6 +1988,24 @@ func (d *Daemon) kbHybridOpts(r *http.Request) kb.HybridSearchOptions {
 		}
 	}
 
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			opts.Offset = offset
+		}
+	}
+
+	if sourceID := r.URL.Query().Get("source_id"); sourceID != "" {
+		opts.SourceIDs = []string{sourceID}
+	}
+
+	if pathPrefix := r.URL.Query().Get("path_prefix"); pathPrefix != "" {
+		opts.PathPrefix = pathPrefix
+	}
+
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		opts.Languages = kb.ParseLanguages(lang)
+	}
+
 	if modeStr := r.URL.Query().Get("hybrid_mode"); modeStr != "" {
 		switch modeStr {
 		case "fusion":
@@ -887,6 +2032,12 @@ func (d *Daemon) kbHybridOpts(r *http.Request) kb.HybridSearchOptions {
 		}
 	}
 
+	if rrfStr := r.URL.Query().Get("rrf_constant"); rrfStr != "" {
+		if rrf, err := strconv.Atoi(rrfStr); err == nil && rrf > 0 {
+			opts.RRFConstant = rrf
+		}
+	}
+
 	if mmrLambdaStr := r.URL.Query().Get("mmr_lambda"); mmrLambdaStr != "" {
 		if mmrLambda, err := strconv.ParseFloat(mmrLambdaStr, 64); err == nil && mmrLambda >= 0 && mmrLambda <= 1 {
 			opts.MMRLambda = mmrLambda
@@ -894,31 +2045,52 @@ func (d *Daemon) kbHybridOpts(r *http.Request) kb.HybridSearchOptions {
 	}
 
 	if mmrStr := r.URL.Query().Get("enable_mmr"); mmrStr != "" {
-		opts.EnableMMR = mmrStr == "true" || mmrStr == "1"
+		opts.EnableMMR = kb.BoolPtr(mmrStr == "true" || mmrStr == "1")
 	}
 
 	if rerankStr := r.URL.Query().Get("enable_rerank"); rerankStr != "" {
-		opts.EnableRerank = rerankStr == "true" || rerankStr == "1"
+		opts.EnableRerank = kb.BoolPtr(rerankStr == "true" || rerankStr == "1")
+	}
+
+	// Knowledge graph entity names as alternative keyword queries
+	if expandStr := r.URL.Query().Get("expand_entities"); expandStr != "" {
+		opts.ExpandEntities = expandStr == "true" || expandStr == "1"
+	}
+
+	if maxStr := r.URL.Query().Get("max_expansions"); maxStr != "" {
+		if maxTerms, err := strconv.Atoi(maxStr); err == nil && maxTerms > 0 {
+			opts.MaxExpansionTerms = maxTerms
+		}
+	}
+
+	// Diagnostic score breakdown per result (does not change ranking)
+	if explainStr := r.URL.Query().Get("explain"); explainStr != "" {
+		opts.Explain = explainStr == "true" || explainStr == "1"
 	}
 
 	return opts
 }
 
 // processHybridResult processes hybrid search results for cleaner output.
-func (d *Daemon) processHybridResult(result *kb.HybridSearchResult) map[string]interface{} {
+func (d *Daemon) processHybridResult(result *kb.HybridSearchResult, snippet kb.SnippetOptions) map[string]interface{} {
 	processor := kb.NewResultProcessor()
+	processor.SetHighlight(result.Query, snippet.Highlight)
+	processor.SetHigherIsBetter(result.HigherScoresBetter())
 	processed := processor.ProcessResults(result.Results)
 
 	return map[string]interface{}{
-		"results":        processed,
-		"total_hits":     result.TotalHits,
-		"query":          result.Query,
-		"search_time":    result.SearchTime,
-		"search_mode":    string(result.Mode),
-		"fts_hits":       result.FTSHits,
-		"semantic_hits":  result.SemanticHits,
-		"query_analysis": result.QueryAnalysis,
-		"processed":      true,
+		"results":              processed,
+		"total_hits":           result.TotalHits,
+		"query":                result.Query,
+		"search_time":          result.SearchTime,
+		"search_mode":          string(result.Mode),
+		"fts_hits":             result.FTSHits,
+		"semantic_hits":        result.SemanticHits,
+		"query_analysis":       result.QueryAnalysis,
+		"offset":               result.Offset,
+		"has_more":             result.HasMore,
+		"total_is_lower_bound": result.TotalIsLowerBound,
+		"processed":            true,
 	}
 }
 
@@ -931,7 +2103,7 @@ func (d *Daemon) kbSemanticOpts(r *http.Request) kb.SemanticSearchOptions {
 	opts := kb.SemanticSearchOptions{
 		Limit:      ragCfg.DefaultLimit,
 		MinScore:   ragCfg.MinScore,
-		ContextLen: 300,
+		ContextLen: ragCfg.SnippetLength,
 	}
 
 	// Fallback to safe defaults if config values are zero
@@ -960,6 +2132,14 @@ func (d *Daemon) kbSemanticOpts(r *http.Request) kb.SemanticSearchOptions {
 		opts.SourceIDs = []string{sourceID}
 	}
 
+	if pathPrefix := r.URL.Query().Get("path_prefix"); pathPrefix != "" {
+		opts.PathPrefix = pathPrefix
+	}
+
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		opts.Languages = kb.ParseLanguages(lang)
+	}
+
 	// Advanced: min_score override
 	if minScoreStr := r.URL.Query().Get("min_score"); minScoreStr != "" {
 		if minScore, err := strconv.ParseFloat(minScoreStr, 64); err == nil && minScore >= 0 && minScore <= 1 {
@@ -971,7 +2151,7 @@ func (d *Daemon) kbSemanticOpts(r *http.Request) kb.SemanticSearchOptions {
 }
 
 // convertSemanticResult converts semantic search results to a common response format.
-func (d *Daemon) convertSemanticResult(result *kb.SemanticSearchResult, mode string) map[string]interface{} {
+func (d *Daemon) convertSemanticResult(result *kb.SemanticSearchResult, mode string, snippet kb.SnippetOptions) map[string]interface{} {
 	// Convert semantic hits to common format
 	results := make([]map[string]interface{}, len(result.Results))
 	for i, hit := range result.Results {
@@ -980,8 +2160,9 @@ func (d *Daemon) convertSemanticResult(result *kb.SemanticSearchResult, mode str
 			"chunk_id":    hit.ChunkID,
 			"path":        hit.Path,
 			"title":       hit.Title,
-			"snippet":     hit.Snippet,
+			"snippet":     kb.HighlightTerms(hit.Snippet, result.Query, snippet.Highlight),
 			"score":       hit.Score,
+			"raw_score":   hit.Score,
 			"confidence":  hit.Confidence,
 			"metadata":    hit.Metadata,
 		}
@@ -998,7 +2179,7 @@ func (d *Daemon) convertSemanticResult(result *kb.SemanticSearchResult, mode str
 }
 
 // processSemanticResult processes semantic search results with chunk merging and boilerplate filtering.
-func (d *Daemon) processSemanticResult(result *kb.SemanticSearchResult, mode string) map[string]interface{} {
+func (d *Daemon) processSemanticResult(result *kb.SemanticSearchResult, mode string, snippet kb.SnippetOptions) map[string]interface{} {
 	// Convert semantic hits to SearchHit format for processing
 	hits := make([]kb.SearchHit, len(result.Results))
 	for i, r := range result.Results {
@@ -1009,12 +2190,16 @@ func (d *Daemon) processSemanticResult(result *kb.SemanticSearchResult, mode str
 			Title:      r.Title,
 			Snippet:    r.Snippet,
 			Score:      r.Score,
+			RawScore:   r.Score,
 			Metadata:   r.Metadata,
+			Content:    r.Content,
 		}
 	}
 
-	// Process results
+	// Process results; semantic scores are similarities, higher is better
 	processor := kb.NewResultProcessor()
+	processor.SetHighlight(result.Query, snippet.Highlight)
+	processor.SetHigherIsBetter(true)
 	processed := processor.ProcessResults(hits)
 
 	// Convert to response format
@@ -1026,6 +2211,7 @@ func (d *Daemon) processSemanticResult(result *kb.SemanticSearchResult, mode str
 			"title":       p.Title,
 			"content":     p.Content,
 			"score":       p.Score,
+			"raw_score":   p.RawScore,
 			"chunk_count": p.ChunkCount,
 			"metadata":    p.Metadata,
 			"source":      p.Source,
@@ -1043,9 +2229,10 @@ func (d *Daemon) processSemanticResult(result *kb.SemanticSearchResult, mode str
 }
 
 // processFTS5Result processes FTS5 search results with chunk merging and boilerplate filtering.
-func (d *Daemon) processFTS5Result(result *kb.SearchResult, mode string) map[string]interface{} {
+func (d *Daemon) processFTS5Result(result *kb.SearchResult, mode string, snippet kb.SnippetOptions) map[string]interface{} {
 	// Process results
 	processor := kb.NewResultProcessor()
+	processor.SetHighlight(result.Query, snippet.Highlight)
 	processed := processor.ProcessResults(result.Results)
 
 	// Convert to response format
@@ -1057,6 +2244,7 @@ func (d *Daemon) processFTS5Result(result *kb.SearchResult, mode string) map[str
 			"title":       p.Title,
 			"content":     p.Content,
 			"score":       p.Score,
+			"raw_score":   p.RawScore,
 			"chunk_count": p.ChunkCount,
 			"metadata":    p.Metadata,
 			"source":      p.Source,
@@ -1112,43 +2300,232 @@ func (d *Daemon) kbSearchOpts(r *http.Request) kb.SearchOptions {
 		opts.SourceIDs = []string{sourceID}
 	}
 
+	if pathPrefix := r.URL.Query().Get("path_prefix"); pathPrefix != "" {
+		opts.PathPrefix = pathPrefix
+	}
+
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		opts.Languages = kb.ParseLanguages(lang)
+	}
+
 	return opts
 }
 
 // handleKBMigrate migrates existing FTS-indexed documents to vector search.
 func (d *Daemon) handleKBMigrate(w http.ResponseWriter, r *http.Request) {
 	if d.kbSemantic == nil {
-		writeError(w, http.StatusServiceUnavailable, "E_SEMANTIC_UNAVAILABLE",
+		writeError(w, http.StatusServiceUnavailable, models.ErrSemanticUnavailable,
 			"semantic search unavailable: Qdrant or Ollama not running")
 		return
 	}
 
+	if !d.beginKBMigration() {
+		writeError(w, http.StatusConflict, models.ErrMigrationRunning,
+			"a migration is already running; check progress with 'conduit kb migrate --status'")
+		return
+	}
+	defer d.endKBMigration()
+
 	// Use background context to avoid cancellation when HTTP client times out
-	// Migration is a long-running operation that should complete even if client disconnects
-	ctx := context.Background()
+	// Migration is a long-running operation that should complete even if client disconnects.
+	// The request ID is kept so migration logs can still be traced to this call.
+	ctx := observability.ContextWithRequestID(context.Background(), observability.RequestIDFromContext(r.Context()))
+
+	if err := d.checkMigrationDiskSpace(ctx, d.kbSemantic); err != nil {
+		if !d.writeDiskSpaceError(w, r, err) {
+			writeError(w, http.StatusInternalServerError, models.ErrMigrationFailed, err.Error())
+		}
+		return
+	}
+
+	// restart=true re-embeds everything instead of resuming
+	if restart := r.URL.Query().Get("restart"); restart == "true" || restart == "1" {
+		if err := kb.ResetMigration(ctx, d.store.DB()); err != nil {
+			d.requestLogger(r).Error().Err(err).Msg("failed to reset migration")
+			writeError(w, http.StatusInternalServerError, models.ErrMigrationFailed, err.Error())
+			return
+		}
+	}
 
 	// Run migration
-	var migratedCount int
 	progressFn := func(current, total int) {
-		migratedCount = current
-		d.logger.Info().
+		d.requestLogger(r).Info().
 			Int("current", current).
 			Int("total", total).
 			Msg("migration progress")
 	}
 
 	if err := d.kbSemantic.MigrateFromFTS(ctx, progressFn); err != nil {
-		d.logger.Error().Err(err).Msg("migration failed")
-		writeError(w, http.StatusInternalServerError, "E_MIGRATION_FAILED", err.Error())
+		d.requestLogger(r).Error().Err(err).Msg("migration failed")
+		writeError(w, http.StatusInternalServerError, models.ErrMigrationFailed, err.Error())
 		return
 	}
 
+	status, err := kb.GetMigrationStatus(ctx, d.store.DB())
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to read migration status")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":    "completed",
+		"migrated":  status.Migrated,
+		"total":     status.Total,
+		"remaining": status.Remaining,
+	})
+}
+
+// checkMigrationDiskSpace refuses a migration whose vectors would not fit
+// in the data directory. Documents that already have vectors are
+// overwritten in place, so only those without count.
+func (d *Daemon) checkMigrationDiskSpace(ctx context.Context, semantic *kb.SemanticSearcher) error {
+	minFree := d.cfg.Disk.MinFreeBytes()
+	if minFree <= 0 {
+		return nil
+	}
+	need, err := semantic.EstimateMigrationBytes(ctx)
+	if err != nil {
+		return err
+	}
+	return diskspace.Check(d.cfg.DataDir, need, minFree, "migrate the knowledge base to vectors")
+}
+
+// writeDiskSpaceError answers with 507 Insufficient Storage when err is a
+// disk space refusal, and reports whether it did.
+func (d *Daemon) writeDiskSpaceError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var insufficient *diskspace.InsufficientError
+	if !errors.As(err, &insufficient) {
+		return false
+	}
+	d.requestLogger(r).Warn().Err(err).Msg("refused: not enough disk space")
+	writeError(w, http.StatusInsufficientStorage, models.ErrInsufficientDisk, err.Error())
+	return true
+}
+
+// handleKBMigrateStatus reports FTS-to-vector migration progress.
+func (d *Daemon) handleKBMigrateStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := kb.GetMigrationStatus(r.Context(), d.store.DB())
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to read migration status")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		return
+	}
+
+	d.mu.RLock()
+	running := d.kbMigrating
+	d.mu.RUnlock()
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"status":   "completed",
-		"migrated": migratedCount,
+		"running":   running,
+		"total":     status.Total,
+		"migrated":  status.Migrated,
+		"remaining": status.Remaining,
 	})
 }
 
+// handleListKBEntities lists extracted KAG entities.
+// Query parameters: type, search, limit, offset.
+func (d *Daemon) handleListKBEntities(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := kb.EntityListOptions{
+		Type:   q.Get("type"),
+		Search: q.Get("search"),
+	}
+	if limit, err := strconv.Atoi(q.Get("limit")); err == nil && limit > 0 {
+		opts.Limit = limit
+	}
+	if offset, err := strconv.Atoi(q.Get("offset")); err == nil && offset >= 0 {
+		opts.Offset = offset
+	}
+
+	result, err := kb.NewKAGSearcher(d.store.DB(), nil).ListEntities(r.Context(), opts)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to list entities")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to list entities")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleGetKBEntity returns an entity with its relations and source documents.
+func (d *Daemon) handleGetKBEntity(w http.ResponseWriter, r *http.Request) {
+	name, err := entityNameParam(r)
+	if err != nil || name == "" {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "invalid entity name")
+		return
+	}
+
+	detail, err := kb.NewKAGSearcher(d.store.DB(), nil).GetEntityDetail(r.Context(), name)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Str("entity", name).Msg("failed to get entity")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get entity")
+		return
+	}
+	if detail == nil {
+		writeError(w, http.StatusNotFound, models.ErrEntityNotFound, "entity not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// entityNameParam returns the entity name from the wildcard route. chi matches
+// against the escaped path when the name contains an escaped "/", leaving the
+// parameter escaped, so it is decoded in that case only.
+func entityNameParam(r *http.Request) (string, error) {
+	name := chi.URLParam(r, "*")
+	if r.URL.RawPath == "" {
+		return name, nil
+	}
+	return url.PathUnescape(name)
+}
+
+// handleGetKBDocument returns an indexed document with its full text. The
+// document is named by ID in the URL, or by path in the "path" parameter.
+func (d *Daemon) handleGetKBDocument(w http.ResponseWriter, r *http.Request) {
+	ref := chi.URLParam(r, "documentID")
+	if ref == "" {
+		ref = r.URL.Query().Get("path")
+	}
+	if ref == "" {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "document ID or path is required")
+		return
+	}
+
+	doc, err := d.kbSource.GetDocumentContent(r.Context(), ref)
+	if errors.Is(err, kb.ErrDocumentNotFound) {
+		writeError(w, http.StatusNotFound, models.ErrDocumentNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Str("document", ref).Msg("failed to get document")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get document")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// beginKBMigration marks a migration as running. It returns false if one
+// already is, since two runs would embed the same documents twice.
+func (d *Daemon) beginKBMigration() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.kbMigrating {
+		return false
+	}
+	d.kbMigrating = true
+	return true
+}
+
+// endKBMigration clears the running migration flag.
+func (d *Daemon) endKBMigration() {
+	d.mu.Lock()
+	d.kbMigrating = false
+	d.mu.Unlock()
+}
+
 // Version information (set at build time)
 var (
 	Version   = "dev"
@@ -1374,9 +2751,14 @@ func getContainerInfo(runtimePath, containerName string) map[string]interface{}
 
 // getSQLiteInfo returns SQLite database information
 func (d *Daemon) getSQLiteInfo() map[string]interface{} {
+	fts5 := "available"
+	if err := store.CheckFTS5(context.Background()); err != nil {
+		fts5 = "unavailable"
+	}
 	info := map[string]interface{}{
 		"available":    true,
-		"fts5_enabled": true, // Always true since we build with FTS5 tag
+		"fts5":         fts5,
+		"fts5_enabled": fts5 == "available",
 	}
 
 	// Get SQLite version
@@ -1429,7 +2811,7 @@ func (d *Daemon) getKAGStats() map[string]interface{} {
 	db.QueryRow("SELECT COUNT(*) FROM kb_extraction_status WHERE status='completed'").Scan(&processed)
 	db.QueryRow(`SELECT COUNT(*) FROM kb_chunks c
 		LEFT JOIN kb_extraction_status s ON c.chunk_id = s.chunk_id
-		WHERE s.status IS NULL`).Scan(&pending)
+		WHERE s.status IS NULL AND ` + kb.ExtractableChunkFilter).Scan(&pending)
 	db.QueryRow("SELECT COUNT(*) FROM kb_extraction_status WHERE status='error'").Scan(&errors)
 
 	// Check if entity/relation tables exist
@@ -0,0 +1,188 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/simpleflo/conduit/internal/config"
+	"github.com/simpleflo/conduit/pkg/models"
+)
+
+func TestDecodeJSONBody(t *testing.T) {
+	var req struct {
+		Path string `json:"path"`
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"path": "/docs"}`))
+	rec := httptest.NewRecorder()
+	if !decodeJSONBody(rec, r, &req) {
+		t.Fatalf("valid body rejected: %s", rec.Body.String())
+	}
+	if req.Path != "/docs" {
+		t.Errorf("expected path /docs, got %q", req.Path)
+	}
+}
+
+// The handlers decode their body before touching any daemon state, so a
+// zero Daemon is enough to exercise the rejections.
+func TestHandlersRejectInvalidBodies(t *testing.T) {
+	d := &Daemon{}
+	handlers := map[string]http.HandlerFunc{
+		"create instance": d.handleCreateInstance,
+		"create binding":  d.handleCreateBinding,
+		"add KB source":   d.handleAddKBSource,
+	}
+	oversized := `{"display_name": "` + strings.Repeat("x", maxRequestBodySize) + `"}`
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   models.ErrorCode
+		wantMsg    string
+	}{
+		{"oversized", oversized, http.StatusRequestEntityTooLarge, models.ErrRequestTooLarge, "exceeds"},
+		{"unknown field", `{"nmae": "docs"}`, http.StatusBadRequest, models.ErrConfigInvalid, `unknown field "nmae"`},
+		{"trailing data", `{} {}`, http.StatusBadRequest, models.ErrConfigInvalid, "single JSON object"},
+		{"malformed", `{"path": `, http.StatusBadRequest, models.ErrConfigInvalid, "invalid request body"},
+		{"empty", ``, http.StatusBadRequest, models.ErrConfigInvalid, "empty"},
+	}
+
+	for handlerName, handler := range handlers {
+		for _, tt := range tests {
+			t.Run(handlerName+"/"+tt.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				handler(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+				if rec.Code != tt.wantStatus {
+					t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+				}
+				var resp struct {
+					Error struct {
+						Code    models.ErrorCode `json:"code"`
+						Message string           `json:"message"`
+					} `json:"error"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("invalid error response %q: %v", rec.Body.String(), err)
+				}
+				if resp.Error.Code != tt.wantCode || !strings.Contains(resp.Error.Message, tt.wantMsg) {
+					t.Errorf("expected %s containing %q, got %s %q", tt.wantCode, tt.wantMsg, resp.Error.Code, resp.Error.Message)
+				}
+			})
+		}
+	}
+}
+
+func TestHandleCreateBindingRejectsProjectPath(t *testing.T) {
+	d := &Daemon{}
+	file := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, path := range map[string]string{
+		"relative":      "project",
+		"traversal":     "/tmp/../etc",
+		"missing":       filepath.Join(t.TempDir(), "missing"),
+		"not directory": file,
+	} {
+		t.Run(name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{
+				"instance_id":  "inst_1",
+				"client_id":    "claude-code",
+				"project_path": path,
+			})
+			rec := httptest.NewRecorder()
+			d.handleCreateBinding(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body))))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), "project_path") {
+				t.Errorf("expected a project_path error, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestCheckBackupPath(t *testing.T) {
+	d := &Daemon{cfg: &config.Config{DataDir: t.TempDir()}}
+	backups := d.cfg.BackupsDir()
+
+	tests := []struct {
+		name      string
+		path      string
+		confirmed bool
+		wantErr   bool
+	}{
+		{"in backups directory", filepath.Join(backups, "conduit.db"), false, false},
+		{"nested in backups directory", filepath.Join(backups, "old", "conduit.db"), false, false},
+		{"relative", "conduit.db", true, true},
+		{"backups directory itself", backups, false, true},
+		{"traversal out of backups", backups + "/../conduit.db", false, true},
+		{"elsewhere", "/etc/passwd", false, true},
+		{"elsewhere confirmed", filepath.Join(t.TempDir(), "conduit.db"), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.checkBackupPath(tt.path, tt.confirmed)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkBackupPath(%q, %v) error = %v, wantErr %v", tt.path, tt.confirmed, err, tt.wantErr)
+			}
+		})
+	}
+
+	// Unconfirmed paths are refused before the store is touched
+	for _, handler := range []http.HandlerFunc{d.handleKBBackup, d.handleKBRestore} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"path": "/etc/passwd"}`)))
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "confirm_path") {
+			t.Errorf("expected a confirm_path error, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestKBHybridOptsLeavesTogglesUnset(t *testing.T) {
+	d := &Daemon{cfg: config.DefaultConfig()}
+
+	opts := d.kbHybridOpts(httptest.NewRequest(http.MethodGet, "/?q=quota", nil))
+	if opts.EnableMMR != nil || opts.EnableRerank != nil {
+		t.Errorf("expected MMR and rerank left to the recall preset, got %v/%v", opts.EnableMMR, opts.EnableRerank)
+	}
+
+	opts = d.kbHybridOpts(httptest.NewRequest(http.MethodGet, "/?q=quota&enable_mmr=false&enable_rerank=true", nil))
+	if opts.EnableMMR == nil || *opts.EnableMMR || opts.EnableRerank == nil || !*opts.EnableRerank {
+		t.Errorf("expected the request toggles to be set, got %v/%v", opts.EnableMMR, opts.EnableRerank)
+	}
+
+	d.cfg.KB.RAG.EnableMMR = false
+	opts = d.kbHybridOpts(httptest.NewRequest(http.MethodGet, "/?q=quota", nil))
+	if opts.EnableMMR == nil || *opts.EnableMMR || opts.EnableRerank != nil {
+		t.Errorf("expected the config to turn MMR off only, got %v/%v", opts.EnableMMR, opts.EnableRerank)
+	}
+}
+
+func TestEntityNameParam(t *testing.T) {
+	var got string
+	r := chi.NewRouter()
+	r.Get("/entities/*", func(w http.ResponseWriter, r *http.Request) {
+		got, _ = entityNameParam(r)
+	})
+
+	for _, name := range []string{"Conduit", "TCP/IP", "client/server/app", "C++", "100%", "a b"} {
+		got = ""
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/entities/"+url.PathEscape(name), nil))
+		if rec.Code != http.StatusOK || got != name {
+			t.Errorf("%q: status %d, name %q", name, rec.Code, got)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/simpleflo/conduit/internal/installer"
+	"github.com/simpleflo/conduit/pkg/models"
+)
+
+// handleOllamaPull starts pulling an Ollama model in the background.
+// Progress is published as ollama_pull_* events on the SSE stream; the pulled
+// model is verified with a tiny test request before completion is reported.
+// POST /api/v1/ollama/pull
+func (d *Daemon) handleOllamaPull(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model string `json:"model"`
+	}
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	model := strings.TrimSpace(req.Model)
+	if model == "" {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "model is required")
+		return
+	}
+
+	d.mu.Lock()
+	if d.ollamaPulls[model] {
+		d.mu.Unlock()
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{
+			"model":  model,
+			"status": "already_pulling",
+		})
+		return
+	}
+	d.ollamaPulls[model] = true
+	d.mu.Unlock()
+
+	go d.pullOllamaModel(model)
+
+	writeJSON(w, http.StatusAccepted, map[string]interface{}{
+		"model":   model,
+		"status":  "started",
+		"message": "Pull started. Progress is reported on /api/v1/events.",
+	})
+}
+
+// pullOllamaModel runs a pull to completion and emits progress events.
+func (d *Daemon) pullOllamaModel(model string) {
+	defer func() {
+		d.mu.Lock()
+		delete(d.ollamaPulls, model)
+		d.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-d.shutdownCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	d.EmitEvent(EventOllamaPullStarted, OllamaPullData{Model: model, Status: "started"})
+
+	// Only emit when the status or whole percentage changes; Ollama sends
+	// many updates per second while downloading.
+	lastStatus, lastPercent := "", -1
+	pullOpts := installer.PullOptions{MinFree: d.cfg.Disk.MinFreeBytes()}
+	err := installer.PullOllamaModelWithOptions(ctx, installer.DefaultOllamaHost, model, pullOpts, func(p installer.PullProgress) {
+		if p.Status == lastStatus && int(p.Percent) == lastPercent {
+			return
+		}
+		lastStatus, lastPercent = p.Status, int(p.Percent)
+		d.EmitEvent(EventOllamaPullProgress, OllamaPullData{
+			Model:      model,
+			Status:     p.Status,
+			Completed:  p.Completed,
+			Total:      p.Total,
+			Percentage: p.Percent,
+		})
+	})
+	if err == nil {
+		d.EmitEvent(EventOllamaPullProgress, OllamaPullData{Model: model, Status: "verifying", Percentage: 100})
+		err = installer.VerifyOllamaModel(ctx, installer.DefaultOllamaHost, model)
+	}
+
+	if err != nil {
+		d.logger.Error().Err(err).Str("model", model).Msg("ollama pull failed")
+		d.EmitEvent(EventOllamaPullFailed, OllamaPullData{
+			Model:        model,
+			Status:       "failed",
+			ErrorMessage: err.Error(),
+		})
+		return
+	}
+
+	d.logger.Info().Str("model", model).Msg("ollama model pulled and verified")
+	d.EmitEvent(EventOllamaPullCompleted, OllamaPullData{Model: model, Status: "completed", Percentage: 100})
+}
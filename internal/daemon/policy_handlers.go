@@ -0,0 +1,238 @@
+package daemon
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/simpleflo/conduit/internal/policy"
+	"github.com/simpleflo/conduit/pkg/models"
+)
+
+// permissionTypes are the grant categories stored by the policy engine.
+var permissionTypes = map[string]bool{
+	"filesystem": true,
+	"network":    true,
+	"secrets":    true,
+	"exposure":   true,
+}
+
+// handleGetInstancePermissions returns the declared, granted and effective
+// permissions for an instance.
+// GET /api/v1/policy/instances/{instanceID}
+func (d *Daemon) handleGetInstancePermissions(w http.ResponseWriter, r *http.Request) {
+	instanceID := chi.URLParam(r, "instanceID")
+	if !d.requireInstance(w, r, instanceID) {
+		return
+	}
+
+	perms, err := d.policy.GetEffectivePermissions(r.Context(), instanceID)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Str("instance_id", instanceID).Msg("failed to get permissions")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get permissions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, perms)
+}
+
+// handleGrantPermissions adds user grants for an instance.
+// The body is a policy.PermissionSet; it is merged with existing grants.
+// Grants that hit forbidden paths are rejected.
+// POST /api/v1/policy/instances/{instanceID}/grants
+func (d *Daemon) handleGrantPermissions(w http.ResponseWriter, r *http.Request) {
+	instanceID := chi.URLParam(r, "instanceID")
+	if !d.requireInstance(w, r, instanceID) {
+		return
+	}
+
+	var grant policy.PermissionSet
+	if !decodeJSONBody(w, r, &grant) {
+		return
+	}
+	if grant.Network.Mode != "" && grant.Network.Mode != "none" && grant.Network.Mode != "egress" {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "network mode must be none or egress")
+		return
+	}
+	if len(grant.Network.EgressDomains) > 0 {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid,
+			"network egress cannot be limited to hosts: the container runtime can only allow all hosts or none; grant egress without egress_domains")
+		return
+	}
+
+	// Run the grant through the policy rules so forbidden paths can never be approved
+	decision, err := d.policy.Evaluate(r.Context(), policy.Request{
+		Scope:      policy.ScopePermissionChange,
+		InstanceID: instanceID,
+		Actor:      "user",
+		Requested:  grant,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to evaluate grant")
+		return
+	}
+	if decision.Decision == policy.Deny {
+		writeError(w, http.StatusForbidden, models.ErrPermissionDenied,
+			"grant denied by policy: "+strings.Join(decision.BlockReasons, "; "))
+		return
+	}
+
+	existing, err := d.policy.GetUserGrants(r.Context(), instanceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get existing grants")
+		return
+	}
+
+	if err := d.policy.GrantPermission(r.Context(), instanceID, existing.Merge(grant)); err != nil {
+		d.requestLogger(r).Error().Err(err).Str("instance_id", instanceID).Msg("failed to grant permissions")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to grant permissions")
+		return
+	}
+
+	perms, err := d.policy.GetEffectivePermissions(r.Context(), instanceID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get permissions")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, perms)
+}
+
+// handleRevokePermission removes all user grants of one type for an instance.
+// DELETE /api/v1/policy/instances/{instanceID}/grants/{permissionType}
+func (d *Daemon) handleRevokePermission(w http.ResponseWriter, r *http.Request) {
+	instanceID := chi.URLParam(r, "instanceID")
+	permType := chi.URLParam(r, "permissionType")
+
+	if !permissionTypes[permType] {
+		writeError(w, http.StatusBadRequest, models.ErrConfigInvalid,
+			"unknown permission type: "+permType+" (expected filesystem, network, secrets or exposure)")
+		return
+	}
+	if !d.requireInstance(w, r, instanceID) {
+		return
+	}
+
+	if err := d.policy.RevokePermission(r.Context(), instanceID, permType); err != nil {
+		d.requestLogger(r).Error().Err(err).Str("instance_id", instanceID).Msg("failed to revoke permission")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to revoke permission")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"instance_id":     instanceID,
+		"permission_type": permType,
+		"message":         "permission revoked",
+	})
+}
+
+// handleEvaluatePolicy previews the decision for a permission request.
+// Nothing is recorded or created, so clients can show the verdict before
+// installing a connector.
+// POST /api/v1/policy/evaluate
+func (d *Daemon) handleEvaluatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req policy.Request
+	if !decodeJSONBody(w, r, &req) {
+		return
+	}
+	if req.Scope == "" {
+		req.Scope = policy.ScopeInstall
+	}
+	if req.Actor == "" {
+		req.Actor = "user"
+	}
+
+	decision, err := d.policy.Preview(r.Context(), req)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to evaluate policy")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to evaluate policy")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, decision)
+}
+
+// handleListDecisions returns recorded policy decisions, newest first.
+// GET /api/v1/policy/decisions?instance_id=&decision=&since=&limit=
+func (d *Daemon) handleListDecisions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := policy.DecisionFilter{
+		InstanceID: q.Get("instance_id"),
+		Limit:      100,
+	}
+
+	if decision := q.Get("decision"); decision != "" {
+		filter.Decision = policy.DecisionType(strings.ToUpper(decision))
+		switch filter.Decision {
+		case policy.Allow, policy.Warn, policy.Deny:
+		default:
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "decision must be allow, warn or deny")
+			return
+		}
+	}
+
+	if since := q.Get("since"); since != "" {
+		t, err := parseSince(since)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "since must be RFC3339, a date like 2024-01-01 or a duration like 24h")
+			return
+		}
+		filter.Since = t
+	}
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, models.ErrConfigInvalid, "limit must be a non-negative integer")
+			return
+		}
+		filter.Limit = n
+	}
+
+	decisions, err := d.policy.ListDecisions(r.Context(), filter)
+	if err != nil {
+		d.requestLogger(r).Error().Err(err).Msg("failed to list policy decisions")
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to list policy decisions")
+		return
+	}
+	if decisions == nil {
+		decisions = []*policy.Decision{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"decisions": decisions,
+		"count":     len(decisions),
+	})
+}
+
+// parseSince accepts an RFC3339 timestamp, a date (midnight local time), or
+// a duration relative to now.
+func parseSince(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	dur, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-dur), nil
+}
+
+// requireInstance writes a 404 and returns false if the instance does not exist.
+func (d *Daemon) requireInstance(w http.ResponseWriter, r *http.Request, instanceID string) bool {
+	if _, err := d.store.GetInstance(r.Context(), instanceID); err != nil {
+		if conduitErr, ok := err.(*models.ConduitError); ok && conduitErr.Code == models.ErrInstanceNotFound {
+			writeError(w, http.StatusNotFound, models.ErrInstanceNotFound, "instance not found")
+			return false
+		}
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "failed to get instance")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/simpleflo/conduit/internal/store"
+	"github.com/simpleflo/conduit/pkg/models"
+)
+
+func TestParseSince(t *testing.T) {
+	if got, err := parseSince("2024-01-01T10:30:00Z"); err != nil || !got.Equal(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("RFC3339: got %v, %v", got, err)
+	}
+
+	// A plain date is midnight local time
+	if got, err := parseSince("2024-01-01"); err != nil || !got.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("date: got %v, %v", got, err)
+	}
+
+	before := time.Now()
+	got, err := parseSince("1h")
+	after := time.Now()
+	if err != nil {
+		t.Fatalf("duration: %v", err)
+	}
+	if got.Before(before.Add(-time.Hour)) || got.After(after.Add(-time.Hour)) {
+		t.Errorf("duration: got %v, want an hour ago", got)
+	}
+
+	for _, bad := range []string{"yesterday", "2024-13-01", "2024/01/01", "01-01-2024"} {
+		if _, err := parseSince(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestHandleGrantPermissionsRejectsEgressDomains(t *testing.T) {
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Skipf("store unavailable: %v", err)
+	}
+	defer st.Close()
+	if err := st.CreateInstance(context.Background(), &models.ConnectorInstance{
+		InstanceID: "inst-1", PackageID: "test/connector", PackageVersion: "1.0.0",
+		DisplayName: "Test", ImageRef: "test:latest", Status: models.StatusCreated,
+	}); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	// The container runtimes cannot filter egress by host
+	d := &Daemon{store: st}
+	r := chi.NewRouter()
+	r.Post("/instances/{instanceID}/grants", d.handleGrantPermissions)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/instances/inst-1/grants",
+		strings.NewReader(`{"network": {"mode": "egress", "egress_domains": ["api.example.com"]}}`)))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "cannot be limited to hosts") {
+		t.Errorf("expected the host list to be rejected, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -41,10 +41,11 @@ func (d *Daemon) handleQdrantAttach(w http.ResponseWriter, r *http.Request) {
 	// Initialize semantic search with the same config as in daemon.go New()
 	semanticCfg := kb.SemanticSearchConfig{
 		EmbeddingConfig: kb.EmbeddingConfig{
-			OllamaHost: "http://localhost:11434",
-			Model:      "nomic-embed-text",
-			Dimension:  768,
-			BatchSize:  10,
+			OllamaHost:  "http://localhost:11434",
+			Model:       "nomic-embed-text",
+			Dimension:   768,
+			BatchSize:   d.cfg.KB.EmbeddingBatchSize,
+			Concurrency: d.cfg.KB.EmbeddingConcurrency,
 		},
 		VectorStoreConfig: kb.VectorStoreConfig{
 			Host:           "localhost",
@@ -67,10 +68,10 @@ func (d *Daemon) handleQdrantAttach(w http.ResponseWriter, r *http.Request) {
 	d.kbSemantic = semantic
 	d.kbSource.SetSemanticSearcher(semantic)
 	d.kbIndexer.SetSemanticSearcher(semantic)
-	d.kbHybrid = kb.NewHybridSearcher(d.kbSearcher, semantic)
+	d.kbHybrid = newKBHybridSearcher(d.cfg, d.store, d.kbSearcher, semantic)
 	d.mu.Unlock()
 
-	d.logger.Info().Msg("semantic search enabled via hot-reload")
+	d.requestLogger(r).Info().Msg("semantic search enabled via hot-reload")
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"status":  "attached",
@@ -95,10 +96,10 @@ func (d *Daemon) handleQdrantDetach(w http.ResponseWriter, r *http.Request) {
 	d.kbSemantic = nil
 	d.kbSource.SetSemanticSearcher(nil)
 	d.kbIndexer.SetSemanticSearcher(nil)
-	d.kbHybrid = kb.NewHybridSearcher(d.kbSearcher, nil)
+	d.kbHybrid = newKBHybridSearcher(d.cfg, d.store, d.kbSearcher, nil)
 	d.mu.Unlock()
 
-	d.logger.Info().Msg("semantic search disabled via hot-reload")
+	d.requestLogger(r).Info().Msg("semantic search disabled via hot-reload")
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"status":  "detached",
@@ -118,25 +119,45 @@ func (d *Daemon) handleQdrantReindex(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := d.checkMigrationDiskSpace(r.Context(), semantic); err != nil {
+		if !d.writeDiskSpaceError(w, r, err) {
+			writeError(w, http.StatusInternalServerError, models.ErrInternal, err.Error())
+		}
+		return
+	}
+
+	if !d.beginKBMigration() {
+		writeError(w, http.StatusConflict, models.ErrMigrationRunning,
+			"a migration is already running; check progress with 'conduit kb migrate --status'")
+		return
+	}
+
 	// Run migration in background
 	go func() {
+		defer d.endKBMigration()
 		ctx := context.Background()
+
+		// Re-index everything, not just documents without vectors
+		if err := kb.ResetMigration(ctx, d.store.DB()); err != nil {
+			d.requestLogger(r).Error().Err(err).Msg("reindex failed")
+			return
+		}
 		err := semantic.MigrateFromFTS(ctx, func(current, total int) {
-			d.logger.Info().
+			d.requestLogger(r).Info().
 				Int("current", current).
 				Int("total", total).
 				Msg("reindex progress")
 		})
 		if err != nil {
-			d.logger.Error().Err(err).Msg("reindex failed")
+			d.requestLogger(r).Error().Err(err).Msg("reindex failed")
 		} else {
-			d.logger.Info().Msg("reindex completed")
+			d.requestLogger(r).Info().Msg("reindex completed")
 		}
 	}()
 
 	writeJSON(w, http.StatusAccepted, map[string]interface{}{
 		"status":  "started",
-		"message": "Re-indexing started in background. Check progress with 'conduit kb stats'.",
+		"message": "Re-indexing started in background. Check progress with 'conduit kb migrate --status'.",
 	})
 }
 
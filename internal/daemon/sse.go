@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/simpleflo/conduit/pkg/models"
 )
 
 // handleSSEEvents handles the SSE endpoint for real-time event streaming.
@@ -22,7 +24,7 @@ import (
 func (d *Daemon) handleSSEEvents(w http.ResponseWriter, r *http.Request) {
 	// Check if EventBus is initialized
 	if d.eventBus == nil {
-		http.Error(w, "event bus not available", http.StatusServiceUnavailable)
+		writeError(w, http.StatusServiceUnavailable, models.ErrDaemonUnavailable, "event bus not available")
 		return
 	}
 
@@ -35,19 +37,19 @@ func (d *Daemon) handleSSEEvents(w http.ResponseWriter, r *http.Request) {
 	// Ensure we can flush
 	flusher, ok := w.(http.Flusher)
 	if !ok {
-		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, models.ErrInternal, "streaming not supported")
 		return
 	}
 
 	// Subscribe to events
 	subID, eventCh := d.eventBus.Subscribe()
 	if eventCh == nil {
-		http.Error(w, "event bus closed", http.StatusServiceUnavailable)
+		writeError(w, http.StatusServiceUnavailable, models.ErrDaemonUnavailable, "event bus closed")
 		return
 	}
 	defer d.eventBus.Unsubscribe(subID)
 
-	d.logger.Debug().
+	d.requestLogger(r).Debug().
 		Uint64("subscriber_id", subID).
 		Msg("SSE client connected")
 
@@ -70,7 +72,7 @@ func (d *Daemon) handleSSEEvents(w http.ResponseWriter, r *http.Request) {
 		select {
 		case <-r.Context().Done():
 			// Client disconnected
-			d.logger.Debug().
+			d.requestLogger(r).Debug().
 				Uint64("subscriber_id", subID).
 				Msg("SSE client disconnected")
 			return
@@ -91,7 +93,7 @@ func (d *Daemon) handleSSEEvents(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			if err := writeSSEEvent(w, flusher, event); err != nil {
-				d.logger.Debug().
+				d.requestLogger(r).Debug().
 					Err(err).
 					Uint64("subscriber_id", subID).
 					Msg("failed to write SSE event")
@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/simpleflo/conduit/internal/config"
+	"github.com/simpleflo/conduit/internal/secrets"
+	"github.com/simpleflo/conduit/pkg/models"
+)
+
+// minTCPTokenLength is the shortest token accepted for the TCP listener.
+const minTCPTokenLength = 16
+
+// listenTCP opens the localhost TCP listener when api.tcp is enabled. It
+// returns nil when the listener is disabled and an error when its
+// configuration is unsafe: a non-loopback address or a weak token. Without
+// api.tcp.token, the token file is used, and created if missing.
+func (d *Daemon) listenTCP() (net.Listener, error) {
+	tcp := d.cfg.API.TCP
+	if !tcp.Enabled {
+		return nil, nil
+	}
+	if tcp.Token == "" {
+		token, err := secrets.LoadOrCreateToken(d.cfg.APITokenPath())
+		if err != nil {
+			return nil, fmt.Errorf("api token: %w", err)
+		}
+		tcp.Token = token
+	}
+	if err := validateTCPConfig(tcp); err != nil {
+		return nil, err
+	}
+	d.tcpToken = tcp.Token
+
+	listener, err := net.Listen("tcp", tcp.Address)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", tcp.Address, err)
+	}
+	return listener, nil
+}
+
+// validateTCPConfig checks that the TCP listener is bound to a loopback
+// address and protected by a token.
+func validateTCPConfig(tcp config.TCPConfig) error {
+	host, _, err := net.SplitHostPort(tcp.Address)
+	if err != nil {
+		return fmt.Errorf("api.tcp.address: %w", err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("api.tcp.address must be a loopback address such as 127.0.0.1, got %q", host)
+	}
+	if len(tcp.Token) < minTCPTokenLength {
+		return fmt.Errorf("api.tcp.token must be at least %d characters", minTCPTokenLength)
+	}
+	return nil
+}
+
+// tcpHandler wraps the router for the TCP listener: CORS first, so browser
+// preflight requests (which carry no credentials) are answered, then token
+// authentication.
+func (d *Daemon) tcpHandler() http.Handler {
+	return corsMiddleware(d.cfg.API.TCP.CORSOrigins)(tokenAuthMiddleware(d.tcpToken)(d.router))
+}
+
+// tokenAuthMiddleware rejects requests without "Authorization: Bearer <token>".
+func tokenAuthMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="conduit"`)
+				writeError(w, http.StatusUnauthorized, models.ErrUnauthorized, "missing or invalid API token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// corsMiddleware allows browser requests from the configured origins.
+// Requests from any other origin are refused; requests without an Origin
+// header, from non-browser tools, pass through.
+func corsMiddleware(origins []string) func(http.Handler) http.Handler {
+	allowAll := slices.Contains(origins, "*")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			if !allowAll && !slices.Contains(origins, origin) {
+				writeError(w, http.StatusForbidden, models.ErrPermissionDenied, "origin not allowed: "+origin)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-Id")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
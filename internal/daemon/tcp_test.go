@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/simpleflo/conduit/internal/config"
+)
+
+func TestValidateTCPConfig(t *testing.T) {
+	token := strings.Repeat("t", minTCPTokenLength)
+	tests := []struct {
+		name    string
+		cfg     config.TCPConfig
+		wantErr string
+	}{
+		{"loopback", config.TCPConfig{Address: "127.0.0.1:9470", Token: token}, ""},
+		{"localhost", config.TCPConfig{Address: "localhost:9470", Token: token}, ""},
+		{"ipv6 loopback", config.TCPConfig{Address: "[::1]:9470", Token: token}, ""},
+		{"all interfaces", config.TCPConfig{Address: "0.0.0.0:9470", Token: token}, "loopback"},
+		{"empty host", config.TCPConfig{Address: ":9470", Token: token}, "loopback"},
+		{"lan address", config.TCPConfig{Address: "192.168.1.10:9470", Token: token}, "loopback"},
+		{"no port", config.TCPConfig{Address: "127.0.0.1", Token: token}, "api.tcp.address"},
+		{"no token", config.TCPConfig{Address: "127.0.0.1:9470"}, "api.tcp.token"},
+		{"short token", config.TCPConfig{Address: "127.0.0.1:9470", Token: "secret"}, "api.tcp.token"},
+	}
+	for _, tt := range tests {
+		err := validateTCPConfig(tt.cfg)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestTCPHandler(t *testing.T) {
+	token := "0123456789abcdef"
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := corsMiddleware([]string{"http://localhost:5173"})(tokenAuthMiddleware(token)(ok))
+
+	tests := []struct {
+		name       string
+		method     string
+		headers    map[string]string
+		wantStatus int
+		wantOrigin string
+	}{
+		{"no token", http.MethodGet, nil, http.StatusUnauthorized, ""},
+		{"wrong token", http.MethodGet, map[string]string{"Authorization": "Bearer wrong"}, http.StatusUnauthorized, ""},
+		{"not a bearer token", http.MethodGet, map[string]string{"Authorization": token}, http.StatusUnauthorized, ""},
+		{"token", http.MethodGet, map[string]string{"Authorization": "Bearer " + token}, http.StatusOK, ""},
+		{"allowed origin", http.MethodGet, map[string]string{
+			"Authorization": "Bearer " + token, "Origin": "http://localhost:5173",
+		}, http.StatusOK, "http://localhost:5173"},
+		{"other origin", http.MethodGet, map[string]string{
+			"Authorization": "Bearer " + token, "Origin": "http://evil.example",
+		}, http.StatusForbidden, ""},
+		{"preflight without token", http.MethodOptions, map[string]string{
+			"Origin": "http://localhost:5173", "Access-Control-Request-Method": "POST",
+		}, http.StatusNoContent, "http://localhost:5173"},
+		{"preflight from other origin", http.MethodOptions, map[string]string{
+			"Origin": "http://evil.example", "Access-Control-Request-Method": "POST",
+		}, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, "/api/v1/health", nil)
+		for k, v := range tt.headers {
+			r.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.wantStatus, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+			t.Errorf("%s: expected Access-Control-Allow-Origin %q, got %q", tt.name, tt.wantOrigin, got)
+		}
+	}
+
+	// "*" allows any origin
+	handler = corsMiddleware([]string{"*"})(tokenAuthMiddleware(token)(ok))
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	r.Header.Set("Origin", "http://tool.example")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "http://tool.example" {
+		t.Errorf("wildcard origin: got status %d, origin %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
@@ -0,0 +1,100 @@
+// Package diskspace checks that a filesystem has room for an operation
+// before it starts writing, so a model pull or KB sync fails up front with a
+// clear message instead of part way through with "no space left on device".
+package diskspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MB is one mebibyte, the unit thresholds are configured in.
+const MB = 1024 * 1024
+
+// DefaultMinFree is the free space left untouched by default after an
+// operation's estimated writes.
+const DefaultMinFree = 1024 * MB
+
+// InsufficientError reports that an operation needs more space than is free.
+type InsufficientError struct {
+	Operation string // What was about to run, e.g. "pull llama3"
+	Path      string // The directory the operation writes to
+	Need      int64  // Estimated bytes the operation writes
+	Free      uint64 // Bytes available to this user
+	MinFree   int64  // Bytes to keep free afterwards
+}
+
+func (e *InsufficientError) Error() string {
+	return fmt.Sprintf("not enough disk space to %s: needs about %s plus %s kept free, but only %s is available at %s (free up space, or lower disk.min_free_mb in conduit.yaml)",
+		e.Operation, Format(uint64(e.Need)), Format(uint64(e.MinFree)), Format(e.Free), e.Path)
+}
+
+// Free returns the bytes available to this user on the filesystem holding
+// path. When path does not exist yet, its nearest existing parent is used.
+func Free(path string) (uint64, error) {
+	dir, err := existingDir(path)
+	if err != nil {
+		return 0, err
+	}
+	return freeBytes(dir)
+}
+
+// Check returns an *InsufficientError when the filesystem holding path has
+// less than need+minFree bytes available. A minFree of zero or less turns the
+// check off. If free space cannot be determined the check passes: it guards
+// against a full disk, it should not block on platforms it cannot query.
+func Check(path string, need, minFree int64, operation string) error {
+	if minFree <= 0 {
+		return nil
+	}
+	free, err := Free(path)
+	if err != nil {
+		return nil
+	}
+	if need < 0 {
+		need = 0
+	}
+	if free < uint64(need)+uint64(minFree) {
+		return &InsufficientError{
+			Operation: operation,
+			Path:      path,
+			Need:      need,
+			Free:      free,
+			MinFree:   minFree,
+		}
+	}
+	return nil
+}
+
+// Format renders a byte count as a human-readable size such as "1.5 GB".
+func Format(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// existingDir returns path, or its nearest parent that exists.
+func existingDir(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", fmt.Errorf("no existing directory above %s", path)
+		}
+		path = parent
+	}
+}
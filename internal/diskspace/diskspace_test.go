@@ -0,0 +1,59 @@
+package diskspace
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFree_MissingPath(t *testing.T) {
+	dir := t.TempDir()
+
+	free, err := Free(filepath.Join(dir, "not", "created", "yet"))
+	if err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+	if free == 0 {
+		t.Error("expected free space on the temp dir's filesystem")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	free, err := Free(dir)
+	if err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+
+	if err := Check(dir, 1, MB, "sync"); err != nil {
+		t.Errorf("expected a small write to fit, got %v", err)
+	}
+
+	err = Check(dir, int64(free), MB, "pull big-model")
+	var insufficient *InsufficientError
+	if !errors.As(err, &insufficient) {
+		t.Fatalf("expected *InsufficientError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "pull big-model") || !strings.Contains(err.Error(), "disk.min_free_mb") {
+		t.Errorf("error should name the operation and the setting: %v", err)
+	}
+
+	if err := Check(dir, int64(free), 0, "pull big-model"); err != nil {
+		t.Errorf("min free 0 should disable the check, got %v", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[uint64]string{
+		512:           "512 B",
+		1536:          "1.5 KB",
+		5 * MB:        "5.0 MB",
+		3 * 1024 * MB: "3.0 GB",
+	}
+	for in, want := range tests {
+		if got := Format(in); got != want {
+			t.Errorf("Format(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Binary names looked for by FindInstallations.
+var conduitBinaries = []string{"conduit", "conduit-daemon"}
+
+// BinaryInstall is one copy of a Conduit binary found on the machine.
+type BinaryInstall struct {
+	Name    string `json:"name"`             // conduit or conduit-daemon
+	Path    string `json:"path"`             // Where it was found
+	Target  string `json:"target,omitempty"` // Resolved path when Path is a symlink
+	Source  string `json:"source"`           // How it was most likely installed
+	Version string `json:"version,omitempty"`
+	OnPath  bool   `json:"onPath"`           // Found in a PATH directory
+	Active  bool   `json:"active,omitempty"` // First match on PATH, the one a bare command runs
+}
+
+// ServiceInstall is a launchd, systemd or Windows service that runs
+// conduit-daemon.
+type ServiceInstall struct {
+	Name string `json:"name"`
+	Path string `json:"path,omitempty"`
+}
+
+// InstallationReport lists the Conduit binaries and daemon services found,
+// with warnings when they conflict.
+type InstallationReport struct {
+	Binaries []BinaryInstall  `json:"binaries"`
+	Services []ServiceInstall `json:"services"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// Conflicting reports whether more than one installation was found.
+func (r *InstallationReport) Conflicting() bool {
+	return len(r.Warnings) > 0
+}
+
+// FindInstallations looks for conduit and conduit-daemon binaries on PATH
+// and where the install script, Homebrew, the desktop app and go install put
+// them, and for services registered to run the daemon. Symlinks to a binary
+// already found are not counted again.
+func (i *Installer) FindInstallations(ctx context.Context) *InstallationReport {
+	homeDir, _ := os.UserHomeDir()
+	report := findBinaries(ctx, filepath.SplitList(os.Getenv("PATH")), knownBinaryDirs(homeDir))
+	report.Services = i.findDaemonServices(homeDir)
+	report.Warnings = installationWarnings(report)
+	return report
+}
+
+// knownBinaryDirs returns where Conduit's installers put binaries.
+func knownBinaryDirs(homeDir string) []string {
+	dirs := []string{
+		filepath.Join(homeDir, ".local", "bin"), // install script
+		"/usr/local/bin",                        // install script symlinks, Homebrew on Intel
+		"/opt/homebrew/bin",                     // Homebrew on Apple Silicon
+		"/home/linuxbrew/.linuxbrew/bin",        // Homebrew on Linux
+		filepath.Join(homeDir, "go", "bin"),     // go install
+	}
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		dirs = append(dirs, gobin)
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		dirs = append(dirs,
+			"/Applications/Conduit.app/Contents/Resources/bin",
+			filepath.Join(homeDir, "Applications", "Conduit.app", "Contents", "Resources", "bin"))
+	case "windows":
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Programs", "Conduit", "resources", "bin"))
+		}
+	}
+	return dirs
+}
+
+// findBinaries collects the Conduit binaries in pathDirs, then those in
+// knownDirs not already found, running each for its version.
+func findBinaries(ctx context.Context, pathDirs, knownDirs []string) *InstallationReport {
+	report := &InstallationReport{Binaries: []BinaryInstall{}}
+	seen := make(map[string]bool) // resolved paths
+	active := make(map[string]bool)
+
+	add := func(dir, name string, onPath bool) {
+		if dir == "" {
+			return
+		}
+		path := filepath.Join(dir, name)
+		if runtime.GOOS == "windows" {
+			path += ".exe"
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			return
+		}
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return
+		}
+		if abs, err := filepath.Abs(resolved); err == nil {
+			resolved = abs
+		}
+		if seen[resolved] {
+			return
+		}
+		seen[resolved] = true
+
+		b := BinaryInstall{
+			Name:    name,
+			Path:    path,
+			Source:  installSource(resolved),
+			Version: binaryVersion(ctx, path),
+			OnPath:  onPath,
+			Active:  onPath && !active[name],
+		}
+		if resolved != path {
+			b.Target = resolved
+		}
+		active[name] = active[name] || onPath
+		report.Binaries = append(report.Binaries, b)
+	}
+
+	for _, name := range conduitBinaries {
+		for _, dir := range pathDirs {
+			add(dir, name, true)
+		}
+		for _, dir := range knownDirs {
+			add(dir, name, false)
+		}
+	}
+	return report
+}
+
+// installSource guesses how the binary at path was installed.
+func installSource(path string) string {
+	slashed := filepath.ToSlash(path)
+	switch {
+	case strings.Contains(slashed, "/Cellar/") || strings.Contains(slashed, "/homebrew/") || strings.Contains(slashed, "/linuxbrew/"):
+		return "Homebrew"
+	case strings.Contains(slashed, "Conduit.app/") || strings.Contains(slashed, "/resources/bin/"):
+		return "desktop app"
+	case strings.Contains(slashed, "/.local/bin/"):
+		return "install script"
+	case strings.Contains(slashed, "/go/bin/") || (os.Getenv("GOBIN") != "" && filepath.Dir(path) == os.Getenv("GOBIN")):
+		return "go install"
+	default:
+		return "manual"
+	}
+}
+
+// binaryVersion runs path --version and returns the version it reports,
+// without the build time, or "" when it cannot be run.
+func binaryVersion(ctx context.Context, path string) string {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return parseVersionOutput(string(out))
+}
+
+// parseVersionOutput extracts the version from cobra's --version output,
+// "conduit version 1.2.0 (built 2025-01-01)".
+func parseVersionOutput(output string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	if _, after, ok := strings.Cut(line, " version "); ok {
+		line = after
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(line), " (built")
+	return strings.TrimSpace(version)
+}
+
+// findDaemonServices returns the services registered to run conduit-daemon,
+// under any name.
+func (i *Installer) findDaemonServices(homeDir string) []ServiceInstall {
+	services := []ServiceInstall{}
+
+	var patterns []string
+	switch runtime.GOOS {
+	case "darwin":
+		patterns = []string{
+			filepath.Join(homeDir, "Library", "LaunchAgents", "*.plist"),
+			"/Library/LaunchAgents/*.plist",
+			"/Library/LaunchDaemons/*.plist",
+		}
+	case "linux":
+		patterns = []string{
+			filepath.Join(homeDir, ".config", "systemd", "user", "*.service"),
+			"/etc/systemd/user/*.service",
+			"/etc/systemd/system/*.service",
+		}
+	case "windows":
+		if _, err := i.WindowsServiceState(); err == nil {
+			services = append(services, ServiceInstall{Name: windowsServiceName})
+		}
+	}
+
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			data, err := os.ReadFile(path)
+			if err != nil || !strings.Contains(string(data), "conduit-daemon") {
+				continue
+			}
+			name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".plist"), ".service")
+			services = append(services, ServiceInstall{Name: name, Path: path})
+		}
+	}
+	return services
+}
+
+// installationWarnings describes the conflicts in report: several copies of
+// a binary, binaries at different versions and several daemon services.
+func installationWarnings(report *InstallationReport) []string {
+	var warnings []string
+
+	for _, name := range conduitBinaries {
+		var copies []string
+		for _, b := range report.Binaries {
+			if b.Name == name {
+				copies = append(copies, fmt.Sprintf("%s (%s)", b.Path, b.Source))
+			}
+		}
+		if len(copies) > 1 {
+			warnings = append(warnings, fmt.Sprintf("%d copies of %s installed: %s", len(copies), name, strings.Join(copies, ", ")))
+		}
+	}
+
+	versions := make(map[string][]string)
+	var order []string
+	for _, b := range report.Binaries {
+		if b.Version == "" {
+			continue
+		}
+		if _, ok := versions[b.Version]; !ok {
+			order = append(order, b.Version)
+		}
+		versions[b.Version] = append(versions[b.Version], b.Path)
+	}
+	if len(order) > 1 {
+		var parts []string
+		for _, v := range order {
+			parts = append(parts, fmt.Sprintf("%s at %s", v, strings.Join(versions[v], ", ")))
+		}
+		warnings = append(warnings, "binaries have different versions: "+strings.Join(parts, "; "))
+	}
+
+	if len(report.Services) > 1 {
+		var names []string
+		for _, s := range report.Services {
+			names = append(names, s.Name)
+		}
+		warnings = append(warnings, fmt.Sprintf("%d daemon services registered: %s", len(report.Services), strings.Join(names, ", ")))
+	}
+	return warnings
+}
+
+// CleanupHints says how to remove each kind of extra installation found.
+func (r *InstallationReport) CleanupHints() []string {
+	sources := make(map[string]bool)
+	for _, b := range r.Binaries {
+		sources[b.Source] = true
+	}
+
+	var hints []string
+	if sources["install script"] {
+		hints = append(hints, "Install script: conduit uninstall --keep-data removes ~/.local/bin binaries, /usr/local/bin symlinks and the PATH entry in your shell config")
+	}
+	if sources["Homebrew"] {
+		hints = append(hints, "Homebrew: brew uninstall conduit")
+	}
+	if sources["desktop app"] {
+		hints = append(hints, "Desktop app: remove Conduit from Applications")
+	}
+	if sources["go install"] {
+		hints = append(hints, "go install: delete the binaries from your Go bin directory")
+	}
+	if sources["manual"] {
+		hints = append(hints, "Other copies: delete them by hand")
+	}
+	if len(r.Services) > 1 {
+		hints = append(hints, "Services: keep dev.simpleflo.conduit (conduit service install) and unload/remove the others")
+	}
+	return hints
+}
@@ -0,0 +1,102 @@
+package installer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeFakeBinary writes an executable that prints version like cobra does.
+func writeFakeBinary(t *testing.T, dir, name, version string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\necho '" + name + " version " + version + " (built 2025-01-01)'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindBinaries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake binaries are shell scripts")
+	}
+	ctx := context.Background()
+	root := t.TempDir()
+	scriptDir := filepath.Join(root, ".local", "bin")
+	brewDir := filepath.Join(root, "homebrew", "bin")
+	linkDir := filepath.Join(root, "usr", "local", "bin")
+
+	writeFakeBinary(t, scriptDir, "conduit", "1.2.0")
+	writeFakeBinary(t, scriptDir, "conduit-daemon", "1.2.0")
+	os.MkdirAll(linkDir, 0755)
+	if err := os.Symlink(filepath.Join(scriptDir, "conduit"), filepath.Join(linkDir, "conduit")); err != nil {
+		t.Fatal(err)
+	}
+
+	// One install, reached through PATH and a symlink: no conflict
+	report := findBinaries(ctx, []string{linkDir}, []string{scriptDir})
+	report.Warnings = installationWarnings(report)
+	if len(report.Binaries) != 2 {
+		t.Fatalf("expected 2 binaries, got %+v", report.Binaries)
+	}
+	conduit := report.Binaries[0]
+	if conduit.Path != filepath.Join(linkDir, "conduit") || !conduit.Active || conduit.Source != "install script" || conduit.Version != "1.2.0" {
+		t.Errorf("unexpected conduit entry: %+v", conduit)
+	}
+	if conduit.Target == "" {
+		t.Error("expected symlink target to be recorded")
+	}
+	if report.Conflicting() {
+		t.Errorf("unexpected warnings: %v", report.Warnings)
+	}
+
+	// A second copy from Homebrew at another version
+	writeFakeBinary(t, brewDir, "conduit", "1.1.0")
+	report = findBinaries(ctx, []string{linkDir, brewDir}, []string{scriptDir})
+	report.Services = []ServiceInstall{{Name: "dev.simpleflo.conduit"}, {Name: "com.example.conduit"}}
+	report.Warnings = installationWarnings(report)
+
+	var homebrew *BinaryInstall
+	for i := range report.Binaries {
+		if report.Binaries[i].Source == "Homebrew" {
+			homebrew = &report.Binaries[i]
+		}
+	}
+	if homebrew == nil || homebrew.Active || !homebrew.OnPath {
+		t.Errorf("expected an inactive Homebrew copy on PATH, got %+v", report.Binaries)
+	}
+
+	joined := strings.Join(report.Warnings, "\n")
+	for _, want := range []string{"2 copies of conduit", "different versions", "2 daemon services"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("warnings missing %q:\n%s", want, joined)
+		}
+	}
+	hints := strings.Join(report.CleanupHints(), "\n")
+	for _, want := range []string{"conduit uninstall --keep-data", "brew uninstall conduit"} {
+		if !strings.Contains(hints, want) {
+			t.Errorf("hints missing %q:\n%s", want, hints)
+		}
+	}
+}
+
+func TestParseVersionOutput(t *testing.T) {
+	tests := map[string]string{
+		"conduit version 1.2.0 (built 2025-01-01)\n": "1.2.0",
+		"conduit-daemon version dev (built unknown)": "dev",
+		"1.0.0": "1.0.0",
+		"":      "",
+	}
+	for output, want := range tests {
+		if got := parseVersionOutput(output); got != want {
+			t.Errorf("parseVersionOutput(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
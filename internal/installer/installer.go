@@ -6,12 +6,15 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/simpleflo/conduit/internal/diskspace"
 )
 
 // Dependency represents a software dependency.
@@ -36,6 +39,17 @@ type InstallResult struct {
 type Installer struct {
 	reader  *bufio.Reader
 	verbose bool
+
+	// assumeYes runs unattended: prompts are auto-confirmed and choices take
+	// the recommended option
+	assumeYes bool
+
+	// socketPath is where the daemon listens; empty means the default
+	socketPath string
+
+	// minFree is the disk space model downloads must leave free; zero means
+	// diskspace.DefaultMinFree
+	minFree int64
 }
 
 // New creates a new Installer.
@@ -46,8 +60,39 @@ func New(verbose bool) *Installer {
 	}
 }
 
+// SetAssumeYes enables unattended mode for automated provisioning.
+func (i *Installer) SetAssumeYes(yes bool) {
+	i.assumeYes = yes
+}
+
+// SetSocketPath sets the socket the daemon listens on, for daemons started
+// with a non-default --socket.
+func (i *Installer) SetSocketPath(path string) {
+	i.socketPath = path
+}
+
+// SetMinFree sets the free space, in bytes, model downloads must leave on
+// disk (disk.min_free_mb in conduit.yaml).
+func (i *Installer) SetMinFree(bytes int64) {
+	i.minFree = bytes
+}
+
+// SocketPath returns the socket the daemon is checked on, by default
+// ~/.conduit/conduit.sock.
+func (i *Installer) SocketPath() string {
+	if i.socketPath != "" {
+		return i.socketPath
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".conduit", "conduit.sock")
+}
+
 // CheckAndInstallAll checks and installs all Conduit dependencies.
 func (i *Installer) CheckAndInstallAll(ctx context.Context) ([]InstallResult, error) {
+	if err := i.checkInteractive(); err != nil {
+		return nil, err
+	}
+
 	fmt.Println()
 	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
 	fmt.Println("║              Conduit Dependency Installer                    ║")
@@ -135,7 +180,7 @@ func (i *Installer) installContainerRuntime(ctx context.Context) InstallResult {
 	fmt.Println("  [3] Skip (install manually later)")
 	fmt.Println()
 
-	choice := i.prompt("Choice [1/2/3]: ")
+	choice := i.choose("Choice [1/2/3]: ", "1")
 
 	switch choice {
 	case "1":
@@ -164,7 +209,7 @@ func (i *Installer) installDocker(ctx context.Context) InstallResult {
 	fmt.Println("Installing Docker...")
 
 	var installCmd []string
-	var postInstall []string
+	var postInstall [][]string
 
 	switch runtime.GOOS {
 	case "darwin":
@@ -179,7 +224,7 @@ func (i *Installer) installDocker(ctx context.Context) InstallResult {
 			}
 		}
 		installCmd = []string{"brew", "install", "--cask", "docker"}
-		postInstall = []string{"open", "-a", "Docker"}
+		postInstall = [][]string{{"open", "-a", "Docker"}}
 
 	case "linux":
 		// Linux - detect distro and use appropriate package manager
@@ -194,7 +239,25 @@ func (i *Installer) installDocker(ctx context.Context) InstallResult {
 			return i.installDockerFedora(ctx)
 		case "arch":
 			installCmd = []string{"sudo", "pacman", "-S", "--noconfirm", "docker"}
-			postInstall = []string{"sudo", "systemctl", "enable", "--now", "docker"}
+			postInstall = [][]string{{"sudo", "systemctl", "enable", "--now", "docker"}}
+		case "alpine":
+			// Alpine uses OpenRC rather than systemd
+			installCmd = []string{"sudo", "apk", "add", "docker"}
+			postInstall = [][]string{
+				{"sudo", "rc-update", "add", "docker", "default"},
+				{"sudo", "service", "docker", "start"},
+			}
+			if user := os.Getenv("USER"); user != "" {
+				postInstall = append(postInstall, []string{"sudo", "addgroup", user, "docker"})
+			}
+		case "opensuse", "sles":
+			installCmd = []string{"sudo", "zypper", "--non-interactive", "install", "docker"}
+			postInstall = [][]string{
+				{"sudo", "systemctl", "enable", "--now", "docker"},
+			}
+			if user := os.Getenv("USER"); user != "" {
+				postInstall = append(postInstall, []string{"sudo", "usermod", "-aG", "docker", user})
+			}
 		default:
 			fmt.Printf("Unsupported Linux distribution: %s\n", distro)
 			fmt.Println("Please install Docker manually: https://docs.docker.com/engine/install/")
@@ -244,8 +307,7 @@ func (i *Installer) installDocker(ctx context.Context) InstallResult {
 
 		// Run post-install commands
 		for _, cmd := range postInstall {
-			parts := strings.Fields(cmd)
-			_ = i.runCommand(ctx, parts[0], parts[1:]...)
+			_ = i.runCommand(ctx, cmd[0], cmd[1:]...)
 		}
 
 		fmt.Println("✓ Docker installed successfully")
@@ -383,6 +445,7 @@ func (i *Installer) installPodman(ctx context.Context) InstallResult {
 	fmt.Println("Installing Podman...")
 
 	var installCmd []string
+	var postInstall [][]string
 
 	switch runtime.GOOS {
 	case "darwin":
@@ -405,6 +468,15 @@ func (i *Installer) installPodman(ctx context.Context) InstallResult {
 			installCmd = []string{"sudo", "dnf", "install", "-y", "podman"}
 		case "arch":
 			installCmd = []string{"sudo", "pacman", "-S", "--noconfirm", "podman"}
+		case "alpine":
+			installCmd = []string{"sudo", "apk", "add", "podman"}
+			// Rootless Podman on Alpine needs the cgroups service (OpenRC)
+			postInstall = [][]string{
+				{"sudo", "rc-update", "add", "cgroups"},
+				{"sudo", "service", "cgroups", "start"},
+			}
+		case "opensuse", "sles":
+			installCmd = []string{"sudo", "zypper", "--non-interactive", "install", "podman"}
 		default:
 			fmt.Printf("Unsupported Linux distribution: %s\n", distro)
 			fmt.Println("Please install Podman manually: https://podman.io/docs/installation")
@@ -448,6 +520,10 @@ func (i *Installer) installPodman(ctx context.Context) InstallResult {
 		}
 	}
 
+	for _, cmd := range postInstall {
+		_ = i.runCommand(ctx, cmd[0], cmd[1:]...)
+	}
+
 	// Initialize Podman machine on macOS
 	if runtime.GOOS == "darwin" {
 		fmt.Println("Initializing Podman machine...")
@@ -495,7 +571,7 @@ func (i *Installer) installOllama(ctx context.Context) InstallResult {
 	fmt.Println("  [2] Skip (use Anthropic API instead)")
 	fmt.Println()
 
-	choice := i.prompt("Choice [1/2]: ")
+	choice := i.choose("Choice [1/2]: ", "1")
 
 	if choice != "1" {
 		fmt.Println("Skipping Ollama installation.")
@@ -609,11 +685,12 @@ func (i *Installer) pullOllamaModel(ctx context.Context, model string) InstallRe
 	fmt.Println("This may take several minutes depending on your internet connection.")
 	fmt.Println()
 
-	cmd := exec.CommandContext(ctx, "ollama", "pull", model)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
+	pullOpts := PullOptions{MinFree: i.minFree}
+	if pullOpts.MinFree <= 0 {
+		pullOpts.MinFree = diskspace.DefaultMinFree
+	}
+	if err := PullOllamaModelWithOptions(ctx, DefaultOllamaHost, model, pullOpts, PrintPullProgress); err != nil {
+		fmt.Println()
 		return InstallResult{
 			Dependency: "AI Model",
 			Error:      err,
@@ -622,7 +699,16 @@ func (i *Installer) pullOllamaModel(ctx context.Context, model string) InstallRe
 	}
 
 	fmt.Println()
-	fmt.Printf("✓ Model '%s' downloaded successfully\n", model)
+	fmt.Println("Verifying model...")
+	if err := VerifyOllamaModel(ctx, DefaultOllamaHost, model); err != nil {
+		return InstallResult{
+			Dependency: "AI Model",
+			Error:      err,
+			Message:    "Model failed verification",
+		}
+	}
+
+	fmt.Printf("✓ Model '%s' downloaded and verified\n", model)
 
 	return InstallResult{
 		Dependency: "AI Model",
@@ -670,7 +756,7 @@ func (i *Installer) installDocumentTools(ctx context.Context) []InstallResult {
 	fmt.Println("  [2] Skip (some document formats won't be indexed)")
 	fmt.Println()
 
-	choice := i.prompt("Choice [1/2]: ")
+	choice := i.choose("Choice [1/2]: ", "1")
 
 	if choice != "1" {
 		fmt.Println("Skipping document tools installation.")
@@ -966,6 +1052,10 @@ func (i *Installer) installDocumentToolsWindows(ctx context.Context) []InstallRe
 // InstallDocumentToolsOnly installs only document extraction tools.
 // This is used by the --document-tools flag.
 func (i *Installer) InstallDocumentToolsOnly(ctx context.Context) ([]InstallResult, error) {
+	if err := i.checkInteractive(); err != nil {
+		return nil, err
+	}
+
 	fmt.Println()
 	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
 	fmt.Println("║           Document Extraction Tools Installer                ║")
@@ -1048,27 +1138,38 @@ func (i *Installer) runShellCommand(ctx context.Context, cmd string) error {
 }
 
 func (i *Installer) detectLinuxDistro() string {
-	// Try /etc/os-release first
 	data, err := os.ReadFile("/etc/os-release")
-	if err == nil {
-		content := string(data)
-		if strings.Contains(strings.ToLower(content), "ubuntu") {
-			return "ubuntu"
-		}
-		if strings.Contains(strings.ToLower(content), "debian") {
-			return "debian"
-		}
-		if strings.Contains(strings.ToLower(content), "fedora") {
-			return "fedora"
-		}
-		if strings.Contains(strings.ToLower(content), "rhel") || strings.Contains(strings.ToLower(content), "red hat") {
-			return "rhel"
-		}
-		if strings.Contains(strings.ToLower(content), "centos") {
-			return "centos"
+	if err != nil {
+		return "unknown"
+	}
+	return parseOSRelease(string(data))
+}
+
+// parseOSRelease maps /etc/os-release contents to a supported distro family.
+// ID is checked first, then ID_LIKE, so derivatives (Mint, Rocky, Manjaro)
+// use their parent's package manager.
+func parseOSRelease(content string) string {
+	values := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
 		}
-		if strings.Contains(strings.ToLower(content), "arch") {
-			return "arch"
+		values[key] = strings.ToLower(strings.Trim(value, `"'`))
+	}
+
+	candidates := []string{values["ID"]}
+	candidates = append(candidates, strings.Fields(values["ID_LIKE"])...)
+
+	for _, id := range candidates {
+		switch {
+		case id == "ubuntu", id == "debian", id == "fedora", id == "rhel",
+			id == "centos", id == "arch", id == "alpine":
+			return id
+		case strings.HasPrefix(id, "opensuse"), id == "suse":
+			return "opensuse"
+		case id == "sles", id == "sled":
+			return "sles"
 		}
 	}
 
@@ -1128,11 +1229,38 @@ func (i *Installer) prompt(message string) string {
 	return strings.TrimSpace(response)
 }
 
+// choose prompts for a numbered choice, taking the recommended option
+// in unattended mode.
+func (i *Installer) choose(message, recommended string) string {
+	if i.assumeYes {
+		fmt.Printf("%s%s (--yes)\n", message, recommended)
+		return recommended
+	}
+	return i.prompt(message)
+}
+
 func (i *Installer) confirmAction(message string) bool {
+	if i.assumeYes {
+		fmt.Printf("%s [y/N]: y (--yes)\n", message)
+		return true
+	}
 	response := i.prompt(fmt.Sprintf("%s [y/N]: ", message))
 	return strings.ToLower(response) == "y" || strings.ToLower(response) == "yes"
 }
 
+// checkInteractive fails fast when prompts could not be answered, instead of
+// blocking on a stdin that is not a terminal (CI, GUI delegation).
+func (i *Installer) checkInteractive() error {
+	if i.assumeYes {
+		return nil
+	}
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("stdin is not a terminal; rerun with --yes to install unattended")
+	}
+	return nil
+}
+
 func (i *Installer) printSummary(results []InstallResult) {
 	fmt.Println()
 	fmt.Println("══════════════════════════════════════════════════════════════")
@@ -1190,6 +1318,8 @@ func (i *Installer) SetupDaemonService(ctx context.Context, binaryPath string) I
 		return i.setupLaunchdService(binaryPath)
 	case "linux":
 		return i.setupSystemdService(binaryPath)
+	case "windows":
+		return i.setupWindowsService(binaryPath)
 	default:
 		return InstallResult{
 			Dependency: "Daemon Service",
@@ -1318,6 +1448,121 @@ WantedBy=default.target
 	}
 }
 
+// windowsServiceName is the name registered with the service control manager.
+const windowsServiceName = "conduit"
+
+func (i *Installer) setupWindowsService(binaryPath string) InstallResult {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return InstallResult{Dependency: "Daemon Service", Error: err}
+	}
+
+	// The service runs as LocalSystem, so pin the data directory to the
+	// installing user's profile rather than the system profile.
+	conduitHome := filepath.Join(homeDir, ".conduit")
+	binPath := fmt.Sprintf(`"%s" --foreground --data-dir "%s"`, binaryPath, conduitHome)
+
+	// Create the service, or update it if it already exists
+	args := []string{"binPath=", binPath, "start=", "auto", "DisplayName=", "Conduit AI Intelligence Hub Daemon"}
+	verb := "create"
+	if _, err := i.WindowsServiceState(); err == nil {
+		verb = "config"
+	}
+	if out, err := exec.Command("sc.exe", append([]string{verb, windowsServiceName}, args...)...).CombinedOutput(); err != nil {
+		return InstallResult{
+			Dependency: "Daemon Service",
+			Error:      fmt.Errorf("sc.exe %s failed (run as Administrator?): %s", verb, strings.TrimSpace(string(out))),
+		}
+	}
+
+	_ = exec.Command("sc.exe", "description", windowsServiceName, "Conduit AI Intelligence Hub Daemon").Run()
+
+	// Restart after 10 seconds on failure, matching the systemd unit
+	_ = exec.Command("sc.exe", "failure", windowsServiceName, "reset=", "86400", "actions=", "restart/10000/restart/10000/restart/10000").Run()
+
+	if out, err := exec.Command("sc.exe", "start", windowsServiceName).CombinedOutput(); err != nil {
+		// Non-fatal - service might already be running
+		fmt.Printf("  Note: Could not start service: %s\n", strings.TrimSpace(string(out)))
+	}
+
+	fmt.Println("✓ Daemon service installed (Windows service)")
+	fmt.Println("  The daemon will start automatically at boot.")
+
+	return InstallResult{
+		Dependency: "Daemon Service",
+		Installed:  true,
+		Message:    "Windows service installed",
+	}
+}
+
+// WindowsServiceState returns the state of the Windows daemon service as
+// reported by sc.exe (e.g. "RUNNING", "STOPPED"). It returns an error if the
+// service is not installed.
+func (i *Installer) WindowsServiceState() (string, error) {
+	out, err := exec.Command("sc.exe", "query", windowsServiceName).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("service not installed: %s", strings.TrimSpace(string(out)))
+	}
+	state := parseScState(string(out))
+	if state == "" {
+		return "", fmt.Errorf("could not parse service state")
+	}
+	return state, nil
+}
+
+// parseScState extracts the state name from `sc.exe query` output, whose
+// relevant line looks like "        STATE              : 4  RUNNING".
+func parseScState(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) != "STATE" {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) >= 2 {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// IsDaemonServiceInstalled reports whether the daemon is installed as a
+// launchd, systemd or Windows service.
+func (i *Installer) IsDaemonServiceInstalled() bool {
+	homeDir, _ := os.UserHomeDir()
+
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := os.Stat(filepath.Join(homeDir, "Library", "LaunchAgents", "dev.simpleflo.conduit.plist"))
+		return err == nil
+	case "linux":
+		_, err := os.Stat(filepath.Join(homeDir, ".config", "systemd", "user", "conduit.service"))
+		return err == nil
+	case "windows":
+		_, err := i.WindowsServiceState()
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// StartDaemonService starts the installed daemon service.
+func (i *Installer) StartDaemonService() error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("launchctl", "start", "dev.simpleflo.conduit").Run()
+	case "linux":
+		return exec.Command("systemctl", "--user", "start", "conduit").Run()
+	case "windows":
+		if out, err := exec.Command("sc.exe", "start", windowsServiceName).CombinedOutput(); err != nil {
+			return fmt.Errorf("start service: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+	}
+}
+
 // StopDaemonService stops the daemon service.
 func (i *Installer) StopDaemonService() error {
 	switch runtime.GOOS {
@@ -1325,11 +1570,64 @@ func (i *Installer) StopDaemonService() error {
 		return exec.Command("launchctl", "stop", "dev.simpleflo.conduit").Run()
 	case "linux":
 		return exec.Command("systemctl", "--user", "stop", "conduit").Run()
+	case "windows":
+		if out, err := exec.Command("sc.exe", "stop", windowsServiceName).CombinedOutput(); err != nil {
+			return fmt.Errorf("stop service: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
 	default:
 		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
 	}
 }
 
+// RestartDaemonService stops and starts the daemon service.
+func (i *Installer) RestartDaemonService() error {
+	switch runtime.GOOS {
+	case "darwin":
+		if out, err := exec.Command("launchctl", "stop", "dev.simpleflo.conduit").CombinedOutput(); err != nil {
+			return fmt.Errorf("stop service: %s", strings.TrimSpace(string(out)))
+		}
+
+		// launchctl stop returns before the daemon has exited
+		for j := 0; j < 30 && i.IsDaemonRunning(); j++ {
+			time.Sleep(time.Second)
+		}
+
+		// launchd's KeepAlive may already have relaunched it after the stop,
+		// in which case start is a no-op
+		if out, err := exec.Command("launchctl", "start", "dev.simpleflo.conduit").CombinedOutput(); err != nil {
+			return fmt.Errorf("start service: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
+	case "linux":
+		return exec.Command("systemctl", "--user", "restart", "conduit").Run()
+	case "windows":
+		_ = exec.Command("sc.exe", "stop", windowsServiceName).Run()
+
+		// sc.exe stop returns before the service has stopped
+		for j := 0; j < 30; j++ {
+			if state, err := i.WindowsServiceState(); err != nil || state == "STOPPED" {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+
+		if out, err := exec.Command("sc.exe", "start", windowsServiceName).CombinedOutput(); err != nil {
+			return fmt.Errorf("start service: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
+	}
+}
+
+// DaemonLogPath returns the log file written by the launchd and Windows
+// services. The systemd service logs to the journal instead.
+func (i *Installer) DaemonLogPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".conduit", "daemon.log")
+}
+
 // RemoveDaemonService removes the daemon service.
 func (i *Installer) RemoveDaemonService() error {
 	homeDir, _ := os.UserHomeDir()
@@ -1344,23 +1642,40 @@ func (i *Installer) RemoveDaemonService() error {
 		_ = exec.Command("systemctl", "--user", "stop", "conduit").Run()
 		_ = exec.Command("systemctl", "--user", "disable", "conduit").Run()
 		return os.Remove(servicePath)
+	case "windows":
+		_ = exec.Command("sc.exe", "stop", windowsServiceName).Run()
+		if out, err := exec.Command("sc.exe", "delete", windowsServiceName).CombinedOutput(); err != nil {
+			return fmt.Errorf("delete service: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
 	default:
 		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
 	}
 }
 
-// IsDaemonRunning checks if the daemon is running.
+// IsDaemonRunning checks if the daemon is running. A socket that accepts
+// connections is not enough: the daemon must answer its ready endpoint, so
+// a stale socket file from a crashed daemon is not mistaken for a live one.
+// The ready endpoint is used rather than health, whose store and service
+// probes can outlast the short timeout on a busy daemon.
 func (i *Installer) IsDaemonRunning() bool {
-	homeDir, _ := os.UserHomeDir()
-	socketPath := filepath.Join(homeDir, ".conduit", "conduit.sock")
+	socketPath := i.SocketPath()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+			},
+		},
+		Timeout: 2 * time.Second,
+	}
 
-	// Try to connect to the socket
-	conn, err := net.DialTimeout("unix", socketPath, 2*time.Second)
+	resp, err := client.Get("http://localhost/api/v1/ready")
 	if err != nil {
 		return false
 	}
-	conn.Close()
-	return true
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
 }
 
 // StartDaemon starts the daemon in the background.
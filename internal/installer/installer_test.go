@@ -2,6 +2,9 @@ package installer
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"path/filepath"
 	"runtime"
 	"testing"
 )
@@ -21,6 +24,21 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestInstaller_AssumeYes(t *testing.T) {
+	inst := New(false)
+	inst.SetAssumeYes(true)
+
+	if !inst.confirmAction("Proceed?") {
+		t.Error("expected confirmAction to auto-confirm with assumeYes")
+	}
+	if got := inst.choose("Choice [1/2]: ", "1"); got != "1" {
+		t.Errorf("expected recommended choice 1, got %q", got)
+	}
+	if err := inst.checkInteractive(); err != nil {
+		t.Errorf("expected no interactivity error with assumeYes, got %v", err)
+	}
+}
+
 func TestInstaller_commandExists(t *testing.T) {
 	inst := New(false)
 
@@ -74,7 +92,7 @@ func TestInstaller_detectLinuxDistro(t *testing.T) {
 	}
 
 	// Known valid distros
-	validDistros := []string{"ubuntu", "debian", "fedora", "rhel", "centos", "arch", "unknown"}
+	validDistros := []string{"ubuntu", "debian", "fedora", "rhel", "centos", "arch", "alpine", "opensuse", "sles", "unknown"}
 	found := false
 	for _, v := range validDistros {
 		if distro == v {
@@ -87,6 +105,81 @@ func TestInstaller_detectLinuxDistro(t *testing.T) {
 	}
 }
 
+func TestParseOSRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name: "ubuntu",
+			content: `NAME="Ubuntu"
+VERSION_ID="22.04"
+ID=ubuntu
+ID_LIKE=debian`,
+			want: "ubuntu",
+		},
+		{
+			name: "alpine",
+			content: `NAME="Alpine Linux"
+ID=alpine
+VERSION_ID=3.19.1
+PRETTY_NAME="Alpine Linux v3.19"`,
+			want: "alpine",
+		},
+		{
+			name: "opensuse tumbleweed",
+			content: `NAME="openSUSE Tumbleweed"
+ID="opensuse-tumbleweed"
+ID_LIKE="opensuse suse"`,
+			want: "opensuse",
+		},
+		{
+			name: "opensuse leap",
+			content: `NAME="openSUSE Leap"
+VERSION="15.5"
+ID="opensuse-leap"
+ID_LIKE="suse opensuse"`,
+			want: "opensuse",
+		},
+		{
+			name: "sles",
+			content: `NAME="SLES"
+VERSION="15-SP5"
+ID="sles"
+ID_LIKE="suse"`,
+			want: "sles",
+		},
+		{
+			name: "rocky via ID_LIKE",
+			content: `NAME="Rocky Linux"
+ID="rocky"
+ID_LIKE="rhel centos fedora"`,
+			want: "rhel",
+		},
+		{
+			name: "mint via ID_LIKE",
+			content: `NAME="Linux Mint"
+ID=linuxmint
+ID_LIKE="ubuntu debian"`,
+			want: "ubuntu",
+		},
+		{
+			name:    "unknown",
+			content: `ID=nixos`,
+			want:    "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseOSRelease(tt.content); got != tt.want {
+				t.Errorf("parseOSRelease() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestInstallResult_Fields(t *testing.T) {
 	result := InstallResult{
 		Dependency:    "Docker",
@@ -173,6 +266,45 @@ func TestInstaller_IsDaemonRunning(t *testing.T) {
 	_ = inst.IsDaemonRunning()
 }
 
+func TestInstaller_IsDaemonRunning_Socket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets only")
+	}
+	socketPath := filepath.Join(t.TempDir(), "d.sock")
+	inst := New(false)
+	inst.SetSocketPath(socketPath)
+
+	if inst.SocketPath() != socketPath {
+		t.Errorf("SocketPath() = %q, want %q", inst.SocketPath(), socketPath)
+	}
+	if inst.IsDaemonRunning() {
+		t.Error("expected daemon not running without a socket")
+	}
+
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := http.StatusOK
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/ready" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	if !inst.IsDaemonRunning() {
+		t.Error("expected daemon running when the socket answers ready")
+	}
+	status = http.StatusServiceUnavailable
+	if inst.IsDaemonRunning() {
+		t.Error("expected daemon not running when ready fails")
+	}
+}
+
 func TestInstaller_StopDaemonService_Unsupported(t *testing.T) {
 	if runtime.GOOS != "windows" {
 		t.Skip("Only testing unsupported OS behavior")
@@ -209,3 +341,29 @@ func TestInstaller_SetupDaemonService_Fields(t *testing.T) {
 		t.Errorf("expected Dependency to be 'Daemon Service', got %s", result.Dependency)
 	}
 }
+
+func TestParseScState(t *testing.T) {
+	output := `
+SERVICE_NAME: conduit
+        TYPE               : 10  WIN32_OWN_PROCESS
+        STATE              : 4  RUNNING
+                                (STOPPABLE, NOT_PAUSABLE, ACCEPTS_SHUTDOWN)
+        WIN32_EXIT_CODE    : 0  (0x0)
+`
+	if got := parseScState(output); got != "RUNNING" {
+		t.Errorf("expected RUNNING, got %q", got)
+	}
+
+	if got := parseScState("[SC] EnumQueryServicesStatus:OpenService FAILED 1060"); got != "" {
+		t.Errorf("expected empty state for missing service, got %q", got)
+	}
+}
+
+func TestInstaller_DaemonLogPath(t *testing.T) {
+	inst := New(false)
+
+	path := inst.DaemonLogPath()
+	if filepath.Base(path) != "daemon.log" || filepath.Base(filepath.Dir(path)) != ".conduit" {
+		t.Errorf("expected ~/.conduit/daemon.log, got %s", path)
+	}
+}
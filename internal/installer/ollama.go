@@ -0,0 +1,340 @@
+package installer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/simpleflo/conduit/internal/diskspace"
+)
+
+// DefaultOllamaHost is the default Ollama API endpoint.
+const DefaultOllamaHost = "http://localhost:11434"
+
+// ollamaPullStallTimeout is how long a pull may go without any progress
+// message before it is considered stalled. Digest verification of large
+// models can be silent for a while, so this is generous.
+const ollamaPullStallTimeout = 5 * time.Minute
+
+// PullProgress is one progress update from an Ollama model pull.
+type PullProgress struct {
+	Model     string  `json:"model"`
+	Status    string  `json:"status"`
+	Digest    string  `json:"digest,omitempty"`
+	Total     int64   `json:"total,omitempty"`
+	Completed int64   `json:"completed,omitempty"`
+	Percent   float64 `json:"percent"`
+}
+
+// PullOptions controls the disk space check of a model pull.
+type PullOptions struct {
+	// ModelsDir is where Ollama stores models. Empty means OllamaModelsDir().
+	ModelsDir string
+
+	// MinFree is the free space in bytes a pull must leave once its layers
+	// are downloaded. 0 disables the check.
+	MinFree int64
+}
+
+// PullOllamaModel pulls a model through the Ollama HTTP API, reporting
+// structured progress to onProgress (which may be nil). Ollama verifies each
+// layer's sha256 digest during the pull; a mismatch is returned as an error.
+// The pull fails if no progress arrives for ollamaPullStallTimeout.
+func PullOllamaModel(ctx context.Context, host, model string, onProgress func(PullProgress)) error {
+	return PullOllamaModelWithOptions(ctx, host, model, PullOptions{MinFree: diskspace.DefaultMinFree}, onProgress)
+}
+
+// PullOllamaModelWithOptions is PullOllamaModel with a configurable disk
+// space check. When Ollama runs on this machine, the pull is refused before
+// it starts if less than opts.MinFree is free, and cancelled as soon as a
+// layer's size is known not to fit.
+func PullOllamaModelWithOptions(ctx context.Context, host, model string, opts PullOptions, onProgress func(PullProgress)) error {
+	return pullOllamaModel(ctx, host, model, opts, ollamaPullStallTimeout, onProgress)
+}
+
+// OllamaModelsDir returns where the local Ollama server most likely stores
+// models: $OLLAMA_MODELS, ~/.ollama/models, or the Linux service's
+// /usr/share/ollama/.ollama/models.
+func OllamaModelsDir() string {
+	if dir := os.Getenv("OLLAMA_MODELS"); dir != "" {
+		return dir
+	}
+	homeDir, _ := os.UserHomeDir()
+	userDir := filepath.Join(homeDir, ".ollama", "models")
+	if _, err := os.Stat(userDir); err == nil {
+		return userDir
+	}
+	if runtime.GOOS == "linux" {
+		serviceDir := "/usr/share/ollama/.ollama/models"
+		if _, err := os.Stat(serviceDir); err == nil {
+			return serviceDir
+		}
+	}
+	return userDir
+}
+
+// isLocalHost reports whether an Ollama API URL points at this machine,
+// where its models take up local disk space.
+func isLocalHost(host string) bool {
+	u, err := url.Parse(host)
+	if err != nil {
+		return false
+	}
+	name := u.Hostname()
+	if name == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(name)
+	return ip != nil && ip.IsLoopback()
+}
+
+func pullOllamaModel(ctx context.Context, host, model string, opts PullOptions, stallTimeout time.Duration, onProgress func(PullProgress)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Layer sizes are only known once the pull starts, so refuse up front
+	// only when even the reserve is gone, then check each layer as it appears
+	checkDisk := opts.MinFree > 0 && isLocalHost(host)
+	modelsDir := opts.ModelsDir
+	if modelsDir == "" {
+		modelsDir = OllamaModelsDir()
+	}
+	operation := "pull " + model
+	if checkDisk {
+		if err := diskspace.Check(modelsDir, 0, opts.MinFree, operation); err != nil {
+			return err
+		}
+	}
+	checkedLayers := make(map[string]bool)
+
+	body, _ := json.Marshal(map[string]interface{}{"model": model, "stream": true})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host+"/api/pull", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create pull request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// No client timeout: pulls take minutes; stalls are detected below
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pull %s: %w", model, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("pull %s: ollama returned %d: %s", model, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	type pullLine struct {
+		Status    string `json:"status"`
+		Digest    string `json:"digest"`
+		Total     int64  `json:"total"`
+		Completed int64  `json:"completed"`
+		Error     string `json:"error"`
+	}
+
+	lines := make(chan pullLine)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var line pullLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				continue
+			}
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	timer := time.NewTimer(stallTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-timer.C:
+			return fmt.Errorf("pull %s stalled: no progress for %s", model, stallTimeout)
+
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-scanErr; err != nil {
+					return fmt.Errorf("pull %s: %w", model, err)
+				}
+				return fmt.Errorf("pull %s: stream ended before completion", model)
+			}
+
+			if line.Error != "" {
+				return fmt.Errorf("pull %s: %s", model, line.Error)
+			}
+
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(stallTimeout)
+
+			if checkDisk && line.Digest != "" && line.Total > 0 && !checkedLayers[line.Digest] {
+				checkedLayers[line.Digest] = true
+				if err := diskspace.Check(modelsDir, line.Total-line.Completed, opts.MinFree, operation); err != nil {
+					return err
+				}
+			}
+
+			if onProgress != nil {
+				progress := PullProgress{
+					Model:     model,
+					Status:    line.Status,
+					Digest:    line.Digest,
+					Total:     line.Total,
+					Completed: line.Completed,
+				}
+				if line.Total > 0 {
+					progress.Percent = float64(line.Completed) / float64(line.Total) * 100
+				}
+				if line.Status == "success" {
+					progress.Percent = 100
+				}
+				onProgress(progress)
+			}
+
+			if line.Status == "success" {
+				return nil
+			}
+		}
+	}
+}
+
+// VerifyOllamaModel checks that a pulled model actually loads and runs by
+// issuing a one-token generation, or a tiny embedding for embedding-only
+// models. A failure usually means the model files are corrupt.
+func VerifyOllamaModel(ctx context.Context, host, model string) error {
+	client := &http.Client{Timeout: 2 * time.Minute}
+
+	embedOnly := false
+	var show struct {
+		Capabilities []string `json:"capabilities"`
+	}
+	if err := postOllamaJSON(ctx, client, host+"/api/show", map[string]interface{}{"model": model}, &show); err != nil {
+		return fmt.Errorf("model %s is not available: %w", model, err)
+	}
+	if len(show.Capabilities) > 0 {
+		embedOnly = true
+		for _, c := range show.Capabilities {
+			if c == "completion" {
+				embedOnly = false
+			}
+		}
+	}
+
+	if !embedOnly {
+		err := postOllamaJSON(ctx, client, host+"/api/generate", map[string]interface{}{
+			"model":   model,
+			"prompt":  "ok",
+			"stream":  false,
+			"options": map[string]interface{}{"num_predict": 1},
+		}, nil)
+		if err == nil {
+			return nil
+		}
+		// Older Ollama versions don't report capabilities
+		if !strings.Contains(err.Error(), "does not support generate") {
+			return fmt.Errorf("model %s failed a test generation (it may be corrupt; try re-pulling): %w", model, err)
+		}
+	}
+
+	var embed struct {
+		Embeddings [][]float64 `json:"embeddings"`
+	}
+	if err := postOllamaJSON(ctx, client, host+"/api/embed", map[string]interface{}{
+		"model": model,
+		"input": "ok",
+	}, &embed); err != nil {
+		return fmt.Errorf("model %s failed a test embedding (it may be corrupt; try re-pulling): %w", model, err)
+	}
+	if len(embed.Embeddings) == 0 || len(embed.Embeddings[0]) == 0 {
+		return fmt.Errorf("model %s returned an empty embedding (it may be corrupt; try re-pulling)", model)
+	}
+	return nil
+}
+
+// postOllamaJSON posts a JSON body and decodes the response into out (if not nil).
+func postOllamaJSON(ctx context.Context, client *http.Client, url string, body, out interface{}) error {
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respData, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respData, &errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("%s", errResp.Error)
+		}
+		return fmt.Errorf("ollama returned %d", resp.StatusCode)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(respData, out); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// PrintPullProgress renders pull progress as a single updating terminal line.
+func PrintPullProgress(p PullProgress) {
+	if p.Total <= 0 {
+		fmt.Printf("\r\033[K  %s", p.Status)
+		return
+	}
+
+	const width = 30
+	filled := int(p.Percent / 100 * width)
+	if filled > width {
+		filled = width
+	}
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+	fmt.Printf("\r\033[K  %s %5.1f%% (%s / %s)", bar, p.Percent,
+		formatPullBytes(p.Completed), formatPullBytes(p.Total))
+}
+
+// formatPullBytes formats a byte count for progress output.
+func formatPullBytes(n int64) string {
+	const (
+		mb = 1 << 20
+		gb = 1 << 30
+	)
+	if n >= gb {
+		return fmt.Sprintf("%.1f GB", float64(n)/gb)
+	}
+	return fmt.Sprintf("%.0f MB", float64(n)/mb)
+}
@@ -0,0 +1,133 @@
+package installer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/simpleflo/conduit/internal/diskspace"
+)
+
+func TestPullOllamaModel_Progress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/pull" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+		fmt.Fprintln(w, `{"status":"downloading","digest":"sha256:abc","total":200,"completed":50}`)
+		fmt.Fprintln(w, `{"status":"downloading","digest":"sha256:abc","total":200,"completed":200}`)
+		fmt.Fprintln(w, `{"status":"verifying sha256 digest"}`)
+		fmt.Fprintln(w, `{"status":"success"}`)
+	}))
+	defer srv.Close()
+
+	var updates []PullProgress
+	err := PullOllamaModel(context.Background(), srv.URL, "tiny", func(p PullProgress) {
+		updates = append(updates, p)
+	})
+	if err != nil {
+		t.Fatalf("PullOllamaModel failed: %v", err)
+	}
+
+	if len(updates) != 5 {
+		t.Fatalf("expected 5 progress updates, got %d", len(updates))
+	}
+	if updates[1].Percent != 25 {
+		t.Errorf("expected 25%%, got %.1f", updates[1].Percent)
+	}
+	if last := updates[len(updates)-1]; last.Status != "success" || last.Percent != 100 {
+		t.Errorf("expected final success at 100%%, got %+v", last)
+	}
+}
+
+func TestPullOllamaModel_DigestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"status":"verifying sha256 digest"}`)
+		fmt.Fprintln(w, `{"error":"digest mismatch"}`)
+	}))
+	defer srv.Close()
+
+	err := PullOllamaModel(context.Background(), srv.URL, "tiny", nil)
+	if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Errorf("expected digest mismatch error, got %v", err)
+	}
+}
+
+func TestPullOllamaModel_Stall(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+		w.(http.Flusher).Flush()
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	err := pullOllamaModel(context.Background(), srv.URL, "tiny", PullOptions{}, 100*time.Millisecond, nil)
+	if err == nil || !strings.Contains(err.Error(), "stalled") {
+		t.Errorf("expected stall error, got %v", err)
+	}
+}
+
+func TestPullOllamaModel_InsufficientDisk(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+		fmt.Fprintln(w, `{"status":"downloading","digest":"sha256:huge","total":4611686018427387904,"completed":0}`)
+		fmt.Fprintln(w, `{"status":"success"}`)
+	}))
+	defer srv.Close()
+
+	opts := PullOptions{ModelsDir: t.TempDir(), MinFree: diskspace.MB}
+	err := PullOllamaModelWithOptions(context.Background(), srv.URL, "huge", opts, nil)
+	var insufficient *diskspace.InsufficientError
+	if !errors.As(err, &insufficient) {
+		t.Fatalf("expected a disk space error, got %v", err)
+	}
+
+	opts.MinFree = 0
+	if err := PullOllamaModelWithOptions(context.Background(), srv.URL, "huge", opts, nil); err != nil {
+		t.Errorf("expected the check to be disabled, got %v", err)
+	}
+}
+
+func TestVerifyOllamaModel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		switch {
+		case r.URL.Path == "/api/show" && req["model"] == "embedder":
+			fmt.Fprint(w, `{"capabilities":["embedding"]}`)
+		case r.URL.Path == "/api/show":
+			fmt.Fprint(w, `{"capabilities":["completion"]}`)
+		case r.URL.Path == "/api/embed":
+			fmt.Fprint(w, `{"embeddings":[[0.1,0.2]]}`)
+		case r.URL.Path == "/api/generate" && req["model"] == "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error":"unable to load model"}`)
+		case r.URL.Path == "/api/generate":
+			fmt.Fprint(w, `{"response":"ok","done":true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if err := VerifyOllamaModel(ctx, srv.URL, "coder"); err != nil {
+		t.Errorf("expected generative model to verify, got %v", err)
+	}
+	if err := VerifyOllamaModel(ctx, srv.URL, "embedder"); err != nil {
+		t.Errorf("expected embedding model to verify, got %v", err)
+	}
+	if err := VerifyOllamaModel(ctx, srv.URL, "broken"); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("expected corrupt model error, got %v", err)
+	}
+}
@@ -117,6 +117,30 @@ func NewUninstallOptionsAll() UninstallOptions {
 	}
 }
 
+// Uninstall tiers, selecting one of the UninstallOptions presets.
+const (
+	UninstallTierKeepData = "keep-data" // NewUninstallOptionsKeepData
+	UninstallTierAll      = "all"       // NewUninstallOptionsAll
+)
+
+// UninstallTiers lists the tiers UninstallOptionsForTier accepts.
+var UninstallTiers = []string{UninstallTierKeepData, UninstallTierAll}
+
+// UninstallOptionsForTier returns the preset options for an uninstall tier.
+func UninstallOptionsForTier(tier string) (UninstallOptions, error) {
+	switch tier {
+	case UninstallTierKeepData:
+		return NewUninstallOptionsKeepData(), nil
+	case UninstallTierAll:
+		return NewUninstallOptionsAll(), nil
+	case "full":
+		// Asked for often enough to deserve its own answer
+		return UninstallOptions{}, fmt.Errorf("uninstall tier %q is not available: containers (Qdrant, FalkorDB) and Ollama may be shared with other projects, so Conduit never removes them; use --tier all and remove them manually", tier)
+	default:
+		return UninstallOptions{}, fmt.Errorf("unknown uninstall tier %q (use %s)", tier, strings.Join(UninstallTiers, ", "))
+	}
+}
+
 // GetUninstallInfo gathers information about what's installed for UI display.
 func (i *Installer) GetUninstallInfo(ctx context.Context) (*UninstallInfo, error) {
 	info := &UninstallInfo{}
@@ -124,7 +148,7 @@ func (i *Installer) GetUninstallInfo(ctx context.Context) (*UninstallInfo, error
 
 	// Check daemon service
 	info.HasDaemonService, info.ServicePath = i.checkDaemonServiceExists(homeDir)
-	info.DaemonRunning = i.checkDaemonRunning()
+	info.DaemonRunning = i.IsDaemonRunning()
 
 	// Check binaries
 	localBin := filepath.Join(homeDir, ".local", "bin")
@@ -356,21 +380,14 @@ func (i *Installer) checkDaemonServiceExists(homeDir string) (bool, string) {
 		if _, err := os.Stat(servicePath); err == nil {
 			return true, servicePath
 		}
+	case "windows":
+		if _, err := i.WindowsServiceState(); err == nil {
+			return true, "Windows service " + windowsServiceName
+		}
 	}
 	return false, ""
 }
 
-func (i *Installer) checkDaemonRunning() bool {
-	// Try health endpoint
-	client := &http.Client{Timeout: 2 * time.Second}
-	resp, err := client.Get("http://localhost:9090/health")
-	if err != nil {
-		return false
-	}
-	defer resp.Body.Close()
-	return resp.StatusCode == http.StatusOK
-}
-
 func (i *Installer) detectContainerRuntime() string {
 	if i.commandExists("podman") {
 		return "podman"
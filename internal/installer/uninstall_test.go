@@ -0,0 +1,31 @@
+package installer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUninstallOptionsForTier(t *testing.T) {
+	opts, err := UninstallOptionsForTier(UninstallTierKeepData)
+	if err != nil {
+		t.Fatalf("keep-data: %v", err)
+	}
+	if opts != NewUninstallOptionsKeepData() || opts.RemoveDataDir {
+		t.Errorf("keep-data options = %+v", opts)
+	}
+
+	opts, err = UninstallOptionsForTier(UninstallTierAll)
+	if err != nil {
+		t.Fatalf("all: %v", err)
+	}
+	if opts != NewUninstallOptionsAll() || !opts.RemoveDataDir {
+		t.Errorf("all options = %+v", opts)
+	}
+
+	if _, err := UninstallOptionsForTier("full"); err == nil || !strings.Contains(err.Error(), "never removes") {
+		t.Errorf("full: expected explanation, got %v", err)
+	}
+	if _, err := UninstallOptionsForTier("bogus"); err == nil || !strings.Contains(err.Error(), "keep-data, all") {
+		t.Errorf("bogus: expected list of tiers, got %v", err)
+	}
+}
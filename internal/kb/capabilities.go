@@ -68,14 +68,15 @@ func checkFTS5(ctx context.Context, db *sql.DB) bool {
 
 // checkQdrant tests Qdrant connectivity.
 func checkQdrant(ctx context.Context) (bool, string) {
-	client := &http.Client{Timeout: 2 * time.Second}
+	ctx, cancel := context.WithTimeout(ctx, qdrantProbeTimeout)
+	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", "http://localhost:6333/collections", nil)
 	if err != nil {
 		return false, "failed to create request"
 	}
 
-	resp, err := client.Do(req)
+	resp, err := qdrantHTTPClient.Do(req)
 	if err != nil {
 		return false, "not reachable"
 	}
@@ -3,8 +3,11 @@ package kb
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -16,12 +19,44 @@ type Chunker struct {
 	defaultOverlap int
 }
 
+// Chunk size bounds, in characters. Very small chunks lose context and very
+// large ones dilute embeddings and exceed embedding model input limits.
+const (
+	DefaultChunkSize    = 1000
+	DefaultChunkOverlap = 100
+	MinChunkSize        = 100
+	MaxChunkSize        = 8000
+)
+
 // NewChunker creates a new chunker with default settings.
 func NewChunker() *Chunker {
 	return &Chunker{
-		defaultMaxSize: 1000,
-		defaultOverlap: 100,
+		defaultMaxSize: DefaultChunkSize,
+		defaultOverlap: DefaultChunkOverlap,
+	}
+}
+
+// ValidateChunkOptions checks that a chunk size and overlap are usable.
+// The overlap may be at most half the chunk size so chunks always advance.
+func ValidateChunkOptions(size, overlap int) error {
+	if size < MinChunkSize || size > MaxChunkSize {
+		return fmt.Errorf("chunk_size must be between %d and %d, got %d", MinChunkSize, MaxChunkSize, size)
 	}
+	if overlap < 0 || overlap > size/2 {
+		return fmt.Errorf("chunk_overlap must be between 0 and %d (half of chunk_size), got %d", size/2, overlap)
+	}
+	return nil
+}
+
+// SetDefaults changes the chunk size and overlap used when ChunkOptions
+// leaves them unset. An overlap of 0 turns overlap off.
+func (c *Chunker) SetDefaults(size, overlap int) error {
+	if err := ValidateChunkOptions(size, overlap); err != nil {
+		return err
+	}
+	c.defaultMaxSize = size
+	c.defaultOverlap = overlap
+	return nil
 }
 
 // ContentType determines how content should be chunked.
@@ -41,7 +76,7 @@ func DetectContentType(path string) ContentType {
 	case ".go", ".py", ".js", ".ts", ".java", ".rs", ".rb", ".c", ".cpp", ".h", ".hpp",
 		".cs", ".swift", ".kt", ".scala", ".php", ".sh", ".bash", ".zsh":
 		return ContentTypeCode
-	case ".md", ".markdown", ".rst":
+	case ".md", ".markdown", ".mdx", ".rst":
 		return ContentTypeMarkdown
 	case ".pdf":
 		return ContentTypePDF
@@ -215,20 +250,26 @@ func (c *Chunker) EstimateChunkCount(contentLength int, opts ChunkOptions) int {
 	return (contentLength-opts.Overlap)/effectiveChunkSize + 1
 }
 
-// ChunkSmart performs content-aware chunking based on file type.
+// ChunkSmart performs content-aware chunking based on file type. Chunks of
+// source files and Markdown code blocks record their language in the
+// "language" metadata.
 func (c *Chunker) ChunkSmart(content string, path string, opts ChunkOptions) []Chunk {
 	contentType := DetectContentType(path)
 
+	var chunks []Chunk
 	switch contentType {
 	case ContentTypeCode:
-		return c.chunkCode(content, path, opts)
+		chunks = c.chunkCode(content, path, opts)
 	case ContentTypeMarkdown:
-		return c.chunkMarkdown(content, opts)
+		chunks = c.chunkMarkdown(content, opts)
 	case ContentTypePDF:
-		return c.chunkPDF(content, opts)
+		chunks = c.chunkPDF(content, opts)
 	default:
-		return c.chunkSentenceAware(content, opts)
+		chunks = c.chunkSentenceAware(content, opts)
 	}
+
+	tagChunkLanguages(chunks, path, contentType)
+	return chunks
 }
 
 // chunkSentenceAware chunks text respecting sentence boundaries.
@@ -380,22 +421,44 @@ func splitIntoSentences(text string) []string {
 	return sentences
 }
 
-// getOverlapFromEnd extracts overlap text from the end of content.
+// getOverlapFromEnd extracts overlap text from the end of content. The overlap
+// starts at a sentence boundary when one falls inside the overlap region, and
+// otherwise at the next word boundary, so it never begins mid-word.
 func getOverlapFromEnd(content string, overlapSize int) string {
+	if overlapSize <= 0 {
+		return ""
+	}
 	if len(content) <= overlapSize {
 		return content
 	}
 
 	// Try to find a sentence boundary in the overlap region
 	overlapStart := len(content) - overlapSize
+	for overlapStart > 0 && !utf8.RuneStart(content[overlapStart]) {
+		overlapStart--
+	}
 	overlapText := content[overlapStart:]
 
 	// Find the start of the last sentence in the overlap
-	sentenceStart := strings.LastIndex(overlapText, ". ")
-	if sentenceStart > 0 && sentenceStart < len(overlapText)-10 {
+	sentenceStart := -1
+	for _, terminator := range []string{". ", "! ", "? "} {
+		if i := strings.LastIndex(overlapText, terminator); i > sentenceStart && i < len(overlapText)-10 {
+			sentenceStart = i
+		}
+	}
+	if sentenceStart > 0 {
 		return strings.TrimSpace(overlapText[sentenceStart+2:])
 	}
 
+	// No sentence boundary: drop the partial word at the start
+	if overlapStart > 0 && !unicode.IsSpace(rune(content[overlapStart-1])) {
+		i := strings.IndexFunc(overlapText, unicode.IsSpace)
+		if i < 0 {
+			return ""
+		}
+		overlapText = overlapText[i:]
+	}
+
 	return strings.TrimSpace(overlapText)
 }
 
@@ -595,7 +658,29 @@ func splitOnBlankLines(content string) []codeBoundary {
 	return boundaries
 }
 
-// chunkMarkdown chunks markdown respecting header boundaries.
+// markdownHeadingPattern matches ATX headings ("## Title").
+var markdownHeadingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// markdownBlock is a run of Markdown that chunking keeps together: a heading,
+// a paragraph or list, a table, or a fenced code block.
+type markdownBlock struct {
+	start, end  int      // Byte offsets in the content
+	headingPath []string // Headings enclosing the block, outermost first
+	heading     bool     // The block is a heading line
+	atomic      bool     // Fenced code or table: never split
+	code        bool     // Fenced code block
+}
+
+// markdownSection is a heading and the blocks up to the next heading.
+type markdownSection struct {
+	headingPath []string
+	blocks      []markdownBlock
+}
+
+// chunkMarkdown chunks Markdown along its structure. Chunks never cross a
+// heading boundary, fenced code blocks and tables are never split, and each
+// chunk records its heading path in the "heading_path" metadata (e.g.
+// "Install > Docker") along with the nearest heading in "section".
 func (c *Chunker) chunkMarkdown(content string, opts ChunkOptions) []Chunk {
 	if opts.MaxSize <= 0 {
 		opts.MaxSize = c.defaultMaxSize
@@ -621,82 +706,195 @@ func (c *Chunker) chunkMarkdown(content string, opts ChunkOptions) []Chunk {
 		}}
 	}
 
-	// Split on markdown headers
-	headerPattern := regexp.MustCompile(`(?m)^(#{1,6})\s+(.+)$`)
-	matches := headerPattern.FindAllStringSubmatchIndex(content, -1)
+	sections := splitMarkdownSections(parseMarkdownBlocks(content))
 
-	if len(matches) == 0 {
-		return c.chunkSentenceAware(content, opts)
+	var chunks []Chunk
+	var carry []markdownBlock
+	for i, section := range sections {
+		blocks := append(carry, section.blocks...)
+		carry = nil
+
+		// A heading directly followed by another heading has no body of its
+		// own; keep it with the next section instead of emitting it alone.
+		if len(section.blocks) == 1 && section.blocks[0].heading && i+1 < len(sections) {
+			carry = blocks
+			continue
+		}
+
+		chunks = c.packMarkdownBlocks(content, blocks, section.headingPath, opts, chunks)
 	}
 
-	var chunks []Chunk
-	index := 0
-	prevEnd := 0
-
-	for i, match := range matches {
-		// Content before this header (or between headers)
-		if match[0] > prevEnd {
-			beforeContent := strings.TrimSpace(content[prevEnd:match[0]])
-			if len(beforeContent) > 0 {
-				subChunks := c.createChunksFromSection(beforeContent, prevEnd, opts, index)
-				for _, ch := range subChunks {
-					chunks = append(chunks, ch)
-					index++
-				}
+	return chunks
+}
+
+// packMarkdownBlocks groups consecutive blocks of one section into chunks of
+// at most opts.MaxSize and appends them to chunks. Oversized atomic blocks
+// become a chunk of their own; other oversized blocks are split by sentence.
+func (c *Chunker) packMarkdownBlocks(content string, blocks []markdownBlock, headingPath []string, opts ChunkOptions, chunks []Chunk) []Chunk {
+	var current []markdownBlock
+
+	emit := func(chunk Chunk, code bool) {
+		chunk.Index = len(chunks)
+		chunk.ChunkID = c.chunkID(chunk.Content, chunk.Index)
+		if len(headingPath) > 0 {
+			if chunk.Metadata == nil {
+				chunk.Metadata = make(map[string]string)
 			}
+			chunk.Metadata["section"] = headingPath[len(headingPath)-1]
+			chunk.Metadata["heading_path"] = strings.Join(headingPath, " > ")
 		}
-
-		// Find end of this section (next header or end of content)
-		sectionEnd := len(content)
-		if i+1 < len(matches) {
-			sectionEnd = matches[i+1][0]
+		if code {
+			if chunk.Metadata == nil {
+				chunk.Metadata = make(map[string]string)
+			}
+			chunk.Metadata["block_type"] = "code"
 		}
+		chunks = append(chunks, chunk)
+	}
 
-		sectionContent := strings.TrimSpace(content[match[0]:sectionEnd])
-		if len(sectionContent) > 0 {
-			// Extract header text for metadata
-			headerText := content[match[4]:match[5]]
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		start, end := current[0].start, current[len(current)-1].end
+		emit(Chunk{
+			Content:   content[start:end],
+			StartChar: start,
+			EndChar:   end,
+		}, len(current) == 1 && current[0].code)
+		current = nil
+	}
 
-			subChunks := c.createChunksFromSection(sectionContent, match[0], opts, index)
-			for j := range subChunks {
-				if subChunks[j].Metadata == nil {
-					subChunks[j].Metadata = make(map[string]string)
-				}
-				subChunks[j].Metadata["section"] = headerText
-				chunks = append(chunks, subChunks[j])
-				index++
+	for _, block := range blocks {
+		size := utf8.RuneCountInString(content[block.start:block.end])
+
+		if size > opts.MaxSize && !block.atomic {
+			flush()
+			for _, sub := range c.chunkSentenceAware(content[block.start:block.end], opts) {
+				sub.StartChar += block.start
+				sub.EndChar += block.start
+				emit(sub, false)
 			}
+			continue
 		}
 
-		prevEnd = sectionEnd
+		if len(current) > 0 && utf8.RuneCountInString(content[current[0].start:block.end]) > opts.MaxSize {
+			// Keep a trailing heading with the content it introduces
+			if last := current[len(current)-1]; last.heading && len(current) > 1 {
+				current = current[:len(current)-1]
+				flush()
+				current = []markdownBlock{last}
+			} else {
+				flush()
+			}
+		}
+		current = append(current, block)
 	}
+	flush()
 
 	return chunks
 }
 
-// createChunksFromSection creates chunks from a section of content.
-func (c *Chunker) createChunksFromSection(content string, offset int, opts ChunkOptions, startIndex int) []Chunk {
-	if utf8.RuneCountInString(content) <= opts.MaxSize {
-		return []Chunk{{
-			ChunkID:   c.chunkID(content, startIndex),
-			Index:     startIndex,
-			Content:   content,
-			StartChar: offset,
-			EndChar:   offset + len(content),
-		}}
+// parseMarkdownBlocks splits Markdown into blocks separated by blank lines,
+// keeping fenced code blocks whole and tracking the enclosing headings.
+func parseMarkdownBlocks(content string) []markdownBlock {
+	type heading struct {
+		level int
+		text  string
+	}
+	var stack []heading
+	path := func() []string {
+		p := make([]string, len(stack))
+		for i, h := range stack {
+			p[i] = h.text
+		}
+		return p
 	}
 
-	// Use sentence-aware chunking for large sections
-	baseChunks := c.chunkSentenceAware(content, opts)
+	var blocks []markdownBlock
+	var current *markdownBlock
+	table := false
+	flush := func() {
+		if current != nil {
+			current.atomic = current.atomic || table
+			blocks = append(blocks, *current)
+			current = nil
+		}
+	}
 
-	// Adjust offsets
-	for i := range baseChunks {
-		baseChunks[i].StartChar += offset
-		baseChunks[i].EndChar += offset
-		baseChunks[i].Index = startIndex + i
+	fence := ""
+	pos := 0
+	for pos < len(content) {
+		lineEnd := strings.IndexByte(content[pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(content)
+		} else {
+			lineEnd += pos
+		}
+		line := content[pos:lineEnd]
+		trimmed := strings.TrimSpace(line)
+		start := pos
+		pos = lineEnd + 1
+
+		// Inside a fence everything belongs to the code block
+		if fence != "" {
+			current.end = lineEnd
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+				flush()
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+			marker := trimmed[:1]
+			fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, marker))]
+			current = &markdownBlock{start: start, end: lineEnd, headingPath: path(), atomic: true, code: true}
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if m := markdownHeadingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			level := len(m[1])
+			for len(stack) > 0 && stack[len(stack)-1].level >= level {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, heading{level: level, text: m[2]})
+			blocks = append(blocks, markdownBlock{start: start, end: lineEnd, headingPath: path(), heading: true})
+			continue
+		}
+
+		isTableRow := strings.HasPrefix(trimmed, "|")
+		if current == nil {
+			current = &markdownBlock{start: start, headingPath: path()}
+			table = isTableRow
+		} else if !isTableRow {
+			table = false
+		}
+		current.end = lineEnd
 	}
+	flush() // Unterminated fence or trailing paragraph
+
+	return blocks
+}
 
-	return baseChunks
+// splitMarkdownSections groups blocks into sections starting at each heading.
+func splitMarkdownSections(blocks []markdownBlock) []markdownSection {
+	var sections []markdownSection
+	for _, block := range blocks {
+		if block.heading || len(sections) == 0 {
+			sections = append(sections, markdownSection{headingPath: block.headingPath})
+		}
+		last := &sections[len(sections)-1]
+		last.blocks = append(last.blocks, block)
+	}
+	return sections
 }
 
 // chunkPDF chunks PDF-extracted text with section and paragraph awareness.
@@ -866,3 +1064,109 @@ func splitPDFIntoParagraphs(content string) []string {
 
 	return result
 }
+
+// chunkLineProbe is how many words from each end of a chunk are matched
+// against the original text to find the lines it spans.
+const chunkLineProbe = 8
+
+// lineWord is a word of the original text and the 1-based line it is on.
+type lineWord struct {
+	word string
+	line int
+}
+
+// annotateChunkLines records the 1-based lines of original that each chunk
+// of content spans, as "start_line" and "end_line" metadata. Chunks are cut
+// from content, the cleaned form of original, so they are located by
+// matching the words at their start and end, which ignores the whitespace
+// and blank lines cleaning changes. Where the words repeat, the occurrence
+// nearest the chunk's offset wins. Chunks that cannot be located get no line
+// numbers. Chunks must be in document order.
+func annotateChunkLines(original, content string, chunks []Chunk) {
+	var words []lineWord
+	positions := make(map[string][]int)
+	for i, line := range strings.Split(original, "\n") {
+		for _, w := range strings.Fields(line) {
+			positions[w] = append(positions[w], len(words))
+			words = append(words, lineWord{word: w, line: i + 1})
+		}
+	}
+	offsets := wordOffsets(content)
+
+	cursor := 0
+	for i := range chunks {
+		chunkWords := strings.Fields(chunks[i].Content)
+		if len(chunkWords) == 0 {
+			continue
+		}
+		k := min(chunkLineProbe, len(chunkWords))
+
+		// Cleaning mostly leaves words alone, so the chunk's word position in
+		// content is close to its position in original
+		near := sort.SearchInts(offsets, chunks[i].StartChar)
+		start := findWordRun(words, positions, chunkWords[:k], cursor, near)
+		if start < 0 {
+			start = findWordRun(words, positions, chunkWords[:k], 0, near)
+		}
+		if start < 0 {
+			continue
+		}
+		cursor = start
+
+		startLine := words[start].line
+		endLine := startLine + strings.Count(strings.TrimSpace(chunks[i].Content), "\n")
+		if end := findWordRun(words, positions, chunkWords[len(chunkWords)-k:], start, start+len(chunkWords)-k); end >= 0 {
+			endLine = words[end+k-1].line
+		}
+
+		if chunks[i].Metadata == nil {
+			chunks[i].Metadata = make(map[string]string)
+		}
+		chunks[i].Metadata["start_line"] = strconv.Itoa(startLine)
+		chunks[i].Metadata["end_line"] = strconv.Itoa(endLine)
+	}
+}
+
+// wordOffsets returns the byte offset of each whitespace-separated word in s.
+func wordOffsets(s string) []int {
+	var offsets []int
+	inWord := false
+	for i, r := range s {
+		space := unicode.IsSpace(r)
+		if !space && !inWord {
+			offsets = append(offsets, i)
+		}
+		inWord = !space
+	}
+	return offsets
+}
+
+// findWordRun returns the index in words of the occurrence of run at or
+// after from that is nearest to near, or -1 if there is none.
+func findWordRun(words []lineWord, positions map[string][]int, run []string, from, near int) int {
+	best := -1
+	candidates := positions[run[0]]
+	for _, pos := range candidates[sort.SearchInts(candidates, from):] {
+		if pos+len(run) > len(words) || (best >= 0 && pos-near > absDiff(best, near)) {
+			break
+		}
+		match := true
+		for j, w := range run[1:] {
+			if words[pos+1+j].word != w {
+				match = false
+				break
+			}
+		}
+		if match && (best < 0 || absDiff(pos, near) < absDiff(best, near)) {
+			best = pos
+		}
+	}
+	return best
+}
+
+func absDiff(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -1,6 +1,9 @@
 package kb
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -131,3 +134,306 @@ func TestChunker_PreservesWords(t *testing.T) {
 		}
 	}
 }
+
+func TestChunker_OverlapStartsAtBoundary(t *testing.T) {
+	c := NewChunker()
+	if err := c.SetDefaults(120, 40); err != nil {
+		t.Fatalf("SetDefaults: %v", err)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < 30; i++ {
+		sb.WriteString("The quick brown fox jumps over the lazy dog near the riverbank. ")
+		sb.WriteString("Meanwhile everybody watches attentively without interrupting anything! ")
+	}
+	content := strings.TrimSpace(sb.String())
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(content) {
+		words[strings.Trim(w, ".!")] = true
+	}
+
+	chunks := c.ChunkSmart(content, "notes.txt", ChunkOptions{})
+	if len(chunks) < 3 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if len(chunk.Content) > 120+40+1 {
+			t.Errorf("chunk %d exceeds size plus overlap: %d chars", i, len(chunk.Content))
+		}
+		if i == 0 {
+			continue
+		}
+		// The overlap carried into each chunk must start on a whole word
+		first := strings.Trim(strings.Fields(chunk.Content)[0], ".!")
+		if !words[first] {
+			t.Errorf("chunk %d starts mid-word: %q", i, chunk.Content[:20])
+		}
+	}
+}
+
+func TestChunker_ZeroOverlap(t *testing.T) {
+	c := NewChunker()
+	if err := c.SetDefaults(120, 0); err != nil {
+		t.Fatalf("SetDefaults: %v", err)
+	}
+	if c.defaultOverlap != 0 {
+		t.Fatalf("an overlap of 0 should turn overlap off, got %d", c.defaultOverlap)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < 20; i++ {
+		sb.WriteString(fmt.Sprintf("Sentence number %d ends here. ", i))
+	}
+	content := strings.TrimSpace(sb.String())
+
+	for _, path := range []string{"notes.txt", "notes.md"} {
+		chunks := c.ChunkSmart(content, path, ChunkOptions{})
+		if len(chunks) < 2 {
+			t.Fatalf("%s: expected several chunks, got %d", path, len(chunks))
+		}
+		total := 0
+		for _, chunk := range chunks {
+			total += len(strings.Fields(chunk.Content))
+		}
+		if want := len(strings.Fields(content)); total != want {
+			t.Errorf("%s: chunks hold %d words, want %d with no overlap", path, total, want)
+		}
+	}
+}
+
+func TestGetOverlapFromEnd(t *testing.T) {
+	tests := []struct {
+		content string
+		size    int
+		want    string
+	}{
+		// Sentence boundary inside the overlap region wins
+		{"First sentence here. Second sentence is longer.", 30, "Second sentence is longer."},
+		// No sentence boundary: the partial leading word is dropped
+		{"alpha bravo charlie delta echo", 12, "delta echo"},
+		// Overlap landing exactly on a word start keeps that word
+		{"alpha bravo charlie", 7, "charlie"},
+		// Content shorter than the overlap is returned whole
+		{"short", 10, "short"},
+		// No overlap configured
+		{"alpha bravo", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := getOverlapFromEnd(tt.content, tt.size); got != tt.want {
+			t.Errorf("getOverlapFromEnd(%q, %d) = %q, want %q", tt.content, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestValidateChunkOptions(t *testing.T) {
+	tests := []struct {
+		size, overlap int
+		wantErr       bool
+	}{
+		{1000, 100, false},
+		{MinChunkSize, 0, false},
+		{MaxChunkSize, MaxChunkSize / 2, false},
+		{MinChunkSize - 1, 0, true},
+		{MaxChunkSize + 1, 100, true},
+		{500, 251, true},
+		{500, -1, true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateChunkOptions(tt.size, tt.overlap)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateChunkOptions(%d, %d) error = %v, wantErr %v", tt.size, tt.overlap, err, tt.wantErr)
+		}
+	}
+
+	c := NewChunker()
+	if err := c.SetDefaults(50, 10); err == nil {
+		t.Error("expected SetDefaults to reject a chunk size below the minimum")
+	}
+	if c.defaultMaxSize != DefaultChunkSize {
+		t.Errorf("invalid SetDefaults should keep the previous size, got %d", c.defaultMaxSize)
+	}
+}
+
+func TestChunker_MarkdownKeepsCodeFencesIntact(t *testing.T) {
+	c := NewChunker()
+
+	code := "```go\n# not a heading\nfunc main() {\n\n\tfmt.Println(\"hello\")\n}\n" +
+		strings.Repeat("// filler line to make the block long\n", 10) + "```"
+	content := "# Guide\n\nIntro paragraph that explains the guide.\n\n" +
+		"## Install\n\n" + strings.Repeat("Install steps are described here in detail. ", 3) + "\n\n" +
+		code + "\n\n" +
+		"### Docker\n\nRun the container with the default settings.\n\n" +
+		"| Flag | Meaning |\n|------|---------|\n| -d | detach |\n\n" +
+		"## Usage\n\nUse it."
+
+	chunks := c.ChunkSmart(content, "README.md", ChunkOptions{MaxSize: 200})
+	if len(chunks) < 3 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+
+	var codeChunk *Chunk
+	for i := range chunks {
+		ch := chunks[i]
+		if ch.Index != i {
+			t.Errorf("chunk %d has index %d", i, ch.Index)
+		}
+		if content[ch.StartChar:ch.EndChar] != ch.Content {
+			t.Errorf("chunk %d offsets do not match its content", i)
+		}
+		if n := strings.Count(ch.Content, "```"); n%2 != 0 {
+			t.Errorf("chunk %d splits a code fence:\n%s", i, ch.Content)
+		}
+		if strings.Contains(ch.Content, "func main()") {
+			codeChunk = &chunks[i]
+		}
+		if strings.Contains(ch.Content, "| Flag |") && !strings.Contains(ch.Content, "| -d |") {
+			t.Errorf("chunk %d splits a table", i)
+		}
+	}
+
+	if codeChunk == nil {
+		t.Fatal("code block missing from chunks")
+	}
+	if !strings.Contains(codeChunk.Content, code) {
+		t.Error("code block was not kept whole")
+	}
+	if got := codeChunk.Metadata["heading_path"]; got != "Guide > Install" {
+		t.Errorf("code chunk heading_path = %q, want %q (a # inside a fence is not a heading)", got, "Guide > Install")
+	}
+	if codeChunk.Metadata["block_type"] != "code" {
+		t.Errorf("expected block_type=code, got %q", codeChunk.Metadata["block_type"])
+	}
+}
+
+func TestChunker_MarkdownHeadingPaths(t *testing.T) {
+	c := NewChunker()
+
+	content := "# Guide\n\n## Install\n\n### Docker\n\n" + strings.Repeat("Docker instructions go here. ", 5) + "\n\n" +
+		"### Podman\n\n" + strings.Repeat("Podman instructions go here. ", 5) + "\n\n" +
+		"## Usage\n\n" + strings.Repeat("Usage notes go here. ", 5)
+
+	chunks := c.ChunkSmart(content, "guide.md", ChunkOptions{MaxSize: 200})
+
+	want := []struct {
+		path    string
+		section string
+		prefix  string
+	}{
+		// Heading-only sections are carried into the next chunk
+		{"Guide > Install > Docker", "Docker", "# Guide\n\n## Install\n\n### Docker"},
+		{"Guide > Install > Podman", "Podman", "### Podman"},
+		{"Guide > Usage", "Usage", "## Usage"},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %d", len(want), len(chunks))
+	}
+	for i, w := range want {
+		if got := chunks[i].Metadata["heading_path"]; got != w.path {
+			t.Errorf("chunk %d heading_path = %q, want %q", i, got, w.path)
+		}
+		if got := chunks[i].Metadata["section"]; got != w.section {
+			t.Errorf("chunk %d section = %q, want %q", i, got, w.section)
+		}
+		if !strings.HasPrefix(chunks[i].Content, w.prefix) {
+			t.Errorf("chunk %d should start with %q, got %q", i, w.prefix, chunks[i].Content)
+		}
+	}
+}
+
+func TestAnnotateChunkLines(t *testing.T) {
+	c := NewChunker()
+
+	// Cleaning trims indentation and collapses blank lines, so chunk text
+	// no longer lines up with the file
+	original := "# Guide\n\n\n\n  Intro paragraph   with  spacing.\n\n## Install\n\n" +
+		strings.Repeat("Install steps go here.\n", 10) + "\n## Usage\n\n" +
+		strings.Repeat("Usage notes go here.\n", 10)
+	cleaned := NewContentCleaner().Clean(original, ContentTypeMarkdown)
+
+	chunks := c.ChunkSmart(cleaned, "guide.md", ChunkOptions{MaxSize: 200})
+	annotateChunkLines(original, cleaned, chunks)
+
+	lines := strings.Split(original, "\n")
+	for i, chunk := range chunks {
+		start, err := strconv.Atoi(chunk.Metadata["start_line"])
+		if err != nil {
+			t.Fatalf("chunk %d: start_line %q: %v", i, chunk.Metadata["start_line"], err)
+		}
+		end, err := strconv.Atoi(chunk.Metadata["end_line"])
+		if err != nil {
+			t.Fatalf("chunk %d: end_line %q: %v", i, chunk.Metadata["end_line"], err)
+		}
+
+		first := strings.Fields(chunk.Content)[0]
+		if !strings.Contains(lines[start-1], first) {
+			t.Errorf("chunk %d: line %d %q does not hold its first word %q", i, start, lines[start-1], first)
+		}
+		words := strings.Fields(chunk.Content)
+		if last := words[len(words)-1]; !strings.Contains(lines[end-1], last) {
+			t.Errorf("chunk %d: line %d %q does not hold its last word %q", i, end, lines[end-1], last)
+		}
+	}
+
+	usage := false
+	for _, chunk := range chunks {
+		if strings.HasPrefix(chunk.Content, "## Usage") {
+			usage = true
+			if chunk.Metadata["start_line"] != "20" {
+				t.Errorf("Usage chunk should start on line 20, got %s", chunk.Metadata["start_line"])
+			}
+		}
+	}
+	if !usage {
+		t.Error("expected a chunk starting at the Usage heading")
+	}
+	if got := chunks[len(chunks)-1].Metadata["end_line"]; got != strconv.Itoa(len(lines)-1) {
+		t.Errorf("last chunk should end on line %d, got %s", len(lines)-1, got)
+	}
+}
+
+func TestAnnotateChunkLines_UnmatchedChunk(t *testing.T) {
+	chunks := []Chunk{{Content: "text that is not in the file"}}
+	annotateChunkLines("something else entirely\n", "text that is not in the file", chunks)
+
+	if _, ok := chunks[0].Metadata["start_line"]; ok {
+		t.Errorf("unmatched chunk should have no line numbers, got %v", chunks[0].Metadata)
+	}
+}
+
+func TestChunker_TagsLanguages(t *testing.T) {
+	c := NewChunker()
+
+	for _, chunk := range c.ChunkSmart("def retry(fn):\n    return fn()\n", "/src/util.py", ChunkOptions{}) {
+		if chunk.Metadata["language"] != "python" {
+			t.Errorf("expected python chunk, got %v", chunk.Metadata)
+		}
+	}
+
+	doc := "# Setup\n\nInstall the client.\n\n```ts title=\"client.ts\"\nconst c = new Client()\n```\n"
+	chunks := c.ChunkSmart(doc, "/docs/setup.md", ChunkOptions{})
+	if len(chunks) != 1 || chunks[0].Metadata["language"] != "typescript" {
+		t.Errorf("expected the fenced block's language, got %+v", chunks)
+	}
+
+	prose := c.ChunkSmart("# Notes\n\nNo code here.\n\n```\nplain fence\n```\n", "/docs/notes.md", ChunkOptions{})
+	if lang, ok := prose[0].Metadata["language"]; ok {
+		t.Errorf("expected no language without a fence info string, got %q", lang)
+	}
+}
+
+func TestParseLanguages(t *testing.T) {
+	got := ParseLanguages(" Py, typescript,,c++ ,golang")
+	want := []string{"python", "typescript", "cpp", "go"}
+	if len(got) != len(want) {
+		t.Fatalf("ParseLanguages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ParseLanguages = %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package kb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrDocumentNotFound is returned when no indexed document has the given ID
+// or path.
+var ErrDocumentNotFound = errors.New("document not found")
+
+// Where a DocumentContent's text came from.
+const (
+	ContentFromDisk  = "disk"  // Re-read from the file
+	ContentFromIndex = "index" // Reassembled from the indexed chunks
+)
+
+// DocumentContent is an indexed document with its full text.
+type DocumentContent struct {
+	Document
+	Content     string `json:"content"`
+	ContentFrom string `json:"content_from"` // ContentFromDisk or ContentFromIndex
+
+	// Changed reports that the file on disk no longer matches what was
+	// indexed; the content is the file's current text.
+	Changed bool `json:"changed,omitempty"`
+}
+
+// FindDocument looks a document up by ID, or by path when ref is not a
+// known document ID.
+func (sm *SourceManager) FindDocument(ctx context.Context, ref string) (*Document, error) {
+	if doc, err := sm.indexer.GetDocument(ctx, ref); err == nil {
+		return doc, nil
+	}
+
+	var documentID string
+	err := sm.db.QueryRowContext(ctx,
+		`SELECT document_id FROM kb_documents WHERE path = ?`, filepath.Clean(ref),
+	).Scan(&documentID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrDocumentNotFound, ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find document: %w", err)
+	}
+	return sm.indexer.GetDocument(ctx, documentID)
+}
+
+// GetDocumentContent returns a document, found by ID or path, with its full
+// text. Files still on disk are re-read so the text keeps its original
+// formatting; otherwise it is reassembled from the indexed chunks.
+func (sm *SourceManager) GetDocumentContent(ctx context.Context, ref string) (*DocumentContent, error) {
+	doc, err := sm.FindDocument(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	result := &DocumentContent{Document: *doc}
+
+	if _, err := os.Stat(doc.Path); err == nil {
+		content, _, err := sm.readFile(doc.Path)
+		if err == nil {
+			result.Content = content
+			result.ContentFrom = ContentFromDisk
+			result.Changed = sm.hashContent(sm.cleanContent(content, doc.Path)) != doc.Hash
+			return result, nil
+		}
+		sm.loggerFor(ctx).Debug().Err(err).Str("path", doc.Path).Msg("reading document failed, using indexed chunks")
+	}
+
+	chunks, err := sm.indexer.GetChunks(ctx, doc.DocumentID)
+	if err != nil {
+		return nil, fmt.Errorf("get chunks: %w", err)
+	}
+	result.Content = reassembleChunks(chunks)
+	result.ContentFrom = ContentFromIndex
+	return result, nil
+}
+
+// reassembleChunks rebuilds a document's indexed text from its chunks in
+// order, dropping the overlap repeated between neighbours.
+func reassembleChunks(chunks []Chunk) string {
+	if len(chunks) == 0 {
+		return ""
+	}
+
+	text := chunks[0].Content
+	prevEnd := chunks[0].EndChar
+	for _, chunk := range chunks[1:] {
+		text = stitchChunks(text, chunk.Content, prevEnd-chunk.StartChar)
+		prevEnd = max(prevEnd, chunk.EndChar)
+	}
+	return text
+}
@@ -24,27 +24,32 @@ const (
 	// DefaultOllamaHost is the default Ollama API endpoint.
 	DefaultOllamaHost = "http://localhost:11434"
 
-	// DefaultBatchSize is the default number of texts to embed in parallel.
-	DefaultBatchSize = 10
+	// DefaultBatchSize is the default number of texts sent per embedding request.
+	DefaultBatchSize = 32
+
+	// DefaultEmbeddingConcurrency is the default number of embedding requests in flight.
+	DefaultEmbeddingConcurrency = 2
 )
 
 // EmbeddingService generates vector embeddings via Ollama.
 type EmbeddingService struct {
-	client    *api.Client
-	model     string
-	dimension int
-	batchSize int
-	logger    zerolog.Logger
-	mu        sync.RWMutex
-	ready     bool
+	client      *api.Client
+	model       string
+	dimension   int
+	batchSize   int
+	concurrency int
+	logger      zerolog.Logger
+	mu          sync.RWMutex
+	ready       bool
 }
 
 // EmbeddingConfig configures the embedding service.
 type EmbeddingConfig struct {
-	OllamaHost string // Ollama API endpoint (default: http://localhost:11434)
-	Model      string // Embedding model name (default: nomic-embed-text)
-	Dimension  int    // Vector dimension (default: 768)
-	BatchSize  int    // Batch size for parallel embedding (default: 10)
+	OllamaHost  string // Ollama API endpoint (default: http://localhost:11434)
+	Model       string // Embedding model name (default: nomic-embed-text)
+	Dimension   int    // Vector dimension (default: 768)
+	BatchSize   int    // Texts per embedding request (default: 32)
+	Concurrency int    // Embedding requests in flight (default: 2)
 }
 
 // NewEmbeddingService creates a new embedding service.
@@ -61,6 +66,9 @@ func NewEmbeddingService(cfg EmbeddingConfig) (*EmbeddingService, error) {
 	if cfg.BatchSize <= 0 {
 		cfg.BatchSize = DefaultBatchSize
 	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultEmbeddingConcurrency
+	}
 
 	// Parse the Ollama host URL
 	ollamaURL, err := url.Parse(cfg.OllamaHost)
@@ -72,11 +80,12 @@ func NewEmbeddingService(cfg EmbeddingConfig) (*EmbeddingService, error) {
 	client := api.NewClient(ollamaURL, http.DefaultClient)
 
 	svc := &EmbeddingService{
-		client:    client,
-		model:     cfg.Model,
-		dimension: cfg.Dimension,
-		batchSize: cfg.BatchSize,
-		logger:    observability.Logger("kb.embeddings"),
+		client:      client,
+		model:       cfg.Model,
+		dimension:   cfg.Dimension,
+		batchSize:   cfg.BatchSize,
+		concurrency: cfg.Concurrency,
+		logger:      observability.Logger("kb.embeddings"),
 	}
 
 	return svc, nil
@@ -139,7 +148,9 @@ func (svc *EmbeddingService) Embed(ctx context.Context, text string) ([]float32,
 	return embeddings[0], nil
 }
 
-// EmbedBatch generates embeddings for multiple texts.
+// EmbedBatch generates embeddings for multiple texts. Texts are sent
+// batchSize at a time in a single request, with up to concurrency requests
+// in flight; embeddings are returned in input order.
 func (svc *EmbeddingService) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	if len(texts) == 0 {
 		return nil, nil
@@ -152,27 +163,36 @@ func (svc *EmbeddingService) EmbedBatch(ctx context.Context, texts []string) ([]
 
 	start := time.Now()
 	embeddings := make([][]float32, len(texts))
-	errors := make([]error, len(texts))
 
-	// Process in batches using goroutines
+	var batches [][2]int // [start, end) ranges into texts
+	for i := 0; i < len(texts); i += svc.batchSize {
+		end := i + svc.batchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+		batches = append(batches, [2]int{i, end})
+	}
+	errors := make([]error, len(batches))
+
+	// Bounded worker pool over batches
 	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, svc.batchSize)
+	semaphore := make(chan struct{}, svc.concurrency)
 
-	for i, text := range texts {
+	for b, r := range batches {
 		wg.Add(1)
 		semaphore <- struct{}{}
 
-		go func(idx int, txt string) {
+		go func(idx, from, to int) {
 			defer wg.Done()
 			defer func() { <-semaphore }()
 
-			embedding, err := svc.embedSingle(ctx, txt)
+			vectors, err := svc.embedTexts(ctx, texts[from:to])
 			if err != nil {
 				errors[idx] = err
 				return
 			}
-			embeddings[idx] = embedding
-		}(i, text)
+			copy(embeddings[from:to], vectors)
+		}(b, r[0], r[1])
 	}
 
 	wg.Wait()
@@ -182,25 +202,31 @@ func (svc *EmbeddingService) EmbedBatch(ctx context.Context, texts []string) ([]
 		if err != nil {
 			svc.logger.Warn().
 				Err(err).
-				Int("index", i).
-				Msg("embedding generation failed for text")
-			return nil, fmt.Errorf("embedding failed for text %d: %w", i, err)
+				Int("batch", i).
+				Int("batch_start", batches[i][0]).
+				Msg("embedding generation failed for batch")
+			return nil, fmt.Errorf("embedding failed for texts %d-%d: %w", batches[i][0], batches[i][1]-1, err)
 		}
 	}
 
+	elapsed := time.Since(start)
 	svc.logger.Debug().
 		Int("count", len(texts)).
-		Dur("duration", time.Since(start)).
+		Int("requests", len(batches)).
+		Int("batch_size", svc.batchSize).
+		Int("concurrency", svc.concurrency).
+		Dur("duration", elapsed).
+		Float64("texts_per_sec", perSecond(len(texts), elapsed)).
 		Msg("batch embedding completed")
 
 	return embeddings, nil
 }
 
-// embedSingle generates an embedding for a single text.
-func (svc *EmbeddingService) embedSingle(ctx context.Context, text string) ([]float32, error) {
+// embedTexts embeds several texts in one request.
+func (svc *EmbeddingService) embedTexts(ctx context.Context, texts []string) ([][]float32, error) {
 	req := &api.EmbedRequest{
 		Model: svc.model,
-		Input: text,
+		Input: texts,
 	}
 
 	resp, err := svc.client.Embed(ctx, req)
@@ -208,17 +234,39 @@ func (svc *EmbeddingService) embedSingle(ctx context.Context, text string) ([]fl
 		return nil, fmt.Errorf("embed request failed: %w", err)
 	}
 
-	if len(resp.Embeddings) == 0 {
-		return nil, fmt.Errorf("no embeddings in response")
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings in response, got %d", len(texts), len(resp.Embeddings))
 	}
 
 	// Convert float64 to float32
-	embedding := make([]float32, len(resp.Embeddings[0]))
-	for i, v := range resp.Embeddings[0] {
-		embedding[i] = float32(v)
+	embeddings := make([][]float32, len(resp.Embeddings))
+	for i, vector := range resp.Embeddings {
+		embedding := make([]float32, len(vector))
+		for j, v := range vector {
+			embedding[j] = float32(v)
+		}
+		embeddings[i] = embedding
 	}
 
-	return embedding, nil
+	return embeddings, nil
+}
+
+// BatchSize returns the number of texts sent per embedding request.
+func (svc *EmbeddingService) BatchSize() int {
+	return svc.batchSize
+}
+
+// Concurrency returns the number of embedding requests allowed in flight.
+func (svc *EmbeddingService) Concurrency() int {
+	return svc.concurrency
+}
+
+// perSecond returns count/elapsed as a rate, or 0 for an empty interval.
+func perSecond(count int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(count) / elapsed.Seconds()
 }
 
 // Dimension returns the embedding dimension.
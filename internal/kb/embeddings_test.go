@@ -0,0 +1,74 @@
+package kb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestEmbeddingService_EmbedBatchSendsBatches(t *testing.T) {
+	var mu sync.Mutex
+	var batchSizes []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/show":
+			w.Write([]byte(`{}`))
+		case "/api/embed":
+			var req struct {
+				Input []string `json:"input"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			batchSizes = append(batchSizes, len(req.Input))
+			mu.Unlock()
+
+			// Encode each text's number so ordering can be checked
+			embeddings := make([][]float32, len(req.Input))
+			for i, text := range req.Input {
+				n, _ := strconv.Atoi(text)
+				embeddings[i] = []float32{float32(n)}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"embeddings": embeddings})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	svc, err := NewEmbeddingService(EmbeddingConfig{
+		OllamaHost:  server.URL,
+		BatchSize:   2,
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	texts := []string{"0", "1", "2", "3", "4"}
+	embeddings, err := svc.EmbedBatch(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("EmbedBatch: %v", err)
+	}
+
+	if len(batchSizes) != 3 {
+		t.Errorf("expected 3 embedding requests, got %d (%v)", len(batchSizes), batchSizes)
+	}
+	for _, n := range batchSizes {
+		if n > 2 {
+			t.Errorf("request carried %d texts, batch size is 2", n)
+		}
+	}
+	for i, e := range embeddings {
+		if len(e) != 1 || int(e[0]) != i {
+			t.Errorf("embedding %d = %v, results out of order", i, e)
+		}
+	}
+}
@@ -20,6 +20,13 @@ import (
 	"github.com/simpleflo/conduit/internal/observability"
 )
 
+// ExtractableChunkFilter is a SQL condition on kb_chunks (aliased c) that keeps
+// only chunks whose source has entity extraction enabled.
+const ExtractableChunkFilter = `c.document_id IN (
+	SELECT xd.document_id FROM kb_documents xd
+	JOIN kb_sources xs ON xd.source_id = xs.source_id
+	WHERE xs.extract_entities = 1)`
+
 // normalizeEntityName normalizes an entity name for deduplication.
 // It lowercases, trims whitespace, normalizes unicode, and removes excess spaces.
 func normalizeEntityName(name string) string {
@@ -369,16 +376,7 @@ func (e *EntityExtractor) storeEntities(ctx context.Context, entities []Entity)
 			}
 
 			// Merge metadata
-			mergedMetadataJSON := existingMetadata
-			if entity.Metadata != nil {
-				if data, err := serializeMetadata(entity.Metadata); err == nil {
-					// For simplicity, just use the new metadata if it exists
-					// A more sophisticated approach would merge the JSON objects
-					if data != "{}" {
-						mergedMetadataJSON = data
-					}
-				}
-			}
+			mergedMetadataJSON := mergeEntityMetadata(existingMetadata, entity.Metadata)
 
 			// Update existing entity
 			_, err = tx.ExecContext(ctx, `
@@ -549,6 +547,65 @@ func GenerateRelationID(subjectID, predicate, objectID string) string {
 	return "rel_" + hex.EncodeToString(h.Sum(nil))[:16]
 }
 
+// mergeEntityMetadata merges new entity metadata into the stored JSON. New
+// values replace stored ones, except aliases, which are combined.
+func mergeEntityMetadata(existingJSON string, metadata map[string]interface{}) string {
+	if len(metadata) == 0 {
+		return existingJSON
+	}
+	merged := make(map[string]interface{})
+	if existingJSON != "" {
+		// Unreadable stored metadata is replaced
+		_ = json.Unmarshal([]byte(existingJSON), &merged)
+	}
+
+	aliases := entityAliases(merged)
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	seen := make(map[string]bool)
+	for _, alias := range entityAliases(merged) {
+		seen[strings.ToLower(alias)] = true
+	}
+	combined := entityAliases(merged)
+	for _, alias := range aliases {
+		if len(combined) >= MaxAliasesPerEntity {
+			break
+		}
+		if !seen[strings.ToLower(alias)] {
+			seen[strings.ToLower(alias)] = true
+			combined = append(combined, alias)
+		}
+	}
+	if len(combined) > 0 {
+		merged["aliases"] = combined
+	}
+
+	data, err := serializeMetadata(merged)
+	if err != nil {
+		return existingJSON
+	}
+	return data
+}
+
+// entityAliases returns the aliases in entity metadata, whether decoded from
+// JSON or set by the extraction validator.
+func entityAliases(metadata map[string]interface{}) []string {
+	switch aliases := metadata["aliases"].(type) {
+	case []string:
+		return append([]string(nil), aliases...)
+	case []interface{}:
+		result := make([]string, 0, len(aliases))
+		for _, alias := range aliases {
+			if s, ok := alias.(string); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
 // serializeMetadata converts metadata map to JSON string.
 func serializeMetadata(metadata map[string]interface{}) (string, error) {
 	if metadata == nil {
@@ -0,0 +1,139 @@
+package kb
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EvalQuery is one query of a ranking evaluation with the documents that
+// are relevant to it. Expected documents are given by document ID, by
+// absolute path, or by a path suffix such as "guides/deploy.md".
+type EvalQuery struct {
+	Query          string   `json:"query"`
+	ExpectedDocIDs []string `json:"expected_doc_ids"`
+}
+
+// EvalDocument is a ranked search result as far as evaluation is concerned.
+type EvalDocument struct {
+	DocumentID string `json:"document_id"`
+	Path       string `json:"path"`
+}
+
+// EvalQueryResult is how one configuration ranked the documents of a query.
+type EvalQueryResult struct {
+	Query          string   `json:"query"`
+	Retrieved      []string `json:"retrieved"`       // Document IDs of the top k, in rank order
+	Found          int      `json:"found"`           // Expected documents in the top k
+	Expected       int      `json:"expected"`        // Expected documents
+	FirstRank      int      `json:"first_rank"`      // Rank of the first expected document (0 = not in the top k)
+	Precision      float64  `json:"precision"`       // Found / k
+	Recall         float64  `json:"recall"`          // Found / Expected
+	ReciprocalRank float64  `json:"reciprocal_rank"` // 1 / FirstRank
+	Error          string   `json:"error,omitempty"` // Why the search failed; the query scores 0
+}
+
+// EvalMetrics are a configuration's metrics averaged over all queries.
+type EvalMetrics struct {
+	PrecisionAtK float64 `json:"precision_at_k"`
+	RecallAtK    float64 `json:"recall_at_k"`
+	MRR          float64 `json:"mrr"`
+}
+
+// ParseEvalQueries reads evaluation queries, one JSON object per line.
+// Blank lines and lines starting with # are skipped.
+func ParseEvalQueries(r io.Reader) ([]EvalQuery, error) {
+	var queries []EvalQuery
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), MaxQueryLength*4)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var q EvalQuery
+		if err := json.Unmarshal([]byte(text), &q); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		q.Query = strings.TrimSpace(q.Query)
+		if q.Query == "" {
+			return nil, fmt.Errorf("line %d: query is empty", line)
+		}
+		if len(q.ExpectedDocIDs) == 0 {
+			return nil, fmt.Errorf("line %d: expected_doc_ids is empty", line)
+		}
+		queries = append(queries, q)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("no queries found")
+	}
+	return queries, nil
+}
+
+// ScoreEvalQuery scores the top k documents a search returned for a query
+// against the query's expected documents. Repeated documents count once.
+func ScoreEvalQuery(q EvalQuery, docs []EvalDocument, k int) EvalQueryResult {
+	result := EvalQueryResult{Query: q.Query, Expected: len(q.ExpectedDocIDs)}
+	matched := make(map[int]bool)
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		if len(result.Retrieved) == k {
+			break
+		}
+		if seen[doc.DocumentID] {
+			continue
+		}
+		seen[doc.DocumentID] = true
+		result.Retrieved = append(result.Retrieved, doc.DocumentID)
+
+		for i, expected := range q.ExpectedDocIDs {
+			if !matched[i] && evalDocumentMatches(doc, expected) {
+				matched[i] = true
+				result.Found++
+				if result.FirstRank == 0 {
+					result.FirstRank = len(result.Retrieved)
+				}
+				break
+			}
+		}
+	}
+
+	if k > 0 {
+		result.Precision = float64(result.Found) / float64(k)
+	}
+	result.Recall = float64(result.Found) / float64(result.Expected)
+	if result.FirstRank > 0 {
+		result.ReciprocalRank = 1 / float64(result.FirstRank)
+	}
+	return result
+}
+
+// evalDocumentMatches reports whether a result is the expected document,
+// given by ID, absolute path or path suffix.
+func evalDocumentMatches(doc EvalDocument, expected string) bool {
+	return doc.DocumentID == expected || doc.Path == expected ||
+		(doc.Path != "" && strings.HasSuffix(doc.Path, "/"+strings.TrimPrefix(expected, "/")))
+}
+
+// MeanEvalMetrics averages the metrics of the query results.
+func MeanEvalMetrics(results []EvalQueryResult) EvalMetrics {
+	var m EvalMetrics
+	if len(results) == 0 {
+		return m
+	}
+	for _, r := range results {
+		m.PrecisionAtK += r.Precision
+		m.RecallAtK += r.Recall
+		m.MRR += r.ReciprocalRank
+	}
+	n := float64(len(results))
+	m.PrecisionAtK /= n
+	m.RecallAtK /= n
+	m.MRR /= n
+	return m
+}
@@ -0,0 +1,72 @@
+package kb
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseEvalQueries(t *testing.T) {
+	queries, err := ParseEvalQueries(strings.NewReader(`
+# deployment questions
+{"query": "deploy to staging", "expected_doc_ids": ["doc_1", "guides/deploy.md"]}
+
+{"query": "rollback", "expected_doc_ids": ["doc_2"]}
+`))
+	if err != nil {
+		t.Fatalf("ParseEvalQueries failed: %v", err)
+	}
+	if len(queries) != 2 || queries[0].Query != "deploy to staging" || len(queries[0].ExpectedDocIDs) != 2 {
+		t.Errorf("unexpected queries: %+v", queries)
+	}
+
+	bad := []struct {
+		name, input, wantErr string
+	}{
+		{"invalid JSON", `{"query": "x"`, "line 1"},
+		{"empty query", `{"query": " ", "expected_doc_ids": ["doc_1"]}`, "query is empty"},
+		{"no expected documents", "\n" + `{"query": "x"}`, "line 2: expected_doc_ids is empty"},
+		{"no queries", "# nothing\n", "no queries"},
+	}
+	for _, tt := range bad {
+		if _, err := ParseEvalQueries(strings.NewReader(tt.input)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestScoreEvalQuery(t *testing.T) {
+	q := EvalQuery{Query: "deploy", ExpectedDocIDs: []string{"doc_b", "guides/deploy.md", "doc_z"}}
+	docs := []EvalDocument{
+		{DocumentID: "doc_a", Path: "/kb/readme.md"},
+		{DocumentID: "doc_b", Path: "/kb/ops.md"},
+		{DocumentID: "doc_b", Path: "/kb/ops.md"},
+		{DocumentID: "doc_c", Path: "/kb/guides/deploy.md"},
+		{DocumentID: "doc_d", Path: "/kb/other.md"},
+	}
+
+	r := ScoreEvalQuery(q, docs, 3)
+	if len(r.Retrieved) != 3 || r.Retrieved[2] != "doc_c" {
+		t.Errorf("expected the top 3 distinct documents, got %v", r.Retrieved)
+	}
+	if r.Found != 2 || r.FirstRank != 2 {
+		t.Errorf("expected 2 found, first at rank 2, got %d at %d", r.Found, r.FirstRank)
+	}
+	if math.Abs(r.Precision-2.0/3) > 1e-9 || math.Abs(r.Recall-2.0/3) > 1e-9 || r.ReciprocalRank != 0.5 {
+		t.Errorf("unexpected metrics: precision %f, recall %f, RR %f", r.Precision, r.Recall, r.ReciprocalRank)
+	}
+
+	// Nothing relevant in the top k
+	r = ScoreEvalQuery(q, docs, 1)
+	if r.Found != 0 || r.FirstRank != 0 || r.Precision != 0 || r.ReciprocalRank != 0 {
+		t.Errorf("expected no matches in the top 1, got %+v", r)
+	}
+
+	m := MeanEvalMetrics([]EvalQueryResult{
+		{Precision: 0.5, Recall: 1, ReciprocalRank: 1},
+		{Precision: 0, Recall: 0, ReciprocalRank: 0},
+	})
+	if m.PrecisionAtK != 0.25 || m.RecallAtK != 0.5 || m.MRR != 0.5 {
+		t.Errorf("unexpected mean metrics: %+v", m)
+	}
+}
@@ -117,6 +117,11 @@ func (v *ExtractionValidator) ValidateAndConvertEntity(extracted ExtractedEntity
 	// Generate deterministic ID
 	entityID := GenerateEntityID(name, string(entityType), documentID)
 
+	metadata := make(map[string]interface{})
+	if aliases := v.normalizeAliases(name, extracted.Aliases); len(aliases) > 0 {
+		metadata["aliases"] = aliases
+	}
+
 	return &Entity{
 		ID:               entityID,
 		Name:             name,
@@ -125,8 +130,28 @@ func (v *ExtractionValidator) ValidateAndConvertEntity(extracted ExtractedEntity
 		Confidence:       extracted.Confidence,
 		SourceChunkID:    chunkID,
 		SourceDocumentID: documentID,
-		Metadata:         make(map[string]interface{}),
+		Metadata:         metadata,
+	}
+}
+
+// normalizeAliases normalizes an entity's aliases, dropping invalid and
+// suspicious ones and those that repeat the name or another alias.
+func (v *ExtractionValidator) normalizeAliases(name string, aliases []string) []string {
+	seen := map[string]bool{strings.ToLower(name): true}
+	var result []string
+	for _, alias := range aliases {
+		alias = v.normalizeName(alias)
+		key := strings.ToLower(alias)
+		if alias == "" || seen[key] || v.containsSuspiciousContent(alias) {
+			continue
+		}
+		seen[key] = true
+		result = append(result, alias)
+		if len(result) == MaxAliasesPerEntity {
+			break
+		}
 	}
+	return result
 }
 
 // ValidateAndConvertRelation validates an extracted relation and converts it to a graph relation.
@@ -71,6 +71,20 @@ func (r *ExtractorRegistry) Extract(path string) (string, error) {
 	return r.readAsText(path)
 }
 
+// ReadsAsText reports whether Extract returns the file's own text for path,
+// rather than text extracted from a document format, so positions in the
+// extracted text are positions in the file.
+func (r *ExtractorRegistry) ReadsAsText(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, extractor := range r.extractors {
+		if extractor.Supported(ext) {
+			_, isText := extractor.(*TextExtractor)
+			return isText
+		}
+	}
+	return true
+}
+
 // readAsText reads a file as plain text.
 func (r *ExtractorRegistry) readAsText(path string) (string, error) {
 	content, err := os.ReadFile(path)
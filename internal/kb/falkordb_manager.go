@@ -0,0 +1,539 @@
+// Package kb provides knowledge base functionality.
+package kb
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/simpleflo/conduit/internal/observability"
+)
+
+// falkorDBImage is the container image used for the managed FalkorDB instance.
+const falkorDBImage = "docker.io/falkordb/falkordb:latest"
+
+// FalkorDBManager handles FalkorDB container lifecycle and health management.
+// It mirrors QdrantManager: it ensures the storage directory exists, the
+// container is running and answering PING, and restarts it if it stops
+// responding.
+type FalkorDBManager struct {
+	dataDir       string
+	storageDir    string
+	containerName string
+	host          string
+	port          int
+	password      string
+	graphName     string
+	logger        zerolog.Logger
+	containerCmd  string // "docker" or "podman"
+}
+
+// FalkorDBManagerConfig configures the FalkorDB manager.
+type FalkorDBManagerConfig struct {
+	DataDir       string // Base data directory (default: ~/.conduit)
+	ContainerName string // Container name (default: conduit-falkordb)
+	Host          string // Host to connect to (default: localhost)
+	Port          int    // Redis port (default: 6379)
+	Password      string // Redis password (optional)
+	GraphName     string // Graph name (default: conduit_kg)
+}
+
+// FalkorDBHealth represents the health status of FalkorDB.
+type FalkorDBHealth struct {
+	ContainerRunning bool   `json:"container_running"`
+	PingOK           bool   `json:"ping_ok"`
+	GraphExists      bool   `json:"graph_exists"`
+	Error            string `json:"error,omitempty"`
+	NeedsRecovery    bool   `json:"needs_recovery"`
+}
+
+// NewFalkorDBManager creates a new FalkorDB manager.
+func NewFalkorDBManager(cfg FalkorDBManagerConfig) *FalkorDBManager {
+	if cfg.DataDir == "" {
+		homeDir, _ := os.UserHomeDir()
+		cfg.DataDir = filepath.Join(homeDir, ".conduit")
+	}
+	if cfg.ContainerName == "" {
+		cfg.ContainerName = "conduit-falkordb"
+	}
+	if cfg.Host == "" {
+		cfg.Host = "localhost"
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 6379
+	}
+	if cfg.GraphName == "" {
+		cfg.GraphName = "conduit_kg"
+	}
+
+	return &FalkorDBManager{
+		dataDir:       cfg.DataDir,
+		storageDir:    filepath.Join(cfg.DataDir, "falkordb"),
+		containerName: cfg.ContainerName,
+		host:          cfg.Host,
+		port:          cfg.Port,
+		password:      cfg.Password,
+		graphName:     cfg.GraphName,
+		logger:        observability.Logger("kb.falkordb"),
+	}
+}
+
+// EnsureReady ensures FalkorDB is ready for use:
+// 1. Ensures storage directory exists
+// 2. Detects container runtime (Docker/Podman)
+// 3. Ensures container is running
+// 4. Waits for FalkorDB to answer PING
+// 5. Restarts the container if it is running but unresponsive
+//
+// A remote host is never managed; only its reachability is checked.
+func (m *FalkorDBManager) EnsureReady(ctx context.Context) error {
+	m.logger.Info().Msg("ensuring FalkorDB is ready")
+
+	if !m.isManaged() {
+		if !m.isReachable(ctx) {
+			return fmt.Errorf("FalkorDB at %s not reachable", m.addr())
+		}
+		m.logger.Info().Str("addr", m.addr()).Msg("using external FalkorDB instance")
+		return nil
+	}
+
+	// Step 1: Ensure storage directory exists
+	if err := m.ensureStorageDir(); err != nil {
+		return fmt.Errorf("ensure storage directory: %w", err)
+	}
+
+	// Step 2: Detect container runtime
+	if err := m.detectContainerRuntime(); err != nil {
+		m.logger.Warn().Err(err).Msg("no container runtime found, knowledge graph disabled")
+		return nil // Not an error - KAG is optional
+	}
+
+	// Step 3: Ensure container is running
+	if err := m.ensureContainerRunning(ctx); err != nil {
+		return fmt.Errorf("ensure container running: %w", err)
+	}
+
+	// Step 4: Wait for FalkorDB to answer PING
+	if err := m.waitForPing(ctx, 30*time.Second); err != nil {
+		// Step 5: A running but unresponsive container gets one restart
+		health := m.CheckHealth(ctx)
+		if !health.NeedsRecovery {
+			return fmt.Errorf("wait for FalkorDB: %w", err)
+		}
+		m.logger.Warn().Str("error", health.Error).Msg("FalkorDB unresponsive, restarting container")
+		if err := m.restartContainer(ctx); err != nil {
+			return fmt.Errorf("recover FalkorDB: %w", err)
+		}
+	}
+
+	m.logger.Info().Msg("FalkorDB is ready")
+	return nil
+}
+
+// isManaged reports whether FalkorDB runs on this machine, where the
+// container can be managed.
+func (m *FalkorDBManager) isManaged() bool {
+	switch m.host {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// addr returns the host:port FalkorDB listens on.
+func (m *FalkorDBManager) addr() string {
+	return net.JoinHostPort(m.host, strconv.Itoa(m.port))
+}
+
+// ensureStorageDir creates the FalkorDB storage directory if it doesn't exist.
+func (m *FalkorDBManager) ensureStorageDir() error {
+	if err := os.MkdirAll(m.storageDir, 0755); err != nil {
+		return fmt.Errorf("create directory %s: %w", m.storageDir, err)
+	}
+	m.logger.Debug().Str("path", m.storageDir).Msg("storage directory ready")
+	return nil
+}
+
+// detectContainerRuntime finds an available container runtime.
+func (m *FalkorDBManager) detectContainerRuntime() error {
+	containerCmd, err := detectContainerCmd(m.logger)
+	if err != nil {
+		return err
+	}
+	m.containerCmd = containerCmd
+	return nil
+}
+
+// ensureContainerRunning ensures the FalkorDB container is running.
+func (m *FalkorDBManager) ensureContainerRunning(ctx context.Context) error {
+	// FalkorDB may already be running outside our managed container
+	if m.isReachable(ctx) {
+		m.logger.Info().Int("port", m.port).Msg("FalkorDB already reachable, using existing instance")
+		return nil
+	}
+
+	running, err := m.isContainerRunning(ctx)
+	if err != nil {
+		m.logger.Debug().Err(err).Msg("error checking container status")
+	}
+	if running {
+		m.logger.Debug().Str("container", m.containerName).Msg("container already running")
+		return nil
+	}
+
+	exists, err := m.containerExists(ctx)
+	if err != nil {
+		m.logger.Debug().Err(err).Msg("error checking if container exists")
+	}
+	if exists {
+		m.logger.Info().Str("container", m.containerName).Msg("starting existing container")
+		if err := m.runContainerCmd(ctx, "start", m.containerName); err != nil {
+			return fmt.Errorf("start container: %w", err)
+		}
+		return nil
+	}
+
+	if m.isPortInUse() {
+		m.logger.Warn().Int("port", m.port).Msg("port already in use but FalkorDB not reachable, cannot start FalkorDB")
+		return fmt.Errorf("port %d already in use by another process", m.port)
+	}
+
+	m.logger.Info().Str("container", m.containerName).Msg("creating new FalkorDB container")
+	return m.createContainer(ctx)
+}
+
+// newClient returns a Redis client for a single health probe.
+func (m *FalkorDBManager) newClient() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:         m.addr(),
+		Password:     m.password,
+		DialTimeout:  2 * time.Second,
+		ReadTimeout:  2 * time.Second,
+		WriteTimeout: 2 * time.Second,
+		MaxRetries:   -1,
+	})
+}
+
+// isReachable checks if FalkorDB answers PING without waiting.
+func (m *FalkorDBManager) isReachable(ctx context.Context) bool {
+	client := m.newClient()
+	defer client.Close()
+	return client.Ping(ctx).Err() == nil
+}
+
+// isPortInUse checks if the Redis port is already bound.
+func (m *FalkorDBManager) isPortInUse() bool {
+	conn, err := net.DialTimeout("tcp", m.addr(), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// createContainer creates a new FalkorDB container.
+func (m *FalkorDBManager) createContainer(ctx context.Context) error {
+	args, env := m.createContainerArgs()
+
+	m.logger.Debug().
+		Str("cmd", m.containerCmd).
+		Str("container", m.containerName).
+		Int("port", m.port).
+		Msg("creating container")
+
+	if err := runContainerCommandEnv(ctx, m.logger, m.containerCmd, env, args...); err != nil {
+		return fmt.Errorf("create container: %w", err)
+	}
+
+	return nil
+}
+
+// createContainerArgs returns the run arguments for a new FalkorDB container
+// and the environment to run them with. The password is passed by name
+// ("-e REDIS_ARGS") so it never appears in the process list.
+func (m *FalkorDBManager) createContainerArgs() (args, env []string) {
+	// Build volume mount - use :Z for SELinux on Linux only
+	volumeMount := fmt.Sprintf("%s:/data", m.storageDir)
+	if runtime.GOOS == "linux" {
+		volumeMount += ":Z"
+	}
+
+	args = []string{
+		"run", "-d",
+		"--name", m.containerName,
+		// Bind to loopback only: the KB stores have no auth by default, so they
+		// must never be reachable from the LAN (see KNOWN_ISSUES: SEC-001).
+		"-p", fmt.Sprintf("127.0.0.1:%d:%d", m.port, 6379),
+		"-v", volumeMount,
+	}
+	if m.password != "" {
+		args = append(args, "-e", "REDIS_ARGS")
+		env = append(env, "REDIS_ARGS=--requirepass "+m.password)
+	}
+	args = append(args, falkorDBImage)
+	return args, env
+}
+
+// isContainerRunning checks if the FalkorDB container is running.
+func (m *FalkorDBManager) isContainerRunning(ctx context.Context) (bool, error) {
+	if m.containerCmd == "" {
+		return false, nil
+	}
+	out, err := exec.CommandContext(ctx, m.containerCmd, "ps", "-q", "-f", fmt.Sprintf("name=%s", m.containerName)).Output()
+	if err != nil {
+		return false, err
+	}
+	return len(strings.TrimSpace(string(out))) > 0, nil
+}
+
+// containerExists checks if the FalkorDB container exists (running or stopped).
+func (m *FalkorDBManager) containerExists(ctx context.Context) (bool, error) {
+	if m.containerCmd == "" {
+		return false, nil
+	}
+	out, err := exec.CommandContext(ctx, m.containerCmd, "ps", "-a", "-q", "-f", fmt.Sprintf("name=%s", m.containerName)).Output()
+	if err != nil {
+		return false, err
+	}
+	return len(strings.TrimSpace(string(out))) > 0, nil
+}
+
+// waitForPing waits for FalkorDB to answer PING.
+func (m *FalkorDBManager) waitForPing(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if m.isReachable(ctx) {
+			m.logger.Debug().Msg("FalkorDB is reachable")
+			return nil
+		}
+
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	return fmt.Errorf("FalkorDB not reachable after %v", timeout)
+}
+
+// CheckHealth checks the health of FalkorDB and its graph.
+func (m *FalkorDBManager) CheckHealth(ctx context.Context) FalkorDBHealth {
+	health := FalkorDBHealth{}
+
+	client := m.newClient()
+	defer client.Close()
+
+	// First check if FalkorDB answers (works regardless of container name)
+	if err := client.Ping(ctx).Err(); err != nil {
+		running, _ := m.isContainerRunning(ctx)
+		health.ContainerRunning = running
+		if running {
+			health.Error = fmt.Sprintf("ping error: %v", err)
+			health.NeedsRecovery = true
+		} else {
+			health.Error = "FalkorDB not running"
+		}
+		return health
+	}
+
+	health.ContainerRunning = true // FalkorDB is running (may not be our container)
+	health.PingOK = true
+
+	// FalkorDB creates a graph on first write, so a missing graph is not an error
+	graphs, err := client.Do(ctx, "GRAPH.LIST").StringSlice()
+	if err != nil {
+		health.Error = fmt.Sprintf("graph module unavailable: %v", err)
+		return health
+	}
+	for _, name := range graphs {
+		if name == m.graphName {
+			health.GraphExists = true
+			break
+		}
+	}
+
+	return health
+}
+
+// restartContainer restarts the FalkorDB container.
+func (m *FalkorDBManager) restartContainer(ctx context.Context) error {
+	m.logger.Info().Str("container", m.containerName).Msg("restarting container")
+
+	if err := m.runContainerCmd(ctx, "restart", m.containerName); err != nil {
+		return fmt.Errorf("restart container: %w", err)
+	}
+
+	return m.waitForPing(ctx, 30*time.Second)
+}
+
+// runContainerCmd runs a container command.
+func (m *FalkorDBManager) runContainerCmd(ctx context.Context, args ...string) error {
+	return runContainerCommand(ctx, m.logger, m.containerCmd, args...)
+}
+
+// GetStorageDir returns the FalkorDB storage directory path.
+func (m *FalkorDBManager) GetStorageDir() string {
+	return m.storageDir
+}
+
+// IsAvailable returns true if FalkorDB can be managed (container runtime exists).
+func (m *FalkorDBManager) IsAvailable() bool {
+	return m.containerCmd != ""
+}
+
+// GetContainerRuntime returns the detected container runtime path.
+// Returns empty string if no runtime is available.
+func (m *FalkorDBManager) GetContainerRuntime() string {
+	return m.containerCmd
+}
+
+// GetContainerName returns the FalkorDB container name.
+func (m *FalkorDBManager) GetContainerName() string {
+	return m.containerName
+}
+
+// GetHost returns the host FalkorDB is reached on.
+func (m *FalkorDBManager) GetHost() string {
+	return m.host
+}
+
+// GetPort returns the Redis port configured for FalkorDB.
+func (m *FalkorDBManager) GetPort() int {
+	return m.port
+}
+
+// GetGraphName returns the graph name used for the knowledge graph.
+func (m *FalkorDBManager) GetGraphName() string {
+	return m.graphName
+}
+
+// Stop stops the FalkorDB container (preserves data).
+func (m *FalkorDBManager) Stop(ctx context.Context) error {
+	if m.containerCmd == "" {
+		return fmt.Errorf("no container runtime available")
+	}
+
+	running, err := m.isContainerRunning(ctx)
+	if err != nil {
+		return fmt.Errorf("check container status: %w", err)
+	}
+
+	if !running {
+		m.logger.Info().Str("container", m.containerName).Msg("container is not running")
+		return nil
+	}
+
+	m.logger.Info().Str("container", m.containerName).Msg("stopping container")
+	if err := m.runContainerCmd(ctx, "stop", m.containerName); err != nil {
+		return fmt.Errorf("stop container: %w", err)
+	}
+
+	m.logger.Info().Str("container", m.containerName).Msg("container stopped")
+	return nil
+}
+
+// Remove removes the FalkorDB container (preserves storage data).
+func (m *FalkorDBManager) Remove(ctx context.Context) error {
+	if m.containerCmd == "" {
+		return fmt.Errorf("no container runtime available")
+	}
+
+	exists, err := m.containerExists(ctx)
+	if err != nil {
+		return fmt.Errorf("check container exists: %w", err)
+	}
+
+	if !exists {
+		m.logger.Info().Str("container", m.containerName).Msg("container does not exist")
+		return nil
+	}
+
+	running, _ := m.isContainerRunning(ctx)
+	if running {
+		m.logger.Info().Str("container", m.containerName).Msg("stopping container before removal")
+		_ = m.runContainerCmd(ctx, "stop", m.containerName)
+	}
+
+	m.logger.Info().Str("container", m.containerName).Msg("removing container")
+	if err := m.runContainerCmd(ctx, "rm", m.containerName); err != nil {
+		return fmt.Errorf("remove container: %w", err)
+	}
+
+	m.logger.Info().Str("container", m.containerName).Msg("container removed (storage data preserved)")
+	return nil
+}
+
+// Install installs and starts FalkorDB (pulls image, creates and starts container).
+func (m *FalkorDBManager) Install(ctx context.Context) error {
+	if !m.isManaged() {
+		return fmt.Errorf("FalkorDB host %s is not local, cannot install a container", m.host)
+	}
+
+	if m.containerCmd == "" {
+		if err := m.detectContainerRuntime(); err != nil {
+			return fmt.Errorf("no container runtime: %w", err)
+		}
+	}
+
+	if m.isReachable(ctx) {
+		m.logger.Info().Int("port", m.port).Msg("FalkorDB is already running")
+		return nil
+	}
+
+	if err := m.ensureStorageDir(); err != nil {
+		return fmt.Errorf("ensure storage directory: %w", err)
+	}
+
+	// Remove existing container if any (to ensure clean state)
+	exists, _ := m.containerExists(ctx)
+	if exists {
+		m.logger.Info().Str("container", m.containerName).Msg("removing existing container")
+		_ = m.runContainerCmd(ctx, "stop", m.containerName)
+		_ = m.runContainerCmd(ctx, "rm", m.containerName)
+	}
+
+	m.logger.Info().Msg("pulling FalkorDB image...")
+	if err := m.runContainerCmd(ctx, "pull", falkorDBImage); err != nil {
+		m.logger.Warn().Err(err).Msg("failed to pull image, trying with existing")
+	}
+
+	m.logger.Info().Str("container", m.containerName).Msg("creating FalkorDB container")
+	if err := m.createContainer(ctx); err != nil {
+		return fmt.Errorf("create container: %w", err)
+	}
+
+	m.logger.Info().Msg("waiting for FalkorDB to be ready...")
+	if err := m.waitForPing(ctx, 60*time.Second); err != nil {
+		return fmt.Errorf("FalkorDB failed to start: %w", err)
+	}
+
+	m.logger.Info().Msg("FalkorDB installed and running")
+	return nil
+}
+
+// SetContainerRuntime explicitly sets the container runtime to use.
+func (m *FalkorDBManager) SetContainerRuntime(runtime string) {
+	m.containerCmd = runtime
+}
+
+// DetectContainerRuntime detects and sets the container runtime.
+// Returns the detected runtime path or error if none available.
+func (m *FalkorDBManager) DetectContainerRuntime() (string, error) {
+	if err := m.detectContainerRuntime(); err != nil {
+		return "", err
+	}
+	return m.containerCmd, nil
+}
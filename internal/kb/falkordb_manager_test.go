@@ -0,0 +1,81 @@
+package kb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFalkorDBManager_CreateContainerArgs(t *testing.T) {
+	m := NewFalkorDBManager(FalkorDBManagerConfig{DataDir: t.TempDir(), Port: 6390})
+
+	args, env := m.createContainerArgs()
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "-p 127.0.0.1:6390:6379") || args[len(args)-1] != falkorDBImage {
+		t.Errorf("unexpected args: %s", joined)
+	}
+	if strings.Contains(joined, "REDIS_ARGS") || len(env) != 0 {
+		t.Errorf("expected no password settings without a password, got %s %v", joined, env)
+	}
+
+	m = NewFalkorDBManager(FalkorDBManagerConfig{DataDir: t.TempDir(), Password: "hunter2"})
+	args, env = m.createContainerArgs()
+	joined = strings.Join(args, " ")
+	if strings.Contains(joined, "hunter2") {
+		t.Errorf("password in run arguments: %s", joined)
+	}
+	if !strings.Contains(joined, "-e REDIS_ARGS "+falkorDBImage) {
+		t.Errorf("expected REDIS_ARGS passed by name, got %s", joined)
+	}
+	if len(env) != 1 || env[0] != "REDIS_ARGS=--requirepass hunter2" {
+		t.Errorf("unexpected env: %v", env)
+	}
+}
+
+// The password goes from the config to the container through the runtime's
+// environment, is used by the health client, and is kept out of errors.
+func TestFalkorDBManager_PasswordLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	record := filepath.Join(dir, "record")
+	runtimeCmd := filepath.Join(dir, "docker")
+	script := "#!/bin/sh\n" +
+		"printf 'args=%s\\nenv=%s\\n' \"$*\" \"$REDIS_ARGS\" > " + record + "\n" +
+		"if [ -n \"$FAIL\" ]; then echo \"bad option: $REDIS_ARGS\"; exit 1; fi\n"
+	if err := os.WriteFile(runtimeCmd, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewFalkorDBManager(FalkorDBManagerConfig{DataDir: dir, Password: "hunter2"})
+	m.SetContainerRuntime(runtimeCmd)
+	ctx := context.Background()
+
+	if err := m.createContainer(ctx); err != nil {
+		t.Fatalf("createContainer: %v", err)
+	}
+	data, err := os.ReadFile(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if strings.Contains(strings.SplitN(got, "\n", 2)[0], "hunter2") {
+		t.Errorf("password passed as an argument: %s", got)
+	}
+	if !strings.Contains(got, "env=--requirepass hunter2") {
+		t.Errorf("password not passed to the runtime environment: %s", got)
+	}
+
+	if pw := m.newClient().Options().Password; pw != "hunter2" {
+		t.Errorf("health client password = %q", pw)
+	}
+
+	t.Setenv("FAIL", "1")
+	err = m.createContainer(ctx)
+	if err == nil {
+		t.Fatal("expected the failing runtime to return an error")
+	}
+	if strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), "REDACTED") {
+		t.Errorf("expected the password to be scrubbed from %q", err)
+	}
+}
@@ -261,6 +261,9 @@ const (
 	// MaxRelationsPerChunk is the maximum relations extracted from one chunk
 	MaxRelationsPerChunk = 100
 
+	// MaxAliasesPerEntity is the maximum aliases kept for one entity
+	MaxAliasesPerEntity = 10
+
 	// MaxEntitiesHint is the maximum entity hints in a query
 	MaxEntitiesHint = 50
 
@@ -2,6 +2,7 @@ package kb
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"regexp"
 	"sort"
@@ -20,6 +21,16 @@ const (
 	DefaultSimilarityFloor = 0.001 // Minimum RRF score threshold (lowered to avoid filtering valid results)
 	DefaultRerankTopN      = 30    // Rerank top 30 candidates
 	DefaultRerankKeep      = 10    // Keep top 10 after reranking
+	DefaultRRFConstant     = 60    // Standard RRF k constant
+
+	// DefaultSemanticTimeout bounds the semantic leg of fusion search; past it
+	// the search degrades to lexical results instead of waiting on embeddings.
+	DefaultSemanticTimeout = 5 * time.Second
+
+	// DefaultMaxExpansionTerms and MaxExpansionTerms bound the entity names
+	// ExpandEntities adds to a query.
+	DefaultMaxExpansionTerms = 5
+	MaxExpansionTerms        = 20
 )
 
 // QueryType represents the classified intent of a search query.
@@ -33,6 +44,9 @@ const (
 	QueryTypeExploratory QueryType = "exploratory"  // Broad topic exploration
 )
 
+// QueryTypes lists every query type.
+var QueryTypes = []QueryType{QueryTypeExactQuote, QueryTypeEntity, QueryTypeConceptual, QueryTypeFactual, QueryTypeExploratory}
+
 // SearchStrategy identifies which search method found a result.
 type SearchStrategy string
 
@@ -48,7 +62,8 @@ type StrategyWeights struct {
 	Lexical  float64
 }
 
-// strategyWeightMatrix maps query types to optimal strategy weights.
+// strategyWeightMatrix maps query types to their default strategy weights
+// (see HybridSearcherConfig).
 var strategyWeightMatrix = map[QueryType]StrategyWeights{
 	QueryTypeExactQuote:  {Semantic: 0.1, Lexical: 0.9},  // Lexical dominant
 	QueryTypeEntity:      {Semantic: 0.4, Lexical: 0.6},  // Balanced, lexical edge
@@ -74,9 +89,61 @@ var (
 
 // HybridSearcher combines FTS5 (lexical) and vector (semantic) search using RRF.
 type HybridSearcher struct {
-	fts      *Searcher
-	semantic *SemanticSearcher
-	logger   zerolog.Logger
+	fts         *Searcher
+	semantic    *SemanticSearcher
+	kag         *KAGSearcher
+	rrfConstant int
+	weights     map[QueryType]StrategyWeights
+	logger      zerolog.Logger
+}
+
+// HybridSearcherConfig tunes the ranking of a hybrid searcher. Zero values
+// keep the defaults.
+type HybridSearcherConfig struct {
+	// RRFConstant is the RRF k constant (default DefaultRRFConstant). Larger
+	// values flatten the difference between top and lower ranks.
+	RRFConstant int
+
+	// SemanticWeight, when set, weighs semantic results by it and lexical
+	// results by 1-SemanticWeight for every query type
+	SemanticWeight float64
+
+	// Weights overrides the strategy weights of individual query types,
+	// taking precedence over SemanticWeight
+	Weights map[QueryType]StrategyWeights
+}
+
+// Validate checks that the RRF constant is not negative (0 keeps the
+// default) and the weights are in [0, 1], not both zero, for known query
+// types.
+func (c HybridSearcherConfig) Validate() error {
+	if c.RRFConstant < 0 {
+		return fmt.Errorf("rrf_constant must not be negative (0 uses the default %d), got %d", DefaultRRFConstant, c.RRFConstant)
+	}
+	if c.SemanticWeight < 0 || c.SemanticWeight > 1 {
+		return fmt.Errorf("semantic_weight must be between 0 and 1, got %g", c.SemanticWeight)
+	}
+	for queryType, w := range c.Weights {
+		if _, ok := strategyWeightMatrix[queryType]; !ok {
+			return fmt.Errorf("unknown query type %q (valid: %s)", queryType, joinQueryTypes())
+		}
+		if w.Semantic < 0 || w.Semantic > 1 || w.Lexical < 0 || w.Lexical > 1 {
+			return fmt.Errorf("%s weights must be between 0 and 1, got semantic %g and lexical %g", queryType, w.Semantic, w.Lexical)
+		}
+		if w.Semantic == 0 && w.Lexical == 0 {
+			return fmt.Errorf("%s weights must not both be 0", queryType)
+		}
+	}
+	return nil
+}
+
+// joinQueryTypes lists the query types for error messages.
+func joinQueryTypes() string {
+	names := make([]string, len(QueryTypes))
+	for i, t := range QueryTypes {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
 }
 
 // HybridSearchMode determines how searches are combined.
@@ -118,20 +185,63 @@ const (
 // HybridSearchOptions configures hybrid search behavior.
 type HybridSearchOptions struct {
 	Limit           int              // Max results (default 10)
+	Offset          int              // Pagination offset into the final ranking
 	Mode            HybridSearchMode // Search mode (default auto)
 	RecallMode      RecallMode       // Recall/precision tradeoff preset (default balanced)
-	SemanticWeight  float64          // Weight for semantic results in fusion (0-1, default 0.5)
-	RRFConstant     int              // RRF k constant (default 60)
+	SemanticWeight  float64          // Weight for semantic results in fusion (0-1), overriding the query type's weights
+	RRFConstant     int              // RRF k constant (default from HybridSearcherConfig)
 	BoostExactMatch bool             // Boost results with exact query match (default true)
 	SourceIDs       []string         // Filter by source IDs
 	MimeTypes       []string         // Filter by MIME types
-
-	// Quality enhancement options (configurable via RecallMode presets)
-	EnableMMR       bool    // Enable Maximal Marginal Relevance for diversity
+	PathPrefix      string           // Filter by document path prefix (see SearchOptions)
+	Languages       []string         // Filter by chunk language (see SearchOptions)
+	ModifiedAfter   time.Time        // Only documents modified at or after this time
+	ModifiedBefore  time.Time        // Only documents modified before this time
+
+	// Quality enhancement options (configurable via RecallMode presets).
+	// EnableMMR and EnableRerank are nil to use the RecallMode preset; set
+	// them with BoolPtr to force either on or off.
+	EnableMMR       *bool   // Enable Maximal Marginal Relevance for diversity
 	MMRLambda       float64 // MMR lambda: 0=max diversity, 1=max relevance (default 0.7)
 	SimilarityFloor float64 // Minimum score threshold, reject below this
-	EnableRerank    bool    // Enable reranking of top candidates
+	EnableRerank    *bool   // Enable reranking of top candidates
 	RerankTopN      int     // Number of candidates to consider for reranking (default 30)
+
+	// SemanticTimeout limits how long fusion search waits for semantic
+	// results before continuing with FTS5 only (default 5s)
+	SemanticTimeout time.Duration
+
+	// Explain attaches a per-result score breakdown (diagnostic only, ranking is unchanged)
+	Explain bool
+
+	// SnippetLength is the length of each hit's snippet in characters, for
+	// both the FTS5 and semantic legs (default DefaultSnippetLength)
+	SnippetLength int
+
+	// ExpandEntities adds the other names of knowledge graph entities the
+	// query mentions (aliases and similar_to entities) as alternatives to
+	// the FTS5 leg. It needs a KAG searcher (see SetKAGSearcher).
+	// MaxExpansionTerms caps the names added (default DefaultMaxExpansionTerms).
+	ExpandEntities    bool
+	MaxExpansionTerms int
+
+	// alternatives are the expanded phrasings of the query for the FTS5 leg
+	alternatives []string
+}
+
+// BoolPtr returns a pointer to v, for the optional HybridSearchOptions toggles.
+func BoolPtr(v bool) *bool {
+	return &v
+}
+
+// mmrEnabled reports whether MMR diversity applies to this search.
+func (opts HybridSearchOptions) mmrEnabled() bool {
+	return opts.EnableMMR != nil && *opts.EnableMMR
+}
+
+// rerankEnabled reports whether reranking applies to this search.
+func (opts HybridSearchOptions) rerankEnabled() bool {
+	return opts.EnableRerank != nil && *opts.EnableRerank
 }
 
 // HybridSearchResult contains combined search results with metadata.
@@ -144,6 +254,12 @@ type HybridSearchResult struct {
 	FTSHits        int              `json:"fts_hits"`
 	SemanticHits   int              `json:"semantic_hits"`
 	QueryAnalysis  QueryAnalysis    `json:"query_analysis,omitempty"`
+	Offset         int              `json:"offset,omitempty"`
+	HasMore       bool             `json:"has_more,omitempty"` // More results exist after this page
+
+	// TotalIsLowerBound is set when TotalHits only counts the candidates
+	// examined and more matches may exist.
+	TotalIsLowerBound bool `json:"total_is_lower_bound,omitempty"`
 
 	// Quality enhancement metrics
 	RejectedByFloor int  `json:"rejected_by_floor,omitempty"` // Count of results below similarity floor
@@ -155,9 +271,29 @@ type HybridSearchResult struct {
 	StrategiesUsed   int      `json:"strategies_used,omitempty"`    // Number of strategies that contributed
 	DegradedMode     bool     `json:"degraded_mode,omitempty"`      // True if semantic search timed out/failed
 	Note             string   `json:"note,omitempty"`               // Human-readable note about results
-	FallbackLevel    int      `json:"fallback_level,omitempty"`     // 0=primary, 1=relaxed, 2=partial
+	FallbackLevel    int      `json:"fallback_level,omitempty"`     // See FallbackPrimary
+
+	// Query terms replaced by indexed words at FallbackSpelling
+	SpellingCorrections []SpellingCorrection `json:"spelling_corrections,omitempty"`
+
+	// Entity names added to the query by ExpandEntities
+	EntityExpansions []EntityExpansion `json:"entity_expansions,omitempty"`
+
+	// bm25Scores is set when the hits carry raw FTS5 scores (lexical-only
+	// search, or semantic-only falling back to it), which rank lower first
+	bm25Scores bool
 }
 
+// Fallback levels of SearchWithFallback (HybridSearchResult.FallbackLevel),
+// from the primary search to progressively looser matching.
+const (
+	FallbackPrimary  = 0 // The query as given
+	FallbackRelaxed  = 1 // Prefix matching of any term
+	FallbackSpelling = 2 // Misspelled terms replaced by similar indexed words
+	FallbackPartial  = 3 // Individual words
+	FallbackNone     = 4 // Nothing matched
+)
+
 // QueryAnalysis provides insight into how the query was interpreted.
 type QueryAnalysis struct {
 	HasQuotedPhrase bool      `json:"has_quoted_phrase,omitempty"`
@@ -178,28 +314,142 @@ type EnhancedSearchHit struct {
 	BestRank     int              `json:"best_rank,omitempty"`     // Best rank across strategies
 }
 
-// NewHybridSearcher creates a new hybrid searcher.
+// NewHybridSearcher creates a new hybrid searcher with the default ranking.
 func NewHybridSearcher(fts *Searcher, semantic *SemanticSearcher) *HybridSearcher {
-	return &HybridSearcher{
-		fts:      fts,
-		semantic: semantic,
-		logger:   observability.Logger("kb.hybrid"),
+	hs, _ := NewHybridSearcherWithConfig(fts, semantic, HybridSearcherConfig{})
+	return hs
+}
+
+// NewHybridSearcherWithConfig creates a hybrid searcher with tuned ranking.
+func NewHybridSearcherWithConfig(fts *Searcher, semantic *SemanticSearcher, cfg HybridSearcherConfig) (*HybridSearcher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	weights := make(map[QueryType]StrategyWeights, len(strategyWeightMatrix))
+	for queryType, w := range strategyWeightMatrix {
+		if cfg.SemanticWeight > 0 {
+			w = StrategyWeights{Semantic: cfg.SemanticWeight, Lexical: 1 - cfg.SemanticWeight}
+		}
+		weights[queryType] = w
+	}
+	for queryType, w := range cfg.Weights {
+		weights[queryType] = w
+	}
+	rrfConstant := cfg.RRFConstant
+	if rrfConstant == 0 {
+		rrfConstant = DefaultRRFConstant
 	}
+
+	return &HybridSearcher{
+		fts:         fts,
+		semantic:    semantic,
+		rrfConstant: rrfConstant,
+		weights:     weights,
+		logger:      observability.Logger("kb.hybrid"),
+	}, nil
+}
+
+// SetKAGSearcher enables entity query expansion (HybridSearchOptions.ExpandEntities)
+// from the knowledge graph.
+func (hs *HybridSearcher) SetKAGSearcher(kag *KAGSearcher) {
+	hs.kag = kag
 }
 
 // Search performs hybrid search using the configured mode.
 func (hs *HybridSearcher) Search(ctx context.Context, query string, opts HybridSearchOptions) (*HybridSearchResult, error) {
 	start := time.Now()
 
-	// Apply defaults
+	if opts.RRFConstant <= 0 {
+		opts.RRFConstant = hs.rrfConstant
+	}
+	opts = opts.withDefaults()
+
+	var expansions []EntityExpansion
+	if opts.ExpandEntities && hs.kag != nil {
+		expansions, opts.alternatives = hs.expandQuery(ctx, query, opts.MaxExpansionTerms)
+	}
+
+	// Analyze query
+	analysis := hs.analyzeQuery(query)
+
+	// Determine mode if auto
+	mode := opts.Mode
+	if mode == "" || mode == HybridModeAuto {
+		mode = hs.selectMode(analysis)
+		analysis.SuggestedMode = string(mode)
+	}
+
+	hs.loggerFor(ctx).Debug().
+		Str("query", query).
+		Str("mode", string(mode)).
+		Bool("has_quoted", analysis.HasQuotedPhrase).
+		Strs("proper_nouns", analysis.ProperNouns).
+		Msg("hybrid search starting")
+
+	var result *HybridSearchResult
+
+	switch mode {
+	case HybridModeLexical:
+		result = hs.searchFTSOnly(ctx, query, opts)
+	case HybridModeSemantic:
+		result = hs.searchSemanticOnly(ctx, query, opts)
+	default:
+		result = hs.searchFusion(ctx, query, opts, analysis)
+	}
+
+	result.Query = query
+	result.Mode = mode
+	result.Offset = opts.Offset
+	result.SearchTime = float64(time.Since(start).Milliseconds())
+	result.QueryAnalysis = analysis
+	result.EntityExpansions = expansions
+
+	return result, nil
+}
+
+// expandQuery looks up the other names of the entities the query mentions
+// and returns them with the query rephrased once per name, the mention
+// replaced by the name. Lookup failures leave the query unexpanded.
+func (hs *HybridSearcher) expandQuery(ctx context.Context, query string, maxTerms int) ([]EntityExpansion, []string) {
+	expansions, err := hs.kag.ExpandQuery(ctx, query, maxTerms)
+	if err != nil {
+		hs.loggerFor(ctx).Warn().Err(err).Str("query", query).Msg("entity query expansion failed")
+		return nil, nil
+	}
+
+	var alternatives []string
+	for _, e := range expansions {
+		mention := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(e.Mention))
+		for _, name := range e.Names {
+			alternatives = append(alternatives, mention.ReplaceAllLiteralString(query, name))
+		}
+	}
+	if len(alternatives) > 0 {
+		hs.loggerFor(ctx).Debug().Str("query", query).Strs("alternatives", alternatives).Msg("query expanded with entity names")
+	}
+	return expansions, alternatives
+}
+
+// withDefaults fills in unset options and applies the RecallMode preset.
+func (opts HybridSearchOptions) withDefaults() HybridSearchOptions {
 	if opts.Limit <= 0 {
 		opts.Limit = 10
 	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+	if opts.SemanticTimeout <= 0 {
+		opts.SemanticTimeout = DefaultSemanticTimeout
+	}
 	if opts.RRFConstant <= 0 {
-		opts.RRFConstant = 60 // Standard RRF constant
+		opts.RRFConstant = DefaultRRFConstant
+	}
+	if opts.MaxExpansionTerms <= 0 {
+		opts.MaxExpansionTerms = DefaultMaxExpansionTerms
 	}
-	if opts.SemanticWeight <= 0 {
-		opts.SemanticWeight = 0.5 // Equal weight by default
+	if opts.MaxExpansionTerms > MaxExpansionTerms {
+		opts.MaxExpansionTerms = MaxExpansionTerms
 	}
 	opts.BoostExactMatch = true // Always boost exact matches
 
@@ -209,12 +459,13 @@ func (hs *HybridSearcher) Search(ctx context.Context, query string, opts HybridS
 		opts.RecallMode = RecallModeBalanced
 	}
 
-	// Apply preset configurations based on RecallMode
+	// Apply preset configurations based on RecallMode. MMR and reranking
+	// follow the preset only when the caller left them unset.
+	var presetMMR, presetRerank bool
 	switch opts.RecallMode {
 	case RecallModeHigh:
 		// High recall: disable MMR, no floor, more candidates
-		opts.EnableMMR = false
-		opts.EnableRerank = true
+		presetMMR, presetRerank = false, true
 		opts.SimilarityFloor = 0.0
 		opts.MMRLambda = 1.0 // Not used when MMR disabled, but set for consistency
 		if opts.RerankTopN <= 0 {
@@ -222,8 +473,7 @@ func (hs *HybridSearcher) Search(ctx context.Context, query string, opts HybridS
 		}
 	case RecallModePrecise:
 		// High precision: aggressive MMR, higher floor
-		opts.EnableMMR = true
-		opts.EnableRerank = true
+		presetMMR, presetRerank = true, true
 		opts.SimilarityFloor = 0.01
 		opts.MMRLambda = 0.5 // 50% relevance, 50% diversity
 		if opts.RerankTopN <= 0 {
@@ -231,8 +481,7 @@ func (hs *HybridSearcher) Search(ctx context.Context, query string, opts HybridS
 		}
 	default: // RecallModeBalanced
 		// Balanced: moderate MMR, standard floor
-		opts.EnableMMR = true
-		opts.EnableRerank = true
+		presetMMR, presetRerank = true, true
 		if opts.SimilarityFloor <= 0 {
 			opts.SimilarityFloor = DefaultSimilarityFloor
 		}
@@ -243,41 +492,20 @@ func (hs *HybridSearcher) Search(ctx context.Context, query string, opts HybridS
 			opts.RerankTopN = DefaultRerankTopN
 		}
 	}
-
-	// Analyze query
-	analysis := hs.analyzeQuery(query)
-
-	// Determine mode if auto
-	mode := opts.Mode
-	if mode == "" || mode == HybridModeAuto {
-		mode = hs.selectMode(analysis)
-		analysis.SuggestedMode = string(mode)
+	if opts.EnableMMR == nil {
+		opts.EnableMMR = BoolPtr(presetMMR)
 	}
-
-	hs.logger.Debug().
-		Str("query", query).
-		Str("mode", string(mode)).
-		Bool("has_quoted", analysis.HasQuotedPhrase).
-		Strs("proper_nouns", analysis.ProperNouns).
-		Msg("hybrid search starting")
-
-	var result *HybridSearchResult
-
-	switch mode {
-	case HybridModeLexical:
-		result = hs.searchFTSOnly(ctx, query, opts)
-	case HybridModeSemantic:
-		result = hs.searchSemanticOnly(ctx, query, opts)
-	default:
-		result = hs.searchFusion(ctx, query, opts, analysis)
+	if opts.EnableRerank == nil {
+		opts.EnableRerank = BoolPtr(presetRerank)
 	}
 
-	result.Query = query
-	result.Mode = mode
-	result.SearchTime = float64(time.Since(start).Milliseconds())
-	result.QueryAnalysis = analysis
+	return opts
+}
 
-	return result, nil
+// loggerFor returns the searcher's logger tagged with the request ID in ctx.
+func (hs *HybridSearcher) loggerFor(ctx context.Context) *zerolog.Logger {
+	logger := observability.LoggerFromContext(ctx, hs.logger)
+	return &logger
 }
 
 // analyzeQuery examines the query to determine the best search strategy.
@@ -417,27 +645,31 @@ func (hs *HybridSearcher) selectMode(analysis QueryAnalysis) HybridSearchMode {
 // searchFusion performs parallel FTS5 and semantic search, then combines with RRF.
 // Phase 12: Enhanced with agreement analysis and query-adaptive weighting.
 func (hs *HybridSearcher) searchFusion(ctx context.Context, query string, opts HybridSearchOptions, analysis QueryAnalysis) *HybridSearchResult {
-	// Fetch more candidates than needed for better fusion
-	candidateLimit := opts.Limit * 3
-	if candidateLimit < 30 {
-		candidateLimit = 30
-	}
+	window := opts.Offset + opts.Limit
+	candidateLimit := fusionCandidateLimit(opts.Offset, opts.Limit)
 
 	var ftsHits []SearchHit
 	var semanticHits []SearchHit
+	ftsTotal := 0
 	var wg sync.WaitGroup
 	var ftsErr, semErr error
-	semanticDegraded := false
+	semanticDegraded, semanticTimedOut := false, false
 
 	// Run FTS5 search
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		ftsOpts := SearchOptions{
-			Limit:     candidateLimit,
-			SourceIDs: opts.SourceIDs,
-			MimeTypes: opts.MimeTypes,
-			Highlight: true,
+			Limit:          candidateLimit,
+			SourceIDs:      opts.SourceIDs,
+			MimeTypes:      opts.MimeTypes,
+			PathPrefix:     opts.PathPrefix,
+			Languages:      opts.Languages,
+			ModifiedAfter:  opts.ModifiedAfter,
+			ModifiedBefore: opts.ModifiedBefore,
+			Highlight:      true,
+			ContextLen:     opts.SnippetLength / 2,
+			Alternatives:   opts.alternatives,
 		}
 		result, err := hs.fts.Search(ctx, query, ftsOpts)
 		if err != nil {
@@ -445,53 +677,69 @@ func (hs *HybridSearcher) searchFusion(ctx context.Context, query string, opts H
 			return
 		}
 		ftsHits = result.Results
+		ftsTotal = result.TotalHits
 	}()
 
-	// Run semantic search (if available)
+	// Run semantic search (if available) with its own deadline, so a slow
+	// embedding server cannot hold up the lexical results
+	var awaitSemanticHits func() ([]SearchHit, bool, error)
 	if hs.semantic != nil {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			semOpts := SemanticSearchOptions{
-				Limit:     candidateLimit,
-				SourceIDs: opts.SourceIDs,
-				MimeTypes: opts.MimeTypes,
-			}
-			result, err := hs.semantic.Search(ctx, query, semOpts)
+		semOpts := SemanticSearchOptions{
+			Limit:          candidateLimit,
+			SourceIDs:      opts.SourceIDs,
+			MimeTypes:      opts.MimeTypes,
+			PathPrefix:     opts.PathPrefix,
+			Languages:      opts.Languages,
+			ModifiedAfter:  opts.ModifiedAfter,
+			ModifiedBefore: opts.ModifiedBefore,
+			ContextLen:     opts.SnippetLength,
+		}
+		awaitSemanticHits = startSemanticLeg(ctx, opts.SemanticTimeout, func(semCtx context.Context) ([]SearchHit, error) {
+			result, err := hs.semantic.Search(semCtx, query, semOpts)
 			if err != nil {
-				semErr = err
-				semanticDegraded = true
-				return
+				return nil, err
 			}
 			// Convert SemanticSearchHit to SearchHit
+			hits := make([]SearchHit, 0, len(result.Results))
 			for _, hit := range result.Results {
-				semanticHits = append(semanticHits, SearchHit{
+				hits = append(hits, SearchHit{
 					DocumentID: hit.DocumentID,
 					ChunkID:    hit.ChunkID,
 					Path:       hit.Path,
 					Title:      hit.Title,
 					Snippet:    hit.Snippet,
 					Score:      hit.Score,
+					RawScore:   hit.Score,
 					Metadata:   hit.Metadata,
+					Content:    hit.Content,
 				})
 			}
-		}()
+			return hits, nil
+		})
 	}
 
 	wg.Wait()
+	if awaitSemanticHits != nil {
+		semanticHits, semanticTimedOut, semErr = awaitSemanticHits()
+	}
 
 	// Log any errors but continue with available results
 	if ftsErr != nil {
-		hs.logger.Warn().Err(ftsErr).Msg("FTS5 search failed, using semantic only")
+		hs.loggerFor(ctx).Warn().Err(ftsErr).Msg("FTS5 search failed, using semantic only")
 	}
-	if semErr != nil {
-		hs.logger.Warn().Err(semErr).Msg("semantic search failed, using FTS5 only")
+	if semanticTimedOut {
+		hs.loggerFor(ctx).Warn().
+			Dur("timeout", opts.SemanticTimeout).
+			Msg("semantic search timed out, using FTS5 only")
+		semanticDegraded = true
+	} else if semErr != nil {
+		hs.loggerFor(ctx).Warn().Err(semErr).Msg("semantic search failed, using FTS5 only")
 		semanticDegraded = true
 	}
 
 	// Phase 12: Get query-type-specific weights
 	weights := hs.getWeightsForQueryType(analysis.QueryType)
-	if opts.SemanticWeight > 0 {
+	if opts.SemanticWeight > 0 && opts.SemanticWeight <= 1 {
 		// Allow override from options
 		weights.Semantic = opts.SemanticWeight
 		weights.Lexical = 1.0 - opts.SemanticWeight
@@ -529,40 +777,99 @@ func (hs *HybridSearcher) searchFusion(ctx context.Context, query string, opts H
 	fused = hs.applySimilarityFloor(fused, opts.SimilarityFloor)
 	result.RejectedByFloor = beforeFloor - len(fused)
 
+	// Count matches before MMR trims the list to the page. A leg that filled
+	// its pool may have more matches than were fused.
+	result.TotalHits = len(fused)
+	result.TotalIsLowerBound = ftsTotal > len(ftsHits) || len(ftsHits) >= candidateLimit ||
+		len(semanticHits) >= candidateLimit
+
 	// Phase 11: Apply reranking on top candidates
-	if opts.EnableRerank && len(fused) > 0 {
+	if opts.rerankEnabled() && len(fused) > 0 {
 		fused = hs.applyReranking(fused, query, opts.RerankTopN, semanticHits)
 		result.Reranked = true
 	}
 
 	// Phase 11: Apply MMR for diversity (after reranking)
-	if opts.EnableMMR && len(fused) > 1 {
-		fused = hs.applyMMR(fused, opts.MMRLambda, opts.Limit)
+	if opts.mmrEnabled() && len(fused) > 1 {
+		// Select one extra result so HasMore can be reported
+		fused = hs.applyMMR(fused, opts.MMRLambda, window+1)
 		result.MMRApplied = true
 	}
 
-	// Final limit
-	if len(fused) > opts.Limit {
-		fused = fused[:opts.Limit]
+	// Final page
+	fused, result.HasMore = paginateHits(fused, opts.Offset, opts.Limit)
+
+	finalizeExplanations(fused, opts.Explain)
+	if opts.Explain {
+		for _, hit := range fused {
+			e := hit.Explanation
+			hs.loggerFor(ctx).Debug().
+				Str("query", query).
+				Str("chunk_id", e.ChunkID).
+				Int("rank", e.Rank).
+				Int("lexical_rank", e.LexicalRank).
+				Int("semantic_rank", e.SemanticRank).
+				Float64("lexical_rrf", e.LexicalRRF).
+				Float64("semantic_rrf", e.SemanticRRF).
+				Float64("entity_boost", e.EntityBoost).
+				Float64("agreement_boost", e.AgreementBoost).
+				Float64("rerank_multiplier", e.RerankMultiplier).
+				Float64("final_score", e.FinalScore).
+				Msg("hybrid ranking decision")
+		}
 	}
 
 	result.Results = fused
-	result.TotalHits = len(fused)
 
 	// Phase 12: Calculate overall confidence
 	result.Confidence = hs.calculateOverallConfidence(fused, agreementInfo, strategiesUsed, semanticDegraded)
 
 	// Add note if degraded
-	if semanticDegraded {
+	if semanticTimedOut {
+		result.Note = fmt.Sprintf("Semantic search timed out after %s, using lexical search only", opts.SemanticTimeout)
+	} else if semanticDegraded {
 		result.Note = "Semantic search unavailable, using lexical search only"
 	}
 
 	return result
 }
 
-// getWeightsForQueryType returns the optimal weights for the given query type.
+// startSemanticLeg runs search in the background under its own timeout and
+// returns a function that waits for it. The wait returns as soon as the
+// timeout expires, reporting timedOut, even if search ignores cancellation.
+func startSemanticLeg(ctx context.Context, timeout time.Duration, search func(context.Context) ([]SearchHit, error)) func() (hits []SearchHit, timedOut bool, err error) {
+	type outcome struct {
+		hits []SearchHit
+		err  error
+	}
+
+	semCtx, cancel := context.WithTimeout(ctx, timeout)
+	done := make(chan outcome, 1) // Buffered so a late search never blocks
+	go func() {
+		hits, err := search(semCtx)
+		done <- outcome{hits: hits, err: err}
+	}()
+
+	return func() ([]SearchHit, bool, error) {
+		defer cancel()
+		select {
+		case out := <-done:
+			if out.err != nil && semCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+				return nil, true, out.err
+			}
+			return out.hits, false, out.err
+		case <-semCtx.Done():
+			if ctx.Err() != nil {
+				return nil, false, ctx.Err()
+			}
+			return nil, true, semCtx.Err()
+		}
+	}
+}
+
+// getWeightsForQueryType returns the configured weights for the given query type.
 func (hs *HybridSearcher) getWeightsForQueryType(queryType QueryType) StrategyWeights {
-	if weights, ok := strategyWeightMatrix[queryType]; ok {
+	if weights, ok := hs.weights[queryType]; ok {
 		return weights
 	}
 	// Default: equal weights
@@ -635,6 +942,21 @@ func (hs *HybridSearcher) applyRRFWithAgreement(ftsHits, semanticHits []SearchHi
 			rrfScore += weights.Semantic * (1.0 / float64(k+rank))
 		}
 
+		hit.Explanation = &ScoreExplanation{
+			ChunkID:          chunkID,
+			LexicalRank:      ftsRanks[chunkID],
+			SemanticRank:     semRanks[chunkID],
+			EntityBoost:      1.0,
+			AgreementBoost:   1.0,
+			RerankMultiplier: 1.0,
+		}
+		if rank := ftsRanks[chunkID]; rank > 0 {
+			hit.Explanation.LexicalRRF = weights.Lexical * (1.0 / float64(k+rank))
+		}
+		if rank := semRanks[chunkID]; rank > 0 {
+			hit.Explanation.SemanticRRF = weights.Semantic * (1.0 / float64(k+rank))
+		}
+
 		scored = append(scored, scoredHit{
 			hit:      hit,
 			rrfScore: rrfScore,
@@ -642,21 +964,39 @@ func (hs *HybridSearcher) applyRRFWithAgreement(ftsHits, semanticHits []SearchHi
 	}
 
 	// Sort by RRF score descending
-	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].rrfScore > scored[j].rrfScore
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].rrfScore != scored[j].rrfScore {
+			return scored[i].rrfScore > scored[j].rrfScore
+		}
+		return scored[i].hit.ChunkID < scored[j].hit.ChunkID
 	})
 
-	// Convert back to SearchHit slice with RRF scores
+	// Convert back to SearchHit slice with RRF scores.
+	// RawScore keeps the unboosted RRF score so callers can threshold on it.
 	result := make([]SearchHit, len(scored))
 	for i, s := range scored {
 		hit := s.hit
 		hit.Score = s.rrfScore
+		hit.RawScore = s.rrfScore
 		result[i] = hit
 	}
 
 	return result, info
 }
 
+// finalizeExplanations records final ranks and scores on score explanations,
+// or strips them when explain was not requested.
+func finalizeExplanations(hits []SearchHit, explain bool) {
+	for i := range hits {
+		if !explain || hits[i].Explanation == nil {
+			hits[i].Explanation = nil
+			continue
+		}
+		hits[i].Explanation.Rank = i + 1
+		hits[i].Explanation.FinalScore = hits[i].Score
+	}
+}
+
 // applyAgreementBoost boosts results that were found by multiple strategies.
 func (hs *HybridSearcher) applyAgreementBoost(hits []SearchHit, info agreementInfo, queryType QueryType) []SearchHit {
 	for i := range hits {
@@ -691,12 +1031,13 @@ func (hs *HybridSearcher) applyAgreementBoost(hits []SearchHit, info agreementIn
 		}
 
 		hits[i].Score *= agreementBonus
+		if hits[i].Explanation != nil {
+			hits[i].Explanation.AgreementBoost = agreementBonus
+		}
 	}
 
 	// Re-sort after boosting
-	sort.Slice(hits, func(i, j int) bool {
-		return hits[i].Score > hits[j].Score
-	})
+	sortHitsByScore(hits)
 
 	return hits
 }
@@ -793,15 +1134,20 @@ func (hs *HybridSearcher) applyRRF(ftsHits, semanticHits []SearchHit, k int, sem
 	}
 
 	// Sort by RRF score descending
-	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].rrfScore > scored[j].rrfScore
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].rrfScore != scored[j].rrfScore {
+			return scored[i].rrfScore > scored[j].rrfScore
+		}
+		return scored[i].hit.ChunkID < scored[j].hit.ChunkID
 	})
 
-	// Convert back to SearchHit slice with RRF scores
+	// Convert back to SearchHit slice with RRF scores.
+	// RawScore keeps the unboosted RRF score so callers can threshold on it.
 	result := make([]SearchHit, len(scored))
 	for i, s := range scored {
 		hit := s.hit
 		hit.Score = s.rrfScore
+		hit.RawScore = s.rrfScore
 		result[i] = hit
 	}
 
@@ -821,10 +1167,12 @@ func (hs *HybridSearcher) boostExactMatches(hits []SearchHit, entities []string)
 	for i := range hits {
 		content := strings.ToLower(hits[i].Snippet + " " + hits[i].Title + " " + hits[i].Path)
 		totalBoost := 1.0
+		var matched []string
 
 		for _, entity := range sortedEntities {
 			entityLower := strings.ToLower(entity)
 			if strings.Contains(content, entityLower) {
+				matched = append(matched, entity)
 				// Boost based on entity length: multi-word gets more boost
 				wordCount := len(strings.Fields(entity))
 				if wordCount >= 2 {
@@ -843,16 +1191,55 @@ func (hs *HybridSearcher) boostExactMatches(hits []SearchHit, entities []string)
 		}
 
 		hits[i].Score *= totalBoost
+		if hits[i].Explanation != nil {
+			hits[i].Explanation.EntityBoost = totalBoost
+			hits[i].Explanation.MatchedEntities = matched
+		}
 	}
 
 	// Re-sort after boosting
-	sort.Slice(hits, func(i, j int) bool {
-		return hits[i].Score > hits[j].Score
-	})
+	sortHitsByScore(hits)
 
 	return hits
 }
 
+// sortHitsByScore orders hits by descending score. Ties are broken by chunk ID
+// so rankings, and therefore pages, are deterministic.
+func sortHitsByScore(hits []SearchHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ChunkID < hits[j].ChunkID
+	})
+}
+
+// fusionCandidateLimit returns how many candidates each fusion leg fetches.
+// The pool is three pages deep (at least 30) and grows in whole multiples of
+// that size to cover the requested window, so every page within one pool
+// ranks the same candidates and deep pages are not cut off.
+func fusionCandidateLimit(offset, limit int) int {
+	base := limit * 3
+	if base < 30 {
+		base = 30
+	}
+	need := offset + limit + 1 // One extra to detect further pages
+	return (need + base - 1) / base * base
+}
+
+// paginateHits returns the page of ranked hits starting at offset, and whether
+// any hits follow it.
+func paginateHits(hits []SearchHit, offset, limit int) ([]SearchHit, bool) {
+	if offset >= len(hits) {
+		return []SearchHit{}, false
+	}
+	hits = hits[offset:]
+	if len(hits) > limit {
+		return hits[:limit], true
+	}
+	return hits, false
+}
+
 // applySimilarityFloor removes results below the minimum score threshold.
 // This prevents low-confidence garbage from appearing in results.
 func (hs *HybridSearcher) applySimilarityFloor(hits []SearchHit, floor float64) []SearchHit {
@@ -905,13 +1292,14 @@ func (hs *HybridSearcher) applyReranking(hits []SearchHit, query string, topN in
 			// Semantic scores are typically 0-1 (cosine similarity)
 			// Boost the RRF score proportionally
 			candidates[i].Score *= (1.0 + semScore)
+			if candidates[i].Explanation != nil {
+				candidates[i].Explanation.RerankMultiplier = 1.0 + semScore
+			}
 		}
 	}
 
 	// Re-sort by new scores
-	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].Score > candidates[j].Score
-	})
+	sortHitsByScore(candidates)
 
 	hs.logger.Debug().
 		Int("candidates", len(candidates)).
@@ -951,7 +1339,7 @@ func (hs *HybridSearcher) applyMMR(hits []SearchHit, lambda float64, limit int)
 			// Diversity: compute max similarity to already selected results
 			maxSimilarity := 0.0
 			for _, sel := range selected {
-				sim := hs.textSimilarity(candidate.Snippet, sel.Snippet)
+				sim := textSimilarity(candidate.Snippet, sel.Snippet)
 				if sim > maxSimilarity {
 					maxSimilarity = sim
 				}
@@ -986,10 +1374,10 @@ func (hs *HybridSearcher) applyMMR(hits []SearchHit, lambda float64, limit int)
 
 // textSimilarity computes Jaccard similarity between two text snippets.
 // Returns a value between 0 (completely different) and 1 (identical).
-func (hs *HybridSearcher) textSimilarity(text1, text2 string) float64 {
+func textSimilarity(text1, text2 string) float64 {
 	// Tokenize into word sets
-	words1 := hs.tokenize(text1)
-	words2 := hs.tokenize(text2)
+	words1 := tokenize(text1)
+	words2 := tokenize(text2)
 
 	if len(words1) == 0 || len(words2) == 0 {
 		return 0.0
@@ -1022,7 +1410,7 @@ func (hs *HybridSearcher) textSimilarity(text1, text2 string) float64 {
 }
 
 // tokenize splits text into lowercase words, filtering out short words and punctuation.
-func (hs *HybridSearcher) tokenize(text string) []string {
+func tokenize(text string) []string {
 	text = strings.ToLower(text)
 	words := strings.Fields(text)
 
@@ -1039,44 +1427,68 @@ func (hs *HybridSearcher) tokenize(text string) []string {
 	return tokens
 }
 
+// HigherScoresBetter reports whether the result's hits rank higher scores
+// first. Fused and semantic scores do; raw FTS5 BM25 scores rank lower first.
+func (r *HybridSearchResult) HigherScoresBetter() bool {
+	return !r.bm25Scores
+}
+
 // searchFTSOnly performs FTS5-only search.
 func (hs *HybridSearcher) searchFTSOnly(ctx context.Context, query string, opts HybridSearchOptions) *HybridSearchResult {
 	ftsOpts := SearchOptions{
-		Limit:     opts.Limit,
-		SourceIDs: opts.SourceIDs,
-		MimeTypes: opts.MimeTypes,
-		Highlight: true,
+		Limit:          opts.Limit + 1, // One extra to detect further pages
+		Offset:         opts.Offset,
+		SourceIDs:      opts.SourceIDs,
+		MimeTypes:      opts.MimeTypes,
+		PathPrefix:     opts.PathPrefix,
+		Languages:      opts.Languages,
+		ModifiedAfter:  opts.ModifiedAfter,
+		ModifiedBefore: opts.ModifiedBefore,
+		Highlight:      true,
+		ContextLen:     opts.SnippetLength / 2,
+		Alternatives:   opts.alternatives,
 	}
 
 	result, err := hs.fts.Search(ctx, query, ftsOpts)
 	if err != nil {
-		hs.logger.Error().Err(err).Msg("FTS5 search failed")
+		hs.loggerFor(ctx).Error().Err(err).Msg("FTS5 search failed")
 		return &HybridSearchResult{}
 	}
 
+	hits, hasMore := paginateHits(result.Results, 0, opts.Limit)
 	return &HybridSearchResult{
-		Results:   result.Results,
-		TotalHits: result.TotalHits,
-		FTSHits:   len(result.Results),
+		Results:        hits,
+		TotalHits:      result.TotalHits,
+		FTSHits:        len(hits),
+		HasMore:        hasMore,
+		StrategiesUsed: 1,
+		Confidence:     hs.calculateOverallConfidence(hits, agreementInfo{}, 1, false),
+		bm25Scores:     true,
 	}
 }
 
 // searchSemanticOnly performs semantic-only search.
 func (hs *HybridSearcher) searchSemanticOnly(ctx context.Context, query string, opts HybridSearchOptions) *HybridSearchResult {
 	if hs.semantic == nil {
-		hs.logger.Warn().Msg("semantic search requested but not available, falling back to FTS5")
+		hs.loggerFor(ctx).Warn().Msg("semantic search requested but not available, falling back to FTS5")
 		return hs.searchFTSOnly(ctx, query, opts)
 	}
 
 	semOpts := SemanticSearchOptions{
-		Limit:     opts.Limit,
-		SourceIDs: opts.SourceIDs,
-		MimeTypes: opts.MimeTypes,
+		Limit:          opts.Limit + 1, // One extra to detect further pages
+		Offset:         opts.Offset,
+		SourceIDs:      opts.SourceIDs,
+		MimeTypes:      opts.MimeTypes,
+		PathPrefix:     opts.PathPrefix,
+		Languages:      opts.Languages,
+		ModifiedAfter:  opts.ModifiedAfter,
+		ModifiedBefore: opts.ModifiedBefore,
+		ContextLen:     opts.SnippetLength,
 	}
 
 	result, err := hs.semantic.Search(ctx, query, semOpts)
 	if err != nil {
-		hs.logger.Error().Err(err).Msg("semantic search failed")
+		hs.loggerFor(ctx).Error().Err(err).Msg("semantic search failed")
 		return &HybridSearchResult{}
 	}
 
@@ -1090,14 +1502,27 @@ func (hs *HybridSearcher) searchSemanticOnly(ctx context.Context, query string,
 			Title:      hit.Title,
 			Snippet:    hit.Snippet,
 			Score:      hit.Score,
+			RawScore:   hit.Score,
 			Metadata:   hit.Metadata,
+			Content:    hit.Content,
 		})
 	}
 
+	// The vector store does not count matches, so only what has been paged
+	// through is known to exist.
+	hits, hasMore := paginateHits(hits, 0, opts.Limit)
+	total := opts.Offset + len(hits)
+	if hasMore {
+		total++
+	}
 	return &HybridSearchResult{
-		Results:      hits,
-		TotalHits:    result.TotalHits,
-		SemanticHits: len(hits),
+		Results:           hits,
+		TotalHits:         total,
+		TotalIsLowerBound: hasMore,
+		SemanticHits:      len(hits),
+		HasMore:           hasMore,
+		StrategiesUsed:    1,
+		Confidence:        hs.calculateOverallConfidence(hits, agreementInfo{}, 1, false),
 	}
 }
 
@@ -1118,18 +1543,20 @@ func (hs *HybridSearcher) SearchWithFallback(ctx context.Context, query string,
 		return nil, err
 	}
 
-	if len(result.Results) > 0 {
-		result.FallbackLevel = 0
+	// An empty later page means the results are exhausted; falling back
+	// would start returning first-page matches again.
+	if len(result.Results) > 0 || opts.Offset > 0 {
+		result.FallbackLevel = FallbackPrimary
 		return result, nil
 	}
 
-	hs.logger.Debug().Str("query", query).Msg("primary search returned no results, trying relaxed search")
+	hs.loggerFor(ctx).Debug().Str("query", query).Msg("primary search returned no results, trying relaxed search")
 
 	// Phase 2: Relaxed search (lower thresholds, broader matching)
 	relaxedOpts := opts
-	relaxedOpts.SimilarityFloor = 0.0001 // Very low floor
-	relaxedOpts.EnableMMR = false        // Don't filter for diversity
-	relaxedOpts.Limit = opts.Limit * 2   // Get more candidates
+	relaxedOpts.SimilarityFloor = 0.0001   // Very low floor
+	relaxedOpts.EnableMMR = BoolPtr(false) // Don't filter for diversity
+	relaxedOpts.Limit = opts.Limit * 2     // Get more candidates
 
 	relaxedResult := hs.searchRelaxed(ctx, query, relaxedOpts)
 	if len(relaxedResult.Results) > 0 {
@@ -1138,27 +1565,37 @@ func (hs *HybridSearcher) SearchWithFallback(ctx context.Context, query string,
 			relaxedResult.Results = relaxedResult.Results[:opts.Limit]
 		}
 		relaxedResult.TotalHits = len(relaxedResult.Results)
-		relaxedResult.FallbackLevel = 1
+		relaxedResult.FallbackLevel = FallbackRelaxed
 		relaxedResult.Confidence = "low"
 		relaxedResult.Note = "Using relaxed matching - verify relevance"
 		relaxedResult.SearchTime = float64(time.Since(start).Milliseconds())
 		return relaxedResult, nil
 	}
 
-	hs.logger.Debug().Str("query", query).Msg("relaxed search returned no results, trying partial match")
+	hs.loggerFor(ctx).Debug().Str("query", query).Msg("relaxed search returned no results, trying spelling correction")
+
+	// Phase 3: Spelling correction (edit distance against indexed terms)
+	if spellingResult := hs.searchCorrected(ctx, query, opts); spellingResult != nil {
+		spellingResult.FallbackLevel = FallbackSpelling
+		spellingResult.Confidence = "low"
+		spellingResult.SearchTime = float64(time.Since(start).Milliseconds())
+		return spellingResult, nil
+	}
+
+	hs.loggerFor(ctx).Debug().Str("query", query).Msg("spelling correction found no results, trying partial match")
 
-	// Phase 3: Partial word matching (split query into individual words)
+	// Phase 4: Partial word matching (split query into individual words)
 	partialResult := hs.searchPartial(ctx, query, opts)
 	if len(partialResult.Results) > 0 {
-		partialResult.FallbackLevel = 2
+		partialResult.FallbackLevel = FallbackPartial
 		partialResult.Confidence = "speculative"
 		partialResult.Note = "Partial word matching - results may not fully match query"
 		partialResult.SearchTime = float64(time.Since(start).Milliseconds())
 		return partialResult, nil
 	}
 
-	// Phase 4: No results found - return empty with suggestions
-	hs.logger.Info().Str("query", query).Msg("no results found after all fallback attempts")
+	// Phase 5: No results found - return empty with suggestions
+	hs.loggerFor(ctx).Info().Str("query", query).Msg("no results found after all fallback attempts")
 
 	return &HybridSearchResult{
 		Results:       []SearchHit{},
@@ -1166,12 +1603,49 @@ func (hs *HybridSearcher) SearchWithFallback(ctx context.Context, query string,
 		Query:         query,
 		SearchTime:    float64(time.Since(start).Milliseconds()),
 		Mode:          HybridModeFusion,
-		FallbackLevel: 3,
+		FallbackLevel: FallbackNone,
 		Confidence:    "none",
 		Note:          "No matching documents found. Try different search terms or verify documents are indexed.",
 	}, nil
 }
 
+// searchCorrected searches again with misspelled query terms replaced by the
+// closest indexed words, first as a normal search and then relaxed. It
+// returns nil when no term needed correcting or nothing was found.
+func (hs *HybridSearcher) searchCorrected(ctx context.Context, query string, opts HybridSearchOptions) *HybridSearchResult {
+	corrected, corrections, err := hs.fts.correctSpelling(ctx, query)
+	if err != nil {
+		hs.loggerFor(ctx).Warn().Err(err).Str("query", query).Msg("spelling correction failed")
+		return nil
+	}
+	if len(corrections) == 0 {
+		return nil
+	}
+
+	result, err := hs.Search(ctx, corrected, opts)
+	if err != nil || len(result.Results) == 0 {
+		relaxedOpts := opts
+		relaxedOpts.Limit = opts.Limit * 2
+		result = hs.searchRelaxed(ctx, corrected, relaxedOpts)
+		if len(result.Results) > opts.Limit {
+			result.Results = result.Results[:opts.Limit]
+		}
+		result.TotalHits = len(result.Results)
+	}
+	if len(result.Results) == 0 {
+		return nil
+	}
+
+	changes := make([]string, len(corrections))
+	for i, c := range corrections {
+		changes[i] = fmt.Sprintf("%q → %q", c.Term, c.Correction)
+	}
+	result.Query = query
+	result.SpellingCorrections = corrections
+	result.Note = "Spelling corrected: " + strings.Join(changes, ", ") + " - verify relevance"
+	return result
+}
+
 // searchRelaxed performs a relaxed FTS5 search with wildcards and stemming.
 func (hs *HybridSearcher) searchRelaxed(ctx context.Context, query string, opts HybridSearchOptions) *HybridSearchResult {
 	// Modify query for relaxed matching
@@ -1193,15 +1667,20 @@ func (hs *HybridSearcher) searchRelaxed(ctx context.Context, query string, opts
 	relaxedQuery := strings.Join(relaxedTerms, " OR ")
 
 	ftsOpts := SearchOptions{
-		Limit:     opts.Limit,
-		SourceIDs: opts.SourceIDs,
-		MimeTypes: opts.MimeTypes,
-		Highlight: true,
+		Limit:          opts.Limit,
+		SourceIDs:      opts.SourceIDs,
+		MimeTypes:      opts.MimeTypes,
+		PathPrefix:     opts.PathPrefix,
+		Languages:      opts.Languages,
+		ModifiedAfter:  opts.ModifiedAfter,
+		ModifiedBefore: opts.ModifiedBefore,
+		Highlight:      true,
+		ContextLen:     opts.SnippetLength / 2,
 	}
 
 	result, err := hs.fts.Search(ctx, relaxedQuery, ftsOpts)
 	if err != nil {
-		hs.logger.Warn().Err(err).Str("query", relaxedQuery).Msg("relaxed FTS5 search failed")
+		hs.loggerFor(ctx).Warn().Err(err).Str("query", relaxedQuery).Msg("relaxed FTS5 search failed")
 		return &HybridSearchResult{}
 	}
 
@@ -1228,10 +1707,15 @@ func (hs *HybridSearcher) searchPartial(ctx context.Context, query string, opts
 		}
 
 		ftsOpts := SearchOptions{
-			Limit:     5, // Small limit per word
-			SourceIDs: opts.SourceIDs,
-			MimeTypes: opts.MimeTypes,
-			Highlight: true,
+			Limit:          5, // Small limit per word
+			SourceIDs:      opts.SourceIDs,
+			MimeTypes:      opts.MimeTypes,
+			PathPrefix:     opts.PathPrefix,
+			Languages:      opts.Languages,
+			ModifiedAfter:  opts.ModifiedAfter,
+			ModifiedBefore: opts.ModifiedBefore,
+			Highlight:      true,
+			ContextLen:     opts.SnippetLength / 2,
 		}
 
 		result, err := hs.fts.Search(ctx, clean, ftsOpts)
@@ -1248,9 +1732,7 @@ func (hs *HybridSearcher) searchPartial(ctx context.Context, query string, opts
 	}
 
 	// Sort by score and limit
-	sort.Slice(allHits, func(i, j int) bool {
-		return allHits[i].Score > allHits[j].Score
-	})
+	sortHitsByScore(allHits)
 
 	if len(allHits) > opts.Limit {
 		allHits = allHits[:opts.Limit]
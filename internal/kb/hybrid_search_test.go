@@ -0,0 +1,293 @@
+package kb
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHybridSearcher_RawScorePreservedThroughBoosts(t *testing.T) {
+	hs := NewHybridSearcher(nil, nil)
+
+	ftsHits := []SearchHit{
+		{ChunkID: "c1", DocumentID: "d1", Snippet: "Oak Ridge laboratory report"},
+		{ChunkID: "c2", DocumentID: "d2", Snippet: "unrelated content"},
+	}
+	semanticHits := []SearchHit{
+		{ChunkID: "c1", DocumentID: "d1", Snippet: "Oak Ridge laboratory report", Score: 0.9},
+	}
+
+	fused, info := hs.applyRRFWithAgreement(ftsHits, semanticHits, 60, StrategyWeights{Semantic: 0.5, Lexical: 0.5})
+	rrf := make(map[string]float64)
+	for _, hit := range fused {
+		if hit.RawScore != hit.Score {
+			t.Errorf("chunk %s: expected raw score %f to equal RRF score %f", hit.ChunkID, hit.RawScore, hit.Score)
+		}
+		rrf[hit.ChunkID] = hit.RawScore
+	}
+
+	fused = hs.boostExactMatches(fused, []string{"Oak Ridge"})
+	fused = hs.applyAgreementBoost(fused, info, QueryTypeEntity)
+	fused = hs.applyReranking(fused, "Oak Ridge", 10, semanticHits)
+
+	for _, hit := range fused {
+		if hit.RawScore != rrf[hit.ChunkID] {
+			t.Errorf("chunk %s: raw score changed from %f to %f", hit.ChunkID, rrf[hit.ChunkID], hit.RawScore)
+		}
+	}
+	if fused[0].ChunkID != "c1" || fused[0].Score <= fused[0].RawScore {
+		t.Errorf("expected boosted score above raw score for top hit, got score=%f raw=%f", fused[0].Score, fused[0].RawScore)
+	}
+}
+
+func TestHybridSearcher_ScoreExplanation(t *testing.T) {
+	hs := NewHybridSearcher(nil, nil)
+
+	ftsHits := []SearchHit{
+		{ChunkID: "c1", DocumentID: "d1", Snippet: "Oak Ridge laboratory report"},
+		{ChunkID: "c2", DocumentID: "d2", Snippet: "unrelated content"},
+	}
+	semanticHits := []SearchHit{
+		{ChunkID: "c2", DocumentID: "d2", Snippet: "unrelated content", Score: 0.4},
+		{ChunkID: "c1", DocumentID: "d1", Snippet: "Oak Ridge laboratory report", Score: 0.9},
+	}
+
+	fused, info := hs.applyRRFWithAgreement(ftsHits, semanticHits, 60, StrategyWeights{Semantic: 0.5, Lexical: 0.5})
+	fused = hs.boostExactMatches(fused, []string{"Oak Ridge"})
+	fused = hs.applyAgreementBoost(fused, info, QueryTypeEntity)
+	fused = hs.applyReranking(fused, "Oak Ridge", 10, semanticHits)
+	finalizeExplanations(fused, true)
+
+	for i, hit := range fused {
+		e := hit.Explanation
+		if e == nil {
+			t.Fatalf("chunk %s: missing explanation", hit.ChunkID)
+		}
+		if e.Rank != i+1 {
+			t.Errorf("chunk %s: expected rank %d, got %d", hit.ChunkID, i+1, e.Rank)
+		}
+		product := (e.LexicalRRF + e.SemanticRRF) * e.EntityBoost * e.AgreementBoost * e.RerankMultiplier
+		if math.Abs(product-hit.Score) > 1e-12 {
+			t.Errorf("chunk %s: components multiply to %f, score is %f", hit.ChunkID, product, hit.Score)
+		}
+	}
+
+	top := fused[0].Explanation
+	if top.ChunkID != "c1" || top.LexicalRank != 1 || top.SemanticRank != 2 {
+		t.Errorf("unexpected top explanation: %+v", top)
+	}
+	if len(top.MatchedEntities) != 1 || top.EntityBoost != 1.5 {
+		t.Errorf("expected Oak Ridge entity boost, got %+v", top)
+	}
+
+	finalizeExplanations(fused, false)
+	for _, hit := range fused {
+		if hit.Explanation != nil {
+			t.Errorf("chunk %s: explanation should be stripped when explain is off", hit.ChunkID)
+		}
+	}
+}
+
+func TestHybridSearcher_PaginationIsStable(t *testing.T) {
+	// Tied scores arrive in different orders; the chunk ID tiebreaker must
+	// give the same ranking every time.
+	orders := [][]string{
+		{"c5", "c3", "c1", "c4", "c2", "c6", "c7"},
+		{"c7", "c6", "c2", "c4", "c1", "c3", "c5"},
+	}
+
+	var rankings [][]string
+	for _, order := range orders {
+		hits := make([]SearchHit, len(order))
+		for i, id := range order {
+			score := 0.5
+			if id == "c7" {
+				score = 0.9
+			}
+			hits[i] = SearchHit{ChunkID: id, Score: score}
+		}
+		sortHitsByScore(hits)
+
+		var ranking []string
+		seen := make(map[string]bool)
+		for offset := 0; ; offset += 3 {
+			page, hasMore := paginateHits(hits, offset, 3)
+			for _, hit := range page {
+				if seen[hit.ChunkID] {
+					t.Fatalf("chunk %s returned on more than one page", hit.ChunkID)
+				}
+				seen[hit.ChunkID] = true
+				ranking = append(ranking, hit.ChunkID)
+			}
+			if !hasMore {
+				break
+			}
+		}
+		if len(ranking) != len(order) {
+			t.Fatalf("pages returned %d hits, want %d", len(ranking), len(order))
+		}
+		rankings = append(rankings, ranking)
+	}
+
+	want := []string{"c7", "c1", "c2", "c3", "c4", "c5", "c6"}
+	for _, ranking := range rankings {
+		for i := range want {
+			if ranking[i] != want[i] {
+				t.Fatalf("ranking = %v, want %v", ranking, want)
+			}
+		}
+	}
+
+	if page, hasMore := paginateHits(make([]SearchHit, 3), 5, 3); len(page) != 0 || hasMore {
+		t.Errorf("offset past the end: got %d hits, hasMore=%v", len(page), hasMore)
+	}
+}
+
+func TestFusionCandidateLimit(t *testing.T) {
+	tests := []struct {
+		offset, limit int
+		want          int
+	}{
+		{0, 5, 30},
+		{0, 20, 60},
+		{20, 5, 30},
+		{25, 5, 60}, // The page plus one look-ahead no longer fits in 30
+		{40, 20, 120},
+		{1000, 10, 1020},
+	}
+	for _, tt := range tests {
+		got := fusionCandidateLimit(tt.offset, tt.limit)
+		if got != tt.want {
+			t.Errorf("fusionCandidateLimit(%d, %d) = %d, want %d", tt.offset, tt.limit, got, tt.want)
+		}
+		if got <= tt.offset+tt.limit {
+			t.Errorf("fusionCandidateLimit(%d, %d) = %d does not cover the page", tt.offset, tt.limit, got)
+		}
+	}
+}
+
+func TestHybridSearchOptions_MMRAndRerankToggles(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       HybridSearchOptions
+		wantMMR    bool
+		wantRerank bool
+	}{
+		{"balanced preset", HybridSearchOptions{}, true, true},
+		{"high recall preset", HybridSearchOptions{RecallMode: RecallModeHigh}, false, true},
+		{"MMR disabled", HybridSearchOptions{EnableMMR: BoolPtr(false)}, false, true},
+		{"rerank disabled", HybridSearchOptions{EnableRerank: BoolPtr(false)}, true, false},
+		{"both disabled on precise", HybridSearchOptions{RecallMode: RecallModePrecise, EnableMMR: BoolPtr(false), EnableRerank: BoolPtr(false)}, false, false},
+		{"MMR forced on for high recall", HybridSearchOptions{RecallMode: RecallModeHigh, EnableMMR: BoolPtr(true)}, true, true},
+	}
+
+	for _, tt := range tests {
+		opts := tt.opts.withDefaults()
+		if opts.mmrEnabled() != tt.wantMMR {
+			t.Errorf("%s: MMR enabled = %v, want %v", tt.name, opts.mmrEnabled(), tt.wantMMR)
+		}
+		if opts.rerankEnabled() != tt.wantRerank {
+			t.Errorf("%s: rerank enabled = %v, want %v", tt.name, opts.rerankEnabled(), tt.wantRerank)
+		}
+	}
+}
+
+func TestHybridSearcherConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HybridSearcherConfig
+		wantErr bool
+	}{
+		{"defaults", HybridSearcherConfig{}, false},
+		{"tuned", HybridSearcherConfig{RRFConstant: 20, SemanticWeight: 0.6, Weights: map[QueryType]StrategyWeights{
+			QueryTypeEntity: {Semantic: 0.2, Lexical: 0.8},
+		}}, false},
+		{"negative k", HybridSearcherConfig{RRFConstant: -1}, true},
+		{"semantic weight above 1", HybridSearcherConfig{SemanticWeight: 1.5}, true},
+		{"weight out of range", HybridSearcherConfig{Weights: map[QueryType]StrategyWeights{
+			QueryTypeFactual: {Semantic: 0.5, Lexical: 2},
+		}}, true},
+		{"both weights zero", HybridSearcherConfig{Weights: map[QueryType]StrategyWeights{
+			QueryTypeFactual: {},
+		}}, true},
+		{"unknown query type", HybridSearcherConfig{Weights: map[QueryType]StrategyWeights{
+			"keyword": {Semantic: 0.5, Lexical: 0.5},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		_, err := NewHybridSearcherWithConfig(nil, nil, tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestHybridSearcher_ConfiguredWeights(t *testing.T) {
+	hs := NewHybridSearcher(nil, nil)
+	if hs.rrfConstant != DefaultRRFConstant || hs.getWeightsForQueryType(QueryTypeConceptual) != strategyWeightMatrix[QueryTypeConceptual] {
+		t.Errorf("expected the default ranking, got k=%d and %+v", hs.rrfConstant, hs.getWeightsForQueryType(QueryTypeConceptual))
+	}
+
+	hs, err := NewHybridSearcherWithConfig(nil, nil, HybridSearcherConfig{
+		RRFConstant:    20,
+		SemanticWeight: 0.6,
+		Weights: map[QueryType]StrategyWeights{
+			QueryTypeExactQuote: {Semantic: 0, Lexical: 1},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hs.rrfConstant != 20 {
+		t.Errorf("expected k=20, got %d", hs.rrfConstant)
+	}
+	if w := hs.getWeightsForQueryType(QueryTypeExactQuote); w != (StrategyWeights{Semantic: 0, Lexical: 1}) {
+		t.Errorf("expected the exact_quote override, got %+v", w)
+	}
+	if w := hs.getWeightsForQueryType(QueryTypeEntity); math.Abs(w.Semantic-0.6) > 1e-9 || math.Abs(w.Lexical-0.4) > 1e-9 {
+		t.Errorf("expected the semantic weight for other types, got %+v", w)
+	}
+}
+
+func TestStartSemanticLeg_Timeout(t *testing.T) {
+	// A search that ignores cancellation must not hold up the caller
+	release := make(chan struct{})
+	defer close(release)
+	wait := startSemanticLeg(context.Background(), 20*time.Millisecond, func(context.Context) ([]SearchHit, error) {
+		<-release
+		return []SearchHit{{ChunkID: "late"}}, nil
+	})
+
+	start := time.Now()
+	hits, timedOut, err := wait()
+	if !timedOut || err == nil {
+		t.Fatalf("expected timeout, got timedOut=%v err=%v", timedOut, err)
+	}
+	if len(hits) != 0 {
+		t.Errorf("expected no hits after timeout, got %d", len(hits))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("wait took %v, expected it to return at the timeout", elapsed)
+	}
+}
+
+func TestStartSemanticLeg_Completes(t *testing.T) {
+	wait := startSemanticLeg(context.Background(), time.Second, func(context.Context) ([]SearchHit, error) {
+		return []SearchHit{{ChunkID: "c1"}}, nil
+	})
+	hits, timedOut, err := wait()
+	if err != nil || timedOut || len(hits) != 1 {
+		t.Fatalf("got hits=%v timedOut=%v err=%v", hits, timedOut, err)
+	}
+
+	failure := errors.New("embedding model not loaded")
+	wait = startSemanticLeg(context.Background(), time.Second, func(context.Context) ([]SearchHit, error) {
+		return nil, failure
+	})
+	if _, timedOut, err := wait(); timedOut || !errors.Is(err, failure) {
+		t.Fatalf("expected plain failure, got timedOut=%v err=%v", timedOut, err)
+	}
+}
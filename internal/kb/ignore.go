@@ -0,0 +1,182 @@
+package kb
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFile is the name of the per-source ignore file. It lives in the source
+// root and uses gitignore syntax.
+const IgnoreFile = ".conduitignore"
+
+// ignoreRule is one compiled gitignore-style pattern.
+type ignoreRule struct {
+	pattern string
+	re      *regexp.Regexp
+	negate  bool // "!pattern" re-includes a previously excluded path
+	dirOnly bool // "pattern/" only matches directories
+}
+
+// IgnoreMatcher decides whether paths inside a source are excluded, using
+// gitignore semantics:
+//
+//   - a pattern without a slash matches a file or directory name at any depth
+//   - a pattern with a leading or inner slash is anchored to the source root
+//   - a trailing slash matches directories only
+//   - "*" and "?" do not cross "/", while "**" matches any number of directories
+//   - "!" negates a pattern; the last matching pattern wins
+//
+// As in git, a file cannot be re-included if one of its parent directories is
+// excluded, because the walker never descends into that directory.
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+// NewIgnoreMatcher compiles patterns in order. Blank lines and lines starting
+// with "#" are ignored so the contents of an ignore file can be passed as-is.
+func NewIgnoreMatcher(patterns []string) *IgnoreMatcher {
+	m := &IgnoreMatcher{}
+	m.Add(patterns...)
+	return m
+}
+
+// Add appends patterns; later patterns take precedence over earlier ones.
+func (m *IgnoreMatcher) Add(patterns ...string) {
+	for _, p := range patterns {
+		if rule, ok := compileIgnoreRule(p); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+// AddFile appends the patterns in an ignore file. A missing file is not an error.
+func (m *IgnoreMatcher) AddFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	m.Add(patterns...)
+	return nil
+}
+
+// Match reports whether relPath (relative to the source root) is excluded.
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) bool {
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "./")
+
+	excluded := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(relPath) {
+			excluded = !rule.negate
+		}
+	}
+	return excluded
+}
+
+// compileIgnoreRule converts one gitignore line into a rule.
+func compileIgnoreRule(line string) (ignoreRule, bool) {
+	p := strings.TrimRight(line, " \t\r")
+	if p == "" || strings.HasPrefix(p, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{pattern: p}
+	if strings.HasPrefix(p, "!") {
+		rule.negate = true
+		p = p[1:]
+	} else if strings.HasPrefix(p, `\!`) || strings.HasPrefix(p, `\#`) {
+		p = p[1:]
+	}
+	if strings.HasSuffix(p, "/") {
+		rule.dirOnly = true
+		p = strings.TrimRight(p, "/")
+	}
+	if p == "" {
+		return ignoreRule{}, false
+	}
+
+	// Patterns containing a slash (other than a trailing one) are anchored
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored && !strings.HasPrefix(p, "**/") {
+		sb.WriteString("(?:.*/)?")
+	}
+	sb.WriteString(globToRegexp(p))
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp translates a gitignore glob into a regular expression body.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				atStart := i == 0 || glob[i-1] == '/'
+				atEnd := i+2 == len(glob)
+				if atStart && atEnd {
+					sb.WriteString(".*") // "**" or "dir/**"
+					i++
+					continue
+				}
+				if atStart && glob[i+2] == '/' {
+					sb.WriteString("(?:.*/)?") // "**/" matches zero or more directories
+					i += 2
+					continue
+				}
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
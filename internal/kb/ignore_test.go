@@ -0,0 +1,138 @@
+package kb
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestIgnoreMatcher_Match(t *testing.T) {
+	m := NewIgnoreMatcher([]string{
+		"# comment",
+		"",
+		"node_modules",
+		"*.log",
+		"!keep.log",
+		"/build",
+		"docs/generated/",
+		"**/tmp/*.txt",
+		"assets/**",
+		"!assets/logo.svg",
+	})
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		// Unanchored names match at any depth
+		{"node_modules", true, true},
+		{"pkg/web/node_modules", true, true},
+		{"app.log", false, true},
+		{"logs/deep/app.log", false, true},
+		// Negation re-includes
+		{"keep.log", false, false},
+		{"logs/keep.log", false, false},
+		// Leading slash anchors to the root
+		{"build", true, true},
+		{"src/build", true, false},
+		// Trailing slash matches directories only
+		{"docs/generated", true, true},
+		{"docs/generated", false, false},
+		// ** spans directories
+		{"tmp/a.txt", false, true},
+		{"a/b/tmp/c.txt", false, true},
+		{"a/b/tmp/c.md", false, false},
+		{"assets/img/x.png", false, true},
+		{"assets/logo.svg", false, false},
+		// Unmatched paths are kept
+		{"README.md", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestIgnoreMatcher_LastRuleWins(t *testing.T) {
+	m := NewIgnoreMatcher([]string{"*.md", "!README.md", "README.md"})
+	if !m.Match("README.md", false) {
+		t.Error("expected later pattern to exclude README.md again")
+	}
+}
+
+func TestSourceManager_CollectFiles(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"README.md",
+		"notes/a.md",
+		"notes/draft.md",
+		"notes/archive/old.md",
+		"notes/archive/important.md",
+		"node_modules/pkg/index.md",
+		"vendor/lib/doc.md",
+		"src/node_modules/dep/readme.md",
+		"build/out.md",
+		"src/build/keep.md",
+		"debug.log",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The ignore file is applied after the configured excludes, so it can
+	// re-include a default exclude and add its own rules.
+	ignore := "# local rules\n/build/\ndraft.md\n!vendor\n!src/build\n"
+	if err := os.WriteFile(filepath.Join(root, IgnoreFile), []byte(ignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &Source{
+		Path:     root,
+		Patterns: []string{"*.md", "*.log"},
+		Excludes: append(append([]string{}, DefaultExcludes...), "*.log", "archive/", "!archive/important.md"),
+	}
+
+	sm := &SourceManager{}
+	got, errs, err := sm.collectFiles(source)
+	if err != nil {
+		t.Fatalf("collectFiles: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Fatalf("unexpected sync errors: %v", errs)
+	}
+
+	var rel []string
+	for _, path := range got {
+		r, _ := filepath.Rel(root, path)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	sort.Strings(rel)
+
+	// notes/archive/important.md stays excluded: its parent directory is
+	// excluded, so it cannot be re-included (same as git).
+	want := []string{
+		"README.md",
+		"notes/a.md",
+		"src/build/keep.md",
+		"vendor/lib/doc.md",
+	}
+	if len(rel) != len(want) {
+		t.Fatalf("expected %v, got %v", want, rel)
+	}
+	for i := range want {
+		if rel[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, rel)
+			break
+		}
+	}
+}
@@ -22,6 +22,10 @@ type Indexer struct {
 	logger           zerolog.Logger
 	semanticErrors   int // Counter for semantic indexing failures in current batch
 	extractionErrors int // Counter for KAG extraction failures in current batch
+
+	// Embedding throughput for the current batch
+	embeddedChunks int
+	embeddingTime  time.Duration
 }
 
 // NewIndexer creates a new indexer.
@@ -68,6 +72,18 @@ func (idx *Indexer) GetSemanticErrors() int {
 	return idx.semanticErrors
 }
 
+// ResetEmbeddingStats resets the embedding throughput counters.
+func (idx *Indexer) ResetEmbeddingStats() {
+	idx.embeddedChunks = 0
+	idx.embeddingTime = 0
+}
+
+// GetEmbeddingStats returns the number of chunks vectorized since the last
+// reset and the time spent doing so.
+func (idx *Indexer) GetEmbeddingStats() (int, time.Duration) {
+	return idx.embeddedChunks, idx.embeddingTime
+}
+
 // ResetExtractionErrors resets the KAG extraction error counter.
 func (idx *Indexer) ResetExtractionErrors() {
 	idx.extractionErrors = 0
@@ -157,7 +173,10 @@ func (idx *Indexer) Index(ctx context.Context, doc *Document, chunks []Chunk) er
 
 	// Index vectors if semantic search is enabled
 	if idx.semantic != nil {
-		if err := idx.semantic.IndexDocument(ctx, doc, chunksWithIDs); err != nil {
+		embedStart := time.Now()
+		err := idx.semantic.IndexDocument(ctx, doc, chunksWithIDs)
+		idx.embeddingTime += time.Since(embedStart)
+		if err != nil {
 			// Log warning but don't fail - FTS indexing succeeded
 			idx.logger.Warn().
 				Err(err).
@@ -165,6 +184,11 @@ func (idx *Indexer) Index(ctx context.Context, doc *Document, chunks []Chunk) er
 				Msg("vector indexing failed, falling back to FTS only")
 			idx.semanticErrors++ // Track for reporting
 		} else {
+			idx.embeddedChunks += len(chunksWithIDs)
+			// Migration skips documents whose vectors are already stored
+			if err := markVectorsIndexed(ctx, idx.db, doc.DocumentID); err != nil {
+				idx.logger.Warn().Err(err).Str("document_id", doc.DocumentID).Msg("failed to record vector indexing")
+			}
 			idx.logger.Debug().
 				Str("document_id", doc.DocumentID).
 				Int("vectors", len(chunksWithIDs)).
@@ -172,8 +196,8 @@ func (idx *Indexer) Index(ctx context.Context, doc *Document, chunks []Chunk) er
 		}
 	}
 
-	// Queue entity extraction if KAG is enabled
-	if idx.HasEntityExtraction() {
+	// Queue entity extraction if KAG is enabled for this document's source
+	if idx.HasEntityExtraction() && idx.sourceExtractsEntities(ctx, doc.SourceID) {
 		for _, chunk := range chunksWithIDs {
 			// Queue chunk for background extraction (non-blocking)
 			if idx.kagConfig.Extraction.EnableBackground {
@@ -270,6 +294,19 @@ func (idx *Indexer) DeleteBySource(ctx context.Context, sourceID string) (int, e
 	return deleted, nil
 }
 
+// sourceExtractsEntities reports whether KAG extraction is enabled for a
+// source. Unknown sources default to enabled.
+func (idx *Indexer) sourceExtractsEntities(ctx context.Context, sourceID string) bool {
+	var enabled bool
+	err := idx.db.QueryRowContext(ctx,
+		`SELECT extract_entities FROM kb_sources WHERE source_id = ?`, sourceID,
+	).Scan(&enabled)
+	if err != nil {
+		return true
+	}
+	return enabled
+}
+
 // deleteInTx deletes a document within a transaction.
 func (idx *Indexer) deleteInTx(ctx context.Context, tx *sql.Tx, documentID string) error {
 	// Delete from FTS first
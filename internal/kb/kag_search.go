@@ -5,6 +5,7 @@ package kb
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"sort"
@@ -506,13 +507,14 @@ func (s *KAGSearcher) formatContext(query string, entities []EntityResult, relat
 // GetEntityByName retrieves an entity by name.
 func (s *KAGSearcher) GetEntityByName(ctx context.Context, name string) (*EntityResult, error) {
 	var e EntityResult
+	// Exact (case-insensitive) matches win over partial ones
 	err := s.db.QueryRowContext(ctx, `
-		SELECT entity_id, name, type, description, confidence, source_document_id
+		SELECT entity_id, name, type, COALESCE(description, ''), confidence, COALESCE(source_document_id, '')
 		FROM kb_entities
-		WHERE name = ? OR name LIKE ?
-		ORDER BY confidence DESC
+		WHERE name = ? COLLATE NOCASE OR name LIKE ?
+		ORDER BY (name = ? COLLATE NOCASE) DESC, confidence DESC
 		LIMIT 1
-	`, name, "%"+name+"%").Scan(&e.ID, &e.Name, &e.Type, &e.Description, &e.Confidence, &e.SourceDocumentID)
+	`, name, "%"+name+"%", name).Scan(&e.ID, &e.Name, &e.Type, &e.Description, &e.Confidence, &e.SourceDocumentID)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -535,7 +537,7 @@ func (s *KAGSearcher) GetRelatedEntities(ctx context.Context, entityID string, m
 	// For now, single-hop relations from SQLite
 	// FalkorDB can be used for multi-hop if connected
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT DISTINCT e.entity_id, e.name, e.type, e.description, e.confidence, e.source_document_id
+		SELECT DISTINCT e.entity_id, e.name, e.type, COALESCE(e.description, ''), e.confidence, COALESCE(e.source_document_id, '')
 		FROM kb_entities e
 		JOIN kb_relations r ON (e.entity_id = r.object_id OR e.entity_id = r.subject_id)
 		WHERE (r.subject_id = ? OR r.object_id = ?) AND e.entity_id != ?
@@ -559,6 +561,320 @@ func (s *KAGSearcher) GetRelatedEntities(ctx context.Context, entityID string, m
 	return entities, rows.Err()
 }
 
+// maxEntityNameWords is the longest entity name, in words, ExpandQuery
+// looks for in a query.
+const maxEntityNameWords = 4
+
+// EntityExpansion lists the other names of an entity a query mentions.
+type EntityExpansion struct {
+	Mention string   `json:"mention"` // The query words naming the entity
+	Names   []string `json:"names"`   // Its name, aliases and similar_to entities
+}
+
+// ExpandQuery finds the entities a query names, by name or alias, and
+// returns their other names: the entity name and aliases, then the names of
+// entities linked to it by similar_to. Longer mentions and more confident
+// entities come first; at most maxTerms names are returned in total.
+func (s *KAGSearcher) ExpandQuery(ctx context.Context, query string, maxTerms int) ([]EntityExpansion, error) {
+	if maxTerms <= 0 {
+		return nil, nil
+	}
+
+	// Every run of up to maxEntityNameWords words may name an entity
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-.+#", r)
+	})
+	mentions := make(map[string]bool)
+	for i := range words {
+		for n := 1; n <= maxEntityNameWords && i+n <= len(words); n++ {
+			mention := strings.Trim(strings.Join(words[i:i+n], " "), ".")
+			if len(mention) >= 2 && !stopwords[mention] {
+				mentions[mention] = true
+			}
+		}
+	}
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, 0, len(mentions))
+	args := make([]interface{}, 0, 2*len(mentions))
+	for mention := range mentions {
+		placeholders = append(placeholders, "?")
+		args = append(args, mention)
+	}
+	in := strings.Join(placeholders, ",")
+	args = append(args, args...)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT entity_id, name, COALESCE(metadata, '')
+		FROM kb_entities
+		WHERE lower(name) IN (`+in+`)
+		   OR EXISTS (
+			SELECT 1 FROM json_each(CASE WHEN json_valid(metadata) THEN metadata ELSE '{}' END, '$.aliases')
+			WHERE lower(value) IN (`+in+`)
+		   )
+		ORDER BY confidence DESC
+		LIMIT 20
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("find query entities: %w", err)
+	}
+	type match struct {
+		id, mention string
+		names       []string
+	}
+	var matches []match
+	for rows.Next() {
+		var id, name, metadataJSON string
+		if err := rows.Scan(&id, &name, &metadataJSON); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("find query entities: %w", err)
+		}
+		metadata := make(map[string]interface{})
+		_ = json.Unmarshal([]byte(metadataJSON), &metadata)
+
+		// The mention is the name or alias the query used
+		m := match{id: id}
+		for _, n := range append([]string{name}, entityAliases(metadata)...) {
+			if m.mention == "" && mentions[strings.ToLower(n)] {
+				m.mention = strings.ToLower(n)
+			} else {
+				m.names = append(m.names, n)
+			}
+		}
+		matches = append(matches, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("find query entities: %w", err)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(matches[i].mention) > len(matches[j].mention)
+	})
+
+	// Names already in the query add nothing
+	seen := make(map[string]bool, len(mentions))
+	for mention := range mentions {
+		seen[mention] = true
+	}
+	var expansions []EntityExpansion
+	byMention := make(map[string]int)
+	total := 0
+	add := func(mention, name string) {
+		if total >= maxTerms || seen[strings.ToLower(name)] {
+			return
+		}
+		seen[strings.ToLower(name)] = true
+		i, ok := byMention[mention]
+		if !ok {
+			i = len(expansions)
+			byMention[mention] = i
+			expansions = append(expansions, EntityExpansion{Mention: mention})
+		}
+		expansions[i].Names = append(expansions[i].Names, name)
+		total++
+	}
+	for _, m := range matches {
+		for _, name := range m.names {
+			add(m.mention, name)
+		}
+	}
+	for _, m := range matches {
+		if total >= maxTerms {
+			break
+		}
+		similar, err := s.similarEntityNames(ctx, m.id, maxTerms-total)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range similar {
+			add(m.mention, name)
+		}
+	}
+	return expansions, nil
+}
+
+// similarEntityNames returns the names of up to limit entities linked to an
+// entity by similar_to, most confident first.
+func (s *KAGSearcher) similarEntityNames(ctx context.Context, entityID string, limit int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.name
+		FROM kb_relations r
+		JOIN kb_entities e ON e.entity_id = CASE WHEN r.subject_id = ? THEN r.object_id ELSE r.subject_id END
+		WHERE r.predicate = ? AND (r.subject_id = ? OR r.object_id = ?) AND e.entity_id != ?
+		ORDER BY r.confidence DESC
+		LIMIT ?
+	`, entityID, string(RelationSimilarTo), entityID, entityID, entityID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("find similar entities: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("find similar entities: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// EntityListOptions filters and pages an entity listing.
+type EntityListOptions struct {
+	Type   string // Only entities of this type
+	Search string // Substring match on the entity name
+	Limit  int    // Page size (default: 50)
+	Offset int    // Entities to skip
+}
+
+// EntitySummary is an entity in a listing, with its number of relations.
+type EntitySummary struct {
+	EntityResult
+	RelationCount int `json:"relation_count"`
+}
+
+// EntityListResult is a page of entities plus counts for the whole filter.
+type EntityListResult struct {
+	Entities []EntitySummary `json:"entities"`
+	Total    int             `json:"total"`
+
+	// TypeCounts counts matching entities by type (ignores the Type filter,
+	// so it shows which types a search term spans)
+	TypeCounts map[string]int `json:"type_counts"`
+}
+
+// ListEntities lists extracted entities, most connected first.
+func (s *KAGSearcher) ListEntities(ctx context.Context, opts EntityListOptions) (*EntityListResult, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = 50
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+
+	var nameCond string
+	var nameArgs []interface{}
+	if opts.Search != "" {
+		nameCond = " AND e.name LIKE ?"
+		nameArgs = append(nameArgs, "%"+opts.Search+"%")
+	}
+	where := "WHERE 1=1" + nameCond
+	args := nameArgs
+	if opts.Type != "" {
+		where += " AND e.type = ?"
+		args = append(append([]interface{}{}, nameArgs...), opts.Type)
+	}
+
+	result := &EntityListResult{TypeCounts: make(map[string]int)}
+
+	typeRows, err := s.db.QueryContext(ctx,
+		"SELECT e.type, COUNT(*) FROM kb_entities e WHERE 1=1"+nameCond+" GROUP BY e.type", nameArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("count entity types: %w", err)
+	}
+	for typeRows.Next() {
+		var t string
+		var c int
+		if err := typeRows.Scan(&t, &c); err != nil {
+			continue
+		}
+		result.TypeCounts[t] = c
+	}
+	typeRows.Close()
+
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM kb_entities e "+where, args...).Scan(&result.Total); err != nil {
+		return nil, fmt.Errorf("count entities: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.entity_id, e.name, e.type, COALESCE(e.description, ''), e.confidence,
+		       COALESCE(e.source_document_id, ''), COALESCE(d.title, ''),
+		       (SELECT COUNT(*) FROM kb_relations r WHERE r.subject_id = e.entity_id OR r.object_id = e.entity_id)
+		FROM kb_entities e
+		LEFT JOIN kb_documents d ON e.source_document_id = d.document_id
+		`+where+`
+		ORDER BY 8 DESC, e.name ASC, e.entity_id ASC
+		LIMIT ? OFFSET ?
+	`, append(args, opts.Limit, opts.Offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("list entities: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e EntitySummary
+		if err := rows.Scan(&e.ID, &e.Name, &e.Type, &e.Description, &e.Confidence,
+			&e.SourceDocumentID, &e.SourceDocTitle, &e.RelationCount); err != nil {
+			continue
+		}
+		result.Entities = append(result.Entities, e)
+	}
+
+	return result, rows.Err()
+}
+
+// EntityDocument is a document an entity was extracted from.
+type EntityDocument struct {
+	DocumentID string `json:"document_id"`
+	Path       string `json:"path"`
+	Title      string `json:"title"`
+}
+
+// EntityDetail describes one entity with its relations and source documents.
+type EntityDetail struct {
+	Entity    EntityResult     `json:"entity"`
+	Relations []RelationResult `json:"relations"`
+	Related   []EntityResult   `json:"related"`
+	Documents []EntityDocument `json:"documents"`
+}
+
+// GetEntityDetail looks up an entity by name and returns its relations,
+// directly related entities and the documents it appears in. It returns nil
+// if no entity matches.
+func (s *KAGSearcher) GetEntityDetail(ctx context.Context, name string) (*EntityDetail, error) {
+	entity, err := s.GetEntityByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("find entity: %w", err)
+	}
+	if entity == nil {
+		return nil, nil
+	}
+
+	detail := &EntityDetail{Entity: *entity}
+
+	if detail.Relations, err = s.getRelations(ctx, []EntityResult{*entity}, 1); err != nil {
+		return nil, fmt.Errorf("get relations: %w", err)
+	}
+	if detail.Related, err = s.GetRelatedEntities(ctx, entity.ID, 1); err != nil {
+		return nil, fmt.Errorf("get related entities: %w", err)
+	}
+
+	// The same name may have been extracted from several documents
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT d.document_id, d.path, COALESCE(d.title, '')
+		FROM kb_entities e
+		JOIN kb_documents d ON e.source_document_id = d.document_id
+		WHERE e.name = ? COLLATE NOCASE
+		ORDER BY d.path
+	`, entity.Name)
+	if err != nil {
+		return nil, fmt.Errorf("get documents: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var doc EntityDocument
+		if err := rows.Scan(&doc.DocumentID, &doc.Path, &doc.Title); err != nil {
+			continue
+		}
+		detail.Documents = append(detail.Documents, doc)
+	}
+
+	return detail, rows.Err()
+}
+
 // GetStats returns KAG statistics.
 func (s *KAGSearcher) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
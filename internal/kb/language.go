@@ -0,0 +1,134 @@
+package kb
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// languageByExt maps source file extensions to the language names recorded
+// in the "language" chunk metadata.
+var languageByExt = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".pyi":   "python",
+	".js":    "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".jsx":   "javascript",
+	".ts":    "typescript",
+	".mts":   "typescript",
+	".tsx":   "typescript",
+	".java":  "java",
+	".rs":    "rust",
+	".rb":    "ruby",
+	".c":     "c",
+	".h":     "c",
+	".cpp":   "cpp",
+	".cc":    "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".swift": "swift",
+	".kt":    "kotlin",
+	".scala": "scala",
+	".php":   "php",
+	".sh":    "shell",
+	".bash":  "shell",
+	".zsh":   "shell",
+	".sql":   "sql",
+	".lua":   "lua",
+	".r":     "r",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".json":  "json",
+	".html":  "html",
+	".css":   "css",
+}
+
+// languageAliases maps the names fenced code blocks and filters commonly use
+// to the canonical language name.
+var languageAliases = map[string]string{
+	"golang":        "go",
+	"py":            "python",
+	"python3":       "python",
+	"js":            "javascript",
+	"node":          "javascript",
+	"jsx":           "javascript",
+	"ts":            "typescript",
+	"tsx":           "typescript",
+	"rs":            "rust",
+	"rb":            "ruby",
+	"c++":           "cpp",
+	"cxx":           "cpp",
+	"cs":            "csharp",
+	"c#":            "csharp",
+	"kt":            "kotlin",
+	"sh":            "shell",
+	"bash":          "shell",
+	"zsh":           "shell",
+	"console":       "shell",
+	"shell-session": "shell",
+	"yml":           "yaml",
+}
+
+// LanguageForPath returns the programming language of a file from its
+// extension, or "" when the extension is not a known language.
+func LanguageForPath(path string) string {
+	return languageByExt[strings.ToLower(filepath.Ext(path))]
+}
+
+// NormalizeLanguage returns the canonical name for a language name or alias,
+// so "py", "Python" and "python3" all become "python".
+func NormalizeLanguage(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := languageAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// ParseLanguages splits a comma-separated language filter into canonical
+// names, dropping empty entries.
+func ParseLanguages(list string) []string {
+	var languages []string
+	for _, name := range strings.Split(list, ",") {
+		if name = NormalizeLanguage(name); name != "" {
+			languages = append(languages, name)
+		}
+	}
+	return languages
+}
+
+// fenceInfoPattern matches the opening line of a fenced code block and
+// captures the first word of its info string ("```python title=x").
+var fenceInfoPattern = regexp.MustCompile("(?m)^ {0,3}(?:```+|~~~+)[ \t]*\\{?\\.?([A-Za-z0-9_+#-]+)")
+
+// fencedCodeLanguage returns the language of the first fenced code block in
+// content that names one, or "".
+func fencedCodeLanguage(content string) string {
+	if m := fenceInfoPattern.FindStringSubmatch(content); m != nil {
+		return NormalizeLanguage(m[1])
+	}
+	return ""
+}
+
+// tagChunkLanguages records each chunk's language in its "language"
+// metadata: the file's language for source files, otherwise the language of
+// the first fenced code block in a Markdown chunk.
+func tagChunkLanguages(chunks []Chunk, path string, contentType ContentType) {
+	fileLang := LanguageForPath(path)
+	for i := range chunks {
+		lang := fileLang
+		if contentType == ContentTypeMarkdown {
+			lang = fencedCodeLanguage(chunks[i].Content)
+		}
+		if lang == "" {
+			continue
+		}
+		if chunks[i].Metadata == nil {
+			chunks[i].Metadata = make(map[string]string)
+		}
+		chunks[i].Metadata["language"] = lang
+	}
+}
@@ -232,13 +232,14 @@ Section: %s
 4. Assign confidence scores (0.0-1.0) based on how clearly the entity/relation is stated
 5. Maximum %d entities, %d relations
 6. Minimum confidence threshold: %.2f
+7. List other names the text uses for an entity (abbreviations, acronyms, alternative spellings) as aliases
 </extraction_rules>
 
 <output_format>
 Respond ONLY with valid JSON in this exact format:
 {
   "entities": [
-    {"name": "entity name", "type": "concept|person|organization|technology|location|event|section", "description": "brief description", "confidence": 0.0-1.0}
+    {"name": "entity name", "type": "concept|person|organization|technology|location|event|section", "description": "brief description", "aliases": ["other name"], "confidence": 0.0-1.0}
   ],
   "relations": [
     {"subject": "entity1 name", "predicate": "mentions|defines|relates_to|contains|part_of|implements|depends_on|created_by|used_by|similar_to", "object": "entity2 name", "confidence": 0.0-1.0}
@@ -0,0 +1,300 @@
+package kb
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/simpleflo/conduit/internal/observability"
+)
+
+// Paths served by MCPHTTPServer.
+const (
+	MCPSSEPath      = "/sse"      // HTTP+SSE transport: event stream
+	MCPMessagesPath = "/messages" // HTTP+SSE transport: client messages
+	MCPPostPath     = "/mcp"      // Plain JSON request/response
+)
+
+const (
+	// maxMCPMessageBytes bounds a single JSON-RPC message posted by a client.
+	maxMCPMessageBytes = 4 << 20
+
+	// mcpSSEKeepAlive is how often an idle event stream gets a comment, so
+	// proxies do not drop the connection.
+	mcpSSEKeepAlive = 30 * time.Second
+
+	// mcpShutdownTimeout bounds how long in-flight requests get to finish.
+	mcpShutdownTimeout = 5 * time.Second
+)
+
+// MCPHTTPServer serves MCP requests over HTTP. It speaks the HTTP+SSE
+// transport, where a client holds an event stream open at /sse and posts
+// messages to the endpoint announced on it, and also answers single JSON
+// requests posted to /mcp. Requests are answered by the same MCPHandler the
+// stdio transport uses.
+//
+// Only loopback clients are served: requests whose Host or Origin names
+// another host are refused, so a web page cannot reach the server through
+// DNS rebinding or a cross-site request.
+type MCPHTTPServer struct {
+	handle MCPHandler
+	logger zerolog.Logger
+	token  string // When set, required as "Authorization: Bearer <token>"
+
+	mu       sync.Mutex
+	sessions map[string]*mcpSSESession
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// mcpSSESession is one open event stream; responses to messages posted for
+// the session are delivered on it.
+type mcpSSESession struct {
+	events chan []byte
+	done   chan struct{}
+}
+
+// NewMCPHTTPServer creates an HTTP transport answering requests with handle.
+func NewMCPHTTPServer(handle MCPHandler) *MCPHTTPServer {
+	return &MCPHTTPServer{
+		handle:   handle,
+		logger:   observability.Logger("kb.mcp.http"),
+		sessions: make(map[string]*mcpSSESession),
+		closed:   make(chan struct{}),
+	}
+}
+
+// RequireToken makes every request carry token as a bearer token.
+func (s *MCPHTTPServer) RequireToken(token string) {
+	s.token = token
+}
+
+// ValidateMCPHTTPAddr checks that addr is a loopback address; the HTTP
+// transport must not be reachable from other machines.
+func ValidateMCPHTTPAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if !isLoopbackHost(host) {
+		return fmt.Errorf("address must be a loopback address such as localhost or 127.0.0.1, got %q", host)
+	}
+	return nil
+}
+
+// Handler returns the HTTP handler for the MCP endpoints.
+func (s *MCPHTTPServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+MCPSSEPath, s.handleSSE)
+	mux.HandleFunc("POST "+MCPMessagesPath, s.handleMessage)
+	mux.HandleFunc("POST "+MCPPostPath, s.handlePost)
+	return s.guard(mux)
+}
+
+// guard refuses requests addressed to, or sent from pages on, a host other
+// than the local machine, then checks the token if one is required.
+func (s *MCPHTTPServer) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !isLoopbackHost(host) {
+			http.Error(w, "forbidden host: "+r.Host, http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !isLoopbackHost(u.Hostname()) {
+				http.Error(w, "forbidden origin: "+origin, http.StatusForbidden)
+				return
+			}
+		}
+		if s.token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="conduit"`)
+				http.Error(w, "missing or invalid API token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackHost reports whether host is localhost or a loopback IP.
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// Serve accepts connections on ln until ctx is cancelled, then closes the
+// open event streams and waits briefly for in-flight requests.
+func (s *MCPHTTPServer) Serve(ctx context.Context, ln net.Listener) error {
+	s.logger.Info().Str("addr", ln.Addr().String()).Msg("KB MCP HTTP server starting")
+
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		s.Close()
+		return err
+	case <-ctx.Done():
+	}
+
+	// Event streams never finish on their own, so end them before Shutdown
+	// waits for connections to go idle
+	s.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), mcpShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close ends all open event streams.
+func (s *MCPHTTPServer) Close() {
+	s.closeOnce.Do(func() { close(s.closed) })
+}
+
+// handleSSE opens an event stream. The first event names the endpoint the
+// client posts its messages to; responses follow as "message" events.
+func (s *MCPHTTPServer) handleSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sessionID := uuid.New().String()
+	session := &mcpSSESession{
+		events: make(chan []byte, 16),
+		done:   make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.sessions[sessionID] = session
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.sessions, sessionID)
+		s.mu.Unlock()
+		close(session.done)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "event: endpoint\ndata: %s?session_id=%s\n\n", MCPMessagesPath, sessionID)
+	flusher.Flush()
+
+	s.logger.Debug().Str("session_id", sessionID).Msg("event stream opened")
+
+	keepAlive := time.NewTicker(mcpSSEKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			s.logger.Debug().Str("session_id", sessionID).Msg("event stream closed by client")
+			return
+		case <-s.closed:
+			return
+		case data := <-session.events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// handleMessage answers a message posted for an event stream session. The
+// post is acknowledged with 202 and the response is sent on the stream.
+func (s *MCPHTTPServer) handleMessage(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.URL.Query().Get("session_id")
+	s.mu.Lock()
+	session, ok := s.sessions[sessionID]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "unknown session: open an event stream at "+MCPSSEPath+" first", http.StatusNotFound)
+		return
+	}
+
+	resp, ok := s.answer(w, r)
+	if !ok {
+		return
+	}
+	if resp != nil {
+		data, _ := json.Marshal(resp)
+		select {
+		case session.events <- data:
+		case <-session.done:
+			http.Error(w, "event stream closed", http.StatusGone)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handlePost answers a single posted request in the response body. Posted
+// notifications are acknowledged with 202 and no body.
+func (s *MCPHTTPServer) handlePost(w http.ResponseWriter, r *http.Request) {
+	resp, ok := s.answer(w, r)
+	if !ok {
+		return
+	}
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// answer decodes the posted request and passes it to the handler. When it
+// returns false an error has already been written.
+func (s *MCPHTTPServer) answer(w http.ResponseWriter, r *http.Request) (*MCPResponse, bool) {
+	var req MCPRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxMCPMessageBytes)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON-RPC message: "+err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	resp, err := s.handle(r.Context(), &req)
+	if err != nil {
+		s.logger.Error().Err(err).Str("method", req.Method).Msg("handle request")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return resp, true
+}
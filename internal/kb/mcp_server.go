@@ -8,7 +8,6 @@ import (
 	"io"
 	"os"
 	"strings"
-	"sync"
 
 	"github.com/rs/zerolog"
 	"github.com/simpleflo/conduit/internal/observability"
@@ -26,7 +25,6 @@ type MCPServer struct {
 
 	input  io.Reader
 	output io.Writer
-	mu     sync.Mutex
 }
 
 // MCPRequest represents an incoming MCP request.
@@ -94,30 +92,15 @@ func (s *MCPServer) SetIO(input io.Reader, output io.Writer) {
 func (s *MCPServer) Run(ctx context.Context) error {
 	s.logger.Info().Msg("KB MCP server starting")
 
-	decoder := json.NewDecoder(s.input)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		var req MCPRequest
-		if err := decoder.Decode(&req); err != nil {
-			if err == io.EOF {
-				return nil
-			}
-			s.logger.Error().Err(err).Msg("decode request")
-			continue
-		}
-
-		s.handleRequest(ctx, &req)
-	}
+	return ServeMCP(ctx, s.input, s.output, func(ctx context.Context, req *MCPRequest) (*MCPResponse, error) {
+		return s.HandleRequest(ctx, req), nil
+	})
 }
 
-// handleRequest processes a single MCP request.
-func (s *MCPServer) handleRequest(ctx context.Context, req *MCPRequest) {
+// HandleRequest processes a single MCP request and returns its response, or
+// nil for notifications. The daemon uses it to serve requests proxied over
+// its socket by "conduit mcp kb".
+func (s *MCPServer) HandleRequest(ctx context.Context, req *MCPRequest) *MCPResponse {
 	s.logger.Debug().
 		Str("method", req.Method).
 		Interface("id", req.ID).
@@ -131,7 +114,7 @@ func (s *MCPServer) handleRequest(ctx context.Context, req *MCPRequest) {
 		result = s.handleInitialize(req.Params)
 	case "initialized":
 		// No response needed for notification
-		return
+		return nil
 	case "tools/list":
 		result = s.handleToolsList()
 	case "tools/call":
@@ -146,9 +129,10 @@ func (s *MCPServer) handleRequest(ctx context.Context, req *MCPRequest) {
 		err = fmt.Errorf("unknown method: %s", req.Method)
 	}
 
-	if req.ID != nil {
-		s.sendResponse(req.ID, result, err)
+	if req.ID == nil {
+		return nil
 	}
+	return NewMCPResponse(req.ID, result, err)
 }
 
 // handleInitialize handles the initialize request.
@@ -181,34 +165,18 @@ func (s *MCPServer) handleToolsList() interface{} {
 			{
 				"name":        "kb_search",
 				"description": "Search the knowledge base for relevant documents using hybrid search (FTS5 keyword matching + semantic similarity when available). Use short keyword phrases for best results.",
-				"inputSchema": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"query": map[string]interface{}{
-							"type":        "string",
-							"description": "The search query. Use short keyword phrases (e.g., 'authentication JWT' rather than 'how does authentication work with JWT tokens').",
-						},
-						"limit": map[string]interface{}{
-							"type":        "integer",
-							"description": "Maximum number of results (default: 10, max: 50)",
-						},
-						"source_id": map[string]interface{}{
-							"type":        "string",
-							"description": "Filter results to a specific knowledge base source. Use kb_list_sources to see available source IDs.",
-						},
-						"mode": map[string]interface{}{
-							"type":        "string",
-							"description": "Search mode: 'hybrid' (default, best results), 'semantic' (vector similarity only), or 'fts5' (keyword matching only)",
-							"enum":        []string{"hybrid", "semantic", "fts5"},
-						},
-						"recall_mode": map[string]interface{}{
-							"type":        "string",
-							"description": "Precision/recall tradeoff: 'high' (disable diversity filtering, get all similar results), 'balanced' (default, moderate filtering), 'precise' (aggressive deduplication)",
-							"enum":        []string{"high", "balanced", "precise"},
-						},
+				"inputSchema": searchToolSchema(map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "The search query. Use short keyword phrases (e.g., 'authentication JWT' rather than 'how does authentication work with JWT tokens').",
 					},
-					"required": []string{"query"},
-				},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("Maximum number of results (default: 10, max: %d)", mcpMaxSearchLimit),
+						"minimum":     1,
+						"maximum":     mcpMaxSearchLimit,
+					},
+				}),
 			},
 			{
 				"name":        "kb_list_sources",
@@ -233,50 +201,59 @@ func (s *MCPServer) handleToolsList() interface{} {
 				},
 			},
 			{
-				"name":        "kb_stats",
-				"description": "Get knowledge base statistics including source counts, document counts, chunk counts, and search capability status.",
+				"name":        "kb_fetch_document",
+				"description": "Read a whole document after finding it with kb_search: its full text with title, path, source and modification time. Long documents are returned in pages; call again with the offset given at the end of a page to read on.",
 				"inputSchema": map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
-						"source_id": map[string]interface{}{
+						"document_id": map[string]interface{}{
 							"type":        "string",
-							"description": "Get stats for a specific source. If omitted, returns aggregate stats for all sources.",
+							"description": "The document ID from search results",
+						},
+						"path": map[string]interface{}{
+							"type":        "string",
+							"description": "The document's absolute path, as an alternative to document_id",
+						},
+						"offset": map[string]interface{}{
+							"type":        "integer",
+							"description": "Character offset to start reading at (default: 0)",
+						},
+						"max_chars": map[string]interface{}{
+							"type":        "integer",
+							"description": fmt.Sprintf("Maximum characters to return (default: %d, max: %d)", fetchDocumentDefaultChars, fetchDocumentMaxChars),
 						},
 					},
 				},
 			},
 			{
-				"name":        "kb_search_with_context",
-				"description": "Search with processed, prompt-ready results. Returns merged chunks from same documents, filters boilerplate, and provides citation-ready source information. Best for RAG use cases.",
+				"name":        "kb_stats",
+				"description": "Get knowledge base statistics including source counts, document counts, chunk counts, and search capability status.",
 				"inputSchema": map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
-						"query": map[string]interface{}{
-							"type":        "string",
-							"description": "The search query for finding relevant context",
-						},
 						"source_id": map[string]interface{}{
 							"type":        "string",
-							"description": "Filter results to a specific source ID",
-						},
-						"limit": map[string]interface{}{
-							"type":        "integer",
-							"description": "Maximum documents to return (default: 5)",
-						},
-						"mode": map[string]interface{}{
-							"type":        "string",
-							"description": "Search mode: 'hybrid' (default), 'semantic', or 'fts5'",
-							"enum":        []string{"hybrid", "semantic", "fts5"},
-						},
-						"recall_mode": map[string]interface{}{
-							"type":        "string",
-							"description": "Precision/recall tradeoff: 'high' (disable diversity filtering, get all similar results), 'balanced' (default, moderate filtering), 'precise' (aggressive deduplication)",
-							"enum":        []string{"high", "balanced", "precise"},
+							"description": "Get stats for a specific source. If omitted, returns aggregate stats for all sources.",
 						},
 					},
-					"required": []string{"query"},
 				},
 			},
+			{
+				"name":        "kb_search_with_context",
+				"description": "Search with processed, prompt-ready results. Returns merged chunks from same documents, filters boilerplate, and provides citation-ready source information. Best for RAG use cases.",
+				"inputSchema": searchToolSchema(map[string]interface{}{
+					"query": map[string]interface{}{
+						"type":        "string",
+						"description": "The search query for finding relevant context",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": fmt.Sprintf("Maximum documents to return (default: 5, max: %d)", mcpMaxSearchLimit),
+						"minimum":     1,
+						"maximum":     mcpMaxSearchLimit,
+					},
+				}),
+			},
 			{
 				"name":        "kag_query",
 				"description": "Query the knowledge graph for entities and their relationships. Use for multi-hop reasoning, aggregation queries, or finding connections between concepts. Complements RAG search with structured entity lookups.",
@@ -335,6 +312,8 @@ func (s *MCPServer) handleToolCall(ctx context.Context, params json.RawMessage)
 		return s.toolListSources(ctx)
 	case "kb_get_document":
 		return s.toolGetDocument(ctx, call.Arguments)
+	case "kb_fetch_document":
+		return s.toolFetchDocument(ctx, call.Arguments)
 	case "kb_stats":
 		return s.toolStats(ctx, call.Arguments)
 	case "kag_query":
@@ -344,58 +323,172 @@ func (s *MCPServer) handleToolCall(ctx context.Context, params json.RawMessage)
 	}
 }
 
-// toolSearch performs a search using the hybrid searcher.
-func (s *MCPServer) toolSearch(ctx context.Context, args json.RawMessage) (interface{}, error) {
-	var params struct {
-		Query      string `json:"query"`
-		Limit      int    `json:"limit"`
-		SourceID   string `json:"source_id"`
-		Mode       string `json:"mode"`
-		RecallMode string `json:"recall_mode"`
+// mcpMaxSearchLimit caps the results a search tool returns.
+const mcpMaxSearchLimit = 50
+
+// mcpSearchModes maps the search tools' mode names to hybrid search modes.
+var mcpSearchModes = map[string]HybridSearchMode{
+	"hybrid":   HybridModeFusion,
+	"semantic": HybridModeSemantic,
+	"fts5":     HybridModeLexical,
+	"lexical":  HybridModeLexical,
+}
+
+// mcpRecallModes maps the search tools' recall_mode names to presets.
+var mcpRecallModes = map[string]RecallMode{
+	"high":     RecallModeHigh,
+	"balanced": RecallModeBalanced,
+	"precise":  RecallModePrecise,
+}
+
+// mcpSearchParams are the arguments shared by kb_search and
+// kb_search_with_context.
+type mcpSearchParams struct {
+	Query      string `json:"query"`
+	Limit      int    `json:"limit"`
+	Source     string `json:"source"`    // Source ID or name
+	SourceID   string `json:"source_id"` // Older spelling of source
+	MimeType   string `json:"mime_type"` // Comma-separated MIME types
+	PathPrefix string `json:"path_prefix"`
+	Lang       string `json:"lang"` // Comma-separated languages
+	Mode       string `json:"mode"`
+	RecallMode string `json:"recall_mode"`
+}
+
+// searchToolSchema returns the input schema of a search tool: its own query
+// and limit properties plus the filters and modes the search tools share.
+func searchToolSchema(properties map[string]interface{}) map[string]interface{} {
+	properties["source"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Only search this knowledge base source, by ID or name. Use kb_list_sources to see available sources.",
 	}
-	if err := json.Unmarshal(args, &params); err != nil {
-		return nil, fmt.Errorf("parse search args: %w", err)
+	properties["source_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Same as source, by ID only (kept for older clients)",
+	}
+	properties["mime_type"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Only documents of these MIME types, comma-separated (e.g. 'text/markdown,application/pdf')",
+	}
+	properties["path_prefix"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Only documents under this path: absolute, or relative to the source root (e.g. 'docs/api')",
+	}
+	properties["lang"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Only chunks in these programming languages, comma-separated (e.g. 'python,typescript'); matches source files and fenced code blocks",
+	}
+	properties["mode"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Search mode: 'hybrid' (default, best results), 'semantic' (vector similarity only), or 'fts5' (keyword matching only)",
+		"enum":        []string{"hybrid", "semantic", "fts5"},
+	}
+	properties["recall_mode"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Precision/recall tradeoff: 'high' (disable diversity filtering, get all similar results), 'balanced' (default, moderate filtering), 'precise' (aggressive deduplication)",
+		"enum":        []string{"high", "balanced", "precise"},
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"query"},
+	}
+}
+
+// searchOptions validates search tool arguments and turns them into search
+// options. The limit is clamped to [1, mcpMaxSearchLimit], defaulting to
+// defaultLimit, and written back to params.
+func (s *MCPServer) searchOptions(ctx context.Context, params *mcpSearchParams, defaultLimit int) (HybridSearchOptions, error) {
+	var opts HybridSearchOptions
+
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return opts, fmt.Errorf("query is required")
 	}
 
 	if params.Limit <= 0 {
-		params.Limit = 10
+		params.Limit = defaultLimit
 	}
-	if params.Limit > 50 {
-		params.Limit = 50 // Cap at max
+	params.Limit = min(params.Limit, mcpMaxSearchLimit)
+	opts.Limit = params.Limit
+
+	mode := strings.ToLower(params.Mode)
+	if mode == "" {
+		mode = "hybrid"
 	}
-	if params.Mode == "" {
-		params.Mode = "hybrid"
+	hybridMode, ok := mcpSearchModes[mode]
+	if !ok {
+		return opts, fmt.Errorf("invalid mode %q: use hybrid, semantic or fts5", params.Mode)
 	}
+	opts.Mode = hybridMode
 
-	// Determine hybrid search mode
-	var hybridMode HybridSearchMode
-	switch params.Mode {
-	case "semantic":
-		hybridMode = HybridModeSemantic
-	case "fts5", "lexical":
-		hybridMode = HybridModeLexical
-	default:
-		hybridMode = HybridModeFusion // hybrid mode
+	recall := strings.ToLower(params.RecallMode)
+	if recall == "" {
+		recall = "balanced"
+	}
+	recallMode, ok := mcpRecallModes[recall]
+	if !ok {
+		return opts, fmt.Errorf("invalid recall_mode %q: use high, balanced or precise", params.RecallMode)
 	}
+	opts.RecallMode = recallMode
 
-	// Map recall_mode string to RecallMode constant
-	var recallMode RecallMode
-	switch params.RecallMode {
-	case "high":
-		recallMode = RecallModeHigh
-	case "precise":
-		recallMode = RecallModePrecise
-	default:
-		recallMode = RecallModeBalanced
+	source := params.Source
+	if source == "" {
+		source = params.SourceID
+	}
+	if source != "" {
+		sourceID, err := s.resolveSource(ctx, source)
+		if err != nil {
+			return opts, err
+		}
+		opts.SourceIDs = []string{sourceID}
 	}
 
-	opts := HybridSearchOptions{
-		Limit:      params.Limit,
-		Mode:       hybridMode,
-		RecallMode: recallMode,
+	for _, mimeType := range strings.Split(params.MimeType, ",") {
+		mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+		if mimeType == "" {
+			continue
+		}
+		if !strings.Contains(mimeType, "/") {
+			return opts, fmt.Errorf("invalid mime_type %q: expected a type like text/markdown", mimeType)
+		}
+		opts.MimeTypes = append(opts.MimeTypes, mimeType)
 	}
-	if params.SourceID != "" {
-		opts.SourceIDs = []string{params.SourceID}
+
+	opts.PathPrefix = strings.TrimSpace(params.PathPrefix)
+	opts.Languages = ParseLanguages(params.Lang)
+
+	return opts, nil
+}
+
+// resolveSource returns the ID of the source named by ref, which is either
+// a source ID or a source name (case-insensitive).
+func (s *MCPServer) resolveSource(ctx context.Context, ref string) (string, error) {
+	if src, err := s.source.Get(ctx, ref); err == nil {
+		return src.SourceID, nil
+	}
+	sources, err := s.source.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list sources: %w", err)
+	}
+	for _, src := range sources {
+		if strings.EqualFold(src.Name, ref) {
+			return src.SourceID, nil
+		}
+	}
+	return "", fmt.Errorf("unknown source %q: use kb_list_sources to see available sources", ref)
+}
+
+// toolSearch performs a search using the hybrid searcher.
+func (s *MCPServer) toolSearch(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var params mcpSearchParams
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, fmt.Errorf("parse search args: %w", err)
+	}
+
+	opts, err := s.searchOptions(ctx, &params, 10)
+	if err != nil {
+		return nil, err
 	}
 
 	// Use hybrid searcher with fallback for better results
@@ -404,13 +497,11 @@ func (s *MCPServer) toolSearch(ctx context.Context, args json.RawMessage) (inter
 		return nil, fmt.Errorf("search: %w", err)
 	}
 
-	// Format results as content blocks
-	var content []map[string]interface{}
+	summary := newSearchSummary(result, len(result.Results), result.HasMore)
 
-	// Add search metadata
-	modeStr := string(result.Mode)
-	if result.DegradedMode {
-		modeStr += " (degraded - semantic unavailable)"
+	// Format results as content blocks, led by the search summary
+	content := []map[string]interface{}{
+		{"type": "text", "text": summary.text("results")},
 	}
 
 	for _, hit := range result.Results {
@@ -421,72 +512,32 @@ func (s *MCPServer) toolSearch(ctx context.Context, args json.RawMessage) (inter
 		})
 	}
 
-	if len(content) == 0 {
-		noteText := "No results found for: " + params.Query
-		if result.Note != "" {
-			noteText += "\n\n" + result.Note
-		}
+	if len(result.Results) == 0 {
 		content = append(content, map[string]interface{}{
 			"type": "text",
-			"text": noteText,
+			"text": "No results found for: " + params.Query,
 		})
 	}
 
 	return map[string]interface{}{
 		"content": content,
+		"_meta":   summary,
 	}, nil
 }
 
 // toolSearchWithContext performs a search and returns processed, prompt-ready results.
 func (s *MCPServer) toolSearchWithContext(ctx context.Context, args json.RawMessage) (interface{}, error) {
-	var params struct {
-		Query      string `json:"query"`
-		SourceID   string `json:"source_id"`
-		Limit      int    `json:"limit"`
-		Mode       string `json:"mode"`
-		RecallMode string `json:"recall_mode"`
-	}
+	var params mcpSearchParams
 	if err := json.Unmarshal(args, &params); err != nil {
 		return nil, fmt.Errorf("parse search args: %w", err)
 	}
 
-	if params.Limit <= 0 {
-		params.Limit = 5 // Default to 5 for processed results
-	}
-	if params.Mode == "" {
-		params.Mode = "hybrid"
-	}
-
-	// Determine hybrid search mode
-	var hybridMode HybridSearchMode
-	switch params.Mode {
-	case "semantic":
-		hybridMode = HybridModeSemantic
-	case "fts5", "lexical":
-		hybridMode = HybridModeLexical
-	default:
-		hybridMode = HybridModeFusion // hybrid mode
-	}
-
-	// Map recall_mode string to RecallMode constant
-	var recallMode RecallMode
-	switch params.RecallMode {
-	case "high":
-		recallMode = RecallModeHigh
-	case "precise":
-		recallMode = RecallModePrecise
-	default:
-		recallMode = RecallModeBalanced
-	}
-
-	opts := HybridSearchOptions{
-		Limit:      params.Limit * 3, // Fetch more to allow for merging
-		Mode:       hybridMode,
-		RecallMode: recallMode,
-	}
-	if params.SourceID != "" {
-		opts.SourceIDs = []string{params.SourceID}
+	// Default to 5 for processed results
+	opts, err := s.searchOptions(ctx, &params, 5)
+	if err != nil {
+		return nil, err
 	}
+	opts.Limit *= 3 // Fetch more to allow for merging
 
 	// Use hybrid searcher with fallback
 	result, err := s.hybrid.SearchWithFallback(ctx, params.Query, opts)
@@ -496,15 +547,22 @@ func (s *MCPServer) toolSearchWithContext(ctx context.Context, args json.RawMess
 
 	// Process results using the result processor
 	processor := NewResultProcessor()
+	processor.SetHigherIsBetter(result.HigherScoresBetter())
 	processed := processor.ProcessResults(result.Results)
 
 	// Limit to requested number of documents
+	truncated := result.HasMore
 	if len(processed) > params.Limit {
 		processed = processed[:params.Limit]
+		truncated = true
 	}
 
+	summary := newSearchSummary(result, len(processed), truncated)
+
 	// Format as prompt-ready content
-	var content []map[string]interface{}
+	content := []map[string]interface{}{
+		{"type": "text", "text": summary.text("documents")},
+	}
 
 	if len(processed) == 0 {
 		content = append(content, map[string]interface{}{
@@ -519,14 +577,25 @@ func (s *MCPServer) toolSearchWithContext(ctx context.Context, args json.RawMess
 
 		for i, p := range processed {
 			sb.WriteString(fmt.Sprintf("### %d. %s\n", i+1, p.Title))
-			sb.WriteString(fmt.Sprintf("*Source: %s", p.Source.File))
+			if p.Source.Breadcrumb != "" {
+				sb.WriteString(fmt.Sprintf("*Source: %s", p.Source.Breadcrumb))
+			} else {
+				sb.WriteString(fmt.Sprintf("*Source: %s", p.Source.File))
+			}
 			if p.Source.Page > 0 {
 				sb.WriteString(fmt.Sprintf(" (page %d)", p.Source.Page))
 			}
-			if p.Source.Section != "" {
+			if p.Source.StartLine > 0 {
+				sb.WriteString(", lines " + formatLineRange(p.Source.StartLine, p.Source.EndLine))
+			}
+			if p.Source.Section != "" && p.Source.Breadcrumb == "" {
 				sb.WriteString(fmt.Sprintf(" - %s", p.Source.Section))
 			}
-			sb.WriteString("*\n\n")
+			sb.WriteString("*\n")
+			if len(p.Alternates) > 0 {
+				sb.WriteString(fmt.Sprintf("*Also in: %s*\n", strings.Join(p.Alternates, ", ")))
+			}
+			sb.WriteString("\n")
 			sb.WriteString(p.Content)
 			sb.WriteString("\n")
 
@@ -543,9 +612,96 @@ func (s *MCPServer) toolSearchWithContext(ctx context.Context, args json.RawMess
 
 	return map[string]interface{}{
 		"content": content,
+		"_meta":   summary,
 	}, nil
 }
 
+// mcpSearchSummary tells the client how complete and how trustworthy a
+// search response is, so a model can caveat partial or speculative matches.
+type mcpSearchSummary struct {
+	TotalHits         int    `json:"total_hits"`                     // Chunks matched by the search
+	Returned          int    `json:"returned"`                       // Results included in the response
+	Truncated         bool   `json:"truncated"`                      // More matches exist than were returned
+	TotalIsLowerBound bool   `json:"total_is_lower_bound,omitempty"` // More chunks may match than TotalHits
+	Confidence        string `json:"confidence"`                     // very_high, high, medium, low, speculative or none
+	FallbackLevel     int    `json:"fallback_level"`                 // 0=primary, 1=relaxed, 2=spelling, 3=partial, 4=no results
+	Mode              string `json:"mode"`
+	DegradedMode      bool   `json:"degraded_mode,omitempty"`
+	Note              string `json:"note,omitempty"`
+}
+
+// fallbackLevelNames describes the HybridSearchResult.FallbackLevel values.
+var fallbackLevelNames = map[int]string{
+	FallbackPrimary:  "primary search",
+	FallbackRelaxed:  "relaxed matching",
+	FallbackSpelling: "spelling correction",
+	FallbackPartial:  "partial word matching",
+	FallbackNone:     "no matches",
+}
+
+// newSearchSummary summarizes a search response. truncated is only reported
+// when the total is known to exceed what was returned, so the summary never
+// claims truncation for "N of N" matches.
+func newSearchSummary(result *HybridSearchResult, returned int, truncated bool) mcpSearchSummary {
+	return mcpSearchSummary{
+		TotalHits:         result.TotalHits,
+		Returned:          returned,
+		Truncated:         truncated && result.TotalHits > returned,
+		TotalIsLowerBound: result.TotalIsLowerBound,
+		Confidence:        result.Confidence,
+		FallbackLevel:     result.FallbackLevel,
+		Mode:              string(result.Mode),
+		DegradedMode:      result.DegradedMode,
+		Note:              result.Note,
+	}
+}
+
+// speculative reports whether the results came from a fallback search or
+// carry little confidence, and should be presented with a caveat.
+func (ss mcpSearchSummary) speculative() bool {
+	switch ss.Confidence {
+	case "low", "speculative", "none":
+		return true
+	}
+	return ss.FallbackLevel > 0
+}
+
+// text renders the summary as the leading content block of a search
+// response. unit names what was returned ("results", "documents").
+func (ss mcpSearchSummary) text(unit string) string {
+	var sb strings.Builder
+	total := fmt.Sprintf("%d", ss.TotalHits)
+	if ss.TotalIsLowerBound {
+		total = "at least " + total
+	}
+	sb.WriteString(fmt.Sprintf("Search summary: returned %d %s from %s matching chunks", ss.Returned, unit, total))
+	if ss.Truncated {
+		sb.WriteString(" (truncated: more matches exist; raise limit or narrow the query)")
+	}
+	sb.WriteString("\n")
+
+	confidence := ss.Confidence
+	if confidence == "" {
+		confidence = "unknown"
+	}
+	level, ok := fallbackLevelNames[ss.FallbackLevel]
+	if !ok {
+		level = "unknown"
+	}
+	sb.WriteString(fmt.Sprintf("Confidence: %s | Fallback level: %d (%s) | Mode: %s",
+		confidence, ss.FallbackLevel, level, ss.Mode))
+	if ss.DegradedMode {
+		sb.WriteString(" (degraded - semantic unavailable)")
+	}
+	if ss.Note != "" {
+		sb.WriteString("\nNote: " + ss.Note)
+	}
+	if ss.speculative() && ss.Returned > 0 {
+		sb.WriteString("\nThese matches are speculative: verify their relevance before relying on them.")
+	}
+	return sb.String()
+}
+
 // toolListSources lists all sources.
 func (s *MCPServer) toolListSources(ctx context.Context) (interface{}, error) {
 	sources, err := s.source.List(ctx)
@@ -612,6 +768,104 @@ func (s *MCPServer) toolGetDocument(ctx context.Context, args json.RawMessage) (
 	}, nil
 }
 
+// Page sizes for kb_fetch_document, in characters. The default keeps a page
+// well inside a client's context window.
+const (
+	fetchDocumentDefaultChars = 20000
+	fetchDocumentMaxChars     = 100000
+)
+
+// toolFetchDocument returns a document's full text, one page at a time.
+func (s *MCPServer) toolFetchDocument(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var params struct {
+		DocumentID string `json:"document_id"`
+		Path       string `json:"path"`
+		Offset     int    `json:"offset"`
+		MaxChars   int    `json:"max_chars"`
+	}
+	if err := json.Unmarshal(args, &params); err != nil {
+		return nil, fmt.Errorf("parse args: %w", err)
+	}
+
+	ref := params.DocumentID
+	if ref == "" {
+		ref = params.Path
+	}
+	if ref == "" {
+		return nil, fmt.Errorf("document_id or path is required")
+	}
+	if params.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+	if params.MaxChars <= 0 {
+		params.MaxChars = fetchDocumentDefaultChars
+	}
+	params.MaxChars = min(params.MaxChars, fetchDocumentMaxChars)
+
+	doc, err := s.source.GetDocumentContent(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("fetch document: %w", err)
+	}
+
+	total := len([]rune(doc.Content))
+	if params.Offset > total {
+		return nil, fmt.Errorf("offset %d is past the end of the document (%d characters)", params.Offset, total)
+	}
+	page, next := documentPage(doc.Content, params.Offset, params.MaxChars)
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "# %s\n\n", doc.Title)
+	fmt.Fprintf(&text, "Document ID: %s\nPath: %s\n", doc.DocumentID, doc.Path)
+	if src, err := s.source.Get(ctx, doc.SourceID); err == nil {
+		fmt.Fprintf(&text, "Source: %s (%s)\n", src.Name, src.SourceID)
+	}
+	if !doc.ModifiedAt.IsZero() {
+		fmt.Fprintf(&text, "Modified: %s\n", doc.ModifiedAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(&text, "Type: %s\nSize: %d bytes\n", doc.MimeType, doc.Size)
+	switch {
+	case doc.ContentFrom == ContentFromIndex:
+		text.WriteString("Note: the file is no longer readable; this text was reassembled from the index.\n")
+	case doc.Changed:
+		text.WriteString("Note: the file changed since it was indexed; this is its current text.\n")
+	}
+	end := total
+	if next > 0 {
+		end = next
+	}
+	fmt.Fprintf(&text, "Characters: %d-%d of %d\n\n---\n\n%s", params.Offset, end, total, page)
+	if next > 0 {
+		fmt.Fprintf(&text, "\n\n---\n\n[Document continues: call kb_fetch_document with offset=%d for the next page]", next)
+	}
+
+	return map[string]interface{}{
+		"content": []map[string]interface{}{
+			{"type": "text", "text": text.String()},
+		},
+	}, nil
+}
+
+// documentPage returns up to maxChars characters of content starting at
+// offset, and the offset of the next page or 0 at the end. Pages break at a
+// line end when one falls in their last fifth, so lines are not split.
+func documentPage(content string, offset, maxChars int) (string, int) {
+	runes := []rune(content)
+	if offset >= len(runes) {
+		return "", 0
+	}
+	end := offset + maxChars
+	if end >= len(runes) {
+		return string(runes[offset:]), 0
+	}
+	for i := end; i > end-maxChars/5; i-- {
+		if runes[i-1] == '\n' {
+			end = i
+			break
+		}
+	}
+	return string(runes[offset:end]), end
+}
+
 // removeOverlaps removes overlapping content from chunks.
 func (s *MCPServer) removeOverlaps(parts []string) string {
 	if len(parts) == 0 {
@@ -838,12 +1092,9 @@ func (s *MCPServer) toolKagQuery(ctx context.Context, args json.RawMessage) (int
 	}, nil
 }
 
-// sendResponse sends an MCP response.
-func (s *MCPServer) sendResponse(id interface{}, result interface{}, err error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	resp := MCPResponse{
+// NewMCPResponse builds the response to request id from a result or error.
+func NewMCPResponse(id interface{}, result interface{}, err error) *MCPResponse {
+	resp := &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      id,
 	}
@@ -857,6 +1108,44 @@ func (s *MCPServer) sendResponse(id interface{}, result interface{}, err error)
 		resp.Result = result
 	}
 
-	data, _ := json.Marshal(resp)
-	fmt.Fprintln(s.output, string(data))
+	return resp
+}
+
+// MCPHandler answers one MCP request. A nil response means nothing is sent
+// back; an error stops the serve loop.
+type MCPHandler func(ctx context.Context, req *MCPRequest) (*MCPResponse, error)
+
+// ServeMCP reads newline-delimited MCP requests from input and writes each
+// response to output until input is exhausted or ctx is cancelled.
+func ServeMCP(ctx context.Context, input io.Reader, output io.Writer, handle MCPHandler) error {
+	logger := observability.Logger("kb.mcp")
+	decoder := json.NewDecoder(input)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		var req MCPRequest
+		if err := decoder.Decode(&req); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			logger.Error().Err(err).Msg("decode request")
+			continue
+		}
+
+		resp, err := handle(ctx, &req)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			continue
+		}
+
+		data, _ := json.Marshal(resp)
+		fmt.Fprintln(output, string(data))
+	}
 }
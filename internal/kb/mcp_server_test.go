@@ -0,0 +1,337 @@
+package kb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMCPServer_Run(t *testing.T) {
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"no/such/method"}`,
+	}, "\n")
+
+	var output strings.Builder
+	server := NewMCPServer(nil, nil)
+	server.SetIO(strings.NewReader(input), &output)
+
+	if err := server.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var responses []MCPResponse
+	scanner := bufio.NewScanner(strings.NewReader(output.String()))
+	for scanner.Scan() {
+		var resp MCPResponse
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+
+	// The notification gets no response
+	if len(responses) != 3 {
+		t.Fatalf("expected 3 responses, got %d: %s", len(responses), output.String())
+	}
+	for i, resp := range responses[:2] {
+		if resp.Error != nil || resp.Result == nil {
+			t.Errorf("response %d: expected result, got error %v", i, resp.Error)
+		}
+	}
+	if responses[2].Error == nil || !strings.Contains(responses[2].Error.Message, "unknown method") {
+		t.Errorf("expected unknown method error, got %+v", responses[2])
+	}
+}
+
+func TestMCPHTTPServer(t *testing.T) {
+	server := NewMCPServer(nil, nil)
+	httpServer := NewMCPHTTPServer(func(ctx context.Context, req *MCPRequest) (*MCPResponse, error) {
+		return server.HandleRequest(ctx, req), nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- httpServer.Serve(ctx, ln) }()
+	baseURL := "http://" + ln.Addr().String()
+
+	post := func(path, body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(baseURL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		return resp
+	}
+
+	// A plain POST is answered in the response body
+	resp := post(MCPPostPath, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	var listed MCPResponse
+	json.NewDecoder(resp.Body).Decode(&listed)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || listed.Error != nil || listed.Result == nil {
+		t.Fatalf("tools/list over POST: status %d, response %+v", resp.StatusCode, listed)
+	}
+	resp = post(MCPPostPath, `{"jsonrpc":"2.0","method":"initialized"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification: expected 202, got %d", resp.StatusCode)
+	}
+
+	// Messages for an unknown event stream are rejected
+	resp = post(MCPMessagesPath+"?session_id=nope", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session: expected 404, got %d", resp.StatusCode)
+	}
+
+	// Over SSE the stream announces the message endpoint and carries responses
+	stream, err := http.Get(baseURL + MCPSSEPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+	events := bufio.NewScanner(stream.Body)
+	readEvent := func() (string, string) {
+		t.Helper()
+		var event, data string
+		for events.Scan() {
+			line := events.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && event != "":
+				return event, data
+			}
+		}
+		t.Fatalf("event stream ended: %v", events.Err())
+		return "", ""
+	}
+
+	event, endpoint := readEvent()
+	if event != "endpoint" || !strings.HasPrefix(endpoint, MCPMessagesPath+"?session_id=") {
+		t.Fatalf("expected endpoint event, got %q %q", event, endpoint)
+	}
+	resp = post(endpoint, `{"jsonrpc":"2.0","id":7,"method":"initialize","params":{}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("message: expected 202, got %d", resp.StatusCode)
+	}
+	event, data := readEvent()
+	var initialized MCPResponse
+	if err := json.Unmarshal([]byte(data), &initialized); err != nil || event != "message" {
+		t.Fatalf("expected message event, got %q %q", event, data)
+	}
+	if initialized.Error != nil || initialized.Result == nil || initialized.ID != float64(7) {
+		t.Errorf("unexpected initialize response %+v", initialized)
+	}
+
+	// Cancelling ends the open stream and shuts the server down
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestMCPHTTPServer_Guard(t *testing.T) {
+	server := NewMCPServer(nil, nil)
+	httpServer := NewMCPHTTPServer(func(ctx context.Context, req *MCPRequest) (*MCPResponse, error) {
+		return server.HandleRequest(ctx, req), nil
+	})
+	handler := httpServer.Handler()
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+
+	do := func(host, origin, auth string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, MCPPostPath, strings.NewReader(body))
+		req.Host = host
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	tests := []struct {
+		name   string
+		host   string
+		origin string
+		want   int
+	}{
+		{"localhost", "localhost:8091", "", http.StatusOK},
+		{"ipv4 loopback", "127.0.0.1:8091", "", http.StatusOK},
+		{"ipv6 loopback", "[::1]:8091", "", http.StatusOK},
+		{"local origin", "localhost:8091", "http://localhost:3000", http.StatusOK},
+		{"rebound host", "attacker.example:8091", "", http.StatusForbidden},
+		{"foreign origin", "localhost:8091", "https://attacker.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if got := do(tt.host, tt.origin, ""); got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+
+	httpServer.RequireToken("secret-token")
+	if got := do("localhost:8091", "", ""); got != http.StatusUnauthorized {
+		t.Errorf("missing token: expected 401, got %d", got)
+	}
+	if got := do("localhost:8091", "", "Bearer wrong"); got != http.StatusUnauthorized {
+		t.Errorf("wrong token: expected 401, got %d", got)
+	}
+	if got := do("localhost:8091", "", "Bearer secret-token"); got != http.StatusOK {
+		t.Errorf("valid token: expected 200, got %d", got)
+	}
+}
+
+func TestValidateMCPHTTPAddr(t *testing.T) {
+	for _, addr := range []string{"localhost:8091", "127.0.0.1:0", "[::1]:8091"} {
+		if err := ValidateMCPHTTPAddr(addr); err != nil {
+			t.Errorf("%s: unexpected error %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"0.0.0.0:8091", ":8091", "192.168.1.10:8091", "example.com:8091", "localhost"} {
+		if err := ValidateMCPHTTPAddr(addr); err == nil {
+			t.Errorf("%s: expected an error", addr)
+		}
+	}
+}
+
+func TestMCPServer_HandleRequestNotification(t *testing.T) {
+	server := NewMCPServer(nil, nil)
+	if resp := server.HandleRequest(context.Background(), &MCPRequest{JSONRPC: "2.0", Method: "initialized"}); resp != nil {
+		t.Errorf("expected no response to a notification, got %+v", resp)
+	}
+}
+
+func TestDocumentPage(t *testing.T) {
+	content := strings.Repeat("line of text\n", 10) // 130 characters
+
+	page, next := documentPage(content, 0, 45)
+	if next != 39 || page != content[:39] {
+		t.Errorf("first page should end after the last full line: got next=%d page=%q", next, page)
+	}
+
+	var pages []string
+	for offset := 0; ; {
+		page, next := documentPage(content, offset, 50)
+		pages = append(pages, page)
+		if next == 0 {
+			break
+		}
+		offset = next
+	}
+	if strings.Join(pages, "") != content {
+		t.Errorf("pages do not cover the document: %q", pages)
+	}
+
+	// No line end in the last fifth: cut at the limit
+	page, next = documentPage(strings.Repeat("é", 30), 10, 10)
+	if next != 20 || page != strings.Repeat("é", 10) {
+		t.Errorf("expected a 10-rune page ending at 20, got next=%d page=%q", next, page)
+	}
+
+	if page, next := documentPage(content, len(content), 50); page != "" || next != 0 {
+		t.Errorf("expected an empty last page, got next=%d page=%q", next, page)
+	}
+}
+
+func TestMCPServer_SearchOptions(t *testing.T) {
+	server := NewMCPServer(nil, nil)
+	ctx := context.Background()
+
+	params := mcpSearchParams{Query: " rate limits ", Limit: 500, Mode: "FTS5", MimeType: "text/markdown, application/pdf", Lang: "py", PathPrefix: "docs/api"}
+	opts, err := server.searchOptions(ctx, &params, 10)
+	if err != nil {
+		t.Fatalf("searchOptions: %v", err)
+	}
+	if opts.Limit != mcpMaxSearchLimit || params.Limit != mcpMaxSearchLimit {
+		t.Errorf("limit should be clamped to %d, got %d", mcpMaxSearchLimit, opts.Limit)
+	}
+	if opts.Mode != HybridModeLexical || opts.RecallMode != RecallModeBalanced {
+		t.Errorf("unexpected modes %q, %q", opts.Mode, opts.RecallMode)
+	}
+	if len(opts.MimeTypes) != 2 || opts.MimeTypes[1] != "application/pdf" {
+		t.Errorf("unexpected MIME types %v", opts.MimeTypes)
+	}
+	if len(opts.Languages) != 1 || opts.Languages[0] != "python" || opts.PathPrefix != "docs/api" {
+		t.Errorf("unexpected filters %v, %q", opts.Languages, opts.PathPrefix)
+	}
+
+	if opts, _ := server.searchOptions(ctx, &mcpSearchParams{Query: "q"}, 5); opts.Limit != 5 || opts.Mode != HybridModeFusion {
+		t.Errorf("expected defaults limit 5 and hybrid mode, got %d, %q", opts.Limit, opts.Mode)
+	}
+
+	for _, bad := range []mcpSearchParams{
+		{Query: "  "},
+		{Query: "q", Mode: "fuzzy"},
+		{Query: "q", RecallMode: "everything"},
+		{Query: "q", MimeType: "markdown"},
+	} {
+		if _, err := server.searchOptions(ctx, &bad, 10); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestSearchSummary(t *testing.T) {
+	primary := newSearchSummary(&HybridSearchResult{
+		TotalHits: 12, HasMore: true, Confidence: "high", Mode: HybridModeFusion,
+	}, 5, true)
+	text := primary.text("results")
+	for _, want := range []string{"returned 5 results from 12 matching chunks", "truncated", "Confidence: high", "Fallback level: 0 (primary search)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary %q missing %q", text, want)
+		}
+	}
+	if strings.Contains(text, "speculative") {
+		t.Errorf("primary high-confidence results should not be caveated: %q", text)
+	}
+
+	partial := newSearchSummary(&HybridSearchResult{
+		TotalHits: 2, FallbackLevel: FallbackPartial, Confidence: "speculative", Mode: HybridModeFusion,
+		Note: "Partial word matching - results may not fully match query",
+	}, 2, false)
+	text = partial.text("documents")
+	for _, want := range []string{"Fallback level: 3 (partial word matching)", "Note: Partial word matching", "verify their relevance"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary %q missing %q", text, want)
+		}
+	}
+	if strings.Contains(text, "truncated") {
+		t.Errorf("complete results reported as truncated: %q", text)
+	}
+
+	// Truncation is only claimed when the total exceeds what was returned
+	exact := newSearchSummary(&HybridSearchResult{TotalHits: 5, HasMore: true, Confidence: "high"}, 5, true)
+	if exact.Truncated || strings.Contains(exact.text("results"), "truncated") {
+		t.Errorf("5 of 5 matches reported as truncated: %+v", exact)
+	}
+
+	bound := newSearchSummary(&HybridSearchResult{TotalHits: 30, TotalIsLowerBound: true, Confidence: "high"}, 10, true)
+	if text := bound.text("results"); !strings.Contains(text, "from at least 30 matching chunks") || !strings.Contains(text, "truncated") {
+		t.Errorf("lower-bound total not reported: %q", text)
+	}
+}
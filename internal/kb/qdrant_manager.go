@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
@@ -29,6 +31,29 @@ type QdrantManager struct {
 	logger         zerolog.Logger
 	containerCmd   string // "docker" or "podman"
 	collectionName string
+	httpClient     *http.Client
+}
+
+// Timeouts for Qdrant HTTP calls, by kind of call.
+const (
+	qdrantProbeTimeout  = 2 * time.Second  // reachability and port checks
+	qdrantHealthTimeout = 5 * time.Second  // collection info
+	qdrantAdminTimeout  = 30 * time.Second // collection deletes
+)
+
+// qdrantHTTPClient is shared by every Qdrant HTTP call so connections to the
+// local API are kept alive and reused instead of opened per request.
+// Timeouts are set per call through the request context.
+var qdrantHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   qdrantProbeTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:        16,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	},
 }
 
 // QdrantConfig configures the Qdrant manager.
@@ -77,10 +102,35 @@ func NewQdrantManager(cfg QdrantConfig) *QdrantManager {
 		httpPort:       cfg.HTTPPort,
 		grpcPort:       cfg.GRPCPort,
 		collectionName: cfg.CollectionName,
+		httpClient:     qdrantHTTPClient,
 		logger:         observability.Logger("kb.qdrant"),
 	}
 }
 
+// request calls the Qdrant HTTP API on the shared client and returns the
+// status and body. The body is always read to the end so the connection can
+// be reused.
+func (m *QdrantManager) request(ctx context.Context, method, path string, timeout time.Duration) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("http://localhost:%d%s", m.httpPort, path), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
 // EnsureReady ensures Qdrant is ready for use.
 // This is the main entry point that handles all aspects of Qdrant readiness:
 // 1. Ensures storage directory exists
@@ -151,22 +201,30 @@ func (m *QdrantManager) ensureStorageDir() error {
 }
 
 // detectContainerRuntime finds an available container runtime.
-// Uses findBinaryPath to locate binaries in known paths (not just PATH).
 func (m *QdrantManager) detectContainerRuntime() error {
+	containerCmd, err := detectContainerCmd(m.logger)
+	if err != nil {
+		return err
+	}
+	m.containerCmd = containerCmd
+	return nil
+}
+
+// detectContainerCmd finds an available container runtime and returns its path.
+// Uses findBinaryPath to locate binaries in known paths (not just PATH).
+func detectContainerCmd(logger zerolog.Logger) (string, error) {
 	// Check for podman first (preferred on macOS with Podman machine)
 	if podmanPath := findBinaryPath("podman"); podmanPath != "" {
 		// On macOS, check if podman machine is running
 		if runtime.GOOS == "darwin" {
 			out, err := exec.Command(podmanPath, "machine", "list", "--format", "{{.Running}}").Output()
 			if err == nil && strings.Contains(string(out), "true") {
-				m.containerCmd = podmanPath
-				m.logger.Debug().Str("runtime", "podman").Str("path", podmanPath).Msg("using container runtime")
-				return nil
+				logger.Debug().Str("runtime", "podman").Str("path", podmanPath).Msg("using container runtime")
+				return podmanPath, nil
 			}
 		} else {
-			m.containerCmd = podmanPath
-			m.logger.Debug().Str("runtime", "podman").Str("path", podmanPath).Msg("using container runtime")
-			return nil
+			logger.Debug().Str("runtime", "podman").Str("path", podmanPath).Msg("using container runtime")
+			return podmanPath, nil
 		}
 	}
 
@@ -174,19 +232,18 @@ func (m *QdrantManager) detectContainerRuntime() error {
 	if dockerPath := findBinaryPath("docker"); dockerPath != "" {
 		// Verify Docker daemon is running
 		if err := exec.Command(dockerPath, "info").Run(); err == nil {
-			m.containerCmd = dockerPath
-			m.logger.Debug().Str("runtime", "docker").Str("path", dockerPath).Msg("using container runtime")
-			return nil
+			logger.Debug().Str("runtime", "docker").Str("path", dockerPath).Msg("using container runtime")
+			return dockerPath, nil
 		}
 	}
 
-	return fmt.Errorf("no container runtime available (need Docker or Podman)")
+	return "", fmt.Errorf("no container runtime available (need Docker or Podman)")
 }
 
 // ensureContainerRunning ensures the Qdrant container is running.
 func (m *QdrantManager) ensureContainerRunning(ctx context.Context) error {
 	// First, check if Qdrant API is already reachable (e.g., from another container or external instance)
-	if m.isAPIReachable() {
+	if m.isAPIReachable(ctx) {
 		m.logger.Info().Int("port", m.httpPort).Msg("Qdrant API already reachable, using existing instance")
 		return nil
 	}
@@ -218,7 +275,7 @@ func (m *QdrantManager) ensureContainerRunning(ctx context.Context) error {
 	}
 
 	// Check if port is already in use by something else
-	if m.isPortInUse() {
+	if m.isPortInUse(ctx) {
 		m.logger.Warn().Int("port", m.httpPort).Msg("port already in use but API not reachable, cannot start Qdrant")
 		return fmt.Errorf("port %d already in use by another process", m.httpPort)
 	}
@@ -229,20 +286,14 @@ func (m *QdrantManager) ensureContainerRunning(ctx context.Context) error {
 }
 
 // isAPIReachable checks if the Qdrant API is reachable without waiting.
-func (m *QdrantManager) isAPIReachable() bool {
-	client := &http.Client{Timeout: 2 * time.Second}
-	resp, err := client.Get(fmt.Sprintf("http://localhost:%d/collections", m.httpPort))
-	if err != nil {
-		return false
-	}
-	resp.Body.Close()
-	return resp.StatusCode == http.StatusOK
+func (m *QdrantManager) isAPIReachable(ctx context.Context) bool {
+	status, _, err := m.request(ctx, http.MethodGet, "/collections", qdrantProbeTimeout)
+	return err == nil && status == http.StatusOK
 }
 
 // isPortInUse checks if the HTTP port is already bound.
-func (m *QdrantManager) isPortInUse() bool {
-	client := &http.Client{Timeout: 1 * time.Second}
-	_, err := client.Get(fmt.Sprintf("http://localhost:%d/", m.httpPort))
+func (m *QdrantManager) isPortInUse(ctx context.Context) bool {
+	_, _, err := m.request(ctx, http.MethodGet, "/", qdrantProbeTimeout)
 	// If we get any response (even error), port is in use
 	// Connection refused means port is free
 	return err == nil || !strings.Contains(err.Error(), "connection refused")
@@ -309,7 +360,6 @@ func (m *QdrantManager) containerExists(ctx context.Context) (bool, error) {
 // waitForAPI waits for the Qdrant API to become reachable.
 func (m *QdrantManager) waitForAPI(ctx context.Context, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
-	client := &http.Client{Timeout: 2 * time.Second}
 
 	for time.Now().Before(deadline) {
 		select {
@@ -318,13 +368,9 @@ func (m *QdrantManager) waitForAPI(ctx context.Context, timeout time.Duration) e
 		default:
 		}
 
-		resp, err := client.Get(fmt.Sprintf("http://localhost:%d/collections", m.httpPort))
-		if err == nil {
-			resp.Body.Close()
-			if resp.StatusCode == http.StatusOK {
-				m.logger.Debug().Msg("Qdrant API is reachable")
-				return nil
-			}
+		if m.isAPIReachable(ctx) {
+			m.logger.Debug().Msg("Qdrant API is reachable")
+			return nil
 		}
 
 		time.Sleep(500 * time.Millisecond)
@@ -338,8 +384,7 @@ func (m *QdrantManager) CheckHealth(ctx context.Context) QdrantHealth {
 	health := QdrantHealth{}
 
 	// First check if API is reachable (works regardless of container name)
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(fmt.Sprintf("http://localhost:%d/collections/%s", m.httpPort, m.collectionName))
+	status, body, err := m.request(ctx, http.MethodGet, "/collections/"+m.collectionName, qdrantHealthTimeout)
 	if err != nil {
 		// API not reachable - check if our managed container is running
 		running, _ := m.isContainerRunning(ctx)
@@ -351,13 +396,12 @@ func (m *QdrantManager) CheckHealth(ctx context.Context) QdrantHealth {
 		}
 		return health
 	}
-	defer resp.Body.Close()
 
 	// API is reachable
 	health.ContainerRunning = true // Qdrant is running (may not be our container)
 	health.APIReachable = true
 
-	if resp.StatusCode == http.StatusNotFound {
+	if status == http.StatusNotFound {
 		health.CollectionStatus = "missing"
 		return health
 	}
@@ -372,7 +416,7 @@ func (m *QdrantManager) CheckHealth(ctx context.Context) QdrantHealth {
 		} `json:"result"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		health.Error = fmt.Sprintf("parse error: %v", err)
 		return health
 	}
@@ -405,21 +449,10 @@ func (m *QdrantManager) recoverCollection(ctx context.Context) error {
 	m.logger.Warn().Str("collection", m.collectionName).Msg("attempting collection recovery")
 
 	// Strategy 1: Try to delete and recreate the collection
-	client := &http.Client{Timeout: 30 * time.Second}
-
-	// Delete the corrupted collection
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete,
-		fmt.Sprintf("http://localhost:%d/collections/%s", m.httpPort, m.collectionName), nil)
-	if err != nil {
-		return fmt.Errorf("create delete request: %w", err)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
+	if _, _, err := m.request(ctx, http.MethodDelete, "/collections/"+m.collectionName, qdrantAdminTimeout); err != nil {
 		m.logger.Warn().Err(err).Msg("failed to delete collection, trying container restart")
 		return m.restartContainer(ctx)
 	}
-	resp.Body.Close()
 
 	m.logger.Info().Str("collection", m.collectionName).Msg("deleted corrupted collection")
 
@@ -469,7 +502,7 @@ func (m *QdrantManager) RecreateContainer(ctx context.Context) error {
 
 // getEmptyAuthFile returns path to an empty auth file for bypassing credential helpers.
 // This is needed when running from Electron where credential helpers (like gcloud) aren't in PATH.
-func (m *QdrantManager) getEmptyAuthFile() (string, error) {
+func getEmptyAuthFile() (string, error) {
 	authFile := filepath.Join(os.TempDir(), "conduit-empty-auth.json")
 	// Create or verify the file exists with valid JSON content
 	if _, err := os.Stat(authFile); os.IsNotExist(err) {
@@ -482,7 +515,7 @@ func (m *QdrantManager) getEmptyAuthFile() (string, error) {
 
 // getDockerConfigDir returns path to a temp directory with empty Docker config.
 // This bypasses credential helpers (docker-credential-desktop, etc.) for Docker.
-func (m *QdrantManager) getDockerConfigDir() string {
+func getDockerConfigDir() string {
 	configDir := filepath.Join(os.TempDir(), "conduit-docker-config")
 	os.MkdirAll(configDir, 0700)
 	configPath := filepath.Join(configDir, "config.json")
@@ -493,21 +526,33 @@ func (m *QdrantManager) getDockerConfigDir() string {
 }
 
 // runContainerCmd runs a container command.
+func (m *QdrantManager) runContainerCmd(ctx context.Context, args ...string) error {
+	return runContainerCommand(ctx, m.logger, m.containerCmd, args...)
+}
+
+// runContainerCommand runs a command with the given container runtime.
 // For pull/run commands, it bypasses credential helpers:
 // - Podman: uses --authfile with empty JSON
 // - Docker: uses DOCKER_CONFIG env pointing to dir with empty config.json
-func (m *QdrantManager) runContainerCmd(ctx context.Context, args ...string) error {
+func runContainerCommand(ctx context.Context, logger zerolog.Logger, containerCmd string, args ...string) error {
+	return runContainerCommandEnv(ctx, logger, containerCmd, nil, args...)
+}
+
+// runContainerCommandEnv is runContainerCommand with env (KEY=VALUE pairs)
+// added to the command's environment. Values passed this way, such as
+// passwords for "-e KEY" arguments, are removed from error messages.
+func runContainerCommandEnv(ctx context.Context, logger zerolog.Logger, containerCmd string, env []string, args ...string) error {
 	// Check if this is a pull or run command that needs auth bypass
 	finalArgs := args
-	isPodman := strings.Contains(filepath.Base(m.containerCmd), "podman")
+	isPodman := strings.Contains(filepath.Base(containerCmd), "podman")
 	var dockerConfigDir string
 
 	if len(args) > 0 && (args[0] == "pull" || args[0] == "run") {
 		if isPodman {
 			// Podman: use --authfile
-			authFile, err := m.getEmptyAuthFile()
+			authFile, err := getEmptyAuthFile()
 			if err != nil {
-				m.logger.Warn().Err(err).Msg("could not create empty auth file, trying without")
+				logger.Warn().Err(err).Msg("could not create empty auth file, trying without")
 			} else {
 				finalArgs = make([]string, 0, len(args)+2)
 				finalArgs = append(finalArgs, args[0])
@@ -516,17 +561,28 @@ func (m *QdrantManager) runContainerCmd(ctx context.Context, args ...string) err
 			}
 		} else {
 			// Docker: use DOCKER_CONFIG env
-			dockerConfigDir = m.getDockerConfigDir()
+			dockerConfigDir = getDockerConfigDir()
 		}
 	}
 
-	cmd := exec.CommandContext(ctx, m.containerCmd, finalArgs...)
+	cmd := exec.CommandContext(ctx, containerCmd, finalArgs...)
+	cmdEnv := env
 	if dockerConfigDir != "" {
-		cmd.Env = append(os.Environ(), "DOCKER_CONFIG="+dockerConfigDir)
+		cmdEnv = append(cmdEnv[:len(cmdEnv):len(cmdEnv)], "DOCKER_CONFIG="+dockerConfigDir)
+	}
+	if len(cmdEnv) > 0 {
+		cmd.Env = append(os.Environ(), cmdEnv...)
 	}
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("%s %v: %w (output: %s)", m.containerCmd, finalArgs, err, string(output))
+		// The values are not in the arguments, but the runtime may echo them
+		out := string(output)
+		for _, kv := range env {
+			if _, value, ok := strings.Cut(kv, "="); ok && value != "" {
+				out = strings.ReplaceAll(out, value, "REDACTED")
+			}
+		}
+		return fmt.Errorf("%s %v: %w (output: %s)", containerCmd, finalArgs, err, out)
 	}
 	return nil
 }
@@ -666,7 +722,7 @@ func (m *QdrantManager) Install(ctx context.Context) error {
 	}
 
 	// Check if already running
-	if m.isAPIReachable() {
+	if m.isAPIReachable(ctx) {
 		m.logger.Info().Int("port", m.httpPort).Msg("Qdrant is already running")
 		return nil
 	}
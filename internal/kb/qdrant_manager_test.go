@@ -0,0 +1,57 @@
+package kb
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQdrantManager_CheckHealthReusesConnections(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/collections":
+			w.Write([]byte(`{"result":{"collections":[{"name":"present"}]}}`))
+		case "/collections/present":
+			w.Write([]byte(`{"result":{"status":"green","indexed_vectors_count":3,"points_count":3,"optimizer_status":"ok"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	ctx := context.Background()
+
+	m := NewQdrantManager(QdrantConfig{DataDir: t.TempDir(), HTTPPort: port, CollectionName: "present"})
+	for i := 0; i < 3; i++ {
+		health := m.CheckHealth(ctx)
+		if !health.APIReachable || health.CollectionStatus != "green" || health.TotalPoints != 3 {
+			t.Fatalf("unexpected health: %+v", health)
+		}
+	}
+
+	missing := NewQdrantManager(QdrantConfig{DataDir: t.TempDir(), HTTPPort: port, CollectionName: "absent"})
+	if health := missing.CheckHealth(ctx); health.CollectionStatus != "missing" {
+		t.Fatalf("expected missing collection, got %+v", health)
+	}
+	if !missing.isAPIReachable(ctx) {
+		t.Fatal("expected API to be reachable")
+	}
+
+	if n := conns.Load(); n != 1 {
+		t.Errorf("expected all calls to share one connection, opened %d", n)
+	}
+}
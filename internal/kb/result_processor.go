@@ -3,12 +3,21 @@ package kb
 import (
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 )
 
 // ResultProcessor post-processes search results for cleaner output.
 type ResultProcessor struct {
 	boilerplatePatterns []*regexp.Regexp
+
+	// Query terms are marked in result content in this style
+	query     string
+	highlight HighlightStyle
+
+	// higherIsBetter ranks higher scores first (semantic and fused scores);
+	// by default lower scores rank first, as with BM25
+	higherIsBetter bool
 }
 
 // NewResultProcessor creates a new result processor.
@@ -18,6 +27,27 @@ func NewResultProcessor() *ResultProcessor {
 	}
 }
 
+// SetHighlight marks the terms of query in processed content with style.
+func (p *ResultProcessor) SetHighlight(query string, style HighlightStyle) {
+	p.query = query
+	p.highlight = style
+}
+
+// SetHigherIsBetter sets whether the hits being processed rank higher scores
+// first. FTS5 BM25 scores are negative and rank lower scores first, the
+// default; semantic and fused hybrid scores rank higher scores first.
+func (p *ResultProcessor) SetHigherIsBetter(higher bool) {
+	p.higherIsBetter = higher
+}
+
+// better reports whether score a ranks ahead of score b.
+func (p *ResultProcessor) better(a, b float64) bool {
+	if p.higherIsBetter {
+		return a > b
+	}
+	return a < b
+}
+
 // ProcessedResult contains a processed search result with merged chunks.
 type ProcessedResult struct {
 	DocumentID string            `json:"document_id"`
@@ -25,18 +55,53 @@ type ProcessedResult struct {
 	Title      string            `json:"title"`
 	Content    string            `json:"content"`
 	Score      float64           `json:"score"`
+	RawScore   float64           `json:"raw_score"`
 	ChunkCount int               `json:"chunk_count"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
 	Source     SourceInfo        `json:"source"`
+
+	// Explanations holds the score breakdown of each merged chunk (explain mode only)
+	Explanations []*ScoreExplanation `json:"explanations,omitempty"`
+
+	// Alternates lists the paths of near-identical documents that were
+	// collapsed into this result (e.g. the same PDF in two sources).
+	Alternates []string `json:"alternates,omitempty"`
 }
 
+// duplicateSimilarity is the Jaccard similarity above which two results from
+// different documents are treated as the same content.
+const duplicateSimilarity = 0.9
+
 // SourceInfo provides citation-ready source information.
 type SourceInfo struct {
 	File    string `json:"file"`
 	Page    int    `json:"page,omitempty"`
 	Section string `json:"section,omitempty"`
+
+	// HeadingPath is the heading hierarchy of the best-matching chunk,
+	// outermost first (Markdown documents only).
+	HeadingPath []string `json:"heading_path,omitempty"`
+
+	// Breadcrumb renders the file and heading path, e.g.
+	// "README.md › Installation › Docker".
+	Breadcrumb string `json:"breadcrumb,omitempty"`
+
+	// Sections lists the distinct heading paths of all merged chunks in
+	// document order, when they span more than one section.
+	Sections []string `json:"sections,omitempty"`
+
+	// StartLine and EndLine are the 1-based lines of the file the
+	// best-matching chunk spans (text files only).
+	StartLine int `json:"start_line,omitempty"`
+	EndLine   int `json:"end_line,omitempty"`
 }
 
+// headingPathSeparator joins headings in the "heading_path" chunk metadata.
+const headingPathSeparator = " > "
+
+// breadcrumbSeparator joins the file and headings in a rendered breadcrumb.
+const breadcrumbSeparator = " › "
+
 // ProcessResults processes raw search hits into cleaner, merged results.
 func (p *ResultProcessor) ProcessResults(hits []SearchHit) []ProcessedResult {
 	if len(hits) == 0 {
@@ -56,117 +121,268 @@ func (p *ResultProcessor) ProcessResults(hits []SearchHit) []ProcessedResult {
 
 	var results []ProcessedResult
 	for _, docID := range docOrder {
-		docHits := byDoc[docID]
-		if len(docHits) == 0 {
-			continue
+		// Each run of adjacent chunks becomes one passage; the first hit of
+		// a passage is its best-ranked one
+		for _, passage := range splitPassages(byDoc[docID]) {
+			results = append(results, p.processPassage(passage))
 		}
+	}
+
+	// Sort by score (best first)
+	sort.SliceStable(results, func(i, j int) bool {
+		return p.better(results[i].Score, results[j].Score)
+	})
 
-		// Merge chunks from same document
-		merged := p.mergeChunks(docHits)
+	results = p.collapseDuplicates(results)
+	for i := range results {
+		results[i].Content = HighlightTerms(results[i].Content, p.query, p.highlight)
+	}
+	return results
+}
 
-		// Filter boilerplate
-		cleaned := p.filterBoilerplate(merged)
+// processPassage builds the result for one passage of a document: its hits
+// stitched into continuous text, scored as its best-ranked hit.
+func (p *ResultProcessor) processPassage(passage []SearchHit) ProcessedResult {
+	best := passage[0]
+
+	result := ProcessedResult{
+		DocumentID: best.DocumentID,
+		Path:       best.Path,
+		Title:      best.Title,
+		Content:    p.filterBoilerplate(p.mergeChunks(passage)),
+		Score:      best.Score,
+		RawScore:   best.RawScore,
+		ChunkCount: len(passage),
+		Metadata:   best.Metadata,
+		Source:     sectionInfo(passage),
+	}
 
-		// Calculate average score
-		var totalScore float64
-		for _, h := range docHits {
-			totalScore += h.Score
-		}
-		avgScore := totalScore / float64(len(docHits))
-
-		result := ProcessedResult{
-			DocumentID: docID,
-			Path:       docHits[0].Path,
-			Title:      docHits[0].Title,
-			Content:    cleaned,
-			Score:      avgScore,
-			ChunkCount: len(docHits),
-			Metadata:   docHits[0].Metadata,
-			Source: SourceInfo{
-				File: extractFilename(docHits[0].Path),
-			},
+	for _, h := range passage {
+		if h.Explanation != nil {
+			result.Explanations = append(result.Explanations, h.Explanation)
 		}
+	}
 
-		// Extract page number if available in metadata
-		if page, ok := docHits[0].Metadata["page"]; ok {
-			if p, err := parseInt(page); err == nil {
-				result.Source.Page = p
-			}
+	// Extract page number if available in metadata
+	if page, ok := best.Metadata["page"]; ok {
+		if p, err := parseInt(page); err == nil {
+			result.Source.Page = p
 		}
+	}
 
-		results = append(results, result)
+	// Cite the lines of the whole passage
+	for _, h := range passage {
+		start, end := chunkLines(h)
+		if start == 0 {
+			continue
+		}
+		if result.Source.StartLine == 0 || start < result.Source.StartLine {
+			result.Source.StartLine = start
+		}
+		result.Source.EndLine = max(result.Source.EndLine, end)
 	}
 
-	// Sort by score (best first)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score < results[j].Score // BM25 scores are negative, lower is better
-	})
+	return result
+}
 
-	return results
+// collapseDuplicates merges results whose content is near-identical, keeping
+// the best-scoring copy and recording the other paths as alternates. The
+// kept results stay in the order they first appear.
+func (p *ResultProcessor) collapseDuplicates(results []ProcessedResult) []ProcessedResult {
+	kept := results[:0:0]
+	for _, r := range results {
+		duplicate := false
+		for i := range kept {
+			if kept[i].Path == r.Path || textSimilarity(kept[i].Content, r.Content) < duplicateSimilarity {
+				continue
+			}
+			if p.better(r.Score, kept[i].Score) {
+				r.Alternates = append(r.Alternates, kept[i].Path)
+				r.Alternates = append(r.Alternates, kept[i].Alternates...)
+				kept[i] = r
+			} else {
+				kept[i].Alternates = append(kept[i].Alternates, r.Path)
+				kept[i].Alternates = append(kept[i].Alternates, r.Alternates...)
+			}
+			duplicate = true
+			break
+		}
+		if !duplicate {
+			kept = append(kept, r)
+		}
+	}
+	return kept
 }
 
-// mergeChunks combines adjacent or overlapping chunks from the same document.
-func (p *ResultProcessor) mergeChunks(hits []SearchHit) string {
-	if len(hits) == 0 {
-		return ""
+// sectionInfo builds the citation for a document's hits. The heading path of
+// the best hit (the first one) is used for the breadcrumb, and the paths of
+// all merged chunks are kept so merging does not lose section context.
+func sectionInfo(docHits []SearchHit) SourceInfo {
+	info := SourceInfo{
+		File:    extractFilename(docHits[0].Path),
+		Section: docHits[0].Metadata["section"],
 	}
-	if len(hits) == 1 {
-		return hits[0].Snippet
+
+	if path := docHits[0].Metadata["heading_path"]; path != "" {
+		info.HeadingPath = strings.Split(path, headingPathSeparator)
+		info.Breadcrumb = info.File + breadcrumbSeparator + strings.Join(info.HeadingPath, breadcrumbSeparator)
 	}
 
-	// Sort by chunk position (if available in metadata)
-	sortedHits := make([]SearchHit, len(hits))
-	copy(sortedHits, hits)
-	sort.Slice(sortedHits, func(i, j int) bool {
-		posI := getChunkPosition(sortedHits[i])
-		posJ := getChunkPosition(sortedHits[j])
-		return posI < posJ
+	sorted := make([]SearchHit, len(docHits))
+	copy(sorted, docHits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return getChunkPosition(sorted[i]) < getChunkPosition(sorted[j])
 	})
 
-	// Merge with overlap detection
-	var parts []string
-	for _, hit := range sortedHits {
-		parts = append(parts, hit.Snippet)
+	seen := make(map[string]bool)
+	for _, h := range sorted {
+		if path := h.Metadata["heading_path"]; path != "" && !seen[path] {
+			seen[path] = true
+			info.Sections = append(info.Sections, path)
+		}
+	}
+	if len(info.Sections) < 2 {
+		info.Sections = nil
 	}
 
-	return removeOverlaps(parts)
+	return info
 }
 
-// removeOverlaps merges text parts by detecting and removing overlapping content.
-func removeOverlaps(parts []string) string {
-	if len(parts) == 0 {
-		return ""
-	}
-	if len(parts) == 1 {
-		return parts[0]
+// maxChunkGap is how many characters may separate two chunks that are still
+// adjacent: the whitespace the chunker trims at their boundary.
+const maxChunkGap = 8
+
+// minStitchOverlap is the shortest repeated text taken as chunk overlap;
+// shorter matches are likely coincidence.
+const minStitchOverlap = 10
+
+// splitPassages groups a document's hits, given in rank order, into runs of
+// adjacent chunks. Hits keep their rank order within a passage, and
+// passages are ordered by their best hit.
+func splitPassages(hits []SearchHit) [][]SearchHit {
+	order := make([]int, len(hits))
+	for i := range order {
+		order[i] = i
 	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return getChunkPosition(hits[order[a]]) < getChunkPosition(hits[order[b]])
+	})
 
-	result := strings.TrimSpace(parts[0])
-	for i := 1; i < len(parts); i++ {
-		next := strings.TrimSpace(parts[i])
-		if next == "" {
-			continue
+	var runs [][]int
+	for _, i := range order {
+		if n := len(runs); n > 0 && adjacentChunks(hits, runs[n-1], hits[i]) {
+			runs[n-1] = append(runs[n-1], i)
+		} else {
+			runs = append(runs, []int{i})
 		}
+	}
 
-		// Find overlap between end of result and start of next part
-		overlapLen := 0
-		maxOverlap := min(len(result), len(next), 150) // Check up to 150 chars
+	for _, run := range runs {
+		sort.Ints(run)
+	}
+	sort.Slice(runs, func(a, b int) bool { return runs[a][0] < runs[b][0] })
 
-		for j := 10; j <= maxOverlap; j++ { // Start at 10 chars to avoid false positives
-			if strings.HasSuffix(result, next[:j]) {
-				overlapLen = j
+	passages := make([][]SearchHit, len(runs))
+	for i, run := range runs {
+		for _, j := range run {
+			passages[i] = append(passages[i], hits[j])
+		}
+	}
+	return passages
+}
+
+// adjacentChunks reports whether next, the chunk after run in document
+// order, continues it: overlapping or directly following its text when
+// offsets are known, or the next chunk index otherwise.
+func adjacentChunks(hits []SearchHit, run []int, next SearchHit) bool {
+	if nextStart, _, ok := chunkSpan(next); ok {
+		runEnd := -1
+		for _, i := range run {
+			if _, end, ok := chunkSpan(hits[i]); ok {
+				runEnd = max(runEnd, end)
 			}
 		}
+		return runEnd >= 0 && nextStart <= runEnd+maxChunkGap
+	}
 
-		if overlapLen > 0 {
-			result += " " + next[overlapLen:]
-		} else {
-			// No overlap detected, add separator
-			result += "\n\n" + next
+	last, err := strconv.Atoi(hits[run[len(run)-1]].Metadata["chunk_index"])
+	if err != nil {
+		return false
+	}
+	index, err := strconv.Atoi(next.Metadata["chunk_index"])
+	return err == nil && index <= last+1
+}
+
+// chunkSpan returns the character offsets of a hit's chunk in its
+// document's indexed text, if recorded.
+func chunkSpan(hit SearchHit) (start, end int, ok bool) {
+	start, err := strconv.Atoi(hit.Metadata["start_char"])
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err = strconv.Atoi(hit.Metadata["end_char"])
+	if err != nil || end <= start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// mergeChunks stitches a passage's chunks into one continuous text in
+// document order, dropping the overlap the chunker repeats between
+// neighbours. Full chunk content is used where available so the stitched
+// text has no gaps; a lone hit keeps its snippet.
+func (p *ResultProcessor) mergeChunks(passage []SearchHit) string {
+	if len(passage) == 1 {
+		return cleanWhitespace(passage[0].Snippet)
+	}
+
+	chunks := make([]SearchHit, len(passage))
+	copy(chunks, passage)
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return getChunkPosition(chunks[i]) < getChunkPosition(chunks[j])
+	})
+
+	merged := chunkText(chunks[0])
+	_, prevEnd, prevOK := chunkSpan(chunks[0])
+	for _, chunk := range chunks[1:] {
+		start, end, ok := chunkSpan(chunk)
+		overlap := 0
+		if prevOK && ok {
+			overlap = prevEnd - start
+		}
+		merged = stitchChunks(merged, chunkText(chunk), overlap)
+		if ok {
+			prevEnd, prevOK = max(prevEnd, end), true
 		}
 	}
+	return cleanWhitespace(merged)
+}
 
-	return result
+// chunkText returns a hit's full chunk content, or its snippet when the
+// content was not carried along.
+func chunkText(hit SearchHit) string {
+	if hit.Content != "" {
+		return hit.Content
+	}
+	return hit.Snippet
+}
+
+// stitchChunks appends next to text, dropping the longest prefix of next
+// that text already ends with. expected is the overlap the offsets
+// suggest; matches are looked for a little beyond it since the chunker
+// trims whitespace at boundaries. A chunk text already contains adds
+// nothing.
+func stitchChunks(text, next string, expected int) string {
+	limit := min(len(text), len(next), max(expected+maxChunkGap, 150))
+	for k := limit; k >= minStitchOverlap; k-- {
+		if strings.HasSuffix(text, next[:k]) {
+			return text + next[k:]
+		}
+	}
+	if strings.Contains(text, next) {
+		return text
+	}
+	return text + "\n\n" + next
 }
 
 // filterBoilerplate removes common boilerplate patterns from content.
@@ -230,19 +446,36 @@ func cleanWhitespace(s string) string {
 	return strings.TrimSpace(s)
 }
 
-// getChunkPosition extracts chunk index from metadata.
+// getChunkPosition returns a sort key for the chunk's place in its document:
+// the chunk index if recorded in metadata, otherwise its character offset.
 func getChunkPosition(hit SearchHit) int {
 	if hit.Metadata == nil {
 		return 0
 	}
-	if idx, ok := hit.Metadata["chunk_index"]; ok {
-		if pos, err := parseInt(idx); err == nil {
-			return pos
+	for _, key := range []string{"chunk_index", "start_char"} {
+		if v, ok := hit.Metadata[key]; ok {
+			if pos, err := strconv.Atoi(v); err == nil {
+				return pos
+			}
 		}
 	}
 	return 0
 }
 
+// chunkLines returns the file lines a hit's chunk spans, or zeros when the
+// chunk has no line numbers.
+func chunkLines(hit SearchHit) (start, end int) {
+	start, err := strconv.Atoi(hit.Metadata["start_line"])
+	if err != nil || start <= 0 {
+		return 0, 0
+	}
+	end, err = strconv.Atoi(hit.Metadata["end_line"])
+	if err != nil || end < start {
+		end = start
+	}
+	return start, end
+}
+
 // extractFilename extracts just the filename from a path.
 func extractFilename(path string) string {
 	parts := strings.Split(path, "/")
@@ -291,6 +524,10 @@ func (p *ResultProcessor) FormatForPrompt(results []ProcessedResult) string {
 			sb.WriteString(strings.Trim(strings.Replace(strings.Trim(strings.Replace(strings.Trim(strings.Replace(string(rune(r.Source.Page+'0')), "\n", "", -1), " "), "\t", "", -1), " "), "\r", "", -1), " "))
 			sb.WriteString(")")
 		}
+		if r.Source.StartLine > 0 {
+			sb.WriteString(", lines ")
+			sb.WriteString(formatLineRange(r.Source.StartLine, r.Source.EndLine))
+		}
 		sb.WriteString("*\n\n")
 		sb.WriteString(r.Content)
 		sb.WriteString("\n")
@@ -302,3 +539,11 @@ func (p *ResultProcessor) FormatForPrompt(results []ProcessedResult) string {
 
 	return sb.String()
 }
+
+// formatLineRange renders a line span as "12" or "12-18".
+func formatLineRange(start, end int) string {
+	if end <= start {
+		return strconv.Itoa(start)
+	}
+	return strconv.Itoa(start) + "-" + strconv.Itoa(end)
+}
@@ -0,0 +1,257 @@
+package kb
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestResultProcessor_HeadingContextSurvivesMerge(t *testing.T) {
+	p := NewResultProcessor()
+
+	hits := []SearchHit{
+		{
+			DocumentID: "doc1", ChunkID: "c2", Path: "/repo/README.md", Title: "README",
+			Snippet: "Run the image with docker run.", Score: 0.9,
+			Metadata: map[string]string{"chunk_index": "2", "section": "Docker", "heading_path": "Installation > Docker"},
+		},
+		{
+			DocumentID: "doc1", ChunkID: "c1", Path: "/repo/README.md", Title: "README",
+			Snippet: "Install with go install.", Score: 0.7,
+			Metadata: map[string]string{"chunk_index": "1", "section": "Installation", "heading_path": "Installation"},
+		},
+		{
+			DocumentID: "doc2", ChunkID: "c9", Path: "/repo/notes.txt", Title: "notes",
+			Snippet: "Plain text has no headings.", Score: 0.5,
+		},
+	}
+
+	results := p.ProcessResults(hits)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	var readme, notes ProcessedResult
+	for _, r := range results {
+		switch r.DocumentID {
+		case "doc1":
+			readme = r
+		case "doc2":
+			notes = r
+		}
+	}
+
+	if readme.ChunkCount != 2 {
+		t.Fatalf("expected README chunks to be merged, got %d", readme.ChunkCount)
+	}
+	if got, want := readme.Source.Breadcrumb, "README.md › Installation › Docker"; got != want {
+		t.Errorf("breadcrumb = %q, want %q", got, want)
+	}
+	if got, want := readme.Source.HeadingPath, []string{"Installation", "Docker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("heading path = %v, want %v", got, want)
+	}
+	if readme.Source.Section != "Docker" {
+		t.Errorf("section = %q, want %q", readme.Source.Section, "Docker")
+	}
+	// Sections are listed in document order, not score order
+	if got, want := readme.Source.Sections, []string{"Installation", "Installation > Docker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %v, want %v", got, want)
+	}
+	if readme.Metadata["heading_path"] != "Installation > Docker" {
+		t.Errorf("merged result lost chunk metadata: %v", readme.Metadata)
+	}
+
+	if notes.Source.Breadcrumb != "" || notes.Source.HeadingPath != nil || notes.Source.Sections != nil {
+		t.Errorf("expected no heading context for plain text, got %+v", notes.Source)
+	}
+	if notes.Source.File != "notes.txt" {
+		t.Errorf("file = %q, want notes.txt", notes.Source.File)
+	}
+}
+
+func TestResultProcessor_CollapsesNearDuplicates(t *testing.T) {
+	p := NewResultProcessor()
+
+	text := "Conduit connects AI clients to local MCP servers and a private knowledge base."
+	hits := []SearchHit{
+		{DocumentID: "a", ChunkID: "a1", Path: "/work/guide.pdf", Title: "guide", Snippet: text, Score: -4},
+		{DocumentID: "b", ChunkID: "b1", Path: "/backup/guide.pdf", Title: "guide", Snippet: text, Score: -2},
+		{DocumentID: "c", ChunkID: "c1", Path: "/work/other.md", Title: "other", Snippet: "Something else entirely.", Score: -3},
+	}
+
+	results := p.ProcessResults(hits)
+	if len(results) != 2 {
+		t.Fatalf("expected duplicates to collapse into 2 results, got %d", len(results))
+	}
+	if results[0].Path != "/work/guide.pdf" {
+		t.Errorf("expected the best-ranked copy to be kept, got %s", results[0].Path)
+	}
+	if got, want := results[0].Alternates, []string{"/backup/guide.pdf"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("alternates = %v, want %v", got, want)
+	}
+	if len(results[1].Alternates) != 0 {
+		t.Errorf("unexpected alternates on distinct result: %v", results[1].Alternates)
+	}
+}
+
+func TestResultProcessor_CollapseKeepsHighestHybridScore(t *testing.T) {
+	p := NewResultProcessor()
+	p.SetHigherIsBetter(true)
+
+	text := "Conduit connects AI clients to local MCP servers and a private knowledge base."
+	hits := []SearchHit{
+		{DocumentID: "c", ChunkID: "c1", Path: "/work/other.md", Title: "other", Snippet: "Something else entirely.", Score: 0.02},
+		{DocumentID: "b", ChunkID: "b1", Path: "/backup/guide.pdf", Title: "guide", Snippet: text, Score: 0.01},
+		{DocumentID: "a", ChunkID: "a1", Path: "/work/guide.pdf", Title: "guide", Snippet: text, Score: 0.03},
+	}
+
+	results := p.ProcessResults(hits)
+	if len(results) != 2 {
+		t.Fatalf("expected duplicates to collapse into 2 results, got %d", len(results))
+	}
+	if results[0].Path != "/work/guide.pdf" || results[0].Score != 0.03 {
+		t.Errorf("expected the highest-scoring copy first, got %s (%v)", results[0].Path, results[0].Score)
+	}
+	if got, want := results[0].Alternates, []string{"/backup/guide.pdf"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("alternates = %v, want %v", got, want)
+	}
+	if results[1].Path != "/work/other.md" {
+		t.Errorf("expected the distinct result second, got %s", results[1].Path)
+	}
+
+	// Directly, a better copy found later replaces the kept one
+	collapsed := p.collapseDuplicates([]ProcessedResult{
+		{Path: "/backup/guide.pdf", Content: text, Score: 0.01},
+		{Path: "/work/guide.pdf", Content: text, Score: 0.03},
+	})
+	if len(collapsed) != 1 || collapsed[0].Path != "/work/guide.pdf" {
+		t.Fatalf("expected the higher-scoring copy to be kept, got %+v", collapsed)
+	}
+	if got, want := collapsed[0].Alternates, []string{"/backup/guide.pdf"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("alternates = %v, want %v", got, want)
+	}
+}
+
+func TestResultProcessor_LinesAndDocumentOrder(t *testing.T) {
+	p := NewResultProcessor()
+
+	// No chunk_index: hits are ordered and found adjacent by offset
+	hits := []SearchHit{
+		{
+			DocumentID: "doc1", ChunkID: "c2", Path: "/repo/guide.md", Title: "guide",
+			Snippet: "Second part.", Score: -5,
+			Metadata: map[string]string{"start_char": "130", "end_char": "142", "start_line": "20", "end_line": "24"},
+		},
+		{
+			DocumentID: "doc1", ChunkID: "c1", Path: "/repo/guide.md", Title: "guide",
+			Snippet: "First part.", Score: -3,
+			Metadata: map[string]string{"start_char": "120", "end_char": "131", "start_line": "8", "end_line": "20"},
+		},
+		{
+			DocumentID: "doc2", ChunkID: "c9", Path: "/repo/manual.pdf", Title: "manual",
+			Snippet: "Extracted text has no lines.", Score: -1,
+		},
+	}
+
+	results := p.ProcessResults(hits)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	guide := results[0]
+	if guide.Content != "First part.\n\nSecond part." {
+		t.Errorf("expected chunks in document order, got %q", guide.Content)
+	}
+	// Lines span the whole passage
+	if guide.Source.StartLine != 8 || guide.Source.EndLine != 24 {
+		t.Errorf("lines = %d-%d, want 8-24", guide.Source.StartLine, guide.Source.EndLine)
+	}
+
+	if manual := results[1]; manual.Source.StartLine != 0 || manual.Source.EndLine != 0 {
+		t.Errorf("expected no lines without metadata, got %d-%d", manual.Source.StartLine, manual.Source.EndLine)
+	}
+}
+
+func TestResultProcessor_StitchesAdjacentChunks(t *testing.T) {
+	p := NewResultProcessor()
+
+	// The chunker repeats the end of each chunk at the start of the next
+	first := "Rate limits apply per API token. Each token may send 100 requests per minute."
+	second := "Each token may send 100 requests per minute. Bursts above that are rejected with 429."
+	overlap := len("Each token may send 100 requests per minute.")
+
+	hits := []SearchHit{
+		{
+			DocumentID: "doc1", ChunkID: "c2", Path: "/docs/limits.md", Title: "limits",
+			Snippet: "...rejected with 429.", Content: second, Score: -6,
+			Metadata: map[string]string{"start_char": strconv.Itoa(len(first) - overlap), "end_char": strconv.Itoa(2*len(first) - overlap)},
+		},
+		{
+			DocumentID: "doc1", ChunkID: "c1", Path: "/docs/limits.md", Title: "limits",
+			Snippet: "Rate limits apply...", Content: first, Score: -4,
+			Metadata: map[string]string{"start_char": "0", "end_char": strconv.Itoa(len(first))},
+		},
+	}
+
+	results := p.ProcessResults(hits)
+	if len(results) != 1 {
+		t.Fatalf("expected adjacent chunks to stitch into 1 result, got %d", len(results))
+	}
+	want := "Rate limits apply per API token. Each token may send 100 requests per minute. Bursts above that are rejected with 429."
+	if results[0].Content != want {
+		t.Errorf("content = %q, want %q", results[0].Content, want)
+	}
+	if results[0].ChunkCount != 2 {
+		t.Errorf("chunk count = %d, want 2", results[0].ChunkCount)
+	}
+	if results[0].Score != -6 {
+		t.Errorf("score = %v, want the best chunk's -6", results[0].Score)
+	}
+}
+
+func TestStitchChunks_DropsContainedChunk(t *testing.T) {
+	text := "Rate limits apply per API token. Each token may send 100 requests per minute. Bursts are rejected."
+	if got := stitchChunks(text, "Each token may send 100 requests per minute.", 60); got != text {
+		t.Errorf("contained chunk was repeated: %q", got)
+	}
+	if got := stitchChunks("First part.", "Second part.", 0); got != "First part.\n\nSecond part." {
+		t.Errorf("unrelated chunks = %q", got)
+	}
+}
+
+func TestResultProcessor_SeparatesDistantChunks(t *testing.T) {
+	p := NewResultProcessor()
+
+	hits := []SearchHit{
+		{
+			DocumentID: "doc1", ChunkID: "c9", Path: "/docs/guide.md", Title: "guide",
+			Snippet: "Troubleshooting near the end.", Score: -6,
+			Metadata: map[string]string{"start_char": "9000", "end_char": "9800"},
+		},
+		{
+			DocumentID: "doc2", ChunkID: "d1", Path: "/docs/other.md", Title: "other",
+			Snippet: "Another document.", Score: -5,
+		},
+		{
+			DocumentID: "doc1", ChunkID: "c1", Path: "/docs/guide.md", Title: "guide",
+			Snippet: "Installation at the start.", Score: -2,
+			Metadata: map[string]string{"start_char": "0", "end_char": "800"},
+		},
+	}
+
+	results := p.ProcessResults(hits)
+	if len(results) != 3 {
+		t.Fatalf("expected distant chunks to stay separate, got %d results", len(results))
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Content)
+		if r.ChunkCount != 1 {
+			t.Errorf("%q: chunk count = %d, want 1", r.Content, r.ChunkCount)
+		}
+	}
+	want := []string{"Troubleshooting near the end.", "Another document.", "Installation at the start."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %q, want %q", got, want)
+	}
+}
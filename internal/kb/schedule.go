@@ -0,0 +1,124 @@
+package kb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MinSyncInterval is the shortest interval a source can be synced on.
+const MinSyncInterval = time.Minute
+
+// DefaultSyncInterval is the scheduled sync interval when none is configured.
+const DefaultSyncInterval = time.Hour
+
+// Values accepted by ParseSyncInterval besides durations.
+const (
+	SyncIntervalOff     = "off"     // Never sync the source on a schedule
+	SyncIntervalDefault = "default" // Use the configured default interval
+)
+
+// SetSchedule enables scheduled syncs of active sources every interval,
+// unless a source overrides it (see SetSyncInterval). Intervals below
+// MinSyncInterval are raised to it.
+func (sm *SourceManager) SetSchedule(enabled bool, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	sm.scheduleEnabled = enabled
+	sm.scheduleInterval = max(interval, MinSyncInterval)
+}
+
+// Schedule reports whether scheduled syncs are enabled and their default
+// interval.
+func (sm *SourceManager) Schedule() (bool, time.Duration) {
+	return sm.scheduleEnabled, sm.scheduleInterval
+}
+
+// ParseSyncInterval parses a per-source schedule: a duration of at least
+// MinSyncInterval, "off", or "default" (or empty) to use the configured
+// interval. It returns the interval in seconds for the sync_interval column,
+// with !valid meaning NULL.
+func ParseSyncInterval(value string) (seconds int64, valid bool, err error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", SyncIntervalDefault:
+		return 0, false, nil
+	case SyncIntervalOff:
+		return 0, true, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid sync interval %q: use a duration such as 30m or 6h, %q or %q", value, SyncIntervalOff, SyncIntervalDefault)
+	}
+	if d < MinSyncInterval {
+		return 0, false, fmt.Errorf("sync interval %s is too short: the minimum is %s", d, MinSyncInterval)
+	}
+	return int64(d / time.Second), true, nil
+}
+
+// SetSyncInterval overrides how often a source is synced on a schedule; see
+// ParseSyncInterval for the accepted values.
+func (sm *SourceManager) SetSyncInterval(ctx context.Context, sourceID, value string) error {
+	seconds, valid, err := ParseSyncInterval(value)
+	if err != nil {
+		return err
+	}
+	res, err := sm.db.ExecContext(ctx, `
+		UPDATE kb_sources SET sync_interval = ?, updated_at = datetime('now') WHERE source_id = ?
+	`, sql.NullInt64{Int64: seconds, Valid: valid}, sourceID)
+	if err != nil {
+		return fmt.Errorf("update sync interval: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return fmt.Errorf("source not found: %s", sourceID)
+	}
+	return nil
+}
+
+// DueSources returns the active sources whose scheduled sync is due at now
+// and that are not already syncing.
+func (sm *SourceManager) DueSources(ctx context.Context, now time.Time) ([]*Source, error) {
+	sources, err := sm.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var due []*Source
+	for _, src := range sources {
+		if src.Status == "active" && src.NextSync != nil && !src.NextSync.After(now) && !src.Syncing {
+			due = append(due, src)
+		}
+	}
+	return due, nil
+}
+
+// applySchedule fills in a source's schedule from its sync_interval column.
+// The next sync is one interval after the last attempt, scheduled or not, so
+// a manual sync postpones it; a source never synced is due immediately.
+func (sm *SourceManager) applySchedule(src *Source, interval sql.NullInt64) {
+	switch {
+	case !interval.Valid:
+		src.SyncInterval = ""
+	case interval.Int64 <= 0:
+		src.SyncInterval = SyncIntervalOff
+	default:
+		src.SyncInterval = (time.Duration(interval.Int64) * time.Second).String()
+	}
+
+	// A source in watch mode whose folder can't be watched is synced on the
+	// schedule even when scheduled sync is disabled
+	scheduled := sm.scheduleEnabled || src.WatchStatus == WatchStatusFallback
+	if !scheduled || src.SyncInterval == SyncIntervalOff {
+		return
+	}
+	every := sm.scheduleInterval
+	if interval.Valid {
+		every = time.Duration(interval.Int64) * time.Second
+	}
+	next := src.LastSyncAttempt.Add(every)
+	if src.LastSyncAttempt.IsZero() {
+		next = src.CreatedAt
+	}
+	src.NextSync = &next
+}
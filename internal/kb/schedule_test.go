@@ -0,0 +1,31 @@
+package kb
+
+import "testing"
+
+func TestParseSyncInterval(t *testing.T) {
+	tests := []struct {
+		value   string
+		seconds int64
+		valid   bool
+		wantErr bool
+	}{
+		{"", 0, false, false},
+		{"default", 0, false, false},
+		{"off", 0, true, false},
+		{"OFF", 0, true, false},
+		{"30m", 1800, true, false},
+		{"6h", 21600, true, false},
+		{"30s", 0, false, true},
+		{"often", 0, false, true},
+	}
+	for _, tt := range tests {
+		seconds, valid, err := ParseSyncInterval(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSyncInterval(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if seconds != tt.seconds || valid != tt.valid {
+			t.Errorf("ParseSyncInterval(%q) = %d, %v; want %d, %v", tt.value, seconds, valid, tt.seconds, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package kb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// explainHealthTimeout bounds the semantic health check of ExplainEmpty.
+const explainHealthTimeout = 2 * time.Second
+
+// EmptySearchExplanation explains why a search found nothing: whether
+// anything is indexed, which search legs were available, which filters
+// excluded everything and which query terms match no indexed text.
+type EmptySearchExplanation struct {
+	Query string `json:"query"`
+
+	// What is indexed, across all sources
+	Sources   int `json:"sources"`
+	Documents int `json:"documents"`
+	Chunks    int `json:"chunks"`
+
+	// Search legs. SemanticError says why semantic search is unavailable or
+	// unhealthy; Vectors is only set when it is available.
+	FTSAvailable      bool   `json:"fts_available"`
+	SemanticAvailable bool   `json:"semantic_available"`
+	SemanticError     string `json:"semantic_error,omitempty"`
+	Vectors           int    `json:"vectors,omitempty"`
+
+	// Filters of the search, each with the chunks it lets through on its
+	// own, and the chunks that pass all of them
+	Filters        []FilterExplanation `json:"filters,omitempty"`
+	FilteredChunks int                 `json:"filtered_chunks"`
+
+	// Keyword matches of the whole query and of each term, ignoring filters
+	QueryChunks int               `json:"query_chunks"`
+	Terms       []TermExplanation `json:"terms,omitempty"`
+
+	Reasons     []string `json:"reasons"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// FilterExplanation is one search filter and the chunks that pass it.
+type FilterExplanation struct {
+	Name   string `json:"name"` // "source", "path_prefix", "lang", "mime_type", "modified"
+	Value  string `json:"value"`
+	Chunks int    `json:"chunks"`
+}
+
+// TermExplanation is one query term and the chunks that contain it.
+type TermExplanation struct {
+	Term   string `json:"term"`
+	Chunks int    `json:"chunks"`
+}
+
+// searchFilter is a filter of a search as a SQL condition on the
+// kb_documents alias "d" and the kb_chunks alias "c".
+type searchFilter struct {
+	FilterExplanation
+	cond string
+	args []interface{}
+}
+
+// ExplainEmpty diagnoses a search that returned no results with opts, and
+// suggests how to relax it. It only reads the index, so it is cheap enough
+// to run after any empty search.
+func (hs *HybridSearcher) ExplainEmpty(ctx context.Context, query string, opts HybridSearchOptions) (*EmptySearchExplanation, error) {
+	db := hs.fts.db
+	e := &EmptySearchExplanation{Query: query, Reasons: []string{}, Suggestions: []string{}}
+
+	for _, count := range []struct {
+		dest  *int
+		query string
+	}{
+		{&e.Sources, "SELECT COUNT(*) FROM kb_sources"},
+		{&e.Documents, "SELECT COUNT(*) FROM kb_documents"},
+		{&e.Chunks, "SELECT COUNT(*) FROM kb_chunks"},
+	} {
+		if err := db.QueryRowContext(ctx, count.query).Scan(count.dest); err != nil {
+			return nil, fmt.Errorf("count indexed content: %w", err)
+		}
+	}
+
+	var ftsRows int
+	e.FTSAvailable = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM kb_fts").Scan(&ftsRows) == nil
+
+	if hs.semantic == nil {
+		e.SemanticError = "Qdrant or Ollama not running"
+	} else {
+		e.SemanticAvailable = true
+		healthCtx, cancel := context.WithTimeout(ctx, explainHealthTimeout)
+		if err := hs.semantic.HealthCheck(healthCtx); err != nil {
+			e.SemanticError = err.Error()
+		} else if stats, err := hs.semantic.GetStats(healthCtx); err == nil {
+			e.Vectors = stats.VectorCount
+		}
+		cancel()
+	}
+
+	// Filters, alone and together
+	filters := searchFilters(opts)
+	var conds []string
+	var args []interface{}
+	for i := range filters {
+		n, err := hs.countChunks(ctx, "", filters[i].cond, filters[i].args)
+		if err != nil {
+			return nil, err
+		}
+		filters[i].Chunks = n
+		e.Filters = append(e.Filters, filters[i].FilterExplanation)
+		conds = append(conds, filters[i].cond)
+		args = append(args, filters[i].args...)
+	}
+	filtered, err := hs.countChunks(ctx, "", strings.Join(conds, " AND "), args)
+	if err != nil {
+		return nil, err
+	}
+	e.FilteredChunks = filtered
+
+	// Keyword matches, ignoring filters
+	var queryFiltered int
+	if e.FTSAvailable {
+		if ftsQuery := hs.fts.prepareFTSQuery(query); ftsQuery != "" {
+			e.QueryChunks, _ = hs.countChunks(ctx, ftsQuery, "", nil)
+			queryFiltered, _ = hs.countChunks(ctx, ftsQuery, strings.Join(conds, " AND "), args)
+		}
+		for _, term := range strings.Fields(sanitizeFTSQuery(query)) {
+			ftsTerm := term
+			if len(term) >= 2 && !strings.Contains(term, ".") {
+				ftsTerm += "*"
+			}
+			n, _ := hs.countChunks(ctx, ftsTerm, "", nil)
+			e.Terms = append(e.Terms, TermExplanation{Term: term, Chunks: n})
+		}
+	}
+
+	e.diagnose(opts, queryFiltered)
+	return e, nil
+}
+
+// diagnose fills in the reasons and suggestions from the counts.
+// queryFiltered is the number of chunks matching the query and the filters.
+func (e *EmptySearchExplanation) diagnose(opts HybridSearchOptions, queryFiltered int) {
+	reason := func(format string, a ...interface{}) {
+		e.Reasons = append(e.Reasons, fmt.Sprintf(format, a...))
+	}
+	suggest := func(format string, a ...interface{}) {
+		e.Suggestions = append(e.Suggestions, fmt.Sprintf(format, a...))
+	}
+
+	switch {
+	case e.Sources == 0:
+		reason("No knowledge base sources are configured")
+		suggest("Add a folder with 'conduit kb add <path>', then run 'conduit kb sync'")
+		return
+	case e.Documents == 0:
+		reason("%d source(s) configured, but no documents are indexed", e.Sources)
+		suggest("Run 'conduit kb sync' and check 'conduit kb list' for sync errors")
+		return
+	}
+
+	if !e.FTSAvailable {
+		reason("Keyword search (FTS5) is unavailable")
+		suggest("Rebuild conduit with -tags fts5 and run 'conduit doctor'")
+	}
+	switch {
+	case !e.SemanticAvailable:
+		reason("Semantic search is unavailable (%s), so only keyword matches count", e.SemanticError)
+		suggest("Start Qdrant and Ollama ('conduit doctor' shows what is missing) to also match by meaning")
+	case e.SemanticError != "":
+		reason("Semantic search is unhealthy: %s", e.SemanticError)
+		suggest("Run 'conduit doctor' to check Qdrant and Ollama")
+	case e.Vectors == 0:
+		reason("Semantic search is available but no vectors are indexed")
+		suggest("Run 'conduit kb migrate' to generate vectors for indexed documents")
+	}
+
+	// Filters that exclude everything
+	if len(e.Filters) > 0 && e.FilteredChunks == 0 {
+		excluding := 0
+		for _, f := range e.Filters {
+			if f.Chunks == 0 {
+				excluding++
+				reason("The %s filter %q matches no indexed content", filterLabel(f.Name), f.Value)
+				suggest("Remove or widen the %s filter", filterLabel(f.Name))
+			}
+		}
+		if excluding == 0 {
+			reason("Each filter matches some content, but no content passes all of them")
+			suggest("Drop one of the filters")
+		}
+	} else if len(e.Filters) > 0 && e.QueryChunks > 0 && queryFiltered == 0 {
+		reason("The query matches %d chunk(s), but none pass the filters", e.QueryChunks)
+		suggest("Search again without the filters to see where the matches are")
+	}
+
+	// Query terms
+	if e.FTSAvailable && len(e.Terms) == 0 {
+		reason("The query has no searchable terms")
+		suggest("Search for words or phrases from your documents")
+	}
+	if e.FTSAvailable && e.QueryChunks == 0 && len(e.Terms) > 0 {
+		var missing, found []string
+		for _, t := range e.Terms {
+			if t.Chunks == 0 {
+				missing = append(missing, t.Term)
+			} else {
+				found = append(found, t.Term)
+			}
+		}
+		switch {
+		case len(missing) > 0:
+			reason("No indexed text contains %s", quoteTerms(missing))
+			suggest("Check the spelling of %s or use a synonym", quoteTerms(missing))
+			if len(found) > 0 {
+				suggest("Search for fewer terms: %q", strings.Join(found, " "))
+			}
+		case len(e.Terms) > 1:
+			reason("Every term appears somewhere, but no chunk contains all of them")
+			suggest("Search for fewer terms, or rephrase as a question for semantic search")
+		}
+	}
+
+	if opts.SimilarityFloor > 0 {
+		suggest("Lower the minimum score (currently %.4g) with --min-score 0", opts.SimilarityFloor)
+	}
+	if len(e.Suggestions) == 0 {
+		suggest("Try different search terms")
+	}
+}
+
+// searchFilters returns the filters of a search.
+func searchFilters(opts HybridSearchOptions) []searchFilter {
+	var filters []searchFilter
+	add := func(name, value, cond string, args []interface{}) {
+		if cond != "" {
+			filters = append(filters, searchFilter{FilterExplanation{Name: name, Value: value}, cond, args})
+		}
+	}
+
+	cond, args := inCondition("d.source_id", opts.SourceIDs)
+	add("source", strings.Join(opts.SourceIDs, ","), cond, args)
+	cond, args = inCondition("d.mime_type", opts.MimeTypes)
+	add("mime_type", strings.Join(opts.MimeTypes, ","), cond, args)
+	cond, args = pathPrefixCondition(opts.PathPrefix)
+	add("path_prefix", opts.PathPrefix, cond, args)
+	cond, args = languageCondition(opts.Languages)
+	add("lang", strings.Join(opts.Languages, ","), cond, args)
+	if !opts.ModifiedAfter.IsZero() || !opts.ModifiedBefore.IsZero() {
+		cond, args = documentConditions("", opts.ModifiedAfter, opts.ModifiedBefore)
+		add("modified", modifiedRange(opts.ModifiedAfter, opts.ModifiedBefore), cond, args)
+	}
+	return filters
+}
+
+// inCondition returns a SQL condition that column is one of values.
+func inCondition(column string, values []string) (string, []interface{}) {
+	if len(values) == 0 {
+		return "", nil
+	}
+	placeholders := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		placeholders[i] = "?"
+		args[i] = v
+	}
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ",")), args
+}
+
+// countChunks counts the chunks matching an FTS5 query (if not empty) and a
+// condition on the kb_documents alias "d" and kb_chunks alias "c".
+func (hs *HybridSearcher) countChunks(ctx context.Context, ftsQuery, cond string, condArgs []interface{}) (int, error) {
+	var args []interface{}
+	sql := `
+		SELECT COUNT(*)
+		FROM kb_chunks c
+		JOIN kb_documents d ON c.document_id = d.document_id
+	`
+	var where []string
+	if ftsQuery != "" {
+		sql = `
+			SELECT COUNT(*)
+			FROM kb_fts f
+			JOIN kb_chunks c ON f.chunk_id = c.chunk_id
+			JOIN kb_documents d ON f.document_id = d.document_id
+		`
+		where = append(where, "kb_fts MATCH ?")
+		args = append(args, ftsQuery)
+	}
+	if cond != "" {
+		where = append(where, cond)
+		args = append(args, condArgs...)
+	}
+	if len(where) > 0 {
+		sql += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var n int
+	if err := hs.fts.db.QueryRowContext(ctx, sql, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count chunks: %w", err)
+	}
+	return n, nil
+}
+
+// filterLabel names a filter for reasons and suggestions.
+func filterLabel(name string) string {
+	switch name {
+	case "path_prefix":
+		return "path prefix"
+	case "lang":
+		return "language"
+	case "mime_type":
+		return "MIME type"
+	case "modified":
+		return "modification date"
+	}
+	return name
+}
+
+// modifiedRange renders a modification time filter.
+func modifiedRange(after, before time.Time) string {
+	const layout = "2006-01-02 15:04"
+	switch {
+	case before.IsZero():
+		return "after " + after.Local().Format(layout)
+	case after.IsZero():
+		return "before " + before.Local().Format(layout)
+	}
+	return after.Local().Format(layout) + " to " + before.Local().Format(layout)
+}
+
+// quoteTerms renders terms as a quoted, comma-separated list.
+func quoteTerms(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = fmt.Sprintf("%q", t)
+	}
+	return strings.Join(quoted, ", ")
+}
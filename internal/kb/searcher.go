@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,9 +34,20 @@ type SearchOptions struct {
 	Offset     int      // Pagination offset
 	SourceIDs  []string // Filter by source IDs
 	MimeTypes  []string // Filter by MIME types
+	PathPrefix string   // Filter by document path prefix (absolute, or relative to the source root)
+	Languages  []string // Filter by chunk language (see LanguageForPath)
 	MinScore   float64  // Minimum BM25 score threshold
-	Highlight  bool     // Include highlighted snippets
-	ContextLen int      // Characters of context around matches
+	Highlight  bool     // Cut snippets around the first matching term
+	ContextLen int      // Characters of context on each side of the match
+
+	// ModifiedAfter and ModifiedBefore restrict results to documents whose
+	// modification time falls in [ModifiedAfter, ModifiedBefore). Zero means unbounded.
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+
+	// Alternatives are other phrasings of the query; chunks matching any
+	// of them match too (see HybridSearchOptions.ExpandEntities)
+	Alternatives []string
 }
 
 // Search performs a full-text search.
@@ -45,11 +58,14 @@ func (s *Searcher) Search(ctx context.Context, query string, opts SearchOptions)
 		opts.Limit = 10
 	}
 	if opts.ContextLen <= 0 {
-		opts.ContextLen = 150
+		opts.ContextLen = DefaultSnippetLength / 2
 	}
 
 	// Prepare query for FTS5
 	ftsQuery := s.prepareFTSQuery(query)
+	if ftsQuery != "" && len(opts.Alternatives) > 0 {
+		ftsQuery = s.alternativesFTSQuery(ftsQuery, opts.Alternatives)
+	}
 
 	// Build the search SQL
 	sql, args := s.buildSearchSQL(ftsQuery, opts)
@@ -65,17 +81,21 @@ func (s *Searcher) Search(ctx context.Context, query string, opts SearchOptions)
 		var hit SearchHit
 		var score float64
 		var metadata string
+		var startChar, endChar int
 
 		if err := rows.Scan(
 			&hit.DocumentID, &hit.ChunkID, &hit.Path, &hit.Title,
-			&hit.Snippet, &score, &metadata,
+			&hit.Snippet, &score, &metadata, &startChar, &endChar,
 		); err != nil {
-			s.logger.Warn().Err(err).Msg("scan search result")
+			s.loggerFor(ctx).Warn().Err(err).Msg("scan search result")
 			continue
 		}
 
 		hit.Score = score
+		hit.RawScore = score
 		json.Unmarshal([]byte(metadata), &hit.Metadata)
+		hit.Metadata = withChunkOffsets(hit.Metadata, startChar, endChar)
+		hit.Content = hit.Snippet
 
 		// Generate snippet if highlighting is enabled
 		if opts.Highlight {
@@ -99,7 +119,7 @@ func (s *Searcher) Search(ctx context.Context, query string, opts SearchOptions)
 		SearchTime: float64(time.Since(start).Milliseconds()),
 	}
 
-	s.logger.Debug().
+	s.loggerFor(ctx).Debug().
 		Str("query", query).
 		Int("hits", len(hits)).
 		Int("total", totalHits).
@@ -109,6 +129,19 @@ func (s *Searcher) Search(ctx context.Context, query string, opts SearchOptions)
 	return result, nil
 }
 
+// withChunkOffsets returns metadata with the chunk's character offsets in
+// its document's indexed text, under "start_char" and "end_char". The map is
+// copied so metadata shared with a cache or vector payload is not modified.
+func withChunkOffsets(metadata map[string]string, startChar, endChar int) map[string]string {
+	out := make(map[string]string, len(metadata)+2)
+	for k, v := range metadata {
+		out[k] = v
+	}
+	out["start_char"] = strconv.Itoa(startChar)
+	out["end_char"] = strconv.Itoa(endChar)
+	return out
+}
+
 // prepareFTSQuery prepares a query string for FTS5.
 // This sanitizes the query to prevent FTS5 syntax errors from special characters.
 func (s *Searcher) prepareFTSQuery(query string) string {
@@ -145,6 +178,18 @@ func (s *Searcher) prepareFTSQuery(query string) string {
 	return strings.Join(parts, " ")
 }
 
+// alternativesFTSQuery combines a prepared FTS5 query with the alternative
+// phrasings of the query, so that chunks matching any of them match.
+func (s *Searcher) alternativesFTSQuery(ftsQuery string, alternatives []string) string {
+	parts := []string{"(" + ftsQuery + ")"}
+	for _, alt := range alternatives {
+		if q := s.prepareFTSQuery(alt); q != "" {
+			parts = append(parts, "("+q+")")
+		}
+	}
+	return strings.Join(parts, " OR ")
+}
+
 // sanitizeFTSQuery removes or escapes FTS5 special characters to prevent syntax errors.
 // FTS5 has several special characters that can cause syntax errors:
 // - Double quotes (") - phrase delimiters
@@ -216,7 +261,9 @@ func (s *Searcher) buildSearchSQL(ftsQuery string, opts SearchOptions) (string,
 			f.title,
 			f.content as snippet,
 			bm25(kb_fts, 1.0, 0.75, 0.5) as score,
-			COALESCE(c.metadata, '{}') as metadata
+			COALESCE(c.metadata, '{}') as metadata,
+			COALESCE(c.start_char, 0),
+			COALESCE(c.end_char, 0)
 		FROM kb_fts f
 		JOIN kb_chunks c ON f.chunk_id = c.chunk_id
 		JOIN kb_documents d ON f.document_id = d.document_id
@@ -243,6 +290,18 @@ func (s *Searcher) buildSearchSQL(ftsQuery string, opts SearchOptions) (string,
 		sql += fmt.Sprintf(" AND d.mime_type IN (%s)", strings.Join(placeholders, ","))
 	}
 
+	// Add path prefix and modification time filters
+	if cond, condArgs := documentConditions(opts.PathPrefix, opts.ModifiedAfter, opts.ModifiedBefore); cond != "" {
+		sql += " AND " + cond
+		args = append(args, condArgs...)
+	}
+
+	// Add language filter
+	if cond, condArgs := languageCondition(opts.Languages); cond != "" {
+		sql += " AND " + cond
+		args = append(args, condArgs...)
+	}
+
 	// Add score threshold
 	if opts.MinScore > 0 {
 		sql += " AND bm25(kb_fts, 1.0, 0.75, 0.5) < ?"
@@ -250,7 +309,7 @@ func (s *Searcher) buildSearchSQL(ftsQuery string, opts SearchOptions) (string,
 	}
 
 	// Order by relevance (BM25 returns negative, so ASC for highest relevance)
-	sql += " ORDER BY score ASC"
+	sql += " ORDER BY score ASC, f.chunk_id ASC"
 
 	// Add pagination
 	sql += " LIMIT ? OFFSET ?"
@@ -267,6 +326,7 @@ func (s *Searcher) countResults(ctx context.Context, ftsQuery string, opts Searc
 	sql := `
 		SELECT COUNT(*)
 		FROM kb_fts f
+		JOIN kb_chunks c ON f.chunk_id = c.chunk_id
 		JOIN kb_documents d ON f.document_id = d.document_id
 		WHERE kb_fts MATCH ?
 	`
@@ -289,11 +349,80 @@ func (s *Searcher) countResults(ctx context.Context, ftsQuery string, opts Searc
 		sql += fmt.Sprintf(" AND d.mime_type IN (%s)", strings.Join(placeholders, ","))
 	}
 
+	// Add path prefix and modification time filters
+	if cond, condArgs := documentConditions(opts.PathPrefix, opts.ModifiedAfter, opts.ModifiedBefore); cond != "" {
+		sql += " AND " + cond
+		args = append(args, condArgs...)
+	}
+
+	// Add language filter
+	if cond, condArgs := languageCondition(opts.Languages); cond != "" {
+		sql += " AND " + cond
+		args = append(args, condArgs...)
+	}
+
 	var count int
 	s.db.QueryRowContext(ctx, sql, args...).Scan(&count)
 	return count
 }
 
+// pathPrefixCondition returns a SQL condition on the kb_documents alias "d"
+// restricting results to documents under prefix. An absolute prefix is matched
+// against the document path; a relative one is matched against the path
+// relative to the document's source root, so "docs/api" works for any source.
+func pathPrefixCondition(prefix string) (string, []interface{}) {
+	if prefix == "" {
+		return "", nil
+	}
+	if filepath.IsAbs(prefix) {
+		return "substr(d.path, 1, length(?)) = ?", []interface{}{prefix, prefix}
+	}
+	prefix = strings.TrimPrefix(filepath.ToSlash(prefix), "./")
+	return `EXISTS (
+			SELECT 1 FROM kb_sources s
+			WHERE s.source_id = d.source_id
+			AND substr(d.path, length(s.path) + 2, length(?)) = ?
+		)`, []interface{}{prefix, prefix}
+}
+
+// languageCondition returns a SQL condition on the kb_chunks alias "c"
+// restricting results to chunks in any of languages.
+func languageCondition(languages []string) (string, []interface{}) {
+	if len(languages) == 0 {
+		return "", nil
+	}
+	placeholders := make([]string, len(languages))
+	args := make([]interface{}, len(languages))
+	for i, lang := range languages {
+		placeholders[i] = "?"
+		args[i] = lang
+	}
+	return fmt.Sprintf("json_extract(c.metadata, '$.language') IN (%s)", strings.Join(placeholders, ",")), args
+}
+
+// documentConditions combines the document-level filters shared by the FTS5
+// and semantic searches into one SQL condition on the kb_documents alias "d".
+func documentConditions(pathPrefix string, modifiedAfter, modifiedBefore time.Time) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+
+	if cond, condArgs := pathPrefixCondition(pathPrefix); cond != "" {
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+	// modified_at is stored in local time without a zone, as written by the indexer
+	if !modifiedAfter.IsZero() {
+		conds = append(conds, "d.modified_at >= ?")
+		args = append(args, modifiedAfter.Local().Format("2006-01-02 15:04:05"))
+	}
+	if !modifiedBefore.IsZero() {
+		conds = append(conds, "d.modified_at < ?")
+		args = append(args, modifiedBefore.Local().Format("2006-01-02 15:04:05"))
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
 // highlightSnippet creates a highlighted snippet around matching terms.
 func (s *Searcher) highlightSnippet(content, query string, contextLen int) string {
 	terms := strings.Fields(strings.ToLower(query))
@@ -440,3 +569,9 @@ func (s *Searcher) Suggest(ctx context.Context, prefix string, limit int) ([]str
 
 	return suggestions, rows.Err()
 }
+
+// loggerFor returns the searcher's logger tagged with the request ID in ctx.
+func (s *Searcher) loggerFor(ctx context.Context) *zerolog.Logger {
+	logger := observability.LoggerFromContext(ctx, s.logger)
+	return &logger
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -54,8 +55,14 @@ type SemanticSearchOptions struct {
 	Offset     int      // Pagination offset
 	SourceIDs  []string // Filter by source IDs
 	MimeTypes  []string // Filter by MIME types (fetched from metadata)
+	PathPrefix string   // Filter by document path prefix (see SearchOptions)
+	Languages  []string // Filter by chunk language (see SearchOptions)
 	MinScore   float64  // Minimum similarity score threshold (0-1)
-	ContextLen int      // Characters of context for snippets
+	ContextLen int      // Snippet length in characters
+
+	// ModifiedAfter and ModifiedBefore bound document modification time (see SearchOptions)
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
 }
 
 // SemanticSearchResult contains semantic search results.
@@ -77,6 +84,7 @@ type SemanticSearchHit struct {
 	Score        float64           `json:"score"`       // Similarity score (0-1)
 	Confidence   string            `json:"confidence"`  // "high", "medium", "low"
 	Metadata     map[string]string `json:"metadata,omitempty"`
+	Content      string            `json:"-"` // Whole chunk text (see SearchHit.Content)
 }
 
 // Search performs a semantic search using the query text.
@@ -87,7 +95,7 @@ func (ss *SemanticSearcher) Search(ctx context.Context, query string, opts Seman
 		opts.Limit = 10
 	}
 	if opts.ContextLen <= 0 {
-		opts.ContextLen = 300
+		opts.ContextLen = DefaultSnippetLength
 	}
 	// MinScore of 0 is valid (no filtering) - only set default if negative
 	if opts.MinScore < 0 {
@@ -105,8 +113,19 @@ func (ss *SemanticSearcher) Search(ctx context.Context, query string, opts Seman
 		Limit:     opts.Limit * 2, // Fetch more to filter
 		Offset:    opts.Offset,
 		SourceIDs: opts.SourceIDs,
+		Languages: opts.Languages,
 		MinScore:  opts.MinScore,
 	}
+	if cond, args := documentConditions(opts.PathPrefix, opts.ModifiedAfter, opts.ModifiedBefore); cond != "" {
+		docIDs, err := ss.documentIDsMatching(ctx, cond, args)
+		if err != nil {
+			return nil, fmt.Errorf("resolve document filters: %w", err)
+		}
+		if len(docIDs) == 0 {
+			return &SemanticSearchResult{Query: query, Results: []SemanticSearchHit{}}, nil
+		}
+		vectorOpts.DocumentIDs = docIDs
+	}
 
 	vectorResults, err := ss.vectorStore.Search(ctx, queryEmbedding, vectorOpts)
 	if err != nil {
@@ -134,6 +153,7 @@ func (ss *SemanticSearcher) Search(ctx context.Context, query string, opts Seman
 			Score:      float64(vr.Score),
 			Confidence: ss.scoreToConfidence(vr.Score),
 			Metadata:   vr.Metadata,
+			Content:    vr.Content,
 		}
 
 		hits = append(hits, hit)
@@ -142,6 +162,7 @@ func (ss *SemanticSearcher) Search(ctx context.Context, query string, opts Seman
 			break
 		}
 	}
+	ss.attachChunkOffsets(ctx, hits)
 
 	result := &SemanticSearchResult{
 		Results:    hits,
@@ -150,7 +171,7 @@ func (ss *SemanticSearcher) Search(ctx context.Context, query string, opts Seman
 		SearchTime: float64(time.Since(start).Milliseconds()),
 	}
 
-	ss.logger.Debug().
+	ss.loggerFor(ctx).Debug().
 		Str("query", query).
 		Int("hits", len(hits)).
 		Float64("time_ms", result.SearchTime).
@@ -184,8 +205,19 @@ func (ss *SemanticSearcher) SearchSimilar(ctx context.Context, documentID string
 		Limit:     opts.Limit + 5, // Extra to account for filtering
 		Offset:    opts.Offset,
 		SourceIDs: opts.SourceIDs,
+		Languages: opts.Languages,
 		MinScore:  opts.MinScore,
 	}
+	if cond, args := documentConditions(opts.PathPrefix, opts.ModifiedAfter, opts.ModifiedBefore); cond != "" {
+		docIDs, err := ss.documentIDsMatching(ctx, cond, args)
+		if err != nil {
+			return nil, fmt.Errorf("resolve document filters: %w", err)
+		}
+		if len(docIDs) == 0 {
+			return &SemanticSearchResult{Query: fmt.Sprintf("similar to: %s", documentID), Results: []SemanticSearchHit{}}, nil
+		}
+		vectorOpts.DocumentIDs = docIDs
+	}
 
 	vectorResults, err := ss.vectorStore.Search(ctx, embedding, vectorOpts)
 	if err != nil {
@@ -210,6 +242,7 @@ func (ss *SemanticSearcher) SearchSimilar(ctx context.Context, documentID string
 			Score:      float64(vr.Score),
 			Confidence: ss.scoreToConfidence(vr.Score),
 			Metadata:   vr.Metadata,
+			Content:    vr.Content,
 		}
 
 		hits = append(hits, hit)
@@ -218,6 +251,7 @@ func (ss *SemanticSearcher) SearchSimilar(ctx context.Context, documentID string
 			break
 		}
 	}
+	ss.attachChunkOffsets(ctx, hits)
 
 	return &SemanticSearchResult{
 		Results:    hits,
@@ -261,6 +295,13 @@ func (ss *SemanticSearcher) IndexDocument(ctx context.Context, doc *Document, ch
 				"mime_type": doc.MimeType,
 			},
 		}
+		// Carry heading context so semantic hits can show breadcrumbs too,
+		// and the language so they can be filtered by it
+		for _, key := range []string{"section", "heading_path", "language"} {
+			if v := chunk.Metadata[key]; v != "" {
+				points[i].Metadata[key] = v
+			}
+		}
 	}
 
 	// Upsert to vector store
@@ -268,7 +309,7 @@ func (ss *SemanticSearcher) IndexDocument(ctx context.Context, doc *Document, ch
 		return fmt.Errorf("failed to upsert vectors: %w", err)
 	}
 
-	ss.logger.Debug().
+	ss.loggerFor(ctx).Debug().
 		Str("document_id", doc.DocumentID).
 		Int("chunks", len(chunks)).
 		Msg("indexed document vectors")
@@ -288,7 +329,7 @@ func (ss *SemanticSearcher) DeleteBySource(ctx context.Context, sourceID string)
 	if err != nil {
 		return 0, err
 	}
-	ss.logger.Info().
+	ss.loggerFor(ctx).Info().
 		Str("source_id", sourceID).
 		Int("deleted", deleted).
 		Msg("deleted source vectors")
@@ -305,6 +346,27 @@ func (ss *SemanticSearcher) getDocumentMimeType(ctx context.Context, documentID
 	return mimeType, err
 }
 
+// documentIDsMatching returns the IDs of documents matching a condition from
+// documentConditions, so the vector search can be filtered in Qdrant rather
+// than after the fact.
+func (ss *SemanticSearcher) documentIDsMatching(ctx context.Context, cond string, args []interface{}) ([]string, error) {
+	rows, err := ss.db.QueryContext(ctx, `SELECT d.document_id FROM kb_documents d WHERE `+cond, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // getDocumentFirstChunk retrieves the first chunk content for a document.
 func (ss *SemanticSearcher) getDocumentFirstChunk(ctx context.Context, documentID string) (string, error) {
 	var content string
@@ -424,72 +486,217 @@ func (ss *SemanticSearcher) Close() error {
 	return ss.vectorStore.Close()
 }
 
+// MigrationStatus reports how many documents have vectors in the vector store.
+type MigrationStatus struct {
+	Total     int `json:"total"`
+	Migrated  int `json:"migrated"`
+	Remaining int `json:"remaining"`
+}
+
+// GetMigrationStatus counts documents with and without vectors. Documents are
+// marked when their vectors are written, by sync or by MigrateFromFTS.
+func GetMigrationStatus(ctx context.Context, db *sql.DB) (*MigrationStatus, error) {
+	var status MigrationStatus
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(vectors_indexed_at) FROM kb_documents
+	`).Scan(&status.Total, &status.Migrated)
+	if err != nil {
+		return nil, fmt.Errorf("count migrated documents: %w", err)
+	}
+	status.Remaining = status.Total - status.Migrated
+	return &status, nil
+}
+
+// EstimateMigrationBytes estimates how much the vector store grows when the
+// documents without vectors are migrated: one vector per chunk plus the chunk
+// text stored in its payload.
+func (ss *SemanticSearcher) EstimateMigrationBytes(ctx context.Context) (int64, error) {
+	var chunks, textBytes int64
+	err := ss.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(LENGTH(c.content)), 0)
+		FROM kb_chunks c
+		JOIN kb_documents d ON d.document_id = c.document_id
+		WHERE d.vectors_indexed_at IS NULL
+	`).Scan(&chunks, &textBytes)
+	if err != nil {
+		return 0, fmt.Errorf("estimate migration size: %w", err)
+	}
+	return chunks*int64(ss.embeddings.Dimension())*4 + textBytes, nil
+}
+
+// ResetMigration clears the vector markers so the next MigrateFromFTS
+// re-embeds every document, e.g. after the vector collection was recreated.
+func ResetMigration(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `UPDATE kb_documents SET vectors_indexed_at = NULL`); err != nil {
+		return fmt.Errorf("reset migration markers: %w", err)
+	}
+	return nil
+}
+
+// markVectorsIndexed records that a document's vectors are in the vector store.
+func markVectorsIndexed(ctx context.Context, db *sql.DB, documentID string) error {
+	_, err := db.ExecContext(ctx, `
+		UPDATE kb_documents SET vectors_indexed_at = datetime('now') WHERE document_id = ?
+	`, documentID)
+	return err
+}
+
 // MigrateFromFTS migrates existing FTS-indexed documents to vector search.
-// This reads all chunks from SQLite and generates embeddings for them.
+// This reads chunks from SQLite and generates embeddings for them. Each
+// document is marked once its vectors are stored, so an interrupted migration
+// resumes with the remaining documents. progressFn receives the number of
+// migrated documents out of the total, including earlier runs.
 func (ss *SemanticSearcher) MigrateFromFTS(ctx context.Context, progressFn func(current, total int)) error {
-	// Count total documents
-	var totalDocs int
-	err := ss.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM kb_documents`).Scan(&totalDocs)
+	status, err := GetMigrationStatus(ctx, ss.db)
 	if err != nil {
-		return fmt.Errorf("failed to count documents: %w", err)
+		return err
 	}
 
-	if totalDocs == 0 {
-		ss.logger.Info().Msg("no documents to migrate")
+	if status.Remaining == 0 {
+		ss.loggerFor(ctx).Info().Int("total", status.Total).Msg("no documents to migrate")
 		return nil
 	}
 
-	ss.logger.Info().Int("total", totalDocs).Msg("starting FTS to vector migration")
+	ss.loggerFor(ctx).Info().
+		Int("total", status.Total).
+		Int("already_migrated", status.Migrated).
+		Int("remaining", status.Remaining).
+		Msg("starting FTS to vector migration")
 
-	// Get all documents
+	// Load the pending documents up front: the store allows a single
+	// connection, so rows cannot stay open while chunks are read.
 	rows, err := ss.db.QueryContext(ctx, `
 		SELECT document_id, source_id, path, title, mime_type
 		FROM kb_documents
+		WHERE vectors_indexed_at IS NULL
 		ORDER BY document_id
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to query documents: %w", err)
 	}
-	defer rows.Close()
-
-	current := 0
+	var pending []Document
 	for rows.Next() {
 		var doc Document
 		if err := rows.Scan(&doc.DocumentID, &doc.SourceID, &doc.Path, &doc.Title, &doc.MimeType); err != nil {
-			ss.logger.Warn().Err(err).Msg("failed to scan document")
+			ss.loggerFor(ctx).Warn().Err(err).Msg("failed to scan document")
 			continue
 		}
+		pending = append(pending, doc)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read documents: %w", err)
+	}
+
+	// Documents go through IndexDocument, so migration uses the same
+	// batched, concurrent embedding requests as sync
+	current, migrated, chunksEmbedded := status.Migrated, 0, 0
+	embedStart := time.Now()
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		doc := &pending[i]
 
 		// Get chunks for this document
 		chunks, err := ss.getDocumentChunks(ctx, doc.DocumentID)
 		if err != nil {
-			ss.logger.Warn().Err(err).Str("doc_id", doc.DocumentID).Msg("failed to get chunks")
+			ss.loggerFor(ctx).Warn().Err(err).Str("doc_id", doc.DocumentID).Msg("failed to get chunks")
 			continue
 		}
 
 		// Index the document
-		if err := ss.IndexDocument(ctx, &doc, chunks); err != nil {
-			ss.logger.Warn().Err(err).Str("doc_id", doc.DocumentID).Msg("failed to index document")
+		if err := ss.IndexDocument(ctx, doc, chunks); err != nil {
+			ss.loggerFor(ctx).Warn().Err(err).Str("doc_id", doc.DocumentID).Msg("failed to index document")
 			continue
 		}
+		if err := markVectorsIndexed(ctx, ss.db, doc.DocumentID); err != nil {
+			ss.loggerFor(ctx).Warn().Err(err).Str("doc_id", doc.DocumentID).Msg("failed to record migrated document")
+		}
 
 		current++
+		migrated++
+		chunksEmbedded += len(chunks)
 		if progressFn != nil {
-			progressFn(current, totalDocs)
+			progressFn(current, status.Total)
 		}
 
-		if current%10 == 0 {
-			ss.logger.Info().
+		if migrated%10 == 0 {
+			ss.loggerFor(ctx).Info().
 				Int("current", current).
-				Int("total", totalDocs).
+				Int("total", status.Total).
+				Float64("chunks_per_sec", perSecond(chunksEmbedded, time.Since(embedStart))).
 				Msg("migration progress")
 		}
 	}
 
-	ss.logger.Info().Int("migrated", current).Msg("FTS to vector migration completed")
+	elapsed := time.Since(embedStart)
+	ss.loggerFor(ctx).Info().
+		Int("migrated", migrated).
+		Int("failed", len(pending)-migrated).
+		Int("chunks", chunksEmbedded).
+		Dur("duration", elapsed).
+		Float64("chunks_per_sec", perSecond(chunksEmbedded, elapsed)).
+		Int("batch_size", ss.embeddings.BatchSize()).
+		Int("concurrency", ss.embeddings.Concurrency()).
+		Msg("FTS to vector migration completed")
 	return nil
 }
 
+// attachChunkOffsets adds each hit's character offsets and line numbers from
+// kb_chunks to its metadata, since vector payloads do not carry them. Hits
+// whose chunk is not found are left unchanged.
+func (ss *SemanticSearcher) attachChunkOffsets(ctx context.Context, hits []SemanticSearchHit) {
+	if len(hits) == 0 {
+		return
+	}
+
+	placeholders := make([]string, len(hits))
+	args := make([]interface{}, len(hits))
+	for i, hit := range hits {
+		placeholders[i] = "?"
+		args[i] = hit.ChunkID
+	}
+
+	rows, err := ss.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT chunk_id, COALESCE(start_char, 0), COALESCE(end_char, 0), COALESCE(metadata, '{}')
+		FROM kb_chunks
+		WHERE chunk_id IN (%s)
+	`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		ss.loggerFor(ctx).Debug().Err(err).Msg("look up chunk offsets")
+		return
+	}
+	defer rows.Close()
+
+	type chunkPosition struct {
+		start, end int
+		metadata   map[string]string
+	}
+	found := make(map[string]chunkPosition, len(hits))
+	for rows.Next() {
+		var chunkID, metadata string
+		var pos chunkPosition
+		if err := rows.Scan(&chunkID, &pos.start, &pos.end, &metadata); err == nil {
+			json.Unmarshal([]byte(metadata), &pos.metadata)
+			found[chunkID] = pos
+		}
+	}
+
+	for i := range hits {
+		pos, ok := found[hits[i].ChunkID]
+		if !ok {
+			continue
+		}
+		hits[i].Metadata = withChunkOffsets(hits[i].Metadata, pos.start, pos.end)
+		for _, key := range []string{"start_line", "end_line"} {
+			if v := pos.metadata[key]; v != "" {
+				hits[i].Metadata[key] = v
+			}
+		}
+	}
+}
+
 // getDocumentChunks retrieves all chunks for a document.
 func (ss *SemanticSearcher) getDocumentChunks(ctx context.Context, documentID string) ([]Chunk, error) {
 	rows, err := ss.db.QueryContext(ctx, `
@@ -521,3 +728,9 @@ func (ss *SemanticSearcher) getDocumentChunks(ctx context.Context, documentID st
 
 	return chunks, rows.Err()
 }
+
+// loggerFor returns the semantic searcher's logger tagged with the request ID in ctx.
+func (ss *SemanticSearcher) loggerFor(ctx context.Context) *zerolog.Logger {
+	logger := observability.LoggerFromContext(ctx, ss.logger)
+	return &logger
+}
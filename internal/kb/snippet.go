@@ -0,0 +1,107 @@
+package kb
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// HighlightStyle selects the markers put around query terms in snippets.
+type HighlightStyle string
+
+const (
+	HighlightNone     HighlightStyle = "none"
+	HighlightMarkdown HighlightStyle = "markdown" // **term**
+	HighlightHTML     HighlightStyle = "html"     // <mark>term</mark>, with the text HTML-escaped
+)
+
+// HighlightStyles lists the accepted highlight styles.
+var HighlightStyles = []HighlightStyle{HighlightNone, HighlightMarkdown, HighlightHTML}
+
+// Snippet length bounds, in characters. Short snippets cut matches off from
+// their context; long ones are no longer snippets.
+const (
+	DefaultSnippetLength = 300
+	MinSnippetLength     = 50
+	MaxSnippetLength     = 4000
+)
+
+// SnippetOptions controls the text returned for each search hit, so FTS,
+// semantic and hybrid results look alike.
+type SnippetOptions struct {
+	Length    int            // Snippet length in characters (default DefaultSnippetLength)
+	Highlight HighlightStyle // Markers around query terms (default none)
+}
+
+// ParseHighlightStyle parses a highlight style name. An empty name is
+// HighlightNone.
+func ParseHighlightStyle(name string) (HighlightStyle, error) {
+	if name == "" {
+		return HighlightNone, nil
+	}
+	for _, style := range HighlightStyles {
+		if HighlightStyle(strings.ToLower(name)) == style {
+			return style, nil
+		}
+	}
+	return "", fmt.Errorf("highlight must be one of none, markdown or html, got %q", name)
+}
+
+// ValidateSnippetOptions checks that a snippet length and highlight style
+// are usable.
+func ValidateSnippetOptions(length int, highlight string) error {
+	if length < MinSnippetLength || length > MaxSnippetLength {
+		return fmt.Errorf("snippet_length must be between %d and %d, got %d", MinSnippetLength, MaxSnippetLength, length)
+	}
+	_, err := ParseHighlightStyle(highlight)
+	return err
+}
+
+// HighlightTerms marks the words of text that start with a query term.
+// With HighlightHTML the whole text is escaped first, so the result is safe
+// to insert into a page.
+func HighlightTerms(text, query string, style HighlightStyle) string {
+	var open, close string
+	switch style {
+	case HighlightMarkdown:
+		open, close = "**", "**"
+	case HighlightHTML:
+		open, close = "<mark>", "</mark>"
+		text = html.EscapeString(text)
+	default:
+		return text
+	}
+
+	re := highlightPattern(query, style == HighlightHTML)
+	if re == nil {
+		return text
+	}
+	return re.ReplaceAllString(text, open+"$0"+close)
+}
+
+// highlightSkipWords are query words not worth marking: search operators
+// and the most common connectives.
+var highlightSkipWords = map[string]bool{"and": true, "or": true, "not": true, "near": true, "the": true, "of": true}
+
+// highlightPattern matches whole words starting with any query term. Terms
+// are escaped the same way as the text they are matched against.
+func highlightPattern(query string, escapeHTML bool) *regexp.Regexp {
+	var alternatives []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(strings.ToLower(query)) {
+		term := strings.Trim(field, `"'.,;:!?()[]{}*`)
+		if len([]rune(term)) < 2 || seen[term] || highlightSkipWords[term] {
+			continue
+		}
+		seen[term] = true
+		if escapeHTML {
+			term = html.EscapeString(term)
+		}
+		alternatives = append(alternatives, regexp.QuoteMeta(term))
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(alternatives, "|") + `)\w*`)
+}
@@ -0,0 +1,86 @@
+package kb
+
+import "testing"
+
+func TestValidateSnippetOptions(t *testing.T) {
+	tests := []struct {
+		length    int
+		highlight string
+		wantErr   bool
+	}{
+		{DefaultSnippetLength, "", false},
+		{MinSnippetLength, "markdown", false},
+		{MaxSnippetLength, "HTML", false},
+		{MinSnippetLength - 1, "none", true},
+		{MaxSnippetLength + 1, "none", true},
+		{0, "none", true},
+		{300, "bold", true},
+	}
+	for _, tt := range tests {
+		err := ValidateSnippetOptions(tt.length, tt.highlight)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSnippetOptions(%d, %q) error = %v, wantErr %v", tt.length, tt.highlight, err, tt.wantErr)
+		}
+	}
+}
+
+func TestHighlightTerms(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		style HighlightStyle
+		want  string
+	}{
+		{
+			name: "markdown marks whole words with the term as prefix", style: HighlightMarkdown,
+			text: "Rate limits apply per token.", query: "rate limit",
+			want: "**Rate** **limits** apply per token.",
+		},
+		{
+			name: "html escapes text and marks terms", style: HighlightHTML,
+			text: "Use <b> & limits", query: "limits",
+			want: "Use &lt;b&gt; &amp; <mark>limits</mark>",
+		},
+		{
+			name: "none leaves text alone", style: HighlightNone,
+			text: "Rate limits", query: "rate",
+			want: "Rate limits",
+		},
+		{
+			name: "operators, quotes and short words are ignored", style: HighlightMarkdown,
+			text: "a cat and a dog", query: `"cat" AND a`,
+			want: "a **cat** and a dog",
+		},
+		{
+			name: "terms do not match inside words", style: HighlightMarkdown,
+			text: "concatenate the cat", query: "cat",
+			want: "concatenate the **cat**",
+		},
+		{
+			name: "regexp characters in terms are literal", style: HighlightMarkdown,
+			text: "c++ and c", query: "c++",
+			want: "**c++** and c",
+		},
+	}
+	for _, tt := range tests {
+		if got := HighlightTerms(tt.text, tt.query, tt.style); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResultProcessor_Highlight(t *testing.T) {
+	p := NewResultProcessor()
+	p.SetHighlight("quota", HighlightHTML)
+
+	results := p.ProcessResults([]SearchHit{
+		{DocumentID: "d1", ChunkID: "c1", Path: "/docs/a.md", Snippet: "The quota resets daily.", Score: -1},
+	})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if want := "The <mark>quota</mark> resets daily."; results[0].Content != want {
+		t.Errorf("content = %q, want %q", results[0].Content, want)
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 
+	"github.com/simpleflo/conduit/internal/diskspace"
 	"github.com/simpleflo/conduit/internal/observability"
 )
 
@@ -27,9 +29,33 @@ type SourceManager struct {
 	cleaner     *ContentCleaner
 	extractors  *ExtractorRegistry
 	logger      zerolog.Logger
-	maxFileSize int64 // Maximum file size to index (default 100MB)
+	maxFileSize int64 // Maximum file size to index (default 5MB)
+
+	// Whether new sources extract entities unless the request says otherwise
+	extractEntitiesDefault bool
+
+	// Free-space check run before indexing (see SetDiskCheck)
+	diskDir     string
+	diskMinFree int64
+
+	// Sources with a sync in progress, counted per source ID
+	mu      sync.Mutex
+	syncing map[string]int
+
+	// Scheduled sync settings (see SetSchedule)
+	scheduleEnabled  bool
+	scheduleInterval time.Duration
+
+	// Watch state of sources in watch mode, guarded by mu: nil while
+	// watched, or why watching failed (see SetWatchState)
+	watchState map[string]error
 }
 
+// indexBytesPerSourceByte estimates how much the index grows per byte of a
+// new source file: document and overlapping chunk text, the FTS5 index and
+// the embeddings.
+const indexBytesPerSourceByte = 4
+
 // NewSourceManager creates a new source manager.
 func NewSourceManager(db *sql.DB) *SourceManager {
 	return &SourceManager{
@@ -39,16 +65,44 @@ func NewSourceManager(db *sql.DB) *SourceManager {
 		cleaner:     NewContentCleaner(),
 		extractors:  NewExtractorRegistry(),
 		logger:      observability.Logger("kb.source"),
-		maxFileSize: 100 * 1024 * 1024, // Default 100MB
+		maxFileSize: DefaultMaxFileSize,
+		syncing:     make(map[string]int),
+		watchState:  make(map[string]error),
+
+		scheduleInterval: DefaultSyncInterval,
 	}
 }
 
-// SetMaxFileSize sets the maximum file size for indexing.
+// SetMaxFileSize sets the maximum file size for indexing. Larger files are
+// skipped during sync. A non-positive size restores the default.
 func (sm *SourceManager) SetMaxFileSize(size int64) {
+	if size <= 0 {
+		size = DefaultMaxFileSize
+	}
 	sm.maxFileSize = size
 	sm.logger.Debug().Int64("max_file_size", size).Msg("max file size set")
 }
 
+// SetExtractEntitiesDefault sets whether new sources have KAG entity
+// extraction enabled when the add request does not specify it.
+func (sm *SourceManager) SetExtractEntitiesDefault(enabled bool) {
+	sm.extractEntitiesDefault = enabled
+}
+
+// SetDiskCheck makes syncs refuse to start when indexing the new files would
+// leave less than minFree bytes free on dir's filesystem. A minFree of zero
+// disables the check.
+func (sm *SourceManager) SetDiskCheck(dir string, minFree int64) {
+	sm.diskDir = dir
+	sm.diskMinFree = minFree
+}
+
+// SetChunkOptions sets the chunk size and overlap used when indexing. Existing
+// documents keep their chunks until the source is reindexed.
+func (sm *SourceManager) SetChunkOptions(size, overlap int) error {
+	return sm.chunker.SetDefaults(size, overlap)
+}
+
 // SetSemanticSearcher enables semantic search for the source manager's indexer.
 // This must be called after NewSourceManager to enable vector-based search.
 func (sm *SourceManager) SetSemanticSearcher(semantic *SemanticSearcher) {
@@ -82,18 +136,20 @@ func (sm *SourceManager) Add(ctx context.Context, req AddSourceRequest) (*Source
 	if len(patterns) == 0 {
 		patterns = DefaultPatterns
 	}
-	excludes := req.Excludes
-	if len(excludes) == 0 {
-		excludes = DefaultExcludes
-	}
-	syncMode := req.SyncMode
-	if syncMode == "" {
-		syncMode = "manual"
+	// Excludes extend the defaults; a "!pattern" re-includes a default
+	excludes := append(append([]string{}, DefaultExcludes...), req.Excludes...)
+	syncMode, err := normalizeSyncMode(req.SyncMode)
+	if err != nil {
+		return nil, err
 	}
 	name := req.Name
 	if name == "" {
 		name = filepath.Base(absPath)
 	}
+	extractEntities := sm.extractEntitiesDefault
+	if req.ExtractEntities != nil {
+		extractEntities = *req.ExtractEntities
+	}
 
 	source := &Source{
 		SourceID:  uuid.New().String(),
@@ -106,6 +162,8 @@ func (sm *SourceManager) Add(ctx context.Context, req AddSourceRequest) (*Source
 		Status:    "active",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+
+		ExtractEntities: extractEntities,
 	}
 
 	patternsJSON, _ := json.Marshal(source.Patterns)
@@ -113,19 +171,20 @@ func (sm *SourceManager) Add(ctx context.Context, req AddSourceRequest) (*Source
 
 	_, err = sm.db.ExecContext(ctx, `
 		INSERT INTO kb_sources
-		(source_id, path, name, type, patterns, excludes, sync_mode, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+		(source_id, path, name, type, patterns, excludes, sync_mode, status, extract_entities, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
 	`, source.SourceID, source.Path, source.Name, source.Type,
-		string(patternsJSON), string(excludesJSON), source.SyncMode, source.Status)
+		string(patternsJSON), string(excludesJSON), source.SyncMode, source.Status, source.ExtractEntities)
 
 	if err != nil {
 		return nil, fmt.Errorf("insert source: %w", err)
 	}
 
-	sm.logger.Info().
+	sm.loggerFor(ctx).Info().
 		Str("source_id", source.SourceID).
 		Str("path", source.Path).
 		Str("name", source.Name).
+		Bool("extract_entities", source.ExtractEntities).
 		Msg("added source")
 
 	return source, nil
@@ -149,46 +208,11 @@ func (sm *SourceManager) Remove(ctx context.Context, sourceID string) (*RemoveRe
 		result.DocumentsDeleted = docCount
 	}
 
-	// Delete vectors from Qdrant FIRST (while we still have source_id reference)
-	// This is done before SQLite deletion so the source_id filter still works
-	vectorsDeleted, err := sm.indexer.DeleteBySource(ctx, sourceID)
+	vectorsDeleted, err := sm.deleteDocuments(ctx, sourceID)
 	if err != nil {
-		// Log warning but continue - SQLite deletion should still proceed
-		sm.logger.Warn().
-			Err(err).
-			Str("source_id", sourceID).
-			Msg("failed to delete vectors, continuing with SQLite cleanup")
-	} else {
-		result.VectorsDeleted = vectorsDeleted
-	}
-
-	// Delete from FTS first (due to foreign key constraints)
-	_, err = sm.db.ExecContext(ctx, `
-		DELETE FROM kb_fts WHERE document_id IN (
-			SELECT document_id FROM kb_documents WHERE source_id = ?
-		)
-	`, sourceID)
-	if err != nil {
-		return nil, fmt.Errorf("delete fts: %w", err)
-	}
-
-	// Delete chunks
-	_, err = sm.db.ExecContext(ctx, `
-		DELETE FROM kb_chunks WHERE document_id IN (
-			SELECT document_id FROM kb_documents WHERE source_id = ?
-		)
-	`, sourceID)
-	if err != nil {
-		return nil, fmt.Errorf("delete chunks: %w", err)
-	}
-
-	// Delete documents
-	_, err = sm.db.ExecContext(ctx, `
-		DELETE FROM kb_documents WHERE source_id = ?
-	`, sourceID)
-	if err != nil {
-		return nil, fmt.Errorf("delete documents: %w", err)
+		return nil, err
 	}
+	result.VectorsDeleted = vectorsDeleted
 
 	// Delete source
 	deleteResult, err := sm.db.ExecContext(ctx, `DELETE FROM kb_sources WHERE source_id = ?`, sourceID)
@@ -201,7 +225,7 @@ func (sm *SourceManager) Remove(ctx context.Context, sourceID string) (*RemoveRe
 		return nil, fmt.Errorf("source not found: %s", sourceID)
 	}
 
-	sm.logger.Info().
+	sm.loggerFor(ctx).Info().
 		Str("source_id", sourceID).
 		Int("documents", result.DocumentsDeleted).
 		Int("vectors", result.VectorsDeleted).
@@ -214,7 +238,8 @@ func (sm *SourceManager) Remove(ctx context.Context, sourceID string) (*RemoveRe
 func (sm *SourceManager) List(ctx context.Context) ([]*Source, error) {
 	rows, err := sm.db.QueryContext(ctx, `
 		SELECT source_id, path, name, type, patterns, excludes, sync_mode, status,
-		       last_sync, doc_count, chunk_count, size_bytes, created_at, updated_at, error
+		       last_sync, doc_count, chunk_count, size_bytes, created_at, updated_at, error,
+		       extract_entities, last_sync_attempt, last_sync_status, sync_interval
 		FROM kb_sources
 		ORDER BY name
 	`)
@@ -228,13 +253,15 @@ func (sm *SourceManager) List(ctx context.Context) ([]*Source, error) {
 		var src Source
 		var patterns, excludes string
 		var lastSync, createdAt, updatedAt sql.NullString
-		var errorMsg sql.NullString
+		var errorMsg, lastAttempt, lastStatus sql.NullString
+		var syncInterval sql.NullInt64
 
 		err := rows.Scan(
 			&src.SourceID, &src.Path, &src.Name, &src.Type,
 			&patterns, &excludes, &src.SyncMode, &src.Status,
 			&lastSync, &src.DocCount, &src.ChunkCount, &src.SizeBytes,
-			&createdAt, &updatedAt, &errorMsg,
+			&createdAt, &updatedAt, &errorMsg, &src.ExtractEntities,
+			&lastAttempt, &lastStatus, &syncInterval,
 		)
 		if err != nil {
 			continue
@@ -242,6 +269,7 @@ func (sm *SourceManager) List(ctx context.Context) ([]*Source, error) {
 
 		json.Unmarshal([]byte(patterns), &src.Patterns)
 		json.Unmarshal([]byte(excludes), &src.Excludes)
+		src.SyncMode = storedSyncMode(src.SyncMode)
 
 		// Normalize patterns to ensure they have * prefix (fixes corrupted data)
 		src.Patterns = normalizePatterns(src.Patterns)
@@ -258,18 +286,63 @@ func (sm *SourceManager) List(ctx context.Context) ([]*Source, error) {
 		if errorMsg.Valid {
 			src.Error = errorMsg.String
 		}
+		if lastAttempt.Valid {
+			src.LastSyncAttempt, _ = time.Parse("2006-01-02 15:04:05", lastAttempt.String)
+		}
+		src.LastSyncStatus = lastStatus.String
+		src.Syncing = sm.isSyncing(src.SourceID)
+		sm.applyWatchState(&src)
+		sm.applySchedule(&src, syncInterval)
 
 		sources = append(sources, &src)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close() // Release the connection before counting entities
+
+	counts, err := sm.entityCounts(ctx)
+	if err != nil {
+		sm.loggerFor(ctx).Debug().Err(err).Msg("failed to count entities per source")
+	}
+	for _, src := range sources {
+		src.EntityCount = counts[src.SourceID]
+	}
+
+	return sources, nil
+}
+
+// entityCounts returns the number of extracted KAG entities per source.
+func (sm *SourceManager) entityCounts(ctx context.Context) (map[string]int, error) {
+	rows, err := sm.db.QueryContext(ctx, `
+		SELECT d.source_id, COUNT(*)
+		FROM kb_entities e
+		JOIN kb_documents d ON e.source_document_id = d.document_id
+		GROUP BY d.source_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return sources, rows.Err()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var sourceID string
+		var count int
+		if err := rows.Scan(&sourceID, &count); err != nil {
+			return nil, err
+		}
+		counts[sourceID] = count
+	}
+	return counts, rows.Err()
 }
 
 // Get returns a source by ID.
 func (sm *SourceManager) Get(ctx context.Context, sourceID string) (*Source, error) {
 	row := sm.db.QueryRowContext(ctx, `
 		SELECT source_id, path, name, type, patterns, excludes, sync_mode, status,
-		       last_sync, doc_count, chunk_count, size_bytes, created_at, updated_at, error
+		       last_sync, doc_count, chunk_count, size_bytes, created_at, updated_at, error,
+		       extract_entities, last_sync_attempt, last_sync_status, sync_interval
 		FROM kb_sources
 		WHERE source_id = ?
 	`, sourceID)
@@ -277,13 +350,15 @@ func (sm *SourceManager) Get(ctx context.Context, sourceID string) (*Source, err
 	var src Source
 	var patterns, excludes string
 	var lastSync, createdAt, updatedAt sql.NullString
-	var errorMsg sql.NullString
+	var errorMsg, lastAttempt, lastStatus sql.NullString
+	var syncInterval sql.NullInt64
 
 	err := row.Scan(
 		&src.SourceID, &src.Path, &src.Name, &src.Type,
 		&patterns, &excludes, &src.SyncMode, &src.Status,
 		&lastSync, &src.DocCount, &src.ChunkCount, &src.SizeBytes,
-		&createdAt, &updatedAt, &errorMsg,
+		&createdAt, &updatedAt, &errorMsg, &src.ExtractEntities,
+		&lastAttempt, &lastStatus, &syncInterval,
 	)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("source not found: %s", sourceID)
@@ -294,6 +369,7 @@ func (sm *SourceManager) Get(ctx context.Context, sourceID string) (*Source, err
 
 	json.Unmarshal([]byte(patterns), &src.Patterns)
 	json.Unmarshal([]byte(excludes), &src.Excludes)
+	src.SyncMode = storedSyncMode(src.SyncMode)
 
 	// Normalize patterns to ensure they have * prefix (fixes corrupted data)
 	src.Patterns = normalizePatterns(src.Patterns)
@@ -310,17 +386,216 @@ func (sm *SourceManager) Get(ctx context.Context, sourceID string) (*Source, err
 	if errorMsg.Valid {
 		src.Error = errorMsg.String
 	}
+	if lastAttempt.Valid {
+		src.LastSyncAttempt, _ = time.Parse("2006-01-02 15:04:05", lastAttempt.String)
+	}
+	src.LastSyncStatus = lastStatus.String
+	src.Syncing = sm.isSyncing(src.SourceID)
+	sm.applyWatchState(&src)
+	sm.applySchedule(&src, syncInterval)
+
+	sm.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM kb_entities e
+		JOIN kb_documents d ON e.source_document_id = d.document_id
+		WHERE d.source_id = ?
+	`, sourceID).Scan(&src.EntityCount)
 
 	return &src, nil
 }
 
+// deleteDocuments removes all documents, chunks, FTS entries and vectors of a
+// source, keeping the source itself. It returns the number of vectors deleted.
+func (sm *SourceManager) deleteDocuments(ctx context.Context, sourceID string) (int, error) {
+	// Delete vectors from Qdrant FIRST (while we still have source_id reference)
+	// This is done before SQLite deletion so the source_id filter still works
+	vectorsDeleted, err := sm.indexer.DeleteBySource(ctx, sourceID)
+	if err != nil {
+		// Log warning but continue - SQLite deletion should still proceed
+		sm.loggerFor(ctx).Warn().
+			Err(err).
+			Str("source_id", sourceID).
+			Msg("failed to delete vectors, continuing with SQLite cleanup")
+	}
+
+	// Delete from FTS first (due to foreign key constraints)
+	_, err = sm.db.ExecContext(ctx, `
+		DELETE FROM kb_fts WHERE document_id IN (
+			SELECT document_id FROM kb_documents WHERE source_id = ?
+		)
+	`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("delete fts: %w", err)
+	}
+
+	// Delete chunks
+	_, err = sm.db.ExecContext(ctx, `
+		DELETE FROM kb_chunks WHERE document_id IN (
+			SELECT document_id FROM kb_documents WHERE source_id = ?
+		)
+	`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("delete chunks: %w", err)
+	}
+
+	// Delete documents
+	_, err = sm.db.ExecContext(ctx, `
+		DELETE FROM kb_documents WHERE source_id = ?
+	`, sourceID)
+	if err != nil {
+		return 0, fmt.Errorf("delete documents: %w", err)
+	}
+
+	return vectorsDeleted, nil
+}
+
+// Reindex drops everything indexed for a source and re-ingests all of its
+// files from disk, keeping the source configuration. Use it after chunking
+// changes or when the FTS or vector index is corrupted.
+func (sm *SourceManager) Reindex(ctx context.Context, sourceID string, opts *SyncOptions) (*SyncResult, error) {
+	return sm.trackSync(ctx, sourceID, func() (*SyncResult, error) {
+		return sm.reindex(ctx, sourceID, opts)
+	})
+}
+
+// reindex clears and re-ingests a source without recording the outcome.
+func (sm *SourceManager) reindex(ctx context.Context, sourceID string, opts *SyncOptions) (*SyncResult, error) {
+	source, err := sm.Get(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check before clearing, so a refusal leaves the index as it was. The
+	// re-ingested documents reuse the space of the cleared ones.
+	if sm.diskMinFree > 0 {
+		existingDocs, err := sm.documentHashes(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		files, _, err := sm.collectFiles(source)
+		if err != nil {
+			return nil, fmt.Errorf("walk directory: %w", err)
+		}
+		if err := sm.checkDiskSpace(source, files, existingDocs); err != nil {
+			return nil, err
+		}
+	}
+
+	vectorsDeleted, err := sm.deleteDocuments(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("clear index: %w", err)
+	}
+
+	sm.loggerFor(ctx).Info().
+		Str("source_id", sourceID).
+		Int("vectors_deleted", vectorsDeleted).
+		Msg("cleared source index, re-ingesting")
+
+	syncOpts := SyncOptions{}
+	if opts != nil {
+		syncOpts = *opts
+	}
+	syncOpts.skipDiskCheck = true
+	return sm.syncSource(ctx, sourceID, &syncOpts)
+}
+
+// documentHashes returns the content hash of each indexed document of a
+// source, by path.
+func (sm *SourceManager) documentHashes(ctx context.Context, sourceID string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	rows, err := sm.db.QueryContext(ctx, `
+		SELECT path, hash FROM kb_documents WHERE source_id = ?
+	`, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("query existing docs: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var path, hash string
+		rows.Scan(&path, &hash)
+		hashes[path] = hash
+	}
+	return hashes, nil
+}
+
+// checkDiskSpace refuses a sync whose new files would not fit. Files already
+// indexed are replaced in place, so only files not in existingDocs count.
+func (sm *SourceManager) checkDiskSpace(source *Source, files []string, existingDocs map[string]string) error {
+	if sm.diskMinFree <= 0 || sm.diskDir == "" {
+		return nil
+	}
+	var newBytes int64
+	for _, path := range files {
+		if _, ok := existingDocs[path]; ok {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Size() <= sm.maxFileSize {
+			newBytes += info.Size()
+		}
+	}
+	return diskspace.Check(sm.diskDir, newBytes*indexBytesPerSourceByte, sm.diskMinFree, "sync "+source.Name)
+}
+
 // Sync synchronizes a source folder with default options.
 func (sm *SourceManager) Sync(ctx context.Context, sourceID string) (*SyncResult, error) {
 	return sm.SyncWithOptions(ctx, sourceID, nil)
 }
 
 // SyncWithOptions synchronizes a source folder with configurable options.
+// The outcome is recorded on the source (see Source.LastSyncStatus).
 func (sm *SourceManager) SyncWithOptions(ctx context.Context, sourceID string, opts *SyncOptions) (*SyncResult, error) {
+	return sm.trackSync(ctx, sourceID, func() (*SyncResult, error) {
+		return sm.syncSource(ctx, sourceID, opts)
+	})
+}
+
+// trackSync marks a source as syncing while run executes, then records when
+// the sync ran, whether it succeeded and its error.
+func (sm *SourceManager) trackSync(ctx context.Context, sourceID string, run func() (*SyncResult, error)) (*SyncResult, error) {
+	sm.mu.Lock()
+	sm.syncing[sourceID]++
+	sm.mu.Unlock()
+	defer func() {
+		sm.mu.Lock()
+		if sm.syncing[sourceID]--; sm.syncing[sourceID] <= 0 {
+			delete(sm.syncing, sourceID)
+		}
+		sm.mu.Unlock()
+	}()
+
+	result, err := run()
+
+	status, message := SyncStatusSuccess, ""
+	switch {
+	case err != nil:
+		status, message = SyncStatusFailed, err.Error()
+	case len(result.Errors) > 0:
+		status = SyncStatusPartial
+		message = fmt.Sprintf("%d file(s) failed, first: %s: %s", len(result.Errors), result.Errors[0].Path, result.Errors[0].Message)
+	}
+
+	// Record even when the sync was cancelled
+	if _, dbErr := sm.db.ExecContext(context.WithoutCancel(ctx), `
+		UPDATE kb_sources SET
+			last_sync_attempt = datetime('now'),
+			last_sync_status = ?,
+			error = NULLIF(?, '')
+		WHERE source_id = ?
+	`, status, message, sourceID); dbErr != nil {
+		sm.loggerFor(ctx).Warn().Err(dbErr).Str("source_id", sourceID).Msg("failed to record sync outcome")
+	}
+
+	return result, err
+}
+
+// isSyncing reports whether a sync of the source is in progress.
+func (sm *SourceManager) isSyncing(sourceID string) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.syncing[sourceID] > 0
+}
+
+// syncSource synchronizes a source folder without recording the outcome.
+func (sm *SourceManager) syncSource(ctx context.Context, sourceID string, opts *SyncOptions) (*SyncResult, error) {
 	start := time.Now()
 
 	// Handle nil options
@@ -336,137 +611,53 @@ func (sm *SourceManager) SyncWithOptions(ctx context.Context, sourceID string, o
 	// Track semantic search status for this sync
 	semanticEnabled := sm.indexer.HasSemanticSearch()
 	sm.indexer.ResetSemanticErrors()
+	sm.indexer.ResetEmbeddingStats()
 
 	result := &SyncResult{
 		SemanticEnabled: semanticEnabled,
+		MaxFileSize:     sm.maxFileSize,
 	}
 
 	// Log if rebuild vectors is requested
 	if opts.RebuildVectors {
-		sm.logger.Info().
+		sm.loggerFor(ctx).Info().
 			Str("source_id", sourceID).
 			Msg("rebuild vectors requested - will re-index all documents")
 	}
 
 	// Get existing documents for this source
-	existingDocs := make(map[string]string) // path -> hash
-	rows, err := sm.db.QueryContext(ctx, `
-		SELECT path, hash FROM kb_documents WHERE source_id = ?
-	`, sourceID)
+	existingDocs, err := sm.documentHashes(ctx, sourceID)
 	if err != nil {
-		return nil, fmt.Errorf("query existing docs: %w", err)
-	}
-	for rows.Next() {
-		var path, hash string
-		rows.Scan(&path, &hash)
-		existingDocs[path] = hash
+		return nil, err
 	}
-	rows.Close()
 
 	// Track processed files
 	processedFiles := make(map[string]bool)
 
-	// Walk the source directory
-	err = filepath.WalkDir(source.Path, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			result.Errors = append(result.Errors, SyncError{Path: path, Message: err.Error()})
-			return nil
-		}
-
-		// Skip excluded directories
-		if d.IsDir() {
-			for _, exclude := range source.Excludes {
-				if matched, _ := filepath.Match(exclude, d.Name()); matched {
-					return filepath.SkipDir
-				}
-			}
-			return nil
-		}
-
-		// Check if file matches patterns
-		if !sm.matchesPatterns(d.Name(), source.Patterns) {
-			return nil
-		}
-
-		sm.logger.Info().Str("path", path).Msg("file matched pattern")
-		processedFiles[path] = true
-
-		// Read file content
-		content, metadata, err := sm.readFile(path)
-		if err != nil {
-			sm.logger.Error().Err(err).Str("path", path).Msg("failed to read file")
-			result.Errors = append(result.Errors, SyncError{Path: path, Message: err.Error()})
-			return nil
-		}
-		sm.logger.Info().Str("path", path).Int("content_len", len(content)).Msg("file read successfully")
-
-		// Clean content BEFORE chunking and embedding
-		// This removes boilerplate, fixes OCR errors, and normalizes text
-		contentType := DetectContentType(path)
-		originalLen := len(content)
-		content = sm.cleaner.Clean(content, contentType)
+	// Collect the files to index
+	files, walkErrors, err := sm.collectFiles(source)
+	if err != nil {
+		return nil, fmt.Errorf("walk directory: %w", err)
+	}
+	result.Errors = append(result.Errors, walkErrors...)
 
-		// For PDFs, also detect and remove repeated headers/footers
-		if contentType == ContentTypePDF {
-			content = sm.cleaner.DetectRepeatedHeaders(content)
+	if !opts.skipDiskCheck {
+		if err := sm.checkDiskSpace(source, files, existingDocs); err != nil {
+			return nil, err
 		}
+	}
 
-		if len(content) != originalLen {
-			sm.logger.Debug().
-				Str("path", path).
-				Int("original_len", originalLen).
-				Int("cleaned_len", len(content)).
-				Msg("content cleaned")
+	for i, path := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-
-		// Calculate hash of CLEANED content
-		// This ensures boilerplate changes don't trigger re-indexing
-		hash := sm.hashContent(content)
-
-		// Check if document needs update
-		// Skip hash check if RebuildVectors is requested (force re-indexing)
-		existingHash, exists := existingDocs[path]
-		if exists && existingHash == hash && !opts.RebuildVectors {
-			// No change and not forcing rebuild
-			return nil
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(files), path)
 		}
-		// Create document
-		doc := &Document{
-			DocumentID: sm.documentID(path),
-			SourceID:   sourceID,
-			Path:       path,
-			Title:      metadata.Title,
-			MimeType:   metadata.MimeType,
-			Size:       metadata.Size,
-			ModifiedAt: metadata.ModifiedAt,
-			Hash:       hash,
-			Metadata:   metadata.Extra,
-		}
-
-		// Chunk content using smart chunking based on file type
-		chunks := sm.chunker.ChunkSmart(content, path, ChunkOptions{
-			MaxSize: 1000,
-			Overlap: 100,
-		})
 
-		// Index document
-		if err := sm.indexer.Index(ctx, doc, chunks); err != nil {
-			sm.logger.Error().Err(err).Str("path", path).Msg("failed to index document")
-			result.Errors = append(result.Errors, SyncError{Path: path, Message: err.Error()})
-			return nil
+		if sm.indexFile(ctx, sourceID, path, existingDocs, opts.RebuildVectors, result) {
+			processedFiles[path] = true
 		}
-
-		if exists {
-			result.Updated++
-		} else {
-			result.Added++
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("walk directory: %w", err)
 	}
 
 	// Delete documents that no longer exist
@@ -483,23 +674,226 @@ func (sm *SourceManager) SyncWithOptions(ctx context.Context, sourceID string, o
 
 	result.Duration = time.Since(start)
 	result.SemanticErrors = sm.indexer.GetSemanticErrors()
+	embedded, embeddingTime := sm.indexer.GetEmbeddingStats()
+	result.EmbeddedChunks = embedded
+	result.EmbeddingRate = perSecond(embedded, embeddingTime)
 
 	// Update source stats
 	sm.updateSourceStats(ctx, sourceID)
 
-	sm.logger.Info().
+	sm.loggerFor(ctx).Info().
 		Str("source_id", sourceID).
 		Int("added", result.Added).
 		Int("updated", result.Updated).
 		Int("deleted", result.Deleted).
+		Int("skipped", len(result.Skipped)).
 		Bool("semantic_enabled", result.SemanticEnabled).
 		Int("semantic_errors", result.SemanticErrors).
+		Int("embedded_chunks", result.EmbeddedChunks).
+		Float64("embedding_chunks_per_sec", result.EmbeddingRate).
 		Dur("duration", result.Duration).
 		Msg("sync completed")
 
 	return result, nil
 }
 
+// indexFile indexes one file of a source unless its content is unchanged
+// since existingDocs was read, counting the outcome in result. It returns
+// false when the file was skipped as too large, so that a previously indexed
+// copy is removed rather than left stale.
+func (sm *SourceManager) indexFile(ctx context.Context, sourceID, path string, existingDocs map[string]string, rebuild bool, result *SyncResult) bool {
+	// Skip oversized files before reading them into memory.
+	if info, err := os.Stat(path); err == nil && info.Size() > sm.maxFileSize {
+		sm.loggerFor(ctx).Warn().
+			Str("path", path).
+			Int64("size", info.Size()).
+			Int64("max_file_size", sm.maxFileSize).
+			Msg("skipping file larger than max_file_size")
+		result.Skipped = append(result.Skipped, SkippedFile{
+			Path:   path,
+			Size:   info.Size(),
+			Reason: SkipReasonTooLarge,
+		})
+		return false
+	}
+
+	sm.loggerFor(ctx).Info().Str("path", path).Msg("file matched pattern")
+
+	// Read file content
+	content, metadata, err := sm.readFile(path)
+	if err != nil {
+		sm.loggerFor(ctx).Error().Err(err).Str("path", path).Msg("failed to read file")
+		result.Errors = append(result.Errors, SyncError{Path: path, Message: err.Error()})
+		return true
+	}
+	sm.loggerFor(ctx).Info().Str("path", path).Int("content_len", len(content)).Msg("file read successfully")
+
+	// Clean content BEFORE chunking and embedding
+	// This removes boilerplate, fixes OCR errors, and normalizes text
+	original := content
+	content = sm.cleanContent(content, path)
+
+	if len(content) != len(original) {
+		sm.loggerFor(ctx).Debug().
+			Str("path", path).
+			Int("original_len", len(original)).
+			Int("cleaned_len", len(content)).
+			Msg("content cleaned")
+	}
+
+	// Calculate hash of CLEANED content
+	// This ensures boilerplate changes don't trigger re-indexing
+	hash := sm.hashContent(content)
+
+	// Check if document needs update
+	// Skip hash check if RebuildVectors is requested (force re-indexing)
+	existingHash, exists := existingDocs[path]
+	if exists && existingHash == hash && !rebuild {
+		// No change and not forcing rebuild
+		return true
+	}
+	// Create document
+	doc := &Document{
+		DocumentID: sm.documentID(path),
+		SourceID:   sourceID,
+		Path:       path,
+		Title:      metadata.Title,
+		MimeType:   metadata.MimeType,
+		Size:       metadata.Size,
+		ModifiedAt: metadata.ModifiedAt,
+		Hash:       hash,
+		Metadata:   metadata.Extra,
+	}
+
+	// Chunk content using smart chunking based on file type. Size and
+	// overlap come from the chunker defaults (see SetChunkOptions).
+	chunks := sm.chunker.ChunkSmart(content, path, ChunkOptions{})
+
+	// Line numbers let results point into the file, which only makes
+	// sense when the file is text rather than an extracted format
+	if sm.extractors.ReadsAsText(path) {
+		annotateChunkLines(strings.ReplaceAll(original, "\r\n", "\n"), content, chunks)
+	}
+
+	// Index document
+	if err := sm.indexer.Index(ctx, doc, chunks); err != nil {
+		sm.loggerFor(ctx).Error().Err(err).Str("path", path).Msg("failed to index document")
+		result.Errors = append(result.Errors, SyncError{Path: path, Message: err.Error()})
+		return true
+	}
+
+	if exists {
+		result.Updated++
+	} else {
+		result.Added++
+	}
+
+	return true
+}
+
+// SyncFiles re-indexes the given paths of a source instead of walking the
+// whole folder: files that still exist and match the source are indexed if
+// changed, and documents at or below paths that no longer do are deleted. A
+// directory or the ignore file among paths falls back to a full incremental
+// sync, since either can change which files match. The outcome is recorded
+// as for SyncWithOptions.
+func (sm *SourceManager) SyncFiles(ctx context.Context, sourceID string, paths []string) (*SyncResult, error) {
+	return sm.trackSync(ctx, sourceID, func() (*SyncResult, error) {
+		return sm.syncFiles(ctx, sourceID, paths)
+	})
+}
+
+// syncFiles implements SyncFiles.
+func (sm *SourceManager) syncFiles(ctx context.Context, sourceID string, paths []string) (*SyncResult, error) {
+	start := time.Now()
+
+	source, err := sm.Get(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); filepath.Base(path) == IgnoreFile || (err == nil && info.IsDir()) {
+			return sm.syncSource(ctx, sourceID, nil)
+		}
+	}
+
+	sm.indexer.ResetSemanticErrors()
+	sm.indexer.ResetEmbeddingStats()
+	result := &SyncResult{
+		SemanticEnabled: sm.indexer.HasSemanticSearch(),
+		MaxFileSize:     sm.maxFileSize,
+	}
+
+	existingDocs, err := sm.documentHashes(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := sourceIgnore(source)
+	if err != nil {
+		result.Errors = append(result.Errors, SyncError{Path: filepath.Join(source.Path, IgnoreFile), Message: err.Error()})
+	}
+
+	var files []string
+	stale := make(map[string]bool)
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().IsRegular() && sm.wantsFile(source, ignore, path) {
+			files = append(files, path)
+			continue
+		}
+		// Gone, ignored or no longer matching: drop the document, and those
+		// below it in case path was a directory
+		for docPath := range existingDocs {
+			if docPath == path || strings.HasPrefix(docPath, path+string(filepath.Separator)) {
+				stale[docPath] = true
+			}
+		}
+	}
+
+	if err := sm.checkDiskSpace(source, files, existingDocs); err != nil {
+		return nil, err
+	}
+
+	for _, path := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !sm.indexFile(ctx, sourceID, path, existingDocs, false, result) {
+			if _, ok := existingDocs[path]; ok {
+				stale[path] = true
+			}
+		}
+	}
+
+	for path := range stale {
+		if err := sm.indexer.Delete(ctx, sm.documentID(path)); err != nil {
+			result.Errors = append(result.Errors, SyncError{Path: path, Message: err.Error()})
+		} else {
+			result.Deleted++
+		}
+	}
+
+	result.Duration = time.Since(start)
+	result.SemanticErrors = sm.indexer.GetSemanticErrors()
+	embedded, embeddingTime := sm.indexer.GetEmbeddingStats()
+	result.EmbeddedChunks = embedded
+	result.EmbeddingRate = perSecond(embedded, embeddingTime)
+
+	sm.updateSourceStats(ctx, sourceID)
+
+	sm.loggerFor(ctx).Info().
+		Str("source_id", sourceID).
+		Int("paths", len(paths)).
+		Int("added", result.Added).
+		Int("updated", result.Updated).
+		Int("deleted", result.Deleted).
+		Dur("duration", result.Duration).
+		Msg("synced changed files")
+
+	return result, nil
+}
+
 // SyncAll synchronizes all active sources.
 func (sm *SourceManager) SyncAll(ctx context.Context) error {
 	sources, err := sm.List(ctx)
@@ -512,13 +906,85 @@ func (sm *SourceManager) SyncAll(ctx context.Context) error {
 			continue
 		}
 		if _, err := sm.Sync(ctx, src.SourceID); err != nil {
-			sm.logger.Error().Err(err).Str("source_id", src.SourceID).Msg("sync failed")
+			sm.loggerFor(ctx).Error().Err(err).Str("source_id", src.SourceID).Msg("sync failed")
 		}
 	}
 
 	return nil
 }
 
+// collectFiles walks a source directory and returns the files that match its
+// patterns. Paths matching the source's excludes or its .conduitignore file
+// are skipped with gitignore semantics. Unreadable entries are reported as
+// sync errors rather than aborting the walk.
+func (sm *SourceManager) collectFiles(source *Source) ([]string, []SyncError, error) {
+	var files []string
+	var errs []SyncError
+
+	ignore, err := sourceIgnore(source)
+	if err != nil {
+		errs = append(errs, SyncError{Path: filepath.Join(source.Path, IgnoreFile), Message: err.Error()})
+	}
+
+	err = filepath.WalkDir(source.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, SyncError{Path: path, Message: err.Error()})
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(source.Path, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+
+		if d.IsDir() {
+			if ignore.Match(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Name() == IgnoreFile || ignore.Match(rel, false) {
+			return nil
+		}
+		if sm.matchesPatterns(d.Name(), source.Patterns) {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	return files, errs, err
+}
+
+// sourceIgnore builds the ignore matcher for a source from its excludes and
+// its ignore file. The matcher is usable even when the ignore file could not
+// be read.
+func sourceIgnore(source *Source) (*IgnoreMatcher, error) {
+	// Ignore-file rules come last so they can re-include configured excludes
+	ignore := NewIgnoreMatcher(source.Excludes)
+	return ignore, ignore.AddFile(filepath.Join(source.Path, IgnoreFile))
+}
+
+// wantsFile reports whether a sync of the source would index path: it lies
+// inside the source, matches its patterns and neither it nor any parent
+// directory is ignored.
+func (sm *SourceManager) wantsFile(source *Source, ignore *IgnoreMatcher, path string) bool {
+	rel, err := filepath.Rel(source.Path, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || rel == ".." {
+		return false
+	}
+	name := filepath.Base(path)
+	if name == IgnoreFile || ignore.Match(rel, false) || !sm.matchesPatterns(name, source.Patterns) {
+		return false
+	}
+	for dir := filepath.Dir(rel); dir != "."; dir = filepath.Dir(dir) {
+		if ignore.Match(dir, true) {
+			return false
+		}
+	}
+	return true
+}
+
 // matchesPatterns checks if a filename matches any pattern.
 func (sm *SourceManager) matchesPatterns(filename string, patterns []string) bool {
 	for _, pattern := range patterns {
@@ -529,6 +995,17 @@ func (sm *SourceManager) matchesPatterns(filename string, patterns []string) boo
 	return false
 }
 
+// cleanContent prepares extracted text for chunking: boilerplate removed,
+// OCR errors fixed and, for PDFs, repeated headers and footers dropped.
+func (sm *SourceManager) cleanContent(content, path string) string {
+	contentType := DetectContentType(path)
+	content = sm.cleaner.Clean(content, contentType)
+	if contentType == ContentTypePDF {
+		content = sm.cleaner.DetectRepeatedHeaders(content)
+	}
+	return content
+}
+
 // readFile reads a file and extracts text content.
 func (sm *SourceManager) readFile(path string) (string, *FileMetadata, error) {
 	info, err := os.Stat(path)
@@ -719,3 +1196,9 @@ func formatBytes(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// loggerFor returns the source manager's logger tagged with the request ID in ctx.
+func (sm *SourceManager) loggerFor(ctx context.Context) *zerolog.Logger {
+	logger := observability.LoggerFromContext(ctx, sm.logger)
+	return &logger
+}
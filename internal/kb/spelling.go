@@ -0,0 +1,219 @@
+package kb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Spelling correction limits. Query terms shorter than minSpellingTermLen are
+// too ambiguous to correct. An indexed term may leave up to
+// maxSpellingSuffixLen characters of the query term unmatched, because the
+// index holds stems ("deploy" for "deployment").
+const (
+	minSpellingTermLen   = 4
+	maxSpellingSuffixLen = 5
+)
+
+// SpellingCorrection is a query term replaced by a similar indexed word.
+type SpellingCorrection struct {
+	Term       string `json:"term"`
+	Correction string `json:"correction"`
+}
+
+// correctSpelling replaces the query terms that appear nowhere in the index
+// with the closest indexed word by edit distance. It returns the corrected
+// query and the corrections, or no corrections when every term is indexed
+// or nothing close enough was found.
+func (s *Searcher) correctSpelling(ctx context.Context, query string) (string, []SpellingCorrection, error) {
+	terms := strings.Fields(sanitizeFTSQuery(query))
+
+	// Terms without any match are the candidates for correction
+	var misspelled []string
+	for _, term := range terms {
+		if len([]rune(term)) < minSpellingTermLen || strings.ContainsFunc(term, unicode.IsDigit) {
+			continue
+		}
+		var n int
+		if err := s.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM (SELECT 1 FROM kb_fts WHERE kb_fts MATCH ? LIMIT 1)`, strings.ToLower(term)+"*",
+		).Scan(&n); err != nil {
+			return "", nil, fmt.Errorf("check term: %w", err)
+		}
+		if n == 0 {
+			misspelled = append(misspelled, strings.ToLower(term))
+		}
+	}
+	if len(misspelled) == 0 {
+		return query, nil, nil
+	}
+
+	stems, err := s.closestStems(ctx, misspelled)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var corrections []SpellingCorrection
+	replace := make(map[string]string)
+	for _, term := range misspelled {
+		stem, ok := stems[term]
+		if !ok {
+			continue
+		}
+		word := s.surfaceWord(ctx, stem, term)
+		replace[term] = word
+		corrections = append(corrections, SpellingCorrection{Term: term, Correction: word})
+	}
+	if len(corrections) == 0 {
+		return query, nil, nil
+	}
+
+	corrected := make([]string, len(terms))
+	for i, term := range terms {
+		if word, ok := replace[strings.ToLower(term)]; ok {
+			term = word
+		}
+		corrected[i] = term
+	}
+	return strings.Join(corrected, " "), corrections, nil
+}
+
+// closestStems finds, for each term, the indexed term (a stem) with the
+// smallest edit distance, preferring terms in more documents on a tie.
+func (s *Searcher) closestStems(ctx context.Context, terms []string) (map[string]string, error) {
+	minLen, maxLen := 1<<30, 0
+	for _, term := range terms {
+		n := len([]rune(term))
+		minLen = min(minLen, n-maxSpellingSuffixLen-maxEditDistance(n))
+		maxLen = max(maxLen, n+maxEditDistance(n))
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT term, doc FROM kb_fts_vocab WHERE length(term) BETWEEN ? AND ?
+	`, max(minLen, minSpellingTermLen), maxLen)
+	if err != nil {
+		return nil, fmt.Errorf("read vocabulary: %w", err)
+	}
+	defer rows.Close()
+
+	type candidate struct {
+		stem string
+		dist int
+		docs int
+	}
+	best := make(map[string]candidate)
+	for rows.Next() {
+		var stem string
+		var docs int
+		if err := rows.Scan(&stem, &docs); err != nil {
+			return nil, fmt.Errorf("read vocabulary: %w", err)
+		}
+		if strings.ContainsFunc(stem, unicode.IsDigit) {
+			continue
+		}
+		for _, term := range terms {
+			dist, ok := stemDistance(stem, term)
+			if !ok {
+				continue
+			}
+			if b, seen := best[term]; !seen || dist < b.dist || (dist == b.dist && docs > b.docs) {
+				best[term] = candidate{stem, dist, docs}
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read vocabulary: %w", err)
+	}
+
+	stems := make(map[string]string, len(best))
+	for term, c := range best {
+		stems[term] = c.stem
+	}
+	return stems, nil
+}
+
+// stemDistance is the edit distance between an indexed stem and the start of
+// a query term, leaving at most maxSpellingSuffixLen characters of the term
+// as a suffix the stemmer would have removed. ok is false when the stem is
+// too far from the term to be a correction of it; the allowed distance
+// follows the shorter of the two, so short stems only absorb small typos.
+func stemDistance(stem, term string) (dist int, ok bool) {
+	s, t := []rune(stem), []rune(term)
+	limit := maxEditDistance(min(len(s), len(t)))
+	dist = limit + 1
+	for k := max(1, len(s)-limit, len(t)-maxSpellingSuffixLen); k <= min(len(t), len(s)+limit); k++ {
+		dist = min(dist, editDistance(s, t[:k]))
+	}
+	return dist, dist <= limit
+}
+
+// maxEditDistance is the most edits allowed to correct a term of n runes.
+func maxEditDistance(n int) int {
+	if n <= 5 {
+		return 1
+	}
+	return 2
+}
+
+// surfaceWord returns the word in the indexed text that stem was derived from
+// and that is closest to term, or stem if none is found. Corrections are
+// shown to the user, who would rather see "configuration" than "configur".
+func (s *Searcher) surfaceWord(ctx context.Context, stem, term string) string {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT content FROM kb_fts WHERE kb_fts MATCH ? LIMIT 5`, stem+"*")
+	if err != nil {
+		return stem
+	}
+	defer rows.Close()
+
+	best, bestDist := stem, -1
+	t := []rune(term)
+	for rows.Next() {
+		var content string
+		if rows.Scan(&content) != nil {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if !strings.HasPrefix(word, string([]rune(stem)[:1])) {
+				continue
+			}
+			if d := editDistance([]rune(word), t); bestDist < 0 || d < bestDist {
+				best, bestDist = word, d
+			}
+		}
+	}
+	if bestDist < 0 || bestDist > maxEditDistance(len(t))+maxSpellingSuffixLen {
+		return stem
+	}
+	return best
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// the insertions, deletions, substitutions and transpositions of adjacent
+// characters needed to turn one into the other.
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
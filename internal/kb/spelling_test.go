@@ -0,0 +1,44 @@
+package kb
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"deploy", "deploy", 0},
+		{"deploy", "", 6},
+		{"deploy", "deplyo", 1},
+		{"depoly", "deploy", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStemDistance(t *testing.T) {
+	tests := []struct {
+		stem, term string
+		dist       int
+		ok         bool
+	}{
+		{"deploy", "depolyment", 1, true},
+		{"configur", "configuraton", 0, true},
+		{"kubernet", "kuberntes", 1, true},
+		{"helm", "hlem", 1, true},
+		{"deploy", "zeppelin", 3, false},
+		{"cluster", "clu", 2, false},
+	}
+	for _, tt := range tests {
+		dist, ok := stemDistance(tt.stem, tt.term)
+		if ok != tt.ok || (ok && dist != tt.dist) {
+			t.Errorf("stemDistance(%q, %q) = %d, %v; want %d, %v", tt.stem, tt.term, dist, ok, tt.dist, tt.ok)
+		}
+	}
+}
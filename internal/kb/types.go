@@ -12,18 +12,47 @@ type Source struct {
 	Name       string    `json:"name"`
 	Type       string    `json:"type"`       // "folder", "git", "confluence" (V1)
 	Patterns   []string  `json:"patterns"`   // ["*.md", "*.txt"]
-	Excludes   []string  `json:"excludes"`   // ["node_modules", ".git"]
-	SyncMode   string    `json:"sync_mode"`  // "watch", "manual", "scheduled"
+	Excludes   []string  `json:"excludes"`   // gitignore-style: ["node_modules", "*.log", "!keep.log"]
+	SyncMode   string    `json:"sync_mode"`  // "watch", "manual", "scheduled" (see SyncModeWatch)
 	Status     string    `json:"status"`     // "active", "paused", "error"
-	LastSync   time.Time `json:"last_sync"`
+	LastSync   time.Time `json:"last_sync"` // Last sync that completed
 	DocCount   int       `json:"doc_count"`
 	ChunkCount int       `json:"chunk_count"`
 	SizeBytes  int64     `json:"size_bytes"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 	Error      string    `json:"error,omitempty"`
+
+	// ExtractEntities enables KAG entity extraction for this source's chunks
+	ExtractEntities bool `json:"extract_entities"`
+	EntityCount     int  `json:"entity_count"` // Entities extracted from this source
+
+	// Outcome of the most recent sync or reindex. Error holds its error, or a
+	// summary of the files that failed when the status is partial.
+	LastSyncAttempt time.Time `json:"last_sync_attempt"`
+	LastSyncStatus  string    `json:"last_sync_status,omitempty"` // "success", "partial", "failed"; empty before the first sync
+	Syncing         bool      `json:"syncing"`                    // A sync is in progress
+
+	// Scheduled sync. SyncInterval overrides the configured interval with a
+	// duration such as "30m0s", or "off"; it is empty when the default
+	// applies. NextSync is absent when the source is not synced on a schedule.
+	SyncInterval string     `json:"sync_interval,omitempty"`
+	NextSync     *time.Time `json:"next_sync,omitempty"`
+
+	// Watch mode. WatchStatus is "watching", or "fallback" when the folder
+	// could not be watched, with the reason in WatchError; it is empty for
+	// other sync modes.
+	WatchStatus string `json:"watch_status,omitempty"`
+	WatchError  string `json:"watch_error,omitempty"`
 }
 
+// Sync outcomes recorded in Source.LastSyncStatus.
+const (
+	SyncStatusSuccess = "success"
+	SyncStatusPartial = "partial"
+	SyncStatusFailed  = "failed"
+)
+
 // AddSourceRequest contains parameters for adding a source.
 type AddSourceRequest struct {
 	Path     string   `json:"path"`
@@ -31,6 +60,10 @@ type AddSourceRequest struct {
 	Patterns []string `json:"patterns,omitempty"`
 	Excludes []string `json:"excludes,omitempty"`
 	SyncMode string   `json:"sync_mode"`
+
+	// ExtractEntities enables KAG entity extraction for the source. When
+	// nil, the source manager's default applies.
+	ExtractEntities *bool `json:"extract_entities,omitempty"`
 }
 
 // UpdateSourceRequest contains parameters for updating a source.
@@ -45,6 +78,12 @@ type UpdateSourceRequest struct {
 // SyncOptions configures sync behavior.
 type SyncOptions struct {
 	RebuildVectors bool // Force regeneration of vector embeddings for all documents
+
+	// Progress, if set, is called before each matching file is processed.
+	Progress func(current, total int, path string)
+
+	// skipDiskCheck is set by Reindex, which checks before clearing the index
+	skipDiskCheck bool
 }
 
 // SyncResult contains the result of a sync operation.
@@ -53,9 +92,23 @@ type SyncResult struct {
 	Updated         int           `json:"updated"`
 	Deleted         int           `json:"deleted"`
 	Errors          []SyncError   `json:"errors,omitempty"`
+	Skipped         []SkippedFile `json:"skipped,omitempty"` // Files not indexed, e.g. over max_file_size
+	MaxFileSize     int64         `json:"max_file_size"`
 	Duration        time.Duration `json:"duration"`
-	SemanticEnabled bool          `json:"semantic_enabled"` // Whether semantic indexing was attempted
-	SemanticErrors  int           `json:"semantic_errors"`  // Number of documents with semantic indexing failures
+	SemanticEnabled bool          `json:"semantic_enabled"`          // Whether semantic indexing was attempted
+	SemanticErrors  int           `json:"semantic_errors"`           // Number of documents with semantic indexing failures
+	EmbeddedChunks  int           `json:"embedded_chunks,omitempty"` // Chunks vectorized during this sync
+	EmbeddingRate   float64       `json:"embedding_rate,omitempty"`  // Chunks vectorized per second
+}
+
+// SkipReasonTooLarge marks a file skipped because it exceeds the size limit.
+const SkipReasonTooLarge = "too large"
+
+// SkippedFile is a matching file that was deliberately not indexed.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason"`
 }
 
 // SyncError represents an error during sync.
@@ -120,8 +173,33 @@ type SearchHit struct {
 	Path       string            `json:"path"`
 	Title      string            `json:"title"`
 	Snippet    string            `json:"snippet"`
-	Score      float64           `json:"score"`
+	Score      float64           `json:"score"`     // Display-ranked score (after boosts and reranking)
+	RawScore   float64           `json:"raw_score"` // Original BM25/semantic/RRF score, comparable for thresholding
 	Metadata   map[string]string `json:"metadata,omitempty"`
+
+	// Content is the whole text of the matched chunk, of which Snippet may
+	// show only part. It is kept for stitching adjacent chunks and is not
+	// serialized.
+	Content string `json:"-"`
+
+	// Explanation breaks down the hybrid score (only set when explain is requested)
+	Explanation *ScoreExplanation `json:"explanation,omitempty"`
+}
+
+// ScoreExplanation records how a hybrid search result's score was built.
+// Final score = (lexical_rrf + semantic_rrf) * entity_boost * agreement_boost * rerank_multiplier.
+type ScoreExplanation struct {
+	ChunkID          string   `json:"chunk_id"`
+	Rank             int      `json:"rank"`                    // Final 1-based position in the results
+	LexicalRank      int      `json:"lexical_rank,omitempty"`  // Position in FTS5 results (0 = not found)
+	SemanticRank     int      `json:"semantic_rank,omitempty"` // Position in semantic results (0 = not found)
+	LexicalRRF       float64  `json:"lexical_rrf"`             // Weighted lexical RRF contribution
+	SemanticRRF      float64  `json:"semantic_rrf"`            // Weighted semantic RRF contribution
+	EntityBoost      float64  `json:"entity_boost"`            // Exact entity match multiplier
+	MatchedEntities  []string `json:"matched_entities,omitempty"`
+	AgreementBoost   float64  `json:"agreement_boost"`   // Multi-strategy agreement multiplier
+	RerankMultiplier float64  `json:"rerank_multiplier"` // Semantic rerank multiplier
+	FinalScore       float64  `json:"final_score"`
 }
 
 // IngestionJob represents a job for the ingestion pipeline.
@@ -148,7 +226,10 @@ var DefaultPatterns = []string{
 	"*.doc", "*.docx", "*.odt", "*.rtf",
 }
 
-// Default excludes for common directories.
+// DefaultMaxFileSize is the largest file indexed when no limit is configured.
+const DefaultMaxFileSize = 5 * 1024 * 1024
+
+// Default excludes for common directories. Patterns use gitignore syntax.
 var DefaultExcludes = []string{
 	"node_modules", ".git", ".svn", ".hg",
 	"__pycache__", ".pytest_cache",
@@ -420,7 +420,7 @@ func (vs *VectorStore) Search(ctx context.Context, queryVector []float32, opts V
 
 	// Build filter if specified
 	var filter *qdrant.Filter
-	if len(opts.SourceIDs) > 0 || opts.PathPrefix != "" {
+	if len(opts.SourceIDs) > 0 || len(opts.DocumentIDs) > 0 || len(opts.Languages) > 0 {
 		var conditions []*qdrant.Condition
 
 		if len(opts.SourceIDs) > 0 {
@@ -438,10 +438,14 @@ func (vs *VectorStore) Search(ctx context.Context, queryVector []float32, opts V
 			})
 		}
 
-		if opts.PathPrefix != "" {
-			// Match path prefix - Qdrant doesn't have native prefix matching,
-			// so we use a text match with the prefix
-			conditions = append(conditions, qdrant.NewMatchText("path", opts.PathPrefix))
+		if len(opts.DocumentIDs) > 0 {
+			// Document-level filters (path prefix, modification time) are
+			// resolved to document IDs in SQLite by the caller
+			conditions = append(conditions, qdrant.NewMatchKeywords("document_id", opts.DocumentIDs...))
+		}
+
+		if len(opts.Languages) > 0 {
+			conditions = append(conditions, qdrant.NewMatchKeywords("language", opts.Languages...))
 		}
 
 		if len(conditions) > 0 {
@@ -514,11 +518,12 @@ func (vs *VectorStore) Search(ctx context.Context, queryVector []float32, opts V
 
 // VectorSearchOptions configures vector search behavior.
 type VectorSearchOptions struct {
-	Limit      int      // Max results (default 10)
-	Offset     int      // Pagination offset
-	SourceIDs  []string // Filter by source IDs
-	PathPrefix string   // Filter by path prefix
-	MinScore   float64  // Minimum similarity score threshold (0-1)
+	Limit       int      // Max results (default 10)
+	Offset      int      // Pagination offset
+	SourceIDs   []string // Filter by source IDs
+	DocumentIDs []string // Filter by document IDs
+	Languages   []string // Filter by chunk language
+	MinScore    float64  // Minimum similarity score threshold (0-1)
 }
 
 // GetStats returns vector store statistics.
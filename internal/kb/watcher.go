@@ -0,0 +1,475 @@
+package kb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog"
+
+	"github.com/simpleflo/conduit/internal/observability"
+)
+
+// Source sync modes (Source.SyncMode).
+const (
+	SyncModeManual    = "manual"    // Synced on request or on the schedule
+	SyncModeWatch     = "watch"     // Re-indexed as files change (see Watcher)
+	SyncModeScheduled = "scheduled" // Kept for older sources; same as manual
+)
+
+// Watch states of a source in watch mode (Source.WatchStatus).
+const (
+	WatchStatusWatching = "watching" // Changes are picked up as they happen
+	WatchStatusFallback = "fallback" // The folder could not be watched; synced on the schedule instead
+)
+
+// DefaultWatchDebounce is how long the watcher waits for changes to settle
+// before re-indexing when no debounce is configured.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// normalizeSyncMode validates a sync mode, defaulting to manual. "auto" is
+// accepted as an alias for watch.
+func normalizeSyncMode(mode string) (string, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "":
+		return SyncModeManual, nil
+	case "auto":
+		return SyncModeWatch, nil
+	case SyncModeManual, SyncModeWatch, SyncModeScheduled:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid sync mode %q: use %q or %q", mode, SyncModeManual, SyncModeWatch)
+}
+
+// storedSyncMode maps a sync mode read from the database to its current
+// name, so sources saved with the legacy "auto" mode are watched.
+func storedSyncMode(mode string) string {
+	if normalized, err := normalizeSyncMode(mode); err == nil {
+		return normalized
+	}
+	return mode
+}
+
+// SetWatch switches a source between watch and manual sync mode. A running
+// Watcher picks the change up on its next Refresh.
+func (sm *SourceManager) SetWatch(ctx context.Context, sourceID string, enabled bool) error {
+	mode := SyncModeManual
+	if enabled {
+		mode = SyncModeWatch
+	}
+	res, err := sm.db.ExecContext(ctx, `
+		UPDATE kb_sources SET sync_mode = ?, updated_at = datetime('now') WHERE source_id = ?
+	`, mode, sourceID)
+	if err != nil {
+		return fmt.Errorf("update sync mode: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return fmt.Errorf("source not found: %s", sourceID)
+	}
+	return nil
+}
+
+// setWatchState records whether a source is being watched: err is nil while
+// it is, or why watching it failed.
+func (sm *SourceManager) setWatchState(sourceID string, err error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.watchState[sourceID] = err
+}
+
+// clearWatchState forgets the watch state of a source no longer watched.
+func (sm *SourceManager) clearWatchState(sourceID string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.watchState, sourceID)
+}
+
+// applyWatchState fills in the watch status of a source in watch mode.
+// Nothing is set until a Watcher has tried to watch it.
+func (sm *SourceManager) applyWatchState(src *Source) {
+	if src.SyncMode != SyncModeWatch {
+		return
+	}
+	sm.mu.Lock()
+	err, ok := sm.watchState[src.SourceID]
+	sm.mu.Unlock()
+	switch {
+	case !ok:
+	case err != nil:
+		src.WatchStatus = WatchStatusFallback
+		src.WatchError = err.Error()
+	default:
+		src.WatchStatus = WatchStatusWatching
+	}
+}
+
+// WatchSyncFunc re-indexes changed paths of a watched source. Paths that are
+// the source root ask for a full incremental sync (see SyncFiles).
+type WatchSyncFunc func(ctx context.Context, source *Source, paths []string) (*SyncResult, error)
+
+// Watcher re-indexes the files of sources in watch mode shortly after they
+// change. Events are debounced per source, so an editor's atomic save (write
+// to a temporary file, then rename over the original) or a burst of changes
+// results in one sync of just the affected files.
+//
+// Directories are watched individually, so a large tree can exceed the
+// operating system's watch limit (fs.inotify.max_user_watches on Linux). A
+// source that cannot be watched is marked WatchStatusFallback and synced on
+// the schedule instead, even when scheduled sync is disabled.
+type Watcher struct {
+	sm       *SourceManager
+	debounce time.Duration
+	fsw      *fsnotify.Watcher
+	initErr  error // Why fsw could not be created
+	logger   zerolog.Logger
+
+	// Sync runs the debounced syncs; it defaults to SourceManager.SyncFiles.
+	// Set it before Run.
+	Sync WatchSyncFunc
+
+	mu      sync.Mutex
+	ctx     context.Context
+	sources map[string]*watchedSource // By source ID
+	dirs    map[string]int            // Watched directories, counted per source
+	syncs   sync.WaitGroup
+	closed  bool // Set by close; no syncs start after it
+}
+
+// watchedSource is the watch state of one source.
+type watchedSource struct {
+	source  *Source
+	ignore  *IgnoreMatcher
+	dirs    map[string]bool
+	pending map[string]bool // Changed paths not yet synced
+	timer   *time.Timer
+
+	syncMu sync.Mutex // Held while a sync runs so syncs of a source don't overlap
+}
+
+// NewWatcher creates a watcher for the sources of sm. A non-positive debounce
+// uses DefaultWatchDebounce. If the operating system refuses to create a
+// watcher, every source in watch mode falls back to scheduled sync.
+func NewWatcher(sm *SourceManager, debounce time.Duration) *Watcher {
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+	w := &Watcher{
+		sm:       sm,
+		debounce: debounce,
+		logger:   observability.Logger("kb.watcher"),
+		sources:  make(map[string]*watchedSource),
+		dirs:     make(map[string]int),
+	}
+	w.Sync = func(ctx context.Context, source *Source, paths []string) (*SyncResult, error) {
+		return sm.SyncFiles(ctx, source.SourceID, paths)
+	}
+	w.fsw, w.initErr = fsnotify.NewWatcher()
+	if w.initErr != nil {
+		w.logger.Warn().Err(w.initErr).Msg("file watching unavailable, watched sources fall back to scheduled sync")
+	}
+	return w
+}
+
+// Run processes file events until ctx is done, then waits for syncs in
+// progress and releases the watches.
+func (w *Watcher) Run(ctx context.Context) {
+	w.mu.Lock()
+	w.ctx = ctx
+	w.mu.Unlock()
+
+	defer w.close()
+	if w.fsw == nil {
+		<-ctx.Done()
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(ev)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				// Events were lost, so changes can only be found by a full sync
+				w.logger.Warn().Err(err).Msg("file events overflowed, syncing watched sources in full")
+				w.mu.Lock()
+				for _, ws := range w.sources {
+					w.queue(ws, ws.source.Path)
+				}
+				w.mu.Unlock()
+				continue
+			}
+			w.logger.Warn().Err(err).Msg("file watcher error")
+		}
+	}
+}
+
+// close stops pending syncs, waits for running ones and closes the watcher.
+func (w *Watcher) close() {
+	w.mu.Lock()
+	w.closed = true
+	for _, ws := range w.sources {
+		if ws.timer != nil {
+			ws.timer.Stop()
+		}
+	}
+	w.mu.Unlock()
+	w.syncs.Wait()
+	if w.fsw != nil {
+		w.fsw.Close()
+	}
+}
+
+// Refresh watches the active sources in watch mode and stops watching the
+// rest. Call it after sources are added, removed or change mode. Sources
+// that failed to be watched are retried.
+func (w *Watcher) Refresh(ctx context.Context) error {
+	sources, err := w.sm.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wanted := make(map[string]bool)
+	for _, src := range sources {
+		if src.Status != "active" || src.SyncMode != SyncModeWatch {
+			continue
+		}
+		wanted[src.SourceID] = true
+
+		if w.fsw == nil {
+			w.sm.setWatchState(src.SourceID, fmt.Errorf("file watching unavailable: %w", w.initErr))
+			continue
+		}
+		if ws, ok := w.sources[src.SourceID]; ok && ws.source.Path == src.Path {
+			ws.source = src
+			ws.ignore, _ = sourceIgnore(src)
+			continue
+		}
+		w.unwatch(src.SourceID)
+		w.watch(src)
+	}
+
+	for id := range w.sources {
+		if !wanted[id] {
+			w.unwatch(id)
+		}
+	}
+	for _, src := range sources {
+		if !wanted[src.SourceID] {
+			w.sm.clearWatchState(src.SourceID)
+		}
+	}
+	return nil
+}
+
+// watch adds watches for every directory of a source that is not ignored.
+// On failure the watches added are removed again and the source is marked
+// as falling back to scheduled sync. Callers hold w.mu.
+func (w *Watcher) watch(src *Source) {
+	ignore, _ := sourceIgnore(src)
+	ws := &watchedSource{
+		source:  src,
+		ignore:  ignore,
+		dirs:    make(map[string]bool),
+		pending: make(map[string]bool),
+	}
+	if err := w.addTree(ws, src.Path); err != nil {
+		w.removeDirs(ws)
+		if errors.Is(err, syscall.ENOSPC) {
+			err = fmt.Errorf("%w (raise fs.inotify.max_user_watches or exclude large directories)", err)
+		}
+		w.logger.Warn().Err(err).Str("source_id", src.SourceID).Str("path", src.Path).
+			Msg("cannot watch source, falling back to scheduled sync")
+		w.sm.setWatchState(src.SourceID, err)
+		return
+	}
+	w.sources[src.SourceID] = ws
+	w.sm.setWatchState(src.SourceID, nil)
+	w.logger.Info().Str("source_id", src.SourceID).Str("path", src.Path).Int("directories", len(ws.dirs)).
+		Msg("watching source")
+}
+
+// addTree watches root and the directories below it that are not ignored.
+// Directories that disappear during the walk are skipped. Callers hold w.mu.
+func (w *Watcher) addTree(ws *watchedSource, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if rel, _ := filepath.Rel(ws.source.Path, path); rel != "." && ws.ignore.Match(rel, true) {
+			return filepath.SkipDir
+		}
+		if ws.dirs[path] {
+			return nil
+		}
+		if err := w.fsw.Add(path); err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("watch %s: %w", path, err)
+		}
+		ws.dirs[path] = true
+		w.dirs[path]++
+		return nil
+	})
+}
+
+// removeDirs drops the watches of a source that no other source shares.
+// Callers hold w.mu.
+func (w *Watcher) removeDirs(ws *watchedSource) {
+	for dir := range ws.dirs {
+		w.dropDir(ws, dir)
+	}
+}
+
+// dropDir stops watching dir for ws. Callers hold w.mu.
+func (w *Watcher) dropDir(ws *watchedSource, dir string) {
+	delete(ws.dirs, dir)
+	if w.dirs[dir]--; w.dirs[dir] <= 0 {
+		delete(w.dirs, dir)
+		// Fails harmlessly for a directory that no longer exists
+		_ = w.fsw.Remove(dir)
+	}
+}
+
+// unwatch stops watching a source. Callers hold w.mu.
+func (w *Watcher) unwatch(sourceID string) {
+	ws, ok := w.sources[sourceID]
+	if !ok {
+		return
+	}
+	if ws.timer != nil {
+		ws.timer.Stop()
+	}
+	w.removeDirs(ws)
+	delete(w.sources, sourceID)
+	w.logger.Info().Str("source_id", sourceID).Msg("stopped watching source")
+}
+
+// handleEvent queues a changed path for the sources that contain it.
+func (w *Watcher) handleEvent(ev fsnotify.Event) {
+	// Permission and timestamp changes don't change content
+	if ev.Op == fsnotify.Chmod {
+		return
+	}
+	path := filepath.Clean(ev.Name)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ws := range w.sources {
+		rel, err := filepath.Rel(ws.source.Path, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		switch {
+		case ev.Has(fsnotify.Create) && isDir(path):
+			// A new or moved-in directory: watch it and index what it holds
+			if !ws.ignore.Match(rel, true) {
+				if err := w.addTree(ws, path); err != nil {
+					w.logger.Warn().Err(err).Str("source_id", ws.source.SourceID).Msg("cannot watch new directory")
+				}
+				w.queue(ws, path)
+			}
+		case ws.dirs[path] && ev.Has(fsnotify.Remove|fsnotify.Rename):
+			// A watched directory went away, with everything below it
+			for dir := range ws.dirs {
+				if dir == path || strings.HasPrefix(dir, path+string(filepath.Separator)) {
+					w.dropDir(ws, dir)
+				}
+			}
+			w.queue(ws, path)
+		case filepath.Base(path) == IgnoreFile && filepath.Dir(path) == ws.source.Path:
+			// Changed ignore rules can change any file, and un-ignore directories
+			ws.ignore, _ = sourceIgnore(ws.source)
+			if err := w.addTree(ws, ws.source.Path); err != nil {
+				w.logger.Warn().Err(err).Str("source_id", ws.source.SourceID).Msg("cannot watch directory")
+			}
+			w.queue(ws, ws.source.Path)
+		case w.sm.wantsFile(ws.source, ws.ignore, path):
+			w.queue(ws, path)
+		}
+	}
+}
+
+// queue adds a path to a source's next sync and restarts its debounce
+// timer. Callers hold w.mu.
+func (w *Watcher) queue(ws *watchedSource, path string) {
+	ws.pending[path] = true
+	if ws.timer != nil {
+		ws.timer.Stop()
+	}
+	ws.timer = time.AfterFunc(w.debounce, func() { w.flush(ws) })
+}
+
+// flush syncs the paths queued for a source, after any sync of it still
+// running. Changes queued meanwhile wait for the next flush.
+func (w *Watcher) flush(ws *watchedSource) {
+	// Checked under w.mu so close cannot start waiting between the check and
+	// the Add
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.syncs.Add(1)
+	w.mu.Unlock()
+	defer w.syncs.Done()
+
+	ws.syncMu.Lock()
+	defer ws.syncMu.Unlock()
+
+	w.mu.Lock()
+	ctx := w.ctx
+	if ctx == nil || ctx.Err() != nil || w.sources[ws.source.SourceID] != ws || len(ws.pending) == 0 {
+		w.mu.Unlock()
+		return
+	}
+	source := ws.source
+	paths := make([]string, 0, len(ws.pending))
+	for path := range ws.pending {
+		if path == source.Path {
+			// A full sync covers every other path
+			paths = []string{path}
+			break
+		}
+		paths = append(paths, path)
+	}
+	ws.pending = make(map[string]bool)
+	w.mu.Unlock()
+
+	if _, err := w.Sync(ctx, source, paths); err != nil && ctx.Err() == nil {
+		w.logger.Warn().Err(err).Str("source_id", source.SourceID).Msg("watched source sync failed")
+	}
+}
+
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
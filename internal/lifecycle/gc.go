@@ -0,0 +1,142 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/simpleflo/conduit/internal/runtime"
+	"github.com/simpleflo/conduit/pkg/models"
+)
+
+// CollectGarbage removes the containers and images labeled
+// conduit.managed=true that no instance owns: containers whose instance is
+// gone, left behind by crashes and interrupted operations, and images that
+// no instance uses. Orphaned containers are removed first, so their images
+// can be removed after them.
+func (m *Manager) CollectGarbage(ctx context.Context, opts GCOptions) (*GCResult, error) {
+	if m.runtime == nil {
+		return nil, models.NewError(models.ErrRuntimeUnavailable, "no container runtime available")
+	}
+
+	instanceImages := make(map[string]string)
+	rows, err := m.db.QueryContext(ctx, `SELECT instance_id, image_ref FROM connector_instances`)
+	if err != nil {
+		return nil, fmt.Errorf("list instances: %w", err)
+	}
+	for rows.Next() {
+		var id, image string
+		if err := rows.Scan(&id, &image); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("list instances: %w", err)
+		}
+		instanceImages[id] = image
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list instances: %w", err)
+	}
+
+	containers, err := m.runtime.ListManagedContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	images, err := m.runtime.ListManagedImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GCResult{DryRun: opts.DryRun, Containers: []GCItem{}, Images: []GCItem{}}
+	now := time.Now()
+	oldEnough := func(created time.Time) bool {
+		if opts.MinAge <= 0 {
+			return true
+		}
+		if created.IsZero() || now.Sub(created) < opts.MinAge {
+			result.Skipped++
+			return false
+		}
+		return true
+	}
+
+	var kept []runtime.ContainerInfo
+	for _, c := range containers {
+		if _, ok := instanceImages[c.InstanceID]; ok || !oldEnough(c.CreatedAt) {
+			kept = append(kept, c)
+			continue
+		}
+		item := GCItem{ID: c.ID, Name: c.Name, InstanceID: c.InstanceID}
+		if !opts.DryRun {
+			if err := m.runtime.Remove(ctx, c.ID, true); err != nil {
+				item.Error = err.Error()
+				kept = append(kept, c)
+			} else {
+				item.Removed = true
+			}
+		}
+		result.Containers = append(result.Containers, item)
+	}
+
+	used := make(map[string]bool)
+	for _, image := range instanceImages {
+		if image != "" {
+			used[normalizeImageRef(image)] = true
+		}
+	}
+	for _, img := range images {
+		if (img.Ref != "" && used[normalizeImageRef(img.Ref)]) || imageInUse(img, kept) || !oldEnough(img.CreatedAt) {
+			continue
+		}
+		item := GCItem{ID: img.ID, Name: img.Ref}
+		if !opts.DryRun {
+			// Remove by reference so other tags of the same image survive
+			ref := img.Ref
+			if ref == "" {
+				ref = img.ID
+			}
+			if err := m.runtime.RemoveImage(ctx, ref); err != nil {
+				item.Error = err.Error()
+			} else {
+				item.Removed = true
+			}
+		}
+		result.Images = append(result.Images, item)
+	}
+
+	m.logger.Info().
+		Bool("dry_run", opts.DryRun).
+		Int("containers", len(result.Containers)).
+		Int("images", len(result.Images)).
+		Int("skipped", result.Skipped).
+		Msg("garbage collection finished")
+
+	return result, nil
+}
+
+// imageInUse reports whether a remaining container was created from img.
+// Containers show the image's reference, or its ID once the reference has
+// moved to a newer build.
+func imageInUse(img runtime.ImageInfo, containers []runtime.ContainerInfo) bool {
+	id := strings.TrimPrefix(img.ID, "sha256:")
+	for _, c := range containers {
+		image := strings.TrimPrefix(c.Image, "sha256:")
+		if img.Ref != "" && normalizeImageRef(c.Image) == normalizeImageRef(img.Ref) {
+			return true
+		}
+		if image != "" && id != "" && (strings.HasPrefix(id, image) || strings.HasPrefix(image, id)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImageRef makes image references comparable: podman lists local
+// images under localhost/, and a reference without a tag means :latest.
+func normalizeImageRef(ref string) string {
+	ref = strings.TrimPrefix(ref, "localhost/")
+	if i := strings.LastIndex(ref, "/"); !strings.Contains(ref[i+1:], ":") && !strings.Contains(ref, "@") {
+		ref += ":latest"
+	}
+	return ref
+}
@@ -0,0 +1,119 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/simpleflo/conduit/internal/policy"
+	"github.com/simpleflo/conduit/internal/runtime"
+)
+
+// gcRuntime serves fixed container and image listings and records removals.
+type gcRuntime struct {
+	runtime.Provider
+	containers        []runtime.ContainerInfo
+	images            []runtime.ImageInfo
+	removedContainers []string
+	removedImages     []string
+}
+
+func (r *gcRuntime) ListManagedContainers(ctx context.Context) ([]runtime.ContainerInfo, error) {
+	return r.containers, nil
+}
+
+func (r *gcRuntime) ListManagedImages(ctx context.Context) ([]runtime.ImageInfo, error) {
+	return r.images, nil
+}
+
+func (r *gcRuntime) Remove(ctx context.Context, containerID string, force bool) error {
+	r.removedContainers = append(r.removedContainers, containerID)
+	return nil
+}
+
+func (r *gcRuntime) RemoveImage(ctx context.Context, image string) error {
+	r.removedImages = append(r.removedImages, image)
+	return nil
+}
+
+func TestManager_CollectGarbage(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	old := time.Now().Add(-2 * time.Hour)
+	rt := &gcRuntime{}
+	m := New(st.DB(), rt, policy.New(st.DB()))
+	ctx := context.Background()
+
+	inst, err := m.CreateInstance(ctx, CreateInstanceRequest{
+		PackageID:   "test/connector",
+		Version:     "1.0.0",
+		DisplayName: "Test Connector",
+		ImageRef:    "conduit-mcp-kept",
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	rt.containers = []runtime.ContainerInfo{
+		{ID: "c-owned", Name: "conduit-owned", Image: "conduit-mcp-kept:latest", CreatedAt: old, InstanceID: inst.InstanceID},
+		{ID: "c-orphan", Name: "conduit-orphan", Image: "conduit-mcp-gone:latest", CreatedAt: old, InstanceID: "inst_deleted"},
+		{ID: "c-unlabeled", Name: "conduit-unlabeled", Image: "conduit-mcp-gone:latest", CreatedAt: old},
+		{ID: "c-new", Name: "conduit-new", Image: "conduit-mcp-new:latest", CreatedAt: time.Now(), InstanceID: "inst_installing"},
+	}
+	rt.images = []runtime.ImageInfo{
+		{ID: "i-kept", Ref: "localhost/conduit-mcp-kept:latest", CreatedAt: old},
+		{ID: "i-gone", Ref: "conduit-mcp-gone:latest", CreatedAt: old},
+		{ID: "i-dangling", CreatedAt: old},
+		{ID: "i-new", Ref: "conduit-mcp-new:latest", CreatedAt: time.Now()},
+		{ID: "i-unknown-age", Ref: "conduit-mcp-unknown:latest"},
+	}
+
+	// A dry run reports the orphans without touching them
+	result, err := m.CollectGarbage(ctx, GCOptions{DryRun: true, MinAge: time.Hour})
+	if err != nil {
+		t.Fatalf("CollectGarbage failed: %v", err)
+	}
+	if len(result.Containers) != 2 || len(result.Images) != 2 || result.Skipped != 2 {
+		t.Errorf("expected 2 containers, 2 images and 2 skipped, got %+v", result)
+	}
+	if len(rt.removedContainers) != 0 || len(rt.removedImages) != 0 {
+		t.Fatalf("dry run removed %v and %v", rt.removedContainers, rt.removedImages)
+	}
+
+	result, err = m.CollectGarbage(ctx, GCOptions{MinAge: time.Hour})
+	if err != nil {
+		t.Fatalf("CollectGarbage failed: %v", err)
+	}
+	for _, item := range append(result.Containers, result.Images...) {
+		if !item.Removed {
+			t.Errorf("expected %s to be removed: %+v", item.ID, item)
+		}
+	}
+	if got := rt.removedContainers; len(got) != 2 || got[0] != "c-orphan" || got[1] != "c-unlabeled" {
+		t.Errorf("unexpected containers removed: %v", got)
+	}
+	if got := rt.removedImages; len(got) != 2 || got[0] != "conduit-mcp-gone:latest" || got[1] != "i-dangling" {
+		t.Errorf("unexpected images removed: %v", got)
+	}
+}
+
+func TestImageInUse(t *testing.T) {
+	img := runtime.ImageInfo{ID: "sha256:abcdef123456", Ref: "localhost/conduit-mcp-x:latest"}
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{"conduit-mcp-x", true},
+		{"abcdef123456", true},
+		{"conduit-mcp-y:latest", false},
+	}
+	for _, tt := range tests {
+		if got := imageInUse(img, []runtime.ContainerInfo{{Image: tt.image}}); got != tt.want {
+			t.Errorf("imageInUse with container image %q = %v, want %v", tt.image, got, tt.want)
+		}
+	}
+}
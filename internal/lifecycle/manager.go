@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -14,8 +17,16 @@ import (
 	"github.com/simpleflo/conduit/internal/observability"
 	"github.com/simpleflo/conduit/internal/policy"
 	"github.com/simpleflo/conduit/internal/runtime"
+	"github.com/simpleflo/conduit/internal/secrets"
+	"github.com/simpleflo/conduit/pkg/models"
 )
 
+// SecretStore looks up secret values by name, returning an error wrapping
+// secrets.ErrNotFound for unknown names.
+type SecretStore interface {
+	Get(name string) (string, error)
+}
+
 // Manager handles connector instance lifecycle operations.
 type Manager struct {
 	db      *sql.DB
@@ -27,6 +38,23 @@ type Manager struct {
 	opsMu      sync.RWMutex
 	operations map[string]*Operation
 
+	// Grace period before a stopping container is killed
+	stopTimeout time.Duration
+
+	// Resource limits for instances that do not set their own
+	defaultResources models.ResourceLimits
+
+	// Resolves secrets granted to instances when their containers start
+	secrets SecretStore
+
+	// Encrypts sensitive config values stored in the database
+	configCipher *secrets.ConfigCipher
+
+	// Auto-restart of DEGRADED instances
+	restartPolicy RestartPolicy
+	restartMu     sync.Mutex
+	degradedSince map[string]time.Time
+
 	// Health monitoring
 	healthInterval time.Duration
 	healthCh       chan struct{}
@@ -41,6 +69,8 @@ func New(db *sql.DB, rt runtime.Provider, pol *policy.Engine) *Manager {
 		policy:         pol,
 		logger:         observability.Logger("lifecycle"),
 		operations:     make(map[string]*Operation),
+		stopTimeout:    30 * time.Second,
+		degradedSince:  make(map[string]time.Time),
 		healthInterval: 30 * time.Second,
 		healthCh:       make(chan struct{}),
 	}
@@ -51,6 +81,95 @@ func (m *Manager) SetHealthInterval(interval time.Duration) {
 	m.healthInterval = interval
 }
 
+// SetStopTimeout configures the default grace period for stopping containers.
+func (m *Manager) SetStopTimeout(timeout time.Duration) {
+	m.stopTimeout = timeout
+}
+
+// SetDefaultResources configures the resource limits applied to instance
+// containers that do not override them.
+func (m *Manager) SetDefaultResources(limits models.ResourceLimits) {
+	m.defaultResources = limits
+}
+
+// SetSecretStore configures where secrets referenced by instances are read
+// from. Without one, instances that reference secrets cannot start.
+func (m *Manager) SetSecretStore(store SecretStore) {
+	m.secrets = store
+}
+
+// SetConfigCipher configures encryption of sensitive instance config values
+// at rest. Without one, config is stored as given.
+func (m *Manager) SetConfigCipher(cipher *secrets.ConfigCipher) {
+	m.configCipher = cipher
+}
+
+// decryptConfig decrypts the encrypted values of a loaded instance's config.
+func (m *Manager) decryptConfig(inst *Instance) error {
+	if m.configCipher == nil {
+		return nil
+	}
+	config, err := m.configCipher.DecryptConfig(inst.Config)
+	if err != nil {
+		return fmt.Errorf("instance %s: %w", inst.InstanceID, err)
+	}
+	inst.Config = config
+	return nil
+}
+
+// effectiveResources merges per-instance resource overrides onto the defaults.
+func (m *Manager) effectiveResources(instance *Instance) runtime.ResourceSpec {
+	limits := m.defaultResources
+	if instance.Resources != nil {
+		if instance.Resources.MemoryMB > 0 {
+			limits.MemoryMB = instance.Resources.MemoryMB
+		}
+		if instance.Resources.CPUs > 0 {
+			limits.CPUs = instance.Resources.CPUs
+		}
+		if instance.Resources.CPUShares > 0 {
+			limits.CPUShares = instance.Resources.CPUShares
+		}
+	}
+	return runtime.ResourceSpec{
+		MemoryMB:  limits.MemoryMB,
+		CPUs:      limits.CPUs,
+		CPUShares: limits.CPUShares,
+	}
+}
+
+// networkSpec derives the container network from the instance's network grant.
+// Containers get no network unless egress is enabled by policy and granted to
+// the instance. The container runtimes cannot filter by host, so a grant
+// limited to a domain list, as stored by older versions, is refused rather
+// than widened to a bridge that allows every host.
+func (m *Manager) networkSpec(ctx context.Context, instanceID string) (runtime.NetworkSpec, error) {
+	none := runtime.NetworkSpec{Mode: "none"} // Default to no network for security
+
+	if m.policy == nil || !m.policy.EgressAllowed() {
+		return none, nil
+	}
+
+	perms, err := m.policy.GetEffectivePermissions(ctx, instanceID)
+	if err != nil || perms.Effective.Network.Mode != "egress" {
+		return none, nil
+	}
+
+	if domains := perms.Granted.Network.EgressDomains; len(domains) > 0 {
+		return none, models.NewError(models.ErrPermissionDenied, fmt.Sprintf(
+			"network egress is granted only to %s, but the container runtime cannot restrict egress by host; "+
+				"to allow all hosts run 'conduit policy revoke %s network' and 'conduit policy grant %s --net'",
+			strings.Join(domains, ", "), instanceID, instanceID))
+	}
+
+	return runtime.NetworkSpec{Mode: "bridge"}, nil
+}
+
+// SetRestartPolicy configures automatic restarts of DEGRADED instances.
+func (m *Manager) SetRestartPolicy(policy RestartPolicy) {
+	m.restartPolicy = policy
+}
+
 // Start begins background health monitoring.
 func (m *Manager) Start(ctx context.Context) {
 	m.wg.Add(1)
@@ -70,20 +189,50 @@ func (m *Manager) WaitForOperations() {
 
 // CreateInstance creates a new connector instance.
 func (m *Manager) CreateInstance(ctx context.Context, req CreateInstanceRequest) (*Instance, error) {
+	for key, value := range req.Config {
+		if err := secrets.ValidateRefs(value); err != nil {
+			return nil, models.Wrap(models.ErrConfigInvalid, fmt.Sprintf("config %s", key), err)
+		}
+	}
+
 	instanceID := uuid.New().String()
 
-	config, _ := json.Marshal(req.Config)
+	storedConfig := req.Config
+	if m.configCipher != nil {
+		var err error
+		if storedConfig, err = m.configCipher.EncryptConfig(req.Config); err != nil {
+			return nil, fmt.Errorf("encrypt config: %w", err)
+		}
+	}
+	config, _ := json.Marshal(storedConfig)
+
+	var resourceLimits sql.NullString
+	if req.Resources != nil {
+		data, _ := json.Marshal(req.Resources)
+		resourceLimits = sql.NullString{String: string(data), Valid: true}
+	}
 
 	_, err := m.db.ExecContext(ctx, `
 		INSERT INTO connector_instances
-		(instance_id, package_id, package_version, display_name, image_ref, config, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
-	`, instanceID, req.PackageID, req.Version, req.DisplayName, req.ImageRef, string(config), string(StatusCreated))
+		(instance_id, package_id, package_version, display_name, image_ref, config, status, resource_limits, platform, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'), datetime('now'))
+	`, instanceID, req.PackageID, req.Version, req.DisplayName, req.ImageRef, string(config), string(StatusCreated), resourceLimits,
+		sql.NullString{String: req.Platform, Valid: req.Platform != ""})
 
 	if err != nil {
 		return nil, fmt.Errorf("create instance: %w", err)
 	}
 
+	if req.Permissions != nil && m.policy != nil {
+		if err := m.policy.SetDeclaredPermissions(ctx, instanceID, *req.Permissions); err != nil {
+			// Don't leave an instance behind without the permissions it declares
+			if _, delErr := m.db.ExecContext(ctx, `DELETE FROM connector_instances WHERE instance_id = ?`, instanceID); delErr != nil {
+				m.logger.Error().Err(delErr).Str("instance_id", instanceID).Msg("failed to remove instance")
+			}
+			return nil, err
+		}
+	}
+
 	m.logger.Info().
 		Str("instance_id", instanceID).
 		Str("package_id", req.PackageID).
@@ -94,13 +243,20 @@ func (m *Manager) CreateInstance(ctx context.Context, req CreateInstanceRequest)
 
 // InstallInstance runs the installation flow for an instance.
 func (m *Manager) InstallInstance(ctx context.Context, instanceID string) (*Operation, error) {
+	return m.InstallInstanceWithOptions(ctx, instanceID, InstallOptions{})
+}
+
+// InstallInstanceWithOptions runs the installation flow for an instance in the background.
+// The returned operation can be polled with GetOperation.
+func (m *Manager) InstallInstanceWithOptions(ctx context.Context, instanceID string, opts InstallOptions) (*Operation, error) {
 	instance, err := m.GetInstance(ctx, instanceID)
 	if err != nil {
 		return nil, err
 	}
 
 	if !IsValidTransition(instance.Status, StatusAuditing) {
-		return nil, fmt.Errorf("cannot install instance in status %s", instance.Status)
+		return nil, models.NewError(models.ErrInvalidTransition,
+			fmt.Sprintf("cannot install instance in status %s", instance.Status))
 	}
 
 	// Create operation
@@ -110,14 +266,14 @@ func (m *Manager) InstallInstance(ctx context.Context, instanceID string) (*Oper
 	m.wg.Add(1)
 	go func() {
 		defer m.wg.Done()
-		m.runInstall(context.Background(), instanceID, op.OperationID)
+		m.runInstall(context.Background(), instanceID, op.OperationID, opts)
 	}()
 
 	return op, nil
 }
 
 // runInstall performs the installation steps.
-func (m *Manager) runInstall(ctx context.Context, instanceID, operationID string) {
+func (m *Manager) runInstall(ctx context.Context, instanceID, operationID string, opts InstallOptions) {
 	// Stage 1: Audit
 	m.updateOperation(operationID, "running", "auditing", 10, "")
 
@@ -138,14 +294,18 @@ func (m *Manager) runInstall(ctx context.Context, instanceID, operationID string
 	// Stage 2: Policy evaluation
 	m.updateOperation(operationID, "running", "policy_check", 25, "")
 
+	// Evaluate what the package declares; packages without a declaration request nothing
+	var requested policy.PermissionSet
+	if declared, err := m.policy.GetDeclaredPermissions(ctx, instanceID); err == nil && declared != nil {
+		requested = *declared
+	}
+
 	decision, err := m.policy.Evaluate(ctx, policy.Request{
 		Scope:      policy.ScopeInstall,
 		InstanceID: instanceID,
 		PackageID:  instance.PackageID,
 		Actor:      "system",
-		// For V0, we don't have declared permissions in the package
-		// So we just evaluate with empty permissions
-		Requested: policy.PermissionSet{},
+		Requested:  requested,
 	})
 	if err != nil {
 		m.failOperation(operationID, fmt.Sprintf("policy evaluation: %v", err))
@@ -161,7 +321,7 @@ func (m *Manager) runInstall(ctx context.Context, instanceID, operationID string
 	// Stage 3: Pull image
 	m.updateOperation(operationID, "running", "pulling", 40, "")
 
-	if instance.ImageRef != "" && m.runtime != nil {
+	if instance.ImageRef != "" && m.runtime != nil && !opts.SkipPull {
 		if err := m.runtime.Pull(ctx, instance.ImageRef, runtime.PullOptions{}); err != nil {
 			m.failOperation(operationID, fmt.Sprintf("image pull: %v", err))
 			return
@@ -184,6 +344,48 @@ func (m *Manager) runInstall(ctx context.Context, instanceID, operationID string
 		Msg("instance installed")
 }
 
+// StartInstanceAsync starts a connector instance in the background.
+// The returned operation can be polled with GetOperation.
+func (m *Manager) StartInstanceAsync(ctx context.Context, instanceID string) (*Operation, error) {
+	instance, err := m.GetInstance(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsValidTransition(instance.Status, StatusStarting) {
+		return nil, models.NewError(models.ErrInvalidTransition,
+			fmt.Sprintf("cannot start instance in status %s", instance.Status))
+	}
+
+	if m.runtime == nil {
+		return nil, models.NewError(models.ErrRuntimeUnavailable, "no runtime provider available")
+	}
+
+	op := m.createOperation("start", instanceID)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ctx := context.Background()
+		m.updateOperation(op.OperationID, "running", "starting", 10, "")
+
+		if err := m.StartInstance(ctx, instanceID); err != nil {
+			m.failOperation(op.OperationID, err.Error())
+			return
+		}
+
+		result := map[string]string{}
+		if inst, err := m.GetInstance(ctx, instanceID); err == nil {
+			result["status"] = string(inst.Status)
+			result["container_id"] = inst.ContainerID
+		}
+		m.completeOperation(op.OperationID, result)
+	}()
+
+	return op, nil
+}
+
 // StartInstance starts a connector instance.
 func (m *Manager) StartInstance(ctx context.Context, instanceID string) error {
 	instance, err := m.GetInstance(ctx, instanceID)
@@ -192,11 +394,23 @@ func (m *Manager) StartInstance(ctx context.Context, instanceID string) error {
 	}
 
 	if !IsValidTransition(instance.Status, StatusStarting) {
-		return fmt.Errorf("cannot start instance in status %s", instance.Status)
+		return models.NewError(models.ErrInvalidTransition,
+			fmt.Sprintf("cannot start instance in status %s", instance.Status))
 	}
 
 	if m.runtime == nil {
-		return fmt.Errorf("no runtime provider available")
+		return models.NewError(models.ErrRuntimeUnavailable, "no runtime provider available")
+	}
+
+	// Resolve secrets before changing state, so a missing grant or secret
+	// leaves the instance as it was
+	env, err := m.instanceEnv(ctx, instance)
+	if err != nil {
+		return err
+	}
+	network, err := m.networkSpec(ctx, instanceID)
+	if err != nil {
+		return err
 	}
 
 	// Transition to STARTING
@@ -217,17 +431,21 @@ func (m *Manager) StartInstance(ctx context.Context, instanceID string) error {
 			NoNewPrivileges: true,
 			DropCapabilities: []string{"ALL"},
 		},
-		Network: runtime.NetworkSpec{
-			Mode: "none", // Default to no network for security
-		},
-		Stdin: true, // MCP servers need stdin
+		Network:   network,
+		Resources: m.effectiveResources(instance),
+		Stdin:     true, // MCP servers need stdin
+		Platform:  instance.Platform,
+		Env:       env,
 	}
 
-	// Add config as environment variables
-	if instance.Config != nil {
-		spec.Env = make(map[string]string)
-		for k, v := range instance.Config {
-			spec.Env[k] = v
+	// Remove the container from a previous run, since its name is reused
+	if instance.ContainerID != "" {
+		if err := m.runtime.Remove(ctx, instance.ContainerID, true); err != nil {
+			m.logger.Debug().
+				Err(err).
+				Str("instance_id", instanceID).
+				Str("container_id", instance.ContainerID).
+				Msg("previous container not removed")
 		}
 	}
 
@@ -236,7 +454,7 @@ func (m *Manager) StartInstance(ctx context.Context, instanceID string) error {
 	if err != nil {
 		m.transitionTo(ctx, instanceID, StatusDegraded)
 		m.updateInstanceError(ctx, instanceID, fmt.Sprintf("container start failed: %v", err))
-		return fmt.Errorf("container start: %w", err)
+		return models.Wrap(models.ErrContainerFailed, "container start failed", err)
 	}
 
 	// Update instance with container ID
@@ -269,19 +487,114 @@ func (m *Manager) StartInstance(ctx context.Context, instanceID string) error {
 	return nil
 }
 
-// StopInstance stops a running connector instance.
+// instanceEnv builds a container's environment from the instance config and
+// the secrets granted to it; see ResolveEnv.
+func (m *Manager) instanceEnv(ctx context.Context, instance *Instance) (map[string]string, error) {
+	var granted []policy.SecretRef
+	if m.policy != nil {
+		perms, err := m.policy.GetEffectivePermissions(ctx, instance.InstanceID)
+		if err != nil {
+			return nil, fmt.Errorf("get permissions: %w", err)
+		}
+		granted = perms.Effective.Secrets
+	}
+	return ResolveEnv(instance.InstanceID, instance.Config, granted, m.secrets, m.logger)
+}
+
+// ResolveEnv builds a container's environment: granted secrets under their
+// bound env keys, then config with ${secret:NAME} references replaced by the
+// secret values. Config only ever stores the references, so the values exist
+// only in the container spec.
+//
+// A secret must be granted to the instance to be used. Granted bindings
+// whose secret has not been stored are skipped with a warning; a config
+// reference that cannot be resolved is an error.
+func ResolveEnv(instanceID string, config map[string]string, granted []policy.SecretRef, store SecretStore, logger zerolog.Logger) (map[string]string, error) {
+	grantedNames := make(map[string]bool, len(granted))
+	for _, ref := range granted {
+		grantedNames[ref.SecretID] = true
+	}
+
+	lookup := func(name string) (string, error) {
+		if !grantedNames[name] {
+			return "", models.NewError(models.ErrPermissionRequired,
+				fmt.Sprintf("secret %q is not granted to this instance: run 'conduit policy grant %s --secret %s'",
+					name, instanceID, name))
+		}
+		if store == nil {
+			return "", models.NewError(models.ErrSecretAccess, "no secret store configured")
+		}
+		value, err := store.Get(name)
+		if errors.Is(err, secrets.ErrNotFound) {
+			return "", models.NewError(models.ErrSecretNotFound,
+				fmt.Sprintf("secret %q not found: add it with 'conduit secrets set %s'", name, name))
+		}
+		if err != nil {
+			return "", models.Wrap(models.ErrSecretAccess, fmt.Sprintf("read secret %q", name), err)
+		}
+		return value, nil
+	}
+
+	env := make(map[string]string)
+	for _, ref := range granted {
+		if ref.EnvKey == "" {
+			continue
+		}
+		value, err := lookup(ref.SecretID)
+		if err != nil {
+			logger.Warn().
+				Err(err).
+				Str("instance_id", instanceID).
+				Str("secret", ref.SecretID).
+				Msg("granted secret not injected")
+			continue
+		}
+		env[ref.EnvKey] = value
+	}
+
+	for k, v := range config {
+		value, err := secrets.Expand(v, lookup)
+		if err != nil {
+			var cerr *models.ConduitError
+			if errors.As(err, &cerr) {
+				return nil, cerr.WithDetails("env", k)
+			}
+			return nil, models.Wrap(models.ErrConfigInvalid, fmt.Sprintf("config %s", k), err)
+		}
+		env[k] = value
+	}
+
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
+
+// StopInstance stops a running connector instance using the default stop timeout.
 func (m *Manager) StopInstance(ctx context.Context, instanceID string) error {
+	return m.StopInstanceWithTimeout(ctx, instanceID, 0)
+}
+
+// StopInstanceWithTimeout stops a running connector instance. The container
+// gets timeout to exit after SIGTERM before it is killed; zero uses the
+// manager's default.
+func (m *Manager) StopInstanceWithTimeout(ctx context.Context, instanceID string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = m.stopTimeout
+	}
+
 	instance, err := m.GetInstance(ctx, instanceID)
 	if err != nil {
 		return err
 	}
 
 	if !IsValidTransition(instance.Status, StatusStopping) {
-		return fmt.Errorf("cannot stop instance in status %s", instance.Status)
+		return models.NewError(models.ErrInvalidTransition,
+			fmt.Sprintf("cannot stop instance in status %s", instance.Status))
 	}
 
 	if m.runtime == nil {
-		return fmt.Errorf("no runtime provider available")
+		return models.NewError(models.ErrRuntimeUnavailable, "no runtime provider available")
 	}
 
 	// Transition to STOPPING
@@ -291,7 +604,7 @@ func (m *Manager) StopInstance(ctx context.Context, instanceID string) error {
 
 	// Stop container
 	if instance.ContainerID != "" {
-		if err := m.runtime.Stop(ctx, instance.ContainerID, 30*time.Second); err != nil {
+		if err := m.runtime.Stop(ctx, instance.ContainerID, timeout); err != nil {
 			m.logger.Warn().
 				Err(err).
 				Str("instance_id", instanceID).
@@ -322,6 +635,39 @@ func (m *Manager) StopInstance(ctx context.Context, instanceID string) error {
 	return nil
 }
 
+// RestartInstance stops a RUNNING or DEGRADED instance, honoring the stop grace
+// period, and starts it again with a fresh container. If the stop phase fails the
+// instance is left as the stop left it; if the start phase fails it ends up
+// STOPPED or DEGRADED like a regular failed start.
+func (m *Manager) RestartInstance(ctx context.Context, instanceID string, stopTimeout time.Duration) error {
+	instance, err := m.GetInstance(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+
+	if instance.Status != StatusRunning && instance.Status != StatusDegraded {
+		return models.NewError(models.ErrInvalidTransition,
+			fmt.Sprintf("cannot restart instance in status %s", instance.Status))
+	}
+
+	if m.runtime == nil {
+		return models.NewError(models.ErrRuntimeUnavailable, "no runtime provider available")
+	}
+
+	if err := m.StopInstanceWithTimeout(ctx, instanceID, stopTimeout); err != nil {
+		return fmt.Errorf("restart: stop phase: %w", err)
+	}
+	if err := m.StartInstance(ctx, instanceID); err != nil {
+		return fmt.Errorf("restart: start phase: %w", err)
+	}
+
+	m.logger.Info().
+		Str("instance_id", instanceID).
+		Msg("instance restarted")
+
+	return nil
+}
+
 // DisableInstance disables a connector instance.
 func (m *Manager) DisableInstance(ctx context.Context, instanceID string) error {
 	instance, err := m.GetInstance(ctx, instanceID)
@@ -361,9 +707,17 @@ func (m *Manager) EnableInstance(ctx context.Context, instanceID string) error {
 
 // RemoveInstance removes a connector instance.
 func (m *Manager) RemoveInstance(ctx context.Context, instanceID string) error {
+	_, err := m.RemoveInstanceWithOptions(ctx, instanceID, RemoveOptions{})
+	return err
+}
+
+// RemoveInstanceWithOptions stops a connector instance, removes its container
+// and deletes it. With PurgeImage, the image is removed too once no other
+// instance uses it; failing to remove the image does not fail the removal.
+func (m *Manager) RemoveInstanceWithOptions(ctx context.Context, instanceID string, opts RemoveOptions) (*RemoveResult, error) {
 	instance, err := m.GetInstance(ctx, instanceID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Stop if running
@@ -386,14 +740,43 @@ func (m *Manager) RemoveInstance(ctx context.Context, instanceID string) error {
 	// Delete from database
 	_, err = m.db.ExecContext(ctx, `DELETE FROM connector_instances WHERE instance_id = ?`, instanceID)
 	if err != nil {
-		return fmt.Errorf("delete instance: %w", err)
+		return nil, fmt.Errorf("delete instance: %w", err)
 	}
 
 	m.logger.Info().
 		Str("instance_id", instanceID).
 		Msg("instance removed")
 
-	return nil
+	result := &RemoveResult{InstanceID: instanceID, ImageRef: instance.ImageRef}
+	if opts.PurgeImage && instance.ImageRef != "" {
+		result.ImageRemoved, result.ImageKept = m.purgeImage(ctx, instance.ImageRef)
+	}
+	return result, nil
+}
+
+// purgeImage removes an image that no remaining instance uses. When the
+// image is kept, reason says why.
+func (m *Manager) purgeImage(ctx context.Context, imageRef string) (removed bool, reason string) {
+	if m.runtime == nil {
+		return false, "no container runtime available"
+	}
+
+	var users int
+	if err := m.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM connector_instances WHERE image_ref = ?`, imageRef,
+	).Scan(&users); err != nil {
+		m.logger.Warn().Err(err).Str("image", imageRef).Msg("failed to check image usage")
+		return false, "could not check whether other instances use it"
+	}
+	if users > 0 {
+		return false, fmt.Sprintf("used by %d other instance(s)", users)
+	}
+
+	if err := m.runtime.RemoveImage(ctx, imageRef); err != nil {
+		m.logger.Warn().Err(err).Str("image", imageRef).Msg("failed to remove image")
+		return false, err.Error()
+	}
+	return true, ""
 }
 
 // GetInstance retrieves an instance by ID.
@@ -401,7 +784,7 @@ func (m *Manager) GetInstance(ctx context.Context, instanceID string) (*Instance
 	row := m.db.QueryRowContext(ctx, `
 		SELECT instance_id, package_id, package_version, display_name, image_ref, status,
 		       container_id, socket_path, config, error_message, created_at, updated_at,
-		       started_at, stopped_at
+		       started_at, stopped_at, restart_count, last_restart_at, resource_limits, platform
 		FROM connector_instances
 		WHERE instance_id = ?
 	`, instanceID)
@@ -409,16 +792,17 @@ func (m *Manager) GetInstance(ctx context.Context, instanceID string) (*Instance
 	var inst Instance
 	var containerID, socketPath, config, errorMsg sql.NullString
 	var createdAt, updatedAt string
-	var startedAt, stoppedAt sql.NullString
+	var startedAt, stoppedAt, lastRestartAt, resourceLimits, platform sql.NullString
 
 	err := row.Scan(
 		&inst.InstanceID, &inst.PackageID, &inst.PackageVersion,
 		&inst.DisplayName, &inst.ImageRef, &inst.Status,
 		&containerID, &socketPath, &config, &errorMsg,
 		&createdAt, &updatedAt, &startedAt, &stoppedAt,
+		&inst.RestartCount, &lastRestartAt, &resourceLimits, &platform,
 	)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("instance not found: %s", instanceID)
+		return nil, models.NewError(models.ErrInstanceNotFound, "instance not found: "+instanceID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("scan instance: %w", err)
@@ -436,6 +820,9 @@ func (m *Manager) GetInstance(ctx context.Context, instanceID string) (*Instance
 	}
 	if config.Valid && config.String != "" {
 		json.Unmarshal([]byte(config.String), &inst.Config)
+		if err := m.decryptConfig(&inst); err != nil {
+			return nil, err
+		}
 	}
 	if errorMsg.Valid {
 		inst.ErrorMessage = errorMsg.String
@@ -448,6 +835,14 @@ func (m *Manager) GetInstance(ctx context.Context, instanceID string) (*Instance
 		t, _ := time.Parse("2006-01-02 15:04:05", stoppedAt.String)
 		inst.StoppedAt = &t
 	}
+	if lastRestartAt.Valid {
+		t, _ := time.Parse(time.RFC3339, lastRestartAt.String)
+		inst.LastRestartAt = &t
+	}
+	if resourceLimits.Valid && resourceLimits.String != "" {
+		json.Unmarshal([]byte(resourceLimits.String), &inst.Resources)
+	}
+	inst.Platform = platform.String
 
 	return &inst, nil
 }
@@ -457,7 +852,7 @@ func (m *Manager) ListInstances(ctx context.Context) ([]*Instance, error) {
 	rows, err := m.db.QueryContext(ctx, `
 		SELECT instance_id, package_id, package_version, display_name, image_ref, status,
 		       container_id, socket_path, config, error_message, created_at, updated_at,
-		       started_at, stopped_at
+		       started_at, stopped_at, restart_count, last_restart_at, resource_limits, platform
 		FROM connector_instances
 		ORDER BY created_at DESC
 	`)
@@ -471,13 +866,14 @@ func (m *Manager) ListInstances(ctx context.Context) ([]*Instance, error) {
 		var inst Instance
 		var containerID, socketPath, config, errorMsg sql.NullString
 		var createdAt, updatedAt string
-		var startedAt, stoppedAt sql.NullString
+		var startedAt, stoppedAt, lastRestartAt, resourceLimits, platform sql.NullString
 
 		err := rows.Scan(
 			&inst.InstanceID, &inst.PackageID, &inst.PackageVersion,
 			&inst.DisplayName, &inst.ImageRef, &inst.Status,
 			&containerID, &socketPath, &config, &errorMsg,
 			&createdAt, &updatedAt, &startedAt, &stoppedAt,
+			&inst.RestartCount, &lastRestartAt, &resourceLimits, &platform,
 		)
 		if err != nil {
 			continue
@@ -495,6 +891,9 @@ func (m *Manager) ListInstances(ctx context.Context) ([]*Instance, error) {
 		}
 		if config.Valid && config.String != "" {
 			json.Unmarshal([]byte(config.String), &inst.Config)
+			if err := m.decryptConfig(&inst); err != nil {
+				return nil, err
+			}
 		}
 		if errorMsg.Valid {
 			inst.ErrorMessage = errorMsg.String
@@ -507,6 +906,14 @@ func (m *Manager) ListInstances(ctx context.Context) ([]*Instance, error) {
 			t, _ := time.Parse("2006-01-02 15:04:05", stoppedAt.String)
 			inst.StoppedAt = &t
 		}
+		if lastRestartAt.Valid {
+			t, _ := time.Parse(time.RFC3339, lastRestartAt.String)
+			inst.LastRestartAt = &t
+		}
+		if resourceLimits.Valid && resourceLimits.String != "" {
+			json.Unmarshal([]byte(resourceLimits.String), &inst.Resources)
+		}
+		inst.Platform = platform.String
 
 		instances = append(instances, &inst)
 	}
@@ -564,12 +971,24 @@ func (m *Manager) RunHealthChecks(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
 
+	// Collect the IDs first: the store allows a single connection, so the
+	// queries below would block while the cursor is open
+	var instanceIDs []string
 	for rows.Next() {
 		var instanceID string
-		rows.Scan(&instanceID)
+		if err := rows.Scan(&instanceID); err != nil {
+			rows.Close()
+			return err
+		}
+		instanceIDs = append(instanceIDs, instanceID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
 
+	for _, instanceID := range instanceIDs {
 		health, err := m.CheckHealth(ctx, instanceID)
 		if err != nil {
 			continue
@@ -582,26 +1001,129 @@ func (m *Manager) RunHealthChecks(ctx context.Context) error {
 
 		// Update status based on health
 		if health.Status == "healthy" && instance.Status == StatusDegraded {
-			m.transitionTo(ctx, instanceID, StatusRunning)
+			if m.transitionTo(ctx, instanceID, StatusRunning) == nil {
+				instance.Status = StatusRunning
+			}
 		} else if health.Status == "unhealthy" && instance.Status == StatusRunning {
-			m.transitionTo(ctx, instanceID, StatusDegraded)
+			if m.transitionTo(ctx, instanceID, StatusDegraded) == nil {
+				instance.Status = StatusDegraded
+			}
+		}
+
+		if m.restartPolicy.Enabled {
+			m.autoRestart(ctx, instance)
 		}
 	}
 
 	return nil
 }
 
+// autoRestart restarts an instance that has been DEGRADED for longer than the
+// restart policy threshold, backing off exponentially between attempts. After
+// MaxAttempts the instance is left DEGRADED with an error message.
+func (m *Manager) autoRestart(ctx context.Context, instance *Instance) {
+	restartPolicy := m.restartPolicy
+	now := time.Now()
+
+	m.restartMu.Lock()
+	if instance.Status != StatusDegraded {
+		delete(m.degradedSince, instance.InstanceID)
+		m.restartMu.Unlock()
+
+		// Forget earlier attempts once the instance has been stable for a full backoff window
+		if instance.Status == StatusRunning && instance.RestartCount > 0 &&
+			instance.LastRestartAt != nil && now.Sub(*instance.LastRestartAt) > restartPolicy.MaxBackoff {
+			m.db.ExecContext(ctx, `
+				UPDATE connector_instances SET restart_count = 0 WHERE instance_id = ?
+			`, instance.InstanceID)
+		}
+		return
+	}
+	since, tracked := m.degradedSince[instance.InstanceID]
+	if !tracked {
+		since = now
+		m.degradedSince[instance.InstanceID] = since
+	}
+	m.restartMu.Unlock()
+
+	if now.Sub(since) < restartPolicy.DegradedThreshold {
+		return
+	}
+
+	if instance.RestartCount >= restartPolicy.MaxAttempts {
+		msg := fmt.Sprintf("auto-restart gave up after %d attempts", instance.RestartCount)
+		if instance.ErrorMessage != msg {
+			m.updateInstanceError(ctx, instance.InstanceID, msg)
+			m.logger.Warn().
+				Str("instance_id", instance.InstanceID).
+				Int("attempts", instance.RestartCount).
+				Msg("auto-restart gave up")
+		}
+		return
+	}
+
+	attempt := instance.RestartCount + 1
+	if instance.LastRestartAt != nil && now.Sub(*instance.LastRestartAt) < restartPolicy.Backoff(attempt) {
+		return
+	}
+
+	_, err := m.db.ExecContext(ctx, `
+		UPDATE connector_instances
+		SET restart_count = ?, last_restart_at = ?
+		WHERE instance_id = ?
+	`, attempt, now.UTC().Format(time.RFC3339), instance.InstanceID)
+	if err != nil {
+		m.logger.Warn().Err(err).Str("instance_id", instance.InstanceID).Msg("failed to record restart attempt")
+		return
+	}
+
+	m.logger.Info().
+		Str("instance_id", instance.InstanceID).
+		Int("attempt", attempt).
+		Int("max_attempts", restartPolicy.MaxAttempts).
+		Msg("auto-restarting degraded instance")
+
+	if err := m.RestartInstance(ctx, instance.InstanceID, 0); err != nil {
+		m.updateInstanceError(ctx, instance.InstanceID,
+			fmt.Sprintf("auto-restart attempt %d failed: %v", attempt, err))
+	}
+}
+
 // GetOperation retrieves an operation by ID.
+// The returned value is a snapshot and is not updated as the operation progresses.
 func (m *Manager) GetOperation(ctx context.Context, operationID string) (*Operation, error) {
 	m.opsMu.RLock()
 	defer m.opsMu.RUnlock()
 
 	op, exists := m.operations[operationID]
 	if !exists {
-		return nil, fmt.Errorf("operation not found: %s", operationID)
+		return nil, models.NewError(models.ErrOperationNotFound, "operation not found: "+operationID)
 	}
 
-	return op, nil
+	snapshot := *op
+	return &snapshot, nil
+}
+
+// ListOperations returns snapshots of tracked operations, newest first.
+// If activeOnly is set, only pending and running operations are returned.
+func (m *Manager) ListOperations(ctx context.Context, activeOnly bool) []*Operation {
+	m.opsMu.RLock()
+	defer m.opsMu.RUnlock()
+
+	ops := make([]*Operation, 0, len(m.operations))
+	for _, op := range m.operations {
+		if activeOnly && !op.IsActive() {
+			continue
+		}
+		snapshot := *op
+		ops = append(ops, &snapshot)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].CreatedAt.After(ops[j].CreatedAt)
+	})
+
+	return ops
 }
 
 // transitionTo changes instance status.
@@ -656,49 +1178,159 @@ func (m *Manager) createOperation(opType, instanceID string) *Operation {
 
 	m.opsMu.Lock()
 	m.operations[op.OperationID] = op
+	snapshot := *op
 	m.opsMu.Unlock()
 
+	m.persistOperation(&snapshot)
+
 	return op
 }
 
 // updateOperation updates operation progress.
 func (m *Manager) updateOperation(operationID, status, stage string, progress int, errorMsg string) {
-	m.opsMu.Lock()
-	defer m.opsMu.Unlock()
-
-	if op, exists := m.operations[operationID]; exists {
+	m.mutateOperation(operationID, func(op *Operation) {
 		op.Status = status
 		op.CurrentStage = stage
 		op.Progress = progress
 		op.Error = errorMsg
-	}
+	})
 }
 
 // completeOperation marks an operation as complete.
 func (m *Manager) completeOperation(operationID string, result interface{}) {
-	m.opsMu.Lock()
-	defer m.opsMu.Unlock()
-
-	if op, exists := m.operations[operationID]; exists {
+	m.mutateOperation(operationID, func(op *Operation) {
 		op.Status = "completed"
 		op.Progress = 100
 		op.Result = result
 		now := time.Now()
 		op.CompletedAt = &now
-	}
+	})
 }
 
 // failOperation marks an operation as failed.
 func (m *Manager) failOperation(operationID, errorMsg string) {
-	m.opsMu.Lock()
-	defer m.opsMu.Unlock()
-
-	if op, exists := m.operations[operationID]; exists {
+	m.mutateOperation(operationID, func(op *Operation) {
 		op.Status = "failed"
 		op.Error = errorMsg
 		now := time.Now()
 		op.CompletedAt = &now
+	})
+}
+
+// mutateOperation applies fn to a tracked operation and persists the result.
+func (m *Manager) mutateOperation(operationID string, fn func(op *Operation)) {
+	m.opsMu.Lock()
+	op, exists := m.operations[operationID]
+	if !exists {
+		m.opsMu.Unlock()
+		return
 	}
+	fn(op)
+	snapshot := *op
+	m.opsMu.Unlock()
+
+	m.persistOperation(&snapshot)
+}
+
+// persistOperation writes the operation state to the operations table.
+// Failures are logged but do not interrupt the operation itself.
+func (m *Manager) persistOperation(op *Operation) {
+	var result sql.NullString
+	if op.Result != nil {
+		if data, err := json.Marshal(op.Result); err == nil {
+			result = sql.NullString{String: string(data), Valid: true}
+		}
+	}
+
+	var completedAt sql.NullString
+	if op.CompletedAt != nil {
+		completedAt = sql.NullString{String: op.CompletedAt.UTC().Format(time.RFC3339Nano), Valid: true}
+	}
+
+	_, err := m.db.Exec(`
+		INSERT INTO operations
+		(operation_id, type, instance_id, status, stage, progress, error, result, created_at, updated_at, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(operation_id) DO UPDATE SET
+			status = excluded.status,
+			stage = excluded.stage,
+			progress = excluded.progress,
+			error = excluded.error,
+			result = excluded.result,
+			updated_at = excluded.updated_at,
+			completed_at = excluded.completed_at
+	`, op.OperationID, op.Type, op.InstanceID, op.Status, op.CurrentStage, op.Progress, op.Error, result,
+		op.CreatedAt.UTC().Format(time.RFC3339Nano), time.Now().UTC().Format(time.RFC3339Nano), completedAt)
+	if err != nil {
+		m.logger.Warn().
+			Err(err).
+			Str("operation_id", op.OperationID).
+			Msg("failed to persist operation")
+	}
+}
+
+// RestoreOperations reloads persisted operations after a daemon restart.
+// Finished operations older than retention are pruned. Operations that were
+// still pending or running when the daemon stopped can no longer make progress,
+// so they are marked as failed.
+func (m *Manager) RestoreOperations(ctx context.Context, retention time.Duration) error {
+	now := time.Now().UTC()
+
+	if retention > 0 {
+		cutoff := now.Add(-retention).Format(time.RFC3339Nano)
+		if _, err := m.db.ExecContext(ctx, `
+			DELETE FROM operations
+			WHERE status NOT IN ('pending', 'running') AND created_at < ?
+		`, cutoff); err != nil {
+			return fmt.Errorf("prune operations: %w", err)
+		}
+	}
+
+	if _, err := m.db.ExecContext(ctx, `
+		UPDATE operations
+		SET status = 'failed', error = 'interrupted by daemon restart', updated_at = ?, completed_at = ?
+		WHERE status IN ('pending', 'running')
+	`, now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano)); err != nil {
+		return fmt.Errorf("mark interrupted operations: %w", err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT operation_id, type, instance_id, status, stage, progress, error, result, created_at, completed_at
+		FROM operations
+	`)
+	if err != nil {
+		return fmt.Errorf("query operations: %w", err)
+	}
+	defer rows.Close()
+
+	m.opsMu.Lock()
+	defer m.opsMu.Unlock()
+
+	for rows.Next() {
+		var op Operation
+		var stage, errorMsg, result, completedAt sql.NullString
+		var createdAt string
+
+		if err := rows.Scan(&op.OperationID, &op.Type, &op.InstanceID, &op.Status, &stage,
+			&op.Progress, &errorMsg, &result, &createdAt, &completedAt); err != nil {
+			continue
+		}
+
+		op.CurrentStage = stage.String
+		op.Error = errorMsg.String
+		if result.Valid && result.String != "" {
+			json.Unmarshal([]byte(result.String), &op.Result)
+		}
+		op.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+		if completedAt.Valid {
+			t, _ := time.Parse(time.RFC3339Nano, completedAt.String)
+			op.CompletedAt = &t
+		}
+
+		m.operations[op.OperationID] = &op
+	}
+
+	return rows.Err()
 }
 
 // healthMonitorLoop runs periodic health checks.
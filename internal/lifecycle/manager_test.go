@@ -2,12 +2,17 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/simpleflo/conduit/internal/policy"
+	"github.com/simpleflo/conduit/internal/runtime"
+	"github.com/simpleflo/conduit/internal/secrets"
 	"github.com/simpleflo/conduit/internal/store"
+	"github.com/simpleflo/conduit/pkg/models"
 )
 
 func TestNew(t *testing.T) {
@@ -47,6 +52,7 @@ func TestManager_CreateInstance(t *testing.T) {
 		Config: map[string]string{
 			"key": "value",
 		},
+		Platform: "linux/arm64",
 	}
 
 	instance, err := m.CreateInstance(ctx, req)
@@ -63,6 +69,42 @@ func TestManager_CreateInstance(t *testing.T) {
 	if instance.Status != StatusCreated {
 		t.Errorf("Status mismatch: got %s, want %s", instance.Status, StatusCreated)
 	}
+	if instance.Platform != req.Platform {
+		t.Errorf("Platform mismatch: got %s, want %s", instance.Platform, req.Platform)
+	}
+}
+
+func TestManager_CreateInstanceRemovesInstanceWhenPermissionsFail(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	// A policy engine on another database cannot find the new instance
+	other := testStore(t)
+	defer other.Close()
+
+	m := New(st.DB(), nil, policy.New(other.DB()))
+	ctx := context.Background()
+
+	_, err := m.CreateInstance(ctx, CreateInstanceRequest{
+		PackageID:   "test/connector",
+		Version:     "1.0.0",
+		ImageRef:    "ghcr.io/test/connector:1.0.0",
+		Permissions: &policy.PermissionSet{Network: policy.NetworkPerms{Mode: "egress"}},
+	})
+	if err == nil {
+		t.Fatal("expected CreateInstance to fail when permissions cannot be stored")
+	}
+
+	instances, err := m.ListInstances(ctx)
+	if err != nil {
+		t.Fatalf("ListInstances failed: %v", err)
+	}
+	if len(instances) != 0 {
+		t.Errorf("expected the instance to be removed, got %d instances", len(instances))
+	}
 }
 
 func TestManager_GetInstance(t *testing.T) {
@@ -187,6 +229,70 @@ func TestManager_RemoveInstance(t *testing.T) {
 	}
 }
 
+// imageRuntime records image removals; other runtime calls are not expected.
+type imageRuntime struct {
+	runtime.Provider
+	removed []string
+}
+
+func (r *imageRuntime) RemoveImage(ctx context.Context, image string) error {
+	r.removed = append(r.removed, image)
+	return nil
+}
+
+func TestManager_RemoveInstancePurgeImage(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	rt := &imageRuntime{}
+	m := New(st.DB(), rt, policy.New(st.DB()))
+	ctx := context.Background()
+
+	create := func(image string) string {
+		inst, err := m.CreateInstance(ctx, CreateInstanceRequest{
+			PackageID:   "test/connector",
+			Version:     "1.0.0",
+			DisplayName: "Test Connector",
+			ImageRef:    image,
+		})
+		if err != nil {
+			t.Fatalf("CreateInstance failed: %v", err)
+		}
+		return inst.InstanceID
+	}
+	first, second := create("conduit-mcp-shared:latest"), create("conduit-mcp-shared:latest")
+	other := create("conduit-mcp-other:latest")
+
+	// The image is still used by the second instance
+	result, err := m.RemoveInstanceWithOptions(ctx, first, RemoveOptions{PurgeImage: true})
+	if err != nil {
+		t.Fatalf("RemoveInstanceWithOptions failed: %v", err)
+	}
+	if result.ImageRemoved || !strings.Contains(result.ImageKept, "1 other instance") {
+		t.Errorf("expected the shared image to be kept, got %+v", result)
+	}
+
+	result, err = m.RemoveInstanceWithOptions(ctx, second, RemoveOptions{PurgeImage: true})
+	if err != nil {
+		t.Fatalf("RemoveInstanceWithOptions failed: %v", err)
+	}
+	if !result.ImageRemoved || result.ImageRef != "conduit-mcp-shared:latest" {
+		t.Errorf("expected the image to be removed with its last instance, got %+v", result)
+	}
+
+	// Without PurgeImage the image stays
+	if err := m.RemoveInstance(ctx, other); err != nil {
+		t.Fatalf("RemoveInstance failed: %v", err)
+	}
+
+	if len(rt.removed) != 1 || rt.removed[0] != "conduit-mcp-shared:latest" {
+		t.Errorf("expected only the shared image to be removed, got %v", rt.removed)
+	}
+}
+
 func TestManager_SetHealthInterval(t *testing.T) {
 	st := testStore(t)
 	if st == nil {
@@ -281,6 +387,404 @@ func TestManager_CheckHealthNoContainer(t *testing.T) {
 	}
 }
 
+// statusRuntime reports every container in a fixed state.
+type statusRuntime struct {
+	runtime.Provider
+	status string
+}
+
+func (r *statusRuntime) Status(ctx context.Context, containerID string) (string, error) {
+	return r.status, nil
+}
+
+func TestManager_RunHealthChecks(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	m := New(st.DB(), &statusRuntime{status: "exited"}, policy.New(st.DB()))
+	ctx := context.Background()
+
+	instance, err := m.CreateInstance(ctx, CreateInstanceRequest{
+		PackageID:   "test/connector",
+		Version:     "1.0.0",
+		DisplayName: "Test Connector",
+		ImageRef:    "ghcr.io/test/connector:1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+	_, err = st.DB().Exec(`
+		UPDATE connector_instances SET status = 'RUNNING', container_id = 'c1' WHERE instance_id = ?
+	`, instance.InstanceID)
+	if err != nil {
+		t.Fatalf("failed to update instance: %v", err)
+	}
+
+	// The store has a single connection, so holding a cursor while
+	// checking an instance would hang here
+	done := make(chan error, 1)
+	go func() { done <- m.RunHealthChecks(ctx) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RunHealthChecks failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunHealthChecks did not return")
+	}
+
+	instance, _ = m.GetInstance(ctx, instance.InstanceID)
+	if instance.Status != StatusDegraded {
+		t.Errorf("expected the exited instance to be DEGRADED, got %s", instance.Status)
+	}
+}
+
+func TestManager_ListOperations(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	pol := policy.New(st.DB())
+	m := New(st.DB(), nil, pol)
+	ctx := context.Background()
+
+	done := m.createOperation("install", "inst-1")
+	m.completeOperation(done.OperationID, nil)
+	running := m.createOperation("start", "inst-2")
+	m.updateOperation(running.OperationID, "running", "starting", 10, "")
+
+	all := m.ListOperations(ctx, false)
+	if len(all) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(all))
+	}
+
+	active := m.ListOperations(ctx, true)
+	if len(active) != 1 || active[0].OperationID != running.OperationID {
+		t.Fatalf("expected only the running operation, got %+v", active)
+	}
+
+	op, err := m.GetOperation(ctx, running.OperationID)
+	if err != nil {
+		t.Fatalf("GetOperation failed: %v", err)
+	}
+	if op.CurrentStage != "starting" || op.Progress != 10 {
+		t.Errorf("unexpected operation state: stage=%s progress=%d", op.CurrentStage, op.Progress)
+	}
+
+	if _, err := m.GetOperation(ctx, "missing"); err == nil {
+		t.Error("expected error for unknown operation")
+	}
+}
+
+func TestManager_RestoreOperations(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	pol := policy.New(st.DB())
+	m := New(st.DB(), nil, pol)
+	ctx := context.Background()
+
+	done := m.createOperation("install", "inst-1")
+	m.completeOperation(done.OperationID, map[string]string{"status": "installed"})
+	running := m.createOperation("start", "inst-2")
+	m.updateOperation(running.OperationID, "running", "starting", 10, "")
+
+	// Simulate a daemon restart with a fresh manager on the same database
+	restarted := New(st.DB(), nil, pol)
+	if err := restarted.RestoreOperations(ctx, 24*time.Hour); err != nil {
+		t.Fatalf("RestoreOperations failed: %v", err)
+	}
+
+	op, err := restarted.GetOperation(ctx, done.OperationID)
+	if err != nil {
+		t.Fatalf("GetOperation failed: %v", err)
+	}
+	if op.Status != "completed" || op.Progress != 100 || op.CompletedAt == nil {
+		t.Errorf("unexpected restored operation: %+v", op)
+	}
+
+	op, err = restarted.GetOperation(ctx, running.OperationID)
+	if err != nil {
+		t.Fatalf("GetOperation failed: %v", err)
+	}
+	if op.Status != "failed" || op.Error == "" {
+		t.Errorf("expected interrupted operation to be failed, got %+v", op)
+	}
+
+	if active := restarted.ListOperations(ctx, true); len(active) != 0 {
+		t.Errorf("expected no active operations after restore, got %d", len(active))
+	}
+}
+
+func TestManager_RestartInstanceInvalidState(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	pol := policy.New(st.DB())
+	m := New(st.DB(), nil, pol)
+	ctx := context.Background()
+
+	instance, err := m.CreateInstance(ctx, CreateInstanceRequest{
+		PackageID:   "test/connector",
+		Version:     "1.0.0",
+		DisplayName: "Test Connector",
+		ImageRef:    "ghcr.io/test/connector:1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	// CREATED instances have no container to restart
+	err = m.RestartInstance(ctx, instance.InstanceID, 0)
+	var conduitErr *models.ConduitError
+	if !errors.As(err, &conduitErr) || conduitErr.Code != models.ErrInvalidTransition {
+		t.Errorf("expected invalid transition error, got %v", err)
+	}
+}
+
+func TestManager_EffectiveResources(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	pol := policy.New(st.DB())
+	m := New(st.DB(), nil, pol)
+	m.SetDefaultResources(models.ResourceLimits{MemoryMB: 512, CPUs: 1.0})
+	ctx := context.Background()
+
+	instance, err := m.CreateInstance(ctx, CreateInstanceRequest{
+		PackageID:   "test/connector",
+		Version:     "1.0.0",
+		DisplayName: "Test Connector",
+		ImageRef:    "ghcr.io/test/connector:1.0.0",
+		Resources:   &models.ResourceLimits{MemoryMB: 256, CPUShares: 512},
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+	if instance.Resources == nil || instance.Resources.MemoryMB != 256 {
+		t.Fatalf("expected resource limits to be stored, got %+v", instance.Resources)
+	}
+
+	spec := m.effectiveResources(instance)
+	if spec.MemoryMB != 256 {
+		t.Errorf("expected instance memory override 256, got %d", spec.MemoryMB)
+	}
+	if spec.CPUs != 1.0 {
+		t.Errorf("expected default CPUs 1.0, got %f", spec.CPUs)
+	}
+	if spec.CPUShares != 512 {
+		t.Errorf("expected CPU shares 512, got %d", spec.CPUShares)
+	}
+}
+
+func TestManager_NetworkSpec(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	pol := policy.New(st.DB())
+	m := New(st.DB(), nil, pol)
+	ctx := context.Background()
+
+	err := pol.GrantPermission(ctx, "inst-1", policy.PermissionSet{
+		Network: policy.NetworkPerms{Mode: "egress", EgressDomains: []string{"api.example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+
+	// Docker and Podman cannot restrict egress by host, so an allowlist
+	// stored by an older version is refused rather than widened to a bridge
+	spec, err := m.networkSpec(ctx, "inst-1")
+	if err == nil || !strings.Contains(err.Error(), "api.example.com") || spec.Mode != "none" {
+		t.Errorf("expected the allowlist to be refused, got %+v, %v", spec, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "conduit policy grant inst-1 --net") {
+		t.Errorf("expected the error to explain how to allow egress, got %v", err)
+	}
+
+	err = pol.GrantPermission(ctx, "inst-3", policy.PermissionSet{
+		Network: policy.NetworkPerms{Mode: "egress"},
+	})
+	if err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+	if spec, err := m.networkSpec(ctx, "inst-3"); err != nil || spec.Mode != "bridge" {
+		t.Errorf("expected bridge network for unrestricted egress, got %+v, %v", spec, err)
+	}
+
+	if spec, err := m.networkSpec(ctx, "inst-2"); err != nil || spec.Mode != "none" {
+		t.Errorf("expected no network without a grant, got %s, %v", spec.Mode, err)
+	}
+
+	pol.SetAllowNetworkEgress(false)
+	if spec, err := m.networkSpec(ctx, "inst-1"); err != nil || spec.Mode != "none" {
+		t.Errorf("expected no network when egress is disabled, got %s, %v", spec.Mode, err)
+	}
+}
+
+func TestManager_InstanceEnvSecrets(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	pol := policy.New(st.DB())
+	m := New(st.DB(), nil, pol)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	secretStore := secrets.NewStore(filepath.Join(dir, "secrets.enc"), filepath.Join(dir, "conduit.key"))
+	if err := secretStore.Set("api-key", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := pol.GrantPermission(ctx, "inst-1", policy.PermissionSet{
+		Secrets: []policy.SecretRef{
+			{SecretID: "api-key", EnvKey: "API_KEY"},
+			{SecretID: "not-stored", EnvKey: "MISSING"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+
+	instance := &Instance{
+		InstanceID: "inst-1",
+		Config:     map[string]string{"AUTH": "Bearer ${secret:api-key}", "MODE": "fast"},
+	}
+
+	// Without a store, referenced secrets cannot be resolved
+	if _, err := m.instanceEnv(ctx, instance); !hasCode(err, models.ErrSecretAccess) {
+		t.Errorf("expected %s without a store, got %v", models.ErrSecretAccess, err)
+	}
+
+	m.SetSecretStore(secretStore)
+	env, err := m.instanceEnv(ctx, instance)
+	if err != nil {
+		t.Fatalf("instanceEnv failed: %v", err)
+	}
+	if env["API_KEY"] != "s3cret" || env["AUTH"] != "Bearer s3cret" || env["MODE"] != "fast" {
+		t.Errorf("unexpected env %v", env)
+	}
+	if _, ok := env["MISSING"]; ok {
+		t.Error("granted secret that is not stored should be skipped")
+	}
+
+	// The stored config keeps the reference, not the value
+	if instance.Config["AUTH"] != "Bearer ${secret:api-key}" {
+		t.Errorf("config was modified: %v", instance.Config)
+	}
+
+	// Secrets must be granted to the instance
+	other := &Instance{InstanceID: "inst-2", Config: map[string]string{"AUTH": "${secret:api-key}"}}
+	if _, err := m.instanceEnv(ctx, other); !hasCode(err, models.ErrPermissionRequired) {
+		t.Errorf("expected %s for an ungranted secret, got %v", models.ErrPermissionRequired, err)
+	}
+
+	instance.Config = map[string]string{"TOKEN": "${secret:not-stored}"}
+	if _, err := m.instanceEnv(ctx, instance); !hasCode(err, models.ErrSecretNotFound) {
+		t.Errorf("expected %s for a missing secret, got %v", models.ErrSecretNotFound, err)
+	}
+
+	// Malformed references are rejected when the instance is created
+	_, err = m.CreateInstance(ctx, CreateInstanceRequest{
+		PackageID: "test/connector",
+		Config:    map[string]string{"TOKEN": "${secret:bad name}"},
+	})
+	if !hasCode(err, models.ErrConfigInvalid) {
+		t.Errorf("expected %s for a malformed reference, got %v", models.ErrConfigInvalid, err)
+	}
+}
+
+func hasCode(err error, code models.ErrorCode) bool {
+	var cerr *models.ConduitError
+	return errors.As(err, &cerr) && cerr.Code == code
+}
+
+func TestRestartPolicy_Backoff(t *testing.T) {
+	p := RestartPolicy{InitialBackoff: 10 * time.Second, MaxBackoff: 60 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 0},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{4, 40 * time.Second},
+		{5, 60 * time.Second},
+		{10, 60 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := p.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestManager_AutoRestartGivesUp(t *testing.T) {
+	st := testStore(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+	defer st.Close()
+
+	pol := policy.New(st.DB())
+	m := New(st.DB(), nil, pol)
+	m.SetRestartPolicy(RestartPolicy{Enabled: true, MaxAttempts: 3})
+	ctx := context.Background()
+
+	instance, err := m.CreateInstance(ctx, CreateInstanceRequest{
+		PackageID:   "test/connector",
+		Version:     "1.0.0",
+		DisplayName: "Test Connector",
+		ImageRef:    "ghcr.io/test/connector:1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	_, err = st.DB().Exec(`
+		UPDATE connector_instances SET status = 'DEGRADED', restart_count = 3 WHERE instance_id = ?
+	`, instance.InstanceID)
+	if err != nil {
+		t.Fatalf("failed to update instance: %v", err)
+	}
+
+	instance, _ = m.GetInstance(ctx, instance.InstanceID)
+	m.autoRestart(ctx, instance)
+
+	instance, _ = m.GetInstance(ctx, instance.InstanceID)
+	if instance.Status != StatusDegraded {
+		t.Errorf("expected instance to stay DEGRADED, got %s", instance.Status)
+	}
+	if instance.RestartCount != 3 {
+		t.Errorf("expected restart count to stay at 3, got %d", instance.RestartCount)
+	}
+	if !strings.Contains(instance.ErrorMessage, "gave up") {
+		t.Errorf("expected give-up error message, got %q", instance.ErrorMessage)
+	}
+}
+
 func TestIsValidTransition(t *testing.T) {
 	tests := []struct {
 		from  InstanceStatus
@@ -3,6 +3,9 @@ package lifecycle
 
 import (
 	"time"
+
+	"github.com/simpleflo/conduit/internal/policy"
+	"github.com/simpleflo/conduit/pkg/models"
 )
 
 // InstanceStatus represents the status of a connector instance.
@@ -74,6 +77,13 @@ type CreateInstanceRequest struct {
 	DisplayName    string            `json:"display_name"`
 	ImageRef       string            `json:"image_ref"`
 	Config         map[string]string `json:"config,omitempty"`
+	Resources      *models.ResourceLimits `json:"resources,omitempty"`
+
+	// Platform the image was built for (e.g. linux/amd64); empty means the host's
+	Platform string `json:"platform,omitempty"`
+
+	// Permissions the package declares it needs (from its manifest or analysis)
+	Permissions *policy.PermissionSet `json:"permissions,omitempty"`
 }
 
 // Instance represents a connector instance with full details.
@@ -95,6 +105,46 @@ type Instance struct {
 	UpdatedAt       time.Time      `json:"updated_at"`
 	StartedAt       *time.Time     `json:"started_at,omitempty"`
 	StoppedAt       *time.Time     `json:"stopped_at,omitempty"`
+	Resources       *models.ResourceLimits `json:"resources,omitempty"`
+	RestartCount    int            `json:"restart_count,omitempty"`
+	LastRestartAt   *time.Time     `json:"last_restart_at,omitempty"`
+	Platform        string         `json:"platform,omitempty"`
+}
+
+// RestartPolicy controls automatic restarts of DEGRADED instances.
+type RestartPolicy struct {
+	// Enabled turns on auto-restart (opt-in)
+	Enabled bool
+
+	// DegradedThreshold is how long an instance must stay DEGRADED before a restart
+	DegradedThreshold time.Duration
+
+	// MaxAttempts is the number of restarts to try before giving up
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the second attempt; it doubles per attempt
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+}
+
+// Backoff returns the minimum delay between restart attempt n-1 and attempt n.
+func (p RestartPolicy) Backoff(attempt int) time.Duration {
+	if attempt <= 1 {
+		return 0
+	}
+	backoff := p.InitialBackoff
+	for i := 2; i < attempt; i++ {
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
 }
 
 // Binding represents a client binding to an instance.
@@ -174,6 +224,57 @@ type Operation struct {
 	CompletedAt   *time.Time  `json:"completed_at,omitempty"`
 }
 
+// IsActive reports whether the operation is still pending or running.
+func (o *Operation) IsActive() bool {
+	return o.Status == "pending" || o.Status == "running"
+}
+
+// InstallOptions configures the installation flow.
+type InstallOptions struct {
+	// SkipPull skips the image pull, for images built locally by the CLI
+	SkipPull bool
+}
+
+// RemoveOptions configures instance removal.
+type RemoveOptions struct {
+	// PurgeImage also removes the instance's image, unless another instance uses it
+	PurgeImage bool
+}
+
+// RemoveResult reports what was cleaned up with a removed instance.
+type RemoveResult struct {
+	InstanceID   string `json:"instance_id"`
+	ImageRef     string `json:"image_ref,omitempty"`
+	ImageRemoved bool   `json:"image_removed"`
+	ImageKept    string `json:"image_kept,omitempty"` // Why a purge left the image in place
+}
+
+// GCOptions configures garbage collection of orphaned containers and images.
+type GCOptions struct {
+	// DryRun reports the orphans without removing them
+	DryRun bool
+	// MinAge spares anything younger, which an install in progress may not
+	// have recorded yet. Items of unknown age are spared too.
+	MinAge time.Duration
+}
+
+// GCItem is an orphaned container or image.
+type GCItem struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`        // Container name or image repository:tag
+	InstanceID string `json:"instance_id,omitempty"` // Instance a container was labeled with
+	Removed    bool   `json:"removed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// GCResult lists the orphans found, and removed unless it was a dry run.
+type GCResult struct {
+	DryRun     bool     `json:"dry_run"`
+	Containers []GCItem `json:"containers"`
+	Images     []GCItem `json:"images"`
+	Skipped    int      `json:"skipped"` // Orphans spared by MinAge
+}
+
 // ContainerSpec describes a container to be started.
 // This mirrors the runtime.ContainerSpec but is defined here to avoid circular dependencies.
 type ContainerSpec struct {
@@ -222,6 +323,7 @@ type SecuritySpec struct {
 
 // ResourceSpec defines resource limits.
 type ResourceSpec struct {
-	MemoryMB int64
-	CPUs     float64
+	MemoryMB  int64
+	CPUs      float64
+	CPUShares int64
 }
@@ -2,6 +2,7 @@
 package observability
 
 import (
+	"context"
 	"io"
 	"os"
 	"time"
@@ -59,6 +60,35 @@ func WithRequestID(logger zerolog.Logger, requestID string) zerolog.Logger {
 	return logger.With().Str("request_id", requestID).Logger()
 }
 
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context carrying the request ID so code
+// below the HTTP layer can tag its log entries with it.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// LoggerFromContext adds the request ID carried by ctx to the logger.
+// The logger is returned unchanged when ctx has no request ID.
+func LoggerFromContext(ctx context.Context, logger zerolog.Logger) zerolog.Logger {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return WithRequestID(logger, id)
+	}
+	return logger
+}
+
 // Event types for structured logging
 const (
 	EventInstanceCreated  = "instance_created"
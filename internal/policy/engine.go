@@ -23,6 +23,13 @@ type Engine struct {
 	logger       zerolog.Logger
 	builtinRules []Rule
 	homeDir      string
+
+	// denyEgress disables network egress regardless of user grants
+	denyEgress bool
+
+	// caseInsensitive folds path case before comparison (macOS and Windows
+	// filesystems are case-insensitive by default)
+	caseInsensitive bool
 }
 
 // New creates a new Policy Engine.
@@ -30,9 +37,10 @@ func New(db *sql.DB) *Engine {
 	homeDir, _ := os.UserHomeDir()
 
 	e := &Engine{
-		db:      db,
-		logger:  observability.Logger("policy"),
-		homeDir: homeDir,
+		db:              db,
+		logger:          observability.Logger("policy"),
+		homeDir:         homeDir,
+		caseInsensitive: runtime.GOOS == "darwin" || runtime.GOOS == "windows",
 	}
 
 	e.builtinRules = e.initBuiltinRules()
@@ -40,10 +48,42 @@ func New(db *sql.DB) *Engine {
 	return e
 }
 
+// SetAllowNetworkEgress enables or disables network egress globally.
+// When disabled, egress grants are ignored and connectors run without network.
+func (e *Engine) SetAllowNetworkEgress(allow bool) {
+	e.denyEgress = !allow
+}
+
+// EgressAllowed reports whether network egress is enabled globally.
+func (e *Engine) EgressAllowed() bool {
+	return !e.denyEgress
+}
+
 // Evaluate evaluates a permission request and returns a decision.
+// The decision is recorded in the audit log.
 func (e *Engine) Evaluate(ctx context.Context, req Request) (*Decision, error) {
+	decision, err := e.evaluate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	e.recordDecision(ctx, decision)
+	return decision, nil
+}
+
+// Preview evaluates a permission request without recording the decision,
+// so callers can show what would be allowed before creating anything.
+func (e *Engine) Preview(ctx context.Context, req Request) (*Decision, error) {
+	return e.evaluate(ctx, req)
+}
+
+// evaluate runs the policy rules against a request.
+func (e *Engine) evaluate(ctx context.Context, req Request) (*Decision, error) {
 	decision := &Decision{
 		DecisionID: uuid.New().String(),
+		InstanceID: req.InstanceID,
+		PackageID:  req.PackageID,
+		Scope:      req.Scope,
 		Requested:  req.Requested,
 		Timestamp:  time.Now(),
 		Actor:      req.Actor,
@@ -62,7 +102,6 @@ func (e *Engine) Evaluate(ctx context.Context, req Request) (*Decision, error) {
 				decision.Decision = Deny
 				decision.BlockReasons = append(decision.BlockReasons, rule.Reason)
 				decision.Reason = rule.Reason
-				e.recordDecision(ctx, decision)
 				return decision, nil
 			}
 		}
@@ -73,7 +112,6 @@ func (e *Engine) Evaluate(ctx context.Context, req Request) (*Decision, error) {
 		decision.Decision = Deny
 		decision.BlockReasons = violations
 		decision.Reason = "Forbidden filesystem access requested"
-		e.recordDecision(ctx, decision)
 		return decision, nil
 	}
 
@@ -118,9 +156,6 @@ func (e *Engine) Evaluate(ctx context.Context, req Request) (*Decision, error) {
 		decision.Reason = "No policy violations"
 	}
 
-	// Step 6: Record decision
-	e.recordDecision(ctx, decision)
-
 	return decision, nil
 }
 
@@ -205,15 +240,96 @@ func (e *Engine) RevokePermission(ctx context.Context, instanceID string, permTy
 }
 
 // GetEffectivePermissions returns computed permissions for an instance.
-func (e *Engine) GetEffectivePermissions(ctx context.Context, instanceID string) (*PermissionSet, error) {
+// Effective permissions are the intersection of what the package declares and
+// what the user granted, per category. Declared permissions that were not
+// granted are reported as warnings. Instances without a declaration fall back
+// to the user grants.
+func (e *Engine) GetEffectivePermissions(ctx context.Context, instanceID string) (*EffectivePermissions, error) {
 	grants, err := e.GetUserGrants(ctx, instanceID)
 	if err != nil {
 		return nil, err
 	}
 
-	// For V0, effective = user grants
-	// In V1, this would merge package-declared with user grants
-	return grants, nil
+	declared, err := e.GetDeclaredPermissions(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EffectivePermissions{
+		InstanceID: instanceID,
+		Declared:   declared,
+		Granted:    *grants,
+	}
+
+	if declared == nil {
+		result.Effective = *grants
+		if result.Effective.Network.Mode == "egress" && e.denyEgress {
+			result.Effective.Network = NetworkPerms{Mode: "none"}
+		}
+		return result, nil
+	}
+
+	warnings := []string{}
+
+	// A declaration of egress without hosts accepts whatever hosts were granted
+	requestedNet := declared.Network
+	if requestedNet.Mode == "egress" && len(requestedNet.EgressDomains) == 0 && grants.Network.Mode == "egress" {
+		requestedNet.EgressDomains = grants.Network.EgressDomains
+	}
+
+	result.Effective = PermissionSet{
+		Filesystem: e.evaluateFilesystem(declared.Filesystem, grants.Filesystem, &warnings),
+		Network:    e.evaluateNetwork(requestedNet, grants.Network, &warnings),
+		Secrets:    e.evaluateSecrets(declared.Secrets, grants.Secrets, &warnings),
+		Exposure:   e.evaluateExposure(declared.Exposure, grants.Exposure, &warnings),
+	}
+	if len(warnings) > 0 {
+		result.Warnings = warnings
+	}
+
+	return result, nil
+}
+
+// SetDeclaredPermissions records the permissions a package declares for an instance.
+func (e *Engine) SetDeclaredPermissions(ctx context.Context, instanceID string, perms PermissionSet) error {
+	data, err := json.Marshal(perms)
+	if err != nil {
+		return fmt.Errorf("marshal declared permissions: %w", err)
+	}
+
+	res, err := e.db.ExecContext(ctx, `
+		UPDATE connector_instances SET declared_perms = ? WHERE instance_id = ?
+	`, string(data), instanceID)
+	if err != nil {
+		return fmt.Errorf("store declared permissions: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("instance not found: %s", instanceID)
+	}
+
+	return nil
+}
+
+// GetDeclaredPermissions returns the permissions declared by an instance's package,
+// or nil if the package did not declare any.
+func (e *Engine) GetDeclaredPermissions(ctx context.Context, instanceID string) (*PermissionSet, error) {
+	var data sql.NullString
+	err := e.db.QueryRowContext(ctx, `
+		SELECT declared_perms FROM connector_instances WHERE instance_id = ?
+	`, instanceID).Scan(&data)
+	if err == sql.ErrNoRows || (err == nil && (!data.Valid || data.String == "")) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query declared permissions: %w", err)
+	}
+
+	var perms PermissionSet
+	if err := json.Unmarshal([]byte(data.String), &perms); err != nil {
+		return nil, fmt.Errorf("parse declared permissions: %w", err)
+	}
+
+	return &perms, nil
 }
 
 // GetUserGrants returns all user-granted permissions for an instance.
@@ -301,12 +417,8 @@ func (e *Engine) isPathGranted(path string, grantedPaths []string) bool {
 	normalizedPath := e.normalizePath(path)
 
 	for _, granted := range grantedPaths {
-		normalizedGranted := e.normalizePath(granted)
-		if normalizedPath == normalizedGranted {
-			return true
-		}
-		// Check if granted is a parent directory
-		if strings.HasPrefix(normalizedPath, normalizedGranted+"/") {
+		// Granted path itself or a directory below it
+		if pathWithin(normalizedPath, e.normalizePath(granted)) {
 			return true
 		}
 	}
@@ -321,7 +433,15 @@ func (e *Engine) evaluateNetwork(requested, granted NetworkPerms, warnings *[]st
 	}
 
 	if requested.Mode == "egress" {
+		if e.denyEgress {
+			*warnings = append(*warnings, "Network egress is disabled by policy (policy.allow_network_egress)")
+			return NetworkPerms{Mode: "none"}
+		}
 		if granted.Mode == "egress" {
+			// A grant without domains allows every host
+			if len(granted.EgressDomains) == 0 {
+				return NetworkPerms{Mode: "egress"}
+			}
 			// Check if domains are covered
 			effective := NetworkPerms{Mode: "egress"}
 			for _, domain := range requested.EgressDomains {
@@ -390,7 +510,8 @@ func (e *Engine) evaluateExposure(requested, granted ExposurePerms, warnings *[]
 	return ExposurePerms{}
 }
 
-// recordDecision logs the decision for audit purposes.
+// recordDecision logs the decision and persists it to the audit log.
+// Failures to persist are logged but never change the decision.
 func (e *Engine) recordDecision(ctx context.Context, decision *Decision) {
 	e.logger.Info().
 		Str("decision_id", decision.DecisionID).
@@ -400,7 +521,93 @@ func (e *Engine) recordDecision(ctx context.Context, decision *Decision) {
 		Int("block_count", len(decision.BlockReasons)).
 		Msg("policy decision")
 
-	// V1: Record to consent ledger
+	requested, _ := json.Marshal(decision.Requested)
+	effective, _ := json.Marshal(decision.Effective)
+	warnings, _ := json.Marshal(decision.Warnings)
+	blockReasons, _ := json.Marshal(decision.BlockReasons)
+
+	_, err := e.db.ExecContext(ctx, `
+		INSERT INTO policy_decisions
+			(decision_id, instance_id, package_id, scope, actor, decision, reason,
+			 requested, effective, warnings, block_reasons, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, decision.DecisionID, decision.InstanceID, decision.PackageID, string(decision.Scope),
+		decision.Actor, string(decision.Decision), decision.Reason,
+		string(requested), string(effective), string(warnings), string(blockReasons),
+		decision.Timestamp.UTC().Format(time.RFC3339))
+	if err != nil {
+		e.logger.Warn().Err(err).Str("decision_id", decision.DecisionID).Msg("failed to record policy decision")
+	}
+}
+
+// ListDecisions returns recorded decisions matching the filter, newest first.
+func (e *Engine) ListDecisions(ctx context.Context, filter DecisionFilter) ([]*Decision, error) {
+	query := `
+		SELECT decision_id, instance_id, package_id, scope, actor, decision, reason,
+		       requested, effective, warnings, block_reasons, created_at
+		FROM policy_decisions
+		WHERE 1=1`
+	var args []interface{}
+
+	if filter.InstanceID != "" {
+		query += " AND instance_id = ?"
+		args = append(args, filter.InstanceID)
+	}
+	if filter.Decision != "" {
+		query += " AND decision = ?"
+		args = append(args, string(filter.Decision))
+	}
+	if !filter.Since.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, filter.Since.UTC().Format(time.RFC3339))
+	}
+	query += " ORDER BY created_at DESC, rowid DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := e.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []*Decision
+	for rows.Next() {
+		var d Decision
+		var instanceID, packageID, scope, actor, reason sql.NullString
+		var requested, effective, warnings, blockReasons sql.NullString
+		var createdAt string
+
+		if err := rows.Scan(&d.DecisionID, &instanceID, &packageID, &scope, &actor,
+			&d.Decision, &reason, &requested, &effective, &warnings, &blockReasons, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan decision: %w", err)
+		}
+
+		d.InstanceID = instanceID.String
+		d.PackageID = packageID.String
+		d.Scope = Scope(scope.String)
+		d.Actor = actor.String
+		d.Reason = reason.String
+		d.Timestamp, _ = time.Parse(time.RFC3339, createdAt)
+		if requested.Valid {
+			json.Unmarshal([]byte(requested.String), &d.Requested)
+		}
+		if effective.Valid {
+			json.Unmarshal([]byte(effective.String), &d.Effective)
+		}
+		if warnings.Valid {
+			json.Unmarshal([]byte(warnings.String), &d.Warnings)
+		}
+		if blockReasons.Valid {
+			json.Unmarshal([]byte(blockReasons.String), &d.BlockReasons)
+		}
+
+		decisions = append(decisions, &d)
+	}
+
+	return decisions, rows.Err()
 }
 
 // initBuiltinRules initializes the built-in security rules.
@@ -524,7 +731,7 @@ func (e *Engine) checkForbiddenPaths(fs FilesystemPerms) []string {
 		// Check exact forbidden paths
 		for _, forbidden := range forbiddenPaths {
 			normalizedForbidden := e.normalizePath(forbidden)
-			if normalizedPath == normalizedForbidden || strings.HasPrefix(normalizedPath, normalizedForbidden+"/") {
+			if pathWithin(normalizedPath, normalizedForbidden) {
 				violations = append(violations, fmt.Sprintf("Path %s is forbidden", path))
 			}
 		}
@@ -534,7 +741,7 @@ func (e *Engine) checkForbiddenPaths(fs FilesystemPerms) []string {
 			for _, pattern := range forbiddenPatterns {
 				forbiddenPath := filepath.Join(e.homeDir, pattern)
 				normalizedForbidden := e.normalizePath(forbiddenPath)
-				if normalizedPath == normalizedForbidden || strings.HasPrefix(normalizedPath, normalizedForbidden+"/") {
+				if pathWithin(normalizedPath, normalizedForbidden) {
 					violations = append(violations, fmt.Sprintf("Path %s matches forbidden pattern ~/%s", path, pattern))
 				}
 			}
@@ -548,7 +755,7 @@ func (e *Engine) checkForbiddenPaths(fs FilesystemPerms) []string {
 func (e *Engine) isPathAllowed(normalizedPath string) bool {
 	for _, allowed := range allowedPaths {
 		normalizedAllowed := e.normalizePath(allowed)
-		if normalizedPath == normalizedAllowed || strings.HasPrefix(normalizedPath, normalizedAllowed+"/") {
+		if pathWithin(normalizedPath, normalizedAllowed) {
 			return true
 		}
 	}
@@ -585,7 +792,7 @@ func (e *Engine) matchesCredentialPaths(fs FilesystemPerms) bool {
 		for _, cred := range credentialDirs {
 			if e.homeDir != "" {
 				credPath := e.normalizePath(filepath.Join(e.homeDir, cred))
-				if normalizedPath == credPath || strings.HasPrefix(normalizedPath, credPath+"/") {
+				if pathWithin(normalizedPath, credPath) {
 					return true
 				}
 			}
@@ -615,7 +822,7 @@ func (e *Engine) matchesSystemPaths(fs FilesystemPerms) bool {
 
 		for _, sysPath := range systemPaths {
 			normalizedSys := e.normalizePath(sysPath)
-			if normalizedPath == normalizedSys || strings.HasPrefix(normalizedPath, normalizedSys+"/") {
+			if pathWithin(normalizedPath, normalizedSys) {
 				return true
 			}
 		}
@@ -624,7 +831,10 @@ func (e *Engine) matchesSystemPaths(fs FilesystemPerms) bool {
 	return false
 }
 
-// normalizePath normalizes a path for comparison.
+// normalizePath canonicalizes a path for comparison: it expands ~, cleans
+// ".." segments, resolves symlinks and folds case on case-insensitive
+// platforms. Without symlink resolution a granted directory could be escaped
+// through a link pointing outside of it.
 func (e *Engine) normalizePath(path string) string {
 	// Expand home directory
 	if strings.HasPrefix(path, "~") {
@@ -636,6 +846,13 @@ func (e *Engine) normalizePath(path string) string {
 	// Clean the path
 	path = filepath.Clean(path)
 
+	// Resolve symlinks so links cannot point outside a granted directory
+	path = resolveSymlinks(path)
+
+	if e.caseInsensitive {
+		return strings.ToLower(path)
+	}
+
 	// On Windows, normalize drive letter to uppercase
 	if runtime.GOOS == "windows" && len(path) >= 2 && path[1] == ':' {
 		path = strings.ToUpper(path[:1]) + path[1:]
@@ -643,3 +860,25 @@ func (e *Engine) normalizePath(path string) string {
 
 	return path
 }
+
+// resolveSymlinks resolves symlinks in the longest existing prefix of a
+// cleaned path, so paths that do not exist yet still have their parent
+// directories canonicalized.
+func resolveSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path
+	}
+	return filepath.Join(resolveSymlinks(parent), filepath.Base(path))
+}
+
+// pathWithin reports whether path equals parent or lies below it. Both paths
+// must already be normalized. The root only matches itself, so forbidding "/"
+// blocks a root mount without blocking every path.
+func pathWithin(path, parent string) bool {
+	return path == parent || strings.HasPrefix(path, parent+string(filepath.Separator))
+}
@@ -2,9 +2,12 @@ package policy
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/simpleflo/conduit/internal/store"
 )
@@ -179,6 +182,103 @@ func TestEngine_GrantAndCheckPermission(t *testing.T) {
 	}
 }
 
+func TestEngine_GetEffectivePermissions(t *testing.T) {
+	engine := testEngine(t)
+	if engine == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+
+	ctx := context.Background()
+	instanceID := "inst_effective_test"
+	readDir := t.TempDir()
+	writeDir := t.TempDir()
+
+	_, err := engine.db.Exec(`
+		INSERT INTO connector_instances (instance_id, package_id, package_version, display_name, image_ref)
+		VALUES (?, 'test/connector', '1.0.0', 'Test', 'test:latest')
+	`, instanceID)
+	if err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+
+	declared := PermissionSet{
+		Filesystem: FilesystemPerms{
+			ReadonlyPaths:  []string{readDir},
+			ReadwritePaths: []string{writeDir},
+		},
+		Network: NetworkPerms{Mode: "egress", EgressDomains: []string{"api.example.com", "cdn.example.com"}},
+		Secrets: []SecretRef{{SecretID: "api-token", EnvKey: "API_TOKEN"}},
+	}
+	if err := engine.SetDeclaredPermissions(ctx, instanceID, declared); err != nil {
+		t.Fatalf("SetDeclaredPermissions failed: %v", err)
+	}
+
+	// Grant only part of what was declared, plus something that was never declared
+	granted := PermissionSet{
+		Filesystem: FilesystemPerms{ReadonlyPaths: []string{readDir}},
+		Network:    NetworkPerms{Mode: "egress", EgressDomains: []string{"api.example.com", "other.example.com"}},
+	}
+	if err := engine.GrantPermission(ctx, instanceID, granted); err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+
+	perms, err := engine.GetEffectivePermissions(ctx, instanceID)
+	if err != nil {
+		t.Fatalf("GetEffectivePermissions failed: %v", err)
+	}
+
+	if perms.Declared == nil {
+		t.Fatal("expected declared permissions")
+	}
+	if len(perms.Effective.Filesystem.ReadonlyPaths) != 1 || len(perms.Effective.Filesystem.ReadwritePaths) != 0 {
+		t.Errorf("unexpected effective filesystem: %+v", perms.Effective.Filesystem)
+	}
+	if perms.Effective.Network.Mode != "egress" || len(perms.Effective.Network.EgressDomains) != 1 ||
+		perms.Effective.Network.EgressDomains[0] != "api.example.com" {
+		t.Errorf("unexpected effective network: %+v", perms.Effective.Network)
+	}
+	if len(perms.Effective.Secrets) != 0 {
+		t.Errorf("expected no effective secrets, got %+v", perms.Effective.Secrets)
+	}
+
+	// Read-write path, cdn domain and secret were declared but not granted
+	if len(perms.Warnings) != 3 {
+		t.Errorf("expected 3 warnings, got %d: %v", len(perms.Warnings), perms.Warnings)
+	}
+
+	// Egress granted without domains allows every declared host
+	if err := engine.GrantPermission(ctx, instanceID, PermissionSet{Network: NetworkPerms{Mode: "egress"}}); err != nil {
+		t.Fatalf("GrantPermission failed: %v", err)
+	}
+	perms, err = engine.GetEffectivePermissions(ctx, instanceID)
+	if err != nil {
+		t.Fatalf("GetEffectivePermissions failed: %v", err)
+	}
+	if perms.Effective.Network.Mode != "egress" || len(perms.Effective.Network.EgressDomains) != 0 {
+		t.Errorf("expected egress to any host, got %+v", perms.Effective.Network)
+	}
+	for _, w := range perms.Warnings {
+		if strings.Contains(w, "Network egress") {
+			t.Errorf("unexpected network warning with egress granted: %s", w)
+		}
+	}
+}
+
+func TestPermissionSet_MergeNetwork(t *testing.T) {
+	anyHost := PermissionSet{Network: NetworkPerms{Mode: "egress"}}
+	hosts := PermissionSet{Network: NetworkPerms{Mode: "egress", EgressDomains: []string{"api.example.com"}}}
+
+	if got := hosts.Merge(anyHost).Network; got.Mode != "egress" || len(got.EgressDomains) != 0 {
+		t.Errorf("expected egress to any host, got %+v", got)
+	}
+	if got := anyHost.Merge(hosts).Network; got.Mode != "egress" || len(got.EgressDomains) != 0 {
+		t.Errorf("expected a host list not to narrow egress to any host, got %+v", got)
+	}
+	if got := (PermissionSet{}).Merge(hosts).Network; len(got.EgressDomains) != 1 {
+		t.Errorf("expected the host list kept, got %+v", got)
+	}
+}
+
 func TestEngine_DecisionID(t *testing.T) {
 	engine := testEngine(t)
 	if engine == nil {
@@ -211,6 +311,68 @@ func TestEngine_DecisionID(t *testing.T) {
 	}
 }
 
+func TestEngine_ListDecisions(t *testing.T) {
+	engine := testEngine(t)
+	if engine == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+
+	ctx := context.Background()
+
+	denied, err := engine.Evaluate(ctx, Request{
+		Scope:      ScopeInstall,
+		InstanceID: "inst_a",
+		Requested: PermissionSet{
+			Filesystem: FilesystemPerms{ReadonlyPaths: []string{"/"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if _, err := engine.Evaluate(ctx, Request{Scope: ScopeInstall, InstanceID: "inst_b"}); err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+
+	all, err := engine.ListDecisions(ctx, DecisionFilter{})
+	if err != nil {
+		t.Fatalf("ListDecisions failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 decisions, got %d", len(all))
+	}
+
+	denials, err := engine.ListDecisions(ctx, DecisionFilter{Decision: Deny})
+	if err != nil {
+		t.Fatalf("ListDecisions failed: %v", err)
+	}
+	if len(denials) != 1 {
+		t.Fatalf("expected 1 denial, got %d", len(denials))
+	}
+	got := denials[0]
+	if got.DecisionID != denied.DecisionID || got.InstanceID != "inst_a" || got.Scope != ScopeInstall {
+		t.Errorf("unexpected decision: %+v", got)
+	}
+	if len(got.BlockReasons) == 0 || len(got.Requested.Filesystem.ReadonlyPaths) != 1 {
+		t.Errorf("expected block reasons and requested permissions to round-trip, got %+v", got)
+	}
+
+	byInstance, err := engine.ListDecisions(ctx, DecisionFilter{InstanceID: "inst_b"})
+	if err != nil {
+		t.Fatalf("ListDecisions failed: %v", err)
+	}
+	if len(byInstance) != 1 || byInstance[0].InstanceID != "inst_b" {
+		t.Errorf("expected 1 decision for inst_b, got %d", len(byInstance))
+	}
+
+	future, err := engine.ListDecisions(ctx, DecisionFilter{Since: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("ListDecisions failed: %v", err)
+	}
+	if len(future) != 0 {
+		t.Errorf("expected no decisions in the future, got %d", len(future))
+	}
+}
+
 func TestEngine_DenySystemPaths(t *testing.T) {
 	engine := testEngine(t)
 	if engine == nil {
@@ -269,6 +431,106 @@ func TestEngine_DenySystemPaths(t *testing.T) {
 	}
 }
 
+func TestEngine_PreviewDoesNotRecord(t *testing.T) {
+	engine := testEngine(t)
+	if engine == nil {
+		t.Skip("FTS5 not available, skipping test")
+	}
+
+	ctx := context.Background()
+	decision, err := engine.Preview(ctx, Request{
+		Scope:     ScopeInstall,
+		PackageID: "test/connector",
+		Requested: PermissionSet{
+			Filesystem: FilesystemPerms{ReadonlyPaths: []string{"/etc"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if decision.Decision != Deny {
+		t.Errorf("expected DENY for /etc, got %s", decision.Decision)
+	}
+
+	recorded, err := engine.ListDecisions(ctx, DecisionFilter{})
+	if err != nil {
+		t.Fatalf("ListDecisions failed: %v", err)
+	}
+	if len(recorded) != 0 {
+		t.Errorf("expected preview to record nothing, got %d decisions", len(recorded))
+	}
+}
+
+func TestEngine_IsPathGrantedDotDot(t *testing.T) {
+	engine := New(nil)
+
+	granted := []string{"/home/me/docs"}
+
+	if engine.isPathGranted("/home/me/docs/../../etc/passwd", granted) {
+		t.Error("expected .. traversal out of /home/me/docs to not be granted")
+	}
+	if engine.isPathGranted("/home/me/docs-private", granted) {
+		t.Error("expected sibling with shared prefix to not be granted")
+	}
+	if !engine.isPathGranted("/home/me/docs/notes/../todo.md", granted) {
+		t.Error("expected path that stays inside /home/me/docs to be granted")
+	}
+}
+
+func TestEngine_IsPathGrantedSymlinkEscape(t *testing.T) {
+	engine := New(nil)
+
+	tmpDir := t.TempDir()
+	docs := filepath.Join(tmpDir, "docs")
+	secret := filepath.Join(tmpDir, "secret")
+	for _, dir := range []string{docs, secret} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	if err := os.Symlink(secret, filepath.Join(docs, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	granted := []string{docs}
+
+	if engine.isPathGranted(filepath.Join(docs, "link", "key.pem"), granted) {
+		t.Error("expected symlink pointing outside the granted directory to not be granted")
+	}
+	if !engine.isPathGranted(filepath.Join(docs, "readme.md"), granted) {
+		t.Error("expected regular file inside the granted directory to be granted")
+	}
+}
+
+func TestEngine_ForbiddenPathViaSymlink(t *testing.T) {
+	engine := New(nil)
+
+	tmpDir := t.TempDir()
+	link := filepath.Join(tmpDir, "etc-link")
+	if err := os.Symlink("/etc", link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	violations := engine.checkForbiddenPaths(FilesystemPerms{ReadonlyPaths: []string{link}})
+	if len(violations) == 0 {
+		t.Error("expected symlink to /etc to be forbidden")
+	}
+}
+
+func TestEngine_IsPathGrantedCaseInsensitive(t *testing.T) {
+	engine := New(nil)
+
+	engine.caseInsensitive = true
+	if !engine.isPathGranted("/Users/Me/Docs/notes.md", []string{"/users/me/docs"}) {
+		t.Error("expected case-insensitive match when case folding is enabled")
+	}
+
+	engine.caseInsensitive = false
+	if engine.isPathGranted("/Users/Me/Docs/notes.md", []string{"/users/me/docs"}) {
+		t.Error("expected case-sensitive mismatch when case folding is disabled")
+	}
+}
+
 // testEngine creates a test policy engine.
 func testEngine(t *testing.T) *Engine {
 	t.Helper()
@@ -279,8 +541,7 @@ func testEngine(t *testing.T) *Engine {
 	st, err := store.New(dbPath)
 	if err != nil {
 		// FTS5 not available, return nil to skip test
-		if err.Error() == "migrate database: run migration 001: no such module: fts5" ||
-			err.Error() == "migrate database: run migration 003: no such module: fts5" {
+		if errors.Is(err, store.ErrFTS5Unavailable) {
 			return nil
 		}
 		t.Fatalf("failed to create store: %v", err)
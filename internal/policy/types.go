@@ -36,6 +36,9 @@ type Request struct {
 // Decision is the result of policy evaluation.
 type Decision struct {
 	DecisionID   string        `json:"decision_id"`
+	InstanceID   string        `json:"instance_id,omitempty"`
+	PackageID    string        `json:"package_id,omitempty"`
+	Scope        Scope         `json:"scope,omitempty"`
 	Decision     DecisionType  `json:"decision"`
 	Reason       string        `json:"reason"`
 	Requested    PermissionSet `json:"requested_permissions"`
@@ -46,6 +49,14 @@ type Decision struct {
 	Actor        string        `json:"actor"`
 }
 
+// DecisionFilter narrows a query of recorded decisions.
+type DecisionFilter struct {
+	InstanceID string
+	Decision   DecisionType
+	Since      time.Time
+	Limit      int
+}
+
 // PermissionSet represents all permission categories.
 type PermissionSet struct {
 	Filesystem FilesystemPerms `json:"filesystem"`
@@ -66,6 +77,20 @@ type NetworkPerms struct {
 	EgressDomains []string `json:"egress_domains,omitempty"`
 }
 
+// EffectivePermissions is the result of merging what a package declares with
+// what the user granted for an instance.
+type EffectivePermissions struct {
+	InstanceID string `json:"instance_id"`
+
+	// Declared is nil when the package did not declare its permissions
+	Declared  *PermissionSet `json:"declared,omitempty"`
+	Granted   PermissionSet  `json:"granted"`
+	Effective PermissionSet  `json:"effective"`
+
+	// Warnings lists declared permissions the user has not granted
+	Warnings []string `json:"warnings,omitempty"`
+}
+
 // SecretRef references a secret to be bound.
 type SecretRef struct {
 	SecretID string `json:"secret_id"`
@@ -100,10 +125,17 @@ func (p PermissionSet) Merge(other PermissionSet) PermissionSet {
 		},
 	}
 
-	// Network: take the more permissive mode
+	// Network: take the more permissive mode. Egress without domains allows
+	// every host, so it absorbs any domain list.
 	if other.Network.Mode == "egress" {
 		result.Network.Mode = "egress"
-		result.Network.EgressDomains = mergeStringSlices(p.Network.EgressDomains, other.Network.EgressDomains)
+		anyHost := len(other.Network.EgressDomains) == 0 ||
+			p.Network.Mode == "egress" && len(p.Network.EgressDomains) == 0
+		if anyHost {
+			result.Network.EgressDomains = nil
+		} else {
+			result.Network.EgressDomains = mergeStringSlices(p.Network.EgressDomains, other.Network.EgressDomains)
+		}
 	}
 
 	return result
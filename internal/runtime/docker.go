@@ -75,6 +75,11 @@ func (p *DockerProvider) Build(ctx context.Context, opts BuildOptions) error {
 		args = append(args, "-t", opts.ImageName)
 	}
 
+	// Built images are labeled like containers so "conduit gc" can find them
+	args = append(args, "--label", "conduit.managed=true")
+
+	args = append(args, platformArgs(opts.Platform)...)
+
 	// Build args
 	for k, v := range opts.BuildArgs {
 		args = append(args, "--build-arg", fmt.Sprintf("%s=%s", k, v))
@@ -91,6 +96,7 @@ func (p *DockerProvider) Build(ctx context.Context, opts BuildOptions) error {
 	p.logger.Info().
 		Str("image", opts.ImageName).
 		Str("context", opts.ContextDir).
+		Str("platform", opts.Platform).
 		Msg("building image")
 
 	// Run build with streaming output
@@ -153,14 +159,18 @@ func (p *DockerProvider) Pull(ctx context.Context, image string, opts PullOption
 
 // Run starts a container.
 func (p *DockerProvider) Run(ctx context.Context, spec ContainerSpec) (string, error) {
+	if err := checkNetwork(spec.Network); err != nil {
+		return "", err
+	}
 	args := p.buildRunArgs(spec)
+	_, cmdEnv := envArgs(spec.Env)
 
 	p.logger.Info().
 		Str("name", spec.Name).
 		Str("image", spec.Image).
 		Msg("starting container")
 
-	out, err := p.run(ctx, args...)
+	out, err := p.runEnv(ctx, cmdEnv, args...)
 	if err != nil {
 		return "", fmt.Errorf("start container: %w", err)
 	}
@@ -182,6 +192,7 @@ func (p *DockerProvider) buildRunArgs(spec ContainerSpec) []string {
 	if spec.Name != "" {
 		args = append(args, "--name", spec.Name)
 	}
+	args = append(args, platformArgs(spec.Platform)...)
 
 	// Security options (critical for isolation)
 	if spec.Security.ReadOnlyRootfs {
@@ -220,6 +231,9 @@ func (p *DockerProvider) buildRunArgs(spec ContainerSpec) []string {
 	if spec.Resources.CPUs > 0 {
 		args = append(args, "--cpus", fmt.Sprintf("%.2f", spec.Resources.CPUs))
 	}
+	if spec.Resources.CPUShares > 0 {
+		args = append(args, "--cpu-shares", strconv.FormatInt(spec.Resources.CPUShares, 10))
+	}
 
 	// Mounts
 	for _, m := range spec.Mounts {
@@ -239,10 +253,9 @@ func (p *DockerProvider) buildRunArgs(spec ContainerSpec) []string {
 		args = append(args, "-p", fmt.Sprintf("%d:%d/%s", port.Host, port.Container, protocol))
 	}
 
-	// Environment
-	for k, v := range spec.Env {
-		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
-	}
+	// Environment; the values are passed in the command's environment
+	envFlags, _ := envArgs(spec.Env)
+	args = append(args, envFlags...)
 
 	// Labels
 	for k, v := range spec.Labels {
@@ -280,15 +293,30 @@ func (p *DockerProvider) buildRunArgs(spec ContainerSpec) []string {
 func (p *DockerProvider) Stop(ctx context.Context, containerID string, timeout time.Duration) error {
 	args := []string{"stop"}
 	if timeout > 0 {
-		args = append(args, "-t", strconv.Itoa(int(timeout.Seconds())))
+		args = append(args, "-t", strconv.Itoa(stopTimeoutSeconds(timeout)))
 	}
 	args = append(args, containerID)
 
 	p.logger.Info().Str("container_id", containerID).Msg("stopping container")
 
-	_, err := p.run(ctx, args...)
+	// Bound the graceful stop so a wedged runtime cannot hang the caller
+	stopCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		stopCtx, cancel = context.WithTimeout(ctx, timeout+stopKillGrace)
+		defer cancel()
+	}
+
+	_, err := p.run(stopCtx, args...)
 	if err != nil {
-		return fmt.Errorf("stop container: %w", err)
+		// Escalate to SIGKILL so the container never outlives the stop request
+		p.logger.Warn().Err(err).Str("container_id", containerID).Msg("graceful stop failed, killing container")
+
+		killCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), stopKillGrace)
+		defer cancel()
+		if _, killErr := p.run(killCtx, "kill", containerID); killErr != nil {
+			return fmt.Errorf("stop container: %w", err)
+		}
 	}
 
 	p.logger.Info().Str("container_id", containerID).Msg("container stopped")
@@ -314,6 +342,39 @@ func (p *DockerProvider) Remove(ctx context.Context, containerID string, force b
 	return nil
 }
 
+// RemoveImage removes a container image. It fails if a container still uses it.
+func (p *DockerProvider) RemoveImage(ctx context.Context, image string) error {
+	p.logger.Info().Str("image", image).Msg("removing image")
+
+	_, err := p.run(ctx, "rmi", image)
+	if err != nil {
+		return fmt.Errorf("remove image: %w", err)
+	}
+
+	p.logger.Info().Str("image", image).Msg("image removed")
+	return nil
+}
+
+// ListManagedContainers lists the containers labeled conduit.managed=true.
+func (p *DockerProvider) ListManagedContainers(ctx context.Context) ([]ContainerInfo, error) {
+	out, err := p.run(ctx, "ps", "-a", "--filter", "label=conduit.managed=true",
+		"--format", "{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.State}}\t{{.CreatedAt}}\t{{.Label \"conduit.instance_id\"}}")
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	return parseContainerList(out), nil
+}
+
+// ListManagedImages lists the images labeled conduit.managed=true.
+func (p *DockerProvider) ListManagedImages(ctx context.Context) ([]ImageInfo, error) {
+	out, err := p.run(ctx, "images", "--filter", "label=conduit.managed=true",
+		"--format", "{{.ID}}\t{{.Repository}}\t{{.Tag}}\t{{.CreatedAt}}")
+	if err != nil {
+		return nil, fmt.Errorf("list images: %w", err)
+	}
+	return parseImageList(out), nil
+}
+
 // Status returns the status of a container.
 func (p *DockerProvider) Status(ctx context.Context, containerID string) (string, error) {
 	out, err := p.run(ctx, "inspect", "--format", "{{.State.Status}}", containerID)
@@ -409,7 +470,14 @@ func (p *DockerProvider) Inspect(ctx context.Context, containerID string) (*Cont
 
 // run executes a docker command and returns the output.
 func (p *DockerProvider) run(ctx context.Context, args ...string) (string, error) {
+	return p.runEnv(ctx, nil, args...)
+}
+
+// runEnv executes a docker command with env added to its environment and
+// returns the output.
+func (p *DockerProvider) runEnv(ctx context.Context, env []string, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, p.executable, args...)
+	cmd.Env = commandEnv(env)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -417,7 +485,7 @@ func (p *DockerProvider) run(ctx context.Context, args ...string) (string, error
 
 	p.logger.Debug().
 		Str("cmd", p.executable).
-		Strs("args", args).
+		Strs("args", redactArgs(args)).
 		Msg("executing docker command")
 
 	err := cmd.Run()
@@ -430,12 +498,16 @@ func (p *DockerProvider) run(ctx context.Context, args ...string) (string, error
 
 // RunInteractive runs a container with stdin/stdout attached for MCP stdio communication.
 func (p *DockerProvider) RunInteractive(ctx context.Context, spec ContainerSpec) error {
+	if err := checkNetwork(spec.Network); err != nil {
+		return err
+	}
 	args := []string{"run", "--rm", "-i"}
 
 	// Container name
 	if spec.Name != "" {
 		args = append(args, "--name", spec.Name)
 	}
+	args = append(args, platformArgs(spec.Platform)...)
 
 	// Security options
 	if spec.Security.ReadOnlyRootfs {
@@ -469,10 +541,9 @@ func (p *DockerProvider) RunInteractive(ctx context.Context, spec ContainerSpec)
 		args = append(args, "-v", opt)
 	}
 
-	// Environment
-	for k, v := range spec.Env {
-		args = append(args, "-e", fmt.Sprintf("%s=%s", k, v))
-	}
+	// Environment; the values are passed in the command's environment
+	envFlags, _ := envArgs(spec.Env)
+	args = append(args, envFlags...)
 
 	// Labels
 	args = append(args, "--label", "conduit.managed=true")
@@ -501,7 +572,9 @@ func (p *DockerProvider) RunInteractive(ctx context.Context, spec ContainerSpec)
 		Str("image", spec.Image).
 		Msg("running interactive container")
 
+	_, cmdEnv := envArgs(spec.Env)
 	cmd := exec.CommandContext(ctx, p.executable, args...)
+	cmd.Env = commandEnv(cmdEnv)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -518,6 +591,9 @@ func (p *DockerProvider) StreamLogs(ctx context.Context, containerID string, w i
 	if opts.Tail > 0 {
 		args = append(args, "--tail", strconv.Itoa(opts.Tail))
 	}
+	if !opts.Since.IsZero() {
+		args = append(args, "--since", opts.Since.Format(time.RFC3339))
+	}
 	args = append(args, containerID)
 
 	cmd := exec.CommandContext(ctx, p.executable, args...)
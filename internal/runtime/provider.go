@@ -4,8 +4,11 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,6 +18,10 @@ import (
 	"github.com/simpleflo/conduit/pkg/models"
 )
 
+// stopKillGrace is the extra time allowed for the runtime to deliver SIGKILL
+// after a stop timeout before Stop falls back to an explicit kill.
+const stopKillGrace = 10 * time.Second
+
 // Provider is the interface for container runtime operations.
 type Provider interface {
 	// Name returns the runtime name (e.g., "podman", "docker")
@@ -35,18 +42,32 @@ type Provider interface {
 	// Run starts a container and returns the container ID
 	Run(ctx context.Context, spec ContainerSpec) (string, error)
 
-	// Stop stops a running container
+	// Stop stops a running container, sending SIGTERM and escalating to
+	// SIGKILL once timeout has elapsed
 	Stop(ctx context.Context, containerID string, timeout time.Duration) error
 
 	// Remove removes a container
 	Remove(ctx context.Context, containerID string, force bool) error
 
+	// RemoveImage removes a container image that no container uses
+	RemoveImage(ctx context.Context, image string) error
+
+	// ListManagedContainers lists the containers labeled conduit.managed=true,
+	// running or not
+	ListManagedContainers(ctx context.Context) ([]ContainerInfo, error)
+
+	// ListManagedImages lists the images labeled conduit.managed=true
+	ListManagedImages(ctx context.Context) ([]ImageInfo, error)
+
 	// Status returns the status of a container
 	Status(ctx context.Context, containerID string) (string, error)
 
 	// Logs returns container logs
 	Logs(ctx context.Context, containerID string, opts LogOptions) (string, error)
 
+	// StreamLogs writes container stdout and stderr to w, following if opts.Follow is set
+	StreamLogs(ctx context.Context, containerID string, w io.Writer, opts LogOptions) error
+
 	// Exec executes a command in a running container
 	Exec(ctx context.Context, containerID string, command []string) (string, error)
 
@@ -63,6 +84,7 @@ type BuildOptions struct {
 	DockerfilePath string            // Path to Dockerfile (relative to ContextDir)
 	ImageName      string            // Name:tag for the built image
 	BuildArgs      map[string]string // Build-time variables
+	Platform       string            // Target platform, e.g. linux/arm64; empty builds for the host
 	NoCache        bool              // Disable build cache
 	Progress       func(line string) // Progress callback
 }
@@ -90,6 +112,7 @@ type ContainerSpec struct {
 	User       string
 	Stdin      bool
 	StdinOnce  bool
+	Platform   string // Image platform, e.g. linux/amd64; empty uses the runtime default
 }
 
 // Mount defines a bind mount.
@@ -112,6 +135,100 @@ type NetworkSpec struct {
 	AllowedHosts []string // For egress filtering
 }
 
+// checkNetwork rejects network specs the runtime cannot honour. Docker and
+// Podman cannot filter egress by hostname, so a bridged container with an
+// allowlist would get unrestricted network access instead.
+func checkNetwork(network NetworkSpec) error {
+	if network.Mode == "bridge" && len(network.AllowedHosts) > 0 {
+		return fmt.Errorf("egress allowlist (%s) cannot be enforced: containers can only run with no network or unrestricted network",
+			strings.Join(network.AllowedHosts, ", "))
+	}
+	return nil
+}
+
+// envArgs returns "-e KEY" run arguments for env, in key order, and the
+// KEY=VALUE pairs to add to the runtime command's environment. The runtime
+// copies each value from there, so secrets never appear in its argv, where
+// other users could read them from the process list.
+func envArgs(env map[string]string) (args, cmdEnv []string) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "-e", k)
+		cmdEnv = append(cmdEnv, k+"="+env[k])
+	}
+	return args, cmdEnv
+}
+
+// commandEnv returns the environment for a runtime command passing extra;
+// nil keeps the inherited environment.
+func commandEnv(extra []string) []string {
+	if len(extra) == 0 {
+		return nil
+	}
+	return append(os.Environ(), extra...)
+}
+
+// stopTimeoutSeconds converts a stop timeout to the whole seconds the runtime
+// CLIs accept, rounding up so a sub-second timeout still allows a graceful
+// stop instead of becoming an immediate kill.
+func stopTimeoutSeconds(timeout time.Duration) int {
+	return int((timeout + time.Second - 1) / time.Second)
+}
+
+// redactArgs returns args with the values of "-e KEY=VALUE" arguments
+// replaced, for logging.
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for i, arg := range redacted {
+		switch {
+		case (arg == "-e" || arg == "--env") && i+1 < len(redacted):
+			if k, _, ok := strings.Cut(redacted[i+1], "="); ok {
+				redacted[i+1] = k + "=REDACTED"
+			}
+		case strings.HasPrefix(arg, "-e=") || strings.HasPrefix(arg, "--env="):
+			flag, value, _ := strings.Cut(arg, "=")
+			if k, _, ok := strings.Cut(value, "="); ok {
+				redacted[i] = flag + "=" + k + "=REDACTED"
+			}
+		}
+	}
+	return redacted
+}
+
+// HostPlatform returns the container platform matching this machine, such as
+// linux/arm64 on Apple Silicon. Containers always run Linux, even on macOS.
+func HostPlatform() string {
+	return "linux/" + runtime.GOARCH
+}
+
+// ValidatePlatform checks that platform has the os/arch[/variant] form
+// accepted by docker and podman --platform.
+func ValidatePlatform(platform string) error {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("invalid platform %q: expected os/arch such as linux/amd64 or linux/arm64", platform)
+	}
+	for _, part := range parts {
+		if part == "" || strings.TrimLeft(part, "abcdefghijklmnopqrstuvwxyz0123456789_") != "" {
+			return fmt.Errorf("invalid platform %q: expected os/arch such as linux/amd64 or linux/arm64", platform)
+		}
+	}
+	return nil
+}
+
+// platformArgs returns the build or run arguments selecting a platform.
+func platformArgs(platform string) []string {
+	if platform == "" {
+		return nil
+	}
+	return []string{"--platform", platform}
+}
+
 // SecuritySpec defines security options.
 type SecuritySpec struct {
 	ReadOnlyRootfs   bool
@@ -124,8 +241,9 @@ type SecuritySpec struct {
 
 // ResourceSpec defines resource limits.
 type ResourceSpec struct {
-	MemoryMB int64
-	CPUs     float64
+	MemoryMB  int64
+	CPUs      float64
+	CPUShares int64 // Relative CPU weight (default 1024)
 }
 
 // LogOptions configures log retrieval.
@@ -148,6 +266,71 @@ type ContainerInfo struct {
 	ExitCode  int
 	Health    string
 	Ports     []Port
+
+	InstanceID string // conduit.instance_id label, set by ListManagedContainers
+}
+
+// ImageInfo describes a container image.
+type ImageInfo struct {
+	ID        string
+	Ref       string // repository:tag, empty for a dangling image
+	CreatedAt time.Time
+}
+
+// parseContainerList parses "docker ps" output formatted as ID, names,
+// image, state, creation time and instance ID label, tab separated.
+func parseContainerList(out string) []ContainerInfo {
+	var containers []ContainerInfo
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) < 6 || fields[0] == "" {
+			continue
+		}
+		containers = append(containers, ContainerInfo{
+			ID:         fields[0],
+			Name:       fields[1],
+			Image:      fields[2],
+			State:      fields[3],
+			CreatedAt:  parseListTime(fields[4]),
+			InstanceID: fields[5],
+		})
+	}
+	return containers
+}
+
+// parseImageList parses "docker images" output formatted as ID, repository,
+// tag and creation time, tab separated.
+func parseImageList(out string) []ImageInfo {
+	var images []ImageInfo
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) < 4 || fields[0] == "" {
+			continue
+		}
+		img := ImageInfo{ID: fields[0], CreatedAt: parseListTime(fields[3])}
+		if fields[1] != "<none>" && fields[1] != "" {
+			img.Ref = fields[1]
+			if fields[2] != "<none>" && fields[2] != "" {
+				img.Ref += ":" + fields[2]
+			}
+		}
+		images = append(images, img)
+	}
+	return images
+}
+
+// parseListTime parses a creation time as listed by docker and podman,
+// e.g. "2024-05-01 10:00:00 +0000 UTC". It returns the zero time when the
+// format is not recognized.
+func parseListTime(s string) time.Time {
+	if i := strings.Index(s, " m="); i >= 0 {
+		s = s[:i] // monotonic clock reading from Go's time.String
+	}
+	t, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
 }
 
 // Selector helps select and configure the runtime.
@@ -2,7 +2,9 @@ package runtime
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewSelector(t *testing.T) {
@@ -95,6 +97,42 @@ func TestParseKeyValue(t *testing.T) {
 	}
 }
 
+func TestParseContainerList(t *testing.T) {
+	out := "a1b2c3\tconduit-inst_abc\tconduit-mcp-x:latest\trunning\t2024-05-01 10:00:00 +0000 UTC\tinst_abc\n" +
+		"d4e5f6\tstray\tconduit-mcp-y:latest\texited\t2024-05-01 10:00:00.123456789 +0000 UTC m=+0.5\t\n\n"
+
+	containers := parseContainerList(out)
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d: %+v", len(containers), containers)
+	}
+	if c := containers[0]; c.ID != "a1b2c3" || c.Name != "conduit-inst_abc" || c.State != "running" || c.InstanceID != "inst_abc" {
+		t.Errorf("unexpected container: %+v", c)
+	}
+	want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	if !containers[0].CreatedAt.Equal(want) {
+		t.Errorf("expected created %v, got %v", want, containers[0].CreatedAt)
+	}
+	if c := containers[1]; c.InstanceID != "" || c.CreatedAt.IsZero() {
+		t.Errorf("expected an unlabeled container with a creation time, got %+v", c)
+	}
+}
+
+func TestParseImageList(t *testing.T) {
+	out := "1111\tlocalhost/conduit-mcp-x\tlatest\t2024-05-01 10:00:00 +0000 UTC\n" +
+		"2222\t<none>\t<none>\tyesterday\n"
+
+	images := parseImageList(out)
+	if len(images) != 2 {
+		t.Fatalf("expected 2 images, got %d: %+v", len(images), images)
+	}
+	if images[0].Ref != "localhost/conduit-mcp-x:latest" || images[0].CreatedAt.IsZero() {
+		t.Errorf("unexpected image: %+v", images[0])
+	}
+	if images[1].Ref != "" || !images[1].CreatedAt.IsZero() {
+		t.Errorf("expected a dangling image with an unknown creation time, got %+v", images[1])
+	}
+}
+
 func TestFormatEnv(t *testing.T) {
 	env := map[string]string{
 		"KEY1": "value1",
@@ -120,6 +158,59 @@ func TestFormatEnv(t *testing.T) {
 	}
 }
 
+func TestEnvArgs(t *testing.T) {
+	env := map[string]string{"TOKEN": "s3cret", "API_URL": "https://api.example.com"}
+
+	args, cmdEnv := envArgs(env)
+	if got, want := strings.Join(args, " "), "-e API_URL -e TOKEN"; got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(cmdEnv, " "), "API_URL=https://api.example.com TOKEN=s3cret"; got != want {
+		t.Errorf("cmdEnv = %q, want %q", got, want)
+	}
+
+	// Values never reach the run arguments
+	spec := ContainerSpec{Image: "test:latest", Env: env}
+	for _, args := range [][]string{NewDockerProvider().buildRunArgs(spec), NewPodmanProvider().buildRunArgs(spec)} {
+		if joined := strings.Join(args, " "); strings.Contains(joined, "s3cret") || !strings.Contains(joined, "-e TOKEN") {
+			t.Errorf("expected env passed by name only, got %s", joined)
+		}
+	}
+
+	if commandEnv(nil) != nil {
+		t.Error("expected the inherited environment without extra variables")
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	args := []string{"run", "-e", "TOKEN=s3cret", "-e", "PLAIN", "--env=KEY=v", "--label", "a=b", "image"}
+	got := strings.Join(redactArgs(args), " ")
+	want := "run -e TOKEN=REDACTED -e PLAIN --env=KEY=REDACTED --label a=b image"
+	if got != want {
+		t.Errorf("redactArgs = %q, want %q", got, want)
+	}
+	if args[2] != "TOKEN=s3cret" {
+		t.Error("redactArgs modified its input")
+	}
+}
+
+func TestStopTimeoutSeconds(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    int
+	}{
+		{500 * time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{10 * time.Second, 10},
+	}
+	for _, tt := range tests {
+		if got := stopTimeoutSeconds(tt.timeout); got != tt.want {
+			t.Errorf("stopTimeoutSeconds(%v) = %d, want %d", tt.timeout, got, tt.want)
+		}
+	}
+}
+
 func TestContainerSpec_Defaults(t *testing.T) {
 	spec := ContainerSpec{
 		Name:  "test",
@@ -173,6 +264,59 @@ func TestNetworkSpec_Modes(t *testing.T) {
 	}
 }
 
+func TestCheckNetwork(t *testing.T) {
+	err := checkNetwork(NetworkSpec{Mode: "bridge", AllowedHosts: []string{"api.example.com", "*.github.com"}})
+	if err == nil || !strings.Contains(err.Error(), "api.example.com, *.github.com") {
+		t.Errorf("expected allowlist to be refused, got %v", err)
+	}
+
+	for _, network := range []NetworkSpec{
+		{Mode: "bridge"},
+		{Mode: "none"},
+		{Mode: "none", AllowedHosts: []string{"api.example.com"}},
+	} {
+		if err := checkNetwork(network); err != nil {
+			t.Errorf("%+v: unexpected error %v", network, err)
+		}
+	}
+
+	// Run refuses before invoking the runtime
+	_, err = NewDockerProvider().Run(context.Background(), ContainerSpec{
+		Image:   "test:latest",
+		Network: NetworkSpec{Mode: "bridge", AllowedHosts: []string{"api.example.com"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot be enforced") {
+		t.Errorf("expected Run to refuse the allowlist, got %v", err)
+	}
+}
+
+func TestValidatePlatform(t *testing.T) {
+	for _, platform := range []string{"linux/amd64", "linux/arm64", "linux/arm/v7", HostPlatform()} {
+		if err := ValidatePlatform(platform); err != nil {
+			t.Errorf("ValidatePlatform(%q) = %v", platform, err)
+		}
+	}
+	for _, platform := range []string{"", "amd64", "linux/", "linux/amd64/v3/x", "Linux/AMD64", "linux/amd64 --privileged"} {
+		if err := ValidatePlatform(platform); err == nil {
+			t.Errorf("ValidatePlatform(%q) accepted an invalid platform", platform)
+		}
+	}
+}
+
+func TestBuildRunArgs_Platform(t *testing.T) {
+	spec := ContainerSpec{Image: "test:latest", Platform: "linux/amd64"}
+	for _, args := range [][]string{NewDockerProvider().buildRunArgs(spec), NewPodmanProvider().buildRunArgs(spec)} {
+		if !strings.Contains(strings.Join(args, " "), "--platform linux/amd64") {
+			t.Errorf("expected platform in run args, got %v", args)
+		}
+	}
+
+	spec.Platform = ""
+	if args := strings.Join(NewDockerProvider().buildRunArgs(spec), " "); strings.Contains(args, "--platform") {
+		t.Errorf("unexpected platform for default spec: %s", args)
+	}
+}
+
 func TestResourceSpec(t *testing.T) {
 	spec := ResourceSpec{
 		MemoryMB: 512,
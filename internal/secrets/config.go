@@ -0,0 +1,116 @@
+package secrets
+
+import (
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// EncryptedPrefix marks a config value encrypted by ConfigCipher.
+const EncryptedPrefix = "enc:v1:"
+
+// configKeyInfo separates the config key from other uses of the keyfile.
+const configKeyInfo = "conduit instance config v1"
+
+// sensitiveKeyParts are the fragments that mark a config key as holding a
+// credential, matched against the lowercased key with separators removed.
+var sensitiveKeyParts = []string{
+	"token", "secret", "password", "passwd", "passphrase",
+	"apikey", "accesskey", "privatekey", "clientkey",
+	"credential", "auth", "cookie", "session",
+}
+
+// IsSensitiveKey reports whether a config key looks like it holds a
+// credential, such as GITHUB_TOKEN, api-key or dbPassword.
+func IsSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "", ".", "").Replace(strings.ToLower(key))
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEncrypted reports whether a config value was encrypted by ConfigCipher.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// ConfigCipher encrypts the sensitive values of instance config maps before
+// they are stored in the database. Its key is derived from the machine-local
+// keyfile, so a copy of the database alone does not expose them.
+type ConfigCipher struct {
+	aead cipher.AEAD
+}
+
+// NewConfigCipher loads the keyfile at keyPath, creating it on first use,
+// and derives the config encryption key from it.
+func NewConfigCipher(keyPath string) (*ConfigCipher, error) {
+	fileKey, err := LoadOrCreateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hkdf.Key(sha256.New, fileKey, nil, configKeyInfo, KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("derive config key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigCipher{aead: aead}, nil
+}
+
+// EncryptConfig returns a copy of config with each sensitive value
+// encrypted. Values already encrypted, empty values and values that are only
+// ${secret:NAME} references are kept as they are. The config key is bound to
+// its value, so encrypted values cannot be swapped between keys.
+func (c *ConfigCipher) EncryptConfig(config map[string]string) (map[string]string, error) {
+	if config == nil {
+		return nil, nil
+	}
+	out := make(map[string]string, len(config))
+	for k, v := range config {
+		if !IsSensitiveKey(k) || IsEncrypted(v) || strings.TrimSpace(refPattern.ReplaceAllString(v, "")) == "" {
+			out[k] = v
+			continue
+		}
+		nonce := make([]byte, c.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("generate nonce: %w", err)
+		}
+		sealed := c.aead.Seal(nonce, nonce, []byte(v), []byte(k))
+		out[k] = EncryptedPrefix + base64.RawStdEncoding.EncodeToString(sealed)
+	}
+	return out, nil
+}
+
+// DecryptConfig returns a copy of config with encrypted values decrypted.
+func (c *ConfigCipher) DecryptConfig(config map[string]string) (map[string]string, error) {
+	if config == nil {
+		return nil, nil
+	}
+	out := make(map[string]string, len(config))
+	for k, v := range config {
+		if !IsEncrypted(v) {
+			out[k] = v
+			continue
+		}
+		data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(v, EncryptedPrefix))
+		if err != nil || len(data) < c.aead.NonceSize() {
+			return nil, fmt.Errorf("decrypt config %s: malformed value", k)
+		}
+		nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+		plaintext, err := c.aead.Open(nil, nonce, sealed, []byte(k))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt config %s: wrong key or corrupted value (was the keyfile replaced?)", k)
+		}
+		out[k] = string(plaintext)
+	}
+	return out, nil
+}
@@ -0,0 +1,371 @@
+// Package secrets keeps connector secrets in an encrypted file, resolves
+// the ${secret:NAME} references instance config uses to ask for them, and
+// encrypts the sensitive values of instance config stored in the database.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when no secret has the requested name.
+var ErrNotFound = errors.New("secret not found")
+
+// KeySize is the length in bytes of the machine-local encryption key.
+const KeySize = 32
+
+var (
+	// namePattern is what a secret name may look like: it must fit in a
+	// ${secret:NAME} reference and a policy grant.
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+	// refPattern matches ${secret:NAME} references in config values.
+	refPattern = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+)
+
+// ValidateName checks that name can be used as a secret name.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Refs returns the secret names referenced in value, in order of first use.
+func Refs(value string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range refPattern.FindAllStringSubmatch(value, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// ValidateRefs checks that every ${secret:NAME} reference in value names a
+// valid secret.
+func ValidateRefs(value string) error {
+	for _, name := range Refs(value) {
+		if err := ValidateName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expand replaces each ${secret:NAME} reference in value with the secret
+// lookup returns for NAME.
+func Expand(value string, lookup func(name string) (string, error)) (string, error) {
+	var firstErr error
+	expanded := refPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		if firstErr != nil {
+			return ""
+		}
+		name := refPattern.FindStringSubmatch(ref)[1]
+		if err := ValidateName(name); err != nil {
+			firstErr = err
+			return ""
+		}
+		secret, err := lookup(name)
+		if err != nil {
+			firstErr = err
+			return ""
+		}
+		return secret
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return expanded, nil
+}
+
+// LoadOrCreateKey reads the machine-local key at path, creating it from
+// fresh random bytes with mode 0600 on first use.
+func LoadOrCreateKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key file %s: expected %d bytes, got %d", path, KeySize, len(key))
+		}
+		return key, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+
+	key = make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	if err := createPrivateFile(path, key); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return LoadOrCreateKey(path)
+		}
+		return nil, fmt.Errorf("create key file: %w", err)
+	}
+	return key, nil
+}
+
+// TokenFileSize is the length in bytes of a generated API token, before
+// hex encoding.
+const TokenFileSize = 32
+
+// LoadOrCreateToken reads the API token at path, creating it from fresh
+// random bytes with mode 0600 on first use. A token file others can read is
+// rejected, since anyone holding the token can use the daemon's API. Windows
+// reports no group or other bits, so the check is skipped there and the
+// file relies on the ACL of the user's profile directory.
+func LoadOrCreateToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("stat token file: %w", err)
+		}
+		if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+			return "", fmt.Errorf("token file %s is accessible by other users; run: chmod 600 %s", path, path)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", path)
+		}
+		return token, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("read token file: %w", err)
+	}
+
+	raw := make([]byte, TokenFileSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+	if err := createPrivateFile(path, []byte(token+"\n")); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return LoadOrCreateToken(path)
+		}
+		return "", fmt.Errorf("create token file: %w", err)
+	}
+	return token, nil
+}
+
+// createPrivateFile creates path with mode 0600 holding data, failing with
+// fs.ErrExist if it already exists. The data is written aside and linked
+// into place, so a concurrent first run never reads a partial file and both
+// end up using the same one.
+func createPrivateFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Link(tmp.Name(), path)
+}
+
+// Encrypt seals plaintext with AES-256-GCM under key. The random nonce is
+// prepended to the result.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt opens data sealed by Encrypt under key.
+func Decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("decrypt: data too short")
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: wrong key or corrupted data")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Store is a file of named secrets, encrypted as a whole with the
+// machine-local key so neither names nor values are readable on disk. The
+// file is re-read on every access, so secrets set by the CLI are seen by a
+// running daemon without a restart.
+type Store struct {
+	path    string
+	keyPath string
+	mu      sync.Mutex
+}
+
+// NewStore returns the store kept at path, encrypted with the key at keyPath.
+// Neither file is created until a secret is set.
+func NewStore(path, keyPath string) *Store {
+	return &Store{path: path, keyPath: keyPath}
+}
+
+// Get returns the named secret, or ErrNotFound.
+func (s *Store) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+// Set stores a secret, replacing any secret with the same name.
+func (s *Store) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	return s.save(values)
+}
+
+// Delete removes the named secret, or returns ErrNotFound.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := values[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(values, name)
+	return s.save(values)
+}
+
+// List returns the names of the stored secrets, sorted.
+func (s *Store) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// load decrypts the store file. A missing file is an empty store.
+func (s *Store) load() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read secrets: %w", err)
+	}
+
+	key, err := LoadOrCreateKey(s.keyPath)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := Decrypt(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("read secrets %s: %w", s.path, err)
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("parse secrets: %w", err)
+	}
+	return values, nil
+}
+
+// save encrypts values and replaces the store file atomically.
+func (s *Store) save(values map[string]string) error {
+	key, err := LoadOrCreateKey(s.keyPath)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("encode secrets: %w", err)
+	}
+	data, err := Encrypt(key, plaintext)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("create secrets directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".secrets-*")
+	if err != nil {
+		return fmt.Errorf("write secrets: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write secrets: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write secrets: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write secrets: %w", err)
+	}
+	return nil
+}
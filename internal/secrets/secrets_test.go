@@ -0,0 +1,245 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secrets.enc")
+	keyPath := filepath.Join(dir, "conduit.key")
+	s := NewStore(path, keyPath)
+
+	if _, err := s.Get("github-token"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound from an empty store, got %v", err)
+	}
+	if _, err := os.Stat(keyPath); !os.IsNotExist(err) {
+		t.Error("reading an empty store should not create a key")
+	}
+
+	if err := s.Set("github-token", "ghp_abc123"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set("openai.key", "sk-xyz"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := s.Set("bad name", "x"); err == nil {
+		t.Error("expected an invalid name to be rejected")
+	}
+
+	// Another store on the same files sees the secrets
+	reopened := NewStore(path, keyPath)
+	if v, err := reopened.Get("github-token"); err != nil || v != "ghp_abc123" {
+		t.Errorf("Get = %q, %v", v, err)
+	}
+	names, err := reopened.List()
+	if err != nil || strings.Join(names, ",") != "github-token,openai.key" {
+		t.Errorf("List = %v, %v", names, err)
+	}
+
+	// Neither names nor values are stored in the clear
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("ghp_abc123")) || bytes.Contains(data, []byte("github-token")) {
+		t.Error("secrets file contains plaintext")
+	}
+	for _, p := range []string{path, keyPath} {
+		if info, err := os.Stat(p); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("%s: expected mode 0600, got %v (%v)", filepath.Base(p), info.Mode().Perm(), err)
+		}
+	}
+
+	if err := s.Delete("github-token"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete("github-token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound deleting twice, got %v", err)
+	}
+
+	// A different key cannot read the store
+	other := NewStore(path, filepath.Join(dir, "other.key"))
+	if _, err := other.Get("openai.key"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a decryption error with the wrong key, got %v", err)
+	}
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "conduit.key")
+	key, err := LoadOrCreateKey(path)
+	if err != nil || len(key) != KeySize {
+		t.Fatalf("LoadOrCreateKey = %d bytes, %v", len(key), err)
+	}
+	again, err := LoadOrCreateKey(path)
+	if err != nil || !bytes.Equal(key, again) {
+		t.Errorf("expected the same key on the second load, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateKey(path); err == nil {
+		t.Error("expected a malformed key file to be rejected")
+	}
+}
+
+func TestLoadOrCreateToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.token")
+	token, err := LoadOrCreateToken(path)
+	if err != nil || len(token) != 2*TokenFileSize {
+		t.Fatalf("LoadOrCreateToken = %q, %v", token, err)
+	}
+	again, err := LoadOrCreateToken(path)
+	if err != nil || again != token {
+		t.Errorf("expected the same token on the second load, got %q, %v", again, err)
+	}
+
+	// Windows has no mode bits to check
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("expected a 0600 token file, got %v, %v", info.Mode().Perm(), err)
+	}
+
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOrCreateToken(path); err == nil || !strings.Contains(err.Error(), "chmod 600") {
+		t.Errorf("expected a world-readable token file to be rejected, got %v", err)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, KeySize)
+	sealed, err := Encrypt(key, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := Decrypt(key, sealed); err != nil || string(plain) != "hello" {
+		t.Errorf("Decrypt = %q, %v", plain, err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, err := Decrypt(key, sealed); err == nil {
+		t.Error("expected tampered data to be rejected")
+	}
+}
+
+func TestExpand(t *testing.T) {
+	values := map[string]string{"token": "abc", "host": "example.com"}
+	lookup := func(name string) (string, error) {
+		if v, ok := values[name]; ok {
+			return v, nil
+		}
+		return "", ErrNotFound
+	}
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "plain", false},
+		{"Bearer ${secret:token}", "Bearer abc", false},
+		{"https://${secret:host}/?t=${secret:token}", "https://example.com/?t=abc", false},
+		{"${SECRET:token} and $token", "${SECRET:token} and $token", false},
+		{"${secret:missing}", "", true},
+		{"${secret:bad name}", "", true},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.value, lookup)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if refs := Refs("${secret:a} ${secret:b} ${secret:a}"); strings.Join(refs, ",") != "a,b" {
+		t.Errorf("Refs = %v", refs)
+	}
+	if err := ValidateRefs("${secret:ok} ${secret:not ok}"); err == nil {
+		t.Error("expected ValidateRefs to reject a malformed name")
+	}
+}
+
+func TestConfigCipher(t *testing.T) {
+	keyPath := filepath.Join(t.TempDir(), "conduit.key")
+	c, err := NewConfigCipher(keyPath)
+	if err != nil {
+		t.Fatalf("NewConfigCipher: %v", err)
+	}
+
+	config := map[string]string{
+		"GITHUB_TOKEN": "ghp_plaintext",
+		"db-password":  "hunter2",
+		"API_KEY":      "${secret:api}",
+		"LOG_LEVEL":    "debug",
+	}
+	encrypted, err := c.EncryptConfig(config)
+	if err != nil {
+		t.Fatalf("EncryptConfig: %v", err)
+	}
+	for _, key := range []string{"GITHUB_TOKEN", "db-password"} {
+		if !IsEncrypted(encrypted[key]) || strings.Contains(encrypted[key], config[key]) {
+			t.Errorf("%s not encrypted: %q", key, encrypted[key])
+		}
+	}
+	if encrypted["API_KEY"] != "${secret:api}" || encrypted["LOG_LEVEL"] != "debug" {
+		t.Errorf("references and non-sensitive values should be kept: %v", encrypted)
+	}
+
+	again, err := c.EncryptConfig(encrypted)
+	if err != nil {
+		t.Fatalf("EncryptConfig again: %v", err)
+	}
+	if again["GITHUB_TOKEN"] != encrypted["GITHUB_TOKEN"] {
+		t.Error("encrypted values should not be encrypted twice")
+	}
+
+	decrypted, err := c.DecryptConfig(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptConfig: %v", err)
+	}
+	for k, v := range config {
+		if decrypted[k] != v {
+			t.Errorf("%s = %q, want %q", k, decrypted[k], v)
+		}
+	}
+
+	// A value moved to another key must not decrypt
+	swapped := map[string]string{"AUTH_TOKEN": encrypted["GITHUB_TOKEN"]}
+	if _, err := c.DecryptConfig(swapped); err == nil {
+		t.Error("expected error decrypting a value under another key")
+	}
+
+	other, err := NewConfigCipher(filepath.Join(t.TempDir(), "conduit.key"))
+	if err != nil {
+		t.Fatalf("NewConfigCipher: %v", err)
+	}
+	if _, err := other.DecryptConfig(encrypted); err == nil {
+		t.Error("expected error decrypting with another keyfile")
+	}
+}
+
+func TestIsSensitiveKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"GITHUB_TOKEN":   true,
+		"api-key":        true,
+		"dbPassword":     true,
+		"client_secret":  true,
+		"AWS_ACCESS_KEY": true,
+		"LOG_LEVEL":      false,
+		"WORKSPACE_DIR":  false,
+	} {
+		if got := IsSensitiveKey(key); got != want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBackupRetention is how many automatic backups are kept when the
+// retention is not configured.
+const DefaultBackupRetention = 5
+
+// autoBackupPrefix names automatic backups so pruning never touches files
+// the user created by hand.
+const autoBackupPrefix = "conduit-db-"
+
+// Backup writes a consistent copy of the database to path using SQLite's
+// online backup API, so it is safe while the daemon is serving requests.
+// The copy is written to a temporary file and renamed into place.
+func (s *Store) Backup(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+	if err := s.copyDatabase(ctx, tmpPath, false); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("move backup into place: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces the database contents with the backup at path. The backup
+// is checked first: it must be a Conduit database no newer than this binary.
+// Older backups are migrated to the current schema after the restore.
+func (s *Store) Restore(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+
+	version, err := backupSchemaVersion(ctx, path)
+	if err != nil {
+		return fmt.Errorf("read backup: %w", err)
+	}
+	if latest := s.LatestSchemaVersion(); version > latest {
+		return fmt.Errorf("%w: backup is at version %d, this binary supports up to %d",
+			ErrSchemaTooNew, version, latest)
+	}
+
+	if err := s.copyDatabase(ctx, path, true); err != nil {
+		return err
+	}
+
+	if err := s.migrate(); err != nil {
+		return fmt.Errorf("migrate restored database: %w", err)
+	}
+	return nil
+}
+
+// AutoBackup takes a timestamped backup in the configured backup directory
+// before a destructive operation, then prunes old automatic backups. It
+// returns the backup path, or "" when automatic backups are disabled.
+func (s *Store) AutoBackup(ctx context.Context, reason string) (string, error) {
+	if s.backupDir == "" {
+		return "", nil
+	}
+
+	name := fmt.Sprintf("%s%s-%s.db", autoBackupPrefix, time.Now().Format("20060102-150405.000"), reason)
+	path := filepath.Join(s.backupDir, name)
+	if err := s.Backup(ctx, path); err != nil {
+		return "", fmt.Errorf("backup before %s: %w", reason, err)
+	}
+
+	s.pruneBackups()
+	return path, nil
+}
+
+// pruneBackups removes the oldest automatic backups beyond the retention.
+// Failures are ignored: a leftover backup is not worth failing an operation.
+func (s *Store) pruneBackups() {
+	matches, err := filepath.Glob(filepath.Join(s.backupDir, autoBackupPrefix+"*.db"))
+	if err != nil {
+		return
+	}
+
+	retention := s.backupRetention
+	if retention <= 0 {
+		retention = DefaultBackupRetention
+	}
+	if len(matches) <= retention {
+		return
+	}
+
+	// Names start with a sortable timestamp, so lexical order is age order
+	sort.Strings(matches)
+	for _, path := range matches[:len(matches)-retention] {
+		os.Remove(path)
+	}
+}
+
+// backupSchemaVersion reads the schema version recorded in a backup file.
+func backupSchemaVersion(ctx context.Context, path string) (int, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	var version int
+	err = db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM migrations").Scan(&version)
+	if err != nil && strings.Contains(err.Error(), "no such table") {
+		return 0, fmt.Errorf("%s is not a Conduit database", path)
+	}
+	return version, err
+}
@@ -0,0 +1,72 @@
+//go:build cgo
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// copyDatabase copies between the store's database and the file at path with
+// the online backup API. With restore set, path is copied into the store;
+// otherwise the store is copied to path.
+func (s *Store) copyDatabase(ctx context.Context, path string, restore bool) error {
+	other, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer other.Close()
+
+	otherConn, err := other.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", path, err)
+	}
+	defer otherConn.Close()
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(liveDriverConn interface{}) error {
+		return otherConn.Raw(func(otherDriverConn interface{}) error {
+			live, ok := liveDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection %T", liveDriverConn)
+			}
+			file, ok := otherDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("unexpected driver connection %T", otherDriverConn)
+			}
+
+			src, dest := live, file
+			if restore {
+				src, dest = file, live
+			}
+			return runBackup(dest, src)
+		})
+	})
+}
+
+// runBackup copies every page of src into dest in a single step.
+func runBackup(dest, src *sqlite3.SQLiteConn) error {
+	bk, err := dest.Backup("main", src, "main")
+	if err != nil {
+		return fmt.Errorf("start backup: %w", err)
+	}
+
+	done, err := bk.Step(-1)
+	if err != nil {
+		bk.Finish()
+		return fmt.Errorf("copy pages: %w", err)
+	}
+	if !done {
+		bk.Finish()
+		return fmt.Errorf("copy pages: backup did not complete")
+	}
+	return bk.Finish()
+}
@@ -0,0 +1,14 @@
+//go:build !cgo
+
+package store
+
+import (
+	"context"
+	"errors"
+)
+
+// copyDatabase needs SQLite's online backup API, which is only available
+// when the sqlite3 driver is built with cgo.
+func (s *Store) copyDatabase(ctx context.Context, path string, restore bool) error {
+	return errors.New("database backup and restore require a build with cgo enabled")
+}
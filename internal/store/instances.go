@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/simpleflo/conduit/pkg/models"
@@ -12,16 +13,25 @@ import (
 
 // CreateInstance creates a new connector instance.
 func (s *Store) CreateInstance(ctx context.Context, instance *models.ConnectorInstance) error {
-	config, _ := json.Marshal(instance.Config)
+	config, err := s.marshalConfig(instance.Config)
+	if err != nil {
+		return err
+	}
 	grantedPerms, _ := json.Marshal(instance.GrantedPerms)
 	auditResult, _ := json.Marshal(instance.AuditResult)
 
-	_, err := s.db.ExecContext(ctx, `
+	var resourceLimits sql.NullString
+	if instance.Resources != nil {
+		data, _ := json.Marshal(instance.Resources)
+		resourceLimits = sql.NullString{String: string(data), Valid: true}
+	}
+
+	_, err = s.db.ExecContext(ctx, `
 		INSERT INTO connector_instances (
 			instance_id, package_id, package_version, display_name, status,
 			container_id, socket_path, image_ref, config, granted_perms,
-			audit_result, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			audit_result, created_at, updated_at, resource_limits, platform
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`,
 		instance.InstanceID,
 		instance.PackageID,
@@ -31,11 +41,13 @@ func (s *Store) CreateInstance(ctx context.Context, instance *models.ConnectorIn
 		nullString(instance.ContainerID),
 		nullString(instance.SocketPath),
 		instance.ImageRef,
-		string(config),
+		config,
 		string(grantedPerms),
 		string(auditResult),
 		instance.CreatedAt.Format(time.RFC3339),
 		instance.UpdatedAt.Format(time.RFC3339),
+		resourceLimits,
+		nullString(instance.Platform),
 	)
 
 	if err != nil {
@@ -52,12 +64,20 @@ func (s *Store) GetInstance(ctx context.Context, instanceID string) (*models.Con
 			instance_id, package_id, package_version, display_name, status,
 			container_id, socket_path, image_ref, config, granted_perms,
 			audit_result, created_at, updated_at, started_at, stopped_at,
-			last_health_check, health_status, error_message
+			last_health_check, health_status, error_message,
+			restart_count, last_restart_at, resource_limits, platform
 		FROM connector_instances
 		WHERE instance_id = ?
 	`, instanceID)
 
-	return scanInstance(row)
+	instance, err := scanInstance(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decryptConfig(instance); err != nil {
+		return nil, err
+	}
+	return instance, nil
 }
 
 // ListInstances returns all connector instances.
@@ -67,7 +87,8 @@ func (s *Store) ListInstances(ctx context.Context) ([]*models.ConnectorInstance,
 			instance_id, package_id, package_version, display_name, status,
 			container_id, socket_path, image_ref, config, granted_perms,
 			audit_result, created_at, updated_at, started_at, stopped_at,
-			last_health_check, health_status, error_message
+			last_health_check, health_status, error_message,
+			restart_count, last_restart_at, resource_limits, platform
 		FROM connector_instances
 		ORDER BY created_at DESC
 	`)
@@ -82,6 +103,9 @@ func (s *Store) ListInstances(ctx context.Context) ([]*models.ConnectorInstance,
 		if err != nil {
 			return nil, fmt.Errorf("scan instance: %w", err)
 		}
+		if err := s.decryptConfig(instance); err != nil {
+			return nil, err
+		}
 		instances = append(instances, instance)
 	}
 
@@ -95,7 +119,8 @@ func (s *Store) ListInstancesByStatus(ctx context.Context, status models.Instanc
 			instance_id, package_id, package_version, display_name, status,
 			container_id, socket_path, image_ref, config, granted_perms,
 			audit_result, created_at, updated_at, started_at, stopped_at,
-			last_health_check, health_status, error_message
+			last_health_check, health_status, error_message,
+			restart_count, last_restart_at, resource_limits, platform
 		FROM connector_instances
 		WHERE status = ?
 		ORDER BY created_at DESC
@@ -111,6 +136,9 @@ func (s *Store) ListInstancesByStatus(ctx context.Context, status models.Instanc
 		if err != nil {
 			return nil, fmt.Errorf("scan instance: %w", err)
 		}
+		if err := s.decryptConfig(instance); err != nil {
+			return nil, err
+		}
 		instances = append(instances, instance)
 	}
 
@@ -202,8 +230,88 @@ func (s *Store) DeleteInstance(ctx context.Context, instanceID string) error {
 	return nil
 }
 
+// EncryptInstanceConfigs encrypts sensitive config values still stored in
+// plain text, such as those written before config encryption was enabled.
+// It returns the number of instances updated and does nothing without a
+// config cipher.
+func (s *Store) EncryptInstanceConfigs(ctx context.Context) (int, error) {
+	if s.configCipher == nil {
+		return 0, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT instance_id, config FROM connector_instances WHERE config IS NOT NULL AND config != ''
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("query instance configs: %w", err)
+	}
+	pending := make(map[string]string)
+	for rows.Next() {
+		var instanceID, data string
+		if err := rows.Scan(&instanceID, &data); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan instance config: %w", err)
+		}
+		pending[instanceID] = data
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("query instance configs: %w", err)
+	}
+
+	updated := 0
+	for instanceID, data := range pending {
+		var config map[string]string
+		if err := json.Unmarshal([]byte(data), &config); err != nil || config == nil {
+			continue
+		}
+		// Compare values, not JSON: the stored key order may differ from ours
+		encrypted, err := s.configCipher.EncryptConfig(config)
+		if err != nil {
+			return updated, fmt.Errorf("encrypt config of %s: %w", instanceID, err)
+		}
+		if maps.Equal(encrypted, config) {
+			continue
+		}
+		encoded, _ := json.Marshal(encrypted)
+		if _, err := s.db.ExecContext(ctx, `
+			UPDATE connector_instances SET config = ? WHERE instance_id = ?
+		`, string(encoded), instanceID); err != nil {
+			return updated, fmt.Errorf("encrypt config of %s: %w", instanceID, err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
 // Helper functions
 
+// marshalConfig encodes an instance config for storage, encrypting its
+// sensitive values when a config cipher is set.
+func (s *Store) marshalConfig(config map[string]string) (string, error) {
+	if s.configCipher != nil {
+		var err error
+		if config, err = s.configCipher.EncryptConfig(config); err != nil {
+			return "", fmt.Errorf("encrypt config: %w", err)
+		}
+	}
+	data, _ := json.Marshal(config)
+	return string(data), nil
+}
+
+// decryptConfig decrypts the encrypted values of a scanned instance's config.
+func (s *Store) decryptConfig(instance *models.ConnectorInstance) error {
+	if s.configCipher == nil {
+		return nil
+	}
+	config, err := s.configCipher.DecryptConfig(instance.Config)
+	if err != nil {
+		return fmt.Errorf("instance %s: %w", instance.InstanceID, err)
+	}
+	instance.Config = config
+	return nil
+}
+
 func nullString(s string) sql.NullString {
 	if s == "" {
 		return sql.NullString{}
@@ -218,6 +326,7 @@ func scanInstance(row *sql.Row) (*models.ConnectorInstance, error) {
 		config, grantedPerms, auditResult                      sql.NullString
 		createdAt, updatedAt                                   string
 		startedAt, stoppedAt, lastHealthCheck                  sql.NullString
+		lastRestartAt, resourceLimits, platform                sql.NullString
 	)
 
 	err := row.Scan(
@@ -239,6 +348,10 @@ func scanInstance(row *sql.Row) (*models.ConnectorInstance, error) {
 		&lastHealthCheck,
 		&healthStatus,
 		&errorMsg,
+		&instance.RestartCount,
+		&lastRestartAt,
+		&resourceLimits,
+		&platform,
 	)
 
 	if err == sql.ErrNoRows {
@@ -264,6 +377,10 @@ func scanInstance(row *sql.Row) (*models.ConnectorInstance, error) {
 		t, _ := time.Parse(time.RFC3339, lastHealthCheck.String)
 		instance.LastHealthCheck = &t
 	}
+	if lastRestartAt.Valid {
+		t, _ := time.Parse(time.RFC3339, lastRestartAt.String)
+		instance.LastRestartAt = &t
+	}
 
 	// Parse JSON fields
 	if config.Valid {
@@ -275,11 +392,15 @@ func scanInstance(row *sql.Row) (*models.ConnectorInstance, error) {
 	if auditResult.Valid {
 		json.Unmarshal([]byte(auditResult.String), &instance.AuditResult)
 	}
+	if resourceLimits.Valid && resourceLimits.String != "" {
+		json.Unmarshal([]byte(resourceLimits.String), &instance.Resources)
+	}
 
 	instance.ContainerID = containerID.String
 	instance.SocketPath = socketPath.String
 	instance.HealthStatus = healthStatus.String
 	instance.ErrorMessage = errorMsg.String
+	instance.Platform = platform.String
 
 	return &instance, nil
 }
@@ -291,6 +412,7 @@ func scanInstanceRows(rows *sql.Rows) (*models.ConnectorInstance, error) {
 		config, grantedPerms, auditResult                      sql.NullString
 		createdAt, updatedAt                                   string
 		startedAt, stoppedAt, lastHealthCheck                  sql.NullString
+		lastRestartAt, resourceLimits, platform                sql.NullString
 	)
 
 	err := rows.Scan(
@@ -312,6 +434,10 @@ func scanInstanceRows(rows *sql.Rows) (*models.ConnectorInstance, error) {
 		&lastHealthCheck,
 		&healthStatus,
 		&errorMsg,
+		&instance.RestartCount,
+		&lastRestartAt,
+		&resourceLimits,
+		&platform,
 	)
 
 	if err != nil {
@@ -334,6 +460,10 @@ func scanInstanceRows(rows *sql.Rows) (*models.ConnectorInstance, error) {
 		t, _ := time.Parse(time.RFC3339, lastHealthCheck.String)
 		instance.LastHealthCheck = &t
 	}
+	if lastRestartAt.Valid {
+		t, _ := time.Parse(time.RFC3339, lastRestartAt.String)
+		instance.LastRestartAt = &t
+	}
 
 	// Parse JSON fields
 	if config.Valid {
@@ -345,11 +475,15 @@ func scanInstanceRows(rows *sql.Rows) (*models.ConnectorInstance, error) {
 	if auditResult.Valid {
 		json.Unmarshal([]byte(auditResult.String), &instance.AuditResult)
 	}
+	if resourceLimits.Valid && resourceLimits.String != "" {
+		json.Unmarshal([]byte(resourceLimits.String), &instance.Resources)
+	}
 
 	instance.ContainerID = containerID.String
 	instance.SocketPath = socketPath.String
 	instance.HealthStatus = healthStatus.String
 	instance.ErrorMessage = errorMsg.String
+	instance.Platform = platform.String
 
 	return &instance, nil
 }
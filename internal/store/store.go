@@ -1,14 +1,26 @@
 // Package store provides SQLite database operations for Conduit.
+//
+// Concurrency model: the database is opened in WAL journal mode, so readers
+// never block the writer and the writer never blocks readers. Within one
+// process the Store uses a single connection, which serializes its own
+// queries. Separate processes (the daemon and "conduit mcp kb") open their
+// own Store on the same file; SQLite allows one writer at a time across them,
+// and a writer that finds the database locked waits up to the busy timeout
+// before failing with "database is locked".
 package store
 
 import (
 	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/simpleflo/conduit/internal/secrets"
 )
 
 //go:embed migrations/*.sql
@@ -17,12 +29,48 @@ var migrationsFS embed.FS
 // Store provides database operations for Conduit.
 type Store struct {
 	db *sql.DB
+
+	backupDir       string
+	backupRetention int
+
+	// Encrypts sensitive instance config values at rest; nil stores them
+	// as given
+	configCipher *secrets.ConfigCipher
+}
+
+// Options configures optional Store behavior.
+type Options struct {
+	// BackupDir receives automatic backups taken before migrations and
+	// destructive operations. Empty disables automatic backups.
+	BackupDir string
+
+	// BackupRetention is the number of automatic backups to keep
+	// (default DefaultBackupRetention).
+	BackupRetention int
+
+	// BusyTimeout is how long a write waits for another connection's lock
+	// before failing (default DefaultBusyTimeout).
+	BusyTimeout time.Duration
 }
 
+// DefaultBusyTimeout is the lock wait used when Options.BusyTimeout is unset.
+const DefaultBusyTimeout = 5 * time.Second
+
 // New creates a new Store with the given database path.
 func New(dbPath string) (*Store, error) {
+	return NewWithOptions(dbPath, Options{})
+}
+
+// NewWithOptions creates a new Store with the given database path and options.
+func NewWithOptions(dbPath string, opts Options) (*Store, error) {
+	busyTimeout := opts.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = DefaultBusyTimeout
+	}
+
 	// Open database with WAL mode for better concurrency
-	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_foreign_keys=ON&_busy_timeout=5000")
+	dsn := fmt.Sprintf("%s?_journal_mode=WAL&_foreign_keys=ON&_busy_timeout=%d", dbPath, busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -38,7 +86,18 @@ func New(dbPath string) (*Store, error) {
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	store := &Store{db: db}
+	// The schema needs FTS5; fail here with a clear message rather than
+	// with "no such module" from a migration
+	if err := probeFTS5(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	store := &Store{
+		db:              db,
+		backupDir:       opts.BackupDir,
+		backupRetention: opts.BackupRetention,
+	}
 
 	// Run migrations
 	if err := store.migrate(); err != nil {
@@ -54,12 +113,100 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// SetConfigCipher enables encryption of sensitive instance config values.
+// Existing plain-text values are encrypted by EncryptInstanceConfigs.
+func (s *Store) SetConfigCipher(cipher *secrets.ConfigCipher) {
+	s.configCipher = cipher
+}
+
 // DB returns the underlying database connection.
 func (s *Store) DB() *sql.DB {
 	return s.db
 }
 
-// migrate runs all pending database migrations.
+// ErrSchemaTooNew is returned when the database was migrated by a newer
+// version of Conduit than this binary understands.
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of conduit")
+
+// ErrFTS5Unavailable is returned when the SQLite driver was compiled without
+// the FTS5 module, which the knowledge base index requires.
+var ErrFTS5Unavailable = errors.New("sqlite fts5 module not available: rebuild conduit with -tags fts5")
+
+// CheckFTS5 reports whether this binary's SQLite driver includes FTS5. It
+// probes a private in-memory database, so no data file is touched.
+func CheckFTS5(ctx context.Context) error {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return fmt.Errorf("open probe database: %w", err)
+	}
+	defer db.Close()
+	return probeFTS5(ctx, db)
+}
+
+// probeFTS5 creates and drops a throwaway FTS5 table in the temp schema.
+func probeFTS5(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("fts5 probe: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `CREATE VIRTUAL TABLE temp.conduit_fts5_probe USING fts5(content)`); err != nil {
+		if strings.Contains(err.Error(), "no such module") {
+			return ErrFTS5Unavailable
+		}
+		return fmt.Errorf("fts5 probe: %w", err)
+	}
+	_, err = conn.ExecContext(ctx, `DROP TABLE temp.conduit_fts5_probe`)
+	return err
+}
+
+// migration is one versioned schema change.
+type migration struct {
+	version     int
+	description string
+	apply       func(tx *sql.Tx) error
+}
+
+// migrations returns the schema changes in the order they must be applied.
+// Append new migrations at the end; never renumber or edit one that has
+// been released, since existing databases have already recorded it.
+func (s *Store) migrations() []migration {
+	return []migration{
+		{1, "initial schema", s.runMigration001},
+		{2, "policy user grants", s.runMigration002},
+		{3, "enhanced FTS5 schema", s.runMigration003},
+		{4, "KAG entity and relation tables", s.runMigration004},
+		{5, "persisted lifecycle operations", s.runMigration005},
+		{6, "instance auto-restart tracking", s.runMigration006},
+		{7, "per-instance resource limits", s.runMigration007},
+		{8, "package-declared permissions", s.runMigration008},
+		{9, "policy decision audit log", s.runMigration009},
+		{10, "resumable vector migration tracking", s.runMigration010},
+		{11, "per-source entity extraction toggle", s.runMigration011},
+		{12, "instance image platform", s.runMigration012},
+		{13, "KB source sync outcome", s.runMigration013},
+		{14, "KB source sync schedule", s.runMigration014},
+		{15, "KB search vocabulary", s.runMigration015},
+	}
+}
+
+// LatestSchemaVersion returns the newest schema version this binary can apply.
+func (s *Store) LatestSchemaVersion() int {
+	all := s.migrations()
+	return all[len(all)-1].version
+}
+
+// SchemaVersion returns the schema version recorded in the database.
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM migrations").Scan(&version)
+	return version, err
+}
+
+// migrate runs all pending database migrations. Each migration is applied in
+// its own transaction together with the row recording its version, so a
+// failure leaves the database at the last complete version.
 func (s *Store) migrate() error {
 	// Create migrations table if not exists
 	_, err := s.db.Exec(`
@@ -72,54 +219,60 @@ func (s *Store) migrate() error {
 		return fmt.Errorf("create migrations table: %w", err)
 	}
 
-	// Get current version
-	var currentVersion int
-	err = s.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM migrations").Scan(&currentVersion)
+	currentVersion, err := s.SchemaVersion(context.Background())
 	if err != nil {
 		return fmt.Errorf("get current version: %w", err)
 	}
 
-	// Run the initial schema migration
-	if currentVersion < 1 {
-		if err := s.runMigration001(); err != nil {
-			return fmt.Errorf("run migration 001: %w", err)
-		}
+	// Refuse to touch a database written by a newer binary: its schema may
+	// not match what this version reads and writes
+	if latest := s.LatestSchemaVersion(); currentVersion > latest {
+		return fmt.Errorf("%w: database is at version %d, this binary supports up to %d; upgrade conduit",
+			ErrSchemaTooNew, currentVersion, latest)
 	}
 
-	// Run migration 002 for policy user grants
-	if currentVersion < 2 {
-		if err := s.runMigration002(); err != nil {
-			return fmt.Errorf("run migration 002: %w", err)
+	// Back up an existing database before changing its schema
+	if currentVersion > 0 && currentVersion < s.LatestSchemaVersion() {
+		if _, err := s.AutoBackup(context.Background(), fmt.Sprintf("pre-migrate-v%d", currentVersion)); err != nil {
+			return err
 		}
 	}
 
-	// Run migration 003 for enhanced FTS5 schema
-	if currentVersion < 3 {
-		if err := s.runMigration003(); err != nil {
-			return fmt.Errorf("run migration 003: %w", err)
+	for _, m := range s.migrations() {
+		if m.version <= currentVersion {
+			continue
 		}
-	}
-
-	// Run migration 004 for KAG (Knowledge-Augmented Generation) tables
-	if currentVersion < 4 {
-		if err := s.runMigration004(); err != nil {
-			return fmt.Errorf("run migration 004: %w", err)
+		if err := s.applyMigration(m); err != nil {
+			return fmt.Errorf("run migration %03d: %w", m.version, err)
 		}
 	}
 
 	return nil
 }
 
-// runMigration001 creates the initial schema.
-func (s *Store) runMigration001() error {
+// applyMigration runs one migration and records its version atomically.
+func (s *Store) applyMigration(m migration) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
+	if err := m.apply(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT INTO migrations (version) VALUES (?)", m.version); err != nil {
+		return fmt.Errorf("record version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// runMigration001 creates the initial schema.
+func (s *Store) runMigration001(tx *sql.Tx) error {
 	// Connector instances table
-	_, err = tx.Exec(`
+	_, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS connector_instances (
 			instance_id TEXT PRIMARY KEY,
 			package_id TEXT NOT NULL,
@@ -302,32 +455,86 @@ func (s *Store) runMigration001() error {
 		return err
 	}
 
-	// Record migration
-	_, err = tx.Exec("INSERT INTO migrations (version) VALUES (1)")
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	return tx.Commit()
+// requiredTables are the tables the daemon cannot operate without.
+var requiredTables = []string{
+	"kb_documents",
+	"kb_chunks",
+	"kb_fts",
+	"connector_instances",
+	"client_bindings",
+	"user_grants",
 }
 
-// Health checks database connectivity.
+// Health checks that the database is reachable, has the required schema, can
+// run FTS5 queries and accepts writes. The error names the first failing check.
 func (s *Store) Health(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	return s.db.PingContext(ctx)
+
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+
+	for _, table := range requiredTables {
+		var name string
+		err := s.db.QueryRowContext(ctx,
+			`SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?`, table,
+		).Scan(&name)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("schema: required table %s is missing", table)
+		}
+		if err != nil {
+			return fmt.Errorf("schema: check table %s: %w", table, err)
+		}
+	}
+
+	// A MATCH query fails if the FTS5 module is unavailable or the index is corrupt
+	var n int
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM (SELECT rowid FROM kb_fts WHERE kb_fts MATCH 'conduit' LIMIT 1)`,
+	).Scan(&n); err != nil {
+		return fmt.Errorf("fts5: test query failed: %w", err)
+	}
+
+	if err := s.checkWritable(ctx); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
 }
 
-// runMigration002 adds policy user grants table.
-func (s *Store) runMigration002() error {
-	tx, err := s.db.Begin()
+// checkWritable verifies the database accepts writes by inserting a sentinel
+// migration row inside a transaction that is always rolled back. It fails if
+// the database is opened read-only or another process holds the lock past the
+// busy timeout.
+func (s *Store) checkWritable(ctx context.Context) error {
+	var queryOnly bool
+	if err := s.db.QueryRowContext(ctx, `PRAGMA query_only`).Scan(&queryOnly); err != nil {
+		return fmt.Errorf("read query_only pragma: %w", err)
+	}
+	if queryOnly {
+		return fmt.Errorf("database is in query-only mode")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return fmt.Errorf("begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	if _, err := tx.ExecContext(ctx, `INSERT INTO migrations (version) VALUES (-1)`); err != nil {
+		return fmt.Errorf("database is read-only or locked: %w", err)
+	}
+	return nil
+}
+
+// runMigration002 adds policy user grants table.
+func (s *Store) runMigration002(tx *sql.Tx) error {
 	// User grants table for policy engine
-	_, err = tx.Exec(`
+	_, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS user_grants (
 			instance_id TEXT NOT NULL,
 			permission_type TEXT NOT NULL,
@@ -348,25 +555,13 @@ func (s *Store) runMigration002() error {
 		return err
 	}
 
-	// Record migration
-	_, err = tx.Exec("INSERT INTO migrations (version) VALUES (2)")
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit()
+	return nil
 }
 
 // runMigration003 updates FTS5 schema with title and path columns.
-func (s *Store) runMigration003() error {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
+func (s *Store) runMigration003(tx *sql.Tx) error {
 	// Drop and recreate FTS5 table with enhanced schema
-	_, err = tx.Exec(`DROP TABLE IF EXISTS kb_fts`)
+	_, err := tx.Exec(`DROP TABLE IF EXISTS kb_fts`)
 	if err != nil {
 		return err
 	}
@@ -397,25 +592,13 @@ func (s *Store) runMigration003() error {
 		// Ignore error if no data exists yet
 	}
 
-	// Record migration
-	_, err = tx.Exec("INSERT INTO migrations (version) VALUES (3)")
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit()
+	return nil
 }
 
 // runMigration004 adds KAG (Knowledge-Augmented Generation) tables for entity/relation storage.
-func (s *Store) runMigration004() error {
-	tx, err := s.db.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
+func (s *Store) runMigration004(tx *sql.Tx) error {
 	// KAG entities table - stores extracted entities from documents
-	_, err = tx.Exec(`
+	_, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS kb_entities (
 			entity_id TEXT PRIMARY KEY,
 			name TEXT NOT NULL,
@@ -487,11 +670,178 @@ func (s *Store) runMigration004() error {
 		return err
 	}
 
-	// Record migration
-	_, err = tx.Exec("INSERT INTO migrations (version) VALUES (4)")
+	return nil
+}
+
+// runMigration005 adds the operations table so lifecycle operations survive daemon restarts.
+func (s *Store) runMigration005(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS operations (
+			operation_id TEXT PRIMARY KEY,
+			type TEXT NOT NULL,
+			instance_id TEXT NOT NULL,
+			status TEXT NOT NULL,
+			stage TEXT,
+			progress INTEGER NOT NULL DEFAULT 0,
+			error TEXT,
+			result TEXT,
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			completed_at TEXT
+		)
+	`)
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	_, err = tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_operations_status ON operations(status);
+		CREATE INDEX IF NOT EXISTS idx_operations_created ON operations(created_at);
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration006 adds restart tracking columns to connector instances.
+func (s *Store) runMigration006(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		ALTER TABLE connector_instances ADD COLUMN restart_count INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE connector_instances ADD COLUMN last_restart_at TEXT;
+	`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration007 adds per-instance container resource limits.
+func (s *Store) runMigration007(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE connector_instances ADD COLUMN resource_limits TEXT`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration008 stores the permissions a package declares alongside the instance.
+func (s *Store) runMigration008(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE connector_instances ADD COLUMN declared_perms TEXT`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration009 adds the policy decision audit log.
+func (s *Store) runMigration009(tx *sql.Tx) error {
+	// Instance ID is not a foreign key: decisions are kept after an instance
+	// is removed, and install-time checks may not have an instance yet.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS policy_decisions (
+			decision_id TEXT PRIMARY KEY,
+			instance_id TEXT,
+			package_id TEXT,
+			scope TEXT,
+			actor TEXT,
+			decision TEXT NOT NULL,
+			reason TEXT,
+			requested TEXT,
+			effective TEXT,
+			warnings TEXT,
+			block_reasons TEXT,
+			created_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_policy_decisions_instance ON policy_decisions(instance_id, created_at)`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_policy_decisions_created ON policy_decisions(created_at)`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration010 records when each document's vectors were stored, so
+// FTS-to-vector migration can resume where it stopped.
+func (s *Store) runMigration010(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE kb_documents ADD COLUMN vectors_indexed_at TEXT`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration011 adds a per-source entity extraction toggle. Existing sources
+// keep extracting so upgrading does not change their behavior.
+func (s *Store) runMigration011(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE kb_sources ADD COLUMN extract_entities INTEGER NOT NULL DEFAULT 1`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration012 records the platform an instance's image was built for.
+func (s *Store) runMigration012(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE connector_instances ADD COLUMN platform TEXT`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration013 records when each KB source last tried to sync and how that
+// went, so a source that keeps failing stands out. The error column already
+// exists and now holds the last sync's error.
+func (s *Store) runMigration013(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE kb_sources ADD COLUMN last_sync_attempt TEXT`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE kb_sources ADD COLUMN last_sync_status TEXT`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration014 adds a per-source override of the scheduled sync interval,
+// in seconds. NULL uses the configured interval and 0 turns scheduling off.
+func (s *Store) runMigration014(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE kb_sources ADD COLUMN sync_interval INTEGER`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runMigration015 exposes the terms of the FTS5 index, which KB search uses
+// to correct misspelled query terms.
+func (s *Store) runMigration015(tx *sql.Tx) error {
+	_, err := tx.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS kb_fts_vocab USING fts5vocab(kb_fts, 'row')`)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
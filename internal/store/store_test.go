@@ -2,12 +2,18 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/simpleflo/conduit/internal/secrets"
 	"github.com/simpleflo/conduit/pkg/models"
 )
 
@@ -30,6 +36,144 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestStore_Migrations(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+
+	// Versions must be consecutive so none is skipped on upgrade
+	for i, m := range store.migrations() {
+		if m.version != i+1 {
+			t.Fatalf("migration %d has version %d, want %d", i, m.version, i+1)
+		}
+	}
+
+	version, err := store.SchemaVersion(context.Background())
+	if err != nil {
+		t.Fatalf("schema version: %v", err)
+	}
+	if version != store.LatestSchemaVersion() {
+		t.Errorf("expected schema version %d, got %d", store.LatestSchemaVersion(), version)
+	}
+}
+
+func TestStore_RefusesNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := New(dbPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "fts5") {
+			t.Skip("FTS5 not available, skipping test")
+		}
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	// Simulate a database upgraded by a newer binary
+	if _, err := store.DB().Exec("INSERT INTO migrations (version) VALUES (?)", store.LatestSchemaVersion()+1); err != nil {
+		t.Fatalf("insert version: %v", err)
+	}
+	store.Close()
+
+	_, err = New(dbPath)
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestNew_ConnectionSettings(t *testing.T) {
+	store, err := NewWithOptions(filepath.Join(t.TempDir(), "test.db"), Options{BusyTimeout: 2 * time.Second})
+	if err != nil {
+		if strings.Contains(err.Error(), "fts5") {
+			t.Skip("FTS5 not available, skipping test")
+		}
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	var journalMode string
+	var foreignKeys, busyTimeout int
+	store.DB().QueryRow("PRAGMA journal_mode").Scan(&journalMode)
+	store.DB().QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys)
+	store.DB().QueryRow("PRAGMA busy_timeout").Scan(&busyTimeout)
+
+	if journalMode != "wal" {
+		t.Errorf("expected WAL journal mode, got %q", journalMode)
+	}
+	if foreignKeys != 1 {
+		t.Error("expected foreign keys to be enabled")
+	}
+	if busyTimeout != 2000 {
+		t.Errorf("expected busy timeout 2000ms, got %d", busyTimeout)
+	}
+}
+
+// TestStore_ConcurrentAccess simulates the daemon and the MCP KB server
+// reading and writing the same database from separate stores.
+func TestStore_ConcurrentAccess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping stress test in short mode")
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	daemon, err := New(dbPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "fts5") {
+			t.Skip("FTS5 not available, skipping test")
+		}
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer daemon.Close()
+
+	mcp, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("failed to open second store: %v", err)
+	}
+	defer mcp.Close()
+
+	const workers, iterations = 4, 50
+	errs := make(chan error, 2*workers*iterations)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		st := daemon
+		if w%2 == 1 {
+			st = mcp
+		}
+
+		wg.Add(2)
+		go func(w int, st *Store) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				_, err := st.DB().Exec(`INSERT INTO kb_sources (source_id, path, name) VALUES (?, ?, ?)`,
+					fmt.Sprintf("src_%d_%d", w, i), fmt.Sprintf("/docs/%d/%d", w, i), "Docs")
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(w, st)
+
+		go func(st *Store) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				var n int
+				if err := st.DB().QueryRow(`SELECT COUNT(*) FROM kb_sources`).Scan(&n); err != nil {
+					errs <- err
+				}
+			}
+		}(st)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent access failed: %v", err)
+	}
+
+	var count int
+	daemon.DB().QueryRow(`SELECT COUNT(*) FROM kb_sources`).Scan(&count)
+	if count != workers*iterations {
+		t.Errorf("expected %d sources, got %d", workers*iterations, count)
+	}
+}
+
 func TestStore_Health(t *testing.T) {
 	store := testStore(t)
 	defer store.Close()
@@ -40,6 +184,133 @@ func TestStore_Health(t *testing.T) {
 	}
 }
 
+func TestStore_HealthMissingTable(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+
+	if _, err := store.DB().Exec("DROP TABLE user_grants"); err != nil {
+		t.Fatalf("drop table: %v", err)
+	}
+
+	err := store.Health(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "user_grants") {
+		t.Errorf("expected error naming user_grants, got %v", err)
+	}
+}
+
+func TestStore_HealthReadOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	store, err := New(dbPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "fts5") {
+			t.Skip("FTS5 not available, skipping test")
+		}
+		t.Fatalf("failed to create store: %v", err)
+	}
+	store.Close()
+
+	db, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		t.Fatalf("open read-only: %v", err)
+	}
+	readOnly := &Store{db: db}
+	defer readOnly.Close()
+
+	err = readOnly.Health(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), "write:") {
+		t.Errorf("expected write check to fail, got %v", err)
+	}
+}
+
+func TestStore_BackupAndRestore(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	if _, err := store.DB().Exec(`INSERT INTO kb_sources (source_id, path, name) VALUES ('src_1', '/docs', 'Docs')`); err != nil {
+		t.Fatalf("insert source: %v", err)
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := store.Backup(ctx, backupPath); err != nil {
+		t.Fatalf("backup: %v", err)
+	}
+
+	if _, err := store.DB().Exec(`DELETE FROM kb_sources`); err != nil {
+		t.Fatalf("delete sources: %v", err)
+	}
+
+	if err := store.Restore(ctx, backupPath); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	var name string
+	if err := store.DB().QueryRow(`SELECT name FROM kb_sources WHERE source_id = 'src_1'`).Scan(&name); err != nil {
+		t.Fatalf("expected restored source: %v", err)
+	}
+	if err := store.Health(ctx); err != nil {
+		t.Errorf("health after restore: %v", err)
+	}
+}
+
+func TestStore_RestoreRejectsNonConduitFile(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+
+	path := filepath.Join(t.TempDir(), "other.db")
+	other, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Exec(`CREATE TABLE notes (body TEXT)`)
+	other.Close()
+
+	if err := store.Restore(context.Background(), path); err == nil {
+		t.Error("expected restore of a non-Conduit database to fail")
+	}
+}
+
+func TestStore_AutoBackupRetention(t *testing.T) {
+	backupDir := t.TempDir()
+	store, err := NewWithOptions(filepath.Join(t.TempDir(), "test.db"), Options{
+		BackupDir:       backupDir,
+		BackupRetention: 2,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "fts5") {
+			t.Skip("FTS5 not available, skipping test")
+		}
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	// A manual backup in the same directory is never pruned
+	manual := filepath.Join(backupDir, "manual.db")
+	if err := store.Backup(context.Background(), manual); err != nil {
+		t.Fatalf("manual backup: %v", err)
+	}
+
+	var last string
+	for i := 0; i < 4; i++ {
+		last, err = store.AutoBackup(context.Background(), "test")
+		if err != nil {
+			t.Fatalf("auto backup: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond) // Distinct timestamps
+	}
+
+	auto, _ := filepath.Glob(filepath.Join(backupDir, autoBackupPrefix+"*.db"))
+	if len(auto) != 2 {
+		t.Errorf("expected 2 automatic backups after pruning, got %d", len(auto))
+	}
+	if _, err := os.Stat(last); err != nil {
+		t.Errorf("newest backup was pruned: %v", err)
+	}
+	if _, err := os.Stat(manual); err != nil {
+		t.Errorf("manual backup was pruned: %v", err)
+	}
+}
+
 func TestStore_CreateAndGetInstance(t *testing.T) {
 	store := testStore(t)
 	defer store.Close()
@@ -51,6 +322,7 @@ func TestStore_CreateAndGetInstance(t *testing.T) {
 		PackageVersion: "1.0.0",
 		DisplayName:    "Test Connector",
 		ImageRef:       "ghcr.io/test/connector:1.0.0",
+		Platform:       "linux/amd64",
 		Status:         models.StatusCreated,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -76,6 +348,9 @@ func TestStore_CreateAndGetInstance(t *testing.T) {
 	if got.Status != instance.Status {
 		t.Errorf("Status mismatch: got %s, want %s", got.Status, instance.Status)
 	}
+	if got.Platform != instance.Platform {
+		t.Errorf("Platform mismatch: got %s, want %s", got.Platform, instance.Platform)
+	}
 }
 
 func TestStore_ListInstances(t *testing.T) {
@@ -237,6 +512,91 @@ func TestStore_GetInstanceNotFound(t *testing.T) {
 	}
 }
 
+func TestStore_EncryptedInstanceConfig(t *testing.T) {
+	store := testStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	newInstance := func(id string) *models.ConnectorInstance {
+		return &models.ConnectorInstance{
+			InstanceID:     id,
+			PackageID:      "test/connector",
+			PackageVersion: "1.0.0",
+			DisplayName:    "Test Connector",
+			ImageRef:       "ghcr.io/test/connector:1.0.0",
+			Config:         map[string]string{"API_TOKEN": "tok_plaintext", "LOG_LEVEL": "debug"},
+			Status:         models.StatusCreated,
+		}
+	}
+	rawConfig := func(id string) string {
+		var data string
+		if err := store.DB().QueryRowContext(ctx,
+			`SELECT config FROM connector_instances WHERE instance_id = ?`, id,
+		).Scan(&data); err != nil {
+			t.Fatalf("read config: %v", err)
+		}
+		return data
+	}
+
+	// Written before a cipher was configured, as by an older version
+	if err := store.CreateInstance(ctx, newInstance("inst_legacy")); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	cipher, err := secrets.NewConfigCipher(filepath.Join(t.TempDir(), "conduit.key"))
+	if err != nil {
+		t.Fatalf("NewConfigCipher failed: %v", err)
+	}
+	store.SetConfigCipher(cipher)
+
+	if err := store.CreateInstance(ctx, newInstance("inst_new")); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+	if data := rawConfig("inst_new"); strings.Contains(data, "tok_plaintext") || !strings.Contains(data, secrets.EncryptedPrefix) {
+		t.Errorf("config stored in plaintext: %s", data)
+	}
+
+	n, err := store.EncryptInstanceConfigs(ctx)
+	if err != nil {
+		t.Fatalf("EncryptInstanceConfigs failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("EncryptInstanceConfigs updated %d instances, want 1", n)
+	}
+	if data := rawConfig("inst_legacy"); strings.Contains(data, "tok_plaintext") {
+		t.Errorf("legacy config not migrated: %s", data)
+	}
+	if n, _ := store.EncryptInstanceConfigs(ctx); n != 0 {
+		t.Errorf("second migration updated %d instances, want 0", n)
+	}
+
+	// Encrypted configs written with another key order are left alone
+	var config map[string]string
+	json.Unmarshal([]byte(rawConfig("inst_new")), &config)
+	reordered := fmt.Sprintf(`{"LOG_LEVEL": %q, "API_TOKEN": %q}`, config["LOG_LEVEL"], config["API_TOKEN"])
+	if _, err := store.DB().ExecContext(ctx,
+		`UPDATE connector_instances SET config = ? WHERE instance_id = ?`, reordered, "inst_new",
+	); err != nil {
+		t.Fatalf("rewrite config: %v", err)
+	}
+	if n, _ := store.EncryptInstanceConfigs(ctx); n != 0 {
+		t.Errorf("migration of a reordered encrypted config updated %d instances, want 0", n)
+	}
+
+	instances, err := store.ListInstances(ctx)
+	if err != nil {
+		t.Fatalf("ListInstances failed: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("expected 2 instances, got %d", len(instances))
+	}
+	for _, inst := range instances {
+		if inst.Config["API_TOKEN"] != "tok_plaintext" || inst.Config["LOG_LEVEL"] != "debug" {
+			t.Errorf("%s: config not decrypted: %v", inst.InstanceID, inst.Config)
+		}
+	}
+}
+
 // testStore creates a temporary store for testing.
 func testStore(t *testing.T) *Store {
 	t.Helper()
@@ -260,3 +620,19 @@ func cleanup(t *testing.T, path string) {
 	t.Helper()
 	os.RemoveAll(path)
 }
+
+func TestCheckFTS5(t *testing.T) {
+	err := CheckFTS5(context.Background())
+	if errors.Is(err, ErrFTS5Unavailable) {
+		// Built without -tags fts5: opening a store must fail with the same
+		// clear error instead of a migration failure
+		_, newErr := New(filepath.Join(t.TempDir(), "test.db"))
+		if !errors.Is(newErr, ErrFTS5Unavailable) {
+			t.Fatalf("expected ErrFTS5Unavailable from New, got %v", newErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("CheckFTS5: %v", err)
+	}
+}
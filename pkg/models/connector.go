@@ -43,6 +43,18 @@ type ConnectorInstance struct {
 	LastHealthCheck *time.Time        `json:"last_health_check,omitempty"`
 	HealthStatus    string            `json:"health_status,omitempty"`
 	ErrorMessage    string            `json:"error_message,omitempty"`
+	Resources       *ResourceLimits   `json:"resources,omitempty"`
+	RestartCount    int               `json:"restart_count,omitempty"`
+	LastRestartAt   *time.Time        `json:"last_restart_at,omitempty"`
+	Platform        string            `json:"platform,omitempty"` // e.g. linux/arm64; empty means the host's
+}
+
+// ResourceLimits caps the CPU and memory available to an instance container.
+// Zero values fall back to the runtime defaults from config.
+type ResourceLimits struct {
+	MemoryMB  int64   `json:"memory_mb,omitempty"`
+	CPUs      float64 `json:"cpus,omitempty"`
+	CPUShares int64   `json:"cpu_shares,omitempty"`
 }
 
 // ConnectorPackage represents a connector package definition.
@@ -221,3 +233,15 @@ func IsValidTransition(from, to InstanceStatus) bool {
 	}
 	return false
 }
+
+// IsBindable reports whether an instance in status can be bound to a client:
+// it is running or can be started. Instances still being set up, blocked,
+// failed or going away would only leave the client with a broken server.
+func IsBindable(status InstanceStatus) bool {
+	switch status {
+	case StatusInstalled, StatusStarting, StatusRunning, StatusDegraded,
+		StatusStopped, StatusRestarting:
+		return true
+	}
+	return false
+}
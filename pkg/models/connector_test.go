@@ -0,0 +1,20 @@
+package models
+
+import "testing"
+
+func TestIsBindable(t *testing.T) {
+	bindable := map[InstanceStatus]bool{
+		StatusInstalled:  true,
+		StatusStarting:   true,
+		StatusRunning:    true,
+		StatusDegraded:   true,
+		StatusStopped:    true,
+		StatusRestarting: true,
+	}
+
+	for status := range ValidTransitions {
+		if got := IsBindable(status); got != bindable[status] {
+			t.Errorf("IsBindable(%s) = %v, want %v", status, got, bindable[status])
+		}
+	}
+}
@@ -16,11 +16,14 @@ const (
 	// Permission errors
 	ErrPermissionDenied   ErrorCode = "E_PERMISSION_DENIED"
 	ErrPermissionRequired ErrorCode = "E_PERMISSION_REQUIRED"
+	ErrUnauthorized       ErrorCode = "E_UNAUTHORIZED"
 
 	// Lifecycle errors
 	ErrInvalidTransition ErrorCode = "E_INVALID_TRANSITION"
 	ErrInstanceNotFound  ErrorCode = "E_INSTANCE_NOT_FOUND"
 	ErrInstanceExists    ErrorCode = "E_INSTANCE_EXISTS"
+	ErrOperationNotFound ErrorCode = "E_OPERATION_NOT_FOUND"
+	ErrInstanceNotReady  ErrorCode = "E_INSTANCE_NOT_READY"
 
 	// Audit errors
 	ErrAuditFailed  ErrorCode = "E_AUDIT_FAILED"
@@ -32,17 +35,25 @@ const (
 	ErrConfigWriteFail ErrorCode = "E_CONFIG_WRITE_FAIL"
 
 	// Client errors
-	ErrClientNotFound    ErrorCode = "E_CLIENT_NOT_FOUND"
+	ErrClientNotFound     ErrorCode = "E_CLIENT_NOT_FOUND"
 	ErrClientNotInstalled ErrorCode = "E_CLIENT_NOT_INSTALLED"
-	ErrBindingNotFound   ErrorCode = "E_BINDING_NOT_FOUND"
-	ErrBindingExists     ErrorCode = "E_BINDING_EXISTS"
+	ErrBindingNotFound    ErrorCode = "E_BINDING_NOT_FOUND"
+	ErrBindingExists      ErrorCode = "E_BINDING_EXISTS"
 
 	// KB errors
-	ErrSourceNotFound  ErrorCode = "E_SOURCE_NOT_FOUND"
-	ErrSourceExists    ErrorCode = "E_SOURCE_EXISTS"
-	ErrPathNotFound    ErrorCode = "E_PATH_NOT_FOUND"
-	ErrPathNotReadable ErrorCode = "E_PATH_NOT_READABLE"
-	ErrIndexFailed     ErrorCode = "E_INDEX_FAILED"
+	ErrSourceNotFound      ErrorCode = "E_SOURCE_NOT_FOUND"
+	ErrSourceExists        ErrorCode = "E_SOURCE_EXISTS"
+	ErrPathNotFound        ErrorCode = "E_PATH_NOT_FOUND"
+	ErrPathNotReadable     ErrorCode = "E_PATH_NOT_READABLE"
+	ErrIndexFailed         ErrorCode = "E_INDEX_FAILED"
+	ErrDocumentNotFound    ErrorCode = "E_DOCUMENT_NOT_FOUND"
+	ErrEntityNotFound      ErrorCode = "E_ENTITY_NOT_FOUND"
+	ErrSemanticUnavailable ErrorCode = "E_SEMANTIC_UNAVAILABLE"
+	ErrMigrationRunning    ErrorCode = "E_MIGRATION_RUNNING"
+	ErrMigrationFailed     ErrorCode = "E_MIGRATION_FAILED"
+	ErrBackupNotFound      ErrorCode = "E_BACKUP_NOT_FOUND"
+	ErrBackupFailed        ErrorCode = "E_BACKUP_FAILED"
+	ErrRestoreFailed       ErrorCode = "E_RESTORE_FAILED"
 
 	// Secret errors
 	ErrSecretNotFound ErrorCode = "E_SECRET_NOT_FOUND"
@@ -56,6 +67,11 @@ const (
 	// Daemon errors
 	ErrDaemonNotRunning  ErrorCode = "E_DAEMON_NOT_RUNNING"
 	ErrDaemonUnavailable ErrorCode = "E_DAEMON_UNAVAILABLE"
+	ErrInsufficientDisk  ErrorCode = "E_INSUFFICIENT_DISK"
+	ErrInternal          ErrorCode = "E_INTERNAL" // Unexpected failure; the daemon log has the details
+	ErrRouteNotFound     ErrorCode = "E_ROUTE_NOT_FOUND"
+	ErrMethodNotAllowed  ErrorCode = "E_METHOD_NOT_ALLOWED"
+	ErrRequestTooLarge   ErrorCode = "E_REQUEST_TOO_LARGE"
 )
 
 // ConduitError represents a structured error with code and context.
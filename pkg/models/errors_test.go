@@ -137,11 +137,27 @@ func TestErrorCodes(t *testing.T) {
 		ErrImagePullFailed:     true,
 		ErrDaemonNotRunning:    true,
 		ErrDaemonUnavailable:   true,
+		ErrOperationNotFound:   true,
+		ErrInstanceNotReady:    true,
+		ErrDocumentNotFound:    true,
+		ErrEntityNotFound:      true,
+		ErrSemanticUnavailable: true,
+		ErrMigrationRunning:    true,
+		ErrMigrationFailed:     true,
+		ErrBackupNotFound:      true,
+		ErrBackupFailed:        true,
+		ErrRestoreFailed:       true,
+		ErrInsufficientDisk:    true,
+		ErrInternal:            true,
+		ErrRouteNotFound:       true,
+		ErrMethodNotAllowed:    true,
+		ErrRequestTooLarge:     true,
+		ErrUnauthorized:        true,
 	}
 
 	// All codes should be unique (map would just overwrite if not)
-	if len(codes) != 30 {
-		t.Errorf("Expected 30 unique error codes, got %d", len(codes))
+	if len(codes) != 46 {
+		t.Errorf("Expected 46 unique error codes, got %d", len(codes))
 	}
 }
 
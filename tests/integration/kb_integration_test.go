@@ -2,11 +2,18 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/simpleflo/conduit/internal/diskspace"
 	"github.com/simpleflo/conduit/internal/kb"
 	"github.com/simpleflo/conduit/internal/store"
 )
@@ -404,20 +411,1552 @@ func min(a, b int) int {
 	return b
 }
 
+// TestKBHybridSearchTogglesIntegration verifies that explicitly disabling MMR
+// and reranking is honored instead of being overridden by the recall preset.
+func TestKBHybridSearchTogglesIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	chunker := kb.NewChunker()
+	indexer := kb.NewIndexer(st.DB())
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	src, err := source.Add(ctx, kb.AddSourceRequest{
+		Path:     t.TempDir(),
+		Name:     "Toggle Docs",
+		SyncMode: "manual",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	for i, content := range []string{
+		"Kubernetes schedules containers across a cluster of nodes.",
+		"Kubernetes deployments roll out new container versions gradually.",
+		"Kubernetes services give pods a stable network address.",
+	} {
+		doc := &kb.Document{
+			DocumentID: "doc_toggle_" + string(rune('a'+i)),
+			SourceID:   src.SourceID,
+			Path:       "/docs/k8s-" + string(rune('a'+i)) + ".md",
+			Title:      "Kubernetes",
+			MimeType:   "text/markdown",
+		}
+		if err := indexer.Index(ctx, doc, chunker.Chunk(content, kb.ChunkOptions{})); err != nil {
+			t.Fatalf("Index failed: %v", err)
+		}
+	}
+
+	hybrid := kb.NewHybridSearcher(kb.NewSearcher(st.DB()), nil)
+
+	result, err := hybrid.Search(ctx, "kubernetes", kb.HybridSearchOptions{
+		Mode:            kb.HybridModeFusion,
+		SimilarityFloor: 0.0001,
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(result.Results) < 2 {
+		t.Fatalf("Expected multiple results, got %d", len(result.Results))
+	}
+	if !result.MMRApplied || !result.Reranked {
+		t.Errorf("Expected balanced preset to apply MMR and reranking, got mmr=%v rerank=%v", result.MMRApplied, result.Reranked)
+	}
+
+	result, err = hybrid.Search(ctx, "kubernetes", kb.HybridSearchOptions{
+		Mode:            kb.HybridModeFusion,
+		SimilarityFloor: 0.0001,
+		EnableMMR:       kb.BoolPtr(false),
+		EnableRerank:    kb.BoolPtr(false),
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if result.MMRApplied {
+		t.Error("MMR was applied although it was disabled")
+	}
+	if result.Reranked {
+		t.Error("Reranking was applied although it was disabled")
+	}
+}
+
+// TestKBMigrationStatusIntegration verifies vector migration progress is
+// derived from per-document markers and can be reset.
+func TestKBMigrationStatusIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	indexer := kb.NewIndexer(st.DB())
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	src, err := source.Add(ctx, kb.AddSourceRequest{
+		Path:     t.TempDir(),
+		Name:     "Migration Docs",
+		SyncMode: "manual",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	for _, id := range []string{"doc_mig_1", "doc_mig_2", "doc_mig_3"} {
+		doc := &kb.Document{DocumentID: id, SourceID: src.SourceID, Path: "/docs/" + id + ".md", Title: id, MimeType: "text/markdown"}
+		if err := indexer.Index(ctx, doc, kb.NewChunker().Chunk("Some content for "+id, kb.ChunkOptions{})); err != nil {
+			t.Fatalf("Index failed: %v", err)
+		}
+	}
+
+	// Without semantic search nothing has vectors yet
+	status, err := kb.GetMigrationStatus(ctx, st.DB())
+	if err != nil {
+		t.Fatalf("GetMigrationStatus failed: %v", err)
+	}
+	if status.Total != 3 || status.Migrated != 0 || status.Remaining != 3 {
+		t.Fatalf("Unexpected status before migration: %+v", status)
+	}
+
+	// Simulate an interrupted migration that finished two documents
+	if _, err := st.DB().Exec(`UPDATE kb_documents SET vectors_indexed_at = datetime('now') WHERE document_id IN ('doc_mig_1', 'doc_mig_2')`); err != nil {
+		t.Fatal(err)
+	}
+	status, _ = kb.GetMigrationStatus(ctx, st.DB())
+	if status.Migrated != 2 || status.Remaining != 1 {
+		t.Errorf("Expected 2 migrated and 1 remaining, got %+v", status)
+	}
+
+	// Re-indexing a document drops its marker, since its vectors are stale
+	doc := &kb.Document{DocumentID: "doc_mig_1", SourceID: src.SourceID, Path: "/docs/doc_mig_1.md", Title: "doc_mig_1", MimeType: "text/markdown"}
+	if err := indexer.Index(ctx, doc, kb.NewChunker().Chunk("Changed content", kb.ChunkOptions{})); err != nil {
+		t.Fatalf("Re-index failed: %v", err)
+	}
+	status, _ = kb.GetMigrationStatus(ctx, st.DB())
+	if status.Migrated != 1 {
+		t.Errorf("Expected re-indexed document to need migration again, got %+v", status)
+	}
+
+	if err := kb.ResetMigration(ctx, st.DB()); err != nil {
+		t.Fatalf("ResetMigration failed: %v", err)
+	}
+	status, _ = kb.GetMigrationStatus(ctx, st.DB())
+	if status.Migrated != 0 || status.Remaining != 3 {
+		t.Errorf("Expected reset to clear all markers, got %+v", status)
+	}
+}
+
+// TestKBSourceExtractEntitiesIntegration tests the per-source KAG extraction
+// toggle and per-source entity counts.
+func TestKBSourceExtractEntitiesIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	indexer := kb.NewIndexer(st.DB())
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	docs, err := source.Add(ctx, kb.AddSourceRequest{
+		Path:            t.TempDir(),
+		Name:            "Docs",
+		ExtractEntities: kb.BoolPtr(true),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	logs, err := source.Add(ctx, kb.AddSourceRequest{Path: t.TempDir(), Name: "Logs"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if !docs.ExtractEntities || logs.ExtractEntities {
+		t.Fatalf("Expected extraction on for docs and off by default for logs, got %v and %v",
+			docs.ExtractEntities, logs.ExtractEntities)
+	}
+
+	for _, d := range []*kb.Document{
+		{DocumentID: "doc_kag_1", SourceID: docs.SourceID, Path: "/docs/guide.md", Title: "Guide"},
+		{DocumentID: "doc_kag_2", SourceID: logs.SourceID, Path: "/logs/app.log", Title: "app.log"},
+	} {
+		if err := indexer.Index(ctx, d, kb.NewChunker().Chunk("Conduit connects AI clients to tools", kb.ChunkOptions{})); err != nil {
+			t.Fatalf("Index failed: %v", err)
+		}
+	}
+
+	// Only chunks from the docs source are eligible for extraction
+	var eligible int
+	if err := st.DB().QueryRow(`SELECT COUNT(*) FROM kb_chunks c WHERE ` + kb.ExtractableChunkFilter).Scan(&eligible); err != nil {
+		t.Fatal(err)
+	}
+	var docsChunks int
+	st.DB().QueryRow(`SELECT COUNT(*) FROM kb_chunks WHERE document_id = 'doc_kag_1'`).Scan(&docsChunks)
+	if eligible != docsChunks {
+		t.Errorf("Expected %d extractable chunks, got %d", docsChunks, eligible)
+	}
+
+	if _, err := st.DB().Exec(`INSERT INTO kb_entities (entity_id, name, type, source_document_id)
+		VALUES ('ent_1', 'Conduit', 'technology', 'doc_kag_1')`); err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := source.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	counts := map[string]int{}
+	for _, src := range sources {
+		counts[src.Name] = src.EntityCount
+	}
+	if counts["Docs"] != 1 || counts["Logs"] != 0 {
+		t.Errorf("Unexpected entity counts: %v", counts)
+	}
+
+	got, err := source.Get(ctx, docs.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !got.ExtractEntities || got.EntityCount != 1 {
+		t.Errorf("Expected Get to report extraction on with 1 entity, got %v and %d", got.ExtractEntities, got.EntityCount)
+	}
+}
+
+// TestKBSearchPathPrefixIntegration verifies that the path prefix filter is
+// applied in SQL, for both absolute and source-relative prefixes.
+func TestKBSearchPathPrefixIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	chunker := kb.NewChunker()
+	indexer := kb.NewIndexer(st.DB())
+	searcher := kb.NewSearcher(st.DB())
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Prefix Test Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	for id, rel := range map[string]string{
+		"doc_api":   "docs/api/limits.md",
+		"doc_guide": "docs/guide.md",
+		"doc_notes": "notes/limits.md",
+	} {
+		doc := &kb.Document{
+			DocumentID: id,
+			SourceID:   src.SourceID,
+			Path:       filepath.Join(root, rel),
+			Title:      rel,
+			MimeType:   "text/markdown",
+		}
+		chunks := chunker.Chunk("Rate limits protect the service from overload.", kb.ChunkOptions{})
+		if err := indexer.Index(ctx, doc, chunks); err != nil {
+			t.Fatalf("Index %s failed: %v", id, err)
+		}
+	}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"docs/api", []string{"doc_api"}},
+		{"./docs", []string{"doc_api", "doc_guide"}},
+		{filepath.Join(root, "notes"), []string{"doc_notes"}},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		// A limit of 1 would miss matches if filtering happened after the query
+		results, err := searcher.Search(ctx, "limits", kb.SearchOptions{Limit: 1, PathPrefix: tt.prefix})
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", tt.prefix, err)
+		}
+		if results.TotalHits != len(tt.want) {
+			t.Errorf("Search(%q): expected %d total hits, got %d", tt.prefix, len(tt.want), results.TotalHits)
+		}
+		if len(tt.want) > 0 && len(results.Results) != 1 {
+			t.Errorf("Search(%q): expected 1 result, got %d", tt.prefix, len(results.Results))
+			continue
+		}
+		for _, r := range results.Results {
+			found := false
+			for _, id := range tt.want {
+				found = found || r.DocumentID == id
+			}
+			if !found {
+				t.Errorf("Search(%q): unexpected result %s (%s)", tt.prefix, r.DocumentID, r.Path)
+			}
+		}
+	}
+}
+
+// TestKBSearchLanguageIntegration verifies that the language filter matches
+// chunk metadata recorded at indexing, for source files and fenced blocks.
+func TestKBSearchLanguageIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	chunker := kb.NewChunker()
+	indexer := kb.NewIndexer(st.DB())
+	searcher := kb.NewSearcher(st.DB())
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Language Test Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	files := map[string]string{
+		"retry.py":   "def retry(fn):\n    # retry the call with backoff\n    return fn()\n",
+		"retry.ts":   "// retry the call with backoff\nexport const retry = (fn) => fn()\n",
+		"guide.md":   "# Retry\n\nUse retry with backoff:\n\n```python\nretry(fetch)\n```\n",
+		"release.md": "# Notes\n\nThe retry helper now uses backoff.\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		doc := &kb.Document{DocumentID: "doc_" + rel, SourceID: src.SourceID, Path: path, Title: rel}
+		if err := indexer.Index(ctx, doc, chunker.ChunkSmart(content, path, kb.ChunkOptions{})); err != nil {
+			t.Fatalf("Index %s failed: %v", rel, err)
+		}
+	}
+
+	tests := []struct {
+		languages []string
+		want      int
+	}{
+		{nil, 4},
+		{[]string{"python"}, 2},
+		{[]string{"typescript"}, 1},
+		{[]string{"python", "typescript"}, 3},
+		{[]string{"rust"}, 0},
+	}
+	for _, tt := range tests {
+		results, err := searcher.Search(ctx, "retry backoff", kb.SearchOptions{Languages: tt.languages})
+		if err != nil {
+			t.Fatalf("Search(%v) failed: %v", tt.languages, err)
+		}
+		if results.TotalHits != tt.want || len(results.Results) != tt.want {
+			t.Errorf("Search(%v): expected %d hits, got %d (total %d)", tt.languages, tt.want, len(results.Results), results.TotalHits)
+		}
+	}
+}
+
+// TestKBGetDocumentContentIntegration verifies that documents are found by
+// ID or path, re-read from disk while present and reassembled from their
+// chunks once the file is gone.
+func TestKBGetDocumentContentIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	var sections []string
+	for i := 1; i <= 12; i++ {
+		sections = append(sections, fmt.Sprintf("## Step %d\n\nStep %d configures part %d of the rate limiter. %s",
+			i, i, i, strings.Repeat("Limits are enforced per token. ", 8)))
+	}
+	original := "# Setup\n\n" + strings.Join(sections, "\n\n") + "\n"
+	path := filepath.Join(root, "setup.md")
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Get Test Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	byPath, err := source.GetDocumentContent(ctx, path)
+	if err != nil {
+		t.Fatalf("GetDocumentContent(path) failed: %v", err)
+	}
+	if byPath.ContentFrom != kb.ContentFromDisk || byPath.Changed || byPath.Content != original {
+		t.Errorf("expected the unchanged file from disk, got from=%s changed=%v", byPath.ContentFrom, byPath.Changed)
+	}
+	if byPath.ChunkCount < 2 {
+		t.Fatalf("expected a multi-chunk document, got %d chunks", byPath.ChunkCount)
+	}
+
+	byID, err := source.GetDocumentContent(ctx, byPath.DocumentID)
+	if err != nil || byID.Path != path {
+		t.Fatalf("GetDocumentContent(id) = %v, %v", byID, err)
+	}
+
+	if err := os.WriteFile(path, []byte(original+"\nA new closing note.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := source.GetDocumentContent(ctx, path); err != nil || !changed.Changed {
+		t.Errorf("expected an edited file to be reported as changed, got %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	indexed, err := source.GetDocumentContent(ctx, path)
+	if err != nil {
+		t.Fatalf("GetDocumentContent after removal failed: %v", err)
+	}
+	if indexed.ContentFrom != kb.ContentFromIndex {
+		t.Errorf("expected content from the index, got %s", indexed.ContentFrom)
+	}
+	// Overlap between chunks must not be repeated in the reassembled text;
+	// cleaning at indexing may have changed whitespace
+	if strings.Join(strings.Fields(indexed.Content), " ") != strings.Join(strings.Fields(original), " ") {
+		t.Errorf("reassembled content differs from the original:\n%s", indexed.Content)
+	}
+
+	if _, err := source.GetDocumentContent(ctx, filepath.Join(root, "missing.md")); !errors.Is(err, kb.ErrDocumentNotFound) {
+		t.Errorf("expected ErrDocumentNotFound, got %v", err)
+	}
+}
+
+// TestKBMCPFetchDocumentIntegration verifies that kb_fetch_document pages
+// through a document by ID and by path.
+func TestKBMCPFetchDocumentIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	var lines []string
+	for i := 1; i <= 200; i++ {
+		lines = append(lines, fmt.Sprintf("Line %d of the runbook.", i))
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	path := filepath.Join(root, "runbook.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Runbooks"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	server := kb.NewMCPServer(st.DB(), nil)
+	fetch := func(args string) (string, *kb.MCPError) {
+		resp := server.HandleRequest(ctx, &kb.MCPRequest{
+			JSONRPC: "2.0", ID: 1, Method: "tools/call",
+			Params: json.RawMessage(`{"name":"kb_fetch_document","arguments":` + args + `}`),
+		})
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		data, _ := json.Marshal(resp.Result)
+		var result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		}
+		json.Unmarshal(data, &result)
+		return result.Content[0].Text, nil
+	}
+
+	var text string
+	offset := 0
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("paging did not finish")
+		}
+		page, mcpErr := fetch(fmt.Sprintf(`{"path":%q,"offset":%d,"max_chars":1000}`, path, offset))
+		if mcpErr != nil {
+			t.Fatalf("kb_fetch_document failed: %s", mcpErr.Message)
+		}
+		if pages == 0 && !strings.Contains(page, "Source: Runbooks") {
+			t.Errorf("expected source metadata in the header:\n%s", page)
+		}
+		_, body, _ := strings.Cut(page, "\n---\n\n")
+		body, more, _ := strings.Cut(body, "\n\n---\n\n[Document continues: call kb_fetch_document with offset=")
+		text += body
+		if more == "" {
+			break
+		}
+		offset, _ = strconv.Atoi(strings.TrimSuffix(more, " for the next page]"))
+	}
+	if text != content {
+		t.Errorf("pages do not reassemble the document: got %d characters, want %d", len(text), len(content))
+	}
+
+	if _, mcpErr := fetch(`{"document_id":"doc_missing"}`); mcpErr == nil || !strings.Contains(mcpErr.Message, "document not found") {
+		t.Errorf("expected a not found error, got %v", mcpErr)
+	}
+}
+
+// TestKBMCPSearchFiltersIntegration verifies that kb_search scopes results
+// by source name and MIME type.
+func TestKBMCPSearchFiltersIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	for name, files := range map[string]map[string]string{
+		"Guides": {"limits.md": "# Limits\n\nQuota rules for the API.\n", "limits.txt": "Quota rules in plain text.\n"},
+		"Notes":  {"notes.md": "# Notes\n\nQuota discussion notes.\n"},
+	} {
+		root := t.TempDir()
+		for file, content := range files {
+			if err := os.WriteFile(filepath.Join(root, file), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: name})
+		if err != nil {
+			t.Fatalf("Failed to create source: %v", err)
+		}
+		if _, err := source.Sync(ctx, src.SourceID); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+	}
+
+	server := kb.NewMCPServer(st.DB(), nil)
+	search := func(args string) (string, *kb.MCPError) {
+		resp := server.HandleRequest(ctx, &kb.MCPRequest{
+			JSONRPC: "2.0", ID: 1, Method: "tools/call",
+			Params: json.RawMessage(`{"name":"kb_search","arguments":` + args + `}`),
+		})
+		if resp.Error != nil {
+			return "", resp.Error
+		}
+		data, _ := json.Marshal(resp.Result)
+		return string(data), nil
+	}
+
+	tests := []struct {
+		args string
+		want []string
+		not  []string
+	}{
+		{`{"query":"quota","mode":"fts5"}`, []string{"limits.md", "limits.txt", "notes.md"}, nil},
+		{`{"query":"quota","mode":"fts5","source":"guides"}`, []string{"limits.md", "limits.txt"}, []string{"notes.md"}},
+		{`{"query":"quota","mode":"fts5","source":"Guides","mime_type":"text/markdown"}`, []string{"limits.md"}, []string{"limits.txt", "notes.md"}},
+	}
+	for _, tt := range tests {
+		out, mcpErr := search(tt.args)
+		if mcpErr != nil {
+			t.Fatalf("kb_search %s failed: %s", tt.args, mcpErr.Message)
+		}
+		for _, file := range tt.want {
+			if !strings.Contains(out, file) {
+				t.Errorf("kb_search %s: expected %s in results", tt.args, file)
+			}
+		}
+		for _, file := range tt.not {
+			if strings.Contains(out, file) {
+				t.Errorf("kb_search %s: unexpected %s in results", tt.args, file)
+			}
+		}
+	}
+
+	if _, mcpErr := search(`{"query":"quota","source":"Missing"}`); mcpErr == nil || !strings.Contains(mcpErr.Message, "unknown source") {
+		t.Errorf("expected an unknown source error, got %v", mcpErr)
+	}
+
+	// The response reports how many results were returned and whether more exist
+	out, mcpErr := search(`{"query":"quota","mode":"fts5","limit":2}`)
+	if mcpErr != nil {
+		t.Fatalf("kb_search failed: %s", mcpErr.Message)
+	}
+	var result struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+		Meta struct {
+			TotalHits     int    `json:"total_hits"`
+			Returned      int    `json:"returned"`
+			Truncated     bool   `json:"truncated"`
+			Confidence    string `json:"confidence"`
+			FallbackLevel int    `json:"fallback_level"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Meta.Returned != 2 || result.Meta.TotalHits != 3 || !result.Meta.Truncated {
+		t.Errorf("expected 2 of 3 matches reported as truncated, got %+v", result.Meta)
+	}
+	if result.Meta.Confidence == "" || result.Meta.FallbackLevel != 0 {
+		t.Errorf("expected a confidence at fallback level 0, got %+v", result.Meta)
+	}
+	if len(result.Content) != 3 || !strings.Contains(result.Content[0].Text, "truncated") {
+		t.Errorf("expected a truncation summary before 2 results, got %+v", result.Content)
+	}
+}
+
+// TestKBSearchModifiedRangeIntegration verifies that modification time
+// bounds are applied in SQL, with an inclusive lower and exclusive upper bound.
+func TestKBSearchModifiedRangeIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	chunker := kb.NewChunker()
+	indexer := kb.NewIndexer(st.DB())
+	searcher := kb.NewSearcher(st.DB())
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: t.TempDir(), Name: "Range Test Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.Local) }
+	for id, modified := range map[string]time.Time{
+		"doc_early": day(1),
+		"doc_mid":   day(10),
+		"doc_late":  day(20),
+	} {
+		doc := &kb.Document{
+			DocumentID: id,
+			SourceID:   src.SourceID,
+			Path:       "/test/" + id + ".md",
+			Title:      id,
+			MimeType:   "text/markdown",
+			ModifiedAt: modified,
+		}
+		chunks := chunker.Chunk("Quarterly release notes for the platform.", kb.ChunkOptions{})
+		if err := indexer.Index(ctx, doc, chunks); err != nil {
+			t.Fatalf("Index %s failed: %v", id, err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		after, before time.Time
+		want          int
+	}{
+		{"unbounded", time.Time{}, time.Time{}, 3},
+		{"after is inclusive", day(10), time.Time{}, 2},
+		{"before is exclusive", time.Time{}, day(10), 1},
+		{"range", day(2), day(15), 1},
+		{"utc bound", day(10).UTC(), day(10).Add(time.Hour).UTC(), 1},
+	}
+	for _, tt := range tests {
+		results, err := searcher.Search(ctx, "release", kb.SearchOptions{
+			Limit:          1,
+			ModifiedAfter:  tt.after,
+			ModifiedBefore: tt.before,
+		})
+		if err != nil {
+			t.Fatalf("%s: search failed: %v", tt.name, err)
+		}
+		if results.TotalHits != tt.want {
+			t.Errorf("%s: expected %d total hits, got %d", tt.name, tt.want, results.TotalHits)
+		}
+	}
+}
+
+// TestKBSearchChunkOffsetsIntegration verifies that search hits carry the
+// matched chunk's offsets in its document, so callers can jump to the match.
+func TestKBSearchChunkOffsetsIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	chunker := kb.NewChunker()
+	indexer := kb.NewIndexer(st.DB())
+	searcher := kb.NewSearcher(st.DB())
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Offsets Test Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	content := strings.Repeat("General filler text about nothing in particular. ", 40) +
+		"\n\nThe gateway enforces quotas per token."
+	chunks := chunker.Chunk(content, kb.ChunkOptions{MaxSize: 500, Overlap: 0})
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	doc := &kb.Document{
+		DocumentID: "doc_offsets",
+		SourceID:   src.SourceID,
+		Path:       filepath.Join(root, "gateway.md"),
+		Title:      "Gateway",
+		MimeType:   "text/markdown",
+	}
+	if err := indexer.Index(ctx, doc, chunks); err != nil {
+		t.Fatalf("Index failed: %v", err)
+	}
+
+	results, err := searcher.Search(ctx, "quotas", kb.SearchOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results.Results) == 0 {
+		t.Fatal("expected results, got none")
+	}
+
+	// Every hit's offsets must locate a chunk containing the match
+	for _, hit := range results.Results {
+		start, err := strconv.Atoi(hit.Metadata["start_char"])
+		if err != nil {
+			t.Fatalf("start_char %q: %v", hit.Metadata["start_char"], err)
+		}
+		end, err := strconv.Atoi(hit.Metadata["end_char"])
+		if err != nil {
+			t.Fatalf("end_char %q: %v", hit.Metadata["end_char"], err)
+		}
+		if start <= 0 || end <= start || end > len(content) {
+			t.Fatalf("offsets %d-%d out of range for %d bytes", start, end, len(content))
+		}
+		if !strings.Contains(content[start:end], "quotas") {
+			t.Errorf("offsets %d-%d do not cover the match", start, end)
+		}
+	}
+}
+
 // testStoreKB creates a temporary store for KB testing.
 func testStoreKB(t *testing.T) *store.Store {
 	t.Helper()
 
-	tmpDir := t.TempDir()
-	dbPath := filepath.Join(tmpDir, "kb-integration-test.db")
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "kb-integration-test.db")
+
+	st, err := store.New(dbPath)
+	if err != nil {
+		if strings.Contains(err.Error(), "fts5") {
+			return nil
+		}
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	return st
+}
+
+// TestKBSyncDiskCheckIntegration verifies that sync and reindex refuse to run
+// when new files would not leave the configured free space, and that a
+// refused reindex leaves the existing index in place.
+func TestKBSyncDiskCheckIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "first.md"), []byte("# First\n\nIndexed before the disk filled up."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Disk Check Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "second.md"), []byte("# Second\n\nWaiting for space."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	free, err := diskspace.Free(root)
+	if err != nil {
+		t.Fatalf("Free failed: %v", err)
+	}
+	source.SetDiskCheck(root, int64(free))
+
+	var insufficient *diskspace.InsufficientError
+	if _, err := source.Sync(ctx, src.SourceID); !errors.As(err, &insufficient) {
+		t.Fatalf("expected sync to be refused, got %v", err)
+	}
+	if _, err := source.Reindex(ctx, src.SourceID, nil); !errors.As(err, &insufficient) {
+		t.Fatalf("expected reindex to be refused, got %v", err)
+	}
+
+	stats, err := kb.NewIndexer(st.DB()).GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats.TotalDocuments != 1 {
+		t.Errorf("expected the refused reindex to keep 1 document, got %d", stats.TotalDocuments)
+	}
+
+	source.SetDiskCheck(root, 0)
+	result, err := source.Sync(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Sync with the check disabled failed: %v", err)
+	}
+	if result.Added != 1 {
+		t.Errorf("expected 1 added document, got %d", result.Added)
+	}
+}
+
+// TestKBSyncMaxFileSizeIntegration verifies that files over the size limit
+// are skipped and reported, and that an indexed file which grows past the
+// limit is removed.
+func TestKBSyncMaxFileSizeIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	large := filepath.Join(root, "large.md")
+	if err := os.WriteFile(filepath.Join(root, "small.md"), []byte("# Small\n\nFits."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(large, []byte("# Large\n\n"+strings.Repeat("Too big to index. ", 20)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Size Limit Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	result, err := source.Sync(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Added != 2 || len(result.Skipped) != 0 || result.MaxFileSize != kb.DefaultMaxFileSize {
+		t.Fatalf("expected both files indexed under the default limit, got %+v", result)
+	}
+
+	source.SetMaxFileSize(100)
+	result, err = source.Sync(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Skipped) != 1 {
+		t.Fatalf("expected 1 skipped file, got %+v", result.Skipped)
+	}
+	skipped := result.Skipped[0]
+	if skipped.Path != large || skipped.Reason != kb.SkipReasonTooLarge || skipped.Size <= 100 {
+		t.Errorf("unexpected skipped file: %+v", skipped)
+	}
+	if result.Deleted != 1 || result.MaxFileSize != 100 {
+		t.Errorf("expected the indexed copy of the large file removed, got %+v", result)
+	}
+
+	stats, err := kb.NewIndexer(st.DB()).GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if stats.TotalDocuments != 1 {
+		t.Errorf("expected 1 document left, got %d", stats.TotalDocuments)
+	}
+
+	// A non-positive limit restores the default
+	source.SetMaxFileSize(0)
+	result, err = source.Sync(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.Added != 1 || len(result.Skipped) != 0 || result.MaxFileSize != kb.DefaultMaxFileSize {
+		t.Errorf("expected the large file indexed again, got %+v", result)
+	}
+}
+
+// TestKBSourceSyncOutcomeIntegration verifies that each sync records its
+// attempt time, status and error on the source.
+func TestKBSourceSyncOutcomeIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := filepath.Join(t.TempDir(), "notes")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a.md"), []byte("# A\n\nSome notes."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Outcome Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if src.LastSyncStatus != "" || !src.LastSyncAttempt.IsZero() {
+		t.Errorf("expected no sync outcome before the first sync, got %q at %v", src.LastSyncStatus, src.LastSyncAttempt)
+	}
+
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	got, err := source.Get(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.LastSyncStatus != kb.SyncStatusSuccess || got.Error != "" {
+		t.Errorf("expected a successful sync without error, got %q %q", got.LastSyncStatus, got.Error)
+	}
+	if got.LastSyncAttempt.IsZero() || got.LastSync.IsZero() {
+		t.Error("expected the sync and attempt times to be recorded")
+	}
+	if got.DocCount != 1 || got.ChunkCount == 0 || got.Syncing {
+		t.Errorf("unexpected counts or syncing flag: %+v", got)
+	}
+
+	// A folder that cannot be walked is reported as a file error
+	if err := os.RemoveAll(root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	sources, err := source.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(sources) != 1 {
+		t.Fatalf("expected 1 source, got %d", len(sources))
+	}
+	if sources[0].LastSyncStatus != kb.SyncStatusPartial || !strings.Contains(sources[0].Error, "1 file(s) failed") {
+		t.Errorf("expected a partial sync with its error, got %q %q", sources[0].LastSyncStatus, sources[0].Error)
+	}
+
+	// A sync that cannot run at all is recorded as failed
+	source.SetDiskCheck(t.TempDir(), math.MaxInt64/2)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "b.md"), []byte("# B"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err == nil {
+		t.Fatal("expected the sync to be refused")
+	}
+	failed, err := source.Get(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if failed.LastSyncStatus != kb.SyncStatusFailed || !strings.Contains(failed.Error, "disk space") {
+		t.Errorf("expected a failed sync with its error, got %q %q", failed.LastSyncStatus, failed.Error)
+	}
+}
+
+// TestKBScheduledSyncIntegration verifies which sources the scheduler finds
+// due, with the default interval and per-source overrides.
+func TestKBScheduledSyncIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
 
-	st, err := store.New(dbPath)
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.md"), []byte("# A\n\nScheduled notes."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Scheduled Source"})
 	if err != nil {
-		if strings.Contains(err.Error(), "fts5") {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	dueAt := func(at time.Time) bool {
+		t.Helper()
+		due, err := source.DueSources(ctx, at)
+		if err != nil {
+			t.Fatalf("DueSources failed: %v", err)
+		}
+		return len(due) == 1 && due[0].SourceID == src.SourceID
+	}
+
+	// Nothing is scheduled until scheduling is enabled
+	if dueAt(time.Now().Add(48 * time.Hour)) {
+		t.Error("expected no due sources with scheduling disabled")
+	}
+
+	source.SetSchedule(true, time.Hour)
+	if !dueAt(time.Now()) {
+		t.Error("expected a source never synced to be due")
+	}
+
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	got, err := source.Get(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.NextSync == nil || !got.NextSync.Equal(got.LastSyncAttempt.Add(time.Hour)) {
+		t.Errorf("expected the next sync an hour after the last attempt, got %v (last %v)", got.NextSync, got.LastSyncAttempt)
+	}
+	if dueAt(time.Now()) || !dueAt(time.Now().Add(61*time.Minute)) {
+		t.Error("expected the source to be due one interval after its sync")
+	}
+
+	if err := source.SetSyncInterval(ctx, src.SourceID, "3h"); err != nil {
+		t.Fatalf("SetSyncInterval failed: %v", err)
+	}
+	if dueAt(time.Now().Add(2*time.Hour)) || !dueAt(time.Now().Add(3*time.Hour+time.Minute)) {
+		t.Error("expected the per-source interval to apply")
+	}
+
+	if err := source.SetSyncInterval(ctx, src.SourceID, "off"); err != nil {
+		t.Fatalf("SetSyncInterval failed: %v", err)
+	}
+	if dueAt(time.Now().Add(48 * time.Hour)) {
+		t.Error("expected a source with scheduling off never to be due")
+	}
+
+	if err := source.SetSyncInterval(ctx, src.SourceID, "default"); err != nil {
+		t.Fatalf("SetSyncInterval failed: %v", err)
+	}
+	if got, _ := source.Get(ctx, src.SourceID); got.SyncInterval != "" {
+		t.Errorf("expected the override to be cleared, got %q", got.SyncInterval)
+	}
+
+	if err := source.SetSyncInterval(ctx, "missing", "1h"); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
+
+// TestKBSyncFilesIntegration tests re-indexing only the files that changed.
+func TestKBSyncFilesIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	root := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	indexed := func(path string) bool {
+		_, err := source.FindDocument(ctx, path)
+		return err == nil
+	}
+
+	a := write("a.md", "# A\n\nFirst draft.")
+	b := write("docs/b.md", "# B\n\nNested notes.")
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Watched Source", SyncMode: "watch"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if src.SyncMode != kb.SyncModeWatch {
+		t.Errorf("expected sync mode watch, got %q", src.SyncMode)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// New and modified files are indexed; unchanged ones are left alone
+	c := write("c.md", "# C\n\nBrand new.")
+	result, err := source.SyncFiles(ctx, src.SourceID, []string{a, c})
+	if err != nil {
+		t.Fatalf("SyncFiles failed: %v", err)
+	}
+	if result.Added != 1 || result.Updated != 0 {
+		t.Errorf("expected 1 added and 0 updated, got %d and %d", result.Added, result.Updated)
+	}
+
+	// An atomic save replaces the file through a rename
+	tmp := write(".a.md.tmp", "# A\n\nSecond draft.")
+	if err := os.Rename(tmp, a); err != nil {
+		t.Fatal(err)
+	}
+	result, err = source.SyncFiles(ctx, src.SourceID, []string{tmp, a})
+	if err != nil {
+		t.Fatalf("SyncFiles failed: %v", err)
+	}
+	if result.Updated != 1 || result.Deleted != 0 {
+		t.Errorf("expected 1 updated and 0 deleted, got %d and %d", result.Updated, result.Deleted)
+	}
+
+	// Deleted files and directories drop their documents
+	if err := os.Remove(c); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "docs")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = source.SyncFiles(ctx, src.SourceID, []string{c, filepath.Join(root, "docs")})
+	if err != nil {
+		t.Fatalf("SyncFiles failed: %v", err)
+	}
+	if result.Deleted != 2 || indexed(b) || indexed(c) || !indexed(a) {
+		t.Errorf("expected b.md and c.md to be deleted, got %d deleted", result.Deleted)
+	}
+
+	// Files that don't match the source's patterns are not indexed
+	other := write("image.png", "not text")
+	if _, err := source.SyncFiles(ctx, src.SourceID, []string{other}); err != nil {
+		t.Fatalf("SyncFiles failed: %v", err)
+	}
+	if indexed(other) {
+		t.Error("expected a file outside the source's patterns not to be indexed")
+	}
+
+	got, err := source.Get(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.DocCount != 1 || got.LastSyncStatus != kb.SyncStatusSuccess {
+		t.Errorf("expected 1 document and a successful sync, got %d and %q", got.DocCount, got.LastSyncStatus)
+	}
+}
+
+// TestKBWatcherIntegration tests that a watched source is re-indexed as its
+// files change.
+func TestKBWatcherIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	root := t.TempDir()
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root, Name: "Watched Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	watcher := kb.NewWatcher(source, 50*time.Millisecond)
+	synced := make(chan []string, 10)
+	watcher.Sync = func(ctx context.Context, s *kb.Source, paths []string) (*kb.SyncResult, error) {
+		defer func() { synced <- paths }()
+		return source.SyncFiles(ctx, s.SourceID, paths)
+	}
+	done := make(chan struct{})
+	go func() {
+		watcher.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Sources not in watch mode are not watched
+	if err := watcher.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got, _ := source.Get(ctx, src.SourceID); got.WatchStatus != "" {
+		t.Errorf("expected no watch status in manual mode, got %q", got.WatchStatus)
+	}
+
+	if err := source.SetWatch(ctx, src.SourceID, true); err != nil {
+		t.Fatalf("SetWatch failed: %v", err)
+	}
+	if err := watcher.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	got, err := source.Get(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.WatchStatus != kb.WatchStatusWatching {
+		t.Fatalf("expected the source to be watched, got %q (%s)", got.WatchStatus, got.WatchError)
+	}
+
+	waitSync := func() []string {
+		t.Helper()
+		select {
+		case paths := <-synced:
+			return paths
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the watcher to sync")
 			return nil
 		}
-		t.Fatalf("Failed to create store: %v", err)
 	}
 
-	return st
+	// A burst of writes to one file is synced once
+	path := filepath.Join(root, "notes.md")
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(path, []byte(fmt.Sprintf("# Notes\n\nRevision %d.", i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if paths := waitSync(); len(paths) != 1 || paths[0] != path {
+		t.Errorf("expected one sync of %s, got %v", path, paths)
+	}
+	if _, err := source.FindDocument(ctx, path); err != nil {
+		t.Errorf("expected the new file to be indexed: %v", err)
+	}
+
+	// Files in a new directory are picked up, and the directory is watched
+	dir := filepath.Join(root, "sub")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	waitSync()
+	nested := filepath.Join(dir, "nested.md")
+	if err := os.WriteFile(nested, []byte("# Nested\n\nDeep notes."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitSync()
+	if _, err := source.FindDocument(ctx, nested); err != nil {
+		t.Errorf("expected the nested file to be indexed: %v", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	waitSync()
+	if _, err := source.FindDocument(ctx, path); err == nil {
+		t.Error("expected the deleted file to be removed from the index")
+	}
+
+	// Turning watch mode off stops watching
+	if err := source.SetWatch(ctx, src.SourceID, false); err != nil {
+		t.Fatalf("SetWatch failed: %v", err)
+	}
+	if err := watcher.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got, _ := source.Get(ctx, src.SourceID); got.WatchStatus != "" || got.SyncMode != kb.SyncModeManual {
+		t.Errorf("expected a manual source without watch status, got %q/%q", got.SyncMode, got.WatchStatus)
+	}
+
+	// A folder that can't be watched falls back to scheduled sync, even with
+	// the schedule disabled
+	gone := filepath.Join(t.TempDir(), "gone")
+	if err := os.Mkdir(gone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lost, err := source.Add(ctx, kb.AddSourceRequest{Path: gone, SyncMode: kb.SyncModeWatch})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	if err := watcher.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	got, err = source.Get(ctx, lost.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.WatchStatus != kb.WatchStatusFallback || got.WatchError == "" || got.NextSync == nil {
+		t.Errorf("expected a fallback to scheduled sync, got %q (%q), next sync %v", got.WatchStatus, got.WatchError, got.NextSync)
+	}
+}
+
+func TestKBWatcherLegacyAutoMode(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	ctx := context.Background()
+
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: t.TempDir(), Name: "Legacy Source"})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	// Older versions stored "auto" for sources that sync on change
+	if _, err := st.DB().ExecContext(ctx, `UPDATE kb_sources SET sync_mode = 'auto' WHERE source_id = ?`, src.SourceID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := source.Get(ctx, src.SourceID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.SyncMode != kb.SyncModeWatch {
+		t.Errorf("expected legacy auto mode to load as watch, got %q", got.SyncMode)
+	}
+
+	watcher := kb.NewWatcher(source, 50*time.Millisecond)
+	if err := watcher.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	got, _ = source.Get(ctx, src.SourceID)
+	if got.WatchStatus != kb.WatchStatusWatching {
+		t.Errorf("expected the legacy source to be watched, got %q (%s)", got.WatchStatus, got.WatchError)
+	}
+
+}
+
+// TestKBExplainEmptySearchIntegration tests the diagnosis of searches that
+// find nothing.
+func TestKBExplainEmptySearchIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	hybrid := kb.NewHybridSearcher(kb.NewSearcher(st.DB()), nil)
+	ctx := context.Background()
+
+	explain := func(query string, opts kb.HybridSearchOptions) *kb.EmptySearchExplanation {
+		t.Helper()
+		e, err := hybrid.ExplainEmpty(ctx, query, opts)
+		if err != nil {
+			t.Fatalf("ExplainEmpty failed: %v", err)
+		}
+		return e
+	}
+	hasReason := func(e *kb.EmptySearchExplanation, substr string) bool {
+		for _, r := range e.Reasons {
+			if strings.Contains(r, substr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Nothing indexed
+	e := explain("kubernetes", kb.HybridSearchOptions{})
+	if e.Sources != 0 || !hasReason(e, "No knowledge base sources") || len(e.Suggestions) == 0 {
+		t.Errorf("expected an empty knowledge base to be reported, got %+v", e)
+	}
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "guides"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "guides", "deploy.md"), []byte("# Deploy\n\nDeploy the kubernetes cluster with helm."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// A term that appears nowhere
+	e = explain("kubernetes zeppelin", kb.HybridSearchOptions{})
+	if e.Documents != 1 || !e.FTSAvailable || e.SemanticAvailable {
+		t.Errorf("expected 1 document with keyword search only, got %+v", e)
+	}
+	if len(e.Terms) != 2 || e.Terms[0].Chunks == 0 || e.Terms[1].Chunks != 0 {
+		t.Errorf("expected only the first term to match, got %+v", e.Terms)
+	}
+	if !hasReason(e, `"zeppelin"`) || !hasReason(e, "Semantic search is unavailable") {
+		t.Errorf("expected the missing term and semantic search to be reported, got %v", e.Reasons)
+	}
+
+	// A filter that excludes every match
+	e = explain("kubernetes", kb.HybridSearchOptions{PathPrefix: "reference"})
+	if len(e.Filters) != 1 || e.Filters[0].Name != "path_prefix" || e.Filters[0].Chunks != 0 {
+		t.Errorf("expected the path prefix filter to match nothing, got %+v", e.Filters)
+	}
+	if e.QueryChunks == 0 || !hasReason(e, "path prefix") {
+		t.Errorf("expected the query to match without the filter, got %d chunks, %v", e.QueryChunks, e.Reasons)
+	}
+
+	// Filters that each match, but not together
+	e = explain("kubernetes", kb.HybridSearchOptions{
+		PathPrefix:    "guides",
+		ModifiedAfter: time.Now().Add(time.Hour),
+	})
+	if len(e.Filters) != 2 || e.Filters[0].Chunks == 0 || e.FilteredChunks != 0 {
+		t.Errorf("expected the filters to exclude everything together, got %+v (%d)", e.Filters, e.FilteredChunks)
+	}
+	if !hasReason(e, "modification date") {
+		t.Errorf("expected the date filter to be reported, got %v", e.Reasons)
+	}
+}
+
+func TestKBSpellingFallbackIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	hybrid := kb.NewHybridSearcher(kb.NewSearcher(st.DB()), nil)
+	ctx := context.Background()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"deploy.md": "# Deployment\n\nThe deployment pipeline builds images and rolls them out to the cluster.",
+		"config.md": "# Configuration\n\nEvery service reads its configuration from environment variables.",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// Correctly spelled queries don't fall back
+	result, err := hybrid.SearchWithFallback(ctx, "deployment", kb.HybridSearchOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("SearchWithFallback failed: %v", err)
+	}
+	if result.FallbackLevel != kb.FallbackPrimary || len(result.SpellingCorrections) != 0 {
+		t.Errorf("expected a primary search, got level %d with %v", result.FallbackLevel, result.SpellingCorrections)
+	}
+
+	// A misspelled term is corrected to the indexed word
+	result, err = hybrid.SearchWithFallback(ctx, "depolyment", kb.HybridSearchOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("SearchWithFallback failed: %v", err)
+	}
+	if result.FallbackLevel != kb.FallbackSpelling || result.Confidence != "low" {
+		t.Fatalf("expected a low-confidence spelling fallback, got level %d (%s)", result.FallbackLevel, result.Confidence)
+	}
+	if len(result.Results) == 0 || !strings.HasSuffix(result.Results[0].Path, "deploy.md") {
+		t.Errorf("expected deploy.md to be found, got %+v", result.Results)
+	}
+	if len(result.SpellingCorrections) != 1 || result.SpellingCorrections[0].Correction != "deployment" {
+		t.Errorf("expected depolyment to be corrected to deployment, got %v", result.SpellingCorrections)
+	}
+	if !strings.Contains(result.Note, "Spelling corrected") || !strings.Contains(result.Note, `"deployment"`) {
+		t.Errorf("expected the note to mention the correction, got %q", result.Note)
+	}
+
+	// Every misspelled term of a longer query is corrected
+	result, err = hybrid.SearchWithFallback(ctx, "configuraton varaibles", kb.HybridSearchOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("SearchWithFallback failed: %v", err)
+	}
+	if result.FallbackLevel != kb.FallbackSpelling || len(result.SpellingCorrections) != 2 ||
+		result.SpellingCorrections[0].Correction != "configuration" || result.SpellingCorrections[1].Correction != "variables" {
+		t.Errorf("expected both terms to be corrected, got level %d with %v", result.FallbackLevel, result.SpellingCorrections)
+	}
+	if len(result.Results) == 0 || !strings.HasSuffix(result.Results[0].Path, "config.md") {
+		t.Errorf("expected config.md to be found, got %+v", result.Results)
+	}
+
+	// Nothing close enough is indexed
+	result, err = hybrid.SearchWithFallback(ctx, "zeppelin", kb.HybridSearchOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("SearchWithFallback failed: %v", err)
+	}
+	if result.FallbackLevel != kb.FallbackNone || len(result.Results) != 0 {
+		t.Errorf("expected no results, got level %d with %d results", result.FallbackLevel, len(result.Results))
+	}
+}
+
+func TestKBEntityExpansionIntegration(t *testing.T) {
+	st := testStoreKB(t)
+	if st == nil {
+		t.Skip("FTS5 not available, skipping KB integration test")
+	}
+	defer st.Close()
+
+	source := kb.NewSourceManager(st.DB())
+	hybrid := kb.NewHybridSearcher(kb.NewSearcher(st.DB()), nil)
+	ctx := context.Background()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"k8s.md":    "# Upgrades\n\nUpgrading k8s clusters means draining every node first.",
+		"backup.md": "# Backups\n\nBackups run nightly and are kept for a month.",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src, err := source.Add(ctx, kb.AddSourceRequest{Path: root})
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	if _, err := source.Sync(ctx, src.SourceID); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if _, err := st.DB().Exec(`INSERT INTO kb_entities (entity_id, name, type, confidence, metadata)
+		VALUES ('ent_k8s', 'Kubernetes', 'technology', 0.9, '{"aliases":["k8s"]}')`); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(opts kb.HybridSearchOptions) *kb.HybridSearchResult {
+		t.Helper()
+		opts.Limit = 5
+		result, err := hybrid.Search(ctx, "kubernetes upgrade", opts)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return result
+	}
+
+	// Without a KAG searcher the option has no effect
+	if result := search(kb.HybridSearchOptions{ExpandEntities: true}); len(result.Results) != 0 || len(result.EntityExpansions) != 0 {
+		t.Errorf("expected no expansion without KAG, got %d results and %v", len(result.Results), result.EntityExpansions)
+	}
+
+	hybrid.SetKAGSearcher(kb.NewKAGSearcher(st.DB(), nil))
+
+	// Expansion is opt-in
+	if result := search(kb.HybridSearchOptions{}); len(result.Results) != 0 {
+		t.Errorf("expected no results without expansion, got %d", len(result.Results))
+	}
+
+	result := search(kb.HybridSearchOptions{ExpandEntities: true})
+	if len(result.Results) != 1 || !strings.HasSuffix(result.Results[0].Path, "k8s.md") {
+		t.Fatalf("expected the alias to find k8s.md, got %+v", result.Results)
+	}
+	if len(result.EntityExpansions) != 1 || result.EntityExpansions[0].Mention != "kubernetes" ||
+		len(result.EntityExpansions[0].Names) != 1 || result.EntityExpansions[0].Names[0] != "k8s" {
+		t.Errorf("expected kubernetes to be expanded with k8s, got %+v", result.EntityExpansions)
+	}
 }